@@ -15,9 +15,11 @@ import (
 	"github.com/golang-migrate/migrate/v4"
 	_ "github.com/golang-migrate/migrate/v4/database/pgx/v5"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 
 	"github.com/shenikar/geo_broadcasting_system/internal/config"
 	v1 "github.com/shenikar/geo_broadcasting_system/internal/handler/http/v1"
+	"github.com/shenikar/geo_broadcasting_system/internal/monitoring"
 	"github.com/shenikar/geo_broadcasting_system/internal/repository"
 	"github.com/shenikar/geo_broadcasting_system/internal/service"
 	"github.com/shenikar/geo_broadcasting_system/internal/webhook"
@@ -26,7 +28,7 @@ import (
 	redisclient "github.com/shenikar/geo_broadcasting_system/pkg/redis"
 	"github.com/sirupsen/logrus"
 
-	_ "github.com/shenikar/geo_broadcasting_system/docs"
+	"github.com/shenikar/geo_broadcasting_system/docs"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
@@ -63,6 +65,34 @@ func runMigrations(cfg *config.Config, log *logrus.Logger) error {
 	return nil
 }
 
+// reloadConfig заново загружает конфигурацию и применяет перезагружаемые параметры:
+// API-ключи, адрес, секрет и число попыток вебхуков, уровень логирования.
+// Изменения остальных параметров требуют перезапуска и только логируются.
+func reloadConfig(cfg *config.Config, log *logrus.Logger, webhookWorker *webhook.WebhookWorker) {
+	log.Info("Received SIGHUP, reloading configuration...")
+
+	next, err := config.LoadConfig()
+	if err != nil {
+		log.WithError(err).Error("Failed to reload config, keeping current configuration")
+		return
+	}
+	if err := webhookWorker.Reload(next); err != nil {
+		log.WithError(err).Error("Failed to reload webhook subscriptions, keeping current configuration")
+		return
+	}
+
+	for _, name := range cfg.Reload(next) {
+		log.WithField("variable", name).Warn("Config change requires restart, ignored")
+	}
+	if level, err := logrus.ParseLevel(next.LogLevel); err == nil {
+		log.SetLevel(level)
+	} else {
+		log.WithField("log_level", next.LogLevel).Warn("Invalid LOG_LEVEL on reload, keeping current level")
+	}
+
+	log.Info("Configuration reloaded")
+}
+
 func main() {
 	// Загрузка конфигурации
 	cfg, err := config.LoadConfig()
@@ -83,8 +113,8 @@ func main() {
 		return
 	}
 
-	// Подключение к PostgreSQL
-	dbpool, err := postgres.NewPostgresDB(ctx, cfg)
+	// Подключение к PostgreSQL; запросы дольше SLOW_QUERY_THRESHOLD пишутся в лог
+	dbpool, err := postgres.NewPostgresDB(ctx, cfg, monitoring.NewSlowQueryTracer(log, cfg.SlowQueryThreshold))
 	if err != nil {
 		log.Fatalf("Failed to connect to PostgreSQL: %v", err)
 	}
@@ -93,35 +123,112 @@ func main() {
 
 	// Инициализация Redis клиента
 	redisClient, err := redisclient.NewRedisClient(ctx, cfg.RedisAddr, cfg.RedisPass, cfg.RedisDB)
+	redisAvailable := err == nil
 	if err != nil {
-		log.Fatalf("Failed to connect to Redis: %v", err)
+		if cfg.RedisRequired {
+			log.Fatalf("Failed to connect to Redis: %v", err)
+		}
+		log.WithError(err).Warn("Redis is unavailable at startup, starting in degraded mode (REDIS_REQUIRED=false)")
+		redisClient = redisclient.NewClient(cfg.RedisAddr, cfg.RedisPass, cfg.RedisDB)
+	} else {
+		log.Info("Successfully connected to Redis")
 	}
 	defer redisClient.Close()
-	log.Info("Successfully connected to Redis")
+
+	// Построитель ключей Redis с префиксом окружения
+	redisKeys := redisclient.NewKeyBuilder(cfg.RedisKeyPrefix)
 
 	// Инициализация издателя вебхуков
-	webhookPublisher := webhook.NewRedisWebhookPublisher(redisClient)
+	var webhookPublisher webhook.WebhookPublisher = webhook.NewRedisWebhookPublisher(redisClient, redisKeys, cfg)
+
+	// Режим деградации: пока Redis недоступен, команды к нему не выполняются, а вебхуки копятся в памяти
+	if !cfg.RedisRequired {
+		redisHealth := monitoring.NewRedisHealth(redisClient, log, cfg.RedisHealthCheckInterval, redisAvailable)
+		redisHealth.Start(ctx)
+		fallbackPublisher := webhook.NewFallbackPublisher(webhookPublisher, redisHealth, log, cfg.WebhookFallbackQueueSize)
+		fallbackPublisher.Start(ctx)
+		webhookPublisher = fallbackPublisher
+	}
 
-	// Инициализация и запуск воркера вебхуков
-	webhookWorker := webhook.NewWebhookWorker(redisClient, log, cfg)
-	webhookWorker.Start(ctx)
 	// Инициализация репозиториев
-	incidentRepo := repository.NewIncidentRepository(dbpool, redisClient)
+	incidentRepo := repository.NewIncidentRepository(dbpool, redisClient, redisKeys)
+
+	// Инициализация и запуск воркера вебхуков; наблюдатели инцидентов читаются из репозитория
+	webhookWorker, err := webhook.NewWebhookWorker(redisClient, redisKeys, log, cfg, incidentRepo)
+	if err != nil {
+		log.Fatalf("Failed to create webhook worker: %v", err)
+	}
+	webhookWorker.Start(ctx)
+
+	// Мониторинг глубины очереди вебхуков
+	webhook.NewQueueMonitor(webhookPublisher, log, cfg.WebhookQueueCheckInterval, cfg.WebhookQueueAlarmThreshold).Start(ctx)
+
+	// Статистика пулов соединений PostgreSQL и Redis
+	monitoring.NewPoolMonitor(dbpool, redisClient, log, cfg.PoolStatsInterval, cfg.DBAcquireWaitThreshold).Start(ctx)
+
+	// Индекс активных инцидентов в памяти для проверки местоположения
+	activeIndex := service.NewActiveIncidentIndex(incidentRepo, log, cfg)
+	activeIndex.Start(ctx)
 
 	// Инициализация сервисов
-	incidentService := service.NewIncidentService(incidentRepo, log, cfg, webhookPublisher)
+	incidentService := service.NewIncidentService(incidentRepo, log, cfg, webhookPublisher, activeIndex)
+
+	// Перенос вебхуков из outbox в очередь Redis
+	service.NewOutboxRelay(incidentRepo, webhookPublisher, log, cfg).Start(ctx)
+
+	// Запуск задачи очистки давно неактивных инцидентов
+	service.NewIncidentPurger(incidentRepo, log, cfg).Start(ctx)
+
+	// Запуск автоэскалации инцидентов по числу пользователей в опасной зоне
+	service.NewIncidentEscalator(incidentRepo, incidentService, log, cfg).Start(ctx)
+
+	// Периодическая синхронизация зарегистрированных GeoJSON-лент
+	service.NewImportFeedSyncer(incidentRepo, incidentService, log, cfg).Start(ctx)
+
+	// Учет просмотров инцидентов: перенос счетчиков в Redis и PostgreSQL
+	service.NewIncidentViewFlusher(incidentRepo, incidentService, log, cfg).Start(ctx)
 
 	// Инициализация хэндлеров
 	handler := v1.NewHandler(incidentService, log, cfg)
 
+	// Счетчик незавершенных запросов для логирования при остановке
+	inFlight := v1.NewInFlightCounter()
+
 	// Настройка Gin роутера
-	router := gin.Default()
+	router := gin.New()
+	// X-Forwarded-For учитывается только от доверенных прокси, иначе клиент подменил бы свой IP
+	// и обошел лимиты по адресу (например, у публичной ленты)
+	if err := router.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		log.Fatalf("Failed to set trusted proxies: %v", err)
+	}
+	router.Use(
+		inFlight.Middleware(),
+		gin.Recovery(),
+		v1.RequestIDMiddleware(),
+		v1.AccessLogMiddleware(log, "/api/v1/system/health", "/metrics"),
+		// Long polling сам ограничивает ожидание LONG_POLL_MAX_TIMEOUT
+		v1.TimeoutMiddleware(cfg.RequestTimeout, "/api/v1/incidents/poll"),
+	)
 	api := router.Group("/api/v1")
 	handler.RegisterRoutes(api)
 
+	// Метрики Prometheus
+	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+
+	// Хост и базовый путь спецификации берутся из конфигурации, в том числе для Swagger UI
+	docs.SwaggerInfo.Host = cfg.OpenAPIHost
+	docs.SwaggerInfo.BasePath = api.BasePath()
+
 	// Добавление маршрута для Swagger UI
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
+	// Выдача спецификации OpenAPI для генераторов клиентов
+	openAPISpec, err := v1.NewOpenAPISpec(docs.SwaggerInfo, cfg.OpenAPIHost, api.BasePath())
+	if err != nil {
+		log.Fatalf("Failed to prepare OpenAPI spec: %v", err)
+	}
+	openAPISpec.RegisterRoutes(router)
+
 	// Запуск HTTP-сервера
 	serverAddr := fmt.Sprintf(":%s", cfg.HTTPPort)
 
@@ -138,18 +245,54 @@ func main() {
 	}()
 	log.Infof("HTTP server started on port %s", cfg.HTTPPort)
 
+	// Перезагрузка конфигурации по SIGHUP
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hangup:
+				reloadConfig(cfg, log, webhookWorker)
+			}
+		}
+	}()
+
 	// Graceful shutdown
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
-	log.Info("Received shutdown signal, shutting down server...")
+	shutdownStart := time.Now()
 
-	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
 	defer shutdownCancel()
 
+	shutdownLog := log.WithFields(logrus.Fields{
+		"in_flight_requests": inFlight.Count(),
+		"shutdown_timeout":   cfg.ShutdownTimeout.String(),
+	})
+	if depth, err := webhookPublisher.QueueDepth(shutdownCtx); err != nil {
+		shutdownLog = shutdownLog.WithField("webhook_queue_error", err.Error())
+	} else {
+		shutdownLog = shutdownLog.WithField("webhook_queue_depth", depth)
+	}
+	shutdownLog.Info("Received shutdown signal, shutting down server...")
+
 	if err := srv.Shutdown(shutdownCtx); err != nil {
-		log.Fatalf("Server forced to shutdown: %v", err)
+		log.WithError(err).WithField("in_flight_requests", inFlight.Count()).Fatal("Server forced to shutdown")
+	}
+	if remaining := inFlight.Wait(shutdownCtx); remaining > 0 {
+		log.WithField("in_flight_requests", remaining).Warn("Shutdown timeout reached with requests still in flight")
+	}
+	if err := incidentService.WaitImports(shutdownCtx); err != nil {
+		log.WithError(err).Warn("Shutdown timeout reached with incident imports still running")
+	}
+	// Фоновые задачи останавливаются после запросов; воркер вебхуков успевает доставить накопленный пакет
+	cancel()
+	if err := webhookWorker.Wait(shutdownCtx); err != nil {
+		log.WithError(err).Warn("Shutdown timeout reached with webhook worker still running")
 	}
 
-	log.Info("Server gracefully stopped")
+	log.WithField("duration_ms", time.Since(shutdownStart).Milliseconds()).Info("Server gracefully stopped")
 }
@@ -15,14 +15,14 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/incidents": {
-            "get": {
+        "/admin/api-keys": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get a paginated list of all incidents. Requires API key.",
+                "description": "Add an API key that is accepted immediately, without a restart. If key is omitted, a random one is generated.\nThe key is returned only in this response. Requires admin API key.",
                 "consumes": [
                     "application/json"
                 ],
@@ -30,32 +30,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Incidents"
+                    "Admin"
                 ],
-                "summary": "Get a list of incidents",
+                "summary": "Issue an API key",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "Number of items per page",
-                        "name": "pageSize",
-                        "in": "query"
+                        "description": "Key to add",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/v1.APIKeyRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/v1.IncidentResponse"
+                            "$ref": "#/definitions/v1.APIKeyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Malformed request body",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     },
@@ -68,6 +68,21 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "403": {
+                        "description": "Admin API key required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ValidationErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -76,16 +91,24 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
+                    },
+                    "503": {
+                        "description": "Storage unavailable, retry after Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
                     }
                 }
-            },
+            }
+        },
+        "/admin/api-keys/revoke": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Create a new incident in the system. Requires API key.",
+                "description": "Revoke an API key issued via /admin/api-keys. Keys from API_KEYS and ADMIN_API_KEYS cannot be revoked at runtime.\nOther instances stop accepting the key within API_KEY_CACHE_TTL. Requires admin API key.",
                 "consumes": [
                     "application/json"
                 ],
@@ -93,29 +116,26 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Incidents"
+                    "Admin"
                 ],
-                "summary": "Create a new incident",
+                "summary": "Revoke an API key",
                 "parameters": [
                     {
-                        "description": "Incident creation request",
-                        "name": "incident",
+                        "description": "Key to revoke",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/v1.CreateIncidentRequest"
+                            "$ref": "#/definitions/v1.APIKeyRevokeRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/v1.IncidentResponse"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
                     "400": {
-                        "description": "Invalid request body or validation error",
+                        "description": "Malformed request body",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -132,6 +152,30 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "403": {
+                        "description": "Admin API key required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "API key not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ValidationErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -140,51 +184,36 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
+                    },
+                    "503": {
+                        "description": "Storage unavailable, retry after Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/incidents/{id}": {
-            "get": {
+        "/admin/cache/invalidate": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get a single incident by its ID. Requires API key.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Flush incident, location tile and stats caches in Redis and reset in-memory indexes, e.g. after fixing data directly in the database. Only keys under the configured key prefix are removed. Requires admin API key.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Incidents"
-                ],
-                "summary": "Get incident by ID",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Incident ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "Admin"
                 ],
+                "summary": "Invalidate caches",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.IncidentResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid incident ID",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/v1.CacheInvalidateResponse"
                         }
                     },
                     "401": {
@@ -196,8 +225,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Incident not found",
+                    "403": {
+                        "description": "Admin API key required",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -213,16 +242,24 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
+                    },
+                    "503": {
+                        "description": "Storage unavailable, retry after Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/admin/incident-templates": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Update an existing incident by ID. Requires API key.",
+                "description": "Get all incident templates ordered by name. Requires admin API key.",
                 "consumes": [
                     "application/json"
                 ],
@@ -230,33 +267,21 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Incidents"
-                ],
-                "summary": "Update an existing incident",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Incident ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Incident update request",
-                        "name": "incident",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/v1.UpdateIncidentRequest"
-                        }
-                    }
+                    "Admin"
                 ],
+                "summary": "List incident templates",
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/v1.IncidentTemplateResponse"
+                            }
+                        }
                     },
-                    "400": {
-                        "description": "Invalid incident ID or request body",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -264,8 +289,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "403": {
+                        "description": "Admin API key required",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -281,16 +306,22 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
+                    },
+                    "503": {
+                        "description": "Storage unavailable, retry after Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
                     }
                 }
             },
-            "delete": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Deactivate an incident by its ID. This marks the incident as inactive. Requires API key.",
+                "description": "Create a named incident template with default description, category and radius. Requires admin API key.",
                 "consumes": [
                     "application/json"
                 ],
@@ -298,24 +329,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Incidents"
+                    "Admin"
                 ],
-                "summary": "Deactivate an incident",
+                "summary": "Create an incident template",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Incident ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Incident template",
+                        "name": "template",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.IncidentTemplateRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/v1.IncidentTemplateResponse"
+                        }
                     },
                     "400": {
-                        "description": "Invalid incident ID",
+                        "description": "Malformed request body",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -332,6 +368,30 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "403": {
+                        "description": "Admin API key required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Template with this name already exists",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ValidationErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -340,18 +400,24 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
+                    },
+                    "503": {
+                        "description": "Storage unavailable, retry after Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/location/check": {
-            "post": {
+        "/admin/incident-templates/{id}": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Check if there are any active incidents at a given location for a user. Requires API key.",
+                "description": "Get an incident template by ID. Requires admin API key.",
                 "consumes": [
                     "application/json"
                 ],
@@ -359,32 +425,27 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Location"
+                    "Admin"
                 ],
-                "summary": "Check location for incidents",
+                "summary": "Get an incident template",
                 "parameters": [
                     {
-                        "description": "Location check request",
-                        "name": "location",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/v1.LocationCheckRequest"
-                        }
+                        "type": "string",
+                        "description": "Template ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/v1.IncidentResponse"
-                            }
+                            "$ref": "#/definitions/v1.IncidentTemplateResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid request body or validation error",
+                        "description": "Invalid template ID",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -401,6 +462,24 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "403": {
+                        "description": "Admin API key required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Template not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -409,18 +488,22 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
+                    },
+                    "503": {
+                        "description": "Storage unavailable, retry after Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
                     }
                 }
-            }
-        },
-        "/stats": {
-            "get": {
+            },
+            "put": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get the total count of active users. Requires API key.",
+                "description": "Replace an incident template. Incidents created from it earlier are not changed. Requires admin API key.",
                 "consumes": [
                     "application/json"
                 ],
@@ -430,13 +513,307 @@ const docTemplate = `{
                 "tags": [
                     "Admin"
                 ],
-                "summary": "Get user statistics",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/v1.StatsResponse"
-                        }
+                "summary": "Update an incident template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Template ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Incident template",
+                        "name": "template",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.IncidentTemplateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.IncidentTemplateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid template ID or malformed request body",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Admin API key required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Template not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Template with this name already exists",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ValidationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Storage unavailable, retry after Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete an incident template. Incidents created from it are not affected. Requires admin API key.",
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Delete an incident template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Template ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Invalid template ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Admin API key required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Template not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Storage unavailable, retry after Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/keys/usage": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get the number of requests made with each API key within API_KEY_USAGE_WINDOW, most active keys first.\nKeys are identified by their non-secret label. Requires admin API key.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get API key usage",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/v1.APIKeyUsageResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Admin API key required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Storage unavailable, retry after Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/location-checks/reevaluate": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Re-run the location check for checks stored within [from, to) that were considered safe and report those\nthat fall into an incident active at the time of the check. Incident status is restored from incident versions,\nthe zone is the current one; stored checks are not changed. Pages are ordered by check ID: pass next_after_id\nas after_id to get the next page. With notify=true a location_check_reevaluated webhook is published for each\ndiscrepancy of the page. Requires admin API key.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Reevaluate stored location checks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "RFC 3339 start of the period (inclusive)",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC 3339 end of the period (exclusive), defaults to now",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only checks with a greater ID (next_after_id of the previous page)",
+                        "name": "after_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Maximum number of discrepancies (1-1000)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Publish catch-up webhooks for the discrepancies",
+                        "name": "notify",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.LocationCheckReevaluationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid period or parameters",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     },
                     "401": {
                         "description": "Unauthorized",
@@ -447,6 +824,15 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "403": {
+                        "description": "Admin API key required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -455,72 +841,5144 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
+                    },
+                    "503": {
+                        "description": "Storage unavailable, retry after Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/webhooks/circuits": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get circuit breaker state per webhook destination and the number of webhooks in the dead letter queue. Deliveries to an open circuit are not attempted until the cooldown expires. Requires admin API key.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get webhook destination circuits",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.WebhookCircuitsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Admin API key required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Storage unavailable, retry after Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/webhooks/queue": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get the number of webhook events waiting for delivery. A growing depth usually means the receiver is down. Requires admin API key.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get webhook queue depth",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.WebhookQueueResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Admin API key required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Storage unavailable, retry after Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get a paginated list of all incidents. Requires API key.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Incidents"
+                ],
+                "summary": "Get a list of incidents",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Number of items per page",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Latitude of the point to search near (requires near_lon and within_meters)",
+                        "name": "near_lat",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Longitude of the point to search near (requires near_lat and within_meters)",
+                        "name": "near_lon",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Maximum distance from the point in meters; results are ordered by distance",
+                        "name": "within_meters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Exclude incidents with lower confidence (0-100); unrated incidents are always included",
+                        "name": "min_confidence",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "created_at",
+                            "updated_at",
+                            "name",
+                            "severity"
+                        ],
+                        "type": "string",
+                        "default": "created_at",
+                        "description": "Sort field; overrides ordering by distance when near_* is set",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "default": "desc",
+                        "description": "Sort direction",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated response fields to return (id is always included)",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC 3339 timestamp; only incidents created at or before it are listed. Pass X-Snapshot-Time from the first page to keep later pages stable",
+                        "name": "snapshot_time",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only incidents created by the API key with this label (see created_by in the response)",
+                        "name": "created_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Return 304 if no incident changed since this HTTP date",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/v1.IncidentResponse"
+                            }
+                        },
+                        "headers": {
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "Time of the latest incident change"
+                            },
+                            "X-Snapshot-Time": {
+                                "type": "string",
+                                "description": "Snapshot the page was read at, to pass as snapshot_time for the next pages"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified since If-Modified-Since"
+                    },
+                    "400": {
+                        "description": "Invalid filter, sort or fields parameters",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Storage unavailable, retry after Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create a new incident in the system. Requires API key.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Incidents"
+                ],
+                "summary": "Create a new incident",
+                "parameters": [
+                    {
+                        "description": "Incident creation request",
+                        "name": "incident",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.CreateIncidentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/v1.IncidentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Malformed request body",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Incident with this external ID already exists",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Validation error or coordinates outside of the service area",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ValidationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Storage unavailable, retry after Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents/by-external/{external_id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get a single incident by its identifier in an external system (external_id). Requires API key.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Incidents"
+                ],
+                "summary": "Get incident by external ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "External incident ID",
+                        "name": "external_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated response fields to return (id is always included)",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.IncidentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Unknown fields",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Incident not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Storage unavailable, retry after Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents/changes": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get incidents (including deactivated ones) changed after the cursor, ordered by modification time and ID.\nPass cursor.updated_at and cursor.id from the response as since and after_id to get the next page while has_more is true.\nserver_time is returned only on the final page; the next sync starts from the cursor of that page. Requires API key.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Incidents"
+                ],
+                "summary": "Get incidents changed since a cursor",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "RFC 3339 timestamp (cursor.updated_at of the previous response)",
+                        "name": "since",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Incident ID (cursor.id of the previous response)",
+                        "name": "after_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Number of items per page",
+                        "name": "pageSize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.IncidentChangesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Missing or invalid since or after_id",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Storage unavailable, retry after Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents/clusters": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Group active incidents inside the bounding box into grid cells whose size shrinks as zoom grows.\nEach cluster has the average position and count; single-incident clusters include incident_id. Requires API key.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Incidents"
+                ],
+                "summary": "Get incident clusters for a map area",
+                "parameters": [
+                    {
+                        "type": "number",
+                        "description": "South boundary latitude",
+                        "name": "min_lat",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "West boundary longitude",
+                        "name": "min_lon",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "North boundary latitude",
+                        "name": "max_lat",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "East boundary longitude (less than min_lon for an area crossing the antimeridian)",
+                        "name": "max_lon",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Map zoom level (0-20)",
+                        "name": "zoom",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/v1.ClusterResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid bounding box or zoom",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Feature disabled (FEATURE_DISABLED)",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Storage unavailable, retry after Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents/deactivate": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Deactivate many incidents in one transaction, either by an explicit list of IDs or by a filter.\nFilter-based operations require \"confirm\": true. Only active incidents are deactivated: drafts, rejected and already finished incidents are skipped, so the operation is idempotent. Requires API key.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Incidents"
+                ],
+                "summary": "Bulk deactivate incidents",
+                "parameters": [
+                    {
+                        "description": "Bulk deactivation request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.BulkDeactivateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.BulkDeactivateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body, missing target or confirmation",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ValidationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Storage unavailable, retry after Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents/from-template/{template_id}": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create an incident at the given coordinates using the template's name, description, category and radius.\nName and description can be overridden. Requires API key.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Incidents"
+                ],
+                "summary": "Create an incident from a template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Template ID",
+                        "name": "template_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Incident coordinates and overrides",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.CreateFromTemplateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/v1.IncidentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid template ID or malformed request body",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Template not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Validation error or coordinates outside of the service area",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ValidationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Storage unavailable, retry after Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents/hotspots": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Group active incidents inside the bounding box into square grid cells of cell_meters and return the cells\nholding at least min_count incidents (HOTSPOT_MIN_INCIDENTS by default): many small incidents close together may indicate a larger situation.\nEach hotspot has the cell center, bounds and incident count, ordered by count. Requires API key.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Incidents"
+                ],
+                "summary": "Get incident hotspots for a map area",
+                "parameters": [
+                    {
+                        "type": "number",
+                        "description": "South boundary latitude",
+                        "name": "min_lat",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "West boundary longitude",
+                        "name": "min_lon",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "North boundary latitude",
+                        "name": "max_lat",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "East boundary longitude (less than min_lon for an area crossing the antimeridian)",
+                        "name": "max_lon",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Grid cell size in meters (100-100000)",
+                        "name": "cell_meters",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Minimum number of active incidents in a cell (at least 2)",
+                        "name": "min_count",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/v1.HotspotResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid bounding box, cell size or threshold",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Feature disabled (FEATURE_DISABLED)",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Storage unavailable, retry after Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents/import-feeds": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List feeds registered for periodic sync with the number of incidents seen on the last successful sync\nand the outcome of the last sync. Requires admin API key.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Incidents"
+                ],
+                "summary": "List registered GeoJSON feeds",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/v1.ImportFeedResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Admin API key required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Storage unavailable, retry after Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Register a GeoJSON FeatureCollection URL to be re-imported every IMPORT_FEED_SYNC_INTERVAL.\nEach sync creates or updates incidents by external ID like POST /incidents/import-url and deactivates incidents\nthat were in the feed on the previous sync but are no longer present; such incidents become active again if they return.\nExternal IDs are scoped to the feed as \"\u003cfeed id\u003e:\u003cfeature id\u003e\". Lifecycle webhooks are published for the changes.\nThe URL is checked against IMPORT_URL_ALLOWED_HOSTS. Requires admin API key.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Incidents"
+                ],
+                "summary": "Register a GeoJSON feed for periodic sync",
+                "parameters": [
+                    {
+                        "description": "Feed URL",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.ImportURLRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ImportFeedResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or URL not allowed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Admin API key required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Feed with this URL is already registered",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ValidationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Storage unavailable, retry after Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents/import-feeds/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Stop syncing the feed. Incidents imported from it are not affected. Requires admin API key.",
+                "tags": [
+                    "Incidents"
+                ],
+                "summary": "Unregister a GeoJSON feed",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Feed ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Invalid feed ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Admin API key required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Feed not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Storage unavailable, retry after Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents/import-jobs/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get the state of a background import started by POST /incidents/import-url: counts of created and updated incidents\nand per-feature errors. Job state is kept for 24 hours. Requires admin API key.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Incidents"
+                ],
+                "summary": "Get incident import job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Import job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ImportJobResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid job ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Admin API key required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Import job not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Storage unavailable, retry after Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents/import-url": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Fetch a GeoJSON FeatureCollection from the URL in the background and create or update incidents by external ID.\nEach Point feature becomes an incident: properties external_id (or the feature id), name, radius_meters, description, category, severity, status and source.\nThe feed is limited by IMPORT_URL_MAX_BYTES and IMPORT_URL_TIMEOUT; if IMPORT_URL_ALLOWED_HOSTS is set, only those hosts are allowed.\nHosts resolving to private, loopback or link-local addresses are rejected. At most IMPORT_MAX_CONCURRENT imports run at once.\nReturns a job to poll via GET /incidents/import-jobs/{id}. Requires admin API key.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Incidents"
+                ],
+                "summary": "Import incidents from a GeoJSON URL",
+                "parameters": [
+                    {
+                        "description": "Feed URL",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.ImportURLRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ImportJobResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or URL not allowed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Admin API key required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ValidationErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many imports in progress, retry after Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Storage unavailable, retry after Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents/merge": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Merge incidents reported independently for the same event. The first of ids stays as the canonical incident:\nits zone is replaced with a circle covering the union of all merged zones, location check matches of the others are redirected to it,\nand the others are deactivated. The merge is recorded in the status history. Requires admin API key.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Incidents"
+                ],
+                "summary": "Merge duplicate incidents",
+                "parameters": [
+                    {
+                        "description": "Incidents to merge, canonical first",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.MergeIncidentsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.MergeIncidentsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or fewer than two distinct incidents",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Admin API key required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Incident not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Incident is finished or rejected",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ValidationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Storage unavailable, retry after Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents/mine": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get a paginated list of incidents created with the API key of the request (incidents created before creator tracking are not included). Accepts the same parameters as GET /incidents except created_by. Requires API key.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Incidents"
+                ],
+                "summary": "List incidents created by the caller",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Number of items per page",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Latitude of the point to search near (requires near_lon and within_meters)",
+                        "name": "near_lat",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Longitude of the point to search near (requires near_lat and within_meters)",
+                        "name": "near_lon",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Maximum distance from the point in meters; results are ordered by distance",
+                        "name": "within_meters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Exclude incidents with lower confidence (0-100); unrated incidents are always included",
+                        "name": "min_confidence",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "created_at",
+                            "updated_at",
+                            "name",
+                            "severity"
+                        ],
+                        "type": "string",
+                        "default": "created_at",
+                        "description": "Sort field; overrides ordering by distance when near_* is set",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "default": "desc",
+                        "description": "Sort direction",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated response fields to return (id is always included)",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC 3339 timestamp; only incidents created at or before it are listed",
+                        "name": "snapshot_time",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Return 304 if no incident changed since this HTTP date",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/v1.IncidentResponse"
+                            }
+                        },
+                        "headers": {
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "Time of the latest incident change"
+                            },
+                            "X-Snapshot-Time": {
+                                "type": "string",
+                                "description": "Snapshot the page was read at, to pass as snapshot_time for the next pages"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified since If-Modified-Since"
+                    },
+                    "400": {
+                        "description": "Invalid filter, sort or fields parameters",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Storage unavailable, retry after Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents/poll": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Wait until incidents change after since, then return the changes. The request is held until a new or changed incident appears\nor the timeout elapses; an empty list means no changes. Pass cursor.updated_at and cursor.id from the response as since and after_id\nin the next request: incidents changed at the same instant are told apart by ID, so none are skipped.\nThe timeout is capped at LONG_POLL_MAX_TIMEOUT, and at most LONG_POLL_MAX_CLIENTS requests may wait at once. Requires API key.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Incidents"
+                ],
+                "summary": "Long-poll incident changes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "RFC 3339 timestamp (cursor.updated_at of the previous response)",
+                        "name": "since",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Incident ID (cursor.id of the previous response)",
+                        "name": "after_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "30s",
+                        "description": "How long to wait for changes, Go duration",
+                        "name": "timeout",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.IncidentChangesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Missing or invalid since, after_id or timeout",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Too many concurrent pollers, retry after Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "string",
+                                "description": "Seconds to wait before retrying"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Storage unavailable, retry after Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents/preview-impact": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Estimate how many users a zone would affect before creating the incident: the number of distinct users\nwhose location checks within the stats window fall inside the circle. Nothing is stored. Requires API key.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Incidents"
+                ],
+                "summary": "Preview impact of a hypothetical incident",
+                "parameters": [
+                    {
+                        "description": "Hypothetical incident zone",
+                        "name": "zone",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.PreviewImpactRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.PreviewImpactResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Malformed request body",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Feature disabled (FEATURE_DISABLED)",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation error or coordinates outside of the service area",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ValidationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Storage unavailable, retry after Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents/timeline": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Count incidents created per hour or day bucket (UTC) within [from, to). Empty buckets are returned with zero count.\nOptionally filter by source and status. Requires API key.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Incidents"
+                ],
+                "summary": "Get incident creation timeline",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "RFC 3339 start of the period (inclusive)",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC 3339 end of the period (exclusive), defaults to now",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "hour",
+                            "day"
+                        ],
+                        "type": "string",
+                        "default": "day",
+                        "description": "Bucket size",
+                        "name": "interval",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "official",
+                            "crowdsourced",
+                            "sensor"
+                        ],
+                        "type": "string",
+                        "description": "Only incidents from this source",
+                        "name": "source",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "active",
+                            "inactive",
+                            "draft",
+                            "rejected",
+                            "resolved"
+                        ],
+                        "type": "string",
+                        "description": "Only incidents in this status",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.TimelineResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid period, interval or filter",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Feature disabled (FEATURE_DISABLED)",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Storage unavailable, retry after Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents/validate": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Run the same validation as incident creation plus a PostGIS geometry validity check (ST_IsValid)\nand return the zone area and bounds. Nothing is persisted. Requires API key.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Incidents"
+                ],
+                "summary": "Validate an incident geofence without saving",
+                "parameters": [
+                    {
+                        "description": "Incident creation request to validate",
+                        "name": "incident",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.CreateIncidentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Validation result; valid is false when errors are found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GeofenceValidationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Malformed request body",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Validation error, including a missing radius for a category without a default radius",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ValidationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Storage unavailable, retry after Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get a single incident by its ID. Requires API key.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Incidents"
+                ],
+                "summary": "Get incident by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Incident ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated response fields to return (id is always included)",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.IncidentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid incident ID or fields",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Incident not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Storage unavailable, retry after Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Replace an existing incident by ID. The update is a full replace: omitted fields are reset,\nso \"silent\" and \"public\" become false unless sent again; only source, severity and external_id\nkeep their current values when omitted. Status changes follow the transition rules:\nactive -\u003e inactive|resolved, inactive -\u003e active|resolved, draft -\u003e inactive, rejected -\u003e draft.\nDrafts become active only through approval and resolved incidents cannot be reopened. Requires API key.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Incidents"
+                ],
+                "summary": "Update an existing incident",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Incident ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Incident update request",
+                        "name": "incident",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.UpdateIncidentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Invalid incident ID or malformed request body",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Status transition is not allowed or external ID is taken",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Validation error or coordinates outside of the service area",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ValidationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Storage unavailable, retry after Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Deactivate an incident by its ID. Without a body the incident is marked as inactive (withdrawn manually);\nwith \"resolved\": true it is marked as resolved (ended naturally) with an optional reason.\nThe status transition rules of PUT /incidents/{id} apply: resolved incidents cannot be deactivated\nand drafts can only be withdrawn (inactive). Requires API key.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Incidents"
+                ],
+                "summary": "Deactivate an incident",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Incident ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Resolution details",
+                        "name": "resolution",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/v1.DeactivateIncidentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Invalid incident ID or malformed request body",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Status transition is not allowed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ValidationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Storage unavailable, retry after Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents/{id}/approve": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Transition a draft incident to active and emit an incident_approved webhook. Requires admin API key.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Moderation"
+                ],
+                "summary": "Approve a draft incident",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Incident ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.IncidentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid incident ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Admin API key required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Incident not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Incident is not a draft",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Storage unavailable, retry after Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents/{id}/attachments": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List attachments of the incident with presigned download URLs valid until expires_at.\nReturns 404 with code FEATURE_DISABLED if S3_BUCKET is not configured. Requires API key.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Incidents"
+                ],
+                "summary": "List incident attachments",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Incident ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/v1.AttachmentResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid incident ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Incident not found or attachments disabled",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Storage unavailable, retry after Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents/{id}/attachments/upload-url": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Register an attachment for the incident and return a presigned URL of the S3-compatible object storage.\nThe client uploads the file directly to the storage with a PUT request and the returned headers before expires_at;\nthe API stores only the object key. Returns 404 with code FEATURE_DISABLED if S3_BUCKET is not configured. Requires API key.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Incidents"
+                ],
+                "summary": "Get a presigned upload URL for an incident attachment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Incident ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "File name and content type",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.AttachmentUploadRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/v1.AttachmentUploadResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid incident ID, request body or content type",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Incident not found or attachments disabled",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ValidationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Storage unavailable, retry after Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents/{id}/escalate": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Raise the severity of an active incident one level (minor → moderate → major → critical),\nrecord a new version and emit an incident_escalated webhook. Requires API key.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Incidents"
+                ],
+                "summary": "Escalate an incident",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Incident ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.IncidentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid incident ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Incident not found or feature disabled (FEATURE_DISABLED)",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Incident is not active or already critical",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Storage unavailable, retry after Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents/{id}/impact": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get the number of distinct users whose location checks matched the incident within the stats time window.\nReflects only users who actively checked their location, not everyone physically inside the zone. Requires API key.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Incidents"
+                ],
+                "summary": "Get incident impact estimate",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Incident ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ImpactResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid incident ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Incident not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Storage unavailable, retry after Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents/{id}/overlapping": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get active incidents whose zones intersect the zone of the given incident (excluding the incident itself),\nordered by overlap area, largest first. Requires API key.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Incidents"
+                ],
+                "summary": "Get incidents overlapping an incident",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Incident ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/v1.OverlappingIncidentResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid incident ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Incident not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Storage unavailable, retry after Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents/{id}/reject": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Transition a draft incident to rejected. Rejected incidents never match location checks. Requires admin API key.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Moderation"
+                ],
+                "summary": "Reject a draft incident",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Incident ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.IncidentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid incident ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Admin API key required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Incident not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Incident is not a draft",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Storage unavailable, retry after Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents/{id}/revert/{version}": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Restore the incident state saved in the given version. The revert is recorded as a new version,\na status change is recorded in the status history and subscribers receive an incident_updated webhook.\nStatus transition rules do not apply to a revert. Requires admin API key.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Incidents"
+                ],
+                "summary": "Revert an incident to a version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Incident ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Version number",
+                        "name": "version",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.IncidentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid incident ID or version",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Admin API key required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Incident or version not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Storage unavailable, retry after Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents/{id}/versions": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get full snapshots of every version of an incident, newest first. Version 1 is the state at creation. Requires API key.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Incidents"
+                ],
+                "summary": "List incident versions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Incident ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/v1.IncidentVersionResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid incident ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Incident not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Storage unavailable, retry after Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents/{id}/views": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get how many times the incident was fetched with GET /incidents/{id}. Views are counted asynchronously,\nso the latest views of other instances may appear with a delay of about a second. Requires admin API key.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get incident view count",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Incident ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.IncidentViewsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid incident ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Admin API key required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Incident not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Storage unavailable, retry after Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents/{id}/watch": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List callback URLs watching the incident. Signing secrets are not returned. Requires API key.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Incidents"
+                ],
+                "summary": "List incident watchers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Incident ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/v1.WatcherResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid incident ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Incident not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Storage unavailable, retry after Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Register a callback URL that receives every lifecycle webhook of the incident (incident_approved, incident_updated,\nincident_escalated, incident_deactivated) in addition to WEBHOOK_URL and WEBHOOK_SUBSCRIPTIONS_FILE subscriptions.\nDeliveries use the same payload, retries and circuit breaker as subscriptions and are signed only with the watcher\nsecret. The URL is checked against WATCH_URL_ALLOWED_HOSTS and must resolve to public addresses. Requires API key.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Incidents"
+                ],
+                "summary": "Watch an incident",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Incident ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Callback URL and optional signing secret",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.WatchIncidentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/v1.WatcherResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid incident ID, request body or URL not allowed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Incident not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "URL already watches this incident",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ValidationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Storage unavailable, retry after Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents/{id}/watch/{watcher_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Remove a watcher from the incident; its callback URL stops receiving the incident webhooks. Requires API key:\nthe key that added the watcher or an admin key.",
+                "tags": [
+                    "Incidents"
+                ],
+                "summary": "Unwatch an incident",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Incident ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Watcher ID",
+                        "name": "watcher_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Invalid incident or watcher ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Watcher was added by another API key",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Watcher not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Storage unavailable, retry after Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/location/check": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Check if there are any active incidents at a given location for a user. The API key is optional: anonymous checks are accepted, checks with a valid key are stored as authenticated.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/vnd.geo.location-check+json"
+                ],
+                "tags": [
+                    "Location"
+                ],
+                "summary": "Check location for incidents",
+                "parameters": [
+                    {
+                        "description": "Location check request",
+                        "name": "location",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.LocationCheckRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "With Accept: application/vnd.geo.location-check+json or LOCATION_CHECK_RESPONSE=object",
+                        "schema": {
+                            "$ref": "#/definitions/v1.LocationCheckResultResponse"
+                        },
+                        "headers": {
+                            "X-Incidents-Truncated": {
+                                "type": "boolean",
+                                "description": "Set to true when more incidents matched than MAX_MATCHED_INCIDENTS allows to return"
+                            },
+                            "X-Matched-Incidents": {
+                                "type": "integer",
+                                "description": "Total number of matched incidents, set together with X-Incidents-Truncated"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Malformed request body",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Validation error or coordinates outside of the service area",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ValidationErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "User location check quota exceeded, retry after Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Storage unavailable, retry after Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/public/incidents": {
+            "get": {
+                "description": "List active incidents published for citizens (public flag set, not silent, not in an internal category).\nDoes not require an API key; only non-sensitive fields are returned. Requests are rate limited per client IP.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Public"
+                ],
+                "summary": "Public feed of active incidents",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Number of items per page",
+                        "name": "pageSize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/v1.PublicIncidentResponse"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Feature disabled (FEATURE_DISABLED)",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded, retry after Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Storage unavailable, retry after Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get the total count of active users. Requires API key.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get user statistics",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.StatsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Storage unavailable, retry after Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/system/capabilities": {
+            "get": {
+                "description": "Get runtime limits and supported values (page size, bulk and merge size, category radius rules, severities, statuses, sources, enabled features, rate limits) so that clients do not hardcode them. Does not require an API key; the response may be cached for 60 seconds.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "Get server capabilities and limits",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.CapabilitiesResponse"
+                        },
+                        "headers": {
+                            "Cache-Control": {
+                                "type": "string",
+                                "description": "public, max-age=60"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/system/health": {
+            "get": {
+                "description": "Get health status of the application",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "Get application health status",
+                "responses": {
+                    "200": {
+                        "description": "Status OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/alerts": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get active incidents whose danger zone covers any of the user's saved locations, grouped by location label.\nLocations without matching incidents are omitted. Requires API key.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get alerts for user locations",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/v1.UserAlertResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Storage unavailable, retry after Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/checks": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get a paginated list of a user's location checks (newest first) with the IDs of matched incidents. Requires API key.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Location"
+                ],
+                "summary": "Get user location check history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Number of items per page",
+                        "name": "pageSize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/v1.LocationCheckResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Storage unavailable, retry after Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/locations": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get all saved locations of a user. Requires API key.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "List user locations",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/v1.UserLocationResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Storage unavailable, retry after Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Save a labeled location (e.g. home, work) for proactive danger zone alerts. Labels are unique per user. Requires API key.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Save a user location",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "User location",
+                        "name": "location",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.UserLocationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/v1.UserLocationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Malformed request body",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Location with this label already exists",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ValidationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Storage unavailable, retry after Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/locations/{location_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete a saved location of a user. Requires API key.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Delete a user location",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Location ID",
+                        "name": "location_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Invalid location ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Location not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Storage unavailable, retry after Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/status": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Re-evaluate the coordinates of the user's most recent location check against currently active incidents.\nThe check is not recorded and no webhook is sent. Requires API key.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get current user danger status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.UserStatusResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "User has no location checks",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Storage unavailable, retry after Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "v1.APIKeyRequest": {
+            "description": "DTO для выпуска API-ключа",
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string",
+                    "maxLength": 256,
+                    "minLength": 16
+                }
+            }
+        },
+        "v1.APIKeyResponse": {
+            "description": "DTO для ответа с выпущенным API-ключом",
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string"
+                },
+                "label": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.APIKeyRevokeRequest": {
+            "description": "DTO для отзыва API-ключа",
+            "type": "object",
+            "required": [
+                "key"
+            ],
+            "properties": {
+                "key": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.APIKeyUsageResponse": {
+            "description": "Количество запросов с API-ключом за окно учета",
+            "type": "object",
+            "properties": {
+                "label": {
+                    "type": "string"
+                },
+                "requests": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v1.AttachmentResponse": {
+            "description": "Вложение инцидента с presigned URL для скачивания",
+            "type": "object",
+            "properties": {
+                "content_type": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "download_url": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "filename": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "object_key": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.AttachmentUploadRequest": {
+            "description": "DTO для запроса URL загрузки вложения инцидента",
+            "type": "object",
+            "required": [
+                "content_type",
+                "filename"
+            ],
+            "properties": {
+                "content_type": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "image/jpeg"
+                },
+                "filename": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "photo.jpg"
+                }
+            }
+        },
+        "v1.AttachmentUploadResponse": {
+            "description": "Presigned URL для загрузки файла вложения напрямую в объектное хранилище. Файл загружается запросом method на upload_url с заголовками headers.",
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "headers": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "method": {
+                    "type": "string",
+                    "example": "PUT"
+                },
+                "object_key": {
+                    "type": "string",
+                    "example": "incidents/3f1c.../9a2e....jpg"
+                },
+                "upload_url": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.BoundsResponse": {
+            "description": "DTO для описывающего прямоугольника",
+            "type": "object",
+            "properties": {
+                "max_lat": {
+                    "type": "number"
+                },
+                "max_lon": {
+                    "type": "number"
+                },
+                "min_lat": {
+                    "type": "number"
+                },
+                "min_lon": {
+                    "type": "number"
+                }
+            }
+        },
+        "v1.BulkDeactivateFilter": {
+            "description": "Фильтр инцидентов для массовой деактивации",
+            "type": "object",
+            "properties": {
+                "older_than": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Status - деактивируются только действующие инциденты, поэтому допустим лишь статус active",
+                    "type": "string",
+                    "enum": [
+                        "active"
+                    ]
+                }
+            }
+        },
+        "v1.BulkDeactivateRequest": {
+            "description": "DTO для массовой деактивации инцидентов",
+            "type": "object",
+            "properties": {
+                "confirm": {
+                    "type": "boolean"
+                },
+                "filter": {
+                    "$ref": "#/definitions/v1.BulkDeactivateFilter"
+                },
+                "ids": {
+                    "type": "array",
+                    "maxItems": 1000,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "v1.BulkDeactivateResponse": {
+            "description": "DTO для ответа на массовую деактивацию",
+            "type": "object",
+            "properties": {
+                "deactivated": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v1.CacheInvalidateResponse": {
+            "description": "DTO для ответа на принудительный сброс кэшей",
+            "type": "object",
+            "properties": {
+                "keys_removed": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v1.CapabilitiesResponse": {
+            "description": "DTO для ответа с возможностями и ограничениями сервера",
+            "type": "object",
+            "properties": {
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.CategoryCapabilityResponse"
+                    }
+                },
+                "features": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "boolean"
+                    }
+                },
+                "max_bulk_deactivate_ids": {
+                    "type": "integer",
+                    "example": 1000
+                },
+                "max_media_urls": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "max_merge_ids": {
+                    "type": "integer",
+                    "example": 50
+                },
+                "max_page_size": {
+                    "type": "integer",
+                    "example": 100
+                },
+                "public_feed_rate_limit_per_minute": {
+                    "description": "PublicFeedRateLimitPerMinute - лимит запросов к открытой ленте с одного IP в минуту; 0 - без лимита",
+                    "type": "integer"
+                },
+                "severities": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "sources": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "statuses": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "user_check_quota_per_minute": {
+                    "description": "UserCheckQuotaPerMinute - лимит проверок местоположения на пользователя в минуту; 0 - без лимита",
+                    "type": "integer"
+                },
+                "webhook_schema_version": {
+                    "type": "string",
+                    "example": "1.5"
+                }
+            }
+        },
+        "v1.CategoryCapabilityResponse": {
+            "description": "DTO ограничений радиуса категории опасности",
+            "type": "object",
+            "properties": {
+                "default_radius_meters": {
+                    "type": "integer"
+                },
+                "max_radius_meters": {
+                    "type": "integer"
+                },
+                "min_radius_meters": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "fire"
+                }
+            }
+        },
+        "v1.ChangeCursorResponse": {
+            "description": "DTO позиции в ленте изменений",
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.ClusterResponse": {
+            "description": "DTO для кластера инцидентов на карте",
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "incident_id": {
+                    "type": "string"
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                }
+            }
+        },
+        "v1.CreateFromTemplateRequest": {
+            "description": "DTO для создания инцидента по шаблону",
+            "type": "object",
+            "required": [
+                "latitude",
+                "longitude"
+            ],
+            "properties": {
+                "confidence": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 0
+                },
+                "description": {
+                    "type": "string"
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "name": {
+                    "description": "Name и Description - необязательная замена названия и описания шаблона",
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 2
+                },
+                "status": {
+                    "description": "Status - начальный статус; если не указан, используется DEFAULT_INCIDENT_STATUS",
+                    "type": "string",
+                    "enum": [
+                        "active",
+                        "draft"
+                    ]
+                }
+            }
+        },
+        "v1.CreateIncidentRequest": {
+            "description": "DTO для создания инцидента",
+            "type": "object",
+            "required": [
+                "latitude",
+                "longitude",
+                "name"
+            ],
+            "properties": {
+                "category": {
+                    "description": "Category - категория опасности; правила радиуса задаются в INCIDENT_CATEGORY_RULES",
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "confidence": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 0
+                },
+                "description": {
+                    "type": "string"
+                },
+                "external_id": {
+                    "description": "ExternalID - идентификатор инцидента во внешней системе; уникален",
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "media_urls": {
+                    "description": "MediaURLs - необязательные ссылки на медиа. Пустая ссылка не проходит проверку url, поэтому required\nдля элементов не указывается: swag по нему пометил бы обязательным все поле",
+                    "type": "array",
+                    "maxItems": 10,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 2
+                },
+                "public": {
+                    "description": "Public - публиковать инцидент в открытой ленте GET /public/incidents",
+                    "type": "boolean"
+                },
+                "radius_meters": {
+                    "description": "RadiusMeters - радиус зоны; можно не указывать вместе с category, если для категории задан радиус по умолчанию",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "severity": {
+                    "description": "Severity - уровень опасности; по умолчанию minor",
+                    "type": "string",
+                    "enum": [
+                        "minor",
+                        "moderate",
+                        "major",
+                        "critical"
+                    ]
+                },
+                "silent": {
+                    "description": "Silent - не отправлять вебхуки об опасности по этому инциденту (временный режим для тестирования)",
+                    "type": "boolean"
+                },
+                "source": {
+                    "description": "Source - источник сведений; по умолчанию official",
+                    "type": "string",
+                    "enum": [
+                        "official",
+                        "crowdsourced",
+                        "sensor"
+                    ]
+                },
+                "status": {
+                    "description": "Status - начальный статус; если не указан, используется DEFAULT_INCIDENT_STATUS",
+                    "type": "string",
+                    "enum": [
+                        "active",
+                        "draft"
+                    ]
+                }
+            }
+        },
+        "v1.DeactivateIncidentRequest": {
+            "description": "DTO для деактивации инцидента",
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "resolved": {
+                    "description": "Resolved - инцидент завершился естественным образом (статус resolved)",
+                    "type": "boolean"
+                }
+            }
+        },
+        "v1.ErrorResponse": {
+            "description": "DTO для ошибки с машиночитаемым кодом",
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.FieldError": {
+            "description": "Ошибка валидации одного поля запроса",
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "tag": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.GeofenceValidationResponse": {
+            "description": "DTO для результата проверки зоны инцидента",
+            "type": "object",
+            "properties": {
+                "area_square_meters": {
+                    "type": "number"
+                },
+                "bounds": {
+                    "$ref": "#/definitions/v1.BoundsResponse"
+                },
+                "crosses_antimeridian": {
+                    "description": "CrossesAntimeridian - зона пересекает 180-й меридиан, в bounds min_lon больше max_lon",
+                    "type": "boolean"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "valid": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "v1.HotspotResponse": {
+            "description": "DTO для очага - ячейки сетки со скоплением активных инцидентов",
+            "type": "object",
+            "properties": {
+                "bounds": {
+                    "$ref": "#/definitions/v1.BoundsResponse"
+                },
+                "count": {
+                    "type": "integer"
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                }
+            }
+        },
+        "v1.ImpactResponse": {
+            "description": "DTO для ответа с оценкой числа затронутых инцидентом пользователей",
+            "type": "object",
+            "properties": {
+                "incident_id": {
+                    "type": "string"
+                },
+                "user_count": {
+                    "type": "integer"
+                },
+                "window_minutes": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v1.ImportFeatureErrorResponse": {
+            "description": "Ошибка импорта одного объекта ленты",
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "external_id": {
+                    "type": "string"
+                },
+                "index": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v1.ImportFeedResponse": {
+            "description": "Зарегистрированная GeoJSON-лента, периодически синхронизируемая с инцидентами",
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "incidents": {
+                    "type": "integer"
+                },
+                "last_sync": {
+                    "$ref": "#/definitions/v1.ImportFeedSyncResponse"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.ImportFeedSyncResponse": {
+            "description": "Итог последней синхронизации ленты: completed или failed",
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "integer"
+                },
+                "deactivated": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "completed"
+                },
+                "synced_at": {
+                    "type": "string"
+                },
+                "updated": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v1.ImportJobResponse": {
+            "description": "Состояние фонового импорта инцидентов: running, completed или failed",
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.ImportFeatureErrorResponse"
+                    }
+                },
+                "features": {
+                    "type": "integer"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "running"
+                },
+                "unchanged": {
+                    "type": "integer"
+                },
+                "updated": {
+                    "type": "integer"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.ImportURLRequest": {
+            "description": "DTO для импорта инцидентов из GeoJSON-ленты по URL",
+            "type": "object",
+            "required": [
+                "url"
+            ],
+            "properties": {
+                "url": {
+                    "type": "string",
+                    "maxLength": 2048,
+                    "example": "https://feeds.example.org/incidents.geojson"
+                }
+            }
+        },
+        "v1.IncidentChangesResponse": {
+            "description": "DTO для ответа инкрементальной синхронизации",
+            "type": "object",
+            "properties": {
+                "cursor": {
+                    "$ref": "#/definitions/v1.ChangeCursorResponse"
+                },
+                "has_more": {
+                    "type": "boolean"
+                },
+                "incidents": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.IncidentResponse"
+                    }
+                },
+                "server_time": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.IncidentResponse": {
+            "description": "DTO для ответа с информацией об инциденте",
+            "type": "object",
+            "properties": {
+                "area_sq_meters": {
+                    "description": "AreaSqMeters - площадь зоны инцидента в квадратных метрах (вычисляется по радиусу)",
+                    "type": "number"
+                },
+                "category": {
+                    "type": "string"
+                },
+                "confidence": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "description": "CreatedBy - метка API-ключа, создавшего инцидент",
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "external_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "media_urls": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "public": {
+                    "type": "boolean"
+                },
+                "radius_meters": {
+                    "type": "integer"
+                },
+                "resolution_reason": {
+                    "description": "ResolutionReason - причина завершения инцидента",
+                    "type": "string"
+                },
+                "severity": {
+                    "type": "string"
+                },
+                "silent": {
+                    "type": "boolean"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.IncidentTemplateRequest": {
+            "description": "DTO для создания и обновления шаблона инцидента",
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "category": {
+                    "description": "Category - категория опасности; без radius_meters используется радиус категории по умолчанию",
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 2
+                },
+                "radius_meters": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "source": {
+                    "type": "string",
+                    "enum": [
+                        "official",
+                        "crowdsourced",
+                        "sensor"
+                    ]
+                }
+            }
+        },
+        "v1.IncidentTemplateResponse": {
+            "description": "DTO для ответа с шаблоном инцидента",
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "radius_meters": {
+                    "type": "integer"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.IncidentVersionResponse": {
+            "description": "Полный снимок состояния инцидента после одного изменения",
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "incident": {
+                    "$ref": "#/definitions/v1.IncidentResponse"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v1.IncidentViewsResponse": {
+            "description": "Сколько раз инцидент запрашивали через GET /incidents/{id}",
+            "type": "object",
+            "properties": {
+                "incident_id": {
+                    "type": "string"
+                },
+                "views": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v1.LocationCheckReevaluationResponse": {
+            "description": "Проверки, признанные безопасными, в которых пользователь был в зоне активного инцидента",
+            "type": "object",
+            "properties": {
+                "checked": {
+                    "type": "integer"
+                },
+                "discrepancies": {
+                    "description": "Discrepancies - проверки с is_dangerous = false; incident_ids - инциденты, найденные при повторной оценке",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.LocationCheckResponse"
+                    }
+                },
+                "from": {
+                    "type": "string"
+                },
+                "next_after_id": {
+                    "description": "NextAfterID - значение after_id для следующей страницы; отсутствует на последней странице",
+                    "type": "integer"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "webhooks_published": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v1.LocationCheckRequest": {
+            "description": "DTO для проверки координат",
+            "type": "object",
+            "required": [
+                "latitude",
+                "longitude",
+                "user_id"
+            ],
+            "properties": {
+                "buffer_meters": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "min_confidence": {
+                    "description": "MinConfidence - не учитывать инциденты с меньшей достоверностью (неоцененные учитываются всегда)",
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 0
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.LocationCheckResponse": {
+            "description": "DTO для записи истории проверок местоположения",
+            "type": "object",
+            "properties": {
+                "authenticated": {
+                    "description": "Authenticated - проверка выполнена клиентом с API-ключом",
+                    "type": "boolean"
+                },
+                "checked_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "incident_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "is_dangerous": {
+                    "type": "boolean"
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.LocationCheckResultResponse": {
+            "description": "DTO результата проверки местоположения в форме объекта",
+            "type": "object",
+            "properties": {
+                "dangerous": {
+                    "description": "Dangerous - точка попала в зону хотя бы одного активного инцидента",
+                    "type": "boolean"
+                },
+                "incidents": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.IncidentResponse"
+                    }
+                },
+                "matched": {
+                    "description": "Matched - число всех совпавших инцидентов, передается вместе с truncated",
+                    "type": "integer"
+                },
+                "truncated": {
+                    "description": "Truncated - возвращены не все совпавшие инциденты (MAX_MATCHED_INCIDENTS); без усечения поле не передается",
+                    "type": "boolean"
+                }
+            }
+        },
+        "v1.MergeIncidentsRequest": {
+            "description": "DTO для слияния дублирующихся инцидентов; первый из ids становится каноническим",
+            "type": "object",
+            "required": [
+                "ids"
+            ],
+            "properties": {
+                "ids": {
+                    "type": "array",
+                    "maxItems": 50,
+                    "minItems": 2,
+                    "uniqueItems": true,
+                    "items": {
+                        "type": "string"
                     }
                 }
             }
         },
-        "/system/health": {
-            "get": {
-                "description": "Get health status of the application",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "System"
-                ],
-                "summary": "Get application health status",
-                "responses": {
-                    "200": {
-                        "description": "Status OK",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
+        "v1.MergeIncidentsResponse": {
+            "description": "DTO для ответа на слияние инцидентов",
+            "type": "object",
+            "properties": {
+                "incident": {
+                    "$ref": "#/definitions/v1.IncidentResponse"
+                },
+                "merged_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
                     }
+                },
+                "redirected_checks": {
+                    "type": "integer",
+                    "example": 12
                 }
             }
-        }
-    },
-    "definitions": {
-        "v1.CreateIncidentRequest": {
-            "description": "DTO для создания инцидента",
+        },
+        "v1.OverlappingIncidentResponse": {
+            "description": "DTO для инцидента, зона которого пересекается с зоной другого инцидента",
+            "type": "object",
+            "properties": {
+                "incident": {
+                    "$ref": "#/definitions/v1.IncidentResponse"
+                },
+                "overlap_area_sq_meters": {
+                    "type": "number"
+                }
+            }
+        },
+        "v1.PreviewImpactRequest": {
+            "description": "DTO для оценки числа пользователей, которых затронул бы гипотетический инцидент",
             "type": "object",
             "required": [
                 "latitude",
                 "longitude",
-                "name",
                 "radius_meters"
             ],
             "properties": {
-                "description": {
-                    "type": "string"
-                },
                 "latitude": {
                     "type": "number"
                 },
                 "longitude": {
                     "type": "number"
                 },
-                "name": {
-                    "type": "string",
-                    "maxLength": 255,
-                    "minLength": 2
-                },
                 "radius_meters": {
                     "type": "integer"
                 }
             }
         },
-        "v1.IncidentResponse": {
-            "description": "DTO для ответа с информацией об инциденте",
+        "v1.PreviewImpactResponse": {
+            "description": "DTO для ответа с оценкой числа пользователей в зоне гипотетического инцидента",
             "type": "object",
             "properties": {
-                "created_at": {
+                "user_count": {
+                    "type": "integer"
+                },
+                "window_minutes": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v1.PublicIncidentResponse": {
+            "description": "Инцидент в публичной ленте без служебных полей",
+            "type": "object",
+            "properties": {
+                "category": {
                     "type": "string"
                 },
                 "description": {
@@ -541,7 +5999,7 @@ const docTemplate = `{
                 "radius_meters": {
                     "type": "integer"
                 },
-                "status": {
+                "severity": {
                     "type": "string"
                 },
                 "updated_at": {
@@ -549,37 +6007,44 @@ const docTemplate = `{
                 }
             }
         },
-        "v1.LocationCheckRequest": {
-            "description": "DTO для проверки координат",
+        "v1.StatsResponse": {
+            "description": "DTO для ответа со статистикой",
             "type": "object",
-            "required": [
-                "latitude",
-                "longitude",
-                "user_id"
-            ],
             "properties": {
-                "latitude": {
-                    "type": "number"
-                },
-                "longitude": {
-                    "type": "number"
+                "user_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v1.TimelineBucketResponse": {
+            "description": "DTO для корзины временной шкалы",
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
                 },
-                "user_id": {
+                "start": {
                     "type": "string"
                 }
             }
         },
-        "v1.StatsResponse": {
-            "description": "DTO для ответа со статистикой",
+        "v1.TimelineResponse": {
+            "description": "DTO для временной шкалы создания инцидентов",
             "type": "object",
             "properties": {
-                "user_count": {
-                    "type": "integer"
+                "buckets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.TimelineBucketResponse"
+                    }
+                },
+                "interval": {
+                    "type": "string"
                 }
             }
         },
         "v1.UpdateIncidentRequest": {
-            "description": "DTO для обновления инцидента",
+            "description": "DTO для полного обновления инцидента: пропущенные поля сбрасываются (silent и public - в false), кроме source, severity и external_id, которые без значения сохраняются",
             "type": "object",
             "required": [
                 "latitude",
@@ -589,29 +6054,297 @@ const docTemplate = `{
                 "status"
             ],
             "properties": {
+                "category": {
+                    "description": "Category - категория опасности; правила радиуса задаются в INCIDENT_CATEGORY_RULES",
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "confidence": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 0
+                },
                 "description": {
                     "type": "string"
                 },
+                "external_id": {
+                    "description": "ExternalID - идентификатор инцидента во внешней системе; если не указан, сохраняется текущий",
+                    "type": "string",
+                    "maxLength": 255
+                },
                 "latitude": {
                     "type": "number"
                 },
                 "longitude": {
                     "type": "number"
                 },
+                "media_urls": {
+                    "description": "MediaURLs - необязательные ссылки на медиа, проверяются как в CreateIncidentRequest",
+                    "type": "array",
+                    "maxItems": 10,
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "name": {
                     "type": "string",
                     "maxLength": 255,
                     "minLength": 2
                 },
+                "public": {
+                    "description": "Public - публиковать инцидент в открытой ленте GET /public/incidents; если не указан, сбрасывается в false",
+                    "type": "boolean"
+                },
                 "radius_meters": {
                     "type": "integer"
                 },
+                "resolution_reason": {
+                    "description": "ResolutionReason - причина завершения инцидента",
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "severity": {
+                    "description": "Severity - уровень опасности; если не указан, сохраняется текущий",
+                    "type": "string",
+                    "enum": [
+                        "minor",
+                        "moderate",
+                        "major",
+                        "critical"
+                    ]
+                },
+                "silent": {
+                    "description": "Silent - не отправлять вебхуки об опасности по этому инциденту (временный режим для тестирования);\nесли не указан, сбрасывается в false",
+                    "type": "boolean"
+                },
+                "source": {
+                    "description": "Source - источник сведений; если не указан, сохраняется текущий",
+                    "type": "string",
+                    "enum": [
+                        "official",
+                        "crowdsourced",
+                        "sensor"
+                    ]
+                },
                 "status": {
                     "type": "string",
                     "enum": [
                         "active",
-                        "inactive"
+                        "inactive",
+                        "draft",
+                        "resolved"
+                    ]
+                }
+            }
+        },
+        "v1.UserAlertResponse": {
+            "description": "DTO для активных инцидентов, затрагивающих сохраненное место пользователя",
+            "type": "object",
+            "properties": {
+                "incidents": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.IncidentResponse"
+                    }
+                },
+                "label": {
+                    "type": "string"
+                },
+                "location": {
+                    "$ref": "#/definitions/v1.UserLocationResponse"
+                }
+            }
+        },
+        "v1.UserLocationRequest": {
+            "description": "DTO для сохранения места пользователя",
+            "type": "object",
+            "required": [
+                "label",
+                "latitude",
+                "longitude"
+            ],
+            "properties": {
+                "label": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                }
+            }
+        },
+        "v1.UserLocationResponse": {
+            "description": "DTO для ответа с сохраненным местом пользователя",
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.UserStatusResponse": {
+            "description": "DTO для текущего статуса опасности пользователя в точке последней проверки",
+            "type": "object",
+            "properties": {
+                "checked_at": {
+                    "type": "string"
+                },
+                "incidents": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.IncidentResponse"
+                    }
+                },
+                "is_dangerous": {
+                    "type": "boolean"
+                },
+                "latitude": {
+                    "description": "Latitude, Longitude и CheckedAt - координаты и время последней проверки",
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "status": {
+                    "description": "Status - danger, если точка последней проверки сейчас в зоне активного инцидента, иначе safe",
+                    "type": "string",
+                    "enum": [
+                        "danger",
+                        "safe"
                     ]
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.ValidationErrorResponse": {
+            "description": "DTO для ответа с ошибками валидации",
+            "type": "object",
+            "properties": {
+                "details": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.FieldError"
+                    }
+                },
+                "error": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.WatchIncidentRequest": {
+            "description": "Адрес, на который доставляются вебхуки о каждом изменении инцидента",
+            "type": "object",
+            "required": [
+                "url"
+            ],
+            "properties": {
+                "secret": {
+                    "description": "Secret - секрет подписи HMAC; если не задан, вебхуки наблюдателя не подписываются",
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "url": {
+                    "type": "string",
+                    "maxLength": 2048,
+                    "example": "https://hooks.example.org/incident-watch"
+                }
+            }
+        },
+        "v1.WatcherResponse": {
+            "description": "Наблюдатель инцидента",
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "incident_id": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.WebhookCircuitResponse": {
+            "description": "DTO состояния цепи адреса доставки вебхуков",
+            "type": "object",
+            "properties": {
+                "consecutive_failures": {
+                    "type": "integer"
+                },
+                "destination": {
+                    "description": "Destination - хеш адреса, которым он помечен в метрике geo_webhook_circuit_state",
+                    "type": "string",
+                    "example": "9f86d081884c7d65"
+                },
+                "opened_at": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string",
+                    "example": "open"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.WebhookCircuitsResponse": {
+            "description": "DTO для ответа с состояниями цепей адресов доставки вебхуков",
+            "type": "object",
+            "properties": {
+                "circuits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.WebhookCircuitResponse"
+                    }
+                },
+                "dead_letter_depth": {
+                    "type": "integer"
+                },
+                "failure_threshold": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v1.WebhookQueueResponse": {
+            "description": "DTO для ответа с состоянием очереди вебхуков",
+            "type": "object",
+            "properties": {
+                "alarm_threshold": {
+                    "type": "integer"
+                },
+                "backend": {
+                    "type": "string"
+                },
+                "depth": {
+                    "type": "integer"
                 }
             }
         }
@@ -1,25 +1,69 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
+	"net"
+	"net/url"
 	"os"
+	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
-	"github.com/joho/godotenv"
+	"github.com/shenikar/geo_broadcasting_system/internal/models"
+	"github.com/shenikar/geo_broadcasting_system/pkg/geo"
+	"github.com/sirupsen/logrus"
 )
 
+// minWebhookTimeout - минимальный таймаут HTTP-клиента вебхуков; меньшие значения поднимаются до него
+const minWebhookTimeout = 1 * time.Second
+
+// iso8601DurationPattern разбирает длительности ISO 8601 вида P1D, PT30S, PT1H30M
+var iso8601DurationPattern = regexp.MustCompile(`^P(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+(?:\.\d+)?)S)?)?$`)
+
 // Config - структура для хранения конфигурации приложения
 type Config struct {
 	DatabaseURL string `env:"DATABASE_URL"`
 	HTTPPort    string `env:"HTTP_PORT" envDefault:"8080"`
 	LogLevel    string `env:"LOG_LEVEL" envDefault:"info"`
+	// OpenAPIHost - хост API (например, api.example.com) в спецификации /openapi.json, /openapi.yaml и Swagger UI;
+	// пусто - host в спецификации не указывается, и клиенты обращаются к хосту, с которого ее загрузили
+	OpenAPIHost string `env:"OPENAPI_HOST"`
+	// RequestTimeout - максимальное время обработки HTTP-запроса, после которого клиент получает 504; 0 - без ограничения
+	RequestTimeout time.Duration `env:"REQUEST_TIMEOUT" envDefault:"15s"`
+	// ShutdownTimeout - сколько при остановке ждать завершения обрабатываемых запросов
+	ShutdownTimeout time.Duration `env:"SHUTDOWN_TIMEOUT" envDefault:"5s"`
+	// TrustedProxies - IP-адреса и подсети обратных прокси, которым доверяется X-Forwarded-For при определении
+	// адреса клиента; пусто - заголовок игнорируется и адресом клиента считается адрес соединения
+	TrustedProxies []string `env:"TRUSTED_PROXIES"`
+	// DefaultLanguage - язык сообщений валидации, если клиент не передал Accept-Language (en или ru)
+	DefaultLanguage string `env:"DEFAULT_LANGUAGE" envDefault:"en"`
+	// LocationCheckResponse - форма ответа POST /location/check по умолчанию: array - массив инцидентов,
+	// object - объект с признаком dangerous и списком incidents. Клиент может запросить объект заголовком Accept
+	LocationCheckResponse string `env:"LOCATION_CHECK_RESPONSE" envDefault:"array"`
+	// StrictQueryParams - отклонять запросы с нераспознанными параметрами строки запроса (400 со списком параметров)
+	StrictQueryParams bool `env:"STRICT_QUERY_PARAMS" envDefault:"false"`
+	// FeatureFlags - включение и отключение функций по именам (ключ - имя в нижнем регистре).
+	// Функции, которых нет в списке, включены
+	FeatureFlags map[string]bool `env:"FEATURE_FLAGS"`
+	// FeatureFlagsRedis - читать флаги из хеша feature_flags в Redis; значения оттуда переопределяют FEATURE_FLAGS
+	FeatureFlagsRedis bool `env:"FEATURE_FLAGS_REDIS" envDefault:"false"`
 
 	// Redis Config
 	RedisAddr string `env:"REDIS_ADDR" envDefault:"localhost:6379"`
 	RedisPass string `env:"REDIS_PASSWORD"`
 	RedisDB   int    `env:"REDIS_DB" envDefault:"0"`
+	// RedisKeyPrefix - префикс всех ключей Redis, позволяет разделять окружения на одном инстансе
+	RedisKeyPrefix string `env:"REDIS_KEY_PREFIX"`
+	// RedisRequired - останавливать запуск, если Redis недоступен; false - работать в режиме деградации:
+	// кэш читается напрямую из PostgreSQL, вебхуки копятся в памяти до восстановления Redis
+	RedisRequired bool `env:"REDIS_REQUIRED" envDefault:"true"`
+	// RedisHealthCheckInterval - период проверки доступности Redis в режиме деградации
+	RedisHealthCheckInterval time.Duration `env:"REDIS_HEALTH_CHECK_INTERVAL" envDefault:"5s"`
+	// WebhookFallbackQueueSize - сколько вебхуков копится в памяти, пока Redis недоступен; сверх этого отбрасываются самые старые
+	WebhookFallbackQueueSize int `env:"WEBHOOK_FALLBACK_QUEUE_SIZE" envDefault:"10000"`
 
 	// Webhook Config
 	WebhookURL        string        `env:"WEBHOOK_URL"`
@@ -27,52 +71,662 @@ type Config struct {
 	WebhookTimeout    time.Duration `env:"WEBHOOK_TIMEOUT" envDefault:"5s"`
 	WebhookMaxRetries int           `env:"WEBHOOK_MAX_RETRIES" envDefault:"5"`
 	WebhookBaseDelay  time.Duration `env:"WEBHOOK_BASE_DELAY_SECONDS" envDefault:"1s"`
+	// WebhookMaxConcurrentPerURL - максимальное число одновременных доставок на один адрес назначения
+	WebhookMaxConcurrentPerURL int `env:"WEBHOOK_MAX_CONCURRENT_PER_URL" envDefault:"4"`
+	// WebhookCircuitFailureThreshold - после скольких неудачных попыток подряд размыкать цепь адреса:
+	// доставки на него сразу уходят в очередь недоставленных до конца WebhookCircuitCooldown. 0 - без размыкания
+	WebhookCircuitFailureThreshold int `env:"WEBHOOK_CIRCUIT_FAILURE_THRESHOLD" envDefault:"5"`
+	// WebhookCircuitCooldown - сколько цепь остается разомкнутой, прежде чем пропустить пробную доставку
+	WebhookCircuitCooldown time.Duration `env:"WEBHOOK_CIRCUIT_COOLDOWN" envDefault:"1m"`
+	// WebhookDeadLetterMaxLen - сколько последних недоставленных вебхуков хранить в очереди недоставленных
+	WebhookDeadLetterMaxLen int `env:"WEBHOOK_DEAD_LETTER_MAX_LEN" envDefault:"10000"`
+	// WebhookBatchSize - сколько событий объединять в один запрос (JSON-массив); 1 - каждое событие отдельно
+	WebhookBatchSize int `env:"WEBHOOK_BATCH_SIZE" envDefault:"1"`
+	// WebhookBatchWindow - сколько ждать накопления пакета после первого события; по истечении пакет отправляется неполным
+	WebhookBatchWindow time.Duration `env:"WEBHOOK_BATCH_WINDOW" envDefault:"1s"`
+	// WebhookQueueBackend - бэкенд очереди вебхуков: list (по умолчанию) или streams
+	WebhookQueueBackend string `env:"WEBHOOK_QUEUE_BACKEND" envDefault:"list"`
+	// WebhookStreamClaimIdle - время простоя, после которого неподтвержденное сообщение потока забирается другим воркером
+	WebhookStreamClaimIdle time.Duration `env:"WEBHOOK_STREAM_CLAIM_IDLE" envDefault:"1m"`
+	// WebhookQueueCheckInterval - период проверки глубины очереди вебхуков
+	WebhookQueueCheckInterval time.Duration `env:"WEBHOOK_QUEUE_CHECK_INTERVAL" envDefault:"30s"`
+	// WebhookQueueAlarmThreshold - глубина очереди, при превышении которой пишется предупреждение; 0 - без предупреждений
+	WebhookQueueAlarmThreshold int `env:"WEBHOOK_QUEUE_ALARM_THRESHOLD" envDefault:"1000"`
+	// PoolStatsInterval - период публикации статистики пулов соединений PostgreSQL и Redis
+	PoolStatsInterval time.Duration `env:"POOL_STATS_INTERVAL" envDefault:"15s"`
+	// DBAcquireWaitThreshold - среднее время получения соединения из пула БД, при превышении которого пишется предупреждение; 0 - без предупреждений
+	DBAcquireWaitThreshold time.Duration `env:"DB_ACQUIRE_WAIT_THRESHOLD" envDefault:"100ms"`
+	// SlowQueryThreshold - длительность запроса к PostgreSQL, при превышении которой пишется предупреждение; 0 - без предупреждений
+	SlowQueryThreshold time.Duration `env:"SLOW_QUERY_THRESHOLD" envDefault:"500ms"`
+	// LongPollMaxTimeout - наибольшее время ожидания изменений в GET /incidents/poll; больший timeout уменьшается до него
+	LongPollMaxTimeout time.Duration `env:"LONG_POLL_MAX_TIMEOUT" envDefault:"60s"`
+	// LongPollMaxClients - наибольшее число одновременных запросов GET /incidents/poll в одном экземпляре сервиса
+	LongPollMaxClients int `env:"LONG_POLL_MAX_CLIENTS" envDefault:"500"`
+	// ImportURLAllowedHosts - хосты, с которых разрешен импорт инцидентов по URL (POST /incidents/import-url); пусто - любые
+	ImportURLAllowedHosts []string `env:"IMPORT_URL_ALLOWED_HOSTS"`
+	// WatchURLAllowedHosts - хосты, на которые разрешено отправлять вебхуки наблюдателей инцидентов
+	// (POST /incidents/{id}/watch); пусто - любые
+	WatchURLAllowedHosts []string `env:"WATCH_URL_ALLOWED_HOSTS"`
+	// ImportURLMaxBytes - наибольший размер GeoJSON-ленты при импорте по URL
+	ImportURLMaxBytes int `env:"IMPORT_URL_MAX_BYTES" envDefault:"10485760"`
+	// ImportURLTimeout - наибольшее время загрузки GeoJSON-ленты при импорте по URL
+	ImportURLTimeout time.Duration `env:"IMPORT_URL_TIMEOUT" envDefault:"30s"`
+	// ImportMaxConcurrent - наибольшее число одновременных фоновых импортов по URL в одном экземпляре; сверх него - 429
+	ImportMaxConcurrent int `env:"IMPORT_MAX_CONCURRENT" envDefault:"4"`
+	// ImportFeedSyncInterval - период повторной синхронизации зарегистрированных GeoJSON-лент; 0 - синхронизация отключена
+	ImportFeedSyncInterval time.Duration `env:"IMPORT_FEED_SYNC_INTERVAL" envDefault:"15m"`
+	// S3Bucket - бакет S3-совместимого хранилища для вложений инцидентов; пусто - вложения отключены
+	S3Bucket string `env:"S3_BUCKET"`
+	// S3Endpoint - адрес S3-совместимого хранилища
+	S3Endpoint string `env:"S3_ENDPOINT" envDefault:"https://s3.amazonaws.com"`
+	S3Region   string `env:"S3_REGION" envDefault:"us-east-1"`
+	// S3AccessKeyID и S3SecretAccessKey - ключи, которыми подписываются presigned URL
+	S3AccessKeyID     string `env:"S3_ACCESS_KEY_ID"`
+	S3SecretAccessKey string `env:"S3_SECRET_ACCESS_KEY"`
+	// S3PathStyle - адресовать бакет путем (endpoint/bucket/key), как требуют MinIO и большинство S3-совместимых хранилищ
+	S3PathStyle bool `env:"S3_PATH_STYLE" envDefault:"true"`
+	// S3PresignTTL - срок действия presigned URL для загрузки и скачивания вложений
+	S3PresignTTL time.Duration `env:"S3_PRESIGN_TTL" envDefault:"15m"`
+	// WebhookPayloadTemplate - путь к файлу text/template для тела вебхука; пусто - отправляется JSON события
+	WebhookPayloadTemplate string `env:"WEBHOOK_PAYLOAD_TEMPLATE"`
+	// WebhookSubscriptionsFile - путь к JSON-файлу с дополнительными подписками на вебхуки (адрес, секрет, регион)
+	WebhookSubscriptionsFile string `env:"WEBHOOK_SUBSCRIPTIONS_FILE"`
+	// WebhookTLSMinVersion - минимальная версия TLS при доставке вебхуков
+	WebhookTLSMinVersion uint16 `env:"WEBHOOK_TLS_MIN_VERSION" envDefault:"1.2"`
+	// WebhookTLSCipherSuites - разрешенные наборы шифров для TLS 1.0-1.2; пусто - наборы Go по умолчанию
+	WebhookTLSCipherSuites []uint16 `env:"WEBHOOK_TLS_CIPHER_SUITES"`
+	// WebhookTLSCAFile - PEM-файл с корневыми сертификатами для проверки получателей вместо системных
+	WebhookTLSCAFile string `env:"WEBHOOK_TLS_CA_FILE"`
+	// WebhookTLSPinnedKeys - SHA-256 отпечатки открытых ключей (SPKI), один из которых должен быть в цепочке получателя
+	WebhookTLSPinnedKeys []string `env:"WEBHOOK_TLS_PINNED_SHA256"`
+	// WebhookFollowRedirects - следовать перенаправлениям при отправке вебхуков
+	WebhookFollowRedirects bool `env:"WEBHOOK_FOLLOW_REDIRECTS" envDefault:"true"`
+	// WebhookQuietHours - окно тишины: опасные события, опубликованные в нем, доставляются после его окончания
+	WebhookQuietHours QuietHours `env:"WEBHOOK_QUIET_HOURS"`
+	// WebhookQuietHoursBypassCategories - категории инцидентов, события по которым доставляются и в окно тишины
+	WebhookQuietHoursBypassCategories []string `env:"WEBHOOK_QUIET_HOURS_BYPASS_CATEGORIES"`
+	// WebhookOutboxEnabled - записывать вебхуки проверок местоположения в outbox в одной транзакции с проверкой
+	WebhookOutboxEnabled bool `env:"WEBHOOK_OUTBOX_ENABLED" envDefault:"true"`
+	// WebhookOutboxPollInterval - период переноса сообщений outbox в очередь вебхуков
+	WebhookOutboxPollInterval time.Duration `env:"WEBHOOK_OUTBOX_POLL_INTERVAL" envDefault:"1s"`
+	// WebhookOutboxBatchSize - количество сообщений outbox, переносимых одной транзакцией
+	WebhookOutboxBatchSize int `env:"WEBHOOK_OUTBOX_BATCH_SIZE" envDefault:"100"`
+
+	// Notifier Config
+	// Notifier - провайдер уведомлений об опасных событиях: пусто (отключено), smtp или twilio
+	Notifier string `env:"NOTIFIER"`
+	// NotifyRecipients - адреса email или номера телефонов получателей уведомлений
+	NotifyRecipients []string `env:"NOTIFY_RECIPIENTS"`
+	SMTPAddr         string   `env:"SMTP_ADDR"`
+	SMTPUsername     string   `env:"SMTP_USERNAME"`
+	SMTPPassword     string   `env:"SMTP_PASSWORD"`
+	SMTPFrom         string   `env:"SMTP_FROM"`
+	TwilioAccountSID string   `env:"TWILIO_ACCOUNT_SID"`
+	TwilioAuthToken  string   `env:"TWILIO_AUTH_TOKEN"`
+	TwilioFrom       string   `env:"TWILIO_FROM"`
+
+	// Incident Config
+	// DefaultIncidentStatus - статус новых инцидентов, если он не указан в запросе (active или draft)
+	DefaultIncidentStatus models.IncidentStatus `env:"DEFAULT_INCIDENT_STATUS" envDefault:"active"`
+	// SanitizeIncidentText - очищать название и описание инцидентов от HTML и управляющих символов
+	SanitizeIncidentText bool `env:"SANITIZE_INCIDENT_TEXT" envDefault:"true"`
+	// IncidentCategoryRules - допустимый радиус и радиус по умолчанию по категориям опасности (ключ - категория в нижнем регистре)
+	IncidentCategoryRules map[string]CategoryRule `env:"INCIDENT_CATEGORY_RULES"`
+	// CoordinateAllowRegions и CoordinateDenyRegions ограничивают координаты инцидентов и проверок местоположения:
+	// точка должна попасть в одну из разрешенных областей (если они заданы) и не попасть ни в одну из запрещенных
+	CoordinateAllowRegions []geo.Region `env:"COORDINATE_ALLOW_REGIONS"`
+	CoordinateDenyRegions  []geo.Region `env:"COORDINATE_DENY_REGIONS"`
+
+	// IncidentRetentionDays - через сколько дней после деактивации инцидент удаляется безвозвратно; 0 - никогда
+	IncidentRetentionDays int `env:"INCIDENT_RETENTION_DAYS" envDefault:"0"`
+	// IncidentPurgeInterval - период запуска задачи очистки неактивных инцидентов
+	IncidentPurgeInterval time.Duration `env:"INCIDENT_PURGE_INTERVAL" envDefault:"1h"`
+	// IncidentPurgeBatchSize - количество инцидентов, удаляемых одним запросом
+	IncidentPurgeBatchSize int `env:"INCIDENT_PURGE_BATCH_SIZE" envDefault:"500"`
+
+	// AutoEscalateDangerousUsers - сколько разных пользователей должно попасть в зону активного инцидента
+	// за AutoEscalateWindow, чтобы его уровень опасности повысился автоматически; 0 - автоэскалация отключена
+	AutoEscalateDangerousUsers int `env:"AUTO_ESCALATE_DANGEROUS_USERS" envDefault:"0"`
+	// AutoEscalateWindow - окно подсчета пользователей; инцидент повышается не чаще одного раза за окно
+	AutoEscalateWindow time.Duration `env:"AUTO_ESCALATE_WINDOW" envDefault:"15m"`
+	// AutoEscalateInterval - период проверки инцидентов на автоэскалацию
+	AutoEscalateInterval time.Duration `env:"AUTO_ESCALATE_INTERVAL" envDefault:"1m"`
+	// HotspotMinIncidents - сколько активных инцидентов должно попасть в ячейку сетки, чтобы она считалась
+	// очагом в GET /incidents/hotspots (если порог не передан в запросе)
+	HotspotMinIncidents int `env:"HOTSPOT_MIN_INCIDENTS" envDefault:"5"`
+
+	// Location Check Config
+	MaxCheckBufferMeters int `env:"MAX_CHECK_BUFFER_METERS" envDefault:"100"`
+	// AlertBufferMeters - дополнительный буфер по уровням опасности, который добавляется к радиусу инцидента
+	// только при отборе инцидентов для вебхука проверки местоположения (вторая ступень сопоставления).
+	// Ответ пользователю и сохраненная проверка его не учитывают; уровень без значения - буфер 0.
+	AlertBufferMeters map[models.IncidentSeverity]int `env:"ALERT_BUFFER_BY_SEVERITY"`
+	// MaxMatchedIncidents - сколько совпавших инцидентов возвращает проверка местоположения (самые опасные и близкие);
+	// 0 - без ограничения. Сохраненная проверка и вебхук содержат все совпавшие инциденты
+	MaxMatchedIncidents int `env:"MAX_MATCHED_INCIDENTS" envDefault:"0"`
+	// LocationTileCacheTTL - срок жизни кэша инцидентов по geohash-ячейкам; 0 - проверка всегда идет в PostGIS
+	LocationTileCacheTTL time.Duration `env:"LOCATION_TILE_CACHE_TTL" envDefault:"30s"`
+	// LocationTilePrecision - длина geohash ячейки кэша (1-12): чем больше, тем мельче ячейка
+	LocationTilePrecision int `env:"LOCATION_TILE_PRECISION" envDefault:"6"`
+	// LocationIndexRefreshInterval - период перестроения индекса активных инцидентов в памяти процесса;
+	// 0 - индекс отключен и проверка идет через кэш ячеек или PostGIS
+	LocationIndexRefreshInterval time.Duration `env:"LOCATION_INDEX_REFRESH_INTERVAL" envDefault:"0"`
+	// UserCheckQuotaPerMinute - сколько проверок местоположения один user_id может выполнить за скользящую минуту;
+	// сверх квоты запросы отклоняются с кодом 429. 0 - без ограничения
+	UserCheckQuotaPerMinute int `env:"USER_CHECK_QUOTA_PER_MINUTE" envDefault:"0"`
+
+	// Public Feed Config
+	// PublicFeedRateLimitPerMinute - сколько запросов к публичной ленте инцидентов один IP может выполнить
+	// за скользящую минуту; 0 - без ограничения
+	PublicFeedRateLimitPerMinute int `env:"PUBLIC_FEED_RATE_LIMIT_PER_MINUTE" envDefault:"60"`
+	// PublicFeedExcludedCategories - служебные категории, инциденты которых не попадают в публичную ленту
+	// даже с флагом public
+	PublicFeedExcludedCategories []string `env:"PUBLIC_FEED_EXCLUDED_CATEGORIES"`
 
 	// Stats Config
 	StatsTimeWindowMinutes int `env:"STATS_TIME_WINDOW_MINUTES" envDefault:"60"`
+	// StatsCacheTTL - срок жизни кэша статистики в Redis; 0 - статистика не кэшируется
+	StatsCacheTTL time.Duration `env:"STATS_CACHE_TTL" envDefault:"30s"`
+	// IncidentViewFlushInterval - период переноса счетчиков просмотров инцидентов из Redis в PostgreSQL;
+	// 0 - просмотры не учитываются
+	IncidentViewFlushInterval time.Duration `env:"INCIDENT_VIEW_FLUSH_INTERVAL" envDefault:"1m"`
 
 	// API Keys for authentication
 	APIKeys []string `env:"API_KEYS"`
+	// AdminAPIKeys - ключи с правами администратора (модерация и служебные операции)
+	AdminAPIKeys []string `env:"ADMIN_API_KEYS"`
+	// APIKeyCacheTTL - как долго набор ключей, добавленных через API, кэшируется в памяти процесса;
+	// 0 - Redis опрашивается при каждом запросе
+	APIKeyCacheTTL time.Duration `env:"API_KEY_CACHE_TTL" envDefault:"10s"`
+	// APIKeyUsageWindow - скользящее окно учета запросов по API-ключам (шаг окна - минута)
+	APIKeyUsageWindow time.Duration `env:"API_KEY_USAGE_WINDOW" envDefault:"1h"`
+
+	// reloadMu защищает поля, которые обновляются при перезагрузке конфигурации (см. Reload)
+	reloadMu sync.RWMutex
+}
+
+// CategoryRule - правила радиуса зоны для категории опасности. Нулевые границы не проверяются.
+type CategoryRule struct {
+	MinRadiusMeters     int `json:"min_radius_meters"`
+	MaxRadiusMeters     int `json:"max_radius_meters"`
+	DefaultRadiusMeters int `json:"default_radius_meters"`
 }
 
 // LoadConfig загружает конфигурацию из переменных окружения и .env файла
 func LoadConfig() (*Config, error) {
 	// Загрузка переменных окружения из .env файла (если есть)
-	if err := godotenv.Load(); err != nil && !os.IsNotExist(err) {
+	if err := loadDotEnv(); err != nil {
 		return nil, fmt.Errorf("ошибка загрузки файла .env: %w", err)
 	}
 
+	requestTimeout, err := getEnvAsDuration("REQUEST_TIMEOUT", 15*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	shutdownTimeout, err := getEnvAsDuration("SHUTDOWN_TIMEOUT", 5*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	webhookTimeout, err := getEnvAsDuration("WEBHOOK_TIMEOUT", 5*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	webhookCircuitCooldown, err := getEnvAsDuration("WEBHOOK_CIRCUIT_COOLDOWN", time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	webhookBaseDelay, err := getEnvAsDuration("WEBHOOK_BASE_DELAY_SECONDS", 1*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	webhookBatchWindow, err := getEnvAsDuration("WEBHOOK_BATCH_WINDOW", 1*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	webhookStreamClaimIdle, err := getEnvAsDuration("WEBHOOK_STREAM_CLAIM_IDLE", 1*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	webhookQueueCheckInterval, err := getEnvAsDuration("WEBHOOK_QUEUE_CHECK_INTERVAL", 30*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	poolStatsInterval, err := getEnvAsDuration("POOL_STATS_INTERVAL", 15*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	dbAcquireWaitThreshold, err := getEnvAsDuration("DB_ACQUIRE_WAIT_THRESHOLD", 100*time.Millisecond)
+	if err != nil {
+		return nil, err
+	}
+	slowQueryThreshold, err := getEnvAsDuration("SLOW_QUERY_THRESHOLD", 500*time.Millisecond)
+	if err != nil {
+		return nil, err
+	}
+	longPollMaxTimeout, err := getEnvAsDuration("LONG_POLL_MAX_TIMEOUT", 60*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	importURLTimeout, err := getEnvAsDuration("IMPORT_URL_TIMEOUT", 30*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	importFeedSyncInterval, err := getEnvAsDuration("IMPORT_FEED_SYNC_INTERVAL", 15*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	redisHealthCheckInterval, err := getEnvAsDuration("REDIS_HEALTH_CHECK_INTERVAL", 5*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	s3PresignTTL, err := getEnvAsDuration("S3_PRESIGN_TTL", 15*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	incidentPurgeInterval, err := getEnvAsDuration("INCIDENT_PURGE_INTERVAL", 1*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	autoEscalateWindow, err := getEnvAsDuration("AUTO_ESCALATE_WINDOW", 15*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	autoEscalateInterval, err := getEnvAsDuration("AUTO_ESCALATE_INTERVAL", time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	statsCacheTTL, err := getEnvAsDuration("STATS_CACHE_TTL", 30*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	incidentViewFlushInterval, err := getEnvAsDuration("INCIDENT_VIEW_FLUSH_INTERVAL", time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	locationTileCacheTTL, err := getEnvAsDuration("LOCATION_TILE_CACHE_TTL", 30*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	locationIndexRefreshInterval, err := getEnvAsDuration("LOCATION_INDEX_REFRESH_INTERVAL", 0)
+	if err != nil {
+		return nil, err
+	}
+	webhookOutboxPollInterval, err := getEnvAsDuration("WEBHOOK_OUTBOX_POLL_INTERVAL", 1*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	apiKeyCacheTTL, err := getEnvAsDuration("API_KEY_CACHE_TTL", 10*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	apiKeyUsageWindow, err := getEnvAsDuration("API_KEY_USAGE_WINDOW", 1*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	webhookQuietHours, err := parseQuietHours("WEBHOOK_QUIET_HOURS", os.Getenv("WEBHOOK_QUIET_HOURS"), getEnv("WEBHOOK_QUIET_HOURS_TZ", "UTC"))
+	if err != nil {
+		return nil, err
+	}
+	featureFlags, err := getEnvAsFeatureFlags("FEATURE_FLAGS")
+	if err != nil {
+		return nil, err
+	}
+	alertBuffers, err := getEnvAsAlertBuffers("ALERT_BUFFER_BY_SEVERITY")
+	if err != nil {
+		return nil, err
+	}
+	categoryRules, err := getEnvAsCategoryRules("INCIDENT_CATEGORY_RULES")
+	if err != nil {
+		return nil, err
+	}
+	allowRegions, err := getEnvAsRegions("COORDINATE_ALLOW_REGIONS")
+	if err != nil {
+		return nil, err
+	}
+	denyRegions, err := getEnvAsRegions("COORDINATE_DENY_REGIONS")
+	if err != nil {
+		return nil, err
+	}
+
 	cfg := &Config{
-		DatabaseURL:            os.Getenv("DATABASE_URL"),
-		HTTPPort:               getEnv("HTTP_PORT", "8080"),
-		LogLevel:               getEnv("LOG_LEVEL", "info"),
-		RedisAddr:              getEnv("REDIS_ADDR", "localhost:6379"),
-		RedisPass:              os.Getenv("REDIS_PASSWORD"),
-		RedisDB:                getEnvAsInt("REDIS_DB", 0),
-		WebhookURL:             os.Getenv("WEBHOOK_URL"),
-		WebhookSecret:          os.Getenv("WEBHOOK_SECRET"),
-		WebhookTimeout:         getEnvAsDuration("WEBHOOK_TIMEOUT", 5*time.Second),
-		WebhookMaxRetries:      getEnvAsInt("WEBHOOK_MAX_RETRIES", 5),
-		WebhookBaseDelay:       getEnvAsDuration("WEBHOOK_BASE_DELAY_SECONDS", 1*time.Second),
-		StatsTimeWindowMinutes: getEnvAsInt("STATS_TIME_WINDOW_MINUTES", 60),
+		DatabaseURL:                    os.Getenv("DATABASE_URL"),
+		HTTPPort:                       getEnv("HTTP_PORT", "8080"),
+		LogLevel:                       getEnv("LOG_LEVEL", "info"),
+		OpenAPIHost:                    strings.TrimSpace(os.Getenv("OPENAPI_HOST")),
+		RequestTimeout:                 requestTimeout,
+		ShutdownTimeout:                shutdownTimeout,
+		DefaultLanguage:                getEnv("DEFAULT_LANGUAGE", "en"),
+		LocationCheckResponse:          getEnv("LOCATION_CHECK_RESPONSE", "array"),
+		StrictQueryParams:              getEnvAsBool("STRICT_QUERY_PARAMS", false),
+		FeatureFlags:                   featureFlags,
+		FeatureFlagsRedis:              getEnvAsBool("FEATURE_FLAGS_REDIS", false),
+		RedisAddr:                      getEnv("REDIS_ADDR", "localhost:6379"),
+		RedisPass:                      os.Getenv("REDIS_PASSWORD"),
+		RedisDB:                        getEnvAsInt("REDIS_DB", 0),
+		RedisKeyPrefix:                 os.Getenv("REDIS_KEY_PREFIX"),
+		RedisRequired:                  getEnvAsBool("REDIS_REQUIRED", true),
+		RedisHealthCheckInterval:       redisHealthCheckInterval,
+		WebhookFallbackQueueSize:       getEnvAsInt("WEBHOOK_FALLBACK_QUEUE_SIZE", 10000),
+		WebhookURL:                     os.Getenv("WEBHOOK_URL"),
+		WebhookSecret:                  os.Getenv("WEBHOOK_SECRET"),
+		WebhookTimeout:                 webhookTimeout,
+		WebhookMaxRetries:              getEnvAsInt("WEBHOOK_MAX_RETRIES", 5),
+		WebhookBaseDelay:               webhookBaseDelay,
+		WebhookMaxConcurrentPerURL:     getEnvAsInt("WEBHOOK_MAX_CONCURRENT_PER_URL", 4),
+		WebhookCircuitFailureThreshold: getEnvAsInt("WEBHOOK_CIRCUIT_FAILURE_THRESHOLD", 5),
+		WebhookCircuitCooldown:         webhookCircuitCooldown,
+		WebhookDeadLetterMaxLen:        getEnvAsInt("WEBHOOK_DEAD_LETTER_MAX_LEN", 10000),
+		WebhookBatchSize:               getEnvAsInt("WEBHOOK_BATCH_SIZE", 1),
+		WebhookBatchWindow:             webhookBatchWindow,
+		WebhookQueueBackend:            getEnv("WEBHOOK_QUEUE_BACKEND", "list"),
+		WebhookStreamClaimIdle:         webhookStreamClaimIdle,
+		WebhookQueueCheckInterval:      webhookQueueCheckInterval,
+		PoolStatsInterval:              poolStatsInterval,
+		DBAcquireWaitThreshold:         dbAcquireWaitThreshold,
+		SlowQueryThreshold:             slowQueryThreshold,
+		LongPollMaxTimeout:             longPollMaxTimeout,
+		LongPollMaxClients:             getEnvAsInt("LONG_POLL_MAX_CLIENTS", 500),
+		ImportURLMaxBytes:              getEnvAsInt("IMPORT_URL_MAX_BYTES", 10<<20),
+		ImportURLTimeout:               importURLTimeout,
+		ImportMaxConcurrent:            getEnvAsInt("IMPORT_MAX_CONCURRENT", 4),
+		ImportFeedSyncInterval:         importFeedSyncInterval,
+		S3Bucket:                       getEnv("S3_BUCKET", ""),
+		S3Endpoint:                     getEnv("S3_ENDPOINT", "https://s3.amazonaws.com"),
+		S3Region:                       getEnv("S3_REGION", "us-east-1"),
+		S3AccessKeyID:                  getEnv("S3_ACCESS_KEY_ID", ""),
+		S3SecretAccessKey:              getEnv("S3_SECRET_ACCESS_KEY", ""),
+		S3PathStyle:                    getEnvAsBool("S3_PATH_STYLE", true),
+		S3PresignTTL:                   s3PresignTTL,
+		WebhookQueueAlarmThreshold:     getEnvAsInt("WEBHOOK_QUEUE_ALARM_THRESHOLD", 1000),
+		WebhookPayloadTemplate:         os.Getenv("WEBHOOK_PAYLOAD_TEMPLATE"),
+		WebhookSubscriptionsFile:       os.Getenv("WEBHOOK_SUBSCRIPTIONS_FILE"),
+		WebhookTLSCAFile:               os.Getenv("WEBHOOK_TLS_CA_FILE"),
+		WebhookFollowRedirects:         getEnvAsBool("WEBHOOK_FOLLOW_REDIRECTS", true),
+		WebhookQuietHours:              webhookQuietHours,
+		WebhookOutboxEnabled:           getEnvAsBool("WEBHOOK_OUTBOX_ENABLED", true),
+		WebhookOutboxPollInterval:      webhookOutboxPollInterval,
+		WebhookOutboxBatchSize:         getEnvAsInt("WEBHOOK_OUTBOX_BATCH_SIZE", 100),
+		Notifier:                       os.Getenv("NOTIFIER"),
+		SMTPAddr:                       os.Getenv("SMTP_ADDR"),
+		SMTPUsername:                   os.Getenv("SMTP_USERNAME"),
+		SMTPPassword:                   os.Getenv("SMTP_PASSWORD"),
+		SMTPFrom:                       os.Getenv("SMTP_FROM"),
+		TwilioAccountSID:               os.Getenv("TWILIO_ACCOUNT_SID"),
+		TwilioAuthToken:                os.Getenv("TWILIO_AUTH_TOKEN"),
+		TwilioFrom:                     os.Getenv("TWILIO_FROM"),
+		DefaultIncidentStatus:          models.IncidentStatus(getEnv("DEFAULT_INCIDENT_STATUS", string(models.StatusActive))),
+		SanitizeIncidentText:           getEnvAsBool("SANITIZE_INCIDENT_TEXT", true),
+		IncidentCategoryRules:          categoryRules,
+		CoordinateAllowRegions:         allowRegions,
+		CoordinateDenyRegions:          denyRegions,
+		IncidentRetentionDays:          getEnvAsInt("INCIDENT_RETENTION_DAYS", 0),
+		IncidentPurgeInterval:          incidentPurgeInterval,
+		IncidentPurgeBatchSize:         getEnvAsInt("INCIDENT_PURGE_BATCH_SIZE", 500),
+		AutoEscalateDangerousUsers:     getEnvAsInt("AUTO_ESCALATE_DANGEROUS_USERS", 0),
+		AutoEscalateWindow:             autoEscalateWindow,
+		AutoEscalateInterval:           autoEscalateInterval,
+		HotspotMinIncidents:            getEnvAsInt("HOTSPOT_MIN_INCIDENTS", 5),
+		MaxCheckBufferMeters:           getEnvAsInt("MAX_CHECK_BUFFER_METERS", 100),
+		AlertBufferMeters:              alertBuffers,
+		MaxMatchedIncidents:            getEnvAsInt("MAX_MATCHED_INCIDENTS", 0),
+		LocationTileCacheTTL:           locationTileCacheTTL,
+		LocationTilePrecision:          getEnvAsInt("LOCATION_TILE_PRECISION", 6),
+		LocationIndexRefreshInterval:   locationIndexRefreshInterval,
+		UserCheckQuotaPerMinute:        getEnvAsInt("USER_CHECK_QUOTA_PER_MINUTE", 0),
+		PublicFeedRateLimitPerMinute:   getEnvAsInt("PUBLIC_FEED_RATE_LIMIT_PER_MINUTE", 60),
+		StatsTimeWindowMinutes:         getEnvAsInt("STATS_TIME_WINDOW_MINUTES", 60),
+		StatsCacheTTL:                  statsCacheTTL,
+		IncidentViewFlushInterval:      incidentViewFlushInterval,
+		APIKeyCacheTTL:                 apiKeyCacheTTL,
+		APIKeyUsageWindow:              apiKeyUsageWindow,
 	}
 
 	// Загрузка API ключей
-	apiKeysStr := os.Getenv("API_KEYS")
-	if apiKeysStr != "" {
-		cfg.APIKeys = strings.Split(apiKeysStr, ",")
-		for i, key := range cfg.APIKeys {
-			cfg.APIKeys[i] = strings.TrimSpace(key)
-		}
+	cfg.APIKeys = getEnvAsList("API_KEYS")
+	cfg.AdminAPIKeys = getEnvAsList("ADMIN_API_KEYS")
+	cfg.NotifyRecipients = getEnvAsList("NOTIFY_RECIPIENTS")
+	cfg.TrustedProxies = getEnvAsList("TRUSTED_PROXIES")
+	for _, host := range getEnvAsList("IMPORT_URL_ALLOWED_HOSTS") {
+		cfg.ImportURLAllowedHosts = append(cfg.ImportURLAllowedHosts, strings.ToLower(host))
+	}
+	for _, host := range getEnvAsList("WATCH_URL_ALLOWED_HOSTS") {
+		cfg.WatchURLAllowedHosts = append(cfg.WatchURLAllowedHosts, strings.ToLower(host))
+	}
+	for _, category := range getEnvAsList("WEBHOOK_QUIET_HOURS_BYPASS_CATEGORIES") {
+		cfg.WebhookQuietHoursBypassCategories = append(cfg.WebhookQuietHoursBypassCategories, strings.ToLower(category))
+	}
+	for _, category := range getEnvAsList("PUBLIC_FEED_EXCLUDED_CATEGORIES") {
+		cfg.PublicFeedExcludedCategories = append(cfg.PublicFeedExcludedCategories, strings.ToLower(category))
+	}
+
+	// Параметры TLS клиента вебхуков
+	if cfg.WebhookTLSMinVersion, err = parseTLSVersion("WEBHOOK_TLS_MIN_VERSION", getEnv("WEBHOOK_TLS_MIN_VERSION", "1.2")); err != nil {
+		return nil, err
+	}
+	if cfg.WebhookTLSCipherSuites, err = parseCipherSuites("WEBHOOK_TLS_CIPHER_SUITES", getEnvAsList("WEBHOOK_TLS_CIPHER_SUITES")); err != nil {
+		return nil, err
+	}
+	if cfg.WebhookTLSPinnedKeys, err = parsePins("WEBHOOK_TLS_PINNED_SHA256", getEnvAsList("WEBHOOK_TLS_PINNED_SHA256")); err != nil {
+		return nil, err
 	}
 
 	if cfg.DatabaseURL == "" {
 		return nil, fmt.Errorf("DATABASE_URL environment variable is required")
 	}
 
+	if cfg.WebhookQueueBackend != "list" && cfg.WebhookQueueBackend != "streams" {
+		return nil, fmt.Errorf("WEBHOOK_QUEUE_BACKEND must be 'list' or 'streams', got %q", cfg.WebhookQueueBackend)
+	}
+
+	if err := cfg.validateNotifier(); err != nil {
+		return nil, err
+	}
+
+	if cfg.DefaultLanguage != "en" && cfg.DefaultLanguage != "ru" {
+		return nil, fmt.Errorf("DEFAULT_LANGUAGE must be 'en' or 'ru', got %q", cfg.DefaultLanguage)
+	}
+	if cfg.LocationCheckResponse != "array" && cfg.LocationCheckResponse != "object" {
+		return nil, fmt.Errorf("LOCATION_CHECK_RESPONSE must be 'array' or 'object', got %q", cfg.LocationCheckResponse)
+	}
+
+	if cfg.WebhookMaxConcurrentPerURL < 1 {
+		return nil, fmt.Errorf("WEBHOOK_MAX_CONCURRENT_PER_URL must be at least 1, got %d", cfg.WebhookMaxConcurrentPerURL)
+	}
+	if cfg.WebhookCircuitFailureThreshold < 0 {
+		return nil, fmt.Errorf("WEBHOOK_CIRCUIT_FAILURE_THRESHOLD must not be negative, got %d", cfg.WebhookCircuitFailureThreshold)
+	}
+	if cfg.WebhookCircuitFailureThreshold > 0 && cfg.WebhookCircuitCooldown <= 0 {
+		return nil, fmt.Errorf("WEBHOOK_CIRCUIT_COOLDOWN must be positive when the circuit breaker is enabled, got %s", cfg.WebhookCircuitCooldown)
+	}
+	if cfg.WebhookDeadLetterMaxLen < 1 {
+		return nil, fmt.Errorf("WEBHOOK_DEAD_LETTER_MAX_LEN must be at least 1, got %d", cfg.WebhookDeadLetterMaxLen)
+	}
+	if cfg.WebhookBatchSize < 1 {
+		return nil, fmt.Errorf("WEBHOOK_BATCH_SIZE must be at least 1, got %d", cfg.WebhookBatchSize)
+	}
+	if cfg.WebhookBatchSize > 1 && cfg.WebhookBatchWindow <= 0 {
+		return nil, fmt.Errorf("WEBHOOK_BATCH_WINDOW must be positive when batching is enabled, got %s", cfg.WebhookBatchWindow)
+	}
+
+	if cfg.MaxMatchedIncidents < 0 {
+		return nil, fmt.Errorf("MAX_MATCHED_INCIDENTS must not be negative, got %d", cfg.MaxMatchedIncidents)
+	}
+
+	if cfg.DefaultIncidentStatus != models.StatusActive && cfg.DefaultIncidentStatus != models.StatusDraft {
+		return nil, fmt.Errorf("DEFAULT_INCIDENT_STATUS must be 'active' or 'draft', got %q", cfg.DefaultIncidentStatus)
+	}
+
+	if err := cfg.validateDurations(); err != nil {
+		return nil, err
+	}
+
 	return cfg, nil
 }
 
+// MaxAlertBufferMeters возвращает наибольший из буферов оповещения ALERT_BUFFER_BY_SEVERITY
+func (c *Config) MaxAlertBufferMeters() int {
+	maxBuffer := 0
+	for _, meters := range c.AlertBufferMeters {
+		maxBuffer = max(maxBuffer, meters)
+	}
+	return maxBuffer
+}
+
+// validateNotifier проверяет, что для выбранного провайдера уведомлений заданы все параметры
+func (c *Config) validateNotifier() error {
+	type setting struct{ name, value string }
+	var required []setting
+	switch c.Notifier {
+	case "":
+		return nil
+	case "smtp":
+		required = []setting{{"SMTP_ADDR", c.SMTPAddr}, {"SMTP_FROM", c.SMTPFrom}}
+	case "twilio":
+		required = []setting{
+			{"TWILIO_ACCOUNT_SID", c.TwilioAccountSID},
+			{"TWILIO_AUTH_TOKEN", c.TwilioAuthToken},
+			{"TWILIO_FROM", c.TwilioFrom},
+		}
+	default:
+		return fmt.Errorf("NOTIFIER must be empty, 'smtp' or 'twilio', got %q", c.Notifier)
+	}
+
+	if len(c.NotifyRecipients) == 0 {
+		return fmt.Errorf("NOTIFY_RECIPIENTS is required when NOTIFIER=%s", c.Notifier)
+	}
+	for _, s := range required {
+		if s.value == "" {
+			return fmt.Errorf("%s is required when NOTIFIER=%s", s.name, c.Notifier)
+		}
+	}
+	return nil
+}
+
+// validateDurations проверяет таймауты и временные окна. Нулевые и отрицательные значения
+// считаются ошибкой, а подозрительно маленький таймаут вебхуков поднимается до минимума.
+func (c *Config) validateDurations() error {
+	if c.RequestTimeout < 0 {
+		return fmt.Errorf("REQUEST_TIMEOUT must not be negative, got %s", c.RequestTimeout)
+	}
+	if c.ShutdownTimeout <= 0 {
+		return fmt.Errorf("SHUTDOWN_TIMEOUT must be positive, got %s", c.ShutdownTimeout)
+	}
+	if c.WebhookTimeout <= 0 {
+		return fmt.Errorf("WEBHOOK_TIMEOUT must be positive, got %s", c.WebhookTimeout)
+	}
+	if c.WebhookTimeout < minWebhookTimeout {
+		logrus.Warnf("WEBHOOK_TIMEOUT %s is too low, using %s instead", c.WebhookTimeout, minWebhookTimeout)
+		c.WebhookTimeout = minWebhookTimeout
+	}
+	if c.WebhookBaseDelay < 0 {
+		return fmt.Errorf("WEBHOOK_BASE_DELAY_SECONDS must not be negative, got %s", c.WebhookBaseDelay)
+	}
+	if c.WebhookStreamClaimIdle <= 0 {
+		return fmt.Errorf("WEBHOOK_STREAM_CLAIM_IDLE must be positive, got %s", c.WebhookStreamClaimIdle)
+	}
+	if c.WebhookQueueCheckInterval <= 0 {
+		return fmt.Errorf("WEBHOOK_QUEUE_CHECK_INTERVAL must be positive, got %s", c.WebhookQueueCheckInterval)
+	}
+	if c.WebhookQueueAlarmThreshold < 0 {
+		return fmt.Errorf("WEBHOOK_QUEUE_ALARM_THRESHOLD must not be negative, got %d", c.WebhookQueueAlarmThreshold)
+	}
+	if c.PoolStatsInterval <= 0 {
+		return fmt.Errorf("POOL_STATS_INTERVAL must be positive, got %s", c.PoolStatsInterval)
+	}
+	if c.DBAcquireWaitThreshold < 0 {
+		return fmt.Errorf("DB_ACQUIRE_WAIT_THRESHOLD must not be negative, got %s", c.DBAcquireWaitThreshold)
+	}
+	if c.SlowQueryThreshold < 0 {
+		return fmt.Errorf("SLOW_QUERY_THRESHOLD must not be negative, got %s", c.SlowQueryThreshold)
+	}
+	if c.LongPollMaxTimeout <= 0 {
+		return fmt.Errorf("LONG_POLL_MAX_TIMEOUT must be positive, got %s", c.LongPollMaxTimeout)
+	}
+	if c.LongPollMaxClients <= 0 {
+		return fmt.Errorf("LONG_POLL_MAX_CLIENTS must be positive, got %d", c.LongPollMaxClients)
+	}
+	if c.ImportURLMaxBytes <= 0 {
+		return fmt.Errorf("IMPORT_URL_MAX_BYTES must be positive, got %d", c.ImportURLMaxBytes)
+	}
+	if c.ImportURLTimeout <= 0 {
+		return fmt.Errorf("IMPORT_URL_TIMEOUT must be positive, got %s", c.ImportURLTimeout)
+	}
+	if c.ImportMaxConcurrent <= 0 {
+		return fmt.Errorf("IMPORT_MAX_CONCURRENT must be positive, got %d", c.ImportMaxConcurrent)
+	}
+	if !c.RedisRequired && (c.RedisHealthCheckInterval <= 0 || c.WebhookFallbackQueueSize <= 0) {
+		return fmt.Errorf("REDIS_HEALTH_CHECK_INTERVAL and WEBHOOK_FALLBACK_QUEUE_SIZE must be positive when REDIS_REQUIRED=false")
+	}
+	if c.ImportFeedSyncInterval < 0 {
+		return fmt.Errorf("IMPORT_FEED_SYNC_INTERVAL must not be negative, got %s", c.ImportFeedSyncInterval)
+	}
+	if c.S3Bucket != "" {
+		if u, err := url.Parse(c.S3Endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("S3_ENDPOINT must be an absolute http(s) URL, got %q", c.S3Endpoint)
+		}
+		if c.S3AccessKeyID == "" || c.S3SecretAccessKey == "" {
+			return fmt.Errorf("S3_ACCESS_KEY_ID and S3_SECRET_ACCESS_KEY are required when S3_BUCKET is set")
+		}
+		if c.S3PresignTTL < time.Second || c.S3PresignTTL > 7*24*time.Hour {
+			return fmt.Errorf("S3_PRESIGN_TTL must be between 1s and 168h, got %s", c.S3PresignTTL)
+		}
+	}
+	if c.WebhookOutboxEnabled && (c.WebhookOutboxPollInterval <= 0 || c.WebhookOutboxBatchSize <= 0) {
+		return fmt.Errorf("WEBHOOK_OUTBOX_POLL_INTERVAL and WEBHOOK_OUTBOX_BATCH_SIZE must be positive when the outbox is enabled")
+	}
+	if c.IncidentRetentionDays < 0 {
+		return fmt.Errorf("INCIDENT_RETENTION_DAYS must not be negative, got %d", c.IncidentRetentionDays)
+	}
+	if c.IncidentRetentionDays > 0 && (c.IncidentPurgeInterval <= 0 || c.IncidentPurgeBatchSize <= 0) {
+		return fmt.Errorf("INCIDENT_PURGE_INTERVAL and INCIDENT_PURGE_BATCH_SIZE must be positive when retention is enabled")
+	}
+	if c.AutoEscalateDangerousUsers < 0 {
+		return fmt.Errorf("AUTO_ESCALATE_DANGEROUS_USERS must not be negative, got %d", c.AutoEscalateDangerousUsers)
+	}
+	if c.AutoEscalateDangerousUsers > 0 && (c.AutoEscalateWindow <= 0 || c.AutoEscalateInterval <= 0) {
+		return fmt.Errorf("AUTO_ESCALATE_WINDOW and AUTO_ESCALATE_INTERVAL must be positive when auto-escalation is enabled")
+	}
+	if c.HotspotMinIncidents < 2 {
+		return fmt.Errorf("HOTSPOT_MIN_INCIDENTS must be at least 2, got %d", c.HotspotMinIncidents)
+	}
+	if c.LocationTileCacheTTL < 0 {
+		return fmt.Errorf("LOCATION_TILE_CACHE_TTL must not be negative, got %s", c.LocationTileCacheTTL)
+	}
+	if c.LocationTilePrecision < 1 || c.LocationTilePrecision > 12 {
+		return fmt.Errorf("LOCATION_TILE_PRECISION must be between 1 and 12, got %d", c.LocationTilePrecision)
+	}
+	if c.UserCheckQuotaPerMinute < 0 {
+		return fmt.Errorf("USER_CHECK_QUOTA_PER_MINUTE must not be negative, got %d", c.UserCheckQuotaPerMinute)
+	}
+	if c.PublicFeedRateLimitPerMinute < 0 {
+		return fmt.Errorf("PUBLIC_FEED_RATE_LIMIT_PER_MINUTE must not be negative, got %d", c.PublicFeedRateLimitPerMinute)
+	}
+	for _, proxy := range c.TrustedProxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			return fmt.Errorf("TRUSTED_PROXIES must contain IP addresses or CIDR subnets, got %q", proxy)
+		}
+	}
+	if c.LocationIndexRefreshInterval < 0 {
+		return fmt.Errorf("LOCATION_INDEX_REFRESH_INTERVAL must not be negative, got %s", c.LocationIndexRefreshInterval)
+	}
+	if c.StatsTimeWindowMinutes <= 0 {
+		return fmt.Errorf("STATS_TIME_WINDOW_MINUTES must be positive, got %d", c.StatsTimeWindowMinutes)
+	}
+	if c.StatsCacheTTL < 0 {
+		return fmt.Errorf("STATS_CACHE_TTL must not be negative, got %s", c.StatsCacheTTL)
+	}
+	if c.IncidentViewFlushInterval < 0 {
+		return fmt.Errorf("INCIDENT_VIEW_FLUSH_INTERVAL must not be negative, got %s", c.IncidentViewFlushInterval)
+	}
+	if c.APIKeyCacheTTL < 0 {
+		return fmt.Errorf("API_KEY_CACHE_TTL must not be negative, got %s", c.APIKeyCacheTTL)
+	}
+	if c.APIKeyUsageWindow < time.Minute {
+		return fmt.Errorf("API_KEY_USAGE_WINDOW must be at least 1m, got %s", c.APIKeyUsageWindow)
+	}
+	return nil
+}
+
 // getEnv возвращает значение переменной окружения или значение по умолчанию
 func getEnv(key string, defaultValue string) string {
 	if value, exists := os.LookupEnv(key); exists {
@@ -91,17 +745,183 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
-// getEnvAsDuration возвращает значение переменной окружения как time.Duration или значение по умолчанию
-func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
+// getEnvAsBool возвращает значение переменной окружения как bool или значение по умолчанию
+func getEnvAsBool(key string, defaultValue bool) bool {
 	if value, exists := os.LookupEnv(key); exists {
-		// Allow parsing durations like "5s", "1m"
-		if durationValue, err := time.ParseDuration(value); err == nil {
-			return durationValue
-		}
-		// Also allow parsing plain integer as seconds
-		if intValue, err := strconv.Atoi(value); err == nil {
-			return time.Duration(intValue) * time.Second
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
 		}
 	}
 	return defaultValue
 }
+
+// getEnvAsFeatureFlags разбирает список флагов вида "clusters=false,public_feed=true".
+// Имена приводятся к нижнему регистру; пустая переменная означает, что все функции включены.
+func getEnvAsFeatureFlags(key string) (map[string]bool, error) {
+	items := getEnvAsList(key)
+	if len(items) == 0 {
+		return nil, nil
+	}
+	flags := make(map[string]bool, len(items))
+	for _, item := range items {
+		name, value, ok := strings.Cut(item, "=")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !ok || name == "" {
+			return nil, fmt.Errorf("%s: expected name=true|false, got %q", key, item)
+		}
+		enabled, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid value of feature %q: %w", key, name, err)
+		}
+		flags[name] = enabled
+	}
+	return flags, nil
+}
+
+// getEnvAsAlertBuffers разбирает буферы оповещения по уровням опасности вида "major=200,critical=1000".
+// Пустая переменная означает, что буферы не заданы и вебхук отбирает те же инциденты, что видит пользователь.
+func getEnvAsAlertBuffers(key string) (map[models.IncidentSeverity]int, error) {
+	items := getEnvAsList(key)
+	if len(items) == 0 {
+		return nil, nil
+	}
+	buffers := make(map[models.IncidentSeverity]int, len(items))
+	for _, item := range items {
+		name, value, ok := strings.Cut(item, "=")
+		severity := models.IncidentSeverity(strings.ToLower(strings.TrimSpace(name)))
+		if !ok || !severity.IsValid() {
+			return nil, fmt.Errorf("%s: expected severity=meters with a known severity, got %q", key, item)
+		}
+		meters, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid buffer of severity %q: %w", key, severity, err)
+		}
+		if meters < 0 {
+			return nil, fmt.Errorf("%s: buffer of severity %q must not be negative, got %d", key, severity, meters)
+		}
+		buffers[severity] = meters
+	}
+	return buffers, nil
+}
+
+// getEnvAsCategoryRules разбирает JSON-объект правил категорий вида
+// {"fire": {"min_radius_meters": 50, "max_radius_meters": 5000, "default_radius_meters": 500}}.
+// Категории приводятся к нижнему регистру; пустая переменная означает отсутствие правил.
+func getEnvAsCategoryRules(key string) (map[string]CategoryRule, error) {
+	value := strings.TrimSpace(os.Getenv(key))
+	if value == "" {
+		return nil, nil
+	}
+	var raw map[string]CategoryRule
+	if err := json.Unmarshal([]byte(value), &raw); err != nil {
+		return nil, fmt.Errorf("%s must be a JSON object of category rules: %w", key, err)
+	}
+
+	rules := make(map[string]CategoryRule, len(raw))
+	for category, rule := range raw {
+		if rule.MinRadiusMeters < 0 || rule.MaxRadiusMeters < 0 || rule.DefaultRadiusMeters < 0 {
+			return nil, fmt.Errorf("%s: radii of category %q must not be negative", key, category)
+		}
+		if rule.MaxRadiusMeters > 0 && rule.MinRadiusMeters > rule.MaxRadiusMeters {
+			return nil, fmt.Errorf("%s: min_radius_meters of category %q is greater than max_radius_meters", key, category)
+		}
+		if rule.DefaultRadiusMeters > 0 && (rule.DefaultRadiusMeters < rule.MinRadiusMeters ||
+			(rule.MaxRadiusMeters > 0 && rule.DefaultRadiusMeters > rule.MaxRadiusMeters)) {
+			return nil, fmt.Errorf("%s: default_radius_meters of category %q is outside of its range", key, category)
+		}
+		rules[strings.ToLower(strings.TrimSpace(category))] = rule
+	}
+	return rules, nil
+}
+
+// getEnvAsRegions разбирает JSON-массив областей вида
+// [{"bbox": {"min_lat": 41, "min_lon": 19, "max_lat": 82, "max_lon": -169}}, {"polygon": [{"lat": 55.6, "lon": 37.4}, ...]}].
+// Пустая переменная означает отсутствие областей.
+func getEnvAsRegions(key string) ([]geo.Region, error) {
+	value := strings.TrimSpace(os.Getenv(key))
+	if value == "" {
+		return nil, nil
+	}
+	var regions []geo.Region
+	if err := json.Unmarshal([]byte(value), &regions); err != nil {
+		return nil, fmt.Errorf("%s must be a JSON array of regions: %w", key, err)
+	}
+	for i := range regions {
+		if err := regions[i].Validate(); err != nil {
+			return nil, fmt.Errorf("%s: region #%d: %w", key, i, err)
+		}
+	}
+	return regions, nil
+}
+
+// getEnvAsDuration возвращает значение переменной окружения как time.Duration или значение по умолчанию.
+// Поддерживаются форматы Go ("5s", "1m"), ISO 8601 ("PT30S", "PT5M") и целое число секунд.
+func getEnvAsDuration(key string, defaultValue time.Duration) (time.Duration, error) {
+	value, exists := os.LookupEnv(key)
+	if !exists || value == "" {
+		return defaultValue, nil
+	}
+	duration, err := parseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return duration, nil
+}
+
+// parseDuration разбирает длительность в формате Go, ISO 8601 или как целое число секунд
+func parseDuration(value string) (time.Duration, error) {
+	if duration, err := time.ParseDuration(value); err == nil {
+		return duration, nil
+	}
+	if intValue, err := strconv.Atoi(value); err == nil {
+		return time.Duration(intValue) * time.Second, nil
+	}
+	if duration, ok := parseISO8601Duration(value); ok {
+		return duration, nil
+	}
+	return 0, fmt.Errorf("unrecognized duration %q", value)
+}
+
+// parseISO8601Duration разбирает подмножество ISO 8601 без годов и месяцев (их длина неоднозначна)
+func parseISO8601Duration(value string) (time.Duration, bool) {
+	value = strings.ToUpper(value)
+	matches := iso8601DurationPattern.FindStringSubmatch(value)
+	// "P" и "PT" без компонентов формально совпадают с шаблоном, но не являются длительностью
+	if matches == nil || value == "P" || strings.HasSuffix(value, "T") {
+		return 0, false
+	}
+
+	units := []time.Duration{24 * time.Hour, time.Hour, time.Minute}
+	var total time.Duration
+	for i, unit := range units {
+		if matches[i+1] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(matches[i+1])
+		if err != nil {
+			return 0, false
+		}
+		total += time.Duration(n) * unit
+	}
+	if matches[4] != "" {
+		seconds, err := strconv.ParseFloat(matches[4], 64)
+		if err != nil {
+			return 0, false
+		}
+		total += time.Duration(seconds * float64(time.Second))
+	}
+	return total, true
+}
+
+// getEnvAsList возвращает значение переменной окружения как список строк, разделенных запятыми
+func getEnvAsList(key string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+	items := strings.Split(value, ",")
+	for i, item := range items {
+		items[i] = strings.TrimSpace(item)
+	}
+	return items
+}
@@ -0,0 +1,532 @@
+package config
+
+import (
+	"crypto/tls"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shenikar/geo_broadcasting_system/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Duration
+	}{
+		{input: "5s", expected: 5 * time.Second},
+		{input: "1m30s", expected: 90 * time.Second},
+		{input: "10", expected: 10 * time.Second},
+		{input: "PT30S", expected: 30 * time.Second},
+		{input: "PT1H30M", expected: 90 * time.Minute},
+		{input: "P1D", expected: 24 * time.Hour},
+		{input: "P1DT2H", expected: 26 * time.Hour},
+		{input: "pt0.5s", expected: 500 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			d, err := parseDuration(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, d)
+		})
+	}
+}
+
+func TestParseDuration_Invalid(t *testing.T) {
+	for _, input := range []string{"abc", "P", "PT", "P1Y", "P1M", "5 seconds"} {
+		t.Run(input, func(t *testing.T) {
+			_, err := parseDuration(input)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLoadConfig_WebhookTimeout(t *testing.T) {
+	t.Setenv("DATABASE_URL", "postgres://localhost/test")
+
+	t.Run("too low is clamped", func(t *testing.T) {
+		t.Setenv("WEBHOOK_TIMEOUT", "10ms")
+		cfg, err := LoadConfig()
+		require.NoError(t, err)
+		assert.Equal(t, minWebhookTimeout, cfg.WebhookTimeout)
+	})
+
+	t.Run("iso8601", func(t *testing.T) {
+		t.Setenv("WEBHOOK_TIMEOUT", "PT15S")
+		cfg, err := LoadConfig()
+		require.NoError(t, err)
+		assert.Equal(t, 15*time.Second, cfg.WebhookTimeout)
+	})
+
+	for _, value := range []string{"0s", "-5s", "soon"} {
+		t.Run("invalid "+value, func(t *testing.T) {
+			t.Setenv("WEBHOOK_TIMEOUT", value)
+			_, err := LoadConfig()
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLoadConfig_InvalidStatsWindow(t *testing.T) {
+	t.Setenv("DATABASE_URL", "postgres://localhost/test")
+	t.Setenv("STATS_TIME_WINDOW_MINUTES", "0")
+
+	_, err := LoadConfig()
+	assert.Error(t, err)
+}
+
+func TestLoadConfig_Notifier(t *testing.T) {
+	t.Setenv("DATABASE_URL", "postgres://localhost/test")
+
+	t.Run("disabled by default", func(t *testing.T) {
+		cfg, err := LoadConfig()
+		require.NoError(t, err)
+		assert.Empty(t, cfg.Notifier)
+	})
+
+	t.Run("smtp requires settings", func(t *testing.T) {
+		t.Setenv("NOTIFIER", "smtp")
+		t.Setenv("NOTIFY_RECIPIENTS", "duty@example.com")
+		_, err := LoadConfig()
+		assert.ErrorContains(t, err, "SMTP_ADDR")
+
+		t.Setenv("SMTP_ADDR", "smtp.example.com:587")
+		t.Setenv("SMTP_FROM", "alerts@example.com")
+		cfg, err := LoadConfig()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"duty@example.com"}, cfg.NotifyRecipients)
+	})
+
+	t.Run("unknown provider", func(t *testing.T) {
+		t.Setenv("NOTIFIER", "pigeon")
+		_, err := LoadConfig()
+		assert.Error(t, err)
+	})
+}
+
+func TestReload(t *testing.T) {
+	cfg := &Config{
+		DatabaseURL:       "postgres://localhost/test",
+		HTTPPort:          "8080",
+		LogLevel:          "info",
+		WebhookURL:        "http://old.example.com",
+		WebhookMaxRetries: 5,
+		APIKeys:           []string{"old-key"},
+	}
+	next := &Config{
+		DatabaseURL:       "postgres://localhost/other",
+		HTTPPort:          "8080",
+		LogLevel:          "debug",
+		WebhookURL:        "http://new.example.com",
+		WebhookSecret:     "secret",
+		WebhookMaxRetries: 3,
+		APIKeys:           []string{"new-key"},
+		AdminAPIKeys:      []string{"admin-key"},
+	}
+
+	ignored := cfg.Reload(next)
+
+	// Изменения, требующие перезапуска, только возвращаются
+	assert.Equal(t, []string{"DATABASE_URL"}, ignored)
+	assert.Equal(t, "postgres://localhost/test", cfg.DatabaseURL)
+
+	apiKeys, adminKeys := cfg.Keys()
+	assert.Equal(t, []string{"new-key"}, apiKeys)
+	assert.Equal(t, []string{"admin-key"}, adminKeys)
+	secret, maxRetries := cfg.WebhookDelivery()
+	assert.Equal(t, "secret", secret)
+	assert.Equal(t, 3, maxRetries)
+	assert.Equal(t, "http://new.example.com", cfg.WebhookURL)
+	assert.Equal(t, "debug", cfg.LogLevel)
+}
+
+func TestLoadConfig_CategoryRules(t *testing.T) {
+	t.Setenv("DATABASE_URL", "postgres://localhost/test")
+
+	t.Run("valid", func(t *testing.T) {
+		t.Setenv("INCIDENT_CATEGORY_RULES", `{"Fire": {"min_radius_meters": 50, "max_radius_meters": 5000, "default_radius_meters": 500}}`)
+		cfg, err := LoadConfig()
+		require.NoError(t, err)
+		assert.Equal(t, CategoryRule{MinRadiusMeters: 50, MaxRadiusMeters: 5000, DefaultRadiusMeters: 500}, cfg.IncidentCategoryRules["fire"])
+	})
+
+	for name, value := range map[string]string{
+		"bad json":         `[1, 2]`,
+		"min above max":    `{"fire": {"min_radius_meters": 500, "max_radius_meters": 50}}`,
+		"default outside":  `{"fire": {"max_radius_meters": 50, "default_radius_meters": 500}}`,
+		"negative minimum": `{"fire": {"min_radius_meters": -1}}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("INCIDENT_CATEGORY_RULES", value)
+			_, err := LoadConfig()
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLoadConfig_CoordinateRegions(t *testing.T) {
+	t.Setenv("DATABASE_URL", "postgres://localhost/test")
+
+	t.Run("default", func(t *testing.T) {
+		cfg, err := LoadConfig()
+		require.NoError(t, err)
+		assert.Empty(t, cfg.CoordinateAllowRegions)
+		assert.Empty(t, cfg.CoordinateDenyRegions)
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		t.Setenv("COORDINATE_ALLOW_REGIONS", `[{"bbox": {"min_lat": 41, "min_lon": 19, "max_lat": 82, "max_lon": -169}}]`)
+		t.Setenv("COORDINATE_DENY_REGIONS", `[{"polygon": [{"lat": 55.74, "lon": 37.60}, {"lat": 55.76, "lon": 37.60}, {"lat": 55.76, "lon": 37.63}]}]`)
+		cfg, err := LoadConfig()
+		require.NoError(t, err)
+		require.Len(t, cfg.CoordinateAllowRegions, 1)
+		assert.Equal(t, 41.0, cfg.CoordinateAllowRegions[0].BBox.MinLat)
+		require.Len(t, cfg.CoordinateDenyRegions, 1)
+		assert.Len(t, cfg.CoordinateDenyRegions[0].Polygon, 3)
+	})
+
+	for name, value := range map[string]string{
+		"bad json":      `{"bbox": {}}`,
+		"empty region":  `[{}]`,
+		"short polygon": `[{"polygon": [{"lat": 1, "lon": 1}]}]`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("COORDINATE_DENY_REGIONS", value)
+			_, err := LoadConfig()
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLoadConfig_WebhookTLS(t *testing.T) {
+	t.Setenv("DATABASE_URL", "postgres://localhost/test")
+
+	t.Run("defaults", func(t *testing.T) {
+		cfg, err := LoadConfig()
+		require.NoError(t, err)
+		assert.Equal(t, uint16(tls.VersionTLS12), cfg.WebhookTLSMinVersion)
+		assert.Empty(t, cfg.WebhookTLSCipherSuites)
+		assert.True(t, cfg.WebhookFollowRedirects)
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		t.Setenv("WEBHOOK_TLS_MIN_VERSION", "1.3")
+		t.Setenv("WEBHOOK_TLS_CIPHER_SUITES", "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256")
+		t.Setenv("WEBHOOK_TLS_PINNED_SHA256", strings.Repeat("AB:", 31)+"AB")
+		cfg, err := LoadConfig()
+		require.NoError(t, err)
+		assert.Equal(t, uint16(tls.VersionTLS13), cfg.WebhookTLSMinVersion)
+		assert.Equal(t, []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256}, cfg.WebhookTLSCipherSuites)
+		assert.Equal(t, []string{strings.Repeat("ab", 32)}, cfg.WebhookTLSPinnedKeys)
+	})
+
+	for name, env := range map[string][2]string{
+		"unknown version":   {"WEBHOOK_TLS_MIN_VERSION", "1.4"},
+		"insecure cipher":   {"WEBHOOK_TLS_CIPHER_SUITES", "TLS_RSA_WITH_RC4_128_SHA"},
+		"short fingerprint": {"WEBHOOK_TLS_PINNED_SHA256", "abcd"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(env[0], env[1])
+			_, err := LoadConfig()
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLoadConfig_WebhookBatch(t *testing.T) {
+	t.Setenv("DATABASE_URL", "postgres://localhost/test")
+
+	cfg, err := LoadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, 1, cfg.WebhookBatchSize)
+	assert.Equal(t, time.Second, cfg.WebhookBatchWindow)
+
+	t.Setenv("WEBHOOK_BATCH_SIZE", "0")
+	_, err = LoadConfig()
+	assert.Error(t, err)
+
+	t.Setenv("WEBHOOK_BATCH_SIZE", "50")
+	t.Setenv("WEBHOOK_BATCH_WINDOW", "0s")
+	_, err = LoadConfig()
+	assert.Error(t, err)
+}
+
+func TestLoadConfig_WebhookCircuit(t *testing.T) {
+	t.Setenv("DATABASE_URL", "postgres://localhost/test")
+
+	cfg, err := LoadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, 5, cfg.WebhookCircuitFailureThreshold)
+	assert.Equal(t, time.Minute, cfg.WebhookCircuitCooldown)
+	assert.Equal(t, 10000, cfg.WebhookDeadLetterMaxLen)
+
+	t.Setenv("WEBHOOK_CIRCUIT_COOLDOWN", "0s")
+	_, err = LoadConfig()
+	assert.Error(t, err)
+
+	t.Setenv("WEBHOOK_CIRCUIT_FAILURE_THRESHOLD", "0")
+	_, err = LoadConfig()
+	assert.NoError(t, err)
+}
+
+func TestLoadConfig_SlowQueryThreshold(t *testing.T) {
+	t.Setenv("DATABASE_URL", "postgres://localhost/test")
+
+	cfg, err := LoadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, 500*time.Millisecond, cfg.SlowQueryThreshold)
+
+	t.Setenv("SLOW_QUERY_THRESHOLD", "-1s")
+	_, err = LoadConfig()
+	assert.Error(t, err)
+}
+
+func TestLoadConfig_LongPoll(t *testing.T) {
+	t.Setenv("DATABASE_URL", "postgres://localhost/test")
+
+	cfg, err := LoadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, 60*time.Second, cfg.LongPollMaxTimeout)
+	assert.Equal(t, 500, cfg.LongPollMaxClients)
+
+	t.Setenv("LONG_POLL_MAX_CLIENTS", "0")
+	_, err = LoadConfig()
+	assert.Error(t, err)
+}
+
+func TestLoadConfig_ImportURL(t *testing.T) {
+	t.Setenv("DATABASE_URL", "postgres://localhost/test")
+	t.Setenv("IMPORT_URL_ALLOWED_HOSTS", "Feeds.Example.org, data.gov.example")
+
+	cfg, err := LoadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"feeds.example.org", "data.gov.example"}, cfg.ImportURLAllowedHosts)
+	assert.Equal(t, 10<<20, cfg.ImportURLMaxBytes)
+	assert.Equal(t, 30*time.Second, cfg.ImportURLTimeout)
+	assert.Equal(t, 15*time.Minute, cfg.ImportFeedSyncInterval)
+
+	t.Setenv("IMPORT_URL_TIMEOUT", "0s")
+	_, err = LoadConfig()
+	assert.Error(t, err)
+}
+
+func TestLoadConfig_S3Attachments(t *testing.T) {
+	t.Setenv("DATABASE_URL", "postgres://localhost/test")
+
+	cfg, err := LoadConfig()
+	require.NoError(t, err)
+	assert.Empty(t, cfg.S3Bucket)
+	assert.True(t, cfg.S3PathStyle)
+	assert.Equal(t, 15*time.Minute, cfg.S3PresignTTL)
+
+	// С бакетом обязательны ключи доступа
+	t.Setenv("S3_BUCKET", "incident-attachments")
+	_, err = LoadConfig()
+	assert.Error(t, err)
+
+	t.Setenv("S3_ACCESS_KEY_ID", "key")
+	t.Setenv("S3_SECRET_ACCESS_KEY", "secret")
+	_, err = LoadConfig()
+	require.NoError(t, err)
+
+	t.Setenv("S3_PRESIGN_TTL", "200h")
+	_, err = LoadConfig()
+	assert.Error(t, err)
+}
+
+func TestLoadConfig_RedisDegradedMode(t *testing.T) {
+	t.Setenv("DATABASE_URL", "postgres://localhost/test")
+
+	cfg, err := LoadConfig()
+	require.NoError(t, err)
+	assert.True(t, cfg.RedisRequired)
+	assert.Equal(t, 5*time.Second, cfg.RedisHealthCheckInterval)
+	assert.Equal(t, 10000, cfg.WebhookFallbackQueueSize)
+
+	t.Setenv("REDIS_REQUIRED", "false")
+	t.Setenv("WEBHOOK_FALLBACK_QUEUE_SIZE", "0")
+	_, err = LoadConfig()
+	assert.Error(t, err)
+}
+
+func TestLoadConfig_IncidentViewFlushInterval(t *testing.T) {
+	t.Setenv("DATABASE_URL", "postgres://localhost/test")
+
+	cfg, err := LoadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, cfg.IncidentViewFlushInterval)
+
+	t.Setenv("INCIDENT_VIEW_FLUSH_INTERVAL", "-1s")
+	_, err = LoadConfig()
+	assert.Error(t, err)
+}
+
+func TestLoadConfig_LocationCheckResponse(t *testing.T) {
+	t.Setenv("DATABASE_URL", "postgres://localhost/test")
+
+	cfg, err := LoadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "array", cfg.LocationCheckResponse)
+
+	t.Setenv("LOCATION_CHECK_RESPONSE", "wrapped")
+	_, err = LoadConfig()
+	assert.Error(t, err)
+}
+
+func TestLoadConfig_HotspotMinIncidents(t *testing.T) {
+	t.Setenv("DATABASE_URL", "postgres://localhost/test")
+
+	cfg, err := LoadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, 5, cfg.HotspotMinIncidents)
+
+	t.Setenv("HOTSPOT_MIN_INCIDENTS", "1")
+	_, err = LoadConfig()
+	assert.Error(t, err)
+}
+
+func TestLoadConfig_PublicFeed(t *testing.T) {
+	t.Setenv("DATABASE_URL", "postgres://localhost/test")
+	t.Setenv("PUBLIC_FEED_EXCLUDED_CATEGORIES", "Internal, drill")
+
+	cfg, err := LoadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, 60, cfg.PublicFeedRateLimitPerMinute)
+	assert.Equal(t, []string{"internal", "drill"}, cfg.PublicFeedExcludedCategories)
+
+	t.Setenv("PUBLIC_FEED_RATE_LIMIT_PER_MINUTE", "-1")
+	_, err = LoadConfig()
+	assert.Error(t, err)
+}
+
+func TestLoadConfig_TrustedProxies(t *testing.T) {
+	t.Setenv("DATABASE_URL", "postgres://localhost/test")
+
+	cfg, err := LoadConfig()
+	require.NoError(t, err)
+	assert.Empty(t, cfg.TrustedProxies)
+
+	t.Setenv("TRUSTED_PROXIES", "10.0.0.0/8, 192.168.1.10")
+	cfg, err = LoadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.0/8", "192.168.1.10"}, cfg.TrustedProxies)
+
+	t.Setenv("TRUSTED_PROXIES", "proxy.internal")
+	_, err = LoadConfig()
+	assert.Error(t, err)
+}
+
+func TestLoadConfig_FeatureFlags(t *testing.T) {
+	t.Setenv("DATABASE_URL", "postgres://localhost/test")
+	t.Setenv("FEATURE_FLAGS", "Clusters=false, public_feed=true")
+
+	cfg, err := LoadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"clusters": false, "public_feed": true}, cfg.FeatureFlags)
+
+	t.Setenv("FEATURE_FLAGS", "clusters")
+	_, err = LoadConfig()
+	assert.Error(t, err)
+
+	t.Setenv("FEATURE_FLAGS", "clusters=maybe")
+	_, err = LoadConfig()
+	assert.Error(t, err)
+}
+
+func TestLoadConfig_AlertBufferBySeverity(t *testing.T) {
+	t.Setenv("DATABASE_URL", "postgres://localhost/test")
+	t.Setenv("ALERT_BUFFER_BY_SEVERITY", "Major=200, critical=1000")
+
+	cfg, err := LoadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, map[models.IncidentSeverity]int{models.SeverityMajor: 200, models.SeverityCritical: 1000}, cfg.AlertBufferMeters)
+	assert.Equal(t, 1000, cfg.MaxAlertBufferMeters())
+
+	for _, value := range []string{"extreme=100", "critical", "critical=far", "critical=-1"} {
+		t.Setenv("ALERT_BUFFER_BY_SEVERITY", value)
+		_, err = LoadConfig()
+		assert.Error(t, err, value)
+	}
+}
+
+func TestLoadConfig_MaxMatchedIncidents(t *testing.T) {
+	t.Setenv("DATABASE_URL", "postgres://localhost/test")
+
+	cfg, err := LoadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, 0, cfg.MaxMatchedIncidents)
+
+	t.Setenv("MAX_MATCHED_INCIDENTS", "-1")
+	_, err = LoadConfig()
+	assert.Error(t, err)
+}
+
+func TestLoadConfig_WatchURLAllowedHosts(t *testing.T) {
+	t.Setenv("DATABASE_URL", "postgres://localhost/test")
+	t.Setenv("WATCH_URL_ALLOWED_HOSTS", "Hooks.Example.org, ops.example")
+
+	cfg, err := LoadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"hooks.example.org", "ops.example"}, cfg.WatchURLAllowedHosts)
+}
+
+func TestParseQuietHours(t *testing.T) {
+	q, err := parseQuietHours("WEBHOOK_QUIET_HOURS", "22:00-07:30", "Europe/Moscow")
+	require.NoError(t, err)
+	assert.Equal(t, 22*time.Hour, q.Start)
+	assert.Equal(t, 7*time.Hour+30*time.Minute, q.End)
+	assert.Equal(t, "Europe/Moscow", q.Location.String())
+
+	q, err = parseQuietHours("WEBHOOK_QUIET_HOURS", "", "UTC")
+	require.NoError(t, err)
+	assert.False(t, q.Enabled())
+
+	for _, tt := range []struct{ window, timezone string }{
+		{"22:00", "UTC"},
+		{"25:00-07:00", "UTC"},
+		{"07:00-07:00", "UTC"},
+		{"22:00-07:00", "Mars/Olympus"},
+	} {
+		_, err := parseQuietHours("WEBHOOK_QUIET_HOURS", tt.window, tt.timezone)
+		assert.Error(t, err, tt.window)
+	}
+}
+
+func TestQuietHours_Until(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	require.NoError(t, err)
+	overnight := QuietHours{Start: 22 * time.Hour, End: 7 * time.Hour, Location: moscow}
+	daytime := QuietHours{Start: 13 * time.Hour, End: 15 * time.Hour, Location: moscow}
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, time.March, day, hour, minute, 0, 0, moscow)
+	}
+
+	tests := []struct {
+		name  string
+		quiet QuietHours
+		now   time.Time
+		until time.Time
+		ok    bool
+	}{
+		{name: "before midnight", quiet: overnight, now: at(10, 23, 15), until: at(11, 7, 0), ok: true},
+		{name: "after midnight", quiet: overnight, now: at(11, 3, 0), until: at(11, 7, 0), ok: true},
+		{name: "end is exclusive", quiet: overnight, now: at(11, 7, 0)},
+		{name: "outside overnight", quiet: overnight, now: at(11, 12, 0)},
+		{name: "inside daytime", quiet: daytime, now: at(11, 14, 59), until: at(11, 15, 0), ok: true},
+		{name: "outside daytime", quiet: daytime, now: at(11, 21, 0)},
+		{name: "disabled", quiet: QuietHours{}, now: at(11, 3, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Время передается в UTC: окно считается по часовому поясу окна, а не времени события
+			until, ok := tt.quiet.Until(tt.now.UTC())
+			assert.Equal(t, tt.ok, ok)
+			if tt.ok {
+				assert.True(t, tt.until.Equal(until), "expected %s, got %s", tt.until, until)
+			}
+		})
+	}
+}
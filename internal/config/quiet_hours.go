@@ -0,0 +1,90 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	// Встроенная база часовых поясов: в образе alpine ее нет
+	_ "time/tzdata"
+)
+
+// QuietHours - ежедневное окно тишины [Start, End) по местному времени Location.
+// Окно может переходить через полночь (например, 22:00-07:00). Нулевое значение означает, что окно не задано.
+type QuietHours struct {
+	// Start и End - начало и конец окна как смещение от полуночи
+	Start    time.Duration
+	End      time.Duration
+	Location *time.Location
+}
+
+// Enabled сообщает, задано ли окно тишины
+func (q QuietHours) Enabled() bool {
+	return q.Location != nil && q.Start != q.End
+}
+
+// Until возвращает момент окончания окна тишины, если t попадает в него
+func (q QuietHours) Until(t time.Time) (time.Time, bool) {
+	if !q.Enabled() {
+		return time.Time{}, false
+	}
+	local := t.In(q.Location)
+	offset := time.Duration(local.Hour())*time.Hour + time.Duration(local.Minute())*time.Minute +
+		time.Duration(local.Second())*time.Second + time.Duration(local.Nanosecond())
+
+	// Окончание считается по настенным часам: при переходе на летнее время окно не сдвигается
+	endOn := func(days int) time.Time {
+		return time.Date(local.Year(), local.Month(), local.Day()+days,
+			int(q.End/time.Hour), int(q.End%time.Hour/time.Minute), 0, 0, q.Location)
+	}
+	if q.Start < q.End {
+		if offset >= q.Start && offset < q.End {
+			return endOn(0), true
+		}
+		return time.Time{}, false
+	}
+	switch {
+	case offset >= q.Start:
+		return endOn(1), true
+	case offset < q.End:
+		return endOn(0), true
+	}
+	return time.Time{}, false
+}
+
+// parseQuietHours разбирает окно вида 22:00-07:00 в часовом поясе timezone (имя из базы IANA, например Europe/Moscow).
+// Пустое окно означает, что тишина не задана.
+func parseQuietHours(key, window, timezone string) (QuietHours, error) {
+	window = strings.TrimSpace(window)
+	if window == "" {
+		return QuietHours{}, nil
+	}
+	startStr, endStr, ok := strings.Cut(window, "-")
+	if !ok {
+		return QuietHours{}, fmt.Errorf("%s must be a window like 22:00-07:00, got %q", key, window)
+	}
+	start, err := parseClock(startStr)
+	if err != nil {
+		return QuietHours{}, fmt.Errorf("%s: invalid start: %w", key, err)
+	}
+	end, err := parseClock(endStr)
+	if err != nil {
+		return QuietHours{}, fmt.Errorf("%s: invalid end: %w", key, err)
+	}
+	if start == end {
+		return QuietHours{}, fmt.Errorf("%s: start and end must differ, got %q", key, window)
+	}
+	location, err := time.LoadLocation(strings.TrimSpace(timezone))
+	if err != nil {
+		return QuietHours{}, fmt.Errorf("%s: unknown timezone %q: %w", key, timezone, err)
+	}
+	return QuietHours{Start: start, End: end, Location: location}, nil
+}
+
+// parseClock разбирает время суток вида 07:30 в смещение от полуночи
+func parseClock(value string) (time.Duration, error) {
+	clock, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("time of day must be HH:MM, got %q", value)
+	}
+	return time.Duration(clock.Hour())*time.Hour + time.Duration(clock.Minute())*time.Minute, nil
+}
@@ -0,0 +1,114 @@
+package config
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/joho/godotenv"
+)
+
+// reloadableFields - поля конфигурации, которые применяются без перезапуска по SIGHUP
+var reloadableFields = map[string]struct{}{
+	"LogLevel":          {},
+	"WebhookURL":        {},
+	"WebhookSecret":     {},
+	"WebhookMaxRetries": {},
+	"APIKeys":           {},
+	"AdminAPIKeys":      {},
+}
+
+var (
+	// processEnv - переменные, заданные в окружении процесса до чтения .env; они имеют приоритет над .env
+	processEnv     map[string]struct{}
+	processEnvOnce sync.Once
+	// dotEnvKeys - переменные, установленные из .env при последней загрузке
+	dotEnvKeys map[string]struct{}
+	dotEnvMu   sync.Mutex
+)
+
+// loadDotEnv применяет переменные из .env (если файл есть), не перекрывая окружение процесса.
+// В отличие от godotenv.Load повторный вызов подхватывает измененные значения файла,
+// а переменные, удаленные из файла, снимаются.
+func loadDotEnv() error {
+	processEnvOnce.Do(func() {
+		processEnv = make(map[string]struct{})
+		for _, kv := range os.Environ() {
+			name, _, _ := strings.Cut(kv, "=")
+			processEnv[name] = struct{}{}
+		}
+	})
+
+	values, err := godotenv.Read()
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return err
+		}
+		values = map[string]string{}
+	}
+
+	dotEnvMu.Lock()
+	defer dotEnvMu.Unlock()
+	for name := range dotEnvKeys {
+		if _, ok := values[name]; !ok {
+			_ = os.Unsetenv(name)
+		}
+	}
+	dotEnvKeys = make(map[string]struct{}, len(values))
+	for name, value := range values {
+		if _, ok := processEnv[name]; ok {
+			continue
+		}
+		if err := os.Setenv(name, value); err != nil {
+			return err
+		}
+		dotEnvKeys[name] = struct{}{}
+	}
+	return nil
+}
+
+// Reload применяет к работающей конфигурации перезагружаемые поля из next (см. reloadableFields)
+// и возвращает имена переменных окружения остальных полей, которые изменились, но требуют перезапуска.
+func (c *Config) Reload(next *Config) []string {
+	c.reloadMu.Lock()
+	defer c.reloadMu.Unlock()
+
+	c.LogLevel = next.LogLevel
+	c.WebhookURL = next.WebhookURL
+	c.WebhookSecret = next.WebhookSecret
+	c.WebhookMaxRetries = next.WebhookMaxRetries
+	c.APIKeys = next.APIKeys
+	c.AdminAPIKeys = next.AdminAPIKeys
+
+	var ignored []string
+	current, updated := reflect.ValueOf(c).Elem(), reflect.ValueOf(next).Elem()
+	for i := 0; i < current.NumField(); i++ {
+		field := current.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if _, ok := reloadableFields[field.Name]; ok {
+			continue
+		}
+		if !reflect.DeepEqual(current.Field(i).Interface(), updated.Field(i).Interface()) {
+			ignored = append(ignored, field.Tag.Get("env"))
+		}
+	}
+	return ignored
+}
+
+// Keys возвращает текущие списки обычных и административных API-ключей.
+// Используется вместо прямого чтения полей, так как они меняются при перезагрузке.
+func (c *Config) Keys() (apiKeys, adminKeys []string) {
+	c.reloadMu.RLock()
+	defer c.reloadMu.RUnlock()
+	return c.APIKeys, c.AdminAPIKeys
+}
+
+// WebhookDelivery возвращает текущие секрет подписи и число попыток доставки вебхуков
+func (c *Config) WebhookDelivery() (secret string, maxRetries int) {
+	c.reloadMu.RLock()
+	defer c.reloadMu.RUnlock()
+	return c.WebhookSecret, c.WebhookMaxRetries
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// tlsVersions - допустимые значения WEBHOOK_TLS_MIN_VERSION
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// parseTLSVersion разбирает версию TLS вида 1.2
+func parseTLSVersion(key, value string) (uint16, error) {
+	version, ok := tlsVersions[strings.TrimSpace(value)]
+	if !ok {
+		return 0, fmt.Errorf("%s must be one of 1.0, 1.1, 1.2, 1.3, got %q", key, value)
+	}
+	return version, nil
+}
+
+// parseCipherSuites переводит имена наборов шифров (например, TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256)
+// в их идентификаторы. Небезопасные наборы не принимаются.
+func parseCipherSuites(key string, names []string) ([]uint16, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	known := make(map[string]uint16)
+	for _, suite := range tls.CipherSuites() {
+		known[suite.Name] = suite.ID
+	}
+
+	ids := make([]uint16, 0, len(names))
+	for _, name := range names {
+		id, ok := known[name]
+		if !ok {
+			return nil, fmt.Errorf("%s: unknown or insecure cipher suite %q", key, name)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// parsePins нормализует SHA-256 отпечатки открытых ключей (hex, допускаются двоеточия) к нижнему регистру без разделителей
+func parsePins(key string, values []string) ([]string, error) {
+	pins := make([]string, 0, len(values))
+	for _, value := range values {
+		pin := strings.ToLower(strings.ReplaceAll(value, ":", ""))
+		if decoded, err := hex.DecodeString(pin); err != nil || len(decoded) != 32 {
+			return nil, fmt.Errorf("%s: %q is not a hex SHA-256 fingerprint", key, value)
+		}
+		pins = append(pins, pin)
+	}
+	return pins, nil
+}
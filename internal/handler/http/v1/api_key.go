@@ -0,0 +1,106 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shenikar/geo_broadcasting_system/internal/service"
+)
+
+// @Summary Issue an API key
+// @Description Add an API key that is accepted immediately, without a restart. If key is omitted, a random one is generated.
+// @Description The key is returned only in this response. Requires admin API key.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body APIKeyRequest false "Key to add"
+// @Success 201 {object} APIKeyResponse
+// @Failure 400 {object} map[string]string "Malformed request body"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Admin API key required"
+// @Failure 422 {object} ValidationErrorResponse "Validation error"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} ErrorResponse "Storage unavailable, retry after Retry-After seconds"
+// @Router /admin/api-keys [post]
+func (h *Handler) addAPIKey(c *gin.Context) {
+	var input APIKeyRequest
+	log := h.logger.WithField("method", "addAPIKey")
+
+	// Пустое тело допустимо: ключ будет сгенерирован
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			log.WithError(err).Warn("Failed to bind JSON")
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+			return
+		}
+	}
+
+	if err := h.validate.Struct(input); err != nil {
+		log.WithError(err).Warn("Validation failed")
+		h.respondValidationError(c, input, err)
+		return
+	}
+
+	apiKeys, adminKeys := h.cfg.Keys()
+	if input.Key != "" && (containsKey(apiKeys, input.Key) || containsKey(adminKeys, input.Key)) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "key is already configured statically"})
+		return
+	}
+
+	apiKey, err := h.incidentService.AddAPIKey(c.Request.Context(), input.Key)
+	if err != nil {
+		log.WithError(err).Error("Failed to add api key in service")
+		respondServiceError(c, err, "internal server error")
+		return
+	}
+
+	c.JSON(http.StatusCreated, APIKeyResponse{Key: apiKey, Label: apiKeyLabel(apiKey)})
+}
+
+// @Summary Revoke an API key
+// @Description Revoke an API key issued via /admin/api-keys. Keys from API_KEYS and ADMIN_API_KEYS cannot be revoked at runtime.
+// @Description Other instances stop accepting the key within API_KEY_CACHE_TTL. Requires admin API key.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body APIKeyRevokeRequest true "Key to revoke"
+// @Success 204 "No Content"
+// @Failure 400 {object} map[string]string "Malformed request body"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Admin API key required"
+// @Failure 404 {object} map[string]string "API key not found"
+// @Failure 422 {object} ValidationErrorResponse "Validation error"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} ErrorResponse "Storage unavailable, retry after Retry-After seconds"
+// @Router /admin/api-keys/revoke [post]
+func (h *Handler) revokeAPIKey(c *gin.Context) {
+	var input APIKeyRevokeRequest
+	log := h.logger.WithField("method", "revokeAPIKey")
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.WithError(err).Warn("Failed to bind JSON")
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	if err := h.validate.Struct(input); err != nil {
+		log.WithError(err).Warn("Validation failed")
+		h.respondValidationError(c, input, err)
+		return
+	}
+
+	if err := h.incidentService.RevokeAPIKey(c.Request.Context(), input.Key); err != nil {
+		if errors.Is(err, service.ErrAPIKeyNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "api key not found"})
+			return
+		}
+		log.WithError(err).Error("Failed to revoke api key in service")
+		respondServiceError(c, err, "internal server error")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,105 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/shenikar/geo_broadcasting_system/internal/service"
+)
+
+// respondAttachmentError отвечает на ошибку сервиса вложений
+func (h *Handler) respondAttachmentError(c *gin.Context, err error, method string) {
+	switch {
+	case errors.Is(err, service.ErrAttachmentsDisabled):
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "feature is disabled", Code: codeFeatureDisabled})
+	case errors.Is(err, service.ErrIncidentNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "incident not found"})
+	case errors.Is(err, service.ErrInvalidAttachment):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		h.logger.WithField("method", method).WithError(err).Error("Failed to process incident attachment in service")
+		respondServiceError(c, err, "internal server error")
+	}
+}
+
+// @Summary Get a presigned upload URL for an incident attachment
+// @Description Register an attachment for the incident and return a presigned URL of the S3-compatible object storage.
+// @Description The client uploads the file directly to the storage with a PUT request and the returned headers before expires_at;
+// @Description the API stores only the object key. Returns 404 with code FEATURE_DISABLED if S3_BUCKET is not configured. Requires API key.
+// @Tags Incidents
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Incident ID"
+// @Param request body AttachmentUploadRequest true "File name and content type"
+// @Success 201 {object} AttachmentUploadResponse
+// @Failure 400 {object} map[string]string "Invalid incident ID, request body or content type"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} ErrorResponse "Incident not found or attachments disabled"
+// @Failure 422 {object} ValidationErrorResponse "Validation error"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} ErrorResponse "Storage unavailable, retry after Retry-After seconds"
+// @Router /incidents/{id}/attachments/upload-url [post]
+func (h *Handler) createAttachmentUploadURL(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid incident ID"})
+		return
+	}
+	log := h.logger.WithField("method", "createAttachmentUploadURL").WithField("id", id)
+
+	var input AttachmentUploadRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.WithError(err).Warn("Failed to bind JSON")
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	if err := h.validate.Struct(input); err != nil {
+		log.WithError(err).Warn("Validation failed")
+		h.respondValidationError(c, input, err)
+		return
+	}
+
+	presigned, err := h.incidentService.CreateAttachmentUploadURL(c.Request.Context(), id, input.Filename, input.ContentType, c.GetString(apiKeyLabelContextKey))
+	if err != nil {
+		h.respondAttachmentError(c, err, "createAttachmentUploadURL")
+		return
+	}
+	c.JSON(http.StatusCreated, ModelToAttachmentUploadResponse(presigned))
+}
+
+// @Summary List incident attachments
+// @Description List attachments of the incident with presigned download URLs valid until expires_at.
+// @Description Returns 404 with code FEATURE_DISABLED if S3_BUCKET is not configured. Requires API key.
+// @Tags Incidents
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Incident ID"
+// @Success 200 {array} AttachmentResponse
+// @Failure 400 {object} map[string]string "Invalid incident ID"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} ErrorResponse "Incident not found or attachments disabled"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} ErrorResponse "Storage unavailable, retry after Retry-After seconds"
+// @Router /incidents/{id}/attachments [get]
+func (h *Handler) listIncidentAttachments(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid incident ID"})
+		return
+	}
+
+	attachments, err := h.incidentService.ListIncidentAttachments(c.Request.Context(), id)
+	if err != nil {
+		h.respondAttachmentError(c, err, "listIncidentAttachments")
+		return
+	}
+
+	responses := make([]*AttachmentResponse, len(attachments))
+	for i, attachment := range attachments {
+		responses[i] = ModelToAttachmentResponse(attachment)
+	}
+	c.JSON(http.StatusOK, responses)
+}
@@ -1,6 +1,9 @@
 package v1
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"net/http"
 	"strings"
 
@@ -9,38 +12,125 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-// APIKeyAuthMiddleware - middleware для аутентификации по API-ключу
-func APIKeyAuthMiddleware(cfg *config.Config, log *logrus.Logger) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		apiKey := c.GetHeader("X-API-Key")
-		if apiKey == "" {
-			// Проверяем также заголовок Authorization: Bearer
-			authHeader := c.GetHeader("Authorization")
-			if authHeader != "" && strings.HasPrefix(authHeader, "Bearer ") {
-				apiKey = strings.TrimPrefix(authHeader, "Bearer ")
-			}
-		}
+const (
+	// apiKeyLabelContextKey - ключ контекста, под которым хранится метка API-ключа
+	apiKeyLabelContextKey = "api_key_label"
+	// apiKeyAdminContextKey - ключ контекста с признаком административного API-ключа
+	apiKeyAdminContextKey = "api_key_admin"
+)
+
+// apiKeyLabel возвращает несекретную метку API-ключа для логов и метрик
+func apiKeyLabel(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return "key_" + hex.EncodeToString(sum[:4])
+}
 
+// RuntimeAPIKeyChecker проверяет API-ключи, выпущенные во время работы сервиса
+type RuntimeAPIKeyChecker interface {
+	IsRuntimeAPIKey(ctx context.Context, apiKey string) (bool, error)
+}
+
+// APIKeyAuthMiddleware - middleware для аутентификации по API-ключу.
+// Помимо ключей из конфигурации принимаются ключи, выпущенные через API (если runtimeKeys не nil);
+// такие ключи никогда не дают прав администратора.
+func APIKeyAuthMiddleware(cfg *config.Config, log *logrus.Logger, runtimeKeys RuntimeAPIKeyChecker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		apiKey := requestAPIKey(c)
 		if apiKey == "" {
 			log.Warn("API key missing from request")
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "API key required"})
 			return
 		}
 
-		isValid := false
-		for _, key := range cfg.APIKeys {
-			if key == apiKey {
-				isValid = true
-				break
-			}
+		if !authenticateAPIKey(c, cfg, log, runtimeKeys, apiKey) {
+			return
 		}
+		c.Next()
+	}
+}
 
-		if !isValid {
-			log.Warnf("Invalid API key provided: %s", apiKey)
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid API key"})
+// OptionalAPIKeyAuthMiddleware - middleware для публичных маршрутов: запрос без ключа пропускается анонимно,
+// а переданный ключ проверяется так же, как в APIKeyAuthMiddleware. Неверный ключ отклоняется,
+// чтобы клиент не получил молча анонимную обработку вместо доверенной.
+func OptionalAPIKeyAuthMiddleware(cfg *config.Config, log *logrus.Logger, runtimeKeys RuntimeAPIKeyChecker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		apiKey := requestAPIKey(c)
+		if apiKey == "" {
+			c.Next()
 			return
 		}
 
+		if !authenticateAPIKey(c, cfg, log, runtimeKeys, apiKey) {
+			return
+		}
 		c.Next()
 	}
 }
+
+// requestAPIKey возвращает API-ключ из заголовка X-API-Key или Authorization: Bearer
+func requestAPIKey(c *gin.Context) string {
+	apiKey := c.GetHeader("X-API-Key")
+	if apiKey == "" {
+		// Проверяем также заголовок Authorization: Bearer
+		authHeader := c.GetHeader("Authorization")
+		if authHeader != "" && strings.HasPrefix(authHeader, "Bearer ") {
+			apiKey = strings.TrimPrefix(authHeader, "Bearer ")
+		}
+	}
+	return apiKey
+}
+
+// authenticateAPIKey проверяет ключ и сохраняет его метку и права в контексте.
+// При неверном ключе или сбое проверки запрос прерывается и возвращается false.
+func authenticateAPIKey(c *gin.Context, cfg *config.Config, log *logrus.Logger, runtimeKeys RuntimeAPIKeyChecker, apiKey string) bool {
+	apiKeys, adminKeys := cfg.Keys()
+	isAdmin := containsKey(adminKeys, apiKey)
+	isValid := isAdmin || containsKey(apiKeys, apiKey)
+	if !isValid && runtimeKeys != nil {
+		var err error
+		isValid, err = runtimeKeys.IsRuntimeAPIKey(c.Request.Context(), apiKey)
+		if err != nil {
+			log.WithError(err).Error("Failed to verify runtime API key")
+			respondUnavailable(c)
+			return false
+		}
+	}
+
+	if !isValid {
+		log.Warnf("Invalid API key provided: %s", apiKey)
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid API key"})
+		return false
+	}
+
+	c.Set(apiKeyLabelContextKey, apiKeyLabel(apiKey))
+	c.Set(apiKeyAdminContextKey, isAdmin)
+	return true
+}
+
+// isAuthenticated сообщает, прошел ли запрос аутентификацию по API-ключу
+func isAuthenticated(c *gin.Context) bool {
+	return c.GetString(apiKeyLabelContextKey) != ""
+}
+
+// AdminOnlyMiddleware - middleware, пропускающее только административные API-ключи.
+// Должно использоваться после APIKeyAuthMiddleware.
+func AdminOnlyMiddleware(log *logrus.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !c.GetBool(apiKeyAdminContextKey) {
+			log.WithField("api_key", c.GetString(apiKeyLabelContextKey)).Warn("Non-admin API key attempted admin operation")
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "admin API key required"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// containsKey проверяет наличие ключа в списке
+func containsKey(keys []string, apiKey string) bool {
+	for _, key := range keys {
+		if key == apiKey {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shenikar/geo_broadcasting_system/internal/models"
+	"github.com/shenikar/geo_broadcasting_system/internal/service"
+)
+
+// @Summary Bulk deactivate incidents
+// @Description Deactivate many incidents in one transaction, either by an explicit list of IDs or by a filter.
+// @Description Filter-based operations require "confirm": true. Only active incidents are deactivated: drafts, rejected and already finished incidents are skipped, so the operation is idempotent. Requires API key.
+// @Tags Incidents
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body BulkDeactivateRequest true "Bulk deactivation request"
+// @Success 200 {object} BulkDeactivateResponse
+// @Failure 400 {object} map[string]string "Invalid request body, missing target or confirmation"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 422 {object} ValidationErrorResponse "Validation error"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} ErrorResponse "Storage unavailable, retry after Retry-After seconds"
+// @Router /incidents/deactivate [post]
+func (h *Handler) bulkDeactivateIncidents(c *gin.Context) {
+	var input BulkDeactivateRequest
+	log := h.logger.WithField("method", "bulkDeactivateIncidents")
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.WithError(err).Warn("Failed to bind JSON")
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	if err := h.validate.Struct(input); err != nil {
+		log.WithError(err).Warn("Validation failed")
+		h.respondValidationError(c, input, err)
+		return
+	}
+
+	hasIDs := len(input.IDs) > 0
+	hasFilter := input.Filter != nil && (input.Filter.Status != "" || input.Filter.OlderThan != nil)
+	switch {
+	case hasIDs == hasFilter:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "exactly one of ids or filter must be provided"})
+		return
+	case hasFilter && !input.Confirm:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "filter-based deactivation requires confirm=true"})
+		return
+	}
+
+	target := models.BulkDeactivation{IDs: input.IDs}
+	if hasFilter {
+		target.Status = models.IncidentStatus(input.Filter.Status)
+		target.OlderThan = input.Filter.OlderThan
+	}
+
+	count, err := h.incidentService.BulkDeactivateIncidents(c.Request.Context(), target)
+	if err != nil {
+		if errors.Is(err, service.ErrEmptyBulkTarget) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		log.WithError(err).Error("Failed to bulk deactivate incidents in service")
+		respondServiceError(c, err, "failed to deactivate incidents")
+		return
+	}
+
+	c.JSON(http.StatusOK, BulkDeactivateResponse{Deactivated: count})
+}
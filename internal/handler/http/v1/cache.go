@@ -0,0 +1,31 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Invalidate caches
+// @Description Flush incident, location tile and stats caches in Redis and reset in-memory indexes, e.g. after fixing data directly in the database. Only keys under the configured key prefix are removed. Requires admin API key.
+// @Tags Admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} CacheInvalidateResponse
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Admin API key required"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} ErrorResponse "Storage unavailable, retry after Retry-After seconds"
+// @Router /admin/cache/invalidate [post]
+func (h *Handler) invalidateCaches(c *gin.Context) {
+	log := h.logger.WithField("method", "invalidateCaches")
+
+	removed, err := h.incidentService.InvalidateCaches(c.Request.Context())
+	if err != nil {
+		log.WithError(err).Error("Failed to invalidate caches in service")
+		respondServiceError(c, err, "internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, CacheInvalidateResponse{KeysRemoved: removed})
+}
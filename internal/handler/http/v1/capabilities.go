@@ -0,0 +1,60 @@
+package v1
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shenikar/geo_broadcasting_system/internal/models"
+	"github.com/shenikar/geo_broadcasting_system/internal/service"
+	"github.com/shenikar/geo_broadcasting_system/internal/webhook"
+)
+
+// capabilitiesMaxAge - сколько клиенты и прокси могут кэшировать ответ GET /system/capabilities
+const capabilitiesMaxAge = "public, max-age=60"
+
+// @Summary Get server capabilities and limits
+// @Description Get runtime limits and supported values (page size, bulk and merge size, category radius rules, severities, statuses, sources, enabled features, rate limits) so that clients do not hardcode them. Does not require an API key; the response may be cached for 60 seconds.
+// @Tags System
+// @Produce json
+// @Success 200 {object} CapabilitiesResponse
+// @Header 200 {string} Cache-Control "public, max-age=60"
+// @Router /system/capabilities [get]
+func (h *Handler) getCapabilities(c *gin.Context) {
+	resp := CapabilitiesResponse{
+		MaxPageSize:                  service.MaxPageSize,
+		MaxBulkDeactivateIDs:         bulkDeactivateMaxIDs,
+		MaxMergeIDs:                  mergeIncidentsMaxIDs,
+		MaxMediaURLs:                 maxMediaURLs,
+		Categories:                   make([]CategoryCapabilityResponse, 0, len(h.cfg.IncidentCategoryRules)),
+		Severities:                   make([]string, 0, len(models.IncidentSeverities)),
+		Statuses:                     make([]string, 0, len(models.IncidentStatuses)),
+		Sources:                      models.IncidentSources,
+		Features:                     make(map[string]bool, len(service.Features)),
+		UserCheckQuotaPerMinute:      h.cfg.UserCheckQuotaPerMinute,
+		PublicFeedRateLimitPerMinute: h.cfg.PublicFeedRateLimitPerMinute,
+		WebhookSchemaVersion:         webhook.SchemaVersion,
+	}
+	for name, rule := range h.cfg.IncidentCategoryRules {
+		resp.Categories = append(resp.Categories, CategoryCapabilityResponse{
+			Name:                name,
+			MinRadiusMeters:     rule.MinRadiusMeters,
+			MaxRadiusMeters:     rule.MaxRadiusMeters,
+			DefaultRadiusMeters: rule.DefaultRadiusMeters,
+		})
+	}
+	slices.SortFunc(resp.Categories, func(a, b CategoryCapabilityResponse) int { return strings.Compare(a.Name, b.Name) })
+	for _, severity := range models.IncidentSeverities {
+		resp.Severities = append(resp.Severities, string(severity))
+	}
+	for _, status := range models.IncidentStatuses {
+		resp.Statuses = append(resp.Statuses, string(status))
+	}
+	for _, feature := range service.Features {
+		resp.Features[feature] = h.incidentService.FeatureEnabled(c.Request.Context(), feature)
+	}
+
+	c.Header("Cache-Control", capabilitiesMaxAge)
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,56 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Get incidents changed since a cursor
+// @Description Get incidents (including deactivated ones) changed after the cursor, ordered by modification time and ID.
+// @Description Pass cursor.updated_at and cursor.id from the response as since and after_id to get the next page while has_more is true.
+// @Description server_time is returned only on the final page; the next sync starts from the cursor of that page. Requires API key.
+// @Tags Incidents
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param since query string true "RFC 3339 timestamp (cursor.updated_at of the previous response)"
+// @Param after_id query string false "Incident ID (cursor.id of the previous response)"
+// @Param pageSize query int false "Number of items per page" default(10)
+// @Success 200 {object} IncidentChangesResponse
+// @Failure 400 {object} map[string]string "Missing or invalid since or after_id"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} ErrorResponse "Storage unavailable, retry after Retry-After seconds"
+// @Router /incidents/changes [get]
+func (h *Handler) listIncidentChanges(c *gin.Context) {
+	log := h.logger.WithField("method", "listIncidentChanges")
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "10"))
+
+	after, err := parseChangeCursor(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Время фиксируется до запроса, поэтому не опережает ни одно изменение, не попавшее в выборку
+	serverTime := time.Now().UTC()
+	incidents, cursor, hasMore, err := h.incidentService.ListIncidentChanges(c.Request.Context(), after, pageSize)
+	if err != nil {
+		log.WithError(err).Error("Failed to list incident changes from service")
+		respondServiceError(c, err, "internal server error")
+		return
+	}
+
+	resp := IncidentChangesResponse{
+		Incidents: ModelsToIncidentResponses(incidents),
+		Cursor:    ModelToChangeCursorResponse(cursor),
+		HasMore:   hasMore,
+	}
+	if !hasMore {
+		resp.ServerTime = &serverTime
+	}
+	c.JSON(http.StatusOK, resp)
+}
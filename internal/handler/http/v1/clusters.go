@@ -0,0 +1,86 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Get incident clusters for a map area
+// @Description Group active incidents inside the bounding box into grid cells whose size shrinks as zoom grows.
+// @Description Each cluster has the average position and count; single-incident clusters include incident_id. Requires API key.
+// @Tags Incidents
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param min_lat query number true "South boundary latitude"
+// @Param min_lon query number true "West boundary longitude"
+// @Param max_lat query number true "North boundary latitude"
+// @Param max_lon query number true "East boundary longitude (less than min_lon for an area crossing the antimeridian)"
+// @Param zoom query int true "Map zoom level (0-20)"
+// @Success 200 {array} ClusterResponse
+// @Failure 400 {object} map[string]string "Invalid bounding box or zoom"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} ErrorResponse "Feature disabled (FEATURE_DISABLED)"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} ErrorResponse "Storage unavailable, retry after Retry-After seconds"
+// @Router /incidents/clusters [get]
+func (h *Handler) getIncidentClusters(c *gin.Context) {
+	log := h.logger.WithField("method", "getIncidentClusters")
+
+	bbox, zoom, err := parseClusterQuery(c)
+	if err != nil {
+		log.WithError(err).Warn("Invalid cluster query")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	clusters, err := h.incidentService.ClusterIncidents(c.Request.Context(), bbox, zoom)
+	if err != nil {
+		log.WithError(err).Error("Failed to cluster incidents in service")
+		respondServiceError(c, err, "internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, ModelsToClusterResponses(clusters))
+}
+
+// @Summary Get incident hotspots for a map area
+// @Description Group active incidents inside the bounding box into square grid cells of cell_meters and return the cells
+// @Description holding at least min_count incidents (HOTSPOT_MIN_INCIDENTS by default): many small incidents close together may indicate a larger situation.
+// @Description Each hotspot has the cell center, bounds and incident count, ordered by count. Requires API key.
+// @Tags Incidents
+// @Produce json
+// @Security ApiKeyAuth
+// @Param min_lat query number true "South boundary latitude"
+// @Param min_lon query number true "West boundary longitude"
+// @Param max_lat query number true "North boundary latitude"
+// @Param max_lon query number true "East boundary longitude (less than min_lon for an area crossing the antimeridian)"
+// @Param cell_meters query number true "Grid cell size in meters (100-100000)"
+// @Param min_count query int false "Minimum number of active incidents in a cell (at least 2)"
+// @Success 200 {array} HotspotResponse
+// @Failure 400 {object} map[string]string "Invalid bounding box, cell size or threshold"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} ErrorResponse "Feature disabled (FEATURE_DISABLED)"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} ErrorResponse "Storage unavailable, retry after Retry-After seconds"
+// @Router /incidents/hotspots [get]
+func (h *Handler) getIncidentHotspots(c *gin.Context) {
+	log := h.logger.WithField("method", "getIncidentHotspots")
+
+	bbox, cellMeters, minCount, err := parseHotspotQuery(c)
+	if err != nil {
+		log.WithError(err).Warn("Invalid hotspot query")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	hotspots, err := h.incidentService.FindHotspots(c.Request.Context(), bbox, cellMeters, minCount)
+	if err != nil {
+		log.WithError(err).Error("Failed to find incident hotspots in service")
+		respondServiceError(c, err, "internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, ModelsToHotspotResponses(hotspots))
+}
@@ -0,0 +1,33 @@
+package v1
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondNotModified выставляет заголовок Last-Modified и отвечает 304, если If-Modified-Since
+// клиента не старше lastModified. Возвращает true, если ответ уже отправлен.
+// HTTP-даты имеют точность в секунду, поэтому lastModified округляется вверх, а пока секунда последнего
+// изменения не закончилась, заголовок не выставляется: изменение в ту же секунду иначе осталось бы незамеченным.
+func respondNotModified(c *gin.Context, lastModified time.Time) bool {
+	if lastModified.IsZero() {
+		return false
+	}
+	rounded := lastModified.Truncate(time.Second)
+	if rounded.Before(lastModified) {
+		rounded = rounded.Add(time.Second)
+	}
+	if rounded.After(time.Now()) {
+		return false
+	}
+
+	c.Header("Last-Modified", rounded.UTC().Format(http.TimeFormat))
+	since, err := http.ParseTime(c.GetHeader("If-Modified-Since"))
+	if err != nil || rounded.After(since) {
+		return false
+	}
+	c.AbortWithStatus(http.StatusNotModified)
+	return true
+}
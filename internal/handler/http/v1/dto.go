@@ -9,22 +9,108 @@ import (
 // CreateIncidentRequest DTO для создания инцидента
 // @Description DTO для создания инцидента
 type CreateIncidentRequest struct {
-	Name         string  `json:"name" validate:"required,min=2,max=255"`
-	Description  string  `json:"description,omitempty"`
-	Latitude     float64 `json:"latitude" validate:"required,latitude"`
-	Longitude    float64 `json:"longitude" validate:"required,longitude"`
-	RadiusMeters int     `json:"radius_meters" validate:"required,gt=0"`
+	Name        string  `json:"name" validate:"required,min=2,max=255"`
+	Description string  `json:"description,omitempty"`
+	Latitude    float64 `json:"latitude" validate:"required,latitude"`
+	Longitude   float64 `json:"longitude" validate:"required,longitude"`
+	// RadiusMeters - радиус зоны; можно не указывать вместе с category, если для категории задан радиус по умолчанию
+	RadiusMeters int `json:"radius_meters,omitempty" validate:"required_without=Category,gte=0"`
+	// MediaURLs - необязательные ссылки на медиа. Пустая ссылка не проходит проверку url, поэтому required
+	// для элементов не указывается: swag по нему пометил бы обязательным все поле
+	MediaURLs []string `json:"media_urls,omitempty" validate:"omitempty,max=10,dive,url,max=2048"`
+	// Status - начальный статус; если не указан, используется DEFAULT_INCIDENT_STATUS
+	Status string `json:"status,omitempty" validate:"omitempty,oneof=active draft"`
+	// Source - источник сведений; по умолчанию official
+	Source     string `json:"source,omitempty" validate:"omitempty,oneof=official crowdsourced sensor"`
+	Confidence *int   `json:"confidence,omitempty" validate:"omitempty,gte=0,lte=100"`
+	// Silent - не отправлять вебхуки об опасности по этому инциденту (временный режим для тестирования)
+	Silent bool `json:"silent,omitempty"`
+	// Public - публиковать инцидент в открытой ленте GET /public/incidents
+	Public bool `json:"public,omitempty"`
+	// Severity - уровень опасности; по умолчанию minor
+	Severity string `json:"severity,omitempty" validate:"omitempty,oneof=minor moderate major critical"`
+	// Category - категория опасности; правила радиуса задаются в INCIDENT_CATEGORY_RULES
+	Category string `json:"category,omitempty" validate:"omitempty,max=64"`
+	// ExternalID - идентификатор инцидента во внешней системе; уникален
+	ExternalID string `json:"external_id,omitempty" validate:"omitempty,max=255"`
 }
 
-// UpdateIncidentRequest DTO для обновления инцидента
-// @Description DTO для обновления инцидента
+// UpdateIncidentRequest DTO для обновления инцидента. Обновление полное (семантика PUT): пропущенное поле
+// сбрасывается в пустое значение, в том числе silent и public становятся false. Сохраняются только
+// source, severity и external_id, если они не указаны.
+// @Description DTO для полного обновления инцидента: пропущенные поля сбрасываются (silent и public - в false),
+// @Description кроме source, severity и external_id, которые без значения сохраняются
 type UpdateIncidentRequest struct {
 	Name         string  `json:"name" validate:"required,min=2,max=255"`
 	Description  string  `json:"description,omitempty"`
 	Latitude     float64 `json:"latitude" validate:"required,latitude"`
 	Longitude    float64 `json:"longitude" validate:"required,longitude"`
 	RadiusMeters int     `json:"radius_meters" validate:"required,gt=0"`
-	Status       string  `json:"status" validate:"required,oneof=active inactive"`
+	Status       string  `json:"status" validate:"required,oneof=active inactive draft resolved"`
+	// MediaURLs - необязательные ссылки на медиа, проверяются как в CreateIncidentRequest
+	MediaURLs []string `json:"media_urls,omitempty" validate:"omitempty,max=10,dive,url,max=2048"`
+	// Source - источник сведений; если не указан, сохраняется текущий
+	Source     string `json:"source,omitempty" validate:"omitempty,oneof=official crowdsourced sensor"`
+	Confidence *int   `json:"confidence,omitempty" validate:"omitempty,gte=0,lte=100"`
+	// Silent - не отправлять вебхуки об опасности по этому инциденту (временный режим для тестирования);
+	// если не указан, сбрасывается в false
+	Silent bool `json:"silent,omitempty"`
+	// Public - публиковать инцидент в открытой ленте GET /public/incidents; если не указан, сбрасывается в false
+	Public bool `json:"public,omitempty"`
+	// Severity - уровень опасности; если не указан, сохраняется текущий
+	Severity string `json:"severity,omitempty" validate:"omitempty,oneof=minor moderate major critical"`
+	// ResolutionReason - причина завершения инцидента
+	ResolutionReason string `json:"resolution_reason,omitempty" validate:"omitempty,max=500"`
+	// Category - категория опасности; правила радиуса задаются в INCIDENT_CATEGORY_RULES
+	Category string `json:"category,omitempty" validate:"omitempty,max=64"`
+	// ExternalID - идентификатор инцидента во внешней системе; если не указан, сохраняется текущий
+	ExternalID string `json:"external_id,omitempty" validate:"omitempty,max=255"`
+}
+
+// DeactivateIncidentRequest DTO для деактивации инцидента.
+// Тело запроса необязательно: без него инцидент снимается вручную (статус inactive).
+// @Description DTO для деактивации инцидента
+type DeactivateIncidentRequest struct {
+	// Resolved - инцидент завершился естественным образом (статус resolved)
+	Resolved bool   `json:"resolved"`
+	Reason   string `json:"reason,omitempty" validate:"omitempty,max=500"`
+}
+
+// IncidentTemplateRequest DTO для создания и обновления шаблона инцидента
+// @Description DTO для создания и обновления шаблона инцидента
+type IncidentTemplateRequest struct {
+	Name        string `json:"name" validate:"required,min=2,max=255"`
+	Description string `json:"description,omitempty"`
+	// Category - категория опасности; без radius_meters используется радиус категории по умолчанию
+	Category     string `json:"category,omitempty" validate:"omitempty,max=64"`
+	RadiusMeters int    `json:"radius_meters,omitempty" validate:"required_without=Category,gte=0"`
+	Source       string `json:"source,omitempty" validate:"omitempty,oneof=official crowdsourced sensor"`
+}
+
+// IncidentTemplateResponse DTO для ответа с шаблоном инцидента
+// @Description DTO для ответа с шаблоном инцидента
+type IncidentTemplateResponse struct {
+	ID           uuid.UUID `json:"id"`
+	Name         string    `json:"name"`
+	Description  string    `json:"description"`
+	Category     string    `json:"category,omitempty"`
+	RadiusMeters int       `json:"radius_meters"`
+	Source       string    `json:"source,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// CreateFromTemplateRequest DTO для создания инцидента по шаблону: достаточно координат
+// @Description DTO для создания инцидента по шаблону
+type CreateFromTemplateRequest struct {
+	Latitude  float64 `json:"latitude" validate:"required,latitude"`
+	Longitude float64 `json:"longitude" validate:"required,longitude"`
+	// Name и Description - необязательная замена названия и описания шаблона
+	Name        string `json:"name,omitempty" validate:"omitempty,min=2,max=255"`
+	Description string `json:"description,omitempty"`
+	// Status - начальный статус; если не указан, используется DEFAULT_INCIDENT_STATUS
+	Status     string `json:"status,omitempty" validate:"omitempty,oneof=active draft"`
+	Confidence *int   `json:"confidence,omitempty" validate:"omitempty,gte=0,lte=100"`
 }
 
 // IncidentResponse DTO для ответа с информацией об инциденте
@@ -36,21 +122,491 @@ type IncidentResponse struct {
 	Latitude     float64   `json:"latitude"`
 	Longitude    float64   `json:"longitude"`
 	RadiusMeters int       `json:"radius_meters"`
-	Status       string    `json:"status"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	// AreaSqMeters - площадь зоны инцидента в квадратных метрах (вычисляется по радиусу)
+	AreaSqMeters float64  `json:"area_sq_meters"`
+	Status       string   `json:"status"`
+	MediaURLs    []string `json:"media_urls,omitempty"`
+	Source       string   `json:"source"`
+	Confidence   *int     `json:"confidence,omitempty"`
+	Silent       bool     `json:"silent"`
+	Public       bool     `json:"public"`
+	Severity     string   `json:"severity"`
+	Category     string   `json:"category,omitempty"`
+	ExternalID   string   `json:"external_id,omitempty"`
+	// ResolutionReason - причина завершения инцидента
+	ResolutionReason string `json:"resolution_reason,omitempty"`
+	// CreatedBy - метка API-ключа, создавшего инцидент
+	CreatedBy string    `json:"created_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// IncidentVersionResponse DTO для ответа с версией инцидента
+// @Description Полный снимок состояния инцидента после одного изменения
+type IncidentVersionResponse struct {
+	Version   int               `json:"version"`
+	Incident  *IncidentResponse `json:"incident"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// BulkDeactivateFilter фильтр инцидентов для массовой деактивации
+// @Description Фильтр инцидентов для массовой деактивации
+type BulkDeactivateFilter struct {
+	// Status - деактивируются только действующие инциденты, поэтому допустим лишь статус active
+	Status    string     `json:"status,omitempty" validate:"omitempty,oneof=active"`
+	OlderThan *time.Time `json:"older_than,omitempty"`
+}
+
+// bulkDeactivateMaxIDs - наибольшее число ids в BulkDeactivateRequest (совпадает с тегом validate)
+const bulkDeactivateMaxIDs = 1000
+
+// maxMediaURLs - наибольшее число media_urls у инцидента (совпадает с тегами validate)
+const maxMediaURLs = 10
+
+// BulkDeactivateRequest DTO для массовой деактивации инцидентов.
+// Указывается либо список ids, либо filter; операции по фильтру требуют confirm=true.
+// @Description DTO для массовой деактивации инцидентов
+type BulkDeactivateRequest struct {
+	IDs     []uuid.UUID           `json:"ids,omitempty" validate:"omitempty,max=1000"`
+	Filter  *BulkDeactivateFilter `json:"filter,omitempty"`
+	Confirm bool                  `json:"confirm"`
+}
+
+// BulkDeactivateResponse DTO для ответа на массовую деактивацию
+// @Description DTO для ответа на массовую деактивацию
+type BulkDeactivateResponse struct {
+	Deactivated int `json:"deactivated"`
+}
+
+// mergeIncidentsMaxIDs - наибольшее число ids в MergeIncidentsRequest (совпадает с тегом validate)
+const mergeIncidentsMaxIDs = 50
+
+// MergeIncidentsRequest DTO для слияния дублирующихся инцидентов.
+// Первый из ids становится каноническим, остальные поглощаются им.
+// @Description DTO для слияния дублирующихся инцидентов; первый из ids становится каноническим
+type MergeIncidentsRequest struct {
+	IDs []uuid.UUID `json:"ids" validate:"required,min=2,max=50,unique"`
+}
+
+// MergeIncidentsResponse DTO для ответа на слияние инцидентов
+// @Description DTO для ответа на слияние инцидентов
+type MergeIncidentsResponse struct {
+	Incident         *IncidentResponse `json:"incident"`
+	MergedIDs        []uuid.UUID       `json:"merged_ids"`
+	RedirectedChecks int64             `json:"redirected_checks" example:"12"`
 }
 
 // LocationCheckRequest DTO для проверки координат
 // @Description DTO для проверки координат
 type LocationCheckRequest struct {
-	UserID    string  `json:"user_id" validate:"required"`
+	UserID       string  `json:"user_id" validate:"required"`
+	Latitude     float64 `json:"latitude" validate:"required,latitude"`
+	Longitude    float64 `json:"longitude" validate:"required,longitude"`
+	BufferMeters int     `json:"buffer_meters,omitempty" validate:"omitempty,gte=0"`
+	// MinConfidence - не учитывать инциденты с меньшей достоверностью (неоцененные учитываются всегда)
+	MinConfidence int `json:"min_confidence,omitempty" validate:"omitempty,gte=0,lte=100"`
+}
+
+// LocationCheckResultResponse DTO результата проверки местоположения в форме объекта
+// (LOCATION_CHECK_RESPONSE=object или Accept: application/vnd.geo.location-check+json)
+// @Description DTO результата проверки местоположения в форме объекта
+type LocationCheckResultResponse struct {
+	// Dangerous - точка попала в зону хотя бы одного активного инцидента
+	Dangerous bool                `json:"dangerous"`
+	Incidents []*IncidentResponse `json:"incidents"`
+	// Truncated - возвращены не все совпавшие инциденты (MAX_MATCHED_INCIDENTS); без усечения поле не передается
+	Truncated bool `json:"truncated,omitempty"`
+	// Matched - число всех совпавших инцидентов, передается вместе с truncated
+	Matched int `json:"matched,omitempty"`
+}
+
+// LocationCheckResponse DTO для записи истории проверок местоположения
+// @Description DTO для записи истории проверок местоположения
+type LocationCheckResponse struct {
+	ID          int64   `json:"id"`
+	UserID      string  `json:"user_id"`
+	Latitude    float64 `json:"latitude"`
+	Longitude   float64 `json:"longitude"`
+	IsDangerous bool    `json:"is_dangerous"`
+	// Authenticated - проверка выполнена клиентом с API-ключом
+	Authenticated bool        `json:"authenticated"`
+	IncidentIDs   []uuid.UUID `json:"incident_ids"`
+	CheckedAt     time.Time   `json:"checked_at"`
+}
+
+// UserLocationRequest DTO для сохранения места пользователя
+// @Description DTO для сохранения места пользователя
+type UserLocationRequest struct {
+	Label     string  `json:"label" validate:"required,max=100"`
 	Latitude  float64 `json:"latitude" validate:"required,latitude"`
 	Longitude float64 `json:"longitude" validate:"required,longitude"`
 }
 
+// UserLocationResponse DTO для ответа с сохраненным местом пользователя
+// @Description DTO для ответа с сохраненным местом пользователя
+type UserLocationResponse struct {
+	ID        uuid.UUID `json:"id"`
+	UserID    string    `json:"user_id"`
+	Label     string    `json:"label"`
+	Latitude  float64   `json:"latitude"`
+	Longitude float64   `json:"longitude"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// UserAlertResponse DTO для активных инцидентов, затрагивающих сохраненное место пользователя
+// @Description DTO для активных инцидентов, затрагивающих сохраненное место пользователя
+type UserAlertResponse struct {
+	Label     string               `json:"label"`
+	Location  UserLocationResponse `json:"location"`
+	Incidents []*IncidentResponse  `json:"incidents"`
+}
+
+// UserStatusResponse DTO для текущего статуса опасности пользователя
+// @Description DTO для текущего статуса опасности пользователя в точке последней проверки
+type UserStatusResponse struct {
+	UserID string `json:"user_id"`
+	// Status - danger, если точка последней проверки сейчас в зоне активного инцидента, иначе safe
+	Status      string `json:"status" enums:"danger,safe"`
+	IsDangerous bool   `json:"is_dangerous"`
+	// Latitude, Longitude и CheckedAt - координаты и время последней проверки
+	Latitude  float64             `json:"latitude"`
+	Longitude float64             `json:"longitude"`
+	CheckedAt time.Time           `json:"checked_at"`
+	Incidents []*IncidentResponse `json:"incidents"`
+}
+
+// ImpactResponse DTO для ответа с оценкой числа затронутых инцидентом пользователей
+// @Description DTO для ответа с оценкой числа затронутых инцидентом пользователей
+type ImpactResponse struct {
+	IncidentID    uuid.UUID `json:"incident_id"`
+	UserCount     int       `json:"user_count"`
+	WindowMinutes int       `json:"window_minutes"`
+}
+
+// PreviewImpactRequest DTO для оценки числа пользователей, которых затронул бы гипотетический инцидент
+// @Description DTO для оценки числа пользователей, которых затронул бы гипотетический инцидент
+type PreviewImpactRequest struct {
+	Latitude     float64 `json:"latitude" validate:"required,latitude"`
+	Longitude    float64 `json:"longitude" validate:"required,longitude"`
+	RadiusMeters int     `json:"radius_meters" validate:"required,gt=0"`
+}
+
+// PreviewImpactResponse DTO для ответа с оценкой числа пользователей в зоне гипотетического инцидента
+// @Description DTO для ответа с оценкой числа пользователей в зоне гипотетического инцидента
+type PreviewImpactResponse struct {
+	UserCount     int `json:"user_count"`
+	WindowMinutes int `json:"window_minutes"`
+}
+
+// PublicIncidentResponse DTO инцидента в публичной ленте: только сведения, которые можно показывать жителям
+// @Description Инцидент в публичной ленте без служебных полей
+type PublicIncidentResponse struct {
+	ID           uuid.UUID `json:"id"`
+	Name         string    `json:"name"`
+	Description  string    `json:"description,omitempty"`
+	Latitude     float64   `json:"latitude"`
+	Longitude    float64   `json:"longitude"`
+	RadiusMeters int       `json:"radius_meters"`
+	Severity     string    `json:"severity"`
+	Category     string    `json:"category,omitempty"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// OverlappingIncidentResponse DTO для инцидента, зона которого пересекается с зоной другого инцидента
+// @Description DTO для инцидента, зона которого пересекается с зоной другого инцидента
+type OverlappingIncidentResponse struct {
+	Incident            *IncidentResponse `json:"incident"`
+	OverlapAreaSqMeters float64           `json:"overlap_area_sq_meters"`
+}
+
+// ChangeCursorResponse DTO позиции в ленте изменений: UpdatedAt и ID передаются как since и after_id в следующем запросе
+// @Description DTO позиции в ленте изменений
+type ChangeCursorResponse struct {
+	UpdatedAt time.Time `json:"updated_at"`
+	ID        uuid.UUID `json:"id"`
+}
+
+// IncidentChangesResponse DTO для ответа инкрементальной синхронизации.
+// Cursor передается как since и after_id в следующем запросе. HasMore и ServerTime заполняет только
+// GET /incidents/changes: ServerTime - на последней странице, когда HasMore = false.
+// @Description DTO для ответа инкрементальной синхронизации
+type IncidentChangesResponse struct {
+	Incidents  []*IncidentResponse  `json:"incidents"`
+	Cursor     ChangeCursorResponse `json:"cursor"`
+	HasMore    bool                 `json:"has_more"`
+	ServerTime *time.Time           `json:"server_time,omitempty"`
+}
+
+// ClusterResponse DTO для кластера инцидентов на карте
+// @Description DTO для кластера инцидентов на карте
+type ClusterResponse struct {
+	Latitude   float64    `json:"latitude"`
+	Longitude  float64    `json:"longitude"`
+	Count      int        `json:"count"`
+	IncidentID *uuid.UUID `json:"incident_id,omitempty"`
+}
+
+// HotspotResponse DTO для очага - ячейки сетки со скоплением активных инцидентов
+// @Description DTO для очага - ячейки сетки со скоплением активных инцидентов
+type HotspotResponse struct {
+	Latitude  float64        `json:"latitude"`
+	Longitude float64        `json:"longitude"`
+	Bounds    BoundsResponse `json:"bounds"`
+	Count     int            `json:"count"`
+}
+
+// BoundsResponse DTO для описывающего прямоугольника
+// @Description DTO для описывающего прямоугольника
+type BoundsResponse struct {
+	MinLat float64 `json:"min_lat"`
+	MinLon float64 `json:"min_lon"`
+	MaxLat float64 `json:"max_lat"`
+	MaxLon float64 `json:"max_lon"`
+}
+
+// GeofenceValidationResponse DTO для результата проверки зоны инцидента
+// @Description DTO для результата проверки зоны инцидента
+type GeofenceValidationResponse struct {
+	Valid            bool           `json:"valid"`
+	Errors           []string       `json:"errors"`
+	AreaSquareMeters float64        `json:"area_square_meters"`
+	Bounds           BoundsResponse `json:"bounds"`
+	// CrossesAntimeridian - зона пересекает 180-й меридиан, в bounds min_lon больше max_lon
+	CrossesAntimeridian bool `json:"crosses_antimeridian"`
+}
+
+// TimelineBucketResponse DTO для корзины временной шкалы
+// @Description DTO для корзины временной шкалы
+type TimelineBucketResponse struct {
+	Start time.Time `json:"start"`
+	Count int       `json:"count"`
+}
+
+// TimelineResponse DTO для временной шкалы создания инцидентов
+// @Description DTO для временной шкалы создания инцидентов
+type TimelineResponse struct {
+	Interval string                    `json:"interval"`
+	Buckets  []*TimelineBucketResponse `json:"buckets"`
+}
+
+// WebhookQueueResponse DTO для ответа с состоянием очереди вебхуков
+// @Description DTO для ответа с состоянием очереди вебхуков
+type WebhookQueueResponse struct {
+	Backend        string `json:"backend"`
+	Depth          int64  `json:"depth"`
+	AlarmThreshold int    `json:"alarm_threshold"`
+}
+
+// WebhookCircuitResponse DTO состояния цепи адреса доставки вебхуков
+// @Description DTO состояния цепи адреса доставки вебхуков
+type WebhookCircuitResponse struct {
+	URL                 string     `json:"url"`
+	State               string     `json:"state" example:"open"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	OpenedAt            *time.Time `json:"opened_at,omitempty"`
+	// Destination - хеш адреса, которым он помечен в метрике geo_webhook_circuit_state
+	Destination string `json:"destination" example:"9f86d081884c7d65"`
+}
+
+// WebhookCircuitsResponse DTO для ответа с состояниями цепей адресов доставки вебхуков
+// @Description DTO для ответа с состояниями цепей адресов доставки вебхуков
+type WebhookCircuitsResponse struct {
+	Circuits         []WebhookCircuitResponse `json:"circuits"`
+	DeadLetterDepth  int64                    `json:"dead_letter_depth"`
+	FailureThreshold int                      `json:"failure_threshold"`
+}
+
+// CategoryCapabilityResponse DTO ограничений радиуса категории опасности (0 - ограничение не задано)
+// @Description DTO ограничений радиуса категории опасности
+type CategoryCapabilityResponse struct {
+	Name                string `json:"name" example:"fire"`
+	MinRadiusMeters     int    `json:"min_radius_meters"`
+	MaxRadiusMeters     int    `json:"max_radius_meters"`
+	DefaultRadiusMeters int    `json:"default_radius_meters"`
+}
+
+// CapabilitiesResponse DTO для ответа с возможностями и ограничениями сервера
+// @Description DTO для ответа с возможностями и ограничениями сервера
+type CapabilitiesResponse struct {
+	MaxPageSize          int                          `json:"max_page_size" example:"100"`
+	MaxBulkDeactivateIDs int                          `json:"max_bulk_deactivate_ids" example:"1000"`
+	MaxMergeIDs          int                          `json:"max_merge_ids" example:"50"`
+	MaxMediaURLs         int                          `json:"max_media_urls" example:"10"`
+	Categories           []CategoryCapabilityResponse `json:"categories"`
+	Severities           []string                     `json:"severities"`
+	Statuses             []string                     `json:"statuses"`
+	Sources              []string                     `json:"sources"`
+	Features             map[string]bool              `json:"features"`
+	// UserCheckQuotaPerMinute - лимит проверок местоположения на пользователя в минуту; 0 - без лимита
+	UserCheckQuotaPerMinute int `json:"user_check_quota_per_minute"`
+	// PublicFeedRateLimitPerMinute - лимит запросов к открытой ленте с одного IP в минуту; 0 - без лимита
+	PublicFeedRateLimitPerMinute int    `json:"public_feed_rate_limit_per_minute"`
+	WebhookSchemaVersion         string `json:"webhook_schema_version" example:"1.5"`
+}
+
+// CacheInvalidateResponse DTO для ответа на принудительный сброс кэшей
+// @Description DTO для ответа на принудительный сброс кэшей
+type CacheInvalidateResponse struct {
+	KeysRemoved int64 `json:"keys_removed"`
+}
+
+// ErrorResponse DTO для ошибки с машиночитаемым кодом
+// @Description DTO для ошибки с машиночитаемым кодом
+type ErrorResponse struct {
+	Error string `json:"error"`
+	Code  string `json:"code"`
+}
+
 // StatsResponse DTO для ответа со статистикой
 // @Description DTO для ответа со статистикой
 type StatsResponse struct {
 	UserCount int `json:"user_count"`
 }
+
+// APIKeyRequest DTO для выпуска API-ключа. Если key не указан, ключ генерируется сервисом.
+// @Description DTO для выпуска API-ключа
+type APIKeyRequest struct {
+	Key string `json:"key,omitempty" validate:"omitempty,min=16,max=256"`
+}
+
+// APIKeyRevokeRequest DTO для отзыва API-ключа
+// @Description DTO для отзыва API-ключа
+type APIKeyRevokeRequest struct {
+	Key string `json:"key" validate:"required"`
+}
+
+// APIKeyResponse DTO для ответа с выпущенным API-ключом.
+// Ключ возвращается только при выпуске, label используется в логах.
+// @Description DTO для ответа с выпущенным API-ключом
+type APIKeyResponse struct {
+	Key   string `json:"key"`
+	Label string `json:"label"`
+}
+
+// APIKeyUsageResponse DTO для количества запросов с API-ключом
+// @Description Количество запросов с API-ключом за окно учета
+type APIKeyUsageResponse struct {
+	Label    string `json:"label"`
+	Requests int64  `json:"requests"`
+}
+
+// ImportURLRequest DTO для импорта инцидентов из GeoJSON-ленты по URL
+// @Description DTO для импорта инцидентов из GeoJSON-ленты по URL
+type ImportURLRequest struct {
+	URL string `json:"url" validate:"required,url,max=2048" example:"https://feeds.example.org/incidents.geojson"`
+}
+
+// ImportFeatureErrorResponse DTO для ошибки импорта одного объекта ленты
+// @Description Ошибка импорта одного объекта ленты
+type ImportFeatureErrorResponse struct {
+	Index      int    `json:"index"`
+	ExternalID string `json:"external_id,omitempty"`
+	Error      string `json:"error"`
+}
+
+// ImportJobResponse DTO для состояния задачи импорта
+// @Description Состояние фонового импорта инцидентов: running, completed или failed
+type ImportJobResponse struct {
+	ID         uuid.UUID                     `json:"id"`
+	URL        string                        `json:"url"`
+	Status     string                        `json:"status" example:"running"`
+	Features   int                           `json:"features"`
+	Created    int                           `json:"created"`
+	Updated    int                           `json:"updated"`
+	Unchanged  int                           `json:"unchanged"`
+	Errors     []*ImportFeatureErrorResponse `json:"errors"`
+	Error      string                        `json:"error,omitempty"`
+	StartedAt  time.Time                     `json:"started_at"`
+	FinishedAt *time.Time                    `json:"finished_at,omitempty"`
+}
+
+// ImportFeedSyncResponse DTO для итога синхронизации ленты
+// @Description Итог последней синхронизации ленты: completed или failed
+type ImportFeedSyncResponse struct {
+	Status      string    `json:"status" example:"completed"`
+	Error       string    `json:"error,omitempty"`
+	Created     int       `json:"created"`
+	Updated     int       `json:"updated"`
+	Deactivated int       `json:"deactivated"`
+	SyncedAt    time.Time `json:"synced_at"`
+}
+
+// ImportFeedResponse DTO для зарегистрированной GeoJSON-ленты
+// @Description Зарегистрированная GeoJSON-лента, периодически синхронизируемая с инцидентами
+type ImportFeedResponse struct {
+	ID        uuid.UUID               `json:"id"`
+	URL       string                  `json:"url"`
+	CreatedBy string                  `json:"created_by"`
+	CreatedAt time.Time               `json:"created_at"`
+	Incidents int                     `json:"incidents"`
+	LastSync  *ImportFeedSyncResponse `json:"last_sync,omitempty"`
+}
+
+// AttachmentUploadRequest DTO для запроса URL загрузки вложения
+// @Description DTO для запроса URL загрузки вложения инцидента
+type AttachmentUploadRequest struct {
+	Filename    string `json:"filename" validate:"required,max=255" example:"photo.jpg"`
+	ContentType string `json:"content_type" validate:"required,max=255" example:"image/jpeg"`
+}
+
+// AttachmentUploadResponse DTO для presigned URL загрузки вложения
+// @Description Presigned URL для загрузки файла вложения напрямую в объектное хранилище.
+// @Description Файл загружается запросом method на upload_url с заголовками headers.
+type AttachmentUploadResponse struct {
+	ID        uuid.UUID         `json:"id"`
+	ObjectKey string            `json:"object_key" example:"incidents/3f1c.../9a2e....jpg"`
+	UploadURL string            `json:"upload_url"`
+	Method    string            `json:"method" example:"PUT"`
+	Headers   map[string]string `json:"headers"`
+	ExpiresAt time.Time         `json:"expires_at"`
+}
+
+// AttachmentResponse DTO для вложения инцидента
+// @Description Вложение инцидента с presigned URL для скачивания
+type AttachmentResponse struct {
+	ID          uuid.UUID `json:"id"`
+	Filename    string    `json:"filename"`
+	ContentType string    `json:"content_type"`
+	ObjectKey   string    `json:"object_key"`
+	CreatedAt   time.Time `json:"created_at"`
+	DownloadURL string    `json:"download_url"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// WatchIncidentRequest DTO для добавления наблюдателя инцидента
+// @Description Адрес, на который доставляются вебхуки о каждом изменении инцидента
+type WatchIncidentRequest struct {
+	URL string `json:"url" validate:"required,url,max=2048" example:"https://hooks.example.org/incident-watch"`
+	// Secret - секрет подписи HMAC; если не задан, вебхуки наблюдателя не подписываются
+	Secret string `json:"secret,omitempty" validate:"max=255"`
+}
+
+// WatcherResponse DTO для наблюдателя инцидента (секрет не возвращается)
+// @Description Наблюдатель инцидента
+type WatcherResponse struct {
+	ID         uuid.UUID `json:"id"`
+	IncidentID uuid.UUID `json:"incident_id"`
+	URL        string    `json:"url"`
+	CreatedBy  string    `json:"created_by,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// IncidentViewsResponse DTO для числа просмотров инцидента
+// @Description Сколько раз инцидент запрашивали через GET /incidents/{id}
+type IncidentViewsResponse struct {
+	IncidentID uuid.UUID `json:"incident_id"`
+	Views      int64     `json:"views"`
+}
+
+// LocationCheckReevaluationResponse DTO итога повторной оценки проверок местоположения
+// @Description Проверки, признанные безопасными, в которых пользователь был в зоне активного инцидента
+type LocationCheckReevaluationResponse struct {
+	From    time.Time `json:"from"`
+	To      time.Time `json:"to"`
+	Checked int       `json:"checked"`
+	// Discrepancies - проверки с is_dangerous = false; incident_ids - инциденты, найденные при повторной оценке
+	Discrepancies []*LocationCheckResponse `json:"discrepancies"`
+	// NextAfterID - значение after_id для следующей страницы; отсутствует на последней странице
+	NextAfterID       *int64 `json:"next_after_id,omitempty"`
+	WebhooksPublished int    `json:"webhooks_published"`
+}
@@ -0,0 +1,53 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/shenikar/geo_broadcasting_system/internal/service"
+)
+
+// @Summary Escalate an incident
+// @Description Raise the severity of an active incident one level (minor → moderate → major → critical),
+// @Description record a new version and emit an incident_escalated webhook. Requires API key.
+// @Tags Incidents
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Incident ID"
+// @Success 200 {object} IncidentResponse
+// @Failure 400 {object} map[string]string "Invalid incident ID"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Incident not found or feature disabled (FEATURE_DISABLED)"
+// @Failure 409 {object} map[string]string "Incident is not active or already critical"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} ErrorResponse "Storage unavailable, retry after Retry-After seconds"
+// @Router /incidents/{id}/escalate [post]
+func (h *Handler) escalateIncident(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid incident ID"})
+		return
+	}
+	log := h.logger.WithField("method", "escalateIncident").WithField("id", id)
+
+	incident, err := h.incidentService.EscalateIncident(c.Request.Context(), id, c.GetString(apiKeyLabelContextKey))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrIncidentNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "incident not found"})
+		case errors.Is(err, service.ErrStatusConflict):
+			c.JSON(http.StatusConflict, gin.H{"error": "incident is not active"})
+		case errors.Is(err, service.ErrSeverityConflict):
+			c.JSON(http.StatusConflict, gin.H{"error": "incident severity cannot be raised"})
+		default:
+			log.WithError(err).Error("Failed to escalate incident in service")
+			respondServiceError(c, err, "internal server error")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, ModelToIncidentResponse(incident))
+}
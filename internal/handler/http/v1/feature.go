@@ -0,0 +1,25 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// codeFeatureDisabled - код ошибки для маршрутов отключенной функции
+const codeFeatureDisabled = "FEATURE_DISABLED"
+
+// featureGate отвечает 404 с кодом FEATURE_DISABLED, если функция name отключена флагом.
+// Флаг проверяется на каждом запросе, поэтому изменение в Redis применяется без перезапуска.
+func (h *Handler) featureGate(name string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !h.incidentService.FeatureEnabled(c.Request.Context(), name) {
+			c.AbortWithStatusJSON(http.StatusNotFound, ErrorResponse{
+				Error: "feature is disabled",
+				Code:  codeFeatureDisabled,
+			})
+			return
+		}
+		c.Next()
+	}
+}
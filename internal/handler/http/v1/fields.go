@@ -0,0 +1,105 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// incidentResponseFields - имена полей IncidentResponse в JSON, допустимые в параметре fields
+var incidentResponseFields = jsonFieldNames(reflect.TypeOf(IncidentResponse{}))
+
+// jsonFieldNames возвращает имена полей структуры в JSON по тегам json
+func jsonFieldNames(t reflect.Type) map[string]struct{} {
+	names := make(map[string]struct{}, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			names[name] = struct{}{}
+		}
+	}
+	return names
+}
+
+// parseIncidentFields разбирает параметр fields (имена полей через запятую).
+// Если параметр не передан, возвращается nil - ответ не сокращается. Поле id включается всегда.
+func parseIncidentFields(c *gin.Context) (map[string]struct{}, error) {
+	value := c.Query("fields")
+	if value == "" {
+		return nil, nil
+	}
+
+	fields := map[string]struct{}{"id": {}}
+	var unknown []string
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if _, ok := incidentResponseFields[name]; !ok {
+			unknown = append(unknown, name)
+			continue
+		}
+		fields[name] = struct{}{}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("unknown fields: %s", strings.Join(unknown, ", "))
+	}
+	return fields, nil
+}
+
+// projectFields оставляет в JSON-представлении DTO только поля из fields.
+// Поля с omitempty и пустым значением в ответ не попадают, даже если запрошены.
+func projectFields(dto any, fields map[string]struct{}) (map[string]json.RawMessage, error) {
+	raw, err := json.Marshal(dto)
+	if err != nil {
+		return nil, err
+	}
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &all); err != nil {
+		return nil, err
+	}
+	for name := range all {
+		if _, ok := fields[name]; !ok {
+			delete(all, name)
+		}
+	}
+	return all, nil
+}
+
+// respondIncidents отправляет список инцидентов, сокращенный до полей fields (nil - полностью)
+func respondIncidents(c *gin.Context, status int, responses []*IncidentResponse, fields map[string]struct{}) {
+	if fields == nil {
+		c.JSON(status, responses)
+		return
+	}
+	projected := make([]map[string]json.RawMessage, len(responses))
+	for i, response := range responses {
+		item, err := projectFields(response, fields)
+		if err != nil {
+			respondServiceError(c, err, "internal server error")
+			return
+		}
+		projected[i] = item
+	}
+	c.JSON(status, projected)
+}
+
+// respondIncident отправляет инцидент, сокращенный до полей fields (nil - полностью)
+func respondIncident(c *gin.Context, status int, response *IncidentResponse, fields map[string]struct{}) {
+	if fields == nil {
+		c.JSON(status, response)
+		return
+	}
+	projected, err := projectFields(response, fields)
+	if err != nil {
+		respondServiceError(c, err, "internal server error")
+		return
+	}
+	c.JSON(status, projected)
+}
@@ -0,0 +1,104 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shenikar/geo_broadcasting_system/internal/service"
+)
+
+// @Summary Validate an incident geofence without saving
+// @Description Run the same validation as incident creation plus a PostGIS geometry validity check (ST_IsValid)
+// @Description and return the zone area and bounds. Nothing is persisted. Requires API key.
+// @Tags Incidents
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param incident body CreateIncidentRequest true "Incident creation request to validate"
+// @Success 200 {object} GeofenceValidationResponse "Validation result; valid is false when errors are found"
+// @Failure 400 {object} map[string]string "Malformed request body"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 422 {object} ValidationErrorResponse "Validation error, including a missing radius for a category without a default radius"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} ErrorResponse "Storage unavailable, retry after Retry-After seconds"
+// @Router /incidents/validate [post]
+func (h *Handler) validateIncident(c *gin.Context) {
+	var input CreateIncidentRequest
+	log := h.logger.WithField("method", "validateIncident")
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.WithError(err).Warn("Failed to bind JSON")
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	if err := h.validate.Struct(input); err != nil {
+		log.WithError(err).Warn("Validation failed")
+		h.respondValidationError(c, input, err)
+		return
+	}
+
+	result, err := h.incidentService.ValidateIncident(c.Request.Context(), DTOToIncidentModel(input))
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidIncident) {
+			log.WithError(err).Warn("Incident rejected by service")
+			h.respondValidationError(c, input, err)
+			return
+		}
+		log.WithError(err).Error("Failed to validate incident in service")
+		respondServiceError(c, err, "internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, ModelToGeofenceValidationResponse(result))
+}
+
+// @Summary Preview impact of a hypothetical incident
+// @Description Estimate how many users a zone would affect before creating the incident: the number of distinct users
+// @Description whose location checks within the stats window fall inside the circle. Nothing is stored. Requires API key.
+// @Tags Incidents
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param zone body PreviewImpactRequest true "Hypothetical incident zone"
+// @Success 200 {object} PreviewImpactResponse
+// @Failure 400 {object} map[string]string "Malformed request body"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} ErrorResponse "Feature disabled (FEATURE_DISABLED)"
+// @Failure 422 {object} ValidationErrorResponse "Validation error or coordinates outside of the service area"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} ErrorResponse "Storage unavailable, retry after Retry-After seconds"
+// @Router /incidents/preview-impact [post]
+func (h *Handler) previewIncidentImpact(c *gin.Context) {
+	var input PreviewImpactRequest
+	log := h.logger.WithField("method", "previewIncidentImpact")
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.WithError(err).Warn("Failed to bind JSON")
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	if err := h.validate.Struct(input); err != nil {
+		log.WithError(err).Warn("Validation failed")
+		h.respondValidationError(c, input, err)
+		return
+	}
+
+	userCount, err := h.incidentService.PreviewIncidentImpact(c.Request.Context(), input.Latitude, input.Longitude, input.RadiusMeters)
+	if err != nil {
+		if errors.Is(err, service.ErrCoordinatesNotAllowed) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+		log.WithError(err).Error("Failed to preview incident impact in service")
+		respondServiceError(c, err, "internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, PreviewImpactResponse{
+		UserCount:     userCount,
+		WindowMinutes: h.cfg.StatsTimeWindowMinutes,
+	})
+}
@@ -1,13 +1,18 @@
 package v1
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 	"github.com/shenikar/geo_broadcasting_system/internal/config"
+	"github.com/shenikar/geo_broadcasting_system/internal/models"
 	"github.com/shenikar/geo_broadcasting_system/internal/service"
 	"github.com/sirupsen/logrus"
 )
@@ -17,6 +22,8 @@ type Handler struct {
 	logger          *logrus.Logger
 	validate        *validator.Validate
 	cfg             *config.Config
+	// pollers - число запросов long polling, ожидающих изменений
+	pollers atomic.Int64
 }
 
 func NewHandler(incidentService service.IncidentService, logger *logrus.Logger, cfg *config.Config) *Handler {
@@ -36,9 +43,12 @@ func NewHandler(incidentService service.IncidentService, logger *logrus.Logger,
 // @Security ApiKeyAuth
 // @Param incident body CreateIncidentRequest true "Incident creation request"
 // @Success 201 {object} IncidentResponse
-// @Failure 400 {object} map[string]string "Invalid request body or validation error"
+// @Failure 400 {object} map[string]string "Malformed request body"
 // @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 409 {object} map[string]string "Incident with this external ID already exists"
+// @Failure 422 {object} ValidationErrorResponse "Validation error or coordinates outside of the service area"
 // @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} ErrorResponse "Storage unavailable, retry after Retry-After seconds"
 // @Router /incidents [post]
 func (h *Handler) createIncident(c *gin.Context) {
 	var input CreateIncidentRequest
@@ -52,14 +62,28 @@ func (h *Handler) createIncident(c *gin.Context) {
 
 	if err := h.validate.Struct(input); err != nil {
 		log.WithError(err).Warn("Validation failed")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		h.respondValidationError(c, input, err)
 		return
 	}
 
 	model := DTOToIncidentModel(input)
+	model.CreatedBy = c.GetString(apiKeyLabelContextKey)
 	if err := h.incidentService.CreateIncident(c.Request.Context(), model); err != nil {
+		if errors.Is(err, service.ErrCoordinatesNotAllowed) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrInvalidIncident) {
+			log.WithError(err).Warn("Incident rejected by service")
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrExternalIDExists) {
+			c.JSON(http.StatusConflict, gin.H{"error": "incident with this external id already exists"})
+			return
+		}
 		log.WithError(err).Error("Failed to create incident in service")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		respondServiceError(c, err, "internal server error")
 		return
 	}
 	c.JSON(http.StatusCreated, ModelToIncidentResponse(model))
@@ -73,23 +97,136 @@ func (h *Handler) createIncident(c *gin.Context) {
 // @Security ApiKeyAuth
 // @Param page query int false "Page number" default(1)
 // @Param pageSize query int false "Number of items per page" default(10)
+// @Param near_lat query number false "Latitude of the point to search near (requires near_lon and within_meters)"
+// @Param near_lon query number false "Longitude of the point to search near (requires near_lat and within_meters)"
+// @Param within_meters query number false "Maximum distance from the point in meters; results are ordered by distance"
+// @Param min_confidence query int false "Exclude incidents with lower confidence (0-100); unrated incidents are always included"
+// @Param sort_by query string false "Sort field; overrides ordering by distance when near_* is set" Enums(created_at, updated_at, name, severity) default(created_at)
+// @Param order query string false "Sort direction" Enums(asc, desc) default(desc)
+// @Param fields query string false "Comma-separated response fields to return (id is always included)"
+// @Param snapshot_time query string false "RFC 3339 timestamp; only incidents created at or before it are listed. Pass X-Snapshot-Time from the first page to keep later pages stable"
+// @Param created_by query string false "Only incidents created by the API key with this label (see created_by in the response)"
+// @Param If-Modified-Since header string false "Return 304 if no incident changed since this HTTP date"
 // @Success 200 {array} IncidentResponse
+// @Header 200 {string} Last-Modified "Time of the latest incident change"
+// @Header 200 {string} X-Snapshot-Time "Snapshot the page was read at, to pass as snapshot_time for the next pages"
+// @Success 304 "Not modified since If-Modified-Since"
+// @Failure 400 {object} map[string]string "Invalid filter, sort or fields parameters"
 // @Failure 401 {object} map[string]string "Unauthorized"
 // @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} ErrorResponse "Storage unavailable, retry after Retry-After seconds"
 // @Router /incidents [get]
 func (h *Handler) listIncidents(c *gin.Context) {
 	log := h.logger.WithField("method", "listIncidents")
+	h.respondIncidentList(c, log, models.IncidentFilter{CreatedBy: strings.TrimSpace(c.Query("created_by"))})
+}
+
+// @Summary List incidents created by the caller
+// @Description Get a paginated list of incidents created with the API key of the request (incidents created before creator tracking are not included). Accepts the same parameters as GET /incidents except created_by. Requires API key.
+// @Tags Incidents
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param page query int false "Page number" default(1)
+// @Param pageSize query int false "Number of items per page" default(10)
+// @Param near_lat query number false "Latitude of the point to search near (requires near_lon and within_meters)"
+// @Param near_lon query number false "Longitude of the point to search near (requires near_lat and within_meters)"
+// @Param within_meters query number false "Maximum distance from the point in meters; results are ordered by distance"
+// @Param min_confidence query int false "Exclude incidents with lower confidence (0-100); unrated incidents are always included"
+// @Param sort_by query string false "Sort field; overrides ordering by distance when near_* is set" Enums(created_at, updated_at, name, severity) default(created_at)
+// @Param order query string false "Sort direction" Enums(asc, desc) default(desc)
+// @Param fields query string false "Comma-separated response fields to return (id is always included)"
+// @Param snapshot_time query string false "RFC 3339 timestamp; only incidents created at or before it are listed"
+// @Param If-Modified-Since header string false "Return 304 if no incident changed since this HTTP date"
+// @Success 200 {array} IncidentResponse
+// @Header 200 {string} Last-Modified "Time of the latest incident change"
+// @Header 200 {string} X-Snapshot-Time "Snapshot the page was read at, to pass as snapshot_time for the next pages"
+// @Success 304 "Not modified since If-Modified-Since"
+// @Failure 400 {object} map[string]string "Invalid filter, sort or fields parameters"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} ErrorResponse "Storage unavailable, retry after Retry-After seconds"
+// @Router /incidents/mine [get]
+func (h *Handler) listMyIncidents(c *gin.Context) {
+	log := h.logger.WithField("method", "listMyIncidents")
+	h.respondIncidentList(c, log, models.IncidentFilter{CreatedBy: c.GetString(apiKeyLabelContextKey)})
+}
+
+// respondIncidentList дополняет filter общими параметрами списка инцидентов (пагинация, близость,
+// сортировка, достоверность, снимок) и отвечает страницей списка
+func (h *Handler) respondIncidentList(c *gin.Context, log *logrus.Entry, filter models.IncidentFilter) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "10"))
 
-	incidents, err := h.incidentService.ListIncidents(c.Request.Context(), page, pageSize)
+	near, err := parseProximityFilter(c)
+	if err != nil {
+		log.WithError(err).Warn("Invalid proximity filter")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	filter.Near = near
+
+	sort, err := parseIncidentSort(c)
+	if err != nil {
+		log.WithError(err).Warn("Invalid sort parameters")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	filter.Sort = sort
+
+	fields, err := parseIncidentFields(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if value, ok := c.GetQuery("min_confidence"); ok {
+		minConfidence, err := strconv.Atoi(value)
+		if err != nil || minConfidence < 0 || minConfidence > 100 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "min_confidence must be an integer between 0 and 100"})
+			return
+		}
+		filter.MinConfidence = minConfidence
+	}
+
+	if value, ok := c.GetQuery("snapshot_time"); ok {
+		snapshotTime, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "snapshot_time must be an RFC 3339 timestamp"})
+			return
+		}
+		filter.CreatedBefore = &snapshotTime
+	}
+
+	// Условный запрос - оптимизация: при ошибке список отдается целиком
+	lastModified, err := h.incidentService.IncidentsLastModified(c.Request.Context())
+	if err != nil {
+		log.WithError(err).Warn("Failed to get incidents last modification time")
+	} else if respondNotModified(c, lastModified) {
+		return
+	}
+
+	// Без snapshot_time снимком считается текущее время базы, и первая страница ограничивается им так же,
+	// как следующие: клиент передает его со следующими страницами
+	if filter.CreatedBefore == nil {
+		snapshotTime, err := h.incidentService.IncidentsSnapshotTime(c.Request.Context())
+		if err != nil {
+			log.WithError(err).Error("Failed to get snapshot time from service")
+			respondServiceError(c, err, "internal server error")
+			return
+		}
+		filter.CreatedBefore = &snapshotTime
+	}
+
+	incidents, err := h.incidentService.ListIncidents(c.Request.Context(), page, pageSize, filter)
 	if err != nil {
 		log.WithError(err).Error("Failed to list incident from service")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		respondServiceError(c, err, "internal server error")
 		return
 	}
 
-	c.JSON(http.StatusOK, ModelsToIncidentResponses(incidents))
+	c.Header(snapshotTimeHeader, filter.CreatedBefore.UTC().Format(time.RFC3339Nano))
+	respondIncidents(c, http.StatusOK, ModelsToIncidentResponses(incidents), fields)
 }
 
 // @Summary Get incident by ID
@@ -99,11 +236,13 @@ func (h *Handler) listIncidents(c *gin.Context) {
 // @Produce json
 // @Security ApiKeyAuth
 // @Param id path string true "Incident ID"
+// @Param fields query string false "Comma-separated response fields to return (id is always included)"
 // @Success 200 {object} IncidentResponse
-// @Failure 400 {object} map[string]string "Invalid incident ID"
+// @Failure 400 {object} map[string]string "Invalid incident ID or fields"
 // @Failure 401 {object} map[string]string "Unauthorized"
 // @Failure 404 {object} map[string]string "Incident not found"
 // @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} ErrorResponse "Storage unavailable, retry after Retry-After seconds"
 // @Router /incidents/{id} [get]
 func (h *Handler) getIncident(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
@@ -112,18 +251,70 @@ func (h *Handler) getIncident(c *gin.Context) {
 		return
 	}
 	log := h.logger.WithField("method", "getIncident").WithField("id", id)
+	fields, err := parseIncidentFields(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	incident, err := h.incidentService.GetIncident(c.Request.Context(), id)
 	if err != nil {
+		if isDependencyUnavailable(err) {
+			log.WithError(err).Error("Failed to get incident from service")
+			respondUnavailable(c)
+			return
+		}
 		log.WithError(err).Warn("Failed to get incident from service")
 		c.JSON(http.StatusNotFound, gin.H{"error": "incident not found"})
 		return
 	}
-	c.JSON(http.StatusOK, ModelToIncidentResponse(incident))
+	h.incidentService.RecordIncidentView(incident.ID)
+	respondIncident(c, http.StatusOK, ModelToIncidentResponse(incident), fields)
+}
+
+// @Summary Get incident by external ID
+// @Description Get a single incident by its identifier in an external system (external_id). Requires API key.
+// @Tags Incidents
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param external_id path string true "External incident ID"
+// @Param fields query string false "Comma-separated response fields to return (id is always included)"
+// @Success 200 {object} IncidentResponse
+// @Failure 400 {object} map[string]string "Unknown fields"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Incident not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} ErrorResponse "Storage unavailable, retry after Retry-After seconds"
+// @Router /incidents/by-external/{external_id} [get]
+func (h *Handler) getIncidentByExternalID(c *gin.Context) {
+	externalID := c.Param("external_id")
+	log := h.logger.WithField("method", "getIncidentByExternalID").WithField("external_id", externalID)
+	fields, err := parseIncidentFields(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	incident, err := h.incidentService.GetIncidentByExternalID(c.Request.Context(), externalID)
+	if err != nil {
+		if errors.Is(err, service.ErrIncidentNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "incident not found"})
+			return
+		}
+		log.WithError(err).Error("Failed to get incident by external id from service")
+		respondServiceError(c, err, "internal server error")
+		return
+	}
+	respondIncident(c, http.StatusOK, ModelToIncidentResponse(incident), fields)
 }
 
 // @Summary Update an existing incident
-// @Description Update an existing incident by ID. Requires API key.
+// @Description Replace an existing incident by ID. The update is a full replace: omitted fields are reset,
+// @Description so "silent" and "public" become false unless sent again; only source, severity and external_id
+// @Description keep their current values when omitted. Status changes follow the transition rules:
+// @Description active -> inactive|resolved, inactive -> active|resolved, draft -> inactive, rejected -> draft.
+// @Description Drafts become active only through approval and resolved incidents cannot be reopened. Requires API key.
 // @Tags Incidents
 // @Accept json
 // @Produce json
@@ -131,9 +322,12 @@ func (h *Handler) getIncident(c *gin.Context) {
 // @Param id path string true "Incident ID"
 // @Param incident body UpdateIncidentRequest true "Incident update request"
 // @Success 200 "OK"
-// @Failure 400 {object} map[string]string "Invalid incident ID or request body"
+// @Failure 400 {object} map[string]string "Invalid incident ID or malformed request body"
 // @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 409 {object} map[string]string "Status transition is not allowed or external ID is taken"
+// @Failure 422 {object} ValidationErrorResponse "Validation error or coordinates outside of the service area"
 // @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} ErrorResponse "Storage unavailable, retry after Retry-After seconds"
 // @Router /incidents/{id} [put]
 func (h *Handler) updateIncident(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
@@ -152,7 +346,7 @@ func (h *Handler) updateIncident(c *gin.Context) {
 
 	if err := h.validate.Struct(input); err != nil {
 		log.WithError(err).Warn("Validation failed")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		h.respondValidationError(c, input, err)
 		return
 	}
 
@@ -160,24 +354,49 @@ func (h *Handler) updateIncident(c *gin.Context) {
 	model.ID = id
 
 	if err := h.incidentService.UpdateIncident(c.Request.Context(), model); err != nil {
+		if errors.Is(err, service.ErrCoordinatesNotAllowed) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrInvalidIncident) {
+			log.WithError(err).Warn("Incident rejected by service")
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrStatusConflict) {
+			log.WithError(err).Warn("Incident status transition rejected")
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrExternalIDExists) {
+			c.JSON(http.StatusConflict, gin.H{"error": "incident with this external id already exists"})
+			return
+		}
 		log.WithError(err).Error("Failed to update incident in service")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update incident in service"})
+		respondServiceError(c, err, "failed to update incident in service")
 		return
 	}
 	c.Status(http.StatusOK)
 }
 
 // @Summary Deactivate an incident
-// @Description Deactivate an incident by its ID. This marks the incident as inactive. Requires API key.
+// @Description Deactivate an incident by its ID. Without a body the incident is marked as inactive (withdrawn manually);
+// @Description with "resolved": true it is marked as resolved (ended naturally) with an optional reason.
+// @Description The status transition rules of PUT /incidents/{id} apply: resolved incidents cannot be deactivated
+// @Description and drafts can only be withdrawn (inactive). Requires API key.
 // @Tags Incidents
 // @Accept json
 // @Produce json
 // @Security ApiKeyAuth
 // @Param id path string true "Incident ID"
+// @Param resolution body DeactivateIncidentRequest false "Resolution details"
 // @Success 204 "No Content"
-// @Failure 400 {object} map[string]string "Invalid incident ID"
+// @Failure 400 {object} map[string]string "Invalid incident ID or malformed request body"
 // @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 409 {object} map[string]string "Status transition is not allowed"
+// @Failure 422 {object} ValidationErrorResponse "Validation error"
 // @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} ErrorResponse "Storage unavailable, retry after Retry-After seconds"
 // @Router /incidents/{id} [delete]
 func (h *Handler) deleteIncident(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
@@ -187,9 +406,30 @@ func (h *Handler) deleteIncident(c *gin.Context) {
 	}
 	log := h.logger.WithField("method", "deleteIncident").WithField("id", id)
 
-	if err := h.incidentService.DeactivateIncident(c.Request.Context(), id); err != nil {
+	// Пустое тело допустимо: инцидент снимается вручную
+	var input DeactivateIncidentRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			log.WithError(err).Warn("Failed to bind JSON")
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+			return
+		}
+	}
+	if err := h.validate.Struct(input); err != nil {
+		log.WithError(err).Warn("Validation failed")
+		h.respondValidationError(c, input, err)
+		return
+	}
+
+	resolution := models.Resolution{Resolved: input.Resolved, Reason: input.Reason}
+	if err := h.incidentService.DeactivateIncident(c.Request.Context(), id, resolution); err != nil {
+		if errors.Is(err, service.ErrStatusConflict) {
+			log.WithError(err).Warn("Incident status transition rejected")
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		log.WithError(err).Error("Failed to deactivate incident in service")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to deactivate incident"})
+		respondServiceError(c, err, "failed to deactivate incident")
 		return
 	}
 
@@ -197,16 +437,23 @@ func (h *Handler) deleteIncident(c *gin.Context) {
 }
 
 // @Summary Check location for incidents
-// @Description Check if there are any active incidents at a given location for a user. Requires API key.
+// @Description Check if there are any active incidents at a given location for a user. The API key is optional: anonymous checks are accepted, checks with a valid key are stored as authenticated.
 // @Tags Location
 // @Accept json
 // @Produce json
 // @Security ApiKeyAuth
 // @Param location body LocationCheckRequest true "Location check request"
-// @Success 200 {array} IncidentResponse
-// @Failure 400 {object} map[string]string "Invalid request body or validation error"
-// @Failure 401 {object} map[string]string "Unauthorized"
+// @Produce application/vnd.geo.location-check+json
+// @Success 200 {array} IncidentResponse "Matched incidents (default, LOCATION_CHECK_RESPONSE=array)"
+// @Success 200 {object} LocationCheckResultResponse "With Accept: application/vnd.geo.location-check+json or LOCATION_CHECK_RESPONSE=object"
+// @Header 200 {boolean} X-Incidents-Truncated "Set to true when more incidents matched than MAX_MATCHED_INCIDENTS allows to return"
+// @Header 200 {integer} X-Matched-Incidents "Total number of matched incidents, set together with X-Incidents-Truncated"
+// @Failure 400 {object} map[string]string "Malformed request body"
+// @Failure 401 {object} map[string]string "Invalid API key"
+// @Failure 422 {object} ValidationErrorResponse "Validation error or coordinates outside of the service area"
+// @Failure 429 {object} ErrorResponse "User location check quota exceeded, retry after Retry-After seconds"
 // @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} ErrorResponse "Storage unavailable, retry after Retry-After seconds"
 // @Router /location/check [post]
 func (h *Handler) checkLocation(c *gin.Context) {
 	var input LocationCheckRequest
@@ -220,18 +467,91 @@ func (h *Handler) checkLocation(c *gin.Context) {
 
 	if err := h.validate.Struct(input); err != nil {
 		log.WithError(err).Warn("Validation failed")
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		h.respondValidationError(c, input, err)
 		return
 	}
 
-	incidents, err := h.incidentService.CheckLocation(c.Request.Context(), input.UserID, input.Latitude, input.Longitude)
+	incidents, matched, err := h.incidentService.CheckLocation(c.Request.Context(), input.UserID, isAuthenticated(c), input.Latitude, input.Longitude, input.BufferMeters, input.MinConfidence)
 	if err != nil {
+		if errors.Is(err, service.ErrCoordinatesNotAllowed) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+		var quotaErr *service.RateLimitError
+		if errors.As(err, &quotaErr) {
+			respondRateLimited(c, quotaErr, "location check quota exceeded", codeCheckQuotaExceeded)
+			return
+		}
 		log.WithError(err).Error("Failed to check location in service")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		respondServiceError(c, err, "internal server error")
 		return
 	}
 
-	c.JSON(http.StatusOK, ModelsToIncidentResponses(incidents))
+	responses := ModelsToIncidentResponses(incidents)
+	result := LocationCheckResultResponse{Dangerous: len(responses) > 0, Incidents: responses}
+	if matched > len(responses) {
+		result.Truncated, result.Matched = true, matched
+		c.Header(incidentsTruncatedHeader, "true")
+		c.Header(matchedIncidentsHeader, strconv.Itoa(matched))
+	}
+	if h.wantsLocationCheckObject(c) {
+		c.JSON(http.StatusOK, result)
+		return
+	}
+	c.JSON(http.StatusOK, responses)
+}
+
+const (
+	// incidentsTruncatedHeader - заголовок ответа проверки местоположения, сообщающий, что совпавших инцидентов
+	// больше, чем возвращено (MAX_MATCHED_INCIDENTS)
+	incidentsTruncatedHeader = "X-Incidents-Truncated"
+	// matchedIncidentsHeader - заголовок с числом всех совпавших инцидентов при усеченном ответе
+	matchedIncidentsHeader = "X-Matched-Incidents"
+)
+
+// locationCheckMediaType - тип ответа, которым клиент запрашивает результат проверки в форме объекта
+const locationCheckMediaType = "application/vnd.geo.location-check+json"
+
+// wantsLocationCheckObject сообщает, нужно ли вернуть результат проверки объектом LocationCheckResultResponse:
+// клиент передал locationCheckMediaType в Accept или LOCATION_CHECK_RESPONSE=object
+func (h *Handler) wantsLocationCheckObject(c *gin.Context) bool {
+	for _, accepted := range strings.Split(c.GetHeader("Accept"), ",") {
+		mediaType, _, _ := strings.Cut(accepted, ";")
+		if strings.EqualFold(strings.TrimSpace(mediaType), locationCheckMediaType) {
+			return true
+		}
+	}
+	return h.cfg.LocationCheckResponse == "object"
+}
+
+// @Summary Get user location check history
+// @Description Get a paginated list of a user's location checks (newest first) with the IDs of matched incidents. Requires API key.
+// @Tags Location
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param user_id path string true "User ID"
+// @Param page query int false "Page number" default(1)
+// @Param pageSize query int false "Number of items per page" default(10)
+// @Success 200 {array} LocationCheckResponse
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} ErrorResponse "Storage unavailable, retry after Retry-After seconds"
+// @Router /users/{user_id}/checks [get]
+func (h *Handler) getUserLocationHistory(c *gin.Context) {
+	userID := c.Param("user_id")
+	log := h.logger.WithField("method", "getUserLocationHistory").WithField("user_id", userID)
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "10"))
+
+	checks, err := h.incidentService.GetUserLocationHistory(c.Request.Context(), userID, page, pageSize)
+	if err != nil {
+		log.WithError(err).Error("Failed to get user location history from service")
+		respondServiceError(c, err, "internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, ModelsToLocationCheckResponses(checks))
 }
 
 // @Summary Get user statistics
@@ -243,6 +563,7 @@ func (h *Handler) checkLocation(c *gin.Context) {
 // @Success 200 {object} StatsResponse
 // @Failure 401 {object} map[string]string "Unauthorized"
 // @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} ErrorResponse "Storage unavailable, retry after Retry-After seconds"
 // @Router /stats [get]
 func (h *Handler) getStats(c *gin.Context) {
 	log := h.logger.WithField("method", "getStats")
@@ -250,13 +571,91 @@ func (h *Handler) getStats(c *gin.Context) {
 	userCount, err := h.incidentService.GetStats(c.Request.Context())
 	if err != nil {
 		log.WithError(err).Error("Failed to get stats from service")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		respondServiceError(c, err, "internal server error")
 		return
 	}
 
 	c.JSON(http.StatusOK, StatsResponse{UserCount: userCount})
 }
 
+// @Summary Get incident impact estimate
+// @Description Get the number of distinct users whose location checks matched the incident within the stats time window.
+// @Description Reflects only users who actively checked their location, not everyone physically inside the zone. Requires API key.
+// @Tags Incidents
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Incident ID"
+// @Success 200 {object} ImpactResponse
+// @Failure 400 {object} map[string]string "Invalid incident ID"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Incident not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} ErrorResponse "Storage unavailable, retry after Retry-After seconds"
+// @Router /incidents/{id}/impact [get]
+func (h *Handler) getIncidentImpact(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid incident ID"})
+		return
+	}
+	log := h.logger.WithField("method", "getIncidentImpact").WithField("id", id)
+
+	userCount, err := h.incidentService.GetIncidentImpact(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrIncidentNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "incident not found"})
+			return
+		}
+		log.WithError(err).Error("Failed to get incident impact from service")
+		respondServiceError(c, err, "internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, ImpactResponse{
+		IncidentID:    id,
+		UserCount:     userCount,
+		WindowMinutes: h.cfg.StatsTimeWindowMinutes,
+	})
+}
+
+// @Summary Get incidents overlapping an incident
+// @Description Get active incidents whose zones intersect the zone of the given incident (excluding the incident itself),
+// @Description ordered by overlap area, largest first. Requires API key.
+// @Tags Incidents
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Incident ID"
+// @Success 200 {array} OverlappingIncidentResponse
+// @Failure 400 {object} map[string]string "Invalid incident ID"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Incident not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} ErrorResponse "Storage unavailable, retry after Retry-After seconds"
+// @Router /incidents/{id}/overlapping [get]
+func (h *Handler) getOverlappingIncidents(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid incident ID"})
+		return
+	}
+	log := h.logger.WithField("method", "getOverlappingIncidents").WithField("id", id)
+
+	overlaps, err := h.incidentService.GetOverlappingIncidents(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrIncidentNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "incident not found"})
+			return
+		}
+		log.WithError(err).Error("Failed to get overlapping incidents from service")
+		respondServiceError(c, err, "internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, ModelsToOverlappingIncidentResponses(overlaps))
+}
+
 // @Summary Get application health status
 // @Description Get health status of the application
 // @Tags System
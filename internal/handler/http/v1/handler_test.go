@@ -7,19 +7,30 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/redis/go-redis/v9"
 	"github.com/shenikar/geo_broadcasting_system/internal/config"
 	"github.com/shenikar/geo_broadcasting_system/internal/models"
+	"github.com/shenikar/geo_broadcasting_system/internal/service"
 	"github.com/shenikar/geo_broadcasting_system/internal/service/mocks"
+	"github.com/shenikar/geo_broadcasting_system/internal/webhook"
+	"github.com/shenikar/geo_broadcasting_system/pkg/geo"
 	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/swaggo/swag"
 	"go.uber.org/mock/gomock"
 )
 
@@ -27,13 +38,19 @@ import (
 func newTestHandler(t *testing.T) (*Handler, *mocks.MockIncidentService, *gin.Engine) {
 	ctrl := gomock.NewController(t)
 	mockService := mocks.NewMockIncidentService(ctrl)
+	// Учет запросов по API-ключам сопровождает каждый аутентифицированный запрос
+	mockService.EXPECT().RecordAPIKeyUsage(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	// Все функции включены, если тест не отключает их явно
+	mockService.EXPECT().FeatureEnabled(gomock.Any(), gomock.Any()).Return(true).AnyTimes()
 
 	logger := logrus.New()
 	logger.SetOutput(&bytes.Buffer{}) // Отключаем вывод логов в тестах
 
 	cfg := &config.Config{
 		APIKeys:                []string{"test-api-key"},
+		AdminAPIKeys:           []string{"test-admin-key"},
 		StatsTimeWindowMinutes: 60,
+		DefaultLanguage:        "en",
 	}
 
 	handler := NewHandler(mockService, logger, cfg)
@@ -47,6 +64,13 @@ func newTestHandler(t *testing.T) (*Handler, *mocks.MockIncidentService, *gin.En
 	return handler, mockService, router
 }
 
+// expectSnapshotTime ожидает запрос момента снимка списка инцидентов и возвращает время базы, которое отдаст сервис
+func expectSnapshotTime(mockService *mocks.MockIncidentService) *time.Time {
+	snapshot := time.Date(2024, 5, 1, 12, 0, 0, 123_456_000, time.UTC)
+	mockService.EXPECT().IncidentsSnapshotTime(gomock.Any()).Return(snapshot, nil).Times(1)
+	return &snapshot
+}
+
 // makeRequest - вспомогательная функция для выполнения HTTP-запросов
 func makeRequest(router *gin.Engine, method, url string, body io.Reader, headers ...map[string]string) *httptest.ResponseRecorder {
 	req := httptest.NewRequest(method, url, body)
@@ -88,6 +112,8 @@ func TestCreateIncident_Success(t *testing.T) {
 	mockService.EXPECT().
 		CreateIncident(gomock.Any(), gomock.Any()).
 		DoAndReturn(func(_ context.Context, inc *models.Incident) error {
+			// Автором записывается метка ключа запроса
+			assert.Equal(t, apiKeyLabel("test-api-key"), inc.CreatedBy)
 			*inc = *expectedIncident // Обновляем переданный инцидент
 			return nil
 		}).Times(1)
@@ -129,10 +155,50 @@ func TestCreateIncident_ValidationError(t *testing.T) {
 	bodyBytes, _ := json.Marshal(reqBody)
 	w := makeRequest(router, "POST", "/api/v1/incidents", bytes.NewBuffer(bodyBytes), map[string]string{"X-API-Key": "test-api-key"})
 
-	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
 	assert.Contains(t, w.Body.String(), "Error:Field validation for 'Name' failed on the 'required' tag")
 }
 
+func TestCreateIncident_InvalidMediaURL(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	reqBody := CreateIncidentRequest{
+		Name:         "Test Incident",
+		Latitude:     10.0,
+		Longitude:    20.0,
+		RadiusMeters: 100,
+		MediaURLs:    []string{"https://example.com/photo.jpg", "not-a-url", ""},
+	}
+
+	mockService.EXPECT().CreateIncident(gomock.Any(), gomock.Any()).Times(0) // Сервис не должен вызываться
+
+	bodyBytes, _ := json.Marshal(reqBody)
+	w := makeRequest(router, "POST", "/api/v1/incidents", bytes.NewBuffer(bodyBytes), map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), "failed on the 'url' tag")
+}
+
+func TestCreateIncident_InvalidConfidence(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	confidence := 150
+	reqBody := CreateIncidentRequest{
+		Name:         "Test Incident",
+		Latitude:     10.0,
+		Longitude:    20.0,
+		RadiusMeters: 100,
+		Source:       models.SourceCrowdsourced,
+		Confidence:   &confidence,
+	}
+
+	mockService.EXPECT().CreateIncident(gomock.Any(), gomock.Any()).Times(0) // Сервис не должен вызываться
+
+	bodyBytes, _ := json.Marshal(reqBody)
+	w := makeRequest(router, "POST", "/api/v1/incidents", bytes.NewBuffer(bodyBytes), map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), "failed on the 'lte' tag")
+}
+
 func TestCreateIncident_ServiceError(t *testing.T) {
 	_, mockService, router := newTestHandler(t)
 	reqBody := CreateIncidentRequest{
@@ -169,6 +235,8 @@ func TestGetIncident_Success(t *testing.T) {
 	}
 
 	mockService.EXPECT().GetIncident(gomock.Any(), incidentID).Return(expectedIncident, nil).Times(1)
+	// Просмотр учитывается только для найденного инцидента
+	mockService.EXPECT().RecordIncidentView(incidentID).Times(1)
 
 	w := makeRequest(router, "GET", fmt.Sprintf("/api/v1/incidents/%s", incidentID.String()), nil, map[string]string{"X-API-Key": "test-api-key"})
 
@@ -178,6 +246,34 @@ func TestGetIncident_Success(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, incidentID, resp.ID)
 	assert.Equal(t, expectedIncident.Name, resp.Name)
+	assert.InDelta(t, 125663.7, resp.AreaSqMeters, 0.1)
+}
+
+func TestGetIncident_Fields(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	incidentID := uuid.New()
+	incident := &models.Incident{ID: incidentID, Name: "Пожар", Latitude: 30.0, Longitude: 40.0, RadiusMeters: 200, Status: "active"}
+
+	mockService.EXPECT().GetIncident(gomock.Any(), incidentID).Return(incident, nil).Times(1)
+	mockService.EXPECT().RecordIncidentView(incidentID).Times(1)
+
+	w := makeRequest(router, "GET", fmt.Sprintf("/api/v1/incidents/%s?fields=name,%%20status", incidentID), nil, map[string]string{"X-API-Key": "test-api-key"})
+
+	// id возвращается всегда, даже если не запрошен
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, fmt.Sprintf(`{"id":%q,"name":"Пожар","status":"active"}`, incidentID), w.Body.String())
+}
+
+func TestGetIncident_UnknownFields(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	incidentID := uuid.New()
+
+	mockService.EXPECT().GetIncident(gomock.Any(), gomock.Any()).Times(0)
+
+	w := makeRequest(router, "GET", fmt.Sprintf("/api/v1/incidents/%s?fields=name,secret,owner", incidentID), nil, map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "unknown fields: owner, secret")
 }
 
 func TestGetIncident_InvalidID(t *testing.T) {
@@ -204,6 +300,18 @@ func TestGetIncident_NotFound(t *testing.T) {
 	assert.Contains(t, w.Body.String(), "incident not found")
 }
 
+func TestGetIncident_StorageUnavailable(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	incidentID := uuid.New()
+
+	mockService.EXPECT().GetIncident(gomock.Any(), incidentID).Return(nil, fmt.Errorf("service: %w", redis.ErrClosed)).Times(1)
+
+	w := makeRequest(router, "GET", fmt.Sprintf("/api/v1/incidents/%s", incidentID), nil, map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+}
+
 func TestGetIncident_ServiceError(t *testing.T) {
 	_, mockService, router := newTestHandler(t)
 	incidentID := uuid.New()
@@ -224,7 +332,9 @@ func TestListIncidents_Success(t *testing.T) {
 		{ID: uuid.New(), Name: "Incident 2", Status: "inactive"},
 	}
 
-	mockService.EXPECT().ListIncidents(gomock.Any(), 1, 10).Return(expectedIncidents, nil).Times(1)
+	mockService.EXPECT().IncidentsLastModified(gomock.Any()).Return(time.Time{}, nil).Times(1)
+	snapshot := expectSnapshotTime(mockService)
+	mockService.EXPECT().ListIncidents(gomock.Any(), 1, 10, models.IncidentFilter{CreatedBefore: snapshot}).Return(expectedIncidents, nil).Times(1)
 
 	w := makeRequest(router, "GET", "/api/v1/incidents?page=1&pageSize=10", nil, map[string]string{"X-API-Key": "test-api-key"})
 
@@ -236,291 +346,2646 @@ func TestListIncidents_Success(t *testing.T) {
 	assert.Equal(t, expectedIncidents[0].Name, resp[0].Name)
 }
 
-func TestListIncidents_ServiceError(t *testing.T) {
+func TestListIncidents_NotModified(t *testing.T) {
 	_, mockService, router := newTestHandler(t)
-	serviceError := errors.New("failed to list incidents")
+	lastModified := time.Date(2024, 5, 1, 10, 0, 0, 500_000_000, time.UTC)
 
-	mockService.EXPECT().ListIncidents(gomock.Any(), 1, 10).Return(nil, serviceError).Times(1)
+	mockService.EXPECT().IncidentsLastModified(gomock.Any()).Return(lastModified, nil).Times(1)
+	mockService.EXPECT().ListIncidents(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
 
-	w := makeRequest(router, "GET", "/api/v1/incidents?page=1&pageSize=10", nil, map[string]string{"X-API-Key": "test-api-key"})
+	w := makeRequest(router, "GET", "/api/v1/incidents", nil, map[string]string{
+		"X-API-Key":         "test-api-key",
+		"If-Modified-Since": "Wed, 01 May 2024 10:00:01 GMT",
+	})
 
-	assert.Equal(t, http.StatusInternalServerError, w.Code)
-	assert.Contains(t, w.Body.String(), "internal server error")
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
 }
 
-func TestUpdateIncident_Success(t *testing.T) {
+func TestListIncidents_ModifiedSince(t *testing.T) {
 	_, mockService, router := newTestHandler(t)
-	incidentID := uuid.New()
-	reqBody := UpdateIncidentRequest{
-		Name:         "Updated Name",
-		Description:  "Updated Description",
-		Latitude:     11.0,
-		Longitude:    21.0,
-		RadiusMeters: 110,
-		Status:       "active",
-	}
+	// Изменение внутри секунды округляется вверх и не совпадает с датой клиента
+	lastModified := time.Date(2024, 5, 1, 10, 0, 0, 500_000_000, time.UTC)
 
-	mockService.EXPECT().
-		UpdateIncident(gomock.Any(), gomock.Any()).
-		DoAndReturn(func(_ context.Context, inc *models.Incident) error {
-			assert.Equal(t, incidentID, inc.ID)
-			assert.Equal(t, reqBody.Name, inc.Name)
-			return nil
-		}).Times(1)
+	mockService.EXPECT().IncidentsLastModified(gomock.Any()).Return(lastModified, nil).Times(1)
+	snapshot := expectSnapshotTime(mockService)
+	mockService.EXPECT().ListIncidents(gomock.Any(), 1, 10, models.IncidentFilter{CreatedBefore: snapshot}).Return([]*models.Incident{}, nil).Times(1)
 
-	bodyBytes, _ := json.Marshal(reqBody)
-	w := makeRequest(router, "PUT", fmt.Sprintf("/api/v1/incidents/%s", incidentID.String()), bytes.NewBuffer(bodyBytes), map[string]string{"X-API-Key": "test-api-key"})
+	w := makeRequest(router, "GET", "/api/v1/incidents", nil, map[string]string{
+		"X-API-Key":         "test-api-key",
+		"If-Modified-Since": "Wed, 01 May 2024 10:00:00 GMT",
+	})
 
 	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "Wed, 01 May 2024 10:00:01 GMT", w.Header().Get("Last-Modified"))
 }
 
-func TestUpdateIncident_InvalidID(t *testing.T) {
+func TestListIncidents_LastModifiedInCurrentSecond(t *testing.T) {
 	_, mockService, router := newTestHandler(t)
-	reqBody := UpdateIncidentRequest{
-		Name:         "Updated Name",
-		Latitude:     11.0,
-		Longitude:    21.0,
-		RadiusMeters: 110,
-		Status:       "active",
-	}
 
-	mockService.EXPECT().UpdateIncident(gomock.Any(), gomock.Any()).Times(0)
+	mockService.EXPECT().IncidentsLastModified(gomock.Any()).Return(time.Now().Add(time.Millisecond), nil).Times(1)
+	snapshot := expectSnapshotTime(mockService)
+	mockService.EXPECT().ListIncidents(gomock.Any(), 1, 10, models.IncidentFilter{CreatedBefore: snapshot}).Return([]*models.Incident{}, nil).Times(1)
 
-	bodyBytes, _ := json.Marshal(reqBody)
-	w := makeRequest(router, "PUT", "/api/v1/incidents/invalid-uuid", bytes.NewBuffer(bodyBytes), map[string]string{"X-API-Key": "test-api-key"})
+	w := makeRequest(router, "GET", "/api/v1/incidents", nil, map[string]string{"X-API-Key": "test-api-key"})
 
-	assert.Equal(t, http.StatusBadRequest, w.Code)
-	assert.Contains(t, w.Body.String(), "invalid incident ID")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("Last-Modified"))
 }
 
-func TestUpdateIncident_ServiceError(t *testing.T) {
+func TestListIncidents_NearFilter(t *testing.T) {
 	_, mockService, router := newTestHandler(t)
-	incidentID := uuid.New()
-	reqBody := UpdateIncidentRequest{
-		Name:         "Updated Name",
-		Description:  "Updated Description",
-		Latitude:     11.0,
-		Longitude:    21.0,
-		RadiusMeters: 110,
-		Status:       "active",
+	expectedFilter := models.IncidentFilter{
+		Near: &models.ProximityFilter{Latitude: 55.75, Longitude: 37.61, WithinMeters: 1500},
 	}
-	serviceError := errors.New("failed to update incident")
 
-	mockService.EXPECT().UpdateIncident(gomock.Any(), gomock.Any()).Return(serviceError).Times(1)
+	mockService.EXPECT().IncidentsLastModified(gomock.Any()).Return(time.Time{}, nil).Times(1)
+	expectedFilter.CreatedBefore = expectSnapshotTime(mockService)
+	mockService.EXPECT().ListIncidents(gomock.Any(), 1, 10, expectedFilter).Return([]*models.Incident{}, nil).Times(1)
 
-	bodyBytes, _ := json.Marshal(reqBody)
-	w := makeRequest(router, "PUT", fmt.Sprintf("/api/v1/incidents/%s", incidentID.String()), bytes.NewBuffer(bodyBytes), map[string]string{"X-API-Key": "test-api-key"})
+	w := makeRequest(router, "GET", "/api/v1/incidents?near_lat=55.75&near_lon=37.61&within_meters=1500", nil, map[string]string{"X-API-Key": "test-api-key"})
 
-	assert.Equal(t, http.StatusInternalServerError, w.Code) // Ожидаем 500, так как валидация пройдена
-	assert.Contains(t, w.Body.String(), "failed to update incident in service")
+	assert.Equal(t, http.StatusOK, w.Code)
 }
 
-func TestDeleteIncident_Success(t *testing.T) {
+func TestListIncidents_MinConfidence(t *testing.T) {
 	_, mockService, router := newTestHandler(t)
-	incidentID := uuid.New()
+	expectedFilter := models.IncidentFilter{MinConfidence: 80}
 
-	mockService.EXPECT().DeactivateIncident(gomock.Any(), incidentID).Return(nil).Times(1)
+	mockService.EXPECT().IncidentsLastModified(gomock.Any()).Return(time.Time{}, nil).Times(1)
+	expectedFilter.CreatedBefore = expectSnapshotTime(mockService)
+	mockService.EXPECT().ListIncidents(gomock.Any(), 1, 10, expectedFilter).Return([]*models.Incident{}, nil).Times(1)
 
-	w := makeRequest(router, "DELETE", fmt.Sprintf("/api/v1/incidents/%s", incidentID.String()), nil, map[string]string{"X-API-Key": "test-api-key"})
+	w := makeRequest(router, "GET", "/api/v1/incidents?min_confidence=80", nil, map[string]string{"X-API-Key": "test-api-key"})
 
-	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, http.StatusOK, w.Code)
 }
 
-func TestDeleteIncident_InvalidID(t *testing.T) {
+func TestListIncidents_CreatedBy(t *testing.T) {
 	_, mockService, router := newTestHandler(t)
+	expectedFilter := models.IncidentFilter{CreatedBy: "key_1a2b3c4d"}
 
-	mockService.EXPECT().DeactivateIncident(gomock.Any(), gomock.Any()).Times(0)
+	mockService.EXPECT().IncidentsLastModified(gomock.Any()).Return(time.Time{}, nil).Times(1)
+	expectedFilter.CreatedBefore = expectSnapshotTime(mockService)
+	mockService.EXPECT().ListIncidents(gomock.Any(), 1, 10, expectedFilter).Return([]*models.Incident{}, nil).Times(1)
 
-	w := makeRequest(router, "DELETE", "/api/v1/incidents/invalid-uuid", nil, map[string]string{"X-API-Key": "test-api-key"})
+	w := makeRequest(router, "GET", "/api/v1/incidents?created_by=key_1a2b3c4d", nil, map[string]string{"X-API-Key": "test-api-key"})
 
-	assert.Equal(t, http.StatusBadRequest, w.Code)
-	assert.Contains(t, w.Body.String(), "invalid incident ID")
+	assert.Equal(t, http.StatusOK, w.Code)
 }
 
-func TestDeleteIncident_NotFound(t *testing.T) {
+func TestListMyIncidents(t *testing.T) {
 	_, mockService, router := newTestHandler(t)
-	incidentID := uuid.New()
-	serviceError := errors.New("incident not found for deactivate")
+	// Список ограничен меткой ключа запроса
+	expectedFilter := models.IncidentFilter{CreatedBy: apiKeyLabel("test-api-key"), MinConfidence: 50}
+	expectedIncidents := []*models.Incident{{ID: uuid.New(), Name: "Mine", CreatedBy: apiKeyLabel("test-api-key")}}
 
-	mockService.EXPECT().DeactivateIncident(gomock.Any(), incidentID).Return(serviceError).Times(1)
+	mockService.EXPECT().IncidentsLastModified(gomock.Any()).Return(time.Time{}, nil).Times(1)
+	expectedFilter.CreatedBefore = expectSnapshotTime(mockService)
+	mockService.EXPECT().ListIncidents(gomock.Any(), 2, 5, expectedFilter).Return(expectedIncidents, nil).Times(1)
 
-	w := makeRequest(router, "DELETE", fmt.Sprintf("/api/v1/incidents/%s", incidentID.String()), nil, map[string]string{"X-API-Key": "test-api-key"})
+	w := makeRequest(router, "GET", "/api/v1/incidents/mine?page=2&pageSize=5&min_confidence=50", nil, map[string]string{"X-API-Key": "test-api-key"})
 
-	assert.Equal(t, http.StatusInternalServerError, w.Code) // Хендлер возвращает 500 для этой ошибки
-	assert.Contains(t, w.Body.String(), "failed to deactivate incident")
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp []IncidentResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp, 1)
+	assert.Equal(t, apiKeyLabel("test-api-key"), resp[0].CreatedBy)
 }
 
-func TestCheckLocation_Success_Danger(t *testing.T) {
+func TestListMyIncidents_Unauthorized(t *testing.T) {
+	_, _, router := newTestHandler(t)
+
+	w := makeRequest(router, "GET", "/api/v1/incidents/mine", nil)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestListIncidents_SnapshotTime(t *testing.T) {
 	_, mockService, router := newTestHandler(t)
-	reqBody := LocationCheckRequest{
-		UserID:    "user123",
-		Latitude:  50.0,
-		Longitude: 50.0,
-	}
-	incidentsFound := []*models.Incident{
-		{ID: uuid.New(), Name: "Danger Zone A"},
-	}
+	snapshot := time.Date(2024, 5, 1, 10, 0, 0, 123_000_000, time.UTC)
+	expectedFilter := models.IncidentFilter{CreatedBefore: &snapshot}
 
-	mockService.EXPECT().CheckLocation(gomock.Any(), reqBody.UserID, reqBody.Latitude, reqBody.Longitude).Return(incidentsFound, nil).Times(1)
+	mockService.EXPECT().IncidentsLastModified(gomock.Any()).Return(time.Time{}, nil).Times(1)
+	mockService.EXPECT().ListIncidents(gomock.Any(), 2, 10, expectedFilter).Return([]*models.Incident{}, nil).Times(1)
 
-	bodyBytes, _ := json.Marshal(reqBody)
-	w := makeRequest(router, "POST", "/api/v1/location/check", bytes.NewBuffer(bodyBytes))
+	w := makeRequest(router, "GET", "/api/v1/incidents?page=2&snapshot_time=2024-05-01T10:00:00.123Z", nil, map[string]string{"X-API-Key": "test-api-key"})
 
 	assert.Equal(t, http.StatusOK, w.Code)
-	var resp []IncidentResponse
-	err := json.Unmarshal(w.Body.Bytes(), &resp)
-	require.NoError(t, err)
-	assert.Len(t, resp, 1)
-	assert.Equal(t, incidentsFound[0].Name, resp[0].Name)
+	assert.Equal(t, "2024-05-01T10:00:00.123Z", w.Header().Get("X-Snapshot-Time"))
 }
 
-func TestCheckLocation_Success_Safe(t *testing.T) {
+func TestListIncidents_SnapshotTimeIssued(t *testing.T) {
 	_, mockService, router := newTestHandler(t)
-	reqBody := LocationCheckRequest{
-		UserID:    "user123",
-		Latitude:  50.0,
-		Longitude: 50.0,
-	}
-	var incidentsFound []*models.Incident // No incidents found
 
-	mockService.EXPECT().CheckLocation(gomock.Any(), reqBody.UserID, reqBody.Latitude, reqBody.Longitude).Return(incidentsFound, nil).Times(1)
+	mockService.EXPECT().IncidentsLastModified(gomock.Any()).Return(time.Time{}, nil).Times(1)
+	snapshot := expectSnapshotTime(mockService)
+	// Первая страница ограничивается моментом снимка так же, как следующие
+	mockService.EXPECT().ListIncidents(gomock.Any(), 1, 10, models.IncidentFilter{CreatedBefore: snapshot}).Return([]*models.Incident{}, nil).Times(1)
 
-	bodyBytes, _ := json.Marshal(reqBody)
-	w := makeRequest(router, "POST", "/api/v1/location/check", bytes.NewBuffer(bodyBytes))
+	w := makeRequest(router, "GET", "/api/v1/incidents", nil, map[string]string{"X-API-Key": "test-api-key"})
 
+	// Первая страница без snapshot_time возвращает время базы для следующих страниц
 	assert.Equal(t, http.StatusOK, w.Code)
-	var resp []IncidentResponse
-	err := json.Unmarshal(w.Body.Bytes(), &resp)
-	require.NoError(t, err)
-	assert.Empty(t, resp)
+	assert.Equal(t, snapshot.Format(time.RFC3339Nano), w.Header().Get("X-Snapshot-Time"))
 }
 
-func TestCheckLocation_ValidationError(t *testing.T) {
+func TestListIncidents_SnapshotTimeError(t *testing.T) {
 	_, mockService, router := newTestHandler(t)
-	reqBody := LocationCheckRequest{ // Отсутствует UserID
-		Latitude:  50.0,
-		Longitude: 50.0,
-	}
 
-	mockService.EXPECT().CheckLocation(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	mockService.EXPECT().IncidentsLastModified(gomock.Any()).Return(time.Time{}, nil).Times(1)
+	mockService.EXPECT().IncidentsSnapshotTime(gomock.Any()).Return(time.Time{}, errors.New("service: could not get snapshot time")).Times(1)
+	mockService.EXPECT().ListIncidents(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
 
-	bodyBytes, _ := json.Marshal(reqBody)
-	w := makeRequest(router, "POST", "/api/v1/location/check", bytes.NewBuffer(bodyBytes))
+	w := makeRequest(router, "GET", "/api/v1/incidents", nil, map[string]string{"X-API-Key": "test-api-key"})
 
-	assert.Equal(t, http.StatusBadRequest, w.Code)
-	assert.Contains(t, w.Body.String(), "Error:Field validation for 'UserID' failed on the 'required' tag")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
 }
 
-func TestCheckLocation_ServiceError(t *testing.T) {
+func TestListIncidents_InvalidSnapshotTime(t *testing.T) {
 	_, mockService, router := newTestHandler(t)
-	reqBody := LocationCheckRequest{
-		UserID:    "user123",
-		Latitude:  50.0,
-		Longitude: 50.0,
-	}
-	serviceError := errors.New("failed to check location")
 
-	mockService.EXPECT().CheckLocation(gomock.Any(), reqBody.UserID, reqBody.Latitude, reqBody.Longitude).Return(nil, serviceError).Times(1)
+	mockService.EXPECT().ListIncidents(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
 
-	bodyBytes, _ := json.Marshal(reqBody)
-	w := makeRequest(router, "POST", "/api/v1/location/check", bytes.NewBuffer(bodyBytes))
+	w := makeRequest(router, "GET", "/api/v1/incidents?snapshot_time=yesterday", nil, map[string]string{"X-API-Key": "test-api-key"})
 
-	assert.Equal(t, http.StatusInternalServerError, w.Code)
-	assert.Contains(t, w.Body.String(), "internal server error")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "snapshot_time")
 }
 
-func TestGetStats_Success(t *testing.T) {
+func TestListIncidents_Sort(t *testing.T) {
 	_, mockService, router := newTestHandler(t)
-	expectedCount := 123
+	expectedFilter := models.IncidentFilter{
+		Sort: &models.IncidentSort{Field: models.SortByName, Descending: false},
+	}
 
-	mockService.EXPECT().GetStats(gomock.Any()).Return(expectedCount, nil).Times(1)
+	mockService.EXPECT().IncidentsLastModified(gomock.Any()).Return(time.Time{}, nil).Times(1)
+	expectedFilter.CreatedBefore = expectSnapshotTime(mockService)
+	mockService.EXPECT().ListIncidents(gomock.Any(), 1, 10, expectedFilter).Return([]*models.Incident{}, nil).Times(1)
 
-	w := makeRequest(router, "GET", "/api/v1/incidents/stats", nil, map[string]string{"X-API-Key": "test-api-key"})
+	w := makeRequest(router, "GET", "/api/v1/incidents?sort_by=name&order=asc", nil, map[string]string{"X-API-Key": "test-api-key"})
 
 	assert.Equal(t, http.StatusOK, w.Code)
-	var resp StatsResponse
-	err := json.Unmarshal(w.Body.Bytes(), &resp)
-	require.NoError(t, err)
-	assert.Equal(t, expectedCount, resp.UserCount)
 }
 
-func TestGetStats_ServiceError(t *testing.T) {
+func TestListIncidents_SortDefaultsToDescending(t *testing.T) {
 	_, mockService, router := newTestHandler(t)
-	serviceError := errors.New("failed to get stats")
+	expectedFilter := models.IncidentFilter{
+		Sort: &models.IncidentSort{Field: models.SortByUpdatedAt, Descending: true},
+	}
 
-	mockService.EXPECT().GetStats(gomock.Any()).Return(0, serviceError).Times(1)
+	mockService.EXPECT().IncidentsLastModified(gomock.Any()).Return(time.Time{}, nil).Times(1)
+	expectedFilter.CreatedBefore = expectSnapshotTime(mockService)
+	mockService.EXPECT().ListIncidents(gomock.Any(), 1, 10, expectedFilter).Return([]*models.Incident{}, nil).Times(1)
 
-	w := makeRequest(router, "GET", "/api/v1/incidents/stats", nil, map[string]string{"X-API-Key": "test-api-key"})
+	w := makeRequest(router, "GET", "/api/v1/incidents?sort_by=updated_at", nil, map[string]string{"X-API-Key": "test-api-key"})
 
-	assert.Equal(t, http.StatusInternalServerError, w.Code)
-	assert.Contains(t, w.Body.String(), "internal server error")
+	assert.Equal(t, http.StatusOK, w.Code)
 }
 
-func TestHealthCheck_Success(t *testing.T) {
-	_, _, router := newTestHandler(t)
+func TestListIncidents_SortBySeverity(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	expectedFilter := models.IncidentFilter{
+		Sort: &models.IncidentSort{Field: models.SortBySeverity, Descending: true},
+	}
 
-	w := makeRequest(router, "GET", "/api/v1/system/health", nil)
+	mockService.EXPECT().IncidentsLastModified(gomock.Any()).Return(time.Time{}, nil).Times(1)
+	expectedFilter.CreatedBefore = expectSnapshotTime(mockService)
+	mockService.EXPECT().ListIncidents(gomock.Any(), 1, 10, expectedFilter).Return([]*models.Incident{}, nil).Times(1)
+
+	w := makeRequest(router, "GET", "/api/v1/incidents?sort_by=severity", nil, map[string]string{"X-API-Key": "test-api-key"})
 
 	assert.Equal(t, http.StatusOK, w.Code)
-	assert.Contains(t, w.Body.String(), `"status":"ok"`)
 }
 
-func TestAPIKeyAuthMiddleware_Success(t *testing.T) {
-	// Создаем Gin-роутер и добавляем middleware
-	gin.SetMode(gin.TestMode)
-	router := gin.New()
-	logger := logrus.New()
-	logger.SetOutput(&bytes.Buffer{})
+func TestListIncidents_InvalidSort(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
 
-	cfg := &config.Config{
-		APIKeys: []string{"valid-key"},
+	mockService.EXPECT().ListIncidents(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	for _, query := range []string{"sort_by=name%3BDROP%20TABLE%20incidents", "sort_by=radius_meters", "order=sideways"} {
+		w := makeRequest(router, "GET", "/api/v1/incidents?"+query, nil, map[string]string{"X-API-Key": "test-api-key"})
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
 	}
+}
 
-	router.Use(APIKeyAuthMiddleware(cfg, logger))
-	router.GET("/test", func(c *gin.Context) {
-		c.Status(http.StatusOK)
-	})
+func TestListIncidents_Fields(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	incidents := []*models.Incident{
+		{ID: uuid.New(), Name: "Incident 1", Latitude: 55.75, Longitude: 37.61},
+		{ID: uuid.New(), Name: "Incident 2", Latitude: 59.93, Longitude: 30.31},
+	}
+
+	mockService.EXPECT().IncidentsLastModified(gomock.Any()).Return(time.Time{}, nil).Times(1)
+	snapshot := expectSnapshotTime(mockService)
+	mockService.EXPECT().ListIncidents(gomock.Any(), 1, 10, models.IncidentFilter{CreatedBefore: snapshot}).Return(incidents, nil).Times(1)
+
+	w := makeRequest(router, "GET", "/api/v1/incidents?fields=latitude,longitude", nil, map[string]string{"X-API-Key": "test-api-key"})
 
-	w := makeRequest(router, "GET", "/test", nil, map[string]string{"X-API-Key": "valid-key"})
 	assert.Equal(t, http.StatusOK, w.Code)
+	var resp []map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp, 2)
+	assert.Len(t, resp[0], 3)
+	assert.Equal(t, incidents[1].ID.String(), resp[1]["id"])
+	assert.Equal(t, 59.93, resp[1]["latitude"])
 }
 
-func TestAPIKeyAuthMiddleware_MissingKey(t *testing.T) {
-	gin.SetMode(gin.TestMode)
-	router := gin.New()
-	logger := logrus.New()
-	logger.SetOutput(&bytes.Buffer{})
+func TestListIncidents_InvalidMinConfidence(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
 
-	cfg := &config.Config{
-		APIKeys: []string{"valid-key"},
-	}
+	mockService.EXPECT().ListIncidents(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
 
-	router.Use(APIKeyAuthMiddleware(cfg, logger))
-	router.GET("/test", func(c *gin.Context) {
-		c.Status(http.StatusOK)
-	})
+	w := makeRequest(router, "GET", "/api/v1/incidents?min_confidence=101", nil, map[string]string{"X-API-Key": "test-api-key"})
 
-	w := makeRequest(router, "GET", "/test", nil) // Нет API ключа
-	assert.Equal(t, http.StatusUnauthorized, w.Code)
-	assert.Contains(t, w.Body.String(), "API key required")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
-func TestAPIKeyAuthMiddleware_InvalidKey(t *testing.T) {
-	gin.SetMode(gin.TestMode)
-	router := gin.New()
-	logger := logrus.New()
-	logger.SetOutput(&bytes.Buffer{})
+func TestListIncidents_NearFilterIncomplete(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
 
-	cfg := &config.Config{
-		APIKeys: []string{"valid-key"},
-	}
+	mockService.EXPECT().ListIncidents(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
 
-	router.Use(APIKeyAuthMiddleware(cfg, logger))
-	router.GET("/test", func(c *gin.Context) {
+	w := makeRequest(router, "GET", "/api/v1/incidents?near_lat=55.75&near_lon=37.61", nil, map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "must be provided together")
+}
+
+func TestListIncidentChanges_Success(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	since := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	changed := []*models.Incident{
+		{ID: uuid.New(), Name: "Пожар", Status: "inactive"},
+		{ID: uuid.New(), Name: "Наводнение", Status: "active"},
+	}
+	cursor := models.ChangeCursor{UpdatedAt: since.Add(time.Minute), ID: changed[1].ID}
+
+	mockService.EXPECT().
+		ListIncidentChanges(gomock.Any(), models.ChangeCursor{UpdatedAt: since}, 10).
+		Return(changed, cursor, false, nil).Times(1)
+
+	before := time.Now().UTC()
+	w := makeRequest(router, "GET", "/api/v1/incidents/changes?since=2024-05-01T12:00:00Z", nil, map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp IncidentChangesResponse
+	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	require.NoError(t, err)
+	require.Len(t, resp.Incidents, 2)
+	assert.Equal(t, "inactive", resp.Incidents[0].Status)
+	assert.Equal(t, cursor.ID, resp.Cursor.ID)
+	assert.False(t, resp.HasMore)
+	require.NotNil(t, resp.ServerTime)
+	assert.False(t, resp.ServerTime.Before(before.Truncate(time.Second)))
+}
+
+func TestListIncidentChanges_HasMore(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	after := models.ChangeCursor{UpdatedAt: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC), ID: uuid.New()}
+	changed := []*models.Incident{{ID: uuid.New(), Name: "Пожар", Status: "active"}}
+	cursor := models.ChangeCursor{UpdatedAt: after.UpdatedAt, ID: changed[0].ID}
+
+	mockService.EXPECT().ListIncidentChanges(gomock.Any(), after, 1).Return(changed, cursor, true, nil).Times(1)
+
+	url := fmt.Sprintf("/api/v1/incidents/changes?since=2024-05-01T12:00:00Z&after_id=%s&pageSize=1", after.ID)
+	w := makeRequest(router, "GET", url, nil, map[string]string{"X-API-Key": "test-api-key"})
+
+	// Проверки: до последней страницы server_time не возвращается
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp IncidentChangesResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.True(t, resp.HasMore)
+	assert.Equal(t, cursor.ID, resp.Cursor.ID)
+	assert.True(t, cursor.UpdatedAt.Equal(resp.Cursor.UpdatedAt))
+	assert.Nil(t, resp.ServerTime)
+	assert.NotContains(t, w.Body.String(), "server_time")
+}
+
+func TestListIncidentChanges_InvalidSince(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+
+	mockService.EXPECT().ListIncidentChanges(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	for _, url := range []string{
+		"/api/v1/incidents/changes",
+		"/api/v1/incidents/changes?since=yesterday",
+		"/api/v1/incidents/changes?since=2024-05-01T12:00:00Z&after_id=last",
+	} {
+		w := makeRequest(router, "GET", url, nil, map[string]string{"X-API-Key": "test-api-key"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestPollIncidentChanges_Success(t *testing.T) {
+	h, mockService, router := newTestHandler(t)
+	h.cfg.LongPollMaxTimeout = 20 * time.Second
+	h.cfg.LongPollMaxClients = 1
+	after := models.ChangeCursor{UpdatedAt: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC), ID: uuid.New()}
+	changed := []*models.Incident{{ID: uuid.New(), Name: "Пожар", Status: "active"}}
+	cursor := models.ChangeCursor{UpdatedAt: time.Date(2024, 5, 1, 12, 0, 5, 0, time.UTC), ID: changed[0].ID}
+
+	// timeout больше LONG_POLL_MAX_TIMEOUT уменьшается до него
+	mockService.EXPECT().PollIncidentChanges(gomock.Any(), after, 20*time.Second).Return(changed, cursor, nil).Times(1)
+
+	url := fmt.Sprintf("/api/v1/incidents/poll?since=2024-05-01T12:00:00Z&after_id=%s&timeout=45s", after.ID)
+	w := makeRequest(router, "GET", url, nil, map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp IncidentChangesResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Incidents, 1)
+	assert.True(t, cursor.UpdatedAt.Equal(resp.Cursor.UpdatedAt))
+	assert.Equal(t, cursor.ID, resp.Cursor.ID)
+	assert.Nil(t, resp.ServerTime)
+	assert.Equal(t, int64(0), h.pollers.Load())
+}
+
+func TestPollIncidentChanges_InvalidTimeout(t *testing.T) {
+	h, mockService, router := newTestHandler(t)
+	h.cfg.LongPollMaxTimeout = time.Minute
+	h.cfg.LongPollMaxClients = 1
+
+	mockService.EXPECT().PollIncidentChanges(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	for _, query := range []string{"timeout=soon", "timeout=-5s", "timeout=0s", "after_id=last"} {
+		w := makeRequest(router, "GET", "/api/v1/incidents/poll?since=2024-05-01T12:00:00Z&"+query, nil, map[string]string{"X-API-Key": "test-api-key"})
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
+
+func TestPollIncidentChanges_TooManyPollers(t *testing.T) {
+	h, mockService, router := newTestHandler(t)
+	h.cfg.LongPollMaxTimeout = time.Minute
+	h.cfg.LongPollMaxClients = 1
+	// Один запрос уже ожидает изменений
+	h.pollers.Store(1)
+
+	mockService.EXPECT().PollIncidentChanges(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	w := makeRequest(router, "GET", "/api/v1/incidents/poll?since=2024-05-01T12:00:00Z", nil, map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "5", w.Header().Get("Retry-After"))
+	assert.Contains(t, w.Body.String(), codeTooManyPollers)
+	assert.Equal(t, int64(1), h.pollers.Load())
+}
+
+func TestGetIncidentClusters_Success(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	incidentID := uuid.New()
+	bbox := geo.BBox{MinLat: 55.5, MinLon: 37.3, MaxLat: 56.0, MaxLon: 37.9}
+	clusters := []*models.IncidentCluster{
+		{Latitude: 55.7, Longitude: 37.6, Count: 12},
+		{Latitude: 55.9, Longitude: 37.4, Count: 1, IncidentID: &incidentID},
+	}
+
+	mockService.EXPECT().ClusterIncidents(gomock.Any(), bbox, 10).Return(clusters, nil).Times(1)
+
+	w := makeRequest(router, "GET", "/api/v1/incidents/clusters?min_lat=55.5&min_lon=37.3&max_lat=56.0&max_lon=37.9&zoom=10", nil, map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp []ClusterResponse
+	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	require.NoError(t, err)
+	require.Len(t, resp, 2)
+	assert.Equal(t, 12, resp[0].Count)
+	assert.Nil(t, resp[0].IncidentID)
+	assert.Equal(t, &incidentID, resp[1].IncidentID)
+}
+
+func TestGetIncidentClusters_AcrossAntimeridian(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	bbox := geo.BBox{MinLat: -19, MinLon: 177, MaxLat: -16, MaxLon: -179}
+
+	mockService.EXPECT().ClusterIncidents(gomock.Any(), bbox, 5).Return([]*models.IncidentCluster{}, nil).Times(1)
+
+	w := makeRequest(router, "GET", "/api/v1/incidents/clusters?min_lat=-19&min_lon=177&max_lat=-16&max_lon=-179&zoom=5", nil, map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestGetIncidentHotspots_Success(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	bbox := geo.BBox{MinLat: 55.5, MinLon: 37.3, MaxLat: 56.0, MaxLon: 37.9}
+	hotspots := []*models.IncidentHotspot{{
+		Latitude:  55.75,
+		Longitude: 37.62,
+		Bounds:    geo.BBox{MinLat: 55.745, MinLon: 37.61, MaxLat: 55.755, MaxLon: 37.63},
+		Count:     9,
+	}}
+
+	mockService.EXPECT().FindHotspots(gomock.Any(), bbox, 500.0, 3).Return(hotspots, nil).Times(1)
+
+	w := makeRequest(router, "GET", "/api/v1/incidents/hotspots?min_lat=55.5&min_lon=37.3&max_lat=56.0&max_lon=37.9&cell_meters=500&min_count=3", nil, map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `[{
+		"latitude": 55.75, "longitude": 37.62, "count": 9,
+		"bounds": {"min_lat": 55.745, "min_lon": 37.61, "max_lat": 55.755, "max_lon": 37.63}
+	}]`, w.Body.String())
+}
+
+func TestGetIncidentHotspots_InvalidQuery(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+
+	mockService.EXPECT().FindHotspots(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	for _, query := range []string{
+		"min_lat=55.5&min_lon=37.3&max_lat=56.0&max_lon=37.9",
+		"min_lat=55.5&min_lon=37.3&max_lat=56.0&max_lon=37.9&cell_meters=10",
+		"min_lat=55.5&min_lon=37.3&max_lat=56.0&max_lon=37.9&cell_meters=500&min_count=1",
+		"min_lat=56.0&min_lon=37.3&max_lat=55.5&max_lon=37.9&cell_meters=500",
+	} {
+		w := makeRequest(router, "GET", "/api/v1/incidents/hotspots?"+query, nil, map[string]string{"X-API-Key": "test-api-key"})
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
+
+func TestGetIncidentClusters_InvalidQuery(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+
+	mockService.EXPECT().ClusterIncidents(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	for _, query := range []string{
+		"min_lat=55.5&min_lon=37.3&max_lat=56.0&max_lon=37.9",
+		"min_lat=55.5&min_lon=37.3&max_lat=56.0&max_lon=37.9&zoom=25",
+		"min_lat=56.0&min_lon=37.3&max_lat=55.5&max_lon=37.9&zoom=5",
+		"min_lat=abc&min_lon=37.3&max_lat=56.0&max_lon=37.9&zoom=5",
+	} {
+		w := makeRequest(router, "GET", "/api/v1/incidents/clusters?"+query, nil, map[string]string{"X-API-Key": "test-api-key"})
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
+
+func TestListIncidents_StorageUnavailable(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	dialErr := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+
+	mockService.EXPECT().IncidentsLastModified(gomock.Any()).Return(time.Time{}, nil).Times(1)
+	expectSnapshotTime(mockService)
+	mockService.EXPECT().ListIncidents(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("service: could not list incidents: %w", dialErr)).Times(1)
+
+	w := makeRequest(router, "GET", "/api/v1/incidents", nil, map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "5", w.Header().Get("Retry-After"))
+	var resp ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "SERVICE_UNAVAILABLE", resp.Code)
+}
+
+func TestIsDependencyUnavailable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"network error", fmt.Errorf("wrapped: %w", &net.OpError{Op: "read", Err: errors.New("connection reset")}), true},
+		{"postgres starting up", fmt.Errorf("wrapped: %w", &pgconn.PgError{Code: "57P03"}), true},
+		{"postgres connection failure", &pgconn.PgError{Code: "08006"}, true},
+		{"postgres constraint violation", &pgconn.PgError{Code: "23505"}, false},
+		{"redis pool timeout", fmt.Errorf("wrapped: %w", redis.ErrPoolTimeout), true},
+		{"not found", service.ErrIncidentNotFound, false},
+		{"generic error", errors.New("boom"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isDependencyUnavailable(tt.err))
+		})
+	}
+}
+
+func TestListIncidents_ServiceError(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	serviceError := errors.New("failed to list incidents")
+
+	mockService.EXPECT().IncidentsLastModified(gomock.Any()).Return(time.Time{}, nil).Times(1)
+	snapshot := expectSnapshotTime(mockService)
+	mockService.EXPECT().ListIncidents(gomock.Any(), 1, 10, models.IncidentFilter{CreatedBefore: snapshot}).Return(nil, serviceError).Times(1)
+
+	w := makeRequest(router, "GET", "/api/v1/incidents?page=1&pageSize=10", nil, map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), "internal server error")
+}
+
+func TestUpdateIncident_Success(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	incidentID := uuid.New()
+	reqBody := UpdateIncidentRequest{
+		Name:         "Updated Name",
+		Description:  "Updated Description",
+		Latitude:     11.0,
+		Longitude:    21.0,
+		RadiusMeters: 110,
+		Status:       "active",
+	}
+
+	mockService.EXPECT().
+		UpdateIncident(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, inc *models.Incident) error {
+			assert.Equal(t, incidentID, inc.ID)
+			assert.Equal(t, reqBody.Name, inc.Name)
+			return nil
+		}).Times(1)
+
+	bodyBytes, _ := json.Marshal(reqBody)
+	w := makeRequest(router, "PUT", fmt.Sprintf("/api/v1/incidents/%s", incidentID.String()), bytes.NewBuffer(bodyBytes), map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestUpdateIncident_OmittedFlagsReset(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	incidentID := uuid.New()
+
+	// Обновление полное: silent и public, не переданные в теле, сбрасываются в false
+	mockService.EXPECT().
+		UpdateIncident(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, inc *models.Incident) error {
+			assert.False(t, inc.Silent)
+			assert.False(t, inc.Public)
+			return nil
+		}).Times(1)
+
+	body := `{"name": "Updated Name", "latitude": 11.0, "longitude": 21.0, "radius_meters": 110, "status": "active"}`
+	w := makeRequest(router, "PUT", fmt.Sprintf("/api/v1/incidents/%s", incidentID), bytes.NewBufferString(body), map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestUpdateIncident_InvalidID(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	reqBody := UpdateIncidentRequest{
+		Name:         "Updated Name",
+		Latitude:     11.0,
+		Longitude:    21.0,
+		RadiusMeters: 110,
+		Status:       "active",
+	}
+
+	mockService.EXPECT().UpdateIncident(gomock.Any(), gomock.Any()).Times(0)
+
+	bodyBytes, _ := json.Marshal(reqBody)
+	w := makeRequest(router, "PUT", "/api/v1/incidents/invalid-uuid", bytes.NewBuffer(bodyBytes), map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid incident ID")
+}
+
+func TestUpdateIncident_ValidationError(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	reqBody := UpdateIncidentRequest{
+		Name:         "Updated Name",
+		Latitude:     91.0, // Вне допустимого диапазона
+		Longitude:    21.0,
+		RadiusMeters: 110,
+		Status:       "unknown",
+	}
+
+	mockService.EXPECT().UpdateIncident(gomock.Any(), gomock.Any()).Times(0)
+
+	bodyBytes, _ := json.Marshal(reqBody)
+	w := makeRequest(router, "PUT", "/api/v1/incidents/"+uuid.New().String(), bytes.NewBuffer(bodyBytes), map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	var resp ValidationErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Details, 2)
+	assert.Equal(t, "latitude", resp.Details[0].Field)
+	assert.Equal(t, "status", resp.Details[1].Field)
+}
+
+func TestUpdateIncident_InvalidJSON(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+
+	mockService.EXPECT().UpdateIncident(gomock.Any(), gomock.Any()).Times(0)
+
+	w := makeRequest(router, "PUT", "/api/v1/incidents/"+uuid.New().String(), bytes.NewBufferString(`{"latitude": "north"}`), map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid request body")
+}
+
+func TestUpdateIncident_ServiceError(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	incidentID := uuid.New()
+	reqBody := UpdateIncidentRequest{
+		Name:         "Updated Name",
+		Description:  "Updated Description",
+		Latitude:     11.0,
+		Longitude:    21.0,
+		RadiusMeters: 110,
+		Status:       "active",
+	}
+	serviceError := errors.New("failed to update incident")
+
+	mockService.EXPECT().UpdateIncident(gomock.Any(), gomock.Any()).Return(serviceError).Times(1)
+
+	bodyBytes, _ := json.Marshal(reqBody)
+	w := makeRequest(router, "PUT", fmt.Sprintf("/api/v1/incidents/%s", incidentID.String()), bytes.NewBuffer(bodyBytes), map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code) // Ожидаем 500, так как валидация пройдена
+	assert.Contains(t, w.Body.String(), "failed to update incident in service")
+}
+
+func TestDeleteIncident_Success(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	incidentID := uuid.New()
+
+	mockService.EXPECT().DeactivateIncident(gomock.Any(), incidentID, models.Resolution{}).Return(nil).Times(1)
+
+	w := makeRequest(router, "DELETE", fmt.Sprintf("/api/v1/incidents/%s", incidentID.String()), nil, map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func TestDeleteIncident_Resolved(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	incidentID := uuid.New()
+
+	resolution := models.Resolution{Resolved: true, Reason: "fire extinguished"}
+	mockService.EXPECT().DeactivateIncident(gomock.Any(), incidentID, resolution).Return(nil).Times(1)
+
+	body := strings.NewReader(`{"resolved": true, "reason": "fire extinguished"}`)
+	w := makeRequest(router, "DELETE", fmt.Sprintf("/api/v1/incidents/%s", incidentID.String()), body, map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func TestDeleteIncident_StatusConflict(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	incidentID := uuid.New()
+
+	mockService.EXPECT().DeactivateIncident(gomock.Any(), incidentID, models.Resolution{}).
+		Return(fmt.Errorf("service: status transition from resolved to inactive is not allowed: %w", service.ErrStatusConflict)).Times(1)
+
+	w := makeRequest(router, "DELETE", fmt.Sprintf("/api/v1/incidents/%s", incidentID), nil, map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "not allowed")
+}
+
+func TestDeleteIncident_ReasonTooLong(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	incidentID := uuid.New()
+
+	mockService.EXPECT().DeactivateIncident(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	body := strings.NewReader(fmt.Sprintf(`{"resolved": true, "reason": %q}`, strings.Repeat("a", 501)))
+	w := makeRequest(router, "DELETE", fmt.Sprintf("/api/v1/incidents/%s", incidentID.String()), body, map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+func TestDeleteIncident_InvalidID(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+
+	mockService.EXPECT().DeactivateIncident(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	w := makeRequest(router, "DELETE", "/api/v1/incidents/invalid-uuid", nil, map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid incident ID")
+}
+
+func TestDeleteIncident_NotFound(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	incidentID := uuid.New()
+	serviceError := errors.New("incident not found for deactivate")
+
+	mockService.EXPECT().DeactivateIncident(gomock.Any(), incidentID, models.Resolution{}).Return(serviceError).Times(1)
+
+	w := makeRequest(router, "DELETE", fmt.Sprintf("/api/v1/incidents/%s", incidentID.String()), nil, map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code) // Хендлер возвращает 500 для этой ошибки
+	assert.Contains(t, w.Body.String(), "failed to deactivate incident")
+}
+
+func TestBulkDeactivateIncidents_ByIDs(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	ids := []uuid.UUID{uuid.New(), uuid.New()}
+
+	mockService.EXPECT().BulkDeactivateIncidents(gomock.Any(), models.BulkDeactivation{IDs: ids}).Return(2, nil).Times(1)
+
+	bodyBytes, _ := json.Marshal(BulkDeactivateRequest{IDs: ids})
+	w := makeRequest(router, "POST", "/api/v1/incidents/deactivate", bytes.NewBuffer(bodyBytes), map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"deactivated":2}`, w.Body.String())
+}
+
+func TestBulkDeactivateIncidents_FilterRequiresConfirm(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+
+	mockService.EXPECT().BulkDeactivateIncidents(gomock.Any(), gomock.Any()).Times(0)
+
+	w := makeRequest(router, "POST", "/api/v1/incidents/deactivate", bytes.NewBufferString(`{"filter":{"status":"active"}}`), map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "requires confirm=true")
+}
+
+func TestBulkDeactivateIncidents_IDsAndFilter(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+
+	mockService.EXPECT().BulkDeactivateIncidents(gomock.Any(), gomock.Any()).Times(0)
+
+	body := fmt.Sprintf(`{"ids":["%s"],"filter":{"status":"active"},"confirm":true}`, uuid.New())
+	w := makeRequest(router, "POST", "/api/v1/incidents/deactivate", bytes.NewBufferString(body), map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "exactly one of ids or filter")
+}
+
+func TestBulkDeactivateIncidents_FilterDraftStatus(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+
+	mockService.EXPECT().BulkDeactivateIncidents(gomock.Any(), gomock.Any()).Times(0)
+
+	w := makeRequest(router, "POST", "/api/v1/incidents/deactivate", bytes.NewBufferString(`{"filter":{"status":"draft"},"confirm":true}`), map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), `"field":"filter.status"`)
+}
+
+func TestMergeIncidents_Success(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	ids := []uuid.UUID{uuid.New(), uuid.New()}
+	result := &models.IncidentMergeResult{
+		Incident:         &models.Incident{ID: ids[0], Name: "Fire", RadiusMeters: 900, Status: "active"},
+		Merged:           []*models.Incident{{ID: ids[1], Status: "inactive"}},
+		RedirectedChecks: 3,
+	}
+
+	mockService.EXPECT().MergeIncidents(gomock.Any(), ids, apiKeyLabel("test-admin-key")).Return(result, nil).Times(1)
+
+	bodyBytes, _ := json.Marshal(MergeIncidentsRequest{IDs: ids})
+	w := makeRequest(router, "POST", "/api/v1/incidents/merge", bytes.NewBuffer(bodyBytes), map[string]string{"X-API-Key": "test-admin-key"})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp MergeIncidentsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, ids[0], resp.Incident.ID)
+	assert.Equal(t, 900, resp.Incident.RadiusMeters)
+	assert.Equal(t, []uuid.UUID{ids[1]}, resp.MergedIDs)
+	assert.Equal(t, int64(3), resp.RedirectedChecks)
+}
+
+func TestMergeIncidents_RequiresAdmin(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+
+	mockService.EXPECT().MergeIncidents(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	bodyBytes, _ := json.Marshal(MergeIncidentsRequest{IDs: []uuid.UUID{uuid.New(), uuid.New()}})
+	w := makeRequest(router, "POST", "/api/v1/incidents/merge", bytes.NewBuffer(bodyBytes), map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestMergeIncidents_SingleID(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+
+	mockService.EXPECT().MergeIncidents(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	bodyBytes, _ := json.Marshal(MergeIncidentsRequest{IDs: []uuid.UUID{uuid.New()}})
+	w := makeRequest(router, "POST", "/api/v1/incidents/merge", bytes.NewBuffer(bodyBytes), map[string]string{"X-API-Key": "test-admin-key"})
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+func TestMergeIncidents_FinishedIncident(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	ids := []uuid.UUID{uuid.New(), uuid.New()}
+
+	mockService.EXPECT().MergeIncidents(gomock.Any(), ids, gomock.Any()).
+		Return(nil, fmt.Errorf("service: could not merge incidents: %w", service.ErrStatusConflict)).Times(1)
+
+	bodyBytes, _ := json.Marshal(MergeIncidentsRequest{IDs: ids})
+	w := makeRequest(router, "POST", "/api/v1/incidents/merge", bytes.NewBuffer(bodyBytes), map[string]string{"X-API-Key": "test-admin-key"})
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestImportIncidentsFromURL_Accepted(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	feedURL := "https://feeds.example.org/incidents.geojson"
+	job := &models.ImportJob{ID: uuid.New(), URL: feedURL, Status: models.ImportJobRunning, StartedAt: time.Now().UTC()}
+
+	mockService.EXPECT().StartIncidentImport(gomock.Any(), feedURL, apiKeyLabel("test-admin-key")).Return(job, nil).Times(1)
+
+	bodyBytes, _ := json.Marshal(ImportURLRequest{URL: feedURL})
+	w := makeRequest(router, "POST", "/api/v1/incidents/import-url", bytes.NewBuffer(bodyBytes), map[string]string{"X-API-Key": "test-admin-key"})
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	var resp ImportJobResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, job.ID, resp.ID)
+	assert.Equal(t, "running", resp.Status)
+	assert.NotNil(t, resp.Errors)
+}
+
+func TestImportIncidentsFromURL_RequiresAdmin(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+
+	mockService.EXPECT().StartIncidentImport(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	bodyBytes, _ := json.Marshal(ImportURLRequest{URL: "https://feeds.example.org/incidents.geojson"})
+	w := makeRequest(router, "POST", "/api/v1/incidents/import-url", bytes.NewBuffer(bodyBytes), map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestImportIncidentsFromURL_HostNotAllowed(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+
+	mockService.EXPECT().StartIncidentImport(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil, fmt.Errorf("service: host is not in IMPORT_URL_ALLOWED_HOSTS: %w", service.ErrImportURLNotAllowed)).Times(1)
+
+	bodyBytes, _ := json.Marshal(ImportURLRequest{URL: "http://10.0.0.1/feed.geojson"})
+	w := makeRequest(router, "POST", "/api/v1/incidents/import-url", bytes.NewBuffer(bodyBytes), map[string]string{"X-API-Key": "test-admin-key"})
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestImportIncidentsFromURL_LimitReached(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+
+	mockService.EXPECT().StartIncidentImport(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil, service.ErrImportLimitReached).Times(1)
+
+	bodyBytes, _ := json.Marshal(ImportURLRequest{URL: "https://feeds.example.org/incidents.geojson"})
+	w := makeRequest(router, "POST", "/api/v1/incidents/import-url", bytes.NewBuffer(bodyBytes), map[string]string{"X-API-Key": "test-admin-key"})
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, strconv.Itoa(importRetryAfter), w.Header().Get("Retry-After"))
+	assert.Contains(t, w.Body.String(), codeTooManyImports)
+}
+
+func TestGetImportJob_Completed(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	finishedAt := time.Now().UTC()
+	job := &models.ImportJob{
+		ID:         uuid.New(),
+		Status:     models.ImportJobCompleted,
+		Features:   3,
+		Created:    1,
+		Updated:    1,
+		Errors:     []models.ImportFeatureError{{Index: 2, ExternalID: "bad-1", Error: "only Point geometry is supported"}},
+		FinishedAt: &finishedAt,
+	}
+
+	mockService.EXPECT().GetImportJob(gomock.Any(), job.ID).Return(job, nil).Times(1)
+
+	w := makeRequest(router, "GET", fmt.Sprintf("/api/v1/incidents/import-jobs/%s", job.ID), nil, map[string]string{"X-API-Key": "test-admin-key"})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp ImportJobResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "completed", resp.Status)
+	require.Len(t, resp.Errors, 1)
+	assert.Equal(t, "bad-1", resp.Errors[0].ExternalID)
+}
+
+func TestGetImportJob_NotFound(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	id := uuid.New()
+
+	mockService.EXPECT().GetImportJob(gomock.Any(), id).Return(nil, service.ErrImportJobNotFound).Times(1)
+
+	w := makeRequest(router, "GET", fmt.Sprintf("/api/v1/incidents/import-jobs/%s", id), nil, map[string]string{"X-API-Key": "test-admin-key"})
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestRegisterImportFeed_Created(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	feedURL := "https://feeds.example.org/incidents.geojson"
+	feed := &models.ImportFeed{ID: uuid.New(), URL: feedURL, CreatedBy: apiKeyLabel("test-admin-key"), CreatedAt: time.Now().UTC()}
+
+	mockService.EXPECT().RegisterImportFeed(gomock.Any(), feedURL, apiKeyLabel("test-admin-key")).Return(feed, nil).Times(1)
+
+	bodyBytes, _ := json.Marshal(ImportURLRequest{URL: feedURL})
+	w := makeRequest(router, "POST", "/api/v1/incidents/import-feeds", bytes.NewBuffer(bodyBytes), map[string]string{"X-API-Key": "test-admin-key"})
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var resp ImportFeedResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, feed.ID, resp.ID)
+	assert.Nil(t, resp.LastSync)
+}
+
+func TestRegisterImportFeed_AlreadyRegistered(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+
+	mockService.EXPECT().RegisterImportFeed(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil, fmt.Errorf("service: could not register import feed: %w", service.ErrImportFeedExists)).Times(1)
+
+	bodyBytes, _ := json.Marshal(ImportURLRequest{URL: "https://feeds.example.org/incidents.geojson"})
+	w := makeRequest(router, "POST", "/api/v1/incidents/import-feeds", bytes.NewBuffer(bodyBytes), map[string]string{"X-API-Key": "test-admin-key"})
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestListImportFeeds_WithLastSync(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	feed := &models.ImportFeed{
+		ID:          uuid.New(),
+		URL:         "https://feeds.example.org/incidents.geojson",
+		ExternalIDs: []string{"a", "b"},
+		LastSync:    &models.ImportFeedSync{Status: models.ImportJobCompleted, Created: 1, Deactivated: 1, SyncedAt: time.Now().UTC()},
+	}
+
+	mockService.EXPECT().ListImportFeeds(gomock.Any()).Return([]*models.ImportFeed{feed}, nil).Times(1)
+
+	w := makeRequest(router, "GET", "/api/v1/incidents/import-feeds", nil, map[string]string{"X-API-Key": "test-admin-key"})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp []ImportFeedResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp, 1)
+	assert.Equal(t, 2, resp[0].Incidents)
+	require.NotNil(t, resp[0].LastSync)
+	assert.Equal(t, 1, resp[0].LastSync.Deactivated)
+}
+
+func TestDeleteImportFeed_NotFound(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	id := uuid.New()
+
+	mockService.EXPECT().DeleteImportFeed(gomock.Any(), id).
+		Return(fmt.Errorf("service: could not delete import feed: %w", service.ErrImportFeedNotFound)).Times(1)
+
+	w := makeRequest(router, "DELETE", fmt.Sprintf("/api/v1/incidents/import-feeds/%s", id), nil, map[string]string{"X-API-Key": "test-admin-key"})
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestWatchIncident_Created(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	incidentID := uuid.New()
+	watchURL := "https://hooks.example.org/watch"
+
+	mockService.EXPECT().WatchIncident(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, watcher *models.IncidentWatcher) error {
+			assert.Equal(t, incidentID, watcher.IncidentID)
+			assert.Equal(t, "s3cret", watcher.Secret)
+			assert.Equal(t, apiKeyLabel("test-api-key"), watcher.CreatedBy)
+			watcher.ID = uuid.New()
+			return nil
+		}).Times(1)
+
+	bodyBytes, _ := json.Marshal(WatchIncidentRequest{URL: watchURL, Secret: "s3cret"})
+	w := makeRequest(router, "POST", fmt.Sprintf("/api/v1/incidents/%s/watch", incidentID), bytes.NewBuffer(bodyBytes), map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.NotContains(t, w.Body.String(), "s3cret")
+	var resp WatcherResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, watchURL, resp.URL)
+	assert.Equal(t, incidentID, resp.IncidentID)
+}
+
+func TestWatchIncident_Errors(t *testing.T) {
+	tests := map[string]struct {
+		err  error
+		code int
+	}{
+		"url not allowed":    {service.ErrWatchURLNotAllowed, http.StatusBadRequest},
+		"incident not found": {service.ErrIncidentNotFound, http.StatusNotFound},
+		"already watching":   {service.ErrWatcherExists, http.StatusConflict},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, mockService, router := newTestHandler(t)
+			mockService.EXPECT().WatchIncident(gomock.Any(), gomock.Any()).
+				Return(fmt.Errorf("service: could not create incident watcher: %w", tt.err)).Times(1)
+
+			bodyBytes, _ := json.Marshal(WatchIncidentRequest{URL: "https://hooks.example.org/watch"})
+			w := makeRequest(router, "POST", fmt.Sprintf("/api/v1/incidents/%s/watch", uuid.New()), bytes.NewBuffer(bodyBytes), map[string]string{"X-API-Key": "test-api-key"})
+
+			assert.Equal(t, tt.code, w.Code)
+		})
+	}
+}
+
+func TestUnwatchIncident(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	incidentID, watcherID := uuid.New(), uuid.New()
+
+	// Метка и права ключа передаются в сервис для проверки владельца наблюдателя
+	mockService.EXPECT().UnwatchIncident(gomock.Any(), incidentID, watcherID, apiKeyLabel("test-api-key"), false).Return(nil).Times(1)
+	mockService.EXPECT().UnwatchIncident(gomock.Any(), incidentID, watcherID, apiKeyLabel("test-admin-key"), true).Return(nil).Times(1)
+	mockService.EXPECT().UnwatchIncident(gomock.Any(), incidentID, gomock.Not(watcherID), gomock.Any(), gomock.Any()).
+		Return(fmt.Errorf("service: could not delete incident watcher: %w", service.ErrWatcherNotFound)).Times(1)
+
+	w := makeRequest(router, "DELETE", fmt.Sprintf("/api/v1/incidents/%s/watch/%s", incidentID, watcherID), nil, map[string]string{"X-API-Key": "test-api-key"})
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	w = makeRequest(router, "DELETE", fmt.Sprintf("/api/v1/incidents/%s/watch/%s", incidentID, watcherID), nil, map[string]string{"X-API-Key": "test-admin-key"})
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	w = makeRequest(router, "DELETE", fmt.Sprintf("/api/v1/incidents/%s/watch/%s", incidentID, uuid.New()), nil, map[string]string{"X-API-Key": "test-api-key"})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestUnwatchIncident_Forbidden(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	incidentID, watcherID := uuid.New(), uuid.New()
+
+	mockService.EXPECT().UnwatchIncident(gomock.Any(), incidentID, watcherID, gomock.Any(), false).Return(service.ErrWatcherForbidden).Times(1)
+
+	w := makeRequest(router, "DELETE", fmt.Sprintf("/api/v1/incidents/%s/watch/%s", incidentID, watcherID), nil, map[string]string{"X-API-Key": "test-api-key"})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestCreateAttachmentUploadURL_Created(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	incidentID := uuid.New()
+	presigned := &models.PresignedAttachment{
+		Attachment: &models.IncidentAttachment{ID: uuid.New(), IncidentID: incidentID, ObjectKey: "incidents/x/y.jpg", Filename: "photo.jpg", ContentType: "image/jpeg"},
+		URL:        "https://storage.example/put/incidents/x/y.jpg",
+		ExpiresAt:  time.Now().Add(15 * time.Minute),
+	}
+
+	mockService.EXPECT().CreateAttachmentUploadURL(gomock.Any(), incidentID, "photo.jpg", "image/jpeg", apiKeyLabel("test-api-key")).
+		Return(presigned, nil).Times(1)
+
+	bodyBytes, _ := json.Marshal(AttachmentUploadRequest{Filename: "photo.jpg", ContentType: "image/jpeg"})
+	w := makeRequest(router, "POST", fmt.Sprintf("/api/v1/incidents/%s/attachments/upload-url", incidentID), bytes.NewBuffer(bodyBytes), map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var resp AttachmentUploadResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, presigned.URL, resp.UploadURL)
+	assert.Equal(t, "PUT", resp.Method)
+	assert.Equal(t, "image/jpeg", resp.Headers["Content-Type"])
+}
+
+func TestCreateAttachmentUploadURL_Disabled(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+
+	mockService.EXPECT().CreateAttachmentUploadURL(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil, service.ErrAttachmentsDisabled).Times(1)
+
+	bodyBytes, _ := json.Marshal(AttachmentUploadRequest{Filename: "photo.jpg", ContentType: "image/jpeg"})
+	w := makeRequest(router, "POST", fmt.Sprintf("/api/v1/incidents/%s/attachments/upload-url", uuid.New()), bytes.NewBuffer(bodyBytes), map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), codeFeatureDisabled)
+}
+
+func TestListIncidentAttachments_IncidentNotFound(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	incidentID := uuid.New()
+
+	mockService.EXPECT().ListIncidentAttachments(gomock.Any(), incidentID).
+		Return(nil, fmt.Errorf("service: could not get incident: %w", service.ErrIncidentNotFound)).Times(1)
+
+	w := makeRequest(router, "GET", fmt.Sprintf("/api/v1/incidents/%s/attachments", incidentID), nil, map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestGetIncidentViews_Success(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	incidentID := uuid.New()
+
+	mockService.EXPECT().GetIncidentViews(gomock.Any(), incidentID).Return(int64(42), nil).Times(1)
+
+	w := makeRequest(router, "GET", "/api/v1/incidents/"+incidentID.String()+"/views", nil, map[string]string{"X-API-Key": "test-admin-key"})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, fmt.Sprintf(`{"incident_id":%q,"views":42}`, incidentID), w.Body.String())
+}
+
+func TestGetIncidentViews_RequiresAdmin(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+
+	mockService.EXPECT().GetIncidentViews(gomock.Any(), gomock.Any()).Times(0)
+
+	w := makeRequest(router, "GET", "/api/v1/incidents/"+uuid.NewString()+"/views", nil, map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestGetIncidentViews_NotFound(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	incidentID := uuid.New()
+
+	mockService.EXPECT().GetIncidentViews(gomock.Any(), incidentID).Return(int64(0), fmt.Errorf("service: %w", service.ErrIncidentNotFound)).Times(1)
+
+	w := makeRequest(router, "GET", "/api/v1/incidents/"+incidentID.String()+"/views", nil, map[string]string{"X-API-Key": "test-admin-key"})
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestReevaluateLocationChecks_Success(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	incidentID := uuid.New()
+
+	expected := models.LocationCheckReevaluationQuery{From: from, To: to, AfterID: 10, Limit: 1, Notify: true}
+	mockService.EXPECT().ReevaluateLocationChecks(gomock.Any(), expected).Return(&models.LocationCheckReevaluation{
+		Checked:           25,
+		Discrepancies:     []*models.LocationCheck{{ID: 12, UserID: "user-1", IncidentIDs: []uuid.UUID{incidentID}}},
+		NextAfterID:       12,
+		WebhooksPublished: 1,
+	}, nil).Times(1)
+
+	w := makeRequest(router, "POST", "/api/v1/admin/location-checks/reevaluate?from=2026-03-01T00:00:00Z&to=2026-03-02T00:00:00Z&after_id=10&limit=1&notify=true",
+		nil, map[string]string{"X-API-Key": "test-admin-key"})
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp LocationCheckReevaluationResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 25, resp.Checked)
+	require.Len(t, resp.Discrepancies, 1)
+	assert.Equal(t, []uuid.UUID{incidentID}, resp.Discrepancies[0].IncidentIDs)
+	require.NotNil(t, resp.NextAfterID)
+	assert.Equal(t, int64(12), *resp.NextAfterID)
+	assert.Equal(t, 1, resp.WebhooksPublished)
+}
+
+func TestReevaluateLocationChecks_InvalidQuery(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+
+	mockService.EXPECT().ReevaluateLocationChecks(gomock.Any(), gomock.Any()).Times(0)
+
+	for _, query := range []string{"", "?from=yesterday", "?from=2026-03-01T00:00:00Z&limit=0", "?from=2026-03-01T00:00:00Z&after_id=-1", "?from=2026-03-01T00:00:00Z&notify=maybe"} {
+		w := makeRequest(router, "POST", "/api/v1/admin/location-checks/reevaluate"+query, nil, map[string]string{"X-API-Key": "test-admin-key"})
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
+
+func TestReevaluateLocationChecks_RequiresAdmin(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+
+	mockService.EXPECT().ReevaluateLocationChecks(gomock.Any(), gomock.Any()).Times(0)
+
+	w := makeRequest(router, "POST", "/api/v1/admin/location-checks/reevaluate?from=2026-03-01T00:00:00Z", nil, map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestOpenAPISpec(t *testing.T) {
+	spec := &swag.Spec{
+		Host:             "localhost:8080",
+		BasePath:         "/api/v1",
+		InfoInstanceName: "test",
+		SwaggerTemplate:  `{"swagger": "2.0", "host": "{{.Host}}", "basePath": "{{.BasePath}}", "paths": {}}`,
+		LeftDelim:        "{{",
+		RightDelim:       "}}",
+	}
+	gin.SetMode(gin.TestMode)
+
+	t.Run("host from config", func(t *testing.T) {
+		openAPI, err := NewOpenAPISpec(spec, "api.example.com", "/api/v1")
+		require.NoError(t, err)
+		router := gin.New()
+		openAPI.RegisterRoutes(router)
+
+		w := makeRequest(router, "GET", "/openapi.json", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+		var doc map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &doc))
+		assert.Equal(t, "api.example.com", doc["host"])
+		assert.Equal(t, "/api/v1", doc["basePath"])
+
+		w = makeRequest(router, "GET", "/openapi.yaml", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/yaml; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Body.String(), "host: api.example.com")
+		assert.Contains(t, w.Body.String(), "swagger: \"2.0\"")
+	})
+
+	t.Run("no host", func(t *testing.T) {
+		openAPI, err := NewOpenAPISpec(spec, "", "/api/v1")
+		require.NoError(t, err)
+		router := gin.New()
+		openAPI.RegisterRoutes(router)
+
+		w := makeRequest(router, "GET", "/openapi.json", nil)
+		var doc map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &doc))
+		assert.NotContains(t, doc, "host")
+	})
+}
+
+func TestCheckLocation_Success_Danger(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	reqBody := LocationCheckRequest{
+		UserID:    "user123",
+		Latitude:  50.0,
+		Longitude: 50.0,
+	}
+	incidentsFound := []*models.Incident{
+		{ID: uuid.New(), Name: "Danger Zone A"},
+	}
+
+	mockService.EXPECT().CheckLocation(gomock.Any(), reqBody.UserID, false, reqBody.Latitude, reqBody.Longitude, 0, 0).Return(incidentsFound, len(incidentsFound), nil).Times(1)
+
+	bodyBytes, _ := json.Marshal(reqBody)
+	w := makeRequest(router, "POST", "/api/v1/location/check", bytes.NewBuffer(bodyBytes))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp []IncidentResponse
+	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	require.NoError(t, err)
+	assert.Len(t, resp, 1)
+	assert.Equal(t, incidentsFound[0].Name, resp[0].Name)
+}
+
+func TestCheckLocation_Success_Safe(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	reqBody := LocationCheckRequest{
+		UserID:    "user123",
+		Latitude:  50.0,
+		Longitude: 50.0,
+	}
+	var incidentsFound []*models.Incident // No incidents found
+
+	mockService.EXPECT().CheckLocation(gomock.Any(), reqBody.UserID, false, reqBody.Latitude, reqBody.Longitude, 0, 0).Return(incidentsFound, len(incidentsFound), nil).Times(1)
+
+	bodyBytes, _ := json.Marshal(reqBody)
+	w := makeRequest(router, "POST", "/api/v1/location/check", bytes.NewBuffer(bodyBytes))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp []IncidentResponse
+	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	require.NoError(t, err)
+	assert.Empty(t, resp)
+}
+
+func TestCheckLocation_Truncated(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	reqBody := LocationCheckRequest{UserID: "user123", Latitude: 50.0, Longitude: 50.0}
+	incidentsFound := []*models.Incident{{ID: uuid.New(), Name: "Critical"}, {ID: uuid.New(), Name: "Major"}}
+
+	// Совпало больше инцидентов, чем вернул сервис
+	mockService.EXPECT().CheckLocation(gomock.Any(), reqBody.UserID, false, reqBody.Latitude, reqBody.Longitude, 0, 0).Return(incidentsFound, 30, nil).Times(1)
+
+	bodyBytes, _ := json.Marshal(reqBody)
+	w := makeRequest(router, "POST", "/api/v1/location/check", bytes.NewBuffer(bodyBytes),
+		map[string]string{"Accept": locationCheckMediaType})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "true", w.Header().Get(incidentsTruncatedHeader))
+	assert.Equal(t, "30", w.Header().Get(matchedIncidentsHeader))
+	var resp LocationCheckResultResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.True(t, resp.Truncated)
+	assert.Equal(t, 30, resp.Matched)
+	assert.Len(t, resp.Incidents, 2)
+}
+
+func TestCheckLocation_ObjectResponseByAccept(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	reqBody := LocationCheckRequest{UserID: "user123", Latitude: 50.0, Longitude: 50.0}
+
+	mockService.EXPECT().CheckLocation(gomock.Any(), reqBody.UserID, false, reqBody.Latitude, reqBody.Longitude, 0, 0).Return(nil, 0, nil).Times(1)
+
+	bodyBytes, _ := json.Marshal(reqBody)
+	w := makeRequest(router, "POST", "/api/v1/location/check", bytes.NewBuffer(bodyBytes),
+		map[string]string{"Accept": "application/vnd.geo.location-check+json, application/json;q=0.5"})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"dangerous": false, "incidents": []}`, w.Body.String())
+}
+
+func TestCheckLocation_ObjectResponseByConfig(t *testing.T) {
+	h, mockService, router := newTestHandler(t)
+	h.cfg.LocationCheckResponse = "object"
+	reqBody := LocationCheckRequest{UserID: "user123", Latitude: 50.0, Longitude: 50.0}
+	incidentsFound := []*models.Incident{{ID: uuid.New(), Name: "Danger Zone A"}}
+
+	mockService.EXPECT().CheckLocation(gomock.Any(), reqBody.UserID, false, reqBody.Latitude, reqBody.Longitude, 0, 0).Return(incidentsFound, len(incidentsFound), nil).Times(1)
+
+	bodyBytes, _ := json.Marshal(reqBody)
+	w := makeRequest(router, "POST", "/api/v1/location/check", bytes.NewBuffer(bodyBytes))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp LocationCheckResultResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.True(t, resp.Dangerous)
+	require.Len(t, resp.Incidents, 1)
+	assert.Equal(t, "Danger Zone A", resp.Incidents[0].Name)
+}
+
+func TestCheckLocation_Authenticated(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	reqBody := LocationCheckRequest{
+		UserID:    "user123",
+		Latitude:  50.0,
+		Longitude: 50.0,
+	}
+
+	// Проверка с действительным API-ключом помечается как аутентифицированная
+	mockService.EXPECT().CheckLocation(gomock.Any(), reqBody.UserID, true, reqBody.Latitude, reqBody.Longitude, 0, 0).Return(nil, 0, nil).Times(1)
+
+	bodyBytes, _ := json.Marshal(reqBody)
+	w := makeRequest(router, "POST", "/api/v1/location/check", bytes.NewBuffer(bodyBytes), map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestCheckLocation_InvalidAPIKey(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	reqBody := LocationCheckRequest{
+		UserID:    "user123",
+		Latitude:  50.0,
+		Longitude: 50.0,
+	}
+
+	// Неверный ключ не понижается молча до анонимной проверки
+	mockService.EXPECT().IsRuntimeAPIKey(gomock.Any(), "wrong-key").Return(false, nil).Times(1)
+	mockService.EXPECT().CheckLocation(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	bodyBytes, _ := json.Marshal(reqBody)
+	w := makeRequest(router, "POST", "/api/v1/location/check", bytes.NewBuffer(bodyBytes), map[string]string{"X-API-Key": "wrong-key"})
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestCheckLocation_InvalidJSON(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+
+	mockService.EXPECT().CheckLocation(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	w := makeRequest(router, "POST", "/api/v1/location/check", bytes.NewBufferString(`{"user_id": "u1",`))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid request body")
+}
+
+func TestCheckLocation_ValidationError(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	reqBody := LocationCheckRequest{ // Отсутствует UserID
+		Latitude:  50.0,
+		Longitude: 50.0,
+	}
+
+	mockService.EXPECT().CheckLocation(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	bodyBytes, _ := json.Marshal(reqBody)
+	w := makeRequest(router, "POST", "/api/v1/location/check", bytes.NewBuffer(bodyBytes))
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), "Error:Field validation for 'UserID' failed on the 'required' tag")
+}
+
+func TestCheckLocation_ValidationError_Localized(t *testing.T) {
+	_, _, router := newTestHandler(t)
+	reqBody := LocationCheckRequest{Latitude: 100.0, Longitude: 20.0}
+	body, _ := json.Marshal(reqBody)
+
+	w := makeRequest(router, "POST", "/api/v1/location/check", bytes.NewBuffer(body), map[string]string{"Accept-Language": "ru-RU,ru;q=0.9,en;q=0.8"})
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	var resp ValidationErrorResponse
+	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []FieldError{
+		{Field: "user_id", Tag: "required", Message: "обязательное поле"},
+		{Field: "latitude", Tag: "latitude", Message: "должно быть корректной широтой от -90 до 90"},
+	}, resp.Details)
+}
+
+func TestCreateIncident_ValidationError_DefaultLanguage(t *testing.T) {
+	_, _, router := newTestHandler(t)
+	reqBody := CreateIncidentRequest{
+		Name:         "Test Incident",
+		Latitude:     10.0,
+		Longitude:    20.0,
+		RadiusMeters: 100,
+		MediaURLs:    []string{"not-a-url"},
+		Status:       "closed",
+	}
+	body, _ := json.Marshal(reqBody)
+
+	w := makeRequest(router, "POST", "/api/v1/incidents", bytes.NewBuffer(body), map[string]string{"X-API-Key": "test-api-key", "Accept-Language": "de"})
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	var resp ValidationErrorResponse
+	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []FieldError{
+		{Field: "media_urls[0]", Tag: "url", Message: "must be a valid URL"},
+		{Field: "status", Tag: "oneof", Message: "must be one of: active draft"},
+	}, resp.Details)
+}
+
+func TestCheckLocation_ValidationError_UnsupportedLanguageUsesConfigDefault(t *testing.T) {
+	h, _, router := newTestHandler(t)
+	h.cfg.DefaultLanguage = "ru"
+	reqBody := LocationCheckRequest{Latitude: 100.0, Longitude: 20.0}
+	body, _ := json.Marshal(reqBody)
+
+	w := makeRequest(router, "POST", "/api/v1/location/check", bytes.NewBuffer(body), map[string]string{"Accept-Language": "de"})
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	var resp ValidationErrorResponse
+	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []FieldError{
+		{Field: "user_id", Tag: "required", Message: "обязательное поле"},
+		{Field: "latitude", Tag: "latitude", Message: "должно быть корректной широтой от -90 до 90"},
+	}, resp.Details)
+}
+
+func TestCheckLocation_ServiceError(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	reqBody := LocationCheckRequest{
+		UserID:    "user123",
+		Latitude:  50.0,
+		Longitude: 50.0,
+	}
+	serviceError := errors.New("failed to check location")
+
+	mockService.EXPECT().CheckLocation(gomock.Any(), reqBody.UserID, false, reqBody.Latitude, reqBody.Longitude, 0, 0).Return(nil, 0, serviceError).Times(1)
+
+	bodyBytes, _ := json.Marshal(reqBody)
+	w := makeRequest(router, "POST", "/api/v1/location/check", bytes.NewBuffer(bodyBytes))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), "internal server error")
+}
+
+func TestCheckLocation_QuotaExceeded(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	reqBody := LocationCheckRequest{
+		UserID:    "user123",
+		Latitude:  50.0,
+		Longitude: 50.0,
+	}
+	quotaErr := fmt.Errorf("service: check location: %w", &service.RateLimitError{Err: service.ErrCheckQuotaExceeded, RetryAfter: 1500 * time.Millisecond})
+
+	mockService.EXPECT().CheckLocation(gomock.Any(), reqBody.UserID, false, reqBody.Latitude, reqBody.Longitude, 0, 0).Return(nil, 0, quotaErr).Times(1)
+
+	bodyBytes, _ := json.Marshal(reqBody)
+	w := makeRequest(router, "POST", "/api/v1/location/check", bytes.NewBuffer(bodyBytes))
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "2", w.Header().Get("Retry-After"))
+	assert.Contains(t, w.Body.String(), codeCheckQuotaExceeded)
+}
+
+func TestListPublicIncidents_NoAPIKey(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	incidents := []*models.Incident{{
+		ID:           uuid.New(),
+		Name:         "Flood",
+		Latitude:     55.75,
+		Longitude:    37.61,
+		RadiusMeters: 500,
+		Status:       models.StatusActive,
+		Source:       models.SourceSensor,
+		ExternalID:   "feed-42",
+		Public:       true,
+		CreatedBy:    "key_1a2b3c4d",
+	}}
+
+	mockService.EXPECT().ListPublicIncidents(gomock.Any(), gomock.Any(), 2, 50).Return(incidents, nil).Times(1)
+
+	// Запрос без X-API-Key
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/public/incidents?page=2&pageSize=50", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var body []map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	require.Len(t, body, 1)
+	assert.Equal(t, "Flood", body[0]["name"])
+	assert.NotContains(t, body[0], "external_id")
+	assert.NotContains(t, body[0], "source")
+	assert.NotContains(t, body[0], "status")
+	assert.NotContains(t, body[0], "created_by")
+}
+
+func TestListPublicIncidents_RateLimited(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	limitErr := &service.RateLimitError{Err: service.ErrPublicFeedRateLimited, RetryAfter: 10 * time.Second}
+
+	mockService.EXPECT().ListPublicIncidents(gomock.Any(), gomock.Any(), 1, 10).Return(nil, limitErr).Times(1)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/public/incidents", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "10", w.Header().Get("Retry-After"))
+	assert.Contains(t, w.Body.String(), codeRateLimited)
+}
+
+func TestGetUserLocationHistory_Success(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	incidentID := uuid.New()
+	checks := []*models.LocationCheck{
+		{ID: 2, UserID: "user123", IsDangerous: true, IncidentIDs: []uuid.UUID{incidentID}},
+		{ID: 1, UserID: "user123", IsDangerous: false},
+	}
+
+	mockService.EXPECT().GetUserLocationHistory(gomock.Any(), "user123", 1, 10).Return(checks, nil).Times(1)
+
+	w := makeRequest(router, "GET", "/api/v1/users/user123/checks", nil, map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp []LocationCheckResponse
+	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	require.NoError(t, err)
+	require.Len(t, resp, 2)
+	assert.Equal(t, []uuid.UUID{incidentID}, resp[0].IncidentIDs)
+	assert.Empty(t, resp[1].IncidentIDs)
+}
+
+func TestAddUserLocation_Success(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	locationID := uuid.New()
+	reqBody := UserLocationRequest{Label: "home", Latitude: 55.75, Longitude: 37.61}
+
+	mockService.EXPECT().
+		AddUserLocation(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, location *models.UserLocation) error {
+			assert.Equal(t, "user123", location.UserID)
+			assert.Equal(t, "home", location.Label)
+			location.ID = locationID
+			return nil
+		}).Times(1)
+
+	body, _ := json.Marshal(reqBody)
+	w := makeRequest(router, "POST", "/api/v1/users/user123/locations", bytes.NewBuffer(body), map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var resp UserLocationResponse
+	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	require.NoError(t, err)
+	assert.Equal(t, locationID, resp.ID)
+	assert.Equal(t, "home", resp.Label)
+}
+
+func TestAddUserLocation_DuplicateLabel(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	reqBody := UserLocationRequest{Label: "home", Latitude: 55.75, Longitude: 37.61}
+
+	mockService.EXPECT().
+		AddUserLocation(gomock.Any(), gomock.Any()).
+		Return(fmt.Errorf("service: could not save user location: %w", service.ErrUserLocationExists)).Times(1)
+
+	body, _ := json.Marshal(reqBody)
+	w := makeRequest(router, "POST", "/api/v1/users/user123/locations", bytes.NewBuffer(body), map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestDeleteUserLocation_NotFound(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	locationID := uuid.New()
+
+	mockService.EXPECT().
+		DeleteUserLocation(gomock.Any(), "user123", locationID).
+		Return(service.ErrUserLocationNotFound).Times(1)
+
+	w := makeRequest(router, "DELETE", fmt.Sprintf("/api/v1/users/user123/locations/%s", locationID), nil, map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestGetUserAlerts_Success(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	incidentID := uuid.New()
+	alerts := []*models.LocationAlert{
+		{
+			Location:  &models.UserLocation{ID: uuid.New(), UserID: "user123", Label: "home"},
+			Incidents: []*models.Incident{{ID: incidentID, Name: "Пожар", Status: "active"}},
+		},
+	}
+
+	mockService.EXPECT().GetUserAlerts(gomock.Any(), "user123").Return(alerts, nil).Times(1)
+
+	w := makeRequest(router, "GET", "/api/v1/users/user123/alerts", nil, map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp []UserAlertResponse
+	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	require.NoError(t, err)
+	require.Len(t, resp, 1)
+	assert.Equal(t, "home", resp[0].Label)
+	require.Len(t, resp[0].Incidents, 1)
+	assert.Equal(t, incidentID, resp[0].Incidents[0].ID)
+}
+
+func TestGetUserStatus_Danger(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	incidentID := uuid.New()
+	status := &models.UserStatus{
+		LastCheck: &models.LocationCheck{UserID: "user123", Latitude: 55.75, Longitude: 37.61},
+		Incidents: []*models.Incident{{ID: incidentID, Name: "Пожар", Status: "active"}},
+	}
+
+	mockService.EXPECT().GetUserStatus(gomock.Any(), "user123").Return(status, nil).Times(1)
+
+	w := makeRequest(router, "GET", "/api/v1/users/user123/status", nil, map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp UserStatusResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "danger", resp.Status)
+	assert.True(t, resp.IsDangerous)
+	assert.Equal(t, 55.75, resp.Latitude)
+	require.Len(t, resp.Incidents, 1)
+	assert.Equal(t, incidentID, resp.Incidents[0].ID)
+}
+
+func TestGetUserStatus_NoChecks(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+
+	mockService.EXPECT().GetUserStatus(gomock.Any(), "user123").Return(nil, fmt.Errorf("wrapped: %w", service.ErrNoLocationCheck)).Times(1)
+
+	w := makeRequest(router, "GET", "/api/v1/users/user123/status", nil, map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestGetStats_Success(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	expectedCount := 123
+
+	mockService.EXPECT().GetStats(gomock.Any()).Return(expectedCount, nil).Times(1)
+
+	w := makeRequest(router, "GET", "/api/v1/incidents/stats", nil, map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp StatsResponse
+	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	require.NoError(t, err)
+	assert.Equal(t, expectedCount, resp.UserCount)
+}
+
+func TestGetStats_ServiceError(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	serviceError := errors.New("failed to get stats")
+
+	mockService.EXPECT().GetStats(gomock.Any()).Return(0, serviceError).Times(1)
+
+	w := makeRequest(router, "GET", "/api/v1/incidents/stats", nil, map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), "internal server error")
+}
+
+func TestGetIncidentImpact_Success(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	incidentID := uuid.New()
+
+	mockService.EXPECT().GetIncidentImpact(gomock.Any(), incidentID).Return(5, nil).Times(1)
+
+	w := makeRequest(router, "GET", fmt.Sprintf("/api/v1/incidents/%s/impact", incidentID), nil, map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp ImpactResponse
+	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	require.NoError(t, err)
+	assert.Equal(t, incidentID, resp.IncidentID)
+	assert.Equal(t, 5, resp.UserCount)
+	assert.Equal(t, 60, resp.WindowMinutes)
+}
+
+func TestGetIncidentImpact_NotFound(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	incidentID := uuid.New()
+
+	mockService.EXPECT().GetIncidentImpact(gomock.Any(), incidentID).Return(0, fmt.Errorf("wrapped: %w", service.ErrIncidentNotFound)).Times(1)
+
+	w := makeRequest(router, "GET", fmt.Sprintf("/api/v1/incidents/%s/impact", incidentID), nil, map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "incident not found")
+}
+
+func TestGetOverlappingIncidents_Success(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	incidentID := uuid.New()
+	overlaps := []*models.IncidentOverlap{
+		{Incident: &models.Incident{ID: uuid.New(), Name: "Соседняя зона"}, OverlapAreaSqMeters: 1500.5},
+	}
+
+	mockService.EXPECT().GetOverlappingIncidents(gomock.Any(), incidentID).Return(overlaps, nil).Times(1)
+
+	w := makeRequest(router, "GET", fmt.Sprintf("/api/v1/incidents/%s/overlapping", incidentID), nil, map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp []OverlappingIncidentResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp, 1)
+	assert.Equal(t, overlaps[0].Incident.ID, resp[0].Incident.ID)
+	assert.Equal(t, 1500.5, resp[0].OverlapAreaSqMeters)
+}
+
+func TestGetOverlappingIncidents_NotFound(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	incidentID := uuid.New()
+
+	mockService.EXPECT().GetOverlappingIncidents(gomock.Any(), incidentID).Return(nil, fmt.Errorf("wrapped: %w", service.ErrIncidentNotFound)).Times(1)
+
+	w := makeRequest(router, "GET", fmt.Sprintf("/api/v1/incidents/%s/overlapping", incidentID), nil, map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestApproveIncident_Success(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	incidentID := uuid.New()
+	approved := &models.Incident{ID: incidentID, Name: "Draft", Status: "active"}
+
+	mockService.EXPECT().ApproveIncident(gomock.Any(), incidentID, apiKeyLabel("test-admin-key")).Return(approved, nil).Times(1)
+
+	w := makeRequest(router, "POST", fmt.Sprintf("/api/v1/incidents/%s/approve", incidentID), nil, map[string]string{"X-API-Key": "test-admin-key"})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp IncidentResponse
+	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	require.NoError(t, err)
+	assert.Equal(t, "active", resp.Status)
+}
+
+func TestApproveIncident_RequiresAdmin(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	incidentID := uuid.New()
+
+	mockService.EXPECT().ApproveIncident(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	w := makeRequest(router, "POST", fmt.Sprintf("/api/v1/incidents/%s/approve", incidentID), nil, map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestRejectIncident_NotDraft(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	incidentID := uuid.New()
+
+	mockService.EXPECT().RejectIncident(gomock.Any(), incidentID, gomock.Any()).Return(nil, fmt.Errorf("wrapped: %w", service.ErrStatusConflict)).Times(1)
+
+	w := makeRequest(router, "POST", fmt.Sprintf("/api/v1/incidents/%s/reject", incidentID), nil, map[string]string{"X-API-Key": "test-admin-key"})
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "not in draft status")
+}
+
+func TestEscalateIncident_Success(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	incidentID := uuid.New()
+	escalated := &models.Incident{ID: incidentID, Name: "Fire", Status: models.StatusActive, Severity: models.SeverityMajor}
+
+	mockService.EXPECT().EscalateIncident(gomock.Any(), incidentID, apiKeyLabel("test-api-key")).Return(escalated, nil).Times(1)
+
+	w := makeRequest(router, "POST", fmt.Sprintf("/api/v1/incidents/%s/escalate", incidentID), nil, map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp IncidentResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "major", resp.Severity)
+}
+
+func TestEscalateIncident_AlreadyCritical(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	incidentID := uuid.New()
+
+	mockService.EXPECT().EscalateIncident(gomock.Any(), incidentID, gomock.Any()).Return(nil, fmt.Errorf("wrapped: %w", service.ErrSeverityConflict)).Times(1)
+
+	w := makeRequest(router, "POST", fmt.Sprintf("/api/v1/incidents/%s/escalate", incidentID), nil, map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "severity cannot be raised")
+}
+
+func TestFeatureGate_Disabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := mocks.NewMockIncidentService(ctrl)
+	mockService.EXPECT().RecordAPIKeyUsage(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockService.EXPECT().FeatureEnabled(gomock.Any(), service.FeatureClusters).Return(false).Times(1)
+	mockService.EXPECT().ClusterIncidents(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	logger := logrus.New()
+	logger.SetOutput(&bytes.Buffer{})
+	handler := NewHandler(mockService, logger, &config.Config{APIKeys: []string{"test-api-key"}})
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	handler.RegisterRoutes(router.Group("/api/v1"))
+
+	w := makeRequest(router, "GET", "/api/v1/incidents/clusters?min_lat=55&min_lon=37&max_lat=56&max_lon=38&zoom=10", nil, map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), codeFeatureDisabled)
+}
+
+func TestGetCapabilities(t *testing.T) {
+	h, _, router := newTestHandler(t)
+	h.cfg.UserCheckQuotaPerMinute = 30
+	h.cfg.PublicFeedRateLimitPerMinute = 60
+	h.cfg.IncidentCategoryRules = map[string]config.CategoryRule{
+		"fire":     {MaxRadiusMeters: 5000, DefaultRadiusMeters: 500},
+		"chemical": {MinRadiusMeters: 100, MaxRadiusMeters: 20000},
+	}
+
+	w := makeRequest(router, "GET", "/api/v1/system/capabilities", nil)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "public, max-age=60", w.Header().Get("Cache-Control"))
+	var resp CapabilitiesResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, service.MaxPageSize, resp.MaxPageSize)
+	assert.Equal(t, []CategoryCapabilityResponse{
+		{Name: "chemical", MinRadiusMeters: 100, MaxRadiusMeters: 20000},
+		{Name: "fire", MaxRadiusMeters: 5000, DefaultRadiusMeters: 500},
+	}, resp.Categories)
+	assert.Equal(t, []string{"minor", "moderate", "major", "critical"}, resp.Severities)
+	assert.Contains(t, resp.Statuses, "draft")
+	assert.Equal(t, []string{"official", "crowdsourced", "sensor"}, resp.Sources)
+	assert.Len(t, resp.Features, len(service.Features))
+	assert.True(t, resp.Features[service.FeaturePublicFeed])
+	assert.Equal(t, 30, resp.UserCheckQuotaPerMinute)
+	assert.Equal(t, webhook.SchemaVersion, resp.WebhookSchemaVersion)
+}
+
+func TestAddAPIKey_Success(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+
+	mockService.EXPECT().AddAPIKey(gomock.Any(), "").Return("generated-key-0001", nil).Times(1)
+
+	w := makeRequest(router, "POST", "/api/v1/admin/api-keys", nil, map[string]string{"X-API-Key": "test-admin-key"})
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var resp APIKeyResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "generated-key-0001", resp.Key)
+	assert.Equal(t, apiKeyLabel("generated-key-0001"), resp.Label)
+}
+
+func TestAddAPIKey_RequiresAdmin(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+
+	mockService.EXPECT().AddAPIKey(gomock.Any(), gomock.Any()).Times(0)
+
+	w := makeRequest(router, "POST", "/api/v1/admin/api-keys", nil, map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestRevokeAPIKey_NotFound(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+
+	mockService.EXPECT().RevokeAPIKey(gomock.Any(), "unknown-key").Return(fmt.Errorf("service: %w", service.ErrAPIKeyNotFound)).Times(1)
+
+	w := makeRequest(router, "POST", "/api/v1/admin/api-keys/revoke", bytes.NewBufferString(`{"key":"unknown-key"}`), map[string]string{"X-API-Key": "test-admin-key"})
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestGetWebhookQueue_Success(t *testing.T) {
+	h, mockService, router := newTestHandler(t)
+	h.cfg.WebhookQueueBackend = "list"
+	h.cfg.WebhookQueueAlarmThreshold = 1000
+
+	mockService.EXPECT().GetWebhookQueueDepth(gomock.Any()).Return(int64(42), nil).Times(1)
+
+	w := makeRequest(router, "GET", "/api/v1/admin/webhooks/queue", nil, map[string]string{"X-API-Key": "test-admin-key"})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp WebhookQueueResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, WebhookQueueResponse{Backend: "list", Depth: 42, AlarmThreshold: 1000}, resp)
+}
+
+func TestGetWebhookCircuits_Success(t *testing.T) {
+	h, mockService, router := newTestHandler(t)
+	h.cfg.WebhookCircuitFailureThreshold = 5
+
+	openedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	circuits := []webhook.CircuitStatus{
+		{URL: "https://a.example.com/hook", State: webhook.CircuitOpen, ConsecutiveFailures: 5, OpenedAt: &openedAt},
+		{URL: "https://b.example.com/hook", State: webhook.CircuitClosed},
+	}
+	mockService.EXPECT().GetWebhookCircuits(gomock.Any()).Return(circuits, int64(12), nil).Times(1)
+
+	w := makeRequest(router, "GET", "/api/v1/admin/webhooks/circuits", nil, map[string]string{"X-API-Key": "test-admin-key"})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{
+		"circuits": [
+			{"url": "https://a.example.com/hook", "state": "open", "consecutive_failures": 5, "opened_at": "2024-03-01T12:00:00Z", "destination": "8d4805bf7a0b65ea"},
+			{"url": "https://b.example.com/hook", "state": "closed", "consecutive_failures": 0, "destination": "7a79e60dc5f9dbcf"}
+		],
+		"dead_letter_depth": 12,
+		"failure_threshold": 5
+	}`, w.Body.String())
+}
+
+func TestGetWebhookCircuits_RequiresAdmin(t *testing.T) {
+	_, _, router := newTestHandler(t)
+
+	w := makeRequest(router, "GET", "/api/v1/admin/webhooks/circuits", nil, map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestInvalidateCaches_Success(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+
+	mockService.EXPECT().InvalidateCaches(gomock.Any()).Return(int64(17), nil).Times(1)
+
+	w := makeRequest(router, "POST", "/api/v1/admin/cache/invalidate", nil, map[string]string{"X-API-Key": "test-admin-key"})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"keys_removed":17}`, w.Body.String())
+}
+
+func TestInvalidateCaches_RequiresAdmin(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+
+	mockService.EXPECT().InvalidateCaches(gomock.Any()).Times(0)
+
+	w := makeRequest(router, "POST", "/api/v1/admin/cache/invalidate", nil, map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestStrictQueryParams_RejectsUnknown(t *testing.T) {
+	h, mockService, router := newTestHandler(t)
+	h.cfg.StrictQueryParams = true
+
+	mockService.EXPECT().ListIncidents(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	w := makeRequest(router, "GET", "/api/v1/incidents?page=1&pagesize=5&sortBy=name", nil, map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"error":"unknown query parameters: pagesize, sortBy"}`, w.Body.String())
+}
+
+func TestStrictQueryParams_AllowsKnown(t *testing.T) {
+	h, mockService, router := newTestHandler(t)
+	h.cfg.StrictQueryParams = true
+	incidentID := uuid.New()
+
+	mockService.EXPECT().GetIncident(gomock.Any(), incidentID).Return(&models.Incident{ID: incidentID}, nil).Times(1)
+	mockService.EXPECT().RecordIncidentView(incidentID).Times(1)
+
+	w := makeRequest(router, "GET", "/api/v1/incidents/"+incidentID.String()+"?fields=name", nil, map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestStrictQueryParams_AllowsReevaluationQuery(t *testing.T) {
+	h, mockService, router := newTestHandler(t)
+	h.cfg.StrictQueryParams = true
+
+	mockService.EXPECT().ReevaluateLocationChecks(gomock.Any(), gomock.Any()).Return(&models.LocationCheckReevaluation{}, nil).Times(1)
+
+	w := makeRequest(router, "POST", "/api/v1/admin/location-checks/reevaluate?from=2026-03-01T00:00:00Z&to=2026-03-02T00:00:00Z&after_id=10&limit=1&notify=false",
+		nil, map[string]string{"X-API-Key": "test-admin-key"})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestStrictQueryParams_DisabledIgnoresUnknown(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	incidentID := uuid.New()
+
+	mockService.EXPECT().GetIncident(gomock.Any(), incidentID).Return(&models.Incident{ID: incidentID}, nil).Times(1)
+	mockService.EXPECT().RecordIncidentView(incidentID).Times(1)
+
+	w := makeRequest(router, "GET", "/api/v1/incidents/"+incidentID.String()+"?feilds=name", nil, map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestHealthCheck_Success(t *testing.T) {
+	_, _, router := newTestHandler(t)
+
+	w := makeRequest(router, "GET", "/api/v1/system/health", nil)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"status":"ok"`)
+}
+
+func TestAPIKeyAuthMiddleware_Success(t *testing.T) {
+	// Создаем Gin-роутер и добавляем middleware
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	logger := logrus.New()
+	logger.SetOutput(&bytes.Buffer{})
+
+	cfg := &config.Config{
+		APIKeys: []string{"valid-key"},
+	}
+
+	router.Use(APIKeyAuthMiddleware(cfg, logger, nil))
+	router.GET("/test", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	w := makeRequest(router, "GET", "/test", nil, map[string]string{"X-API-Key": "valid-key"})
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestAPIKeyAuthMiddleware_MissingKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	logger := logrus.New()
+	logger.SetOutput(&bytes.Buffer{})
+
+	cfg := &config.Config{
+		APIKeys: []string{"valid-key"},
+	}
+
+	router.Use(APIKeyAuthMiddleware(cfg, logger, nil))
+	router.GET("/test", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	w := makeRequest(router, "GET", "/test", nil) // Нет API ключа
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), "API key required")
+}
+
+func TestAPIKeyAuthMiddleware_InvalidKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	logger := logrus.New()
+	logger.SetOutput(&bytes.Buffer{})
+
+	cfg := &config.Config{
+		APIKeys: []string{"valid-key"},
+	}
+
+	router.Use(APIKeyAuthMiddleware(cfg, logger, nil))
+	router.GET("/test", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	w := makeRequest(router, "GET", "/test", nil, map[string]string{"X-API-Key": "invalid-key"})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), "Invalid API key")
+}
+
+func TestAPIKeyAuthMiddleware_RuntimeKey(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+
+	mockService.EXPECT().IsRuntimeAPIKey(gomock.Any(), "runtime-key-0001").Return(true, nil).Times(1)
+	mockService.EXPECT().IncidentsLastModified(gomock.Any()).Return(time.Time{}, nil).Times(1)
+	expectSnapshotTime(mockService)
+	mockService.EXPECT().ListIncidents(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return([]*models.Incident{}, nil).Times(1)
+
+	w := makeRequest(router, "GET", "/api/v1/incidents", nil, map[string]string{"X-API-Key": "runtime-key-0001"})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestAPIKeyAuthMiddleware_RuntimeKeyUnavailable(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+
+	mockService.EXPECT().IsRuntimeAPIKey(gomock.Any(), "runtime-key-0001").Return(false, errors.New("redis down")).Times(1)
+
+	w := makeRequest(router, "GET", "/api/v1/incidents", nil, map[string]string{"X-API-Key": "runtime-key-0001"})
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestAccessLogMiddleware_LogsRequest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	logger, hook := test.NewNullLogger()
+
+	cfg := &config.Config{
+		APIKeys: []string{"valid-key"},
+	}
+
+	router.Use(RequestIDMiddleware(), AccessLogMiddleware(logger))
+	router.GET("/test", APIKeyAuthMiddleware(cfg, logger, nil), func(c *gin.Context) {
 		c.Status(http.StatusOK)
 	})
 
-	w := makeRequest(router, "GET", "/test", nil, map[string]string{"X-API-Key": "invalid-key"})
-	assert.Equal(t, http.StatusUnauthorized, w.Code)
-	assert.Contains(t, w.Body.String(), "Invalid API key")
+	w := makeRequest(router, "GET", "/test", nil, map[string]string{"X-API-Key": "valid-key", "X-Request-ID": "req-1"})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "req-1", w.Header().Get("X-Request-ID"))
+
+	entry := hook.LastEntry()
+	require.NotNil(t, entry)
+	assert.Equal(t, logrus.InfoLevel, entry.Level)
+	assert.Equal(t, "GET", entry.Data["method"])
+	assert.Equal(t, "/test", entry.Data["path"])
+	assert.Equal(t, http.StatusOK, entry.Data["status"])
+	assert.Equal(t, "req-1", entry.Data["request_id"])
+	assert.Equal(t, apiKeyLabel("valid-key"), entry.Data["api_key"])
+	assert.NotContains(t, entry.Data["api_key"], "valid-key")
+}
+
+func TestAccessLogMiddleware_SkipsHealth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	logger, hook := test.NewNullLogger()
+
+	router.Use(AccessLogMiddleware(logger, "/api/v1/system/health"))
+	router.GET("/api/v1/system/health", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	w := makeRequest(router, "GET", "/api/v1/system/health", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, hook.AllEntries())
+}
+
+func TestValidateIncident_ReturnsGeometry(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	reqBody := CreateIncidentRequest{Name: "Test Incident", Latitude: 10.0, Longitude: 20.0, RadiusMeters: 100}
+
+	mockService.EXPECT().ValidateIncident(gomock.Any(), gomock.Any()).
+		Return(&models.GeofenceValidation{
+			Valid:            true,
+			AreaSquareMeters: 31393.5,
+			Bounds:           geo.BBox{MinLat: 9.999, MinLon: 19.999, MaxLat: 10.001, MaxLon: 20.001},
+		}, nil).Times(1)
+	mockService.EXPECT().CreateIncident(gomock.Any(), gomock.Any()).Times(0)
+
+	bodyBytes, _ := json.Marshal(reqBody)
+	w := makeRequest(router, "POST", "/api/v1/incidents/validate", bytes.NewBuffer(bodyBytes), map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response GeofenceValidationResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.True(t, response.Valid)
+	assert.Empty(t, response.Errors)
+	assert.InDelta(t, 31393.5, response.AreaSquareMeters, 0.01)
+	assert.Equal(t, 20.001, response.Bounds.MaxLon)
+}
+
+func TestPreviewIncidentImpact_Success(t *testing.T) {
+	h, mockService, router := newTestHandler(t)
+	h.cfg.StatsTimeWindowMinutes = 60
+
+	mockService.EXPECT().PreviewIncidentImpact(gomock.Any(), 55.75, 37.61, 500).Return(42, nil).Times(1)
+
+	body := bytes.NewBufferString(`{"latitude": 55.75, "longitude": 37.61, "radius_meters": 500}`)
+	w := makeRequest(router, "POST", "/api/v1/incidents/preview-impact", body, map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"user_count": 42, "window_minutes": 60}`, w.Body.String())
+}
+
+func TestPreviewIncidentImpact_ValidationError(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+
+	mockService.EXPECT().PreviewIncidentImpact(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	body := bytes.NewBufferString(`{"latitude": 55.75, "longitude": 37.61}`)
+	w := makeRequest(router, "POST", "/api/v1/incidents/preview-impact", body, map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+func TestValidateIncident_ValidationError(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	reqBody := CreateIncidentRequest{Name: "Test Incident", Latitude: 10.0, Longitude: 20.0}
+
+	mockService.EXPECT().ValidateIncident(gomock.Any(), gomock.Any()).Times(0)
+
+	bodyBytes, _ := json.Marshal(reqBody)
+	w := makeRequest(router, "POST", "/api/v1/incidents/validate", bytes.NewBuffer(bodyBytes), map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), "radius_meters")
+}
+
+func TestValidateIncident_MissingRadius(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	reqBody := CreateIncidentRequest{Name: "Test Incident", Latitude: 10.0, Longitude: 20.0, Category: "leak"}
+	serviceErr := fmt.Errorf("service: radius_meters is required, category %q has no default radius: %w", "leak", service.ErrInvalidIncident)
+
+	mockService.EXPECT().ValidateIncident(gomock.Any(), gomock.Any()).Return(nil, serviceErr).Times(1)
+
+	bodyBytes, _ := json.Marshal(reqBody)
+	w := makeRequest(router, "POST", "/api/v1/incidents/validate", bytes.NewBuffer(bodyBytes), map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), "radius_meters is required")
+}
+
+func TestTimeoutMiddleware_ReturnsGatewayTimeout(t *testing.T) {
+	router := gin.New()
+	router.Use(TimeoutMiddleware(10 * time.Millisecond))
+	router.GET("/slow", func(c *gin.Context) {
+		// Обработчик, как и запросы к хранилищам, завершается по отмене контекста
+		<-c.Request.Context().Done()
+		respondServiceError(c, c.Request.Context().Err(), "internal server error")
+	})
+
+	w := makeRequest(router, "GET", "/slow", nil)
+
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+	assert.Contains(t, w.Body.String(), codeRequestTimeout)
+}
+
+func TestTimeoutMiddleware_HandlerIgnoresContext(t *testing.T) {
+	router := gin.New()
+	router.Use(TimeoutMiddleware(10 * time.Millisecond))
+	router.GET("/slow", func(c *gin.Context) {
+		<-c.Request.Context().Done()
+	})
+
+	w := makeRequest(router, "GET", "/slow", nil)
+
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+}
+
+func TestTimeoutMiddleware_SkipPaths(t *testing.T) {
+	router := gin.New()
+	router.Use(TimeoutMiddleware(time.Millisecond, "/stream"))
+	router.GET("/stream", func(c *gin.Context) {
+		_, hasDeadline := c.Request.Context().Deadline()
+		assert.False(t, hasDeadline)
+		c.Status(http.StatusOK)
+	})
+
+	w := makeRequest(router, "GET", "/stream", nil)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestGetIncidentTimeline_Success(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	expected := models.TimelineQuery{From: from, To: from.AddDate(0, 0, 2), Interval: models.TimelineDay, Status: "active"}
+
+	mockService.EXPECT().GetIncidentTimeline(gomock.Any(), expected).Return([]*models.TimelineBucket{
+		{Start: from, Count: 3},
+		{Start: from.AddDate(0, 0, 1), Count: 0},
+	}, nil).Times(1)
+
+	w := makeRequest(router, "GET", "/api/v1/incidents/timeline?from=2026-03-01T00:00:00Z&to=2026-03-03T00:00:00Z&status=active", nil, map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response TimelineResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, models.TimelineDay, response.Interval)
+	require.Len(t, response.Buckets, 2)
+	assert.Equal(t, 3, response.Buckets[0].Count)
+}
+
+func TestGetIncidentTimeline_InvalidQuery(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	mockService.EXPECT().GetIncidentTimeline(gomock.Any(), gomock.Any()).Times(0)
+
+	for _, query := range []string{
+		"",
+		"?from=yesterday",
+		"?from=2026-03-01T00:00:00Z&interval=week",
+		"?from=2026-03-01T00:00:00Z&source=rumor",
+		"?from=2026-03-01T00:00:00Z&status=archived",
+	} {
+		w := makeRequest(router, "GET", "/api/v1/incidents/timeline"+query, nil, map[string]string{"X-API-Key": "test-api-key"})
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
+
+func TestCreateIncident_CategoryWithoutRadius(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+
+	// Радиус по умолчанию категории подставляет сервис
+	mockService.EXPECT().CreateIncident(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, inc *models.Incident) error {
+			assert.Equal(t, "fire", inc.Category)
+			assert.Zero(t, inc.RadiusMeters)
+			inc.RadiusMeters = 500
+			return nil
+		}).Times(1)
+
+	body := `{"name": "Пожар", "latitude": 10, "longitude": 20, "category": "fire"}`
+	w := makeRequest(router, "POST", "/api/v1/incidents", bytes.NewBufferString(body), map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"radius_meters":500`)
+}
+
+func TestCreateIncidentFromTemplate_Success(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	templateID := uuid.New()
+
+	mockService.EXPECT().
+		CreateIncidentFromTemplate(gomock.Any(), templateID, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ uuid.UUID, incident *models.Incident) error {
+			assert.Equal(t, 55.75, incident.Latitude)
+			assert.Equal(t, 37.61, incident.Longitude)
+			incident.ID = uuid.New()
+			incident.Name = "Зона эвакуации"
+			incident.RadiusMeters = 500
+			incident.Status = models.StatusActive
+			return nil
+		}).Times(1)
+
+	w := makeRequest(router, "POST", fmt.Sprintf("/api/v1/incidents/from-template/%s", templateID),
+		bytes.NewBufferString(`{"latitude":55.75,"longitude":37.61}`), map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var resp IncidentResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "Зона эвакуации", resp.Name)
+	assert.Equal(t, 500, resp.RadiusMeters)
+}
+
+func TestCreateIncidentFromTemplate_NotFound(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	templateID := uuid.New()
+
+	mockService.EXPECT().CreateIncidentFromTemplate(gomock.Any(), templateID, gomock.Any()).
+		Return(fmt.Errorf("service: %w", service.ErrTemplateNotFound)).Times(1)
+
+	w := makeRequest(router, "POST", fmt.Sprintf("/api/v1/incidents/from-template/%s", templateID),
+		bytes.NewBufferString(`{"latitude":55.75,"longitude":37.61}`), map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestCreateIncidentFromTemplate_MissingCoordinates(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+
+	mockService.EXPECT().CreateIncidentFromTemplate(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	w := makeRequest(router, "POST", fmt.Sprintf("/api/v1/incidents/from-template/%s", uuid.New()),
+		bytes.NewBufferString(`{"name":"Пожар"}`), map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+func TestCreateIncidentTemplate_Success(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+
+	mockService.EXPECT().CreateIncidentTemplate(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, template *models.IncidentTemplate) error {
+			template.ID = uuid.New()
+			return nil
+		}).Times(1)
+
+	w := makeRequest(router, "POST", "/api/v1/admin/incident-templates",
+		bytes.NewBufferString(`{"name":"Зона эвакуации","radius_meters":500}`), map[string]string{"X-API-Key": "test-admin-key"})
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var resp IncidentTemplateResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "Зона эвакуации", resp.Name)
+	assert.Equal(t, 500, resp.RadiusMeters)
+}
+
+func TestCreateIncidentTemplate_Conflict(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+
+	mockService.EXPECT().CreateIncidentTemplate(gomock.Any(), gomock.Any()).
+		Return(fmt.Errorf("service: %w", service.ErrTemplateExists)).Times(1)
+
+	w := makeRequest(router, "POST", "/api/v1/admin/incident-templates",
+		bytes.NewBufferString(`{"name":"Зона эвакуации","radius_meters":500}`), map[string]string{"X-API-Key": "test-admin-key"})
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestCreateIncidentTemplate_RequiresAdmin(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+
+	mockService.EXPECT().CreateIncidentTemplate(gomock.Any(), gomock.Any()).Times(0)
+
+	w := makeRequest(router, "POST", "/api/v1/admin/incident-templates",
+		bytes.NewBufferString(`{"name":"Зона эвакуации","radius_meters":500}`), map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestDeleteIncidentTemplate_NotFound(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	templateID := uuid.New()
+
+	mockService.EXPECT().DeleteIncidentTemplate(gomock.Any(), templateID).
+		Return(fmt.Errorf("service: %w", service.ErrTemplateNotFound)).Times(1)
+
+	w := makeRequest(router, "DELETE", fmt.Sprintf("/api/v1/admin/incident-templates/%s", templateID), nil, map[string]string{"X-API-Key": "test-admin-key"})
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestInFlightCounter(t *testing.T) {
+	counter := NewInFlightCounter()
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(counter.Middleware())
+	router.GET("/slow", func(c *gin.Context) {
+		close(started)
+		<-release
+		c.Status(http.StatusOK)
+	})
+
+	done := make(chan struct{})
+	go func() {
+		makeRequest(router, "GET", "/slow", nil)
+		close(done)
+	}()
+	<-started
+	assert.Equal(t, int64(1), counter.Count())
+
+	// Пока запрос не завершен, ожидание прерывается по контексту
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, int64(1), counter.Wait(ctx))
+
+	close(release)
+	<-done
+	assert.Equal(t, int64(0), counter.Wait(context.Background()))
+}
+
+func TestUpdateIncident_StatusConflict(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	incidentID := uuid.New()
+	reqBody := UpdateIncidentRequest{
+		Name:         "Updated Name",
+		Latitude:     11.0,
+		Longitude:    21.0,
+		RadiusMeters: 110,
+		Status:       "active",
+	}
+
+	mockService.EXPECT().UpdateIncident(gomock.Any(), gomock.Any()).
+		Return(fmt.Errorf("service: status transition from resolved to active is not allowed: %w", service.ErrStatusConflict)).Times(1)
+
+	bodyBytes, _ := json.Marshal(reqBody)
+	w := makeRequest(router, "PUT", fmt.Sprintf("/api/v1/incidents/%s", incidentID), bytes.NewBuffer(bodyBytes), map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "from resolved to active")
+}
+
+func TestGetIncidentByExternalID_Success(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	incident := &models.Incident{ID: uuid.New(), Name: "Пожар", ExternalID: "feed-42", Status: models.StatusActive}
+
+	mockService.EXPECT().GetIncidentByExternalID(gomock.Any(), "feed-42").Return(incident, nil).Times(1)
+
+	w := makeRequest(router, "GET", "/api/v1/incidents/by-external/feed-42", nil, map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp IncidentResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, incident.ID, resp.ID)
+	assert.Equal(t, "feed-42", resp.ExternalID)
+}
+
+func TestGetIncidentByExternalID_NotFound(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+
+	mockService.EXPECT().GetIncidentByExternalID(gomock.Any(), "missing").
+		Return(nil, fmt.Errorf("service: %w", service.ErrIncidentNotFound)).Times(1)
+
+	w := makeRequest(router, "GET", "/api/v1/incidents/by-external/missing", nil, map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestCreateIncident_ExternalIDConflict(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+
+	mockService.EXPECT().CreateIncident(gomock.Any(), gomock.Any()).
+		Return(fmt.Errorf("service: %w", service.ErrExternalIDExists)).Times(1)
+
+	body := `{"name":"Пожар","latitude":55.75,"longitude":37.61,"radius_meters":500,"external_id":"feed-42"}`
+	w := makeRequest(router, "POST", "/api/v1/incidents", bytes.NewBufferString(body), map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestCheckLocation_OutsideServiceArea(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	reqBody := LocationCheckRequest{
+		UserID:    "user123",
+		Latitude:  48.85,
+		Longitude: 2.35,
+	}
+
+	mockService.EXPECT().CheckLocation(gomock.Any(), reqBody.UserID, false, reqBody.Latitude, reqBody.Longitude, 0, 0).
+		Return(nil, 0, fmt.Errorf("service: %w", service.ErrCoordinatesNotAllowed)).Times(1)
+
+	bodyBytes, _ := json.Marshal(reqBody)
+	w := makeRequest(router, "POST", "/api/v1/location/check", bytes.NewBuffer(bodyBytes))
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), "service area")
+}
+
+func TestListIncidentVersions_Success(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	incidentID := uuid.New()
+	versions := []*models.IncidentVersion{
+		{IncidentID: incidentID, Version: 2, Incident: &models.Incident{ID: incidentID, Name: "Пожар", Status: models.StatusResolved}},
+		{IncidentID: incidentID, Version: 1, Incident: &models.Incident{ID: incidentID, Name: "Пожар", Status: models.StatusActive}},
+	}
+
+	mockService.EXPECT().ListIncidentVersions(gomock.Any(), incidentID).Return(versions, nil).Times(1)
+
+	w := makeRequest(router, "GET", fmt.Sprintf("/api/v1/incidents/%s/versions", incidentID), nil, map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp []IncidentVersionResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp, 2)
+	assert.Equal(t, 2, resp[0].Version)
+	assert.Equal(t, "resolved", resp[0].Incident.Status)
+}
+
+func TestRevertIncident_Success(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	incidentID := uuid.New()
+
+	mockService.EXPECT().RevertIncident(gomock.Any(), incidentID, 1, apiKeyLabel("test-admin-key")).
+		Return(&models.Incident{ID: incidentID, Name: "Пожар", Status: models.StatusActive}, nil).Times(1)
+
+	w := makeRequest(router, "POST", fmt.Sprintf("/api/v1/incidents/%s/revert/1", incidentID), nil, map[string]string{"X-API-Key": "test-admin-key"})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestRevertIncident_VersionNotFound(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	incidentID := uuid.New()
+
+	mockService.EXPECT().RevertIncident(gomock.Any(), incidentID, 9, gomock.Any()).
+		Return(nil, fmt.Errorf("service: %w", service.ErrVersionNotFound)).Times(1)
+
+	w := makeRequest(router, "POST", fmt.Sprintf("/api/v1/incidents/%s/revert/9", incidentID), nil, map[string]string{"X-API-Key": "test-admin-key"})
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestRevertIncident_InvalidVersion(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+
+	mockService.EXPECT().RevertIncident(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	w := makeRequest(router, "POST", fmt.Sprintf("/api/v1/incidents/%s/revert/zero", uuid.New()), nil, map[string]string{"X-API-Key": "test-admin-key"})
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestGetAPIKeyUsage_Success(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+	usage := []*models.APIKeyUsage{
+		{Label: "key_aaaa", Requests: 42},
+		{Label: "key_bbbb", Requests: 7},
+	}
+
+	mockService.EXPECT().GetAPIKeyUsage(gomock.Any()).Return(usage, nil).Times(1)
+
+	w := makeRequest(router, "GET", "/api/v1/admin/keys/usage", nil, map[string]string{"X-API-Key": "test-admin-key"})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp []APIKeyUsageResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp, 2)
+	assert.Equal(t, "key_aaaa", resp[0].Label)
+	assert.Equal(t, int64(42), resp[0].Requests)
+}
+
+func TestGetAPIKeyUsage_Forbidden(t *testing.T) {
+	_, mockService, router := newTestHandler(t)
+
+	mockService.EXPECT().GetAPIKeyUsage(gomock.Any()).Times(0)
+
+	w := makeRequest(router, "GET", "/api/v1/admin/keys/usage", nil, map[string]string{"X-API-Key": "test-api-key"})
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestAPIKeyUsage_RecordedPerRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := mocks.NewMockIncidentService(ctrl)
+	logger := logrus.New()
+	logger.SetOutput(&bytes.Buffer{})
+	handler := NewHandler(mockService, logger, &config.Config{APIKeys: []string{"test-api-key"}})
+	router := gin.New()
+	handler.RegisterRoutes(router.Group("/api/v1"))
+	label := apiKeyLabel("test-api-key")
+	before := testutil.ToFloat64(apiKeyRequestsCounter.WithLabelValues(label))
+
+	// Запрос с ключом учитывается под меткой ключа, анонимный - не учитывается
+	mockService.EXPECT().RecordAPIKeyUsage(gomock.Any(), label).Return(nil).Times(1)
+	incidentID := uuid.New()
+	mockService.EXPECT().GetIncident(gomock.Any(), incidentID).Return(&models.Incident{ID: incidentID}, nil).Times(1)
+	mockService.EXPECT().RecordIncidentView(incidentID).Times(1)
+
+	w := makeRequest(router, "GET", "/api/v1/incidents/"+incidentID.String(), nil, map[string]string{"X-API-Key": "test-api-key"})
+	require.Equal(t, http.StatusOK, w.Code)
+	makeRequest(router, "GET", "/api/v1/system/health", nil)
+
+	assert.Equal(t, before+1, testutil.ToFloat64(apiKeyRequestsCounter.WithLabelValues(label)))
 }
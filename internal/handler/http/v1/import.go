@@ -0,0 +1,225 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/shenikar/geo_broadcasting_system/internal/service"
+)
+
+const (
+	// importRetryAfter - через сколько секунд повторить импорт, отклоненный из-за лимита одновременных импортов
+	importRetryAfter = 30
+	// codeTooManyImports - код ошибки, когда одновременно выполняется IMPORT_MAX_CONCURRENT импортов
+	codeTooManyImports = "TOO_MANY_IMPORTS"
+)
+
+// @Summary Import incidents from a GeoJSON URL
+// @Description Fetch a GeoJSON FeatureCollection from the URL in the background and create or update incidents by external ID.
+// @Description Each Point feature becomes an incident: properties external_id (or the feature id), name, radius_meters, description, category, severity, status and source.
+// @Description The feed is limited by IMPORT_URL_MAX_BYTES and IMPORT_URL_TIMEOUT; if IMPORT_URL_ALLOWED_HOSTS is set, only those hosts are allowed.
+// @Description Hosts resolving to private, loopback or link-local addresses are rejected. At most IMPORT_MAX_CONCURRENT imports run at once.
+// @Description Returns a job to poll via GET /incidents/import-jobs/{id}. Requires admin API key.
+// @Tags Incidents
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body ImportURLRequest true "Feed URL"
+// @Success 202 {object} ImportJobResponse
+// @Failure 400 {object} map[string]string "Invalid request body or URL not allowed"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Admin API key required"
+// @Failure 422 {object} ValidationErrorResponse "Validation error"
+// @Failure 429 {object} ErrorResponse "Too many imports in progress, retry after Retry-After seconds"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} ErrorResponse "Storage unavailable, retry after Retry-After seconds"
+// @Router /incidents/import-url [post]
+func (h *Handler) importIncidentsFromURL(c *gin.Context) {
+	var input ImportURLRequest
+	log := h.logger.WithField("method", "importIncidentsFromURL")
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.WithError(err).Warn("Failed to bind JSON")
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	if err := h.validate.Struct(input); err != nil {
+		log.WithError(err).Warn("Validation failed")
+		h.respondValidationError(c, input, err)
+		return
+	}
+
+	job, err := h.incidentService.StartIncidentImport(c.Request.Context(), input.URL, c.GetString(apiKeyLabelContextKey))
+	if err != nil {
+		if errors.Is(err, service.ErrImportURLNotAllowed) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrImportLimitReached) {
+			c.Header("Retry-After", strconv.Itoa(importRetryAfter))
+			c.JSON(http.StatusTooManyRequests, ErrorResponse{
+				Error: "too many imports in progress",
+				Code:  codeTooManyImports,
+			})
+			return
+		}
+		log.WithError(err).Error("Failed to start incident import in service")
+		respondServiceError(c, err, "failed to start import")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, ModelToImportJobResponse(job))
+}
+
+// @Summary Get incident import job
+// @Description Get the state of a background import started by POST /incidents/import-url: counts of created and updated incidents
+// @Description and per-feature errors. Job state is kept for 24 hours. Requires admin API key.
+// @Tags Incidents
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Import job ID"
+// @Success 200 {object} ImportJobResponse
+// @Failure 400 {object} map[string]string "Invalid job ID"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Admin API key required"
+// @Failure 404 {object} map[string]string "Import job not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} ErrorResponse "Storage unavailable, retry after Retry-After seconds"
+// @Router /incidents/import-jobs/{id} [get]
+func (h *Handler) getImportJob(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid import job ID"})
+		return
+	}
+	log := h.logger.WithField("method", "getImportJob").WithField("id", id)
+
+	job, err := h.incidentService.GetImportJob(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrImportJobNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "import job not found"})
+			return
+		}
+		log.WithError(err).Error("Failed to get import job from service")
+		respondServiceError(c, err, "internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, ModelToImportJobResponse(job))
+}
+
+// @Summary Register a GeoJSON feed for periodic sync
+// @Description Register a GeoJSON FeatureCollection URL to be re-imported every IMPORT_FEED_SYNC_INTERVAL.
+// @Description Each sync creates or updates incidents by external ID like POST /incidents/import-url and deactivates incidents
+// @Description that were in the feed on the previous sync but are no longer present; such incidents become active again if they return.
+// @Description External IDs are scoped to the feed as "<feed id>:<feature id>". Lifecycle webhooks are published for the changes.
+// @Description The URL is checked against IMPORT_URL_ALLOWED_HOSTS. Requires admin API key.
+// @Tags Incidents
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body ImportURLRequest true "Feed URL"
+// @Success 201 {object} ImportFeedResponse
+// @Failure 400 {object} map[string]string "Invalid request body or URL not allowed"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Admin API key required"
+// @Failure 409 {object} map[string]string "Feed with this URL is already registered"
+// @Failure 422 {object} ValidationErrorResponse "Validation error"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} ErrorResponse "Storage unavailable, retry after Retry-After seconds"
+// @Router /incidents/import-feeds [post]
+func (h *Handler) registerImportFeed(c *gin.Context) {
+	var input ImportURLRequest
+	log := h.logger.WithField("method", "registerImportFeed")
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.WithError(err).Warn("Failed to bind JSON")
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	if err := h.validate.Struct(input); err != nil {
+		log.WithError(err).Warn("Validation failed")
+		h.respondValidationError(c, input, err)
+		return
+	}
+
+	feed, err := h.incidentService.RegisterImportFeed(c.Request.Context(), input.URL, c.GetString(apiKeyLabelContextKey))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrImportURLNotAllowed):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrImportFeedExists):
+			c.JSON(http.StatusConflict, gin.H{"error": "feed with this url is already registered"})
+		default:
+			log.WithError(err).Error("Failed to register import feed in service")
+			respondServiceError(c, err, "failed to register import feed")
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, ModelToImportFeedResponse(feed))
+}
+
+// @Summary List registered GeoJSON feeds
+// @Description List feeds registered for periodic sync with the number of incidents seen on the last successful sync
+// @Description and the outcome of the last sync. Requires admin API key.
+// @Tags Incidents
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} ImportFeedResponse
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Admin API key required"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} ErrorResponse "Storage unavailable, retry after Retry-After seconds"
+// @Router /incidents/import-feeds [get]
+func (h *Handler) listImportFeeds(c *gin.Context) {
+	feeds, err := h.incidentService.ListImportFeeds(c.Request.Context())
+	if err != nil {
+		h.logger.WithField("method", "listImportFeeds").WithError(err).Error("Failed to list import feeds from service")
+		respondServiceError(c, err, "internal server error")
+		return
+	}
+
+	responses := make([]*ImportFeedResponse, len(feeds))
+	for i, feed := range feeds {
+		responses[i] = ModelToImportFeedResponse(feed)
+	}
+	c.JSON(http.StatusOK, responses)
+}
+
+// @Summary Unregister a GeoJSON feed
+// @Description Stop syncing the feed. Incidents imported from it are not affected. Requires admin API key.
+// @Tags Incidents
+// @Security ApiKeyAuth
+// @Param id path string true "Feed ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} map[string]string "Invalid feed ID"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Admin API key required"
+// @Failure 404 {object} map[string]string "Feed not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} ErrorResponse "Storage unavailable, retry after Retry-After seconds"
+// @Router /incidents/import-feeds/{id} [delete]
+func (h *Handler) deleteImportFeed(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid import feed ID"})
+		return
+	}
+
+	if err := h.incidentService.DeleteImportFeed(c.Request.Context(), id); err != nil {
+		if errors.Is(err, service.ErrImportFeedNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "import feed not found"})
+			return
+		}
+		h.logger.WithField("method", "deleteImportFeed").WithError(err).Error("Failed to delete import feed in service")
+		respondServiceError(c, err, "internal server error")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
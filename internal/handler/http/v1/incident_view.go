@@ -0,0 +1,46 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/shenikar/geo_broadcasting_system/internal/service"
+)
+
+// @Summary Get incident view count
+// @Description Get how many times the incident was fetched with GET /incidents/{id}. Views are counted asynchronously,
+// @Description so the latest views of other instances may appear with a delay of about a second. Requires admin API key.
+// @Tags Admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Incident ID"
+// @Success 200 {object} IncidentViewsResponse
+// @Failure 400 {object} map[string]string "Invalid incident ID"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Admin API key required"
+// @Failure 404 {object} map[string]string "Incident not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} ErrorResponse "Storage unavailable, retry after Retry-After seconds"
+// @Router /incidents/{id}/views [get]
+func (h *Handler) getIncidentViews(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid incident ID"})
+		return
+	}
+	log := h.logger.WithField("method", "getIncidentViews").WithField("id", id)
+
+	views, err := h.incidentService.GetIncidentViews(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrIncidentNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "incident not found"})
+			return
+		}
+		log.WithError(err).Error("Failed to get incident views from service")
+		respondServiceError(c, err, "internal server error")
+		return
+	}
+	c.JSON(http.StatusOK, IncidentViewsResponse{IncidentID: id, Views: views})
+}
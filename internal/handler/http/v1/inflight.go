@@ -0,0 +1,56 @@
+package v1
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// inFlightPollInterval - период проверки счетчика при ожидании завершения запросов
+const inFlightPollInterval = 50 * time.Millisecond
+
+// InFlightCounter считает HTTP-запросы, обработка которых еще не завершилась.
+// Используется при остановке сервера, чтобы залогировать и дождаться незавершенные запросы.
+type InFlightCounter struct {
+	n atomic.Int64
+}
+
+// NewInFlightCounter создает счетчик незавершенных запросов
+func NewInFlightCounter() *InFlightCounter {
+	return &InFlightCounter{}
+}
+
+// Middleware - middleware, учитывающее запрос в счетчике на время его обработки
+func (c *InFlightCounter) Middleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		c.n.Add(1)
+		defer c.n.Add(-1)
+		ctx.Next()
+	}
+}
+
+// Count возвращает текущее число незавершенных запросов
+func (c *InFlightCounter) Count() int64 {
+	return c.n.Load()
+}
+
+// Wait ждет, пока все запросы завершатся, или отмены ctx.
+// Возвращает число запросов, оставшихся незавершенными.
+func (c *InFlightCounter) Wait(ctx context.Context) int64 {
+	ticker := time.NewTicker(inFlightPollInterval)
+	defer ticker.Stop()
+
+	for {
+		count := c.Count()
+		if count == 0 {
+			return 0
+		}
+		select {
+		case <-ctx.Done():
+			return count
+		case <-ticker.C:
+		}
+	}
+}
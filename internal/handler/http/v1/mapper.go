@@ -1,6 +1,10 @@
 package v1
 
-import "github.com/shenikar/geo_broadcasting_system/internal/models"
+import (
+	"github.com/google/uuid"
+	"github.com/shenikar/geo_broadcasting_system/internal/models"
+	"github.com/shenikar/geo_broadcasting_system/pkg/geo"
+)
 
 // DTOToIncidentModel преобразует DTO создания/обновления в доменную модель.
 // Используем одну функцию, так как поля совпадают.
@@ -13,15 +17,33 @@ func DTOToIncidentModel(dto any) *models.Incident {
 			Latitude:     v.Latitude,
 			Longitude:    v.Longitude,
 			RadiusMeters: v.RadiusMeters,
+			Status:       models.IncidentStatus(v.Status),
+			MediaURLs:    v.MediaURLs,
+			Source:       v.Source,
+			Confidence:   v.Confidence,
+			Silent:       v.Silent,
+			Public:       v.Public,
+			Severity:     models.IncidentSeverity(v.Severity),
+			Category:     v.Category,
+			ExternalID:   v.ExternalID,
 		}
 	case UpdateIncidentRequest:
 		return &models.Incident{
-			Name:         v.Name,
-			Description:  v.Description,
-			Latitude:     v.Latitude,
-			Longitude:    v.Longitude,
-			RadiusMeters: v.RadiusMeters,
-			Status:       v.Status,
+			Name:             v.Name,
+			Description:      v.Description,
+			Latitude:         v.Latitude,
+			Longitude:        v.Longitude,
+			RadiusMeters:     v.RadiusMeters,
+			Status:           models.IncidentStatus(v.Status),
+			MediaURLs:        v.MediaURLs,
+			Source:           v.Source,
+			Confidence:       v.Confidence,
+			Silent:           v.Silent,
+			Public:           v.Public,
+			Severity:         models.IncidentSeverity(v.Severity),
+			ResolutionReason: v.ResolutionReason,
+			Category:         v.Category,
+			ExternalID:       v.ExternalID,
 		}
 	}
 	return nil
@@ -30,18 +52,34 @@ func DTOToIncidentModel(dto any) *models.Incident {
 // ModelToIncidentResponse преобразует доменную модель в DTO для ответа
 func ModelToIncidentResponse(model *models.Incident) *IncidentResponse {
 	return &IncidentResponse{
-		ID:           model.ID,
-		Name:         model.Name,
-		Description:  model.Description,
-		Latitude:     model.Latitude,
-		Longitude:    model.Longitude,
-		RadiusMeters: model.RadiusMeters,
-		Status:       model.Status,
-		CreatedAt:    model.CreatedAt,
-		UpdatedAt:    model.UpdatedAt,
+		ID:               model.ID,
+		Name:             model.Name,
+		Description:      model.Description,
+		Latitude:         model.Latitude,
+		Longitude:        model.Longitude,
+		RadiusMeters:     model.RadiusMeters,
+		AreaSqMeters:     geo.CircleAreaSqMeters(float64(model.RadiusMeters)),
+		Status:           string(model.Status),
+		MediaURLs:        model.MediaURLs,
+		Source:           model.Source,
+		Confidence:       model.Confidence,
+		Silent:           model.Silent,
+		Public:           model.Public,
+		Severity:         string(model.Severity),
+		Category:         model.Category,
+		ExternalID:       model.ExternalID,
+		ResolutionReason: model.ResolutionReason,
+		CreatedBy:        model.CreatedBy,
+		CreatedAt:        model.CreatedAt,
+		UpdatedAt:        model.UpdatedAt,
 	}
 }
 
+// ModelToChangeCursorResponse преобразует позицию в ленте изменений в DTO
+func ModelToChangeCursorResponse(cursor models.ChangeCursor) ChangeCursorResponse {
+	return ChangeCursorResponse{UpdatedAt: cursor.UpdatedAt, ID: cursor.ID}
+}
+
 // ModelsToIncidentResponses преобразует слайс моделей в слайс DTO
 func ModelsToIncidentResponses(models []*models.Incident) []*IncidentResponse {
 	responses := make([]*IncidentResponse, len(models))
@@ -50,3 +88,319 @@ func ModelsToIncidentResponses(models []*models.Incident) []*IncidentResponse {
 	}
 	return responses
 }
+
+// ModelsToPublicIncidentResponses преобразует инциденты публичной ленты в слайс DTO без служебных полей
+func ModelsToPublicIncidentResponses(incidents []*models.Incident) []*PublicIncidentResponse {
+	responses := make([]*PublicIncidentResponse, len(incidents))
+	for i, incident := range incidents {
+		responses[i] = &PublicIncidentResponse{
+			ID:           incident.ID,
+			Name:         incident.Name,
+			Description:  incident.Description,
+			Latitude:     incident.Latitude,
+			Longitude:    incident.Longitude,
+			RadiusMeters: incident.RadiusMeters,
+			Severity:     string(incident.Severity),
+			Category:     incident.Category,
+			UpdatedAt:    incident.UpdatedAt,
+		}
+	}
+	return responses
+}
+
+// ModelsToOverlappingIncidentResponses преобразует пересекающиеся инциденты в слайс DTO
+func ModelsToOverlappingIncidentResponses(overlaps []*models.IncidentOverlap) []*OverlappingIncidentResponse {
+	responses := make([]*OverlappingIncidentResponse, len(overlaps))
+	for i, overlap := range overlaps {
+		responses[i] = &OverlappingIncidentResponse{
+			Incident:            ModelToIncidentResponse(overlap.Incident),
+			OverlapAreaSqMeters: overlap.OverlapAreaSqMeters,
+		}
+	}
+	return responses
+}
+
+// ModelsToIncidentVersionResponses преобразует слайс версий инцидента в слайс DTO
+func ModelsToIncidentVersionResponses(versions []*models.IncidentVersion) []*IncidentVersionResponse {
+	responses := make([]*IncidentVersionResponse, len(versions))
+	for i, version := range versions {
+		responses[i] = &IncidentVersionResponse{
+			Version:   version.Version,
+			Incident:  ModelToIncidentResponse(version.Incident),
+			CreatedAt: version.CreatedAt,
+		}
+	}
+	return responses
+}
+
+// ModelsToLocationCheckResponses преобразует слайс проверок местоположения в слайс DTO
+func ModelsToLocationCheckResponses(checks []*models.LocationCheck) []*LocationCheckResponse {
+	responses := make([]*LocationCheckResponse, len(checks))
+	for i, check := range checks {
+		incidentIDs := check.IncidentIDs
+		if incidentIDs == nil {
+			incidentIDs = []uuid.UUID{}
+		}
+		responses[i] = &LocationCheckResponse{
+			ID:            check.ID,
+			UserID:        check.UserID,
+			Latitude:      check.Latitude,
+			Longitude:     check.Longitude,
+			IsDangerous:   check.IsDangerous,
+			Authenticated: check.Authenticated,
+			IncidentIDs:   incidentIDs,
+			CheckedAt:     check.CheckedAt,
+		}
+	}
+	return responses
+}
+
+// ModelToUserLocationResponse преобразует сохраненное место пользователя в DTO
+func ModelToUserLocationResponse(location *models.UserLocation) *UserLocationResponse {
+	return &UserLocationResponse{
+		ID:        location.ID,
+		UserID:    location.UserID,
+		Label:     location.Label,
+		Latitude:  location.Latitude,
+		Longitude: location.Longitude,
+		CreatedAt: location.CreatedAt,
+	}
+}
+
+// ModelsToUserLocationResponses преобразует слайс сохраненных мест в слайс DTO
+func ModelsToUserLocationResponses(locations []*models.UserLocation) []*UserLocationResponse {
+	responses := make([]*UserLocationResponse, len(locations))
+	for i, location := range locations {
+		responses[i] = ModelToUserLocationResponse(location)
+	}
+	return responses
+}
+
+// ModelsToUserAlertResponses преобразует оповещения по сохраненным местам в слайс DTO
+func ModelsToUserAlertResponses(alerts []*models.LocationAlert) []*UserAlertResponse {
+	responses := make([]*UserAlertResponse, len(alerts))
+	for i, alert := range alerts {
+		responses[i] = &UserAlertResponse{
+			Label:     alert.Location.Label,
+			Location:  *ModelToUserLocationResponse(alert.Location),
+			Incidents: ModelsToIncidentResponses(alert.Incidents),
+		}
+	}
+	return responses
+}
+
+// ModelToUserStatusResponse преобразует статус пользователя в DTO
+func ModelToUserStatusResponse(status *models.UserStatus) *UserStatusResponse {
+	response := &UserStatusResponse{
+		UserID:      status.LastCheck.UserID,
+		Status:      "safe",
+		IsDangerous: status.IsDangerous(),
+		Latitude:    status.LastCheck.Latitude,
+		Longitude:   status.LastCheck.Longitude,
+		CheckedAt:   status.LastCheck.CheckedAt,
+		Incidents:   ModelsToIncidentResponses(status.Incidents),
+	}
+	if response.IsDangerous {
+		response.Status = "danger"
+	}
+	return response
+}
+
+// DTOToIncidentTemplateModel преобразует запрос шаблона в доменную модель
+func DTOToIncidentTemplateModel(dto IncidentTemplateRequest) *models.IncidentTemplate {
+	return &models.IncidentTemplate{
+		Name:         dto.Name,
+		Description:  dto.Description,
+		Category:     dto.Category,
+		RadiusMeters: dto.RadiusMeters,
+		Source:       dto.Source,
+	}
+}
+
+// ModelToIncidentTemplateResponse преобразует шаблон инцидента в DTO
+func ModelToIncidentTemplateResponse(template *models.IncidentTemplate) *IncidentTemplateResponse {
+	return &IncidentTemplateResponse{
+		ID:           template.ID,
+		Name:         template.Name,
+		Description:  template.Description,
+		Category:     template.Category,
+		RadiusMeters: template.RadiusMeters,
+		Source:       template.Source,
+		CreatedAt:    template.CreatedAt,
+		UpdatedAt:    template.UpdatedAt,
+	}
+}
+
+// ModelsToIncidentTemplateResponses преобразует слайс шаблонов в слайс DTO
+func ModelsToIncidentTemplateResponses(templates []*models.IncidentTemplate) []*IncidentTemplateResponse {
+	responses := make([]*IncidentTemplateResponse, len(templates))
+	for i, template := range templates {
+		responses[i] = ModelToIncidentTemplateResponse(template)
+	}
+	return responses
+}
+
+// ModelsToClusterResponses преобразует кластеры инцидентов в слайс DTO
+func ModelsToClusterResponses(clusters []*models.IncidentCluster) []*ClusterResponse {
+	responses := make([]*ClusterResponse, len(clusters))
+	for i, cluster := range clusters {
+		responses[i] = &ClusterResponse{
+			Latitude:   cluster.Latitude,
+			Longitude:  cluster.Longitude,
+			Count:      cluster.Count,
+			IncidentID: cluster.IncidentID,
+		}
+	}
+	return responses
+}
+
+// ModelsToHotspotResponses преобразует очаги инцидентов в слайс DTO
+func ModelsToHotspotResponses(hotspots []*models.IncidentHotspot) []*HotspotResponse {
+	responses := make([]*HotspotResponse, len(hotspots))
+	for i, hotspot := range hotspots {
+		responses[i] = &HotspotResponse{
+			Latitude:  hotspot.Latitude,
+			Longitude: hotspot.Longitude,
+			Bounds: BoundsResponse{
+				MinLat: hotspot.Bounds.MinLat,
+				MinLon: hotspot.Bounds.MinLon,
+				MaxLat: hotspot.Bounds.MaxLat,
+				MaxLon: hotspot.Bounds.MaxLon,
+			},
+			Count: hotspot.Count,
+		}
+	}
+	return responses
+}
+
+// ModelToGeofenceValidationResponse преобразует результат проверки зоны в DTO
+func ModelToGeofenceValidationResponse(model *models.GeofenceValidation) *GeofenceValidationResponse {
+	errs := model.Errors
+	if errs == nil {
+		errs = []string{}
+	}
+	return &GeofenceValidationResponse{
+		Valid:            model.Valid,
+		Errors:           errs,
+		AreaSquareMeters: model.AreaSquareMeters,
+		Bounds: BoundsResponse{
+			MinLat: model.Bounds.MinLat,
+			MinLon: model.Bounds.MinLon,
+			MaxLat: model.Bounds.MaxLat,
+			MaxLon: model.Bounds.MaxLon,
+		},
+		CrossesAntimeridian: model.CrossesAntimeridian,
+	}
+}
+
+// ModelsToTimelineResponse преобразует корзины временной шкалы в DTO
+func ModelsToTimelineResponse(interval string, buckets []*models.TimelineBucket) *TimelineResponse {
+	responses := make([]*TimelineBucketResponse, len(buckets))
+	for i, bucket := range buckets {
+		responses[i] = &TimelineBucketResponse{Start: bucket.Start, Count: bucket.Count}
+	}
+	return &TimelineResponse{Interval: interval, Buckets: responses}
+}
+
+// ModelsToAPIKeyUsageResponses преобразует учет запросов по API-ключам в слайс DTO
+func ModelsToAPIKeyUsageResponses(usage []*models.APIKeyUsage) []*APIKeyUsageResponse {
+	responses := make([]*APIKeyUsageResponse, len(usage))
+	for i, u := range usage {
+		responses[i] = &APIKeyUsageResponse{Label: u.Label, Requests: u.Requests}
+	}
+	return responses
+}
+
+// ModelToImportJobResponse преобразует состояние задачи импорта в DTO
+func ModelToImportJobResponse(job *models.ImportJob) *ImportJobResponse {
+	errs := make([]*ImportFeatureErrorResponse, len(job.Errors))
+	for i, e := range job.Errors {
+		errs[i] = &ImportFeatureErrorResponse{Index: e.Index, ExternalID: e.ExternalID, Error: e.Error}
+	}
+	return &ImportJobResponse{
+		ID:         job.ID,
+		URL:        job.URL,
+		Status:     string(job.Status),
+		Features:   job.Features,
+		Created:    job.Created,
+		Updated:    job.Updated,
+		Unchanged:  job.Unchanged,
+		Errors:     errs,
+		Error:      job.Error,
+		StartedAt:  job.StartedAt,
+		FinishedAt: job.FinishedAt,
+	}
+}
+
+// ModelToImportFeedResponse преобразует зарегистрированную ленту в DTO
+func ModelToImportFeedResponse(feed *models.ImportFeed) *ImportFeedResponse {
+	resp := &ImportFeedResponse{
+		ID:        feed.ID,
+		URL:       feed.URL,
+		CreatedBy: feed.CreatedBy,
+		CreatedAt: feed.CreatedAt,
+		Incidents: len(feed.ExternalIDs),
+	}
+	if sync := feed.LastSync; sync != nil {
+		resp.LastSync = &ImportFeedSyncResponse{
+			Status:      string(sync.Status),
+			Error:       sync.Error,
+			Created:     sync.Created,
+			Updated:     sync.Updated,
+			Deactivated: sync.Deactivated,
+			SyncedAt:    sync.SyncedAt,
+		}
+	}
+	return resp
+}
+
+// ModelToAttachmentUploadResponse преобразует вложение с URL загрузки в DTO
+func ModelToAttachmentUploadResponse(presigned *models.PresignedAttachment) *AttachmentUploadResponse {
+	return &AttachmentUploadResponse{
+		ID:        presigned.Attachment.ID,
+		ObjectKey: presigned.Attachment.ObjectKey,
+		UploadURL: presigned.URL,
+		Method:    "PUT",
+		Headers:   map[string]string{"Content-Type": presigned.Attachment.ContentType},
+		ExpiresAt: presigned.ExpiresAt,
+	}
+}
+
+// ModelToAttachmentResponse преобразует вложение с URL скачивания в DTO
+func ModelToAttachmentResponse(presigned *models.PresignedAttachment) *AttachmentResponse {
+	return &AttachmentResponse{
+		ID:          presigned.Attachment.ID,
+		Filename:    presigned.Attachment.Filename,
+		ContentType: presigned.Attachment.ContentType,
+		ObjectKey:   presigned.Attachment.ObjectKey,
+		CreatedAt:   presigned.Attachment.CreatedAt,
+		DownloadURL: presigned.URL,
+		ExpiresAt:   presigned.ExpiresAt,
+	}
+}
+
+// ModelToWatcherResponse преобразует наблюдателя инцидента в DTO
+func ModelToWatcherResponse(watcher *models.IncidentWatcher) *WatcherResponse {
+	return &WatcherResponse{
+		ID:         watcher.ID,
+		IncidentID: watcher.IncidentID,
+		URL:        watcher.URL,
+		CreatedBy:  watcher.CreatedBy,
+		CreatedAt:  watcher.CreatedAt,
+	}
+}
+
+// ModelToLocationCheckReevaluationResponse преобразует итог повторной оценки проверок в DTO
+func ModelToLocationCheckReevaluationResponse(q models.LocationCheckReevaluationQuery, result *models.LocationCheckReevaluation) *LocationCheckReevaluationResponse {
+	response := &LocationCheckReevaluationResponse{
+		From:              q.From,
+		To:                q.To,
+		Checked:           result.Checked,
+		Discrepancies:     ModelsToLocationCheckResponses(result.Discrepancies),
+		WebhooksPublished: result.WebhooksPublished,
+	}
+	if result.NextAfterID > 0 {
+		response.NextAfterID = &result.NextAfterID
+	}
+	return response
+}
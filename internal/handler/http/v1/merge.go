@@ -0,0 +1,72 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/shenikar/geo_broadcasting_system/internal/service"
+)
+
+// @Summary Merge duplicate incidents
+// @Description Merge incidents reported independently for the same event. The first of ids stays as the canonical incident:
+// @Description its zone is replaced with a circle covering the union of all merged zones, location check matches of the others are redirected to it,
+// @Description and the others are deactivated. The merge is recorded in the status history. Requires admin API key.
+// @Tags Incidents
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body MergeIncidentsRequest true "Incidents to merge, canonical first"
+// @Success 200 {object} MergeIncidentsResponse
+// @Failure 400 {object} map[string]string "Invalid request body or fewer than two distinct incidents"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Admin API key required"
+// @Failure 404 {object} map[string]string "Incident not found"
+// @Failure 409 {object} map[string]string "Incident is finished or rejected"
+// @Failure 422 {object} ValidationErrorResponse "Validation error"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} ErrorResponse "Storage unavailable, retry after Retry-After seconds"
+// @Router /incidents/merge [post]
+func (h *Handler) mergeIncidents(c *gin.Context) {
+	var input MergeIncidentsRequest
+	log := h.logger.WithField("method", "mergeIncidents")
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.WithError(err).Warn("Failed to bind JSON")
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	if err := h.validate.Struct(input); err != nil {
+		log.WithError(err).Warn("Validation failed")
+		h.respondValidationError(c, input, err)
+		return
+	}
+
+	result, err := h.incidentService.MergeIncidents(c.Request.Context(), input.IDs, c.GetString(apiKeyLabelContextKey))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidMerge):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrIncidentNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "incident not found"})
+		case errors.Is(err, service.ErrStatusConflict):
+			c.JSON(http.StatusConflict, gin.H{"error": "only active or draft incidents can be merged"})
+		default:
+			log.WithError(err).Error("Failed to merge incidents in service")
+			respondServiceError(c, err, "failed to merge incidents")
+		}
+		return
+	}
+
+	resp := MergeIncidentsResponse{
+		Incident:         ModelToIncidentResponse(result.Incident),
+		MergedIDs:        make([]uuid.UUID, 0, len(result.Merged)),
+		RedirectedChecks: result.RedirectedChecks,
+	}
+	for _, incident := range result.Merged {
+		resp.MergedIDs = append(resp.MergedIDs, incident.ID)
+	}
+	c.JSON(http.StatusOK, resp)
+}
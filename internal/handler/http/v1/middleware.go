@@ -0,0 +1,72 @@
+package v1
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	requestIDHeader     = "X-Request-ID"
+	requestIDContextKey = "request_id"
+)
+
+// RequestIDMiddleware - middleware, присваивающее каждому запросу идентификатор.
+// Если клиент передал X-Request-ID, используется его значение.
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(requestIDHeader)
+		if requestID == "" {
+			requestID = uuid.NewString()
+		}
+		c.Set(requestIDContextKey, requestID)
+		c.Header(requestIDHeader, requestID)
+		c.Next()
+	}
+}
+
+// AccessLogMiddleware - middleware для структурированного логирования запросов через logrus.
+// Запросы к путям из skipPaths не логируются.
+func AccessLogMiddleware(log *logrus.Logger, skipPaths ...string) gin.HandlerFunc {
+	skip := make(map[string]struct{}, len(skipPaths))
+	for _, path := range skipPaths {
+		skip[path] = struct{}{}
+	}
+
+	return func(c *gin.Context) {
+		path := c.Request.URL.Path
+		if _, ok := skip[path]; ok {
+			c.Next()
+			return
+		}
+
+		start := time.Now()
+		c.Next()
+		latency := time.Since(start)
+
+		status := c.Writer.Status()
+		entry := log.WithFields(logrus.Fields{
+			"method":     c.Request.Method,
+			"path":       path,
+			"status":     status,
+			"latency_ms": latency.Milliseconds(),
+			"client_ip":  c.ClientIP(),
+			"request_id": c.GetString(requestIDContextKey),
+			"api_key":    c.GetString(apiKeyLabelContextKey),
+		})
+		if len(c.Errors) > 0 {
+			entry = entry.WithField("errors", c.Errors.String())
+		}
+
+		switch {
+		case status >= 500:
+			entry.Error("HTTP request")
+		case status >= 400:
+			entry.Warn("HTTP request")
+		default:
+			entry.Info("HTTP request")
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/shenikar/geo_broadcasting_system/internal/models"
+	"github.com/shenikar/geo_broadcasting_system/internal/service"
+)
+
+// @Summary Approve a draft incident
+// @Description Transition a draft incident to active and emit an incident_approved webhook. Requires admin API key.
+// @Tags Moderation
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Incident ID"
+// @Success 200 {object} IncidentResponse
+// @Failure 400 {object} map[string]string "Invalid incident ID"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Admin API key required"
+// @Failure 404 {object} map[string]string "Incident not found"
+// @Failure 409 {object} map[string]string "Incident is not a draft"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} ErrorResponse "Storage unavailable, retry after Retry-After seconds"
+// @Router /incidents/{id}/approve [post]
+func (h *Handler) approveIncident(c *gin.Context) {
+	h.reviewIncident(c, "approveIncident", h.incidentService.ApproveIncident)
+}
+
+// @Summary Reject a draft incident
+// @Description Transition a draft incident to rejected. Rejected incidents never match location checks. Requires admin API key.
+// @Tags Moderation
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Incident ID"
+// @Success 200 {object} IncidentResponse
+// @Failure 400 {object} map[string]string "Invalid incident ID"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Admin API key required"
+// @Failure 404 {object} map[string]string "Incident not found"
+// @Failure 409 {object} map[string]string "Incident is not a draft"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} ErrorResponse "Storage unavailable, retry after Retry-After seconds"
+// @Router /incidents/{id}/reject [post]
+func (h *Handler) rejectIncident(c *gin.Context) {
+	h.reviewIncident(c, "rejectIncident", h.incidentService.RejectIncident)
+}
+
+// reviewIncident - общая логика модерации черновиков
+func (h *Handler) reviewIncident(c *gin.Context, method string, review func(ctx context.Context, id uuid.UUID, reviewer string) (*models.Incident, error)) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid incident ID"})
+		return
+	}
+	log := h.logger.WithField("method", method).WithField("id", id)
+
+	incident, err := review(c.Request.Context(), id, c.GetString(apiKeyLabelContextKey))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrIncidentNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "incident not found"})
+		case errors.Is(err, service.ErrStatusConflict):
+			c.JSON(http.StatusConflict, gin.H{"error": "incident is not in draft status"})
+		default:
+			log.WithError(err).Error("Failed to review incident in service")
+			respondServiceError(c, err, "internal server error")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, ModelToIncidentResponse(incident))
+}
@@ -0,0 +1,56 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/swaggo/swag"
+	"gopkg.in/yaml.v3"
+)
+
+// openAPIYAMLContentType - тип ответа /openapi.yaml (RFC 9512)
+const openAPIYAMLContentType = "application/yaml"
+
+// OpenAPISpec - сгенерированная swag спецификация API, подготовленная для выдачи в JSON и YAML.
+// Маршруты спецификации не зависят от Swagger UI и доступны без API-ключа, чтобы их могли читать генераторы клиентов в CI.
+type OpenAPISpec struct {
+	json []byte
+	yaml []byte
+}
+
+// NewOpenAPISpec готовит спецификацию из документа swag. host (OPENAPI_HOST) и basePath заменяют значения
+// из аннотаций; пустой host удаляется из спецификации, и клиенты используют хост, с которого ее загрузили.
+func NewOpenAPISpec(spec *swag.Spec, host, basePath string) (*OpenAPISpec, error) {
+	var doc map[string]any
+	if err := json.Unmarshal([]byte(spec.ReadDoc()), &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI spec: %w", err)
+	}
+	if host == "" {
+		delete(doc, "host")
+	} else {
+		doc["host"] = host
+	}
+	doc["basePath"] = basePath
+
+	jsonSpec, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal OpenAPI spec to JSON: %w", err)
+	}
+	yamlSpec, err := yaml.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal OpenAPI spec to YAML: %w", err)
+	}
+	return &OpenAPISpec{json: jsonSpec, yaml: yamlSpec}, nil
+}
+
+// RegisterRoutes регистрирует GET /openapi.json и GET /openapi.yaml
+func (s *OpenAPISpec) RegisterRoutes(router gin.IRoutes) {
+	router.GET("/openapi.json", func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/json; charset=utf-8", s.json)
+	})
+	router.GET("/openapi.yaml", func(c *gin.Context) {
+		c.Data(http.StatusOK, openAPIYAMLContentType+"; charset=utf-8", s.yaml)
+	})
+}
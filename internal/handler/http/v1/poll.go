@@ -0,0 +1,80 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// defaultPollTimeout - время ожидания изменений, если timeout не передан (не больше LONG_POLL_MAX_TIMEOUT)
+	defaultPollTimeout = 30 * time.Second
+	// pollRetryAfter - через сколько секунд повторить запрос, отклоненный из-за лимита одновременных ожиданий
+	pollRetryAfter = 5
+	// codeTooManyPollers - код ошибки, когда исчерпан лимит одновременных запросов long polling
+	codeTooManyPollers = "TOO_MANY_POLLERS"
+)
+
+// @Summary Long-poll incident changes
+// @Description Wait until incidents change after since, then return the changes. The request is held until a new or changed incident appears
+// @Description or the timeout elapses; an empty list means no changes. Pass cursor.updated_at and cursor.id from the response as since and after_id
+// @Description in the next request: incidents changed at the same instant are told apart by ID, so none are skipped.
+// @Description The timeout is capped at LONG_POLL_MAX_TIMEOUT, and at most LONG_POLL_MAX_CLIENTS requests may wait at once. Requires API key.
+// @Tags Incidents
+// @Produce json
+// @Security ApiKeyAuth
+// @Param since query string true "RFC 3339 timestamp (cursor.updated_at of the previous response)"
+// @Param after_id query string false "Incident ID (cursor.id of the previous response)"
+// @Param timeout query string false "How long to wait for changes, Go duration" default(30s)
+// @Success 200 {object} IncidentChangesResponse
+// @Failure 400 {object} map[string]string "Missing or invalid since, after_id or timeout"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 429 {object} ErrorResponse "Too many concurrent pollers, retry after Retry-After seconds"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} ErrorResponse "Storage unavailable, retry after Retry-After seconds"
+// @Header 429 {string} Retry-After "Seconds to wait before retrying"
+// @Router /incidents/poll [get]
+func (h *Handler) pollIncidentChanges(c *gin.Context) {
+	log := h.logger.WithField("method", "pollIncidentChanges")
+
+	after, err := parseChangeCursor(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	timeout := defaultPollTimeout
+	if raw := c.Query("timeout"); raw != "" {
+		timeout, err = time.ParseDuration(raw)
+		if err != nil || timeout <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "timeout must be a positive duration, e.g. 30s"})
+			return
+		}
+	}
+	timeout = min(timeout, h.cfg.LongPollMaxTimeout)
+
+	if h.pollers.Add(1) > int64(h.cfg.LongPollMaxClients) {
+		h.pollers.Add(-1)
+		log.WithField("max_clients", h.cfg.LongPollMaxClients).Warn("Long polling client limit reached")
+		c.Header("Retry-After", strconv.Itoa(pollRetryAfter))
+		c.JSON(http.StatusTooManyRequests, ErrorResponse{
+			Error: "too many concurrent pollers",
+			Code:  codeTooManyPollers,
+		})
+		return
+	}
+	defer h.pollers.Add(-1)
+
+	incidents, cursor, err := h.incidentService.PollIncidentChanges(c.Request.Context(), after, timeout)
+	if err != nil {
+		log.WithError(err).Error("Failed to poll incident changes from service")
+		respondServiceError(c, err, "internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, IncidentChangesResponse{
+		Incidents: ModelsToIncidentResponses(incidents),
+		Cursor:    ModelToChangeCursorResponse(cursor),
+	})
+}
@@ -0,0 +1,44 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shenikar/geo_broadcasting_system/internal/service"
+)
+
+// @Summary Public feed of active incidents
+// @Description List active incidents published for citizens (public flag set, not silent, not in an internal category).
+// @Description Does not require an API key; only non-sensitive fields are returned. Requests are rate limited per client IP.
+// @Tags Public
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param pageSize query int false "Number of items per page" default(10)
+// @Success 200 {array} PublicIncidentResponse
+// @Failure 404 {object} ErrorResponse "Feature disabled (FEATURE_DISABLED)"
+// @Failure 429 {object} ErrorResponse "Rate limit exceeded, retry after Retry-After seconds"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} ErrorResponse "Storage unavailable, retry after Retry-After seconds"
+// @Router /public/incidents [get]
+func (h *Handler) listPublicIncidents(c *gin.Context) {
+	log := h.logger.WithField("method", "listPublicIncidents")
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "10"))
+
+	incidents, err := h.incidentService.ListPublicIncidents(c.Request.Context(), c.ClientIP(), page, pageSize)
+	if err != nil {
+		var limitErr *service.RateLimitError
+		if errors.As(err, &limitErr) {
+			respondRateLimited(c, limitErr, "rate limit exceeded", codeRateLimited)
+			return
+		}
+		log.WithError(err).Error("Failed to list public incidents in service")
+		respondServiceError(c, err, "internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, ModelsToPublicIncidentResponses(incidents))
+}
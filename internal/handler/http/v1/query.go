@@ -0,0 +1,156 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/shenikar/geo_broadcasting_system/internal/models"
+	"github.com/shenikar/geo_broadcasting_system/internal/service"
+	"github.com/shenikar/geo_broadcasting_system/pkg/geo"
+)
+
+// snapshotTimeHeader - заголовок ответа со временем снимка списка для передачи в snapshot_time следующих страниц
+const snapshotTimeHeader = "X-Snapshot-Time"
+
+// parseIncidentSort разбирает параметры sort_by и order (по умолчанию desc). Если не передан ни один,
+// возвращается nil и используется порядок по умолчанию; order без sort_by относится к created_at.
+func parseIncidentSort(c *gin.Context) (*models.IncidentSort, error) {
+	sortBy, hasSortBy := c.GetQuery("sort_by")
+	order := c.Query("order")
+	if !hasSortBy && order == "" {
+		return nil, nil
+	}
+
+	sort := &models.IncidentSort{Field: models.SortByCreatedAt, Descending: true}
+	if hasSortBy {
+		sort.Field = models.IncidentSortField(sortBy)
+		if !sort.Field.IsValid() {
+			return nil, errors.New("sort_by must be one of: created_at, updated_at, name, severity")
+		}
+	}
+	switch order {
+	case "", "desc":
+	case "asc":
+		sort.Descending = false
+	default:
+		return nil, errors.New("order must be asc or desc")
+	}
+	return sort, nil
+}
+
+// parseProximityFilter разбирает параметры near_lat, near_lon и within_meters.
+// Параметры задаются только все вместе; если не передан ни один, возвращается nil.
+func parseProximityFilter(c *gin.Context) (*models.ProximityFilter, error) {
+	latStr, hasLat := c.GetQuery("near_lat")
+	lonStr, hasLon := c.GetQuery("near_lon")
+	withinStr, hasWithin := c.GetQuery("within_meters")
+
+	if !hasLat && !hasLon && !hasWithin {
+		return nil, nil
+	}
+	if !hasLat || !hasLon || !hasWithin {
+		return nil, errors.New("near_lat, near_lon and within_meters must be provided together")
+	}
+
+	lat, err := strconv.ParseFloat(latStr, 64)
+	if err != nil || lat < -90 || lat > 90 {
+		return nil, errors.New("near_lat must be a valid latitude")
+	}
+	lon, err := strconv.ParseFloat(lonStr, 64)
+	if err != nil || lon < -180 || lon > 180 {
+		return nil, errors.New("near_lon must be a valid longitude")
+	}
+	within, err := strconv.ParseFloat(withinStr, 64)
+	if err != nil || within <= 0 {
+		return nil, errors.New("within_meters must be a positive number")
+	}
+
+	return &models.ProximityFilter{
+		Latitude:     lat,
+		Longitude:    lon,
+		WithinMeters: within,
+	}, nil
+}
+
+// parseChangeCursor разбирает позицию в ленте изменений: обязательный since (RFC 3339) и необязательный after_id -
+// ID последнего полученного инцидента с этим временем изменения
+func parseChangeCursor(c *gin.Context) (models.ChangeCursor, error) {
+	since, err := time.Parse(time.RFC3339Nano, c.Query("since"))
+	if err != nil {
+		return models.ChangeCursor{}, errors.New("since must be an RFC 3339 timestamp")
+	}
+	cursor := models.ChangeCursor{UpdatedAt: since}
+	if raw, ok := c.GetQuery("after_id"); ok {
+		if cursor.ID, err = uuid.Parse(raw); err != nil {
+			return models.ChangeCursor{}, errors.New("after_id must be an incident ID")
+		}
+	}
+	return cursor, nil
+}
+
+// parseClusterQuery разбирает границы видимой области (min_lat, min_lon, max_lat, max_lon) и масштаб zoom.
+func parseClusterQuery(c *gin.Context) (geo.BBox, int, error) {
+	bbox, err := parseBBoxQuery(c)
+	if err != nil {
+		return geo.BBox{}, 0, err
+	}
+
+	zoom, err := strconv.Atoi(c.Query("zoom"))
+	if err != nil || zoom < service.MinClusterZoom || zoom > service.MaxClusterZoom {
+		return geo.BBox{}, 0, fmt.Errorf("zoom must be an integer between %d and %d", service.MinClusterZoom, service.MaxClusterZoom)
+	}
+	return bbox, zoom, nil
+}
+
+// parseHotspotQuery разбирает границы области, размер ячейки cell_meters и необязательный порог min_count
+// (0 - порог по умолчанию из HOTSPOT_MIN_INCIDENTS)
+func parseHotspotQuery(c *gin.Context) (geo.BBox, float64, int, error) {
+	bbox, err := parseBBoxQuery(c)
+	if err != nil {
+		return geo.BBox{}, 0, 0, err
+	}
+
+	cellMeters, err := strconv.ParseFloat(c.Query("cell_meters"), 64)
+	if err != nil || cellMeters < service.MinHotspotCellMeters || cellMeters > service.MaxHotspotCellMeters {
+		return geo.BBox{}, 0, 0, fmt.Errorf("cell_meters must be a number between %d and %d", service.MinHotspotCellMeters, service.MaxHotspotCellMeters)
+	}
+
+	var minCount int
+	if value, ok := c.GetQuery("min_count"); ok {
+		minCount, err = strconv.Atoi(value)
+		if err != nil || minCount < 2 {
+			return geo.BBox{}, 0, 0, errors.New("min_count must be an integer of at least 2")
+		}
+	}
+	return bbox, cellMeters, minCount, nil
+}
+
+// parseBBoxQuery разбирает границы области min_lat, min_lon, max_lat и max_lon.
+// Область, пересекающая 180-й меридиан, задается min_lon больше max_lon (например, от 170 до -170).
+func parseBBoxQuery(c *gin.Context) (geo.BBox, error) {
+	var bbox geo.BBox
+	bounds := []struct {
+		name  string
+		value *float64
+	}{
+		{"min_lat", &bbox.MinLat},
+		{"min_lon", &bbox.MinLon},
+		{"max_lat", &bbox.MaxLat},
+		{"max_lon", &bbox.MaxLon},
+	}
+	for _, b := range bounds {
+		value, err := strconv.ParseFloat(c.Query(b.name), 64)
+		if err != nil {
+			return geo.BBox{}, fmt.Errorf("%s must be a number", b.name)
+		}
+		*b.value = value
+	}
+	if err := bbox.Validate(); err != nil {
+		return geo.BBox{}, err
+	}
+	return bbox, nil
+}
@@ -0,0 +1,27 @@
+package v1
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shenikar/geo_broadcasting_system/internal/service"
+)
+
+const (
+	// codeCheckQuotaExceeded - код ошибки, когда пользователь исчерпал квоту проверок местоположения
+	codeCheckQuotaExceeded = "CHECK_QUOTA_EXCEEDED"
+	// codeRateLimited - код ошибки, когда клиент превысил лимит запросов к публичной ленте
+	codeRateLimited = "RATE_LIMITED"
+)
+
+// respondRateLimited отвечает 429 с Retry-After (в целых секундах, с округлением вверх) и кодом ошибки
+func respondRateLimited(c *gin.Context, err *service.RateLimitError, msg, code string) {
+	retryAfter := max(int(math.Ceil(err.RetryAfter.Seconds())), 1)
+	c.Header("Retry-After", strconv.Itoa(retryAfter))
+	c.JSON(http.StatusTooManyRequests, ErrorResponse{
+		Error: msg,
+		Code:  code,
+	})
+}
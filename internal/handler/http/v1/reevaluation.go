@@ -0,0 +1,100 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shenikar/geo_broadcasting_system/internal/models"
+	"github.com/shenikar/geo_broadcasting_system/internal/service"
+)
+
+// defaultReevaluationLimit - число расхождений в ответе повторной оценки, если limit не задан
+const defaultReevaluationLimit = 100
+
+// @Summary Reevaluate stored location checks
+// @Description Re-run the location check for checks stored within [from, to) that were considered safe and report those
+// @Description that fall into an incident active at the time of the check. Incident status is restored from incident versions,
+// @Description the zone is the current one; stored checks are not changed. Pages are ordered by check ID: pass next_after_id
+// @Description as after_id to get the next page. With notify=true a location_check_reevaluated webhook is published for each
+// @Description discrepancy of the page. Requires admin API key.
+// @Tags Admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Param from query string true "RFC 3339 start of the period (inclusive)"
+// @Param to query string false "RFC 3339 end of the period (exclusive), defaults to now"
+// @Param after_id query int false "Only checks with a greater ID (next_after_id of the previous page)"
+// @Param limit query int false "Maximum number of discrepancies (1-1000)" default(100)
+// @Param notify query bool false "Publish catch-up webhooks for the discrepancies" default(false)
+// @Success 200 {object} LocationCheckReevaluationResponse
+// @Failure 400 {object} map[string]string "Invalid period or parameters"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Admin API key required"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} ErrorResponse "Storage unavailable, retry after Retry-After seconds"
+// @Router /admin/location-checks/reevaluate [post]
+func (h *Handler) reevaluateLocationChecks(c *gin.Context) {
+	log := h.logger.WithField("method", "reevaluateLocationChecks")
+
+	q, err := parseReevaluationQuery(c)
+	if err != nil {
+		log.WithError(err).Warn("Invalid reevaluation query")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.incidentService.ReevaluateLocationChecks(c.Request.Context(), q)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidReevaluation) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		log.WithError(err).Error("Failed to reevaluate location checks in service")
+		respondServiceError(c, err, "internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, ModelToLocationCheckReevaluationResponse(q, result))
+}
+
+// parseReevaluationQuery разбирает параметры from, to, after_id, limit и notify
+func parseReevaluationQuery(c *gin.Context) (models.LocationCheckReevaluationQuery, error) {
+	q := models.LocationCheckReevaluationQuery{
+		To:    time.Now().UTC(),
+		Limit: defaultReevaluationLimit,
+	}
+
+	from, err := time.Parse(time.RFC3339Nano, c.Query("from"))
+	if err != nil {
+		return q, errors.New("from must be an RFC 3339 timestamp")
+	}
+	q.From = from
+	if value, ok := c.GetQuery("to"); ok {
+		to, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return q, errors.New("to must be an RFC 3339 timestamp")
+		}
+		q.To = to
+	}
+	if value, ok := c.GetQuery("after_id"); ok {
+		q.AfterID, err = strconv.ParseInt(value, 10, 64)
+		if err != nil || q.AfterID < 0 {
+			return q, errors.New("after_id must be a non-negative integer")
+		}
+	}
+	if value, ok := c.GetQuery("limit"); ok {
+		q.Limit, err = strconv.Atoi(value)
+		if err != nil || q.Limit < 1 || q.Limit > service.MaxReevaluationLimit {
+			return q, errors.New("limit must be an integer between 1 and 1000")
+		}
+	}
+	if value, ok := c.GetQuery("notify"); ok {
+		q.Notify, err = strconv.ParseBool(value)
+		if err != nil {
+			return q, errors.New("notify must be a boolean")
+		}
+	}
+	return q, nil
+}
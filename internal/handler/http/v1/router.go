@@ -2,25 +2,97 @@ package v1
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/shenikar/geo_broadcasting_system/internal/service"
 )
 
 // RegisterRoutes регистрирует все маршруты API v1
 func (h *Handler) RegisterRoutes(api *gin.RouterGroup) {
+	// Учет запросов по API-ключам для всех маршрутов ниже
+	api.Use(h.apiKeyUsageMiddleware())
+	// Отклонение нераспознанных параметров строки запроса (STRICT_QUERY_PARAMS)
+	api.Use(h.strictQueryParamsMiddleware(api.BasePath()))
+
 	// Маршруты для управления инцидентами (CRUD), защищенные API ключом
 	incidents := api.Group("/incidents")
-	incidents.Use(APIKeyAuthMiddleware(h.cfg, h.logger))
+	incidents.Use(APIKeyAuthMiddleware(h.cfg, h.logger, h.incidentService))
 	{
 		incidents.POST("", h.createIncident)
 		incidents.GET("", h.listIncidents)
+		incidents.GET("/mine", h.listMyIncidents)
+		incidents.POST("/validate", h.validateIncident)
+		incidents.POST("/preview-impact", h.featureGate(service.FeaturePreviewImpact), h.previewIncidentImpact)
+		incidents.POST("/from-template/:template_id", h.createIncidentFromTemplate)
+		incidents.POST("/deactivate", h.bulkDeactivateIncidents)
+		incidents.POST("/merge", AdminOnlyMiddleware(h.logger), h.mergeIncidents)
+		incidents.POST("/import-url", AdminOnlyMiddleware(h.logger), h.importIncidentsFromURL)
+		incidents.GET("/import-jobs/:id", AdminOnlyMiddleware(h.logger), h.getImportJob)
+		incidents.POST("/import-feeds", AdminOnlyMiddleware(h.logger), h.registerImportFeed)
+		incidents.GET("/import-feeds", AdminOnlyMiddleware(h.logger), h.listImportFeeds)
+		incidents.DELETE("/import-feeds/:id", AdminOnlyMiddleware(h.logger), h.deleteImportFeed)
+		incidents.GET("/changes", h.listIncidentChanges)
+		incidents.GET("/poll", h.pollIncidentChanges)
+		incidents.GET("/clusters", h.featureGate(service.FeatureClusters), h.getIncidentClusters)
+		incidents.GET("/timeline", h.featureGate(service.FeatureTimeline), h.getIncidentTimeline)
+		incidents.GET("/hotspots", h.featureGate(service.FeatureHotspots), h.getIncidentHotspots)
+		incidents.GET("/by-external/:external_id", h.getIncidentByExternalID)
 		incidents.GET("/:id", h.getIncident)
 		incidents.PUT("/:id", h.updateIncident)
 		incidents.DELETE("/:id", h.deleteIncident)
+		incidents.GET("/:id/impact", h.getIncidentImpact)
+		incidents.GET("/:id/overlapping", h.getOverlappingIncidents)
+		incidents.POST("/:id/attachments/upload-url", h.createAttachmentUploadURL)
+		incidents.GET("/:id/attachments", h.listIncidentAttachments)
+		incidents.POST("/:id/watch", h.watchIncident)
+		incidents.GET("/:id/watch", h.listIncidentWatchers)
+		incidents.DELETE("/:id/watch/:watcher_id", h.unwatchIncident)
+		incidents.GET("/:id/views", AdminOnlyMiddleware(h.logger), h.getIncidentViews)
+		incidents.POST("/:id/approve", AdminOnlyMiddleware(h.logger), h.approveIncident)
+		incidents.POST("/:id/reject", AdminOnlyMiddleware(h.logger), h.rejectIncident)
+		incidents.POST("/:id/escalate", h.featureGate(service.FeatureEscalation), h.escalateIncident)
+		incidents.GET("/:id/versions", h.listIncidentVersions)
+		incidents.POST("/:id/revert/:version", AdminOnlyMiddleware(h.logger), h.revertIncident)
 		incidents.GET("/stats", h.getStats)
 	}
 
-	// Маршрут для проверки местоположения (публичный)
-	api.POST("/location/check", h.checkLocation)
+	// Маршруты для данных пользователей, защищенные API ключом
+	users := api.Group("/users")
+	users.Use(APIKeyAuthMiddleware(h.cfg, h.logger, h.incidentService))
+	{
+		users.GET("/:user_id/checks", h.getUserLocationHistory)
+		users.GET("/:user_id/alerts", h.getUserAlerts)
+		users.GET("/:user_id/status", h.getUserStatus)
+		users.POST("/:user_id/locations", h.addUserLocation)
+		users.GET("/:user_id/locations", h.listUserLocations)
+		users.DELETE("/:user_id/locations/:location_id", h.deleteUserLocation)
+	}
+
+	// Служебные маршруты, доступные только административным API ключам
+	admin := api.Group("/admin")
+	admin.Use(APIKeyAuthMiddleware(h.cfg, h.logger, h.incidentService), AdminOnlyMiddleware(h.logger))
+	{
+		admin.POST("/api-keys", h.addAPIKey)
+		admin.POST("/api-keys/revoke", h.revokeAPIKey)
+		admin.GET("/keys/usage", h.getAPIKeyUsage)
+		admin.GET("/webhooks/queue", h.getWebhookQueue)
+		admin.GET("/webhooks/circuits", h.getWebhookCircuits)
+		admin.POST("/cache/invalidate", h.invalidateCaches)
+		admin.POST("/location-checks/reevaluate", h.reevaluateLocationChecks)
+		admin.POST("/incident-templates", h.createIncidentTemplate)
+		admin.GET("/incident-templates", h.listIncidentTemplates)
+		admin.GET("/incident-templates/:id", h.getIncidentTemplate)
+		admin.PUT("/incident-templates/:id", h.updateIncidentTemplate)
+		admin.DELETE("/incident-templates/:id", h.deleteIncidentTemplate)
+	}
+
+	// Маршрут для проверки местоположения (публичный; с API-ключом проверка помечается как аутентифицированная)
+	api.POST("/location/check", OptionalAPIKeyAuthMiddleware(h.cfg, h.logger, h.incidentService), h.checkLocation)
+
+	// Публичная лента активных инцидентов (без API-ключа, с лимитом запросов по IP)
+	api.GET("/public/incidents", h.featureGate(service.FeaturePublicFeed), h.listPublicIncidents)
 
 	// Маршрут Health-check (публичный)
 	api.GET("/system/health", h.healthCheck)
+
+	// Возможности и ограничения сервера для клиентских SDK (публичный)
+	api.GET("/system/capabilities", h.getCapabilities)
 }
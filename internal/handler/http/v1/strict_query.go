@@ -0,0 +1,64 @@
+package v1
+
+import (
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// routeQueryParams - допустимые параметры строки запроса по маршрутам ("МЕТОД путь" относительно группы API).
+// Маршруты, которых нет в списке, параметров не принимают. При добавлении параметра в обработчик
+// его нужно добавить и сюда, иначе в строгом режиме (STRICT_QUERY_PARAMS) запросы с ним будут отклонены.
+var routeQueryParams = map[string][]string{
+	"GET /incidents": {
+		"page", "pageSize", "near_lat", "near_lon", "within_meters", "min_confidence",
+		"sort_by", "order", "fields", "snapshot_time", "created_by",
+	},
+	"GET /incidents/mine": {
+		"page", "pageSize", "near_lat", "near_lon", "within_meters", "min_confidence",
+		"sort_by", "order", "fields", "snapshot_time",
+	},
+	"GET /incidents/changes":                  {"since", "after_id", "pageSize"},
+	"GET /incidents/poll":                     {"since", "after_id", "timeout"},
+	"GET /incidents/clusters":                 {"min_lat", "min_lon", "max_lat", "max_lon", "zoom"},
+	"GET /incidents/timeline":                 {"from", "to", "interval", "source", "status"},
+	"GET /incidents/hotspots":                 {"min_lat", "min_lon", "max_lat", "max_lon", "cell_meters", "min_count"},
+	"GET /incidents/:id":                      {"fields"},
+	"GET /incidents/by-external/:external_id": {"fields"},
+	"GET /users/:user_id/checks":              {"page", "pageSize"},
+	"GET /public/incidents":                   {"page", "pageSize"},
+	"POST /admin/location-checks/reevaluate":  {"from", "to", "after_id", "limit", "notify"},
+}
+
+// strictQueryParamsMiddleware отклоняет с кодом 400 запросы с нераспознанными параметрами строки запроса,
+// если включен STRICT_QUERY_PARAMS. Без строгого режима неизвестные параметры, как и раньше, игнорируются.
+// basePath - путь группы API, относительно которого заданы маршруты в routeQueryParams.
+func (h *Handler) strictQueryParamsMiddleware(basePath string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Несуществующие маршруты отдают 404 независимо от параметров
+		if !h.cfg.StrictQueryParams || c.FullPath() == "" {
+			c.Next()
+			return
+		}
+
+		route := c.Request.Method + " " + strings.TrimPrefix(c.FullPath(), basePath)
+		allowed := routeQueryParams[route]
+		var unknown []string
+		for name := range c.Request.URL.Query() {
+			if !slices.Contains(allowed, name) {
+				unknown = append(unknown, name)
+			}
+		}
+		if len(unknown) > 0 {
+			sort.Strings(unknown)
+			h.logger.WithFields(logrus.Fields{"route": route, "params": unknown}).Warn("Rejected request with unknown query parameters")
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "unknown query parameters: " + strings.Join(unknown, ", ")})
+			return
+		}
+		c.Next()
+	}
+}
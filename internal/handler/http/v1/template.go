@@ -0,0 +1,245 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/shenikar/geo_broadcasting_system/internal/models"
+	"github.com/shenikar/geo_broadcasting_system/internal/service"
+)
+
+// respondTemplateError отвечает на ошибку операции с шаблоном: 404, 409, 422 для ошибок проверки,
+// иначе как на ошибку сервиса
+func (h *Handler) respondTemplateError(c *gin.Context, err error, method string) {
+	log := h.logger.WithField("method", method)
+	switch {
+	case errors.Is(err, service.ErrTemplateNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "incident template not found"})
+	case errors.Is(err, service.ErrCoordinatesNotAllowed):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrTemplateExists):
+		c.JSON(http.StatusConflict, gin.H{"error": "incident template with this name already exists"})
+	case errors.Is(err, service.ErrInvalidIncident):
+		log.WithError(err).Warn("Incident template rejected by service")
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	default:
+		log.WithError(err).Error("Incident template operation failed in service")
+		respondServiceError(c, err, "internal server error")
+	}
+}
+
+// bindTemplateRequest разбирает и проверяет тело запроса шаблона; при ошибке ответ уже отправлен
+func (h *Handler) bindTemplateRequest(c *gin.Context, method string) (*models.IncidentTemplate, bool) {
+	var input IncidentTemplateRequest
+	log := h.logger.WithField("method", method)
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.WithError(err).Warn("Failed to bind JSON")
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return nil, false
+	}
+	if err := h.validate.Struct(input); err != nil {
+		log.WithError(err).Warn("Validation failed")
+		h.respondValidationError(c, input, err)
+		return nil, false
+	}
+	return DTOToIncidentTemplateModel(input), true
+}
+
+// @Summary Create an incident template
+// @Description Create a named incident template with default description, category and radius. Requires admin API key.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param template body IncidentTemplateRequest true "Incident template"
+// @Success 201 {object} IncidentTemplateResponse
+// @Failure 400 {object} map[string]string "Malformed request body"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Admin API key required"
+// @Failure 409 {object} map[string]string "Template with this name already exists"
+// @Failure 422 {object} ValidationErrorResponse "Validation error"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} ErrorResponse "Storage unavailable, retry after Retry-After seconds"
+// @Router /admin/incident-templates [post]
+func (h *Handler) createIncidentTemplate(c *gin.Context) {
+	template, ok := h.bindTemplateRequest(c, "createIncidentTemplate")
+	if !ok {
+		return
+	}
+	if err := h.incidentService.CreateIncidentTemplate(c.Request.Context(), template); err != nil {
+		h.respondTemplateError(c, err, "createIncidentTemplate")
+		return
+	}
+	c.JSON(http.StatusCreated, ModelToIncidentTemplateResponse(template))
+}
+
+// @Summary List incident templates
+// @Description Get all incident templates ordered by name. Requires admin API key.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} IncidentTemplateResponse
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Admin API key required"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} ErrorResponse "Storage unavailable, retry after Retry-After seconds"
+// @Router /admin/incident-templates [get]
+func (h *Handler) listIncidentTemplates(c *gin.Context) {
+	templates, err := h.incidentService.ListIncidentTemplates(c.Request.Context())
+	if err != nil {
+		h.respondTemplateError(c, err, "listIncidentTemplates")
+		return
+	}
+	c.JSON(http.StatusOK, ModelsToIncidentTemplateResponses(templates))
+}
+
+// @Summary Get an incident template
+// @Description Get an incident template by ID. Requires admin API key.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Template ID"
+// @Success 200 {object} IncidentTemplateResponse
+// @Failure 400 {object} map[string]string "Invalid template ID"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Admin API key required"
+// @Failure 404 {object} map[string]string "Template not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} ErrorResponse "Storage unavailable, retry after Retry-After seconds"
+// @Router /admin/incident-templates/{id} [get]
+func (h *Handler) getIncidentTemplate(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid template ID"})
+		return
+	}
+
+	template, err := h.incidentService.GetIncidentTemplate(c.Request.Context(), id)
+	if err != nil {
+		h.respondTemplateError(c, err, "getIncidentTemplate")
+		return
+	}
+	c.JSON(http.StatusOK, ModelToIncidentTemplateResponse(template))
+}
+
+// @Summary Update an incident template
+// @Description Replace an incident template. Incidents created from it earlier are not changed. Requires admin API key.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Template ID"
+// @Param template body IncidentTemplateRequest true "Incident template"
+// @Success 200 {object} IncidentTemplateResponse
+// @Failure 400 {object} map[string]string "Invalid template ID or malformed request body"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Admin API key required"
+// @Failure 404 {object} map[string]string "Template not found"
+// @Failure 409 {object} map[string]string "Template with this name already exists"
+// @Failure 422 {object} ValidationErrorResponse "Validation error"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} ErrorResponse "Storage unavailable, retry after Retry-After seconds"
+// @Router /admin/incident-templates/{id} [put]
+func (h *Handler) updateIncidentTemplate(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid template ID"})
+		return
+	}
+	template, ok := h.bindTemplateRequest(c, "updateIncidentTemplate")
+	if !ok {
+		return
+	}
+	template.ID = id
+
+	if err := h.incidentService.UpdateIncidentTemplate(c.Request.Context(), template); err != nil {
+		h.respondTemplateError(c, err, "updateIncidentTemplate")
+		return
+	}
+	c.JSON(http.StatusOK, ModelToIncidentTemplateResponse(template))
+}
+
+// @Summary Delete an incident template
+// @Description Delete an incident template. Incidents created from it are not affected. Requires admin API key.
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Param id path string true "Template ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} map[string]string "Invalid template ID"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Admin API key required"
+// @Failure 404 {object} map[string]string "Template not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} ErrorResponse "Storage unavailable, retry after Retry-After seconds"
+// @Router /admin/incident-templates/{id} [delete]
+func (h *Handler) deleteIncidentTemplate(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid template ID"})
+		return
+	}
+
+	if err := h.incidentService.DeleteIncidentTemplate(c.Request.Context(), id); err != nil {
+		h.respondTemplateError(c, err, "deleteIncidentTemplate")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Create an incident from a template
+// @Description Create an incident at the given coordinates using the template's name, description, category and radius.
+// @Description Name and description can be overridden. Requires API key.
+// @Tags Incidents
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param template_id path string true "Template ID"
+// @Param request body CreateFromTemplateRequest true "Incident coordinates and overrides"
+// @Success 201 {object} IncidentResponse
+// @Failure 400 {object} map[string]string "Invalid template ID or malformed request body"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Template not found"
+// @Failure 422 {object} ValidationErrorResponse "Validation error or coordinates outside of the service area"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} ErrorResponse "Storage unavailable, retry after Retry-After seconds"
+// @Router /incidents/from-template/{template_id} [post]
+func (h *Handler) createIncidentFromTemplate(c *gin.Context) {
+	templateID, err := uuid.Parse(c.Param("template_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid template ID"})
+		return
+	}
+	var input CreateFromTemplateRequest
+	log := h.logger.WithField("method", "createIncidentFromTemplate").WithField("template_id", templateID)
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.WithError(err).Warn("Failed to bind JSON")
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	if err := h.validate.Struct(input); err != nil {
+		log.WithError(err).Warn("Validation failed")
+		h.respondValidationError(c, input, err)
+		return
+	}
+
+	incident := &models.Incident{
+		Name:        input.Name,
+		Description: input.Description,
+		Latitude:    input.Latitude,
+		Longitude:   input.Longitude,
+		Status:      models.IncidentStatus(input.Status),
+		Confidence:  input.Confidence,
+		CreatedBy:   c.GetString(apiKeyLabelContextKey),
+	}
+	if err := h.incidentService.CreateIncidentFromTemplate(c.Request.Context(), templateID, incident); err != nil {
+		h.respondTemplateError(c, err, "createIncidentFromTemplate")
+		return
+	}
+	c.JSON(http.StatusCreated, ModelToIncidentResponse(incident))
+}
@@ -0,0 +1,92 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shenikar/geo_broadcasting_system/internal/models"
+	"github.com/shenikar/geo_broadcasting_system/internal/service"
+)
+
+// timelineSources - допустимые значения фильтра источника временной шкалы
+var timelineSources = []string{models.SourceOfficial, models.SourceCrowdsourced, models.SourceSensor}
+
+// @Summary Get incident creation timeline
+// @Description Count incidents created per hour or day bucket (UTC) within [from, to). Empty buckets are returned with zero count.
+// @Description Optionally filter by source and status. Requires API key.
+// @Tags Incidents
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param from query string true "RFC 3339 start of the period (inclusive)"
+// @Param to query string false "RFC 3339 end of the period (exclusive), defaults to now"
+// @Param interval query string false "Bucket size" Enums(hour, day) default(day)
+// @Param source query string false "Only incidents from this source" Enums(official, crowdsourced, sensor)
+// @Param status query string false "Only incidents in this status" Enums(active, inactive, draft, rejected, resolved)
+// @Success 200 {object} TimelineResponse
+// @Failure 400 {object} map[string]string "Invalid period, interval or filter"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} ErrorResponse "Feature disabled (FEATURE_DISABLED)"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} ErrorResponse "Storage unavailable, retry after Retry-After seconds"
+// @Router /incidents/timeline [get]
+func (h *Handler) getIncidentTimeline(c *gin.Context) {
+	log := h.logger.WithField("method", "getIncidentTimeline")
+
+	q, err := parseTimelineQuery(c)
+	if err != nil {
+		log.WithError(err).Warn("Invalid timeline query")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	buckets, err := h.incidentService.GetIncidentTimeline(c.Request.Context(), q)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidTimeline) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		log.WithError(err).Error("Failed to get incident timeline from service")
+		respondServiceError(c, err, "internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, ModelsToTimelineResponse(q.Interval, buckets))
+}
+
+// parseTimelineQuery разбирает параметры from, to, interval, source и status
+func parseTimelineQuery(c *gin.Context) (models.TimelineQuery, error) {
+	q := models.TimelineQuery{
+		Interval: c.DefaultQuery("interval", models.TimelineDay),
+		Source:   c.Query("source"),
+		Status:   models.IncidentStatus(c.Query("status")),
+		To:       time.Now().UTC(),
+	}
+
+	from, err := time.Parse(time.RFC3339Nano, c.Query("from"))
+	if err != nil {
+		return q, errors.New("from must be an RFC 3339 timestamp")
+	}
+	q.From = from
+	if value, ok := c.GetQuery("to"); ok {
+		to, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return q, errors.New("to must be an RFC 3339 timestamp")
+		}
+		q.To = to
+	}
+
+	if q.Interval != models.TimelineHour && q.Interval != models.TimelineDay {
+		return q, errors.New("interval must be 'hour' or 'day'")
+	}
+	if q.Source != "" && !slices.Contains(timelineSources, q.Source) {
+		return q, errors.New("source must be one of official, crowdsourced, sensor")
+	}
+	if q.Status != "" && !q.Status.IsValid() {
+		return q, errors.New("status must be one of active, inactive, draft, rejected, resolved")
+	}
+	return q, nil
+}
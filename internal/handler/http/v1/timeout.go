@@ -0,0 +1,54 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// codeRequestTimeout - код ошибки, когда запрос не уложился в REQUEST_TIMEOUT
+const codeRequestTimeout = "REQUEST_TIMEOUT"
+
+// TimeoutMiddleware - middleware, ограничивающее время обработки запроса.
+// Контекст запроса получает дедлайн timeout, поэтому запросы к PostgreSQL и Redis прерываются по его истечении,
+// а клиент получает 504. Обработчик выполняется в той же горутине: ответ отправляется, когда он вернет управление
+// после отмены контекста. Пути из skipPaths (потоковые ответы) не ограничиваются; timeout <= 0 отключает ограничение.
+func TimeoutMiddleware(timeout time.Duration, skipPaths ...string) gin.HandlerFunc {
+	skip := make(map[string]struct{}, len(skipPaths))
+	for _, path := range skipPaths {
+		skip[path] = struct{}{}
+	}
+
+	return func(c *gin.Context) {
+		if _, ok := skip[c.Request.URL.Path]; ok || timeout <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		if isRequestTimedOut(c) && !c.Writer.Written() {
+			respondTimeout(c)
+		}
+	}
+}
+
+// isRequestTimedOut сообщает, истек ли дедлайн контекста запроса
+func isRequestTimedOut(c *gin.Context) bool {
+	return errors.Is(c.Request.Context().Err(), context.DeadlineExceeded)
+}
+
+// respondTimeout отвечает 504 с кодом REQUEST_TIMEOUT
+func respondTimeout(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusGatewayTimeout, ErrorResponse{
+		Error: "request timed out",
+		Code:  codeRequestTimeout,
+	})
+}
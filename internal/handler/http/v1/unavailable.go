@@ -0,0 +1,52 @@
+package v1
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shenikar/geo_broadcasting_system/pkg/postgres"
+	redisclient "github.com/shenikar/geo_broadcasting_system/pkg/redis"
+)
+
+const (
+	// codeServiceUnavailable - код ошибки, когда запрос не выполнен из-за недоступности PostgreSQL или Redis
+	codeServiceUnavailable = "SERVICE_UNAVAILABLE"
+	// unavailableRetryAfterSeconds - рекомендуемая клиенту пауза перед повтором запроса
+	unavailableRetryAfterSeconds = 5
+)
+
+// isDependencyUnavailable сообщает, вызвана ли ошибка сервиса недоступностью хранилищ, а не ошибкой в запросе или коде.
+// Сетевые ошибки (отказ в соединении, обрыв, таймаут) считаются недоступностью для любой зависимости.
+func isDependencyUnavailable(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	return postgres.IsUnavailable(err) || redisclient.IsUnavailable(err)
+}
+
+// respondUnavailable отвечает 503 с Retry-After и кодом SERVICE_UNAVAILABLE
+func respondUnavailable(c *gin.Context) {
+	c.Header("Retry-After", strconv.Itoa(unavailableRetryAfterSeconds))
+	c.AbortWithStatusJSON(http.StatusServiceUnavailable, ErrorResponse{
+		Error: "service temporarily unavailable",
+		Code:  codeServiceUnavailable,
+	})
+}
+
+// respondServiceError отвечает 504, если истек дедлайн запроса (REQUEST_TIMEOUT), 503, если ошибка вызвана
+// недоступностью хранилищ, и 500 с message в остальных случаях
+func respondServiceError(c *gin.Context, err error, message string) {
+	if isRequestTimedOut(c) {
+		respondTimeout(c)
+		return
+	}
+	if isDependencyUnavailable(err) {
+		respondUnavailable(c)
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+}
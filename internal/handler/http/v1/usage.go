@@ -0,0 +1,59 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// apiKeyRequestsCounter - количество запросов по меткам API-ключей с момента запуска инстанса
+var apiKeyRequestsCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "geo_api_key_requests_total",
+	Help: "Number of requests authenticated with an API key, by key label.",
+}, []string{"api_key"})
+
+// apiKeyUsageMiddleware учитывает запросы, прошедшие аутентификацию по API-ключу.
+// Учет выполняется после обработки запроса, поскольку метку ключа устанавливает middleware аутентификации группы;
+// ошибка записи в Redis не влияет на ответ.
+func (h *Handler) apiKeyUsageMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		label := c.GetString(apiKeyLabelContextKey)
+		if label == "" {
+			return
+		}
+		apiKeyRequestsCounter.WithLabelValues(label).Inc()
+		if err := h.incidentService.RecordAPIKeyUsage(c.Request.Context(), label); err != nil {
+			h.logger.WithError(err).WithField("api_key", label).Warn("Failed to record api key usage")
+		}
+	}
+}
+
+// @Summary Get API key usage
+// @Description Get the number of requests made with each API key within API_KEY_USAGE_WINDOW, most active keys first.
+// @Description Keys are identified by their non-secret label. Requires admin API key.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} APIKeyUsageResponse
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Admin API key required"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} ErrorResponse "Storage unavailable, retry after Retry-After seconds"
+// @Router /admin/keys/usage [get]
+func (h *Handler) getAPIKeyUsage(c *gin.Context) {
+	log := h.logger.WithField("method", "getAPIKeyUsage")
+
+	usage, err := h.incidentService.GetAPIKeyUsage(c.Request.Context())
+	if err != nil {
+		log.WithError(err).Error("Failed to get api key usage from service")
+		respondServiceError(c, err, "internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, ModelsToAPIKeyUsageResponses(usage))
+}
@@ -0,0 +1,185 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/shenikar/geo_broadcasting_system/internal/models"
+	"github.com/shenikar/geo_broadcasting_system/internal/service"
+)
+
+// @Summary Save a user location
+// @Description Save a labeled location (e.g. home, work) for proactive danger zone alerts. Labels are unique per user. Requires API key.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param user_id path string true "User ID"
+// @Param location body UserLocationRequest true "User location"
+// @Success 201 {object} UserLocationResponse
+// @Failure 400 {object} map[string]string "Malformed request body"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 409 {object} map[string]string "Location with this label already exists"
+// @Failure 422 {object} ValidationErrorResponse "Validation error"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} ErrorResponse "Storage unavailable, retry after Retry-After seconds"
+// @Router /users/{user_id}/locations [post]
+func (h *Handler) addUserLocation(c *gin.Context) {
+	userID := c.Param("user_id")
+	log := h.logger.WithField("method", "addUserLocation").WithField("user_id", userID)
+
+	var input UserLocationRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.WithError(err).Warn("Failed to bind JSON")
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	if err := h.validate.Struct(input); err != nil {
+		log.WithError(err).Warn("Validation failed")
+		h.respondValidationError(c, input, err)
+		return
+	}
+
+	location := &models.UserLocation{
+		UserID:    userID,
+		Label:     input.Label,
+		Latitude:  input.Latitude,
+		Longitude: input.Longitude,
+	}
+	if err := h.incidentService.AddUserLocation(c.Request.Context(), location); err != nil {
+		if errors.Is(err, service.ErrUserLocationExists) {
+			c.JSON(http.StatusConflict, gin.H{"error": "location with this label already exists"})
+			return
+		}
+		log.WithError(err).Error("Failed to save user location in service")
+		respondServiceError(c, err, "internal server error")
+		return
+	}
+
+	c.JSON(http.StatusCreated, ModelToUserLocationResponse(location))
+}
+
+// @Summary List user locations
+// @Description Get all saved locations of a user. Requires API key.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param user_id path string true "User ID"
+// @Success 200 {array} UserLocationResponse
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} ErrorResponse "Storage unavailable, retry after Retry-After seconds"
+// @Router /users/{user_id}/locations [get]
+func (h *Handler) listUserLocations(c *gin.Context) {
+	userID := c.Param("user_id")
+	log := h.logger.WithField("method", "listUserLocations").WithField("user_id", userID)
+
+	locations, err := h.incidentService.ListUserLocations(c.Request.Context(), userID)
+	if err != nil {
+		log.WithError(err).Error("Failed to list user locations from service")
+		respondServiceError(c, err, "internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, ModelsToUserLocationResponses(locations))
+}
+
+// @Summary Delete a user location
+// @Description Delete a saved location of a user. Requires API key.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param user_id path string true "User ID"
+// @Param location_id path string true "Location ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} map[string]string "Invalid location ID"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Location not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} ErrorResponse "Storage unavailable, retry after Retry-After seconds"
+// @Router /users/{user_id}/locations/{location_id} [delete]
+func (h *Handler) deleteUserLocation(c *gin.Context) {
+	userID := c.Param("user_id")
+	id, err := uuid.Parse(c.Param("location_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid location ID"})
+		return
+	}
+	log := h.logger.WithField("method", "deleteUserLocation").WithField("user_id", userID).WithField("location_id", id)
+
+	if err := h.incidentService.DeleteUserLocation(c.Request.Context(), userID, id); err != nil {
+		if errors.Is(err, service.ErrUserLocationNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "location not found"})
+			return
+		}
+		log.WithError(err).Error("Failed to delete user location in service")
+		respondServiceError(c, err, "internal server error")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Get alerts for user locations
+// @Description Get active incidents whose danger zone covers any of the user's saved locations, grouped by location label.
+// @Description Locations without matching incidents are omitted. Requires API key.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param user_id path string true "User ID"
+// @Success 200 {array} UserAlertResponse
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} ErrorResponse "Storage unavailable, retry after Retry-After seconds"
+// @Router /users/{user_id}/alerts [get]
+func (h *Handler) getUserAlerts(c *gin.Context) {
+	userID := c.Param("user_id")
+	log := h.logger.WithField("method", "getUserAlerts").WithField("user_id", userID)
+
+	alerts, err := h.incidentService.GetUserAlerts(c.Request.Context(), userID)
+	if err != nil {
+		log.WithError(err).Error("Failed to get user alerts from service")
+		respondServiceError(c, err, "internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, ModelsToUserAlertResponses(alerts))
+}
+
+// @Summary Get current user danger status
+// @Description Re-evaluate the coordinates of the user's most recent location check against currently active incidents.
+// @Description The check is not recorded and no webhook is sent. Requires API key.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param user_id path string true "User ID"
+// @Success 200 {object} UserStatusResponse
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "User has no location checks"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} ErrorResponse "Storage unavailable, retry after Retry-After seconds"
+// @Router /users/{user_id}/status [get]
+func (h *Handler) getUserStatus(c *gin.Context) {
+	userID := c.Param("user_id")
+	log := h.logger.WithField("method", "getUserStatus").WithField("user_id", userID)
+
+	status, err := h.incidentService.GetUserStatus(c.Request.Context(), userID)
+	if err != nil {
+		if errors.Is(err, service.ErrNoLocationCheck) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "user has no location checks"})
+			return
+		}
+		log.WithError(err).Error("Failed to get user status from service")
+		respondServiceError(c, err, "internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, ModelToUserStatusResponse(status))
+}
@@ -0,0 +1,168 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"golang.org/x/text/language"
+)
+
+// supportedLanguages - языки сообщений валидации; первый используется, если язык по умолчанию не поддерживается
+var supportedLanguages = []language.Tag{language.English, language.Russian}
+
+var languageMatcher = language.NewMatcher(supportedLanguages)
+
+// validationMessages - переводы сообщений для тегов валидатора. %s заменяется параметром тега.
+// Для строк и списков min/max/gt/gte ограничивают длину, поэтому у них отдельные сообщения.
+var validationMessages = map[string]map[string]string{
+	"en": {
+		"required":  "is required",
+		"latitude":  "must be a valid latitude between -90 and 90",
+		"longitude": "must be a valid longitude between -180 and 180",
+		"oneof":     "must be one of: %s",
+		"url":       "must be a valid URL",
+		"min":       "must be at least %s",
+		"max":       "must be at most %s",
+		"gt":        "must be greater than %s",
+		"gte":       "must be greater than or equal to %s",
+		"min_len":   "must contain at least %s characters or items",
+		"max_len":   "must contain at most %s characters or items",
+		"gt_len":    "must contain more than %s characters or items",
+		"gte_len":   "must contain at least %s characters or items",
+		"default":   "is invalid",
+	},
+	"ru": {
+		"required":  "обязательное поле",
+		"latitude":  "должно быть корректной широтой от -90 до 90",
+		"longitude": "должно быть корректной долготой от -180 до 180",
+		"oneof":     "должно быть одним из значений: %s",
+		"url":       "должно быть корректным URL",
+		"min":       "должно быть не меньше %s",
+		"max":       "должно быть не больше %s",
+		"gt":        "должно быть больше %s",
+		"gte":       "должно быть больше или равно %s",
+		"min_len":   "должно содержать не менее %s символов или элементов",
+		"max_len":   "должно содержать не более %s символов или элементов",
+		"gt_len":    "должно содержать более %s символов или элементов",
+		"gte_len":   "должно содержать не менее %s символов или элементов",
+		"default":   "некорректное значение",
+	},
+}
+
+// FieldError описывает ошибку валидации одного поля запроса
+// @Description Ошибка валидации одного поля запроса
+type FieldError struct {
+	Field   string `json:"field"`
+	Tag     string `json:"tag"`
+	Message string `json:"message"`
+}
+
+// ValidationErrorResponse DTO для ответа с ошибками валидации.
+// Error содержит исходный текст ошибки валидатора, Details - локализованные сообщения по полям.
+// @Description DTO для ответа с ошибками валидации
+type ValidationErrorResponse struct {
+	Error   string       `json:"error"`
+	Details []FieldError `json:"details,omitempty"`
+}
+
+// respondValidationError отвечает 422 с локализованными по Accept-Language сообщениями для каждого поля.
+// Код 400 остается за телом, которое не удалось разобрать: так клиент отличает неверный JSON от неверных значений.
+func (h *Handler) respondValidationError(c *gin.Context, input any, err error) {
+	resp := ValidationErrorResponse{Error: err.Error()}
+
+	var fieldErrors validator.ValidationErrors
+	if errors.As(err, &fieldErrors) {
+		lang := h.requestLanguage(c)
+		for _, fe := range fieldErrors {
+			resp.Details = append(resp.Details, FieldError{
+				Field:   jsonFieldPath(reflect.TypeOf(input), fe.StructNamespace()),
+				Tag:     fe.Tag(),
+				Message: translateFieldError(lang, fe),
+			})
+		}
+	}
+
+	c.JSON(http.StatusUnprocessableEntity, resp)
+}
+
+// requestLanguage выбирает язык сообщений по заголовку Accept-Language, а если заголовок
+// отсутствует или не содержит поддерживаемых языков - язык по умолчанию из конфигурации (DEFAULT_LANGUAGE)
+func (h *Handler) requestLanguage(c *gin.Context) string {
+	header := c.GetHeader("Accept-Language")
+	if header == "" {
+		header = h.cfg.DefaultLanguage
+	}
+	tags, _, _ := language.ParseAcceptLanguage(header)
+	if len(tags) == 0 {
+		tags, _, _ = language.ParseAcceptLanguage(h.cfg.DefaultLanguage)
+	}
+	_, index, confidence := languageMatcher.Match(tags...)
+	if confidence == language.No {
+		return h.cfg.DefaultLanguage
+	}
+	base, _ := supportedLanguages[index].Base()
+	return base.String()
+}
+
+// translateFieldError возвращает сообщение об ошибке поля на выбранном языке
+func translateFieldError(lang string, fe validator.FieldError) string {
+	messages, ok := validationMessages[lang]
+	if !ok {
+		messages = validationMessages["en"]
+	}
+
+	key := fe.Tag()
+	switch fe.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		if _, ok := messages[key+"_len"]; ok {
+			key += "_len"
+		}
+	}
+
+	message, ok := messages[key]
+	if !ok {
+		return messages["default"]
+	}
+	if strings.Contains(message, "%s") {
+		return fmt.Sprintf(message, fe.Param())
+	}
+	return message
+}
+
+// jsonFieldPath переводит путь поля в структуре (например, CreateIncidentRequest.MediaURLs[0])
+// в путь по именам JSON (media_urls[0]), чтобы клиент мог сопоставить ошибку с полем запроса
+func jsonFieldPath(t reflect.Type, structNamespace string) string {
+	segments := strings.Split(structNamespace, ".")
+	if len(segments) > 1 {
+		segments = segments[1:] // Первый сегмент - имя самой структуры
+	}
+
+	path := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		name, index := segment, ""
+		if i := strings.IndexByte(segment, '['); i >= 0 {
+			name, index = segment[:i], segment[i:]
+		}
+
+		for t != nil && (t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+			t = t.Elem()
+		}
+		if t != nil && t.Kind() == reflect.Struct {
+			if field, ok := t.FieldByName(name); ok {
+				if tag := strings.Split(field.Tag.Get("json"), ",")[0]; tag != "" && tag != "-" {
+					name = tag
+				}
+				t = field.Type
+			} else {
+				t = nil
+			}
+		}
+		path = append(path, name+index)
+	}
+	return strings.Join(path, ".")
+}
@@ -0,0 +1,93 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/shenikar/geo_broadcasting_system/internal/service"
+)
+
+// @Summary List incident versions
+// @Description Get full snapshots of every version of an incident, newest first. Version 1 is the state at creation. Requires API key.
+// @Tags Incidents
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Incident ID"
+// @Success 200 {array} IncidentVersionResponse
+// @Failure 400 {object} map[string]string "Invalid incident ID"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Incident not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} ErrorResponse "Storage unavailable, retry after Retry-After seconds"
+// @Router /incidents/{id}/versions [get]
+func (h *Handler) listIncidentVersions(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid incident ID"})
+		return
+	}
+	log := h.logger.WithField("method", "listIncidentVersions").WithField("id", id)
+
+	versions, err := h.incidentService.ListIncidentVersions(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrIncidentNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "incident not found"})
+			return
+		}
+		log.WithError(err).Error("Failed to list incident versions in service")
+		respondServiceError(c, err, "internal server error")
+		return
+	}
+	c.JSON(http.StatusOK, ModelsToIncidentVersionResponses(versions))
+}
+
+// @Summary Revert an incident to a version
+// @Description Restore the incident state saved in the given version. The revert is recorded as a new version,
+// @Description a status change is recorded in the status history and subscribers receive an incident_updated webhook.
+// @Description Status transition rules do not apply to a revert. Requires admin API key.
+// @Tags Incidents
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Incident ID"
+// @Param version path int true "Version number"
+// @Success 200 {object} IncidentResponse
+// @Failure 400 {object} map[string]string "Invalid incident ID or version"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Admin API key required"
+// @Failure 404 {object} map[string]string "Incident or version not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} ErrorResponse "Storage unavailable, retry after Retry-After seconds"
+// @Router /incidents/{id}/revert/{version} [post]
+func (h *Handler) revertIncident(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid incident ID"})
+		return
+	}
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid version"})
+		return
+	}
+	log := h.logger.WithField("method", "revertIncident").WithField("id", id).WithField("version", version)
+
+	incident, err := h.incidentService.RevertIncident(c.Request.Context(), id, version, c.GetString(apiKeyLabelContextKey))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrIncidentNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "incident not found"})
+		case errors.Is(err, service.ErrVersionNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "incident version not found"})
+		default:
+			log.WithError(err).Error("Failed to revert incident in service")
+			respondServiceError(c, err, "internal server error")
+		}
+		return
+	}
+	c.JSON(http.StatusOK, ModelToIncidentResponse(incident))
+}
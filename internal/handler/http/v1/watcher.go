@@ -0,0 +1,156 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/shenikar/geo_broadcasting_system/internal/models"
+	"github.com/shenikar/geo_broadcasting_system/internal/service"
+)
+
+// @Summary Watch an incident
+// @Description Register a callback URL that receives every lifecycle webhook of the incident (incident_approved, incident_updated,
+// @Description incident_escalated, incident_deactivated) in addition to WEBHOOK_URL and WEBHOOK_SUBSCRIPTIONS_FILE subscriptions.
+// @Description Deliveries use the same payload, retries and circuit breaker as subscriptions and are signed only with the watcher
+// @Description secret. The URL is checked against WATCH_URL_ALLOWED_HOSTS and must resolve to public addresses. Requires API key.
+// @Tags Incidents
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Incident ID"
+// @Param request body WatchIncidentRequest true "Callback URL and optional signing secret"
+// @Success 201 {object} WatcherResponse
+// @Failure 400 {object} map[string]string "Invalid incident ID, request body or URL not allowed"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Incident not found"
+// @Failure 409 {object} map[string]string "URL already watches this incident"
+// @Failure 422 {object} ValidationErrorResponse "Validation error"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} ErrorResponse "Storage unavailable, retry after Retry-After seconds"
+// @Router /incidents/{id}/watch [post]
+func (h *Handler) watchIncident(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid incident ID"})
+		return
+	}
+	log := h.logger.WithField("method", "watchIncident").WithField("id", id)
+
+	var input WatchIncidentRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.WithError(err).Warn("Failed to bind JSON")
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	if err := h.validate.Struct(input); err != nil {
+		log.WithError(err).Warn("Validation failed")
+		h.respondValidationError(c, input, err)
+		return
+	}
+
+	watcher := &models.IncidentWatcher{
+		IncidentID: id,
+		URL:        input.URL,
+		Secret:     input.Secret,
+		CreatedBy:  c.GetString(apiKeyLabelContextKey),
+	}
+	if err := h.incidentService.WatchIncident(c.Request.Context(), watcher); err != nil {
+		switch {
+		case errors.Is(err, service.ErrWatchURLNotAllowed):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrIncidentNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "incident not found"})
+		case errors.Is(err, service.ErrWatcherExists):
+			c.JSON(http.StatusConflict, gin.H{"error": "url already watches this incident"})
+		default:
+			log.WithError(err).Error("Failed to watch incident in service")
+			respondServiceError(c, err, "failed to watch incident")
+		}
+		return
+	}
+	c.JSON(http.StatusCreated, ModelToWatcherResponse(watcher))
+}
+
+// @Summary List incident watchers
+// @Description List callback URLs watching the incident. Signing secrets are not returned. Requires API key.
+// @Tags Incidents
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Incident ID"
+// @Success 200 {array} WatcherResponse
+// @Failure 400 {object} map[string]string "Invalid incident ID"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Incident not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} ErrorResponse "Storage unavailable, retry after Retry-After seconds"
+// @Router /incidents/{id}/watch [get]
+func (h *Handler) listIncidentWatchers(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid incident ID"})
+		return
+	}
+
+	watchers, err := h.incidentService.ListIncidentWatchers(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrIncidentNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "incident not found"})
+			return
+		}
+		h.logger.WithField("method", "listIncidentWatchers").WithError(err).Error("Failed to list incident watchers from service")
+		respondServiceError(c, err, "internal server error")
+		return
+	}
+
+	responses := make([]*WatcherResponse, len(watchers))
+	for i, watcher := range watchers {
+		responses[i] = ModelToWatcherResponse(watcher)
+	}
+	c.JSON(http.StatusOK, responses)
+}
+
+// @Summary Unwatch an incident
+// @Description Remove a watcher from the incident; its callback URL stops receiving the incident webhooks. Requires API key:
+// @Description the key that added the watcher or an admin key.
+// @Tags Incidents
+// @Security ApiKeyAuth
+// @Param id path string true "Incident ID"
+// @Param watcher_id path string true "Watcher ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} map[string]string "Invalid incident or watcher ID"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Watcher was added by another API key"
+// @Failure 404 {object} map[string]string "Watcher not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} ErrorResponse "Storage unavailable, retry after Retry-After seconds"
+// @Router /incidents/{id}/watch/{watcher_id} [delete]
+func (h *Handler) unwatchIncident(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid incident ID"})
+		return
+	}
+	watcherID, err := uuid.Parse(c.Param("watcher_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid watcher ID"})
+		return
+	}
+
+	requestedBy, admin := c.GetString(apiKeyLabelContextKey), c.GetBool(apiKeyAdminContextKey)
+	if err := h.incidentService.UnwatchIncident(c.Request.Context(), id, watcherID, requestedBy, admin); err != nil {
+		switch {
+		case errors.Is(err, service.ErrWatcherNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "watcher not found"})
+			return
+		case errors.Is(err, service.ErrWatcherForbidden):
+			c.JSON(http.StatusForbidden, gin.H{"error": "watcher was added by another API key"})
+			return
+		}
+		h.logger.WithField("method", "unwatchIncident").WithError(err).Error("Failed to unwatch incident in service")
+		respondServiceError(c, err, "internal server error")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,74 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shenikar/geo_broadcasting_system/internal/webhook"
+)
+
+// @Summary Get webhook queue depth
+// @Description Get the number of webhook events waiting for delivery. A growing depth usually means the receiver is down. Requires admin API key.
+// @Tags Admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} WebhookQueueResponse
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Admin API key required"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} ErrorResponse "Storage unavailable, retry after Retry-After seconds"
+// @Router /admin/webhooks/queue [get]
+func (h *Handler) getWebhookQueue(c *gin.Context) {
+	log := h.logger.WithField("method", "getWebhookQueue")
+
+	depth, err := h.incidentService.GetWebhookQueueDepth(c.Request.Context())
+	if err != nil {
+		log.WithError(err).Error("Failed to get webhook queue depth from service")
+		respondServiceError(c, err, "internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, WebhookQueueResponse{
+		Backend:        h.cfg.WebhookQueueBackend,
+		Depth:          depth,
+		AlarmThreshold: h.cfg.WebhookQueueAlarmThreshold,
+	})
+}
+
+// @Summary Get webhook destination circuits
+// @Description Get circuit breaker state per webhook destination and the number of webhooks in the dead letter queue. Deliveries to an open circuit are not attempted until the cooldown expires. Requires admin API key.
+// @Tags Admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} WebhookCircuitsResponse
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Admin API key required"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} ErrorResponse "Storage unavailable, retry after Retry-After seconds"
+// @Router /admin/webhooks/circuits [get]
+func (h *Handler) getWebhookCircuits(c *gin.Context) {
+	log := h.logger.WithField("method", "getWebhookCircuits")
+
+	circuits, deadLetters, err := h.incidentService.GetWebhookCircuits(c.Request.Context())
+	if err != nil {
+		log.WithError(err).Error("Failed to get webhook circuits from service")
+		respondServiceError(c, err, "internal server error")
+		return
+	}
+
+	resp := WebhookCircuitsResponse{
+		Circuits:         make([]WebhookCircuitResponse, 0, len(circuits)),
+		DeadLetterDepth:  deadLetters,
+		FailureThreshold: h.cfg.WebhookCircuitFailureThreshold,
+	}
+	for _, circuit := range circuits {
+		resp.Circuits = append(resp.Circuits, WebhookCircuitResponse{
+			URL:                 circuit.URL,
+			State:               circuit.State,
+			ConsecutiveFailures: circuit.ConsecutiveFailures,
+			OpenedAt:            circuit.OpenedAt,
+			Destination:         webhook.DestinationLabel(circuit.URL),
+		})
+	}
+	c.JSON(http.StatusOK, resp)
+}
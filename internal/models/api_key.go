@@ -0,0 +1,8 @@
+package models
+
+// APIKeyUsage - количество запросов с API-ключом за окно учета
+type APIKeyUsage struct {
+	// Label - несекретная метка ключа (как в логах)
+	Label    string `json:"label"`
+	Requests int64  `json:"requests"`
+}
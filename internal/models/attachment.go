@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IncidentAttachment - вложение инцидента, хранящееся в объектном хранилище под ключом ObjectKey
+type IncidentAttachment struct {
+	ID          uuid.UUID
+	IncidentID  uuid.UUID
+	ObjectKey   string
+	Filename    string
+	ContentType string
+	// CreatedBy - метка API-ключа, запросившего загрузку
+	CreatedBy string
+	CreatedAt time.Time
+}
+
+// PresignedAttachment - вложение вместе с presigned URL для загрузки или скачивания
+type PresignedAttachment struct {
+	Attachment *IncidentAttachment
+	URL        string
+	ExpiresAt  time.Time
+}
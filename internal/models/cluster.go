@@ -0,0 +1,26 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"github.com/shenikar/geo_broadcasting_system/pkg/geo"
+)
+
+// IncidentCluster - группа активных инцидентов, попавших в одну ячейку сетки карты
+type IncidentCluster struct {
+	// Latitude и Longitude - среднее положение центров инцидентов кластера
+	Latitude  float64
+	Longitude float64
+	Count     int
+	// IncidentID заполняется, если кластер состоит из одного инцидента
+	IncidentID *uuid.UUID
+}
+
+// IncidentHotspot - ячейка сетки, в которую попало не меньше порогового числа активных инцидентов
+type IncidentHotspot struct {
+	// Latitude и Longitude - центр ячейки
+	Latitude  float64
+	Longitude float64
+	// Bounds - границы ячейки
+	Bounds geo.BBox
+	Count  int
+}
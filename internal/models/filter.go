@@ -0,0 +1,85 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProximityFilter ограничивает выборку инцидентами в пределах расстояния от точки
+type ProximityFilter struct {
+	Latitude     float64
+	Longitude    float64
+	WithinMeters float64
+}
+
+// IncidentSortField - поле сортировки списка инцидентов
+type IncidentSortField string
+
+const (
+	SortByCreatedAt IncidentSortField = "created_at"
+	SortByUpdatedAt IncidentSortField = "updated_at"
+	SortByName      IncidentSortField = "name"
+	// SortBySeverity - по уровню опасности в порядке IncidentSeverities, а не по алфавиту
+	SortBySeverity IncidentSortField = "severity"
+)
+
+// IsValid сообщает, входит ли поле в список допустимых для сортировки
+func (f IncidentSortField) IsValid() bool {
+	switch f {
+	case SortByCreatedAt, SortByUpdatedAt, SortByName, SortBySeverity:
+		return true
+	}
+	return false
+}
+
+// IncidentSort - явно запрошенный порядок списка инцидентов
+type IncidentSort struct {
+	Field      IncidentSortField
+	Descending bool
+}
+
+// ChangeCursor - позиция в ленте изменений инцидентов, упорядоченной по (updated_at, id): время изменения и ID
+// последнего полученного инцидента. Инциденты, измененные в ту же микросекунду, различаются по ID и не пропускаются
+// между страницами. Нулевой ID означает начало ленты с момента UpdatedAt.
+type ChangeCursor struct {
+	UpdatedAt time.Time
+	ID        uuid.UUID
+}
+
+// IncidentFilter - необязательные условия выборки списка инцидентов
+type IncidentFilter struct {
+	// Near - при заданном значении выборка ограничивается радиусом и сортируется по удаленности
+	Near *ProximityFilter
+	// MinConfidence - при значении больше 0 исключаются инциденты с меньшей достоверностью
+	// (инциденты без оценки достоверности не исключаются)
+	MinConfidence int
+	// ChangedAfter - при заданном значении выбираются инциденты (включая неактивные), измененные
+	// после этой позиции, в порядке изменения; используется для инкрементальной синхронизации
+	ChangedAfter *ChangeCursor
+	// CreatedBefore - при заданном значении выбираются только инциденты, созданные не позже этого момента;
+	// фиксирует набор для постраничного чтения, чтобы новые инциденты не сдвигали страницы
+	CreatedBefore *time.Time
+	// CreatedBy - при непустом значении выбираются только инциденты, созданные API-ключом с этой меткой
+	CreatedBy string
+	// Sort - при заданном значении заменяет порядок по умолчанию (по дате создания, новые первыми,
+	// или по удаленности при заданном Near)
+	Sort *IncidentSort
+}
+
+// BulkDeactivation описывает инциденты для массовой деактивации:
+// либо явный список IDs, либо фильтр по статусу и дате создания
+type BulkDeactivation struct {
+	IDs []uuid.UUID
+	// Status - деактивировать только инциденты в этом статусе
+	Status IncidentStatus
+	// OlderThan - деактивировать только инциденты, созданные раньше этого момента
+	OlderThan *time.Time
+	// Reason - причина деактивации, записываемая в resolution_reason; пусто - причина не меняется
+	Reason string
+}
+
+// IsFilter сообщает, задан ли набор фильтром, а не списком ID
+func (b BulkDeactivation) IsFilter() bool {
+	return len(b.IDs) == 0
+}
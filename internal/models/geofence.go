@@ -0,0 +1,16 @@
+package models
+
+import "github.com/shenikar/geo_broadcasting_system/pkg/geo"
+
+// GeofenceValidation - результат проверки зоны инцидента без сохранения
+type GeofenceValidation struct {
+	// Valid - зона прошла все проверки; иначе причины перечислены в Errors
+	Valid  bool
+	Errors []string
+	// AreaSquareMeters - площадь зоны на поверхности Земли
+	AreaSquareMeters float64
+	// Bounds - описывающий прямоугольник зоны; у зоны, пересекающей 180-й меридиан, MinLon > MaxLon
+	Bounds geo.BBox
+	// CrossesAntimeridian - зона пересекает 180-й меридиан
+	CrossesAntimeridian bool
+}
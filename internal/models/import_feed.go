@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ImportFeed - зарегистрированная GeoJSON-лента, периодически синхронизируемая с инцидентами
+type ImportFeed struct {
+	ID  uuid.UUID
+	URL string
+	// CreatedBy - метка API-ключа, зарегистрировавшего ленту
+	CreatedBy string
+	CreatedAt time.Time
+	// ExternalIDs - внешние ID объектов ленты при последней успешной синхронизации;
+	// инциденты, пропавшие из ленты при следующей синхронизации, деактивируются
+	ExternalIDs []string
+	// LastSync - итог последней синхронизации; nil, если лента еще не синхронизировалась
+	LastSync *ImportFeedSync
+}
+
+// ImportFeedSync - итог одной синхронизации ленты
+type ImportFeedSync struct {
+	// Status - completed или failed; при failed инциденты не изменялись
+	Status      ImportJobStatus
+	Error       string
+	Created     int
+	Updated     int
+	Deactivated int
+	SyncedAt    time.Time
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ImportJobStatus - состояние фонового импорта инцидентов
+type ImportJobStatus string
+
+const (
+	// ImportJobRunning - импорт выполняется
+	ImportJobRunning ImportJobStatus = "running"
+	// ImportJobCompleted - лента загружена и обработана; ошибки отдельных объектов перечислены в Errors
+	ImportJobCompleted ImportJobStatus = "completed"
+	// ImportJobFailed - ленту не удалось загрузить или разобрать, инциденты не изменялись
+	ImportJobFailed ImportJobStatus = "failed"
+)
+
+// ImportJob - фоновый импорт инцидентов из GeoJSON-ленты по URL
+type ImportJob struct {
+	ID     uuid.UUID       `json:"id"`
+	URL    string          `json:"url"`
+	Status ImportJobStatus `json:"status"`
+	// StartedBy - метка API-ключа, запустившего импорт
+	StartedBy string `json:"started_by"`
+	// Features - число объектов в ленте
+	Features int `json:"features"`
+	Created  int `json:"created"`
+	Updated  int `json:"updated"`
+	// Unchanged - инциденты, уже совпадавшие с объектами ленты
+	Unchanged int `json:"unchanged"`
+	// Errors - объекты ленты, которые не удалось импортировать
+	Errors []ImportFeatureError `json:"errors"`
+	// Error - причина, по которой импорт завершился статусом failed
+	Error      string     `json:"error,omitempty"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// ImportFeatureError - ошибка импорта одного объекта ленты
+type ImportFeatureError struct {
+	// Index - номер объекта в features, с нуля
+	Index      int    `json:"index"`
+	ExternalID string `json:"external_id,omitempty"`
+	Error      string `json:"error"`
+}
@@ -6,14 +6,62 @@ import (
 	"github.com/google/uuid"
 )
 
+// Источники сведений об инциденте
+const (
+	SourceOfficial     = "official"
+	SourceCrowdsourced = "crowdsourced"
+	SourceSensor       = "sensor"
+)
+
+// IncidentSources - все допустимые источники сведений
+var IncidentSources = []string{SourceOfficial, SourceCrowdsourced, SourceSensor}
+
 type Incident struct {
-	ID           uuid.UUID `json:"id"`
-	Name         string    `json:"name"`
-	Description  string    `json:"description"`
-	Latitude     float64   `json:"latitude"`
-	Longitude    float64   `json:"longitude"`
-	RadiusMeters int       `json:"radius_meters"`
-	Status       string    `json:"status"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID           uuid.UUID      `json:"id"`
+	Name         string         `json:"name"`
+	Description  string         `json:"description"`
+	Latitude     float64        `json:"latitude"`
+	Longitude    float64        `json:"longitude"`
+	RadiusMeters int            `json:"radius_meters"`
+	Status       IncidentStatus `json:"status"`
+	MediaURLs    []string       `json:"media_urls,omitempty"`
+	// Source - источник сведений об инциденте: official, crowdsourced или sensor
+	Source string `json:"source"`
+	// Confidence - достоверность сведений от 0 до 100; nil - не оценена
+	Confidence *int `json:"confidence,omitempty"`
+	// Silent - не отправлять вебхуки об опасности при попадании в зону этого инцидента (например, при тестировании).
+	// Проверка при этом сохраняется и возвращает инцидент.
+	Silent bool `json:"silent"`
+	// Public - инцидент публикуется в открытой ленте без API-ключа (если активен, не silent и не служебной категории)
+	Public bool `json:"public"`
+	// Severity - уровень опасности; повышается эскалацией (см. IncidentSeverity.Next)
+	Severity IncidentSeverity `json:"severity"`
+	// Category - категория опасности (например, fire или chemical); определяет допустимый радиус
+	// по правилам INCIDENT_CATEGORY_RULES. Пусто - без категории
+	Category string `json:"category,omitempty"`
+	// ExternalID - идентификатор инцидента во внешней системе (например, в ленте-источнике); уникален, пусто - не задан
+	ExternalID string `json:"external_id,omitempty"`
+	// ResolutionReason - причина завершения инцидента (для статусов resolved и inactive)
+	ResolutionReason string `json:"resolution_reason,omitempty"`
+	// CreatedBy - метка API-ключа, создавшего инцидент (при импорте - запустившего импорт или зарегистрировавшего ленту).
+	// Задается только при создании; пусто - неизвестно. Метки ключей не раскрываются в вебхуках, поэтому поле
+	// не сериализуется: ответы API передают его явно (IncidentResponse), кэш и снимки версий - через storedIncident
+	CreatedBy string    `json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Resolution описывает завершение инцидента при деактивации
+type Resolution struct {
+	// Resolved - инцидент завершился сам (статус resolved), иначе снят вручную (статус inactive)
+	Resolved bool
+	Reason   string
+}
+
+// Status возвращает статус, в который переводится инцидент
+func (r Resolution) Status() IncidentStatus {
+	if r.Resolved {
+		return StatusResolved
+	}
+	return StatusInactive
 }
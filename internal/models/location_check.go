@@ -2,14 +2,59 @@ package models
 
 import (
 	"time"
+
+	"github.com/google/uuid"
 )
 
 // LocationCheck представляет запись о проверке местоположения пользователя
 type LocationCheck struct {
-	ID          int64     `json:"id"`
-	UserID      string    `json:"user_id"`
-	Latitude    float64   `json:"latitude"`
-	Longitude   float64   `json:"longitude"`
-	IsDangerous bool      `json:"is_dangerous"`
-	CheckedAt   time.Time `json:"checked_at"`
+	ID          int64   `json:"id"`
+	UserID      string  `json:"user_id"`
+	Latitude    float64 `json:"latitude"`
+	Longitude   float64 `json:"longitude"`
+	IsDangerous bool    `json:"is_dangerous"`
+	// Authenticated - проверка выполнена клиентом с API-ключом, user_id подтвержден им, а не указан анонимно
+	Authenticated bool      `json:"authenticated"`
+	CheckedAt     time.Time `json:"checked_at"`
+	// IncidentIDs - инциденты, в зону которых попала проверка
+	IncidentIDs []uuid.UUID `json:"incident_ids,omitempty"`
+}
+
+// UserStatus - текущая опасность для пользователя в точке его последней проверки местоположения
+type UserStatus struct {
+	// LastCheck - последняя проверка пользователя, координаты которой переоценены
+	LastCheck *LocationCheck `json:"last_check"`
+	// Incidents - активные сейчас инциденты, в зону которых попадает точка последней проверки
+	Incidents []*Incident `json:"incidents"`
+}
+
+// IsDangerous сообщает, находится ли пользователь в опасной зоне
+func (s *UserStatus) IsDangerous() bool {
+	return len(s.Incidents) > 0
+}
+
+// LocationCheckReevaluationQuery - параметры повторной оценки сохраненных проверок местоположения
+type LocationCheckReevaluationQuery struct {
+	// From и To - полуинтервал [From, To) по времени проверки
+	From time.Time
+	To   time.Time
+	// AfterID - курсор: оцениваются проверки с ID больше него (NextAfterID предыдущего ответа)
+	AfterID int64
+	// Limit - наибольшее число расхождений в ответе
+	Limit int
+	// Notify - отправить вебхуки о найденных пропущенных предупреждениях
+	Notify bool
+}
+
+// LocationCheckReevaluation - итог повторной оценки проверок местоположения
+type LocationCheckReevaluation struct {
+	// Checked - число проверок в периоде
+	Checked int
+	// Discrepancies - проверки, признанные безопасными, в которых пользователь был в зоне активного инцидента;
+	// IncidentIDs - найденные при повторной оценке инциденты
+	Discrepancies []*LocationCheck
+	// NextAfterID - курсор следующей страницы; 0, если расхождений больше нет
+	NextAfterID int64
+	// WebhooksPublished - число отправленных вебхуков (при Notify)
+	WebhooksPublished int
 }
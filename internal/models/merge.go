@@ -0,0 +1,23 @@
+package models
+
+import "github.com/google/uuid"
+
+// IncidentMerge - слияние дублирующихся инцидентов: DuplicateIDs поглощаются инцидентом CanonicalID
+type IncidentMerge struct {
+	CanonicalID  uuid.UUID
+	DuplicateIDs []uuid.UUID
+	// MergedBy - метка API-ключа, выполнившего слияние
+	MergedBy string
+}
+
+// IncidentMergeResult - результат слияния инцидентов
+type IncidentMergeResult struct {
+	// Before - канонический инцидент до слияния
+	Before *Incident
+	// Incident - канонический инцидент с объединенной зоной
+	Incident *Incident
+	// Merged - деактивированные дубликаты
+	Merged []*Incident
+	// RedirectedChecks - число совпадений проверок местоположения, перенесенных на канонический инцидент
+	RedirectedChecks int64
+}
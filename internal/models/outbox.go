@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// OutboxMessage - событие вебхука, записанное в outbox в одной транзакции с изменением данных.
+// Ретранслятор переносит сообщения в очередь вебхуков только после фиксации транзакции.
+type OutboxMessage struct {
+	ID int64
+	// Payload - JSON события вебхука
+	Payload   []byte
+	CreatedAt time.Time
+}
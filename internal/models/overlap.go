@@ -0,0 +1,8 @@
+package models
+
+// IncidentOverlap - активный инцидент, зона которого пересекается с зоной другого инцидента
+type IncidentOverlap struct {
+	Incident *Incident
+	// OverlapAreaSqMeters - площадь пересечения зон в квадратных метрах
+	OverlapAreaSqMeters float64
+}
@@ -0,0 +1,40 @@
+package models
+
+import "slices"
+
+// IncidentSeverity - уровень опасности инцидента. Допустимые значения перечислены в IncidentSeverities
+// по возрастанию и совпадают с ограничением incidents_severity_check в базе данных.
+type IncidentSeverity string
+
+const (
+	// SeverityMinor - локальное происшествие (уровень по умолчанию)
+	SeverityMinor IncidentSeverity = "minor"
+	// SeverityModerate - происшествие, требующее внимания жителей района
+	SeverityModerate IncidentSeverity = "moderate"
+	// SeverityMajor - крупное происшествие
+	SeverityMajor IncidentSeverity = "major"
+	// SeverityCritical - чрезвычайная ситуация, высший уровень
+	SeverityCritical IncidentSeverity = "critical"
+)
+
+// IncidentSeverities - все уровни опасности по возрастанию
+var IncidentSeverities = []IncidentSeverity{SeverityMinor, SeverityModerate, SeverityMajor, SeverityCritical}
+
+// IsValid сообщает, входит ли уровень в IncidentSeverities
+func (s IncidentSeverity) IsValid() bool {
+	return slices.Contains(IncidentSeverities, s)
+}
+
+// Rank возвращает позицию уровня в IncidentSeverities (чем выше уровень, тем больше); -1 - неизвестный уровень
+func (s IncidentSeverity) Rank() int {
+	return slices.Index(IncidentSeverities, s)
+}
+
+// Next возвращает следующий по возрастанию уровень; false, если уровень высший или неизвестный
+func (s IncidentSeverity) Next() (IncidentSeverity, bool) {
+	i := slices.Index(IncidentSeverities, s)
+	if i < 0 || i == len(IncidentSeverities)-1 {
+		return "", false
+	}
+	return IncidentSeverities[i+1], true
+}
@@ -0,0 +1,9 @@
+package models
+
+import "time"
+
+// StatsSnapshot - вычисленное значение статистики проверок с моментом вычисления (для кэширования)
+type StatsSnapshot struct {
+	UserCount  int       `json:"user_count"`
+	ComputedAt time.Time `json:"computed_at"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import "slices"
+
+// IncidentStatus - статус инцидента. Допустимые значения перечислены в IncidentStatuses
+// и совпадают с ограничением incidents_status_check в базе данных.
+type IncidentStatus string
+
+const (
+	// StatusActive - инцидент действует, проверки местоположения его учитывают
+	StatusActive IncidentStatus = "active"
+	// StatusDraft - инцидент ожидает модерации
+	StatusDraft IncidentStatus = "draft"
+	// StatusRejected - черновик отклонен модератором
+	StatusRejected IncidentStatus = "rejected"
+	// StatusInactive - инцидент снят вручную
+	StatusInactive IncidentStatus = "inactive"
+	// StatusResolved - инцидент завершился естественным образом (пожар потушен, вода ушла)
+	StatusResolved IncidentStatus = "resolved"
+)
+
+var (
+	// IncidentStatuses - все допустимые статусы инцидента
+	IncidentStatuses = []IncidentStatus{StatusActive, StatusDraft, StatusRejected, StatusInactive, StatusResolved}
+	// FinishedStatuses - статусы завершенного инцидента: он не учитывается в проверках и подлежит очистке
+	FinishedStatuses = []IncidentStatus{StatusInactive, StatusResolved}
+)
+
+// IsValid сообщает, входит ли статус в IncidentStatuses
+func (s IncidentStatus) IsValid() bool {
+	return slices.Contains(IncidentStatuses, s)
+}
+
+// IsFinished сообщает, завершен ли инцидент (снят вручную или завершился сам)
+func (s IncidentStatus) IsFinished() bool {
+	return slices.Contains(FinishedStatuses, s)
+}
+
+// IsMergeable сообщает, может ли инцидент участвовать в слиянии дубликатов (действует или ожидает модерации)
+func (s IncidentStatus) IsMergeable() bool {
+	return s == StatusActive || s == StatusDraft
+}
@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StatusChange представляет запись истории смены статуса инцидента
+type StatusChange struct {
+	ID         int64          `json:"id"`
+	IncidentID uuid.UUID      `json:"incident_id"`
+	FromStatus IncidentStatus `json:"from_status"`
+	ToStatus   IncidentStatus `json:"to_status"`
+	ChangedBy  string         `json:"changed_by"`
+	ChangedAt  time.Time      `json:"changed_at"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IncidentTemplate - именованный шаблон инцидента (например, «стандартная зона эвакуации 500 м»).
+// При создании инцидента из шаблона указываются только координаты, остальное берется из шаблона.
+type IncidentTemplate struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	// Category и RadiusMeters - категория и радиус зоны; нулевой радиус означает радиус категории по умолчанию
+	Category     string    `json:"category,omitempty"`
+	RadiusMeters int       `json:"radius_meters"`
+	Source       string    `json:"source,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// Apply заполняет инцидент значениями шаблона. Название и описание, уже заданные в инциденте, сохраняются.
+func (t *IncidentTemplate) Apply(incident *Incident) {
+	if incident.Name == "" {
+		incident.Name = t.Name
+	}
+	if incident.Description == "" {
+		incident.Description = t.Description
+	}
+	if incident.Source == "" {
+		incident.Source = t.Source
+	}
+	incident.Category = t.Category
+	incident.RadiusMeters = t.RadiusMeters
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// Интервалы группировки временной шкалы инцидентов
+const (
+	TimelineHour = "hour"
+	TimelineDay  = "day"
+)
+
+// TimelineQuery - параметры временной шкалы создания инцидентов
+type TimelineQuery struct {
+	// From и To - полуинтервал [From, To) по времени создания
+	From time.Time
+	To   time.Time
+	// Interval - размер корзины: TimelineHour или TimelineDay (границы корзин в UTC)
+	Interval string
+	// Source и Status - необязательные фильтры по источнику и статусу инцидента
+	Source string
+	Status IncidentStatus
+}
+
+// TimelineBucket - число инцидентов, созданных в пределах корзины, начинающейся в Start
+type TimelineBucket struct {
+	Start time.Time
+	Count int
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserLocation представляет сохраненное место пользователя (дом, работа и т.п.)
+type UserLocation struct {
+	ID        uuid.UUID `json:"id"`
+	UserID    string    `json:"user_id"`
+	Label     string    `json:"label"`
+	Latitude  float64   `json:"latitude"`
+	Longitude float64   `json:"longitude"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// LocationAlert - активные инциденты, в зону которых попадает сохраненное место пользователя
+type LocationAlert struct {
+	Location  *UserLocation `json:"location"`
+	Incidents []*Incident   `json:"incidents"`
+}
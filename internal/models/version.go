@@ -0,0 +1,16 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IncidentVersion - полный снимок состояния инцидента после одного изменения.
+// Версии нумеруются с 1 (создание инцидента) и не изменяются после записи.
+type IncidentVersion struct {
+	IncidentID uuid.UUID `json:"incident_id"`
+	Version    int       `json:"version"`
+	Incident   *Incident `json:"incident"`
+	CreatedAt  time.Time `json:"created_at"`
+}
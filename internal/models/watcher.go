@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IncidentWatcher - адрес, получающий вебхуки о каждом изменении инцидента независимо от подписок
+type IncidentWatcher struct {
+	ID         uuid.UUID
+	IncidentID uuid.UUID
+	URL        string
+	// Secret - секрет подписи HMAC; если не задан, вебхуки наблюдателя не подписываются (WEBHOOK_SECRET ему не раскрывается)
+	Secret string
+	// CreatedBy - метка API-ключа, добавившего наблюдателя
+	CreatedBy string
+	CreatedAt time.Time
+}
@@ -0,0 +1,181 @@
+package monitoring
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	dbAcquiredConnsGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "geo_db_pool_acquired_conns",
+		Help: "Number of PostgreSQL connections currently acquired from the pool.",
+	})
+	dbIdleConnsGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "geo_db_pool_idle_conns",
+		Help: "Number of idle PostgreSQL connections in the pool.",
+	})
+	dbTotalConnsGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "geo_db_pool_total_conns",
+		Help: "Total number of PostgreSQL connections in the pool.",
+	})
+	dbMaxConnsGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "geo_db_pool_max_conns",
+		Help: "Maximum size of the PostgreSQL pool.",
+	})
+	dbEmptyAcquireCounter = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "geo_db_pool_empty_acquire_total",
+		Help: "Cumulative number of acquires that had to wait because the PostgreSQL pool was empty.",
+	})
+	dbAcquireWaitGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "geo_db_pool_acquire_wait_seconds",
+		Help: "Average PostgreSQL connection acquire time over the last check interval.",
+	})
+	redisTotalConnsGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "geo_redis_pool_total_conns",
+		Help: "Total number of Redis connections in the pool.",
+	})
+	redisIdleConnsGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "geo_redis_pool_idle_conns",
+		Help: "Number of idle Redis connections in the pool.",
+	})
+	redisHitsCounter = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "geo_redis_pool_hits_total",
+		Help: "Cumulative number of times a free Redis connection was found in the pool.",
+	})
+	redisMissesCounter = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "geo_redis_pool_misses_total",
+		Help: "Cumulative number of times a free Redis connection was not found in the pool.",
+	})
+	redisTimeoutsCounter = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "geo_redis_pool_timeouts_total",
+		Help: "Cumulative number of Redis pool wait timeouts.",
+	})
+)
+
+// DBPoolStats - снимок статистики пула PostgreSQL
+type DBPoolStats struct {
+	AcquiredConns     int32
+	IdleConns         int32
+	TotalConns        int32
+	MaxConns          int32
+	AcquireCount      int64
+	AcquireDuration   time.Duration
+	EmptyAcquireCount int64
+}
+
+// PoolMonitor периодически публикует статистику пулов PostgreSQL и Redis как метрики
+// и предупреждает в логе, когда среднее время получения соединения из пула БД превышает порог.
+// Накопительные счетчики пулов публикуются как счетчики Prometheus, увеличиваемые на приращение с прошлой проверки.
+type PoolMonitor struct {
+	dbStats    func() DBPoolStats
+	redisStats func() *redis.PoolStats
+	logger     *logrus.Logger
+	interval   time.Duration
+	threshold  time.Duration
+	// prev - снимок статистики БД при предыдущей проверке, для расчета среднего за интервал и приращения счетчиков
+	prev DBPoolStats
+	// prevRedis - снимок статистики Redis при предыдущей проверке, для приращения счетчиков
+	prevRedis redis.PoolStats
+	// alarmed - время ожидания было выше порога при предыдущей проверке
+	alarmed bool
+}
+
+// NewPoolMonitor создает PoolMonitor. Нулевой threshold отключает предупреждения, метрики обновляются всегда.
+func NewPoolMonitor(dbpool *pgxpool.Pool, redisClient *redis.Client, logger *logrus.Logger, interval, threshold time.Duration) *PoolMonitor {
+	return &PoolMonitor{
+		dbStats: func() DBPoolStats {
+			stat := dbpool.Stat()
+			return DBPoolStats{
+				AcquiredConns:     stat.AcquiredConns(),
+				IdleConns:         stat.IdleConns(),
+				TotalConns:        stat.TotalConns(),
+				MaxConns:          stat.MaxConns(),
+				AcquireCount:      stat.AcquireCount(),
+				AcquireDuration:   stat.AcquireDuration(),
+				EmptyAcquireCount: stat.EmptyAcquireCount(),
+			}
+		},
+		redisStats: redisClient.PoolStats,
+		logger:     logger,
+		interval:   interval,
+		threshold:  threshold,
+	}
+}
+
+// Start запускает горутину периодического сбора статистики пулов
+func (m *PoolMonitor) Start(ctx context.Context) {
+	m.logger.WithFields(logrus.Fields{
+		"interval":  m.interval,
+		"threshold": m.threshold,
+	}).Info("Starting connection pool monitor...")
+
+	go func() {
+		ticker := time.NewTicker(m.interval)
+		defer ticker.Stop()
+
+		for {
+			m.check()
+			select {
+			case <-ctx.Done():
+				m.logger.Info("Stopping connection pool monitor.")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// check выполняет один сбор статистики пулов
+func (m *PoolMonitor) check() {
+	log := m.logger.WithField("component", "pool_monitor")
+
+	db := m.dbStats()
+	dbAcquiredConnsGauge.Set(float64(db.AcquiredConns))
+	dbIdleConnsGauge.Set(float64(db.IdleConns))
+	dbTotalConnsGauge.Set(float64(db.TotalConns))
+	dbMaxConnsGauge.Set(float64(db.MaxConns))
+	if db.EmptyAcquireCount > m.prev.EmptyAcquireCount {
+		dbEmptyAcquireCounter.Add(float64(db.EmptyAcquireCount - m.prev.EmptyAcquireCount))
+	}
+
+	// Среднее время получения соединения считается по приращению счетчиков за интервал;
+	// если за интервал соединения не запрашивались, ожидания не было
+	var wait time.Duration
+	if acquires := db.AcquireCount - m.prev.AcquireCount; acquires > 0 {
+		wait = (db.AcquireDuration - m.prev.AcquireDuration) / time.Duration(acquires)
+	}
+	m.prev = db
+	dbAcquireWaitGauge.Set(wait.Seconds())
+
+	rs := m.redisStats()
+	redisTotalConnsGauge.Set(float64(rs.TotalConns))
+	redisIdleConnsGauge.Set(float64(rs.IdleConns))
+	// Разность uint32 верна и после переполнения счетчика пула
+	redisHitsCounter.Add(float64(rs.Hits - m.prevRedis.Hits))
+	redisMissesCounter.Add(float64(rs.Misses - m.prevRedis.Misses))
+	redisTimeoutsCounter.Add(float64(rs.Timeouts - m.prevRedis.Timeouts))
+	m.prevRedis = *rs
+
+	if m.threshold <= 0 {
+		return
+	}
+	switch {
+	case wait > m.threshold:
+		log.WithFields(logrus.Fields{
+			"acquire_wait_ms": wait.Milliseconds(),
+			"threshold_ms":    m.threshold.Milliseconds(),
+			"acquired_conns":  db.AcquiredConns,
+			"max_conns":       db.MaxConns,
+		}).Warn("Database connection acquire time exceeds threshold, pool may be exhausted")
+		m.alarmed = true
+	case m.alarmed:
+		log.WithField("acquire_wait_ms", wait.Milliseconds()).Info("Database connection acquire time back below threshold")
+		m.alarmed = false
+	}
+}
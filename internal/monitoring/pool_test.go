@@ -0,0 +1,93 @@
+package monitoring
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestPoolMonitor создает монитор, читающий статистику из переданных указателей
+func newTestPoolMonitor(db *DBPoolStats, rs *redis.PoolStats, threshold time.Duration) (*PoolMonitor, *test.Hook) {
+	logger, hook := test.NewNullLogger()
+	return &PoolMonitor{
+		dbStats:    func() DBPoolStats { return *db },
+		redisStats: func() *redis.PoolStats { return rs },
+		logger:     logger,
+		threshold:  threshold,
+	}, hook
+}
+
+func TestPoolMonitor_PublishesStats(t *testing.T) {
+	db := &DBPoolStats{AcquiredConns: 3, IdleConns: 2, TotalConns: 5, MaxConns: 10, EmptyAcquireCount: 7}
+	rs := &redis.PoolStats{Hits: 100, Misses: 4, Timeouts: 1, TotalConns: 6, IdleConns: 5}
+	monitor, hook := newTestPoolMonitor(db, rs, 0)
+	// Счетчики глобальны для процесса, поэтому проверяется их приращение
+	counters := func() []float64 {
+		return []float64{
+			testutil.ToFloat64(dbEmptyAcquireCounter),
+			testutil.ToFloat64(redisHitsCounter),
+			testutil.ToFloat64(redisMissesCounter),
+			testutil.ToFloat64(redisTimeoutsCounter),
+		}
+	}
+	start := counters()
+	increase := func() []float64 {
+		current := counters()
+		for i := range current {
+			current[i] -= start[i]
+		}
+		return current
+	}
+
+	monitor.check()
+
+	assert.Equal(t, 3.0, testutil.ToFloat64(dbAcquiredConnsGauge))
+	assert.Equal(t, 2.0, testutil.ToFloat64(dbIdleConnsGauge))
+	assert.Equal(t, 5.0, testutil.ToFloat64(dbTotalConnsGauge))
+	assert.Equal(t, 10.0, testutil.ToFloat64(dbMaxConnsGauge))
+	assert.Equal(t, 6.0, testutil.ToFloat64(redisTotalConnsGauge))
+	assert.Equal(t, 5.0, testutil.ToFloat64(redisIdleConnsGauge))
+	assert.Equal(t, []float64{7, 100, 4, 1}, increase())
+	assert.Empty(t, hook.AllEntries())
+
+	// Следующая проверка прибавляет только приращение накопительной статистики пулов
+	db.EmptyAcquireCount = 9
+	rs.Hits, rs.Misses = 150, 4
+	monitor.check()
+
+	assert.Equal(t, []float64{9, 150, 4, 1}, increase())
+}
+
+func TestPoolMonitor_WarnsOnSlowAcquire(t *testing.T) {
+	db := &DBPoolStats{}
+	monitor, hook := newTestPoolMonitor(db, &redis.PoolStats{}, 100*time.Millisecond)
+	monitor.check()
+
+	// За интервал 4 получения соединения суммарно за 1с - в среднем 250мс
+	db.AcquireCount = 4
+	db.AcquireDuration = time.Second
+	monitor.check()
+
+	assert.Equal(t, 0.25, testutil.ToFloat64(dbAcquireWaitGauge))
+	entry := hook.LastEntry()
+	require.NotNil(t, entry)
+	assert.Equal(t, logrus.WarnLevel, entry.Level)
+	assert.Equal(t, int64(250), entry.Data["acquire_wait_ms"])
+
+	// Быстрые получения снимают предупреждение одним сообщением о восстановлении
+	hook.Reset()
+	db.AcquireCount = 14
+	db.AcquireDuration = time.Second + 10*time.Millisecond
+	monitor.check()
+	monitor.check()
+
+	assert.Equal(t, 0.0, testutil.ToFloat64(dbAcquireWaitGauge))
+	require.Len(t, hook.AllEntries(), 1)
+	assert.Equal(t, logrus.InfoLevel, hook.LastEntry().Level)
+}
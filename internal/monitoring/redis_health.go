@@ -0,0 +1,148 @@
+package monitoring
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/redis/go-redis/v9"
+	redisclient "github.com/shenikar/geo_broadcasting_system/pkg/redis"
+	"github.com/sirupsen/logrus"
+)
+
+var redisDegradedGauge = promauto.NewGauge(prometheus.GaugeOpts{
+	Name: "geo_redis_degraded",
+	Help: "1 while Redis is unavailable and the service runs in degraded mode, 0 otherwise.",
+})
+
+// redisProbeKey - ключ контекста проверочного PING, который выполняется и в режиме деградации
+type redisProbeKey struct{}
+
+// RedisHealth следит за доступностью Redis при REDIS_REQUIRED=false. Пока Redis недоступен, хук клиента
+// сразу возвращает redisclient.ErrDegraded вместо выполнения команд: чтение кэша уходит в PostgreSQL
+// без ожидания таймаута соединения, а вебхуки копятся в памяти (см. webhook.FallbackPublisher).
+// Доступность проверяется PING с периодом interval; после успешного PING команды снова идут в Redis.
+type RedisHealth struct {
+	client   *redis.Client
+	logger   *logrus.Logger
+	interval time.Duration
+
+	mu        sync.RWMutex
+	available bool
+}
+
+// NewRedisHealth создает монитор и подключает его хук к клиенту. available - результат проверки соединения при запуске.
+func NewRedisHealth(client *redis.Client, logger *logrus.Logger, interval time.Duration, available bool) *RedisHealth {
+	h := &RedisHealth{client: client, logger: logger, interval: interval, available: available}
+	if !available {
+		redisDegradedGauge.Set(1)
+	}
+	client.AddHook(h)
+	return h
+}
+
+// Available сообщает, считается ли Redis доступным
+func (h *RedisHealth) Available() bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.available
+}
+
+// Start запускает горутину проверки доступности Redis
+func (h *RedisHealth) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(h.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				h.probe(ctx)
+			}
+		}
+	}()
+}
+
+// probe выполняет проверочный PING и обновляет состояние
+func (h *RedisHealth) probe(ctx context.Context) {
+	probeCtx, cancel := context.WithTimeout(context.WithValue(ctx, redisProbeKey{}, true), h.interval)
+	defer cancel()
+	err := h.client.Ping(probeCtx).Err()
+	if ctx.Err() != nil {
+		return
+	}
+	h.setAvailable(err == nil, err)
+}
+
+// setAvailable меняет состояние и пишет в лог только переходы между режимами
+func (h *RedisHealth) setAvailable(available bool, cause error) {
+	h.mu.Lock()
+	changed := h.available != available
+	h.available = available
+	h.mu.Unlock()
+	if !changed {
+		return
+	}
+
+	if available {
+		redisDegradedGauge.Set(0)
+		h.logger.WithField("component", "redis_health").Info("Redis connection restored, leaving degraded mode")
+		return
+	}
+	redisDegradedGauge.Set(1)
+	h.logger.WithField("component", "redis_health").WithError(cause).
+		Warn("Redis is unavailable, running in degraded mode: cache reads go to PostgreSQL, webhooks are buffered in memory")
+}
+
+// connectionLost сообщает, вызвана ли ошибка команды потерей соединения с Redis.
+// Отмена и истечение контекста запроса (они тоже реализуют net.Error) потерей соединения не считаются.
+func connectionLost(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, redisclient.ErrDegraded) {
+		return false
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) || redisclient.IsUnavailable(err)
+}
+
+// DialHook реализует redis.Hook
+func (h *RedisHealth) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+// ProcessHook реализует redis.Hook: в режиме деградации команды не выполняются, а потеря соединения
+// во время команды переводит сервис в режим деградации до следующего успешного PING
+func (h *RedisHealth) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if !h.Available() && ctx.Value(redisProbeKey{}) == nil {
+			cmd.SetErr(redisclient.ErrDegraded)
+			return redisclient.ErrDegraded
+		}
+		err := next(ctx, cmd)
+		if connectionLost(err) && ctx.Value(redisProbeKey{}) == nil {
+			h.setAvailable(false, err)
+		}
+		return err
+	}
+}
+
+// ProcessPipelineHook реализует redis.Hook так же, как ProcessHook
+func (h *RedisHealth) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		if !h.Available() {
+			for _, cmd := range cmds {
+				cmd.SetErr(redisclient.ErrDegraded)
+			}
+			return redisclient.ErrDegraded
+		}
+		err := next(ctx, cmds)
+		if connectionLost(err) {
+			h.setAvailable(false, err)
+		}
+		return err
+	}
+}
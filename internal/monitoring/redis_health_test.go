@@ -0,0 +1,76 @@
+package monitoring
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	redisclient "github.com/shenikar/geo_broadcasting_system/pkg/redis"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// unusedAddr возвращает адрес, на котором никто не слушает
+func unusedAddr(t *testing.T) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	require.NoError(t, listener.Close())
+	return addr
+}
+
+func TestRedisHealth_DegradedModeFailsFast(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	client := redisclient.NewClient(unusedAddr(t), "", 0)
+	defer client.Close()
+	health := NewRedisHealth(client, logger, time.Second, false)
+	ctx := context.Background()
+
+	// В режиме деградации команда не обращается к сети
+	err := client.Get(ctx, "key").Err()
+	assert.ErrorIs(t, err, redisclient.ErrDegraded)
+	assert.True(t, redisclient.IsUnavailable(err))
+	assert.Equal(t, 1.0, testutil.ToFloat64(redisDegradedGauge))
+
+	// Проверочный PING выполняется, но Redis по-прежнему недоступен
+	health.probe(ctx)
+	assert.False(t, health.Available())
+	assert.Empty(t, hook.AllEntries())
+}
+
+func TestRedisHealth_ConnectionLossEntersDegradedMode(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	client := redisclient.NewClient(unusedAddr(t), "", 0)
+	defer client.Close()
+	health := NewRedisHealth(client, logger, time.Second, true)
+
+	// Команда к недоступному серверу переводит сервис в режим деградации одним сообщением
+	err := client.Get(context.Background(), "key").Err()
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, redisclient.ErrDegraded)
+	assert.False(t, health.Available())
+	require.Len(t, hook.AllEntries(), 1)
+	assert.Equal(t, logrus.WarnLevel, hook.LastEntry().Level)
+
+	assert.ErrorIs(t, client.Get(context.Background(), "key").Err(), redisclient.ErrDegraded)
+	assert.Len(t, hook.AllEntries(), 1)
+
+	// Восстановление пишется в лог и снова пропускает команды
+	health.setAvailable(true, nil)
+	assert.True(t, health.Available())
+	assert.Equal(t, logrus.InfoLevel, hook.LastEntry().Level)
+	assert.Equal(t, 0.0, testutil.ToFloat64(redisDegradedGauge))
+}
+
+func TestConnectionLost_IgnoresRequestContext(t *testing.T) {
+	assert.False(t, connectionLost(nil))
+	assert.False(t, connectionLost(context.Canceled))
+	assert.False(t, connectionLost(context.DeadlineExceeded))
+	assert.False(t, connectionLost(redisclient.ErrDegraded))
+	assert.True(t, connectionLost(&net.OpError{Op: "dial", Err: assert.AnError}))
+}
@@ -0,0 +1,146 @@
+package monitoring
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sirupsen/logrus"
+)
+
+// repositoryPackage - префикс функций пакета репозиториев; имя медленного запроса - первый такой вызов в стеке
+const repositoryPackage = "github.com/shenikar/geo_broadcasting_system/internal/repository."
+
+var slowQueriesCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "geo_db_slow_queries_total",
+	Help: "Number of PostgreSQL queries that exceeded the slow query threshold, by repository method.",
+}, []string{"query"})
+
+// slowQueryStartKey - ключ контекста, под которым TraceQueryStart сохраняет начало запроса
+type slowQueryStartKey struct{}
+
+// slowQueryStart - данные запроса, сохраненные до его выполнения
+type slowQueryStart struct {
+	started time.Time
+	args    []any
+}
+
+// SlowQueryTracer - pgx.QueryTracer, замеряющий каждый запрос пула PostgreSQL и предупреждающий в логе
+// о запросах дольше порога. Запрос называется по методу репозитория, который его выполнил;
+// параметры не логируются (в них координаты и идентификаторы пользователей), вместо них пишется их хэш,
+// по которому повторы одного и того же запроса видны без раскрытия значений.
+type SlowQueryTracer struct {
+	logger    *logrus.Logger
+	threshold time.Duration
+	// pkgPrefix - префикс функций, по которым определяется имя запроса
+	pkgPrefix string
+	// hashKey - случайный ключ HMAC параметров, создаваемый при запуске процесса. Без ключа параметры
+	// с малым числом вариантов (координаты, идентификаторы пользователей) восстанавливаются перебором по хэшу.
+	hashKey []byte
+}
+
+// NewSlowQueryTracer создает SlowQueryTracer. Нулевой threshold отключает замеры.
+func NewSlowQueryTracer(logger *logrus.Logger, threshold time.Duration) *SlowQueryTracer {
+	hashKey := make([]byte, sha256.Size)
+	_, _ = rand.Read(hashKey) // crypto/rand.Read не возвращает ошибок
+	return &SlowQueryTracer{logger: logger, threshold: threshold, pkgPrefix: repositoryPackage, hashKey: hashKey}
+}
+
+// TraceQueryStart запоминает время начала и параметры запроса
+func (t *SlowQueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	if t.threshold <= 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, slowQueryStartKey{}, slowQueryStart{started: time.Now(), args: data.Args})
+}
+
+// TraceQueryEnd пишет предупреждение, если запрос выполнялся дольше порога.
+// Вызывается, пока метод репозитория еще читает результат, поэтому он виден в стеке.
+func (t *SlowQueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	start, ok := ctx.Value(slowQueryStartKey{}).(slowQueryStart)
+	if !ok {
+		return
+	}
+	duration := time.Since(start.started)
+	if duration <= t.threshold {
+		return
+	}
+
+	name := t.queryName()
+	slowQueriesCounter.WithLabelValues(name).Inc()
+
+	entry := t.logger.WithFields(logrus.Fields{
+		"component":    "slow_query",
+		"query":        name,
+		"duration_ms":  duration.Milliseconds(),
+		"threshold_ms": t.threshold.Milliseconds(),
+		"params_hash":  t.paramsHash(start.args),
+		"rows":         data.CommandTag.RowsAffected(),
+	})
+	if data.Err != nil {
+		entry = entry.WithError(data.Err)
+	}
+	entry.Warn("Slow database query")
+}
+
+// queryName возвращает имя первой функции из pkgPrefix в стеке вызовов, например IncidentRepository.FindHotspots
+func (t *SlowQueryTracer) queryName() string {
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs)])
+	for {
+		frame, more := frames.Next()
+		if name, ok := strings.CutPrefix(frame.Function, t.pkgPrefix); ok {
+			return strings.NewReplacer("(*", "", ")", "").Replace(name)
+		}
+		if !more {
+			return "unknown"
+		}
+	}
+}
+
+// paramsHash возвращает короткий HMAC-SHA256 параметров запроса. Хэшируются нормализованные значения
+// (см. normalizeArg), поэтому одинаковые параметры дают одинаковый хэш в пределах процесса.
+func (t *SlowQueryTracer) paramsHash(args []any) string {
+	mac := hmac.New(sha256.New, t.hashKey)
+	for _, arg := range args {
+		value := normalizeArg(arg)
+		fmt.Fprintf(mac, "%T:%v\x00", value, value)
+	}
+	return hex.EncodeToString(mac.Sum(nil)[:8])
+}
+
+// normalizeArg приводит параметр запроса к значению, не зависящему от адреса в памяти:
+// указатели разыменовываются, driver.Valuer заменяется своим значением, время - строкой без монотонных часов
+func normalizeArg(arg any) any {
+	v := reflect.ValueOf(arg)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return nil
+	}
+	arg = v.Interface()
+	if valuer, ok := arg.(driver.Valuer); ok {
+		if value, err := valuer.Value(); err == nil {
+			arg = value
+		}
+	}
+	if ts, ok := arg.(time.Time); ok {
+		return ts.UTC().Format(time.RFC3339Nano)
+	}
+	return arg
+}
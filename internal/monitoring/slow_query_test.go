@@ -0,0 +1,78 @@
+package monitoring
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestSlowQueryTracer создает трассировщик, называющий запросы по функциям этого пакета
+func newTestSlowQueryTracer(threshold time.Duration) (*SlowQueryTracer, *test.Hook) {
+	logger, hook := test.NewNullLogger()
+	tracer := NewSlowQueryTracer(logger, threshold)
+	tracer.pkgPrefix = "github.com/shenikar/geo_broadcasting_system/internal/monitoring."
+	return tracer, hook
+}
+
+func TestSlowQueryTracer_LogsSlowQuery(t *testing.T) {
+	tracer, hook := newTestSlowQueryTracer(50 * time.Millisecond)
+	args := []any{"user-1", 55.75, 37.61}
+
+	// Запрос начался 80мс назад
+	ctx := context.WithValue(context.Background(), slowQueryStartKey{}, slowQueryStart{started: time.Now().Add(-80 * time.Millisecond), args: args})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{CommandTag: pgconn.NewCommandTag("SELECT 3")})
+
+	entry := hook.LastEntry()
+	require.NotNil(t, entry)
+	assert.Equal(t, logrus.WarnLevel, entry.Level)
+	assert.Equal(t, "TestSlowQueryTracer_LogsSlowQuery", entry.Data["query"])
+	assert.GreaterOrEqual(t, entry.Data["duration_ms"], int64(80))
+	assert.Equal(t, int64(50), entry.Data["threshold_ms"])
+	assert.Equal(t, int64(3), entry.Data["rows"])
+	assert.Equal(t, tracer.paramsHash(args), entry.Data["params_hash"])
+	assert.NotContains(t, entry.Data["params_hash"], "user-1")
+	assert.Equal(t, 1.0, testutil.ToFloat64(slowQueriesCounter.WithLabelValues("TestSlowQueryTracer_LogsSlowQuery")))
+}
+
+func TestSlowQueryTracer_ParamsHash(t *testing.T) {
+	tracer, _ := newTestSlowQueryTracer(time.Second)
+	userID, otherUserID := "user-1", "user-1"
+	checkedAt := time.Now()
+
+	// Одинаковые значения за разными указателями и время с монотонными часами хэшируются одинаково
+	assert.Equal(t,
+		tracer.paramsHash([]any{&userID, 55.75, checkedAt}),
+		tracer.paramsHash([]any{&otherUserID, 55.75, checkedAt.Round(0)}))
+	assert.NotEqual(t, tracer.paramsHash([]any{"user-1"}), tracer.paramsHash([]any{"user-2"}))
+
+	// Без ключа процесса хэш не воспроизводится, поэтому значения не подобрать перебором
+	other, _ := newTestSlowQueryTracer(time.Second)
+	assert.NotEqual(t, tracer.paramsHash([]any{"user-1"}), other.paramsHash([]any{"user-1"}))
+}
+
+func TestSlowQueryTracer_SkipsFastQuery(t *testing.T) {
+	tracer, hook := newTestSlowQueryTracer(time.Second)
+
+	ctx := tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: "SELECT 1"})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{})
+
+	assert.Empty(t, hook.AllEntries())
+}
+
+func TestSlowQueryTracer_DisabledByZeroThreshold(t *testing.T) {
+	tracer, hook := newTestSlowQueryTracer(0)
+
+	ctx := tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: "SELECT 1"})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{})
+
+	assert.Nil(t, ctx.Value(slowQueryStartKey{}))
+	assert.Empty(t, hook.AllEntries())
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/shenikar/geo_broadcasting_system/internal/service"
+)
+
+// apiKeysKey возвращает ключ множества Redis с хэшами API-ключей, выпущенных во время работы
+func (r *IncidentRepository) apiKeysKey() string {
+	return r.keys.Key("api_keys")
+}
+
+// AddAPIKey сохраняет хэш API-ключа. Повторное добавление того же ключа не является ошибкой.
+func (r *IncidentRepository) AddAPIKey(ctx context.Context, keyHash string) error {
+	if err := r.redisClient.SAdd(ctx, r.apiKeysKey(), keyHash).Err(); err != nil {
+		return fmt.Errorf("failed to add api key: %w", err)
+	}
+	return nil
+}
+
+// RemoveAPIKey удаляет хэш API-ключа
+func (r *IncidentRepository) RemoveAPIKey(ctx context.Context, keyHash string) error {
+	removed, err := r.redisClient.SRem(ctx, r.apiKeysKey(), keyHash).Result()
+	if err != nil {
+		return fmt.Errorf("failed to remove api key: %w", err)
+	}
+	if removed == 0 {
+		return service.ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+// ListAPIKeys возвращает хэши всех API-ключей, выпущенных во время работы
+func (r *IncidentRepository) ListAPIKeys(ctx context.Context) ([]string, error) {
+	hashes, err := r.redisClient.SMembers(ctx, r.apiKeysKey()).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list api keys: %w", err)
+	}
+	return hashes, nil
+}
+
+// apiKeyUsageKey возвращает ключ хэша Redis со счетчиками запросов по меткам ключей за интервал bucket
+func (r *IncidentRepository) apiKeyUsageKey(bucket time.Time) string {
+	return r.keys.Key("api_key_usage", strconv.FormatInt(bucket.Unix(), 10))
+}
+
+// IncrementAPIKeyUsage увеличивает счетчик запросов ключа с меткой label в интервале bucket.
+// Счетчики интервала удаляются Redis через ttl.
+func (r *IncidentRepository) IncrementAPIKeyUsage(ctx context.Context, label string, bucket time.Time, ttl time.Duration) error {
+	key := r.apiKeyUsageKey(bucket)
+	pipe := r.redisClient.TxPipeline()
+	pipe.HIncrBy(ctx, key, label, 1)
+	pipe.Expire(ctx, key, ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to increment api key usage: %w", err)
+	}
+	return nil
+}
+
+// CountAPIKeyUsage суммирует счетчики запросов по меткам ключей за интервалы buckets
+func (r *IncidentRepository) CountAPIKeyUsage(ctx context.Context, buckets []time.Time) (map[string]int64, error) {
+	pipe := r.redisClient.Pipeline()
+	cmds := make([]*redis.MapStringStringCmd, len(buckets))
+	for i, bucket := range buckets {
+		cmds[i] = pipe.HGetAll(ctx, r.apiKeyUsageKey(bucket))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed to get api key usage: %w", err)
+	}
+
+	usage := make(map[string]int64)
+	for _, cmd := range cmds {
+		for label, value := range cmd.Val() {
+			count, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid api key usage counter for %s: %w", label, err)
+			}
+			usage[label] += count
+		}
+	}
+	return usage, nil
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/shenikar/geo_broadcasting_system/internal/models"
+)
+
+// CreateIncidentAttachment сохраняет ссылку на объект вложения инцидента
+func (r *IncidentRepository) CreateIncidentAttachment(ctx context.Context, attachment *models.IncidentAttachment) error {
+	query := `
+		INSERT INTO incident_attachments (incident_id, object_key, filename, content_type, created_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at;
+	`
+	err := r.db.QueryRow(ctx, query,
+		attachment.IncidentID,
+		attachment.ObjectKey,
+		attachment.Filename,
+		attachment.ContentType,
+		attachment.CreatedBy,
+	).Scan(&attachment.ID, &attachment.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create incident attachment: %w", err)
+	}
+	return nil
+}
+
+// ListIncidentAttachments возвращает вложения инцидента в порядке добавления
+func (r *IncidentRepository) ListIncidentAttachments(ctx context.Context, incidentID uuid.UUID) ([]*models.IncidentAttachment, error) {
+	query := `
+		SELECT id, incident_id, object_key, filename, content_type, created_by, created_at
+		FROM incident_attachments
+		WHERE incident_id = $1
+		ORDER BY created_at, id;
+	`
+	rows, err := r.db.Query(ctx, query, incidentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list incident attachments: %w", err)
+	}
+	defer rows.Close()
+
+	attachments := make([]*models.IncidentAttachment, 0)
+	for rows.Next() {
+		attachment := &models.IncidentAttachment{}
+		err := rows.Scan(
+			&attachment.ID,
+			&attachment.IncidentID,
+			&attachment.ObjectKey,
+			&attachment.Filename,
+			&attachment.ContentType,
+			&attachment.CreatedBy,
+			&attachment.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan incident attachment row: %w", err)
+		}
+		attachments = append(attachments, attachment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error list iteration in ListIncidentAttachments: %w", err)
+	}
+	return attachments, nil
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+)
+
+// cacheScanCount - сколько ключей запрашивать у Redis за один шаг SCAN и удалять за одну команду
+const cacheScanCount = 500
+
+// FlushCaches удаляет кэш инцидентов, ячеек проверки местоположения и статистики в пространстве имен окружения.
+// Счетчик поколений ячеек не удаляется: для сброса ячеек используется InvalidateLocationTiles.
+// Возвращает число удаленных ключей.
+func (r *IncidentRepository) FlushCaches(ctx context.Context) (int64, error) {
+	generationKey := r.locationTileGenerationKey()
+	patterns := []string{
+		r.keys.Pattern("incident"),
+		r.keys.Pattern("location_tiles"),
+		r.keys.Pattern("stats"),
+	}
+
+	var removed int64
+	for _, pattern := range patterns {
+		iter := r.redisClient.Scan(ctx, 0, pattern, cacheScanCount).Iterator()
+		batch := make([]string, 0, cacheScanCount)
+		flush := func() error {
+			if len(batch) == 0 {
+				return nil
+			}
+			n, err := r.redisClient.Del(ctx, batch...).Result()
+			if err != nil {
+				return fmt.Errorf("failed to delete cache keys: %w", err)
+			}
+			removed += n
+			batch = batch[:0]
+			return nil
+		}
+
+		for iter.Next(ctx) {
+			if key := iter.Val(); key != generationKey {
+				batch = append(batch, key)
+			}
+			if len(batch) == cacheScanCount {
+				if err := flush(); err != nil {
+					return removed, err
+				}
+			}
+		}
+		if err := iter.Err(); err != nil {
+			return removed, fmt.Errorf("failed to scan cache keys %s: %w", pattern, err)
+		}
+		if err := flush(); err != nil {
+			return removed, err
+		}
+	}
+	return removed, nil
+}
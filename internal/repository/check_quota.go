@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// slidingWindowScript - скользящее окно на отсортированном множестве: элементы - учтенные запросы,
+// оценки - время запроса в миллисекундах. Устаревшие элементы удаляются, и если в окне осталось меньше
+// limit запросов, добавляется новый. Возвращает 0, если запрос разрешен, иначе число миллисекунд
+// до освобождения места в окне.
+// KEYS[1] - ключ окна; ARGV: текущее время (мс), окно (мс), limit, уникальный идентификатор запроса.
+var slidingWindowScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+if redis.call('ZCARD', KEYS[1]) >= tonumber(ARGV[3]) then
+	local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+	return math.max(tonumber(oldest[2]) + window - now, 1)
+end
+redis.call('ZADD', KEYS[1], now, ARGV[4])
+redis.call('PEXPIRE', KEYS[1], window)
+return 0
+`)
+
+// reserveSlidingWindow учитывает запрос в скользящем окне window по ключу key.
+// Если за окно уже учтено limit запросов, запрос не учитывается и возвращается время,
+// через которое освободится место; иначе возвращается 0.
+func (r *IncidentRepository) reserveSlidingWindow(ctx context.Context, key string, limit int, window time.Duration) (time.Duration, error) {
+	wait, err := slidingWindowScript.Run(ctx, r.redisClient, []string{key},
+		time.Now().UnixMilli(), window.Milliseconds(), limit, uuid.NewString()).Int64()
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(wait) * time.Millisecond, nil
+}
+
+// ReserveUserCheck учитывает проверку местоположения пользователя в скользящем окне window.
+// Если за окно уже выполнено limit проверок, проверка не учитывается и возвращается время,
+// через которое освободится место; иначе возвращается 0.
+func (r *IncidentRepository) ReserveUserCheck(ctx context.Context, userID string, limit int, window time.Duration) (time.Duration, error) {
+	wait, err := r.reserveSlidingWindow(ctx, r.keys.Key("check_quota", userID), limit, window)
+	if err != nil {
+		return 0, fmt.Errorf("failed to reserve user location check: %w", err)
+	}
+	return wait, nil
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/shenikar/geo_broadcasting_system/internal/models"
+	"github.com/shenikar/geo_broadcasting_system/internal/service"
+)
+
+// EscalateIncident повышает уровень опасности инцидента с from до to, отмечает время эскалации
+// и записывает новую версию снимка. Если уровень инцидента уже не from (изменен параллельно),
+// возвращается service.ErrSeverityConflict.
+func (r *IncidentRepository) EscalateIncident(ctx context.Context, id uuid.UUID, from, to models.IncidentSeverity) (*models.Incident, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin escalate incident transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	query := `
+		UPDATE incidents SET
+			severity = $3,
+			escalated_at = NOW(),
+			updated_at = NOW()
+		WHERE id = $1 AND severity = $2
+		RETURNING ` + incidentColumns + `;
+	`
+	escalated, err := scanIncident(tx.QueryRow(ctx, query, id, from, to))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("incident %s is no longer %s: %w", id, from, service.ErrSeverityConflict)
+		}
+		return nil, fmt.Errorf("failed to escalate incident: %w", err)
+	}
+	if err := insertIncidentVersion(ctx, tx, escalated); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit escalate incident: %w", err)
+	}
+	return escalated, nil
+}
+
+// ListEscalationCandidates возвращает активные инциденты ниже critical, в зону которых за окно window
+// попали не менее minUsers разных пользователей. Инциденты, повышенные в течение того же окна,
+// пропускаются, чтобы одна волна проверок не поднимала уровень на каждом проходе.
+func (r *IncidentRepository) ListEscalationCandidates(ctx context.Context, minUsers int, window time.Duration) ([]uuid.UUID, error) {
+	query := `
+		SELECT i.id
+		FROM incidents i
+		JOIN location_check_incidents lci ON lci.incident_id = i.id
+		JOIN location_checks lc ON lc.id = lci.location_check_id
+		WHERE i.status = 'active'
+			AND i.severity <> 'critical'
+			AND (i.escalated_at IS NULL OR i.escalated_at < NOW() - ($2 * INTERVAL '1 second'))
+			AND lc.checked_at >= NOW() - ($2 * INTERVAL '1 second')
+		GROUP BY i.id
+		HAVING COUNT(DISTINCT lc.user_id) >= $1;
+	`
+	rows, err := r.db.Query(ctx, query, minUsers, window.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list escalation candidates: %w", err)
+	}
+	defer rows.Close()
+	ids := make([]uuid.UUID, 0)
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan escalation candidate: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error list iteration in ListEscalationCandidates: %w", err)
+	}
+	return ids, nil
+}
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// GetFeatureFlag читает флаг функции из хеша feature_flags в Redis.
+// found = false, если флаг в Redis не задан.
+func (r *IncidentRepository) GetFeatureFlag(ctx context.Context, name string) (enabled, found bool, err error) {
+	value, err := r.redisClient.HGet(ctx, r.keys.Key("feature_flags"), name).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return false, false, nil
+		}
+		return false, false, fmt.Errorf("failed to get feature flag %q: %w", name, err)
+	}
+	enabled, err = strconv.ParseBool(value)
+	if err != nil {
+		return false, false, fmt.Errorf("invalid value of feature flag %q: %w", name, err)
+	}
+	return enabled, true, nil
+}
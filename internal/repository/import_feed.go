@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/shenikar/geo_broadcasting_system/internal/models"
+	"github.com/shenikar/geo_broadcasting_system/internal/service"
+)
+
+// importFeedColumns - список колонок ленты в порядке, ожидаемом scanImportFeed
+const importFeedColumns = `
+			id,
+			url,
+			created_by,
+			created_at,
+			external_ids,
+			last_synced_at,
+			last_status,
+			last_error,
+			last_created,
+			last_updated,
+			last_deactivated`
+
+// scanImportFeed читает ленту из строки результата
+func scanImportFeed(row pgx.Row) (*models.ImportFeed, error) {
+	feed := &models.ImportFeed{}
+	var syncedAt *time.Time
+	sync := models.ImportFeedSync{}
+	err := row.Scan(
+		&feed.ID,
+		&feed.URL,
+		&feed.CreatedBy,
+		&feed.CreatedAt,
+		&feed.ExternalIDs,
+		&syncedAt,
+		&sync.Status,
+		&sync.Error,
+		&sync.Created,
+		&sync.Updated,
+		&sync.Deactivated,
+	)
+	if syncedAt != nil {
+		sync.SyncedAt = *syncedAt
+		feed.LastSync = &sync
+	}
+	return feed, err
+}
+
+// scanImportFeeds читает все ленты из результата запроса
+func scanImportFeeds(rows pgx.Rows) ([]*models.ImportFeed, error) {
+	defer rows.Close()
+	feeds := make([]*models.ImportFeed, 0)
+	for rows.Next() {
+		feed, err := scanImportFeed(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan import feed row: %w", err)
+		}
+		feeds = append(feeds, feed)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error list iteration in import feeds: %w", err)
+	}
+	return feeds, nil
+}
+
+// CreateImportFeed регистрирует ленту. URL ленты уникален.
+func (r *IncidentRepository) CreateImportFeed(ctx context.Context, feed *models.ImportFeed) error {
+	query := `
+		INSERT INTO import_feeds (url, created_by)
+		VALUES ($1, $2)
+		RETURNING id, created_at;
+	`
+	err := r.db.QueryRow(ctx, query, feed.URL, feed.CreatedBy).Scan(&feed.ID, &feed.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation {
+			return fmt.Errorf("import feed %q: %w", feed.URL, service.ErrImportFeedExists)
+		}
+		return fmt.Errorf("failed to create import feed: %w", err)
+	}
+	feed.ExternalIDs = []string{}
+	return nil
+}
+
+// ListImportFeeds возвращает все ленты в порядке регистрации
+func (r *IncidentRepository) ListImportFeeds(ctx context.Context) ([]*models.ImportFeed, error) {
+	query := `
+		SELECT ` + importFeedColumns + `
+		FROM import_feeds
+		ORDER BY created_at, id;
+	`
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list import feeds: %w", err)
+	}
+	return scanImportFeeds(rows)
+}
+
+// DeleteImportFeed удаляет ленту. Импортированные из нее инциденты не затрагиваются.
+func (r *IncidentRepository) DeleteImportFeed(ctx context.Context, id uuid.UUID) error {
+	cmdTag, err := r.db.Exec(ctx, `DELETE FROM import_feeds WHERE id = $1;`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete import feed: %w", err)
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("import feed with id %s: %w", id, service.ErrImportFeedNotFound)
+	}
+	return nil
+}
+
+// ClaimDueImportFeeds отмечает начало синхронизации лент, которые не брались в работу дольше interval,
+// и возвращает их. Отметка делается одним UPDATE, поэтому несколько экземпляров сервиса
+// не синхронизируют одну ленту одновременно.
+func (r *IncidentRepository) ClaimDueImportFeeds(ctx context.Context, interval time.Duration) ([]*models.ImportFeed, error) {
+	query := `
+		UPDATE import_feeds SET
+			sync_started_at = NOW()
+		WHERE sync_started_at IS NULL OR sync_started_at <= NOW() - ($1 * INTERVAL '1 second')
+		RETURNING ` + importFeedColumns + `;
+	`
+	rows, err := r.db.Query(ctx, query, interval.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim import feeds: %w", err)
+	}
+	return scanImportFeeds(rows)
+}
+
+// SaveImportFeedSync записывает итог синхронизации ленты. externalIDs заменяет сохраненный набор
+// внешних ID; nil оставляет его прежним (синхронизация не удалась).
+func (r *IncidentRepository) SaveImportFeedSync(ctx context.Context, id uuid.UUID, externalIDs []string, sync models.ImportFeedSync) error {
+	query := `
+		UPDATE import_feeds SET
+			external_ids = COALESCE($2, external_ids),
+			last_synced_at = $3,
+			last_status = $4,
+			last_error = $5,
+			last_created = $6,
+			last_updated = $7,
+			last_deactivated = $8
+		WHERE id = $1;
+	`
+	cmdTag, err := r.db.Exec(ctx, query, id, externalIDs, sync.SyncedAt, sync.Status, sync.Error, sync.Created, sync.Updated, sync.Deactivated)
+	if err != nil {
+		return fmt.Errorf("failed to save import feed sync: %w", err)
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("import feed with id %s: %w", id, service.ErrImportFeedNotFound)
+	}
+	return nil
+}
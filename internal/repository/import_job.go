@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/shenikar/geo_broadcasting_system/internal/models"
+)
+
+// importJobKey возвращает ключ Redis с состоянием задачи импорта
+func (r *IncidentRepository) importJobKey(id uuid.UUID) string {
+	return r.keys.Key("import_job", id.String())
+}
+
+// SaveImportJob сохраняет состояние задачи импорта в Redis на время ttl.
+// Состояние хранится в Redis, чтобы его мог вернуть любой экземпляр сервиса.
+func (r *IncidentRepository) SaveImportJob(ctx context.Context, job *models.ImportJob, ttl time.Duration) error {
+	val, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to marshal import job: %w", err)
+	}
+	if err := r.redisClient.Set(ctx, r.importJobKey(job.ID), val, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to save import job: %w", err)
+	}
+	return nil
+}
+
+// GetImportJob возвращает состояние задачи импорта или nil, если задачи нет или срок ее хранения истек
+func (r *IncidentRepository) GetImportJob(ctx context.Context, id uuid.UUID) (*models.ImportJob, error) {
+	val, err := r.redisClient.Get(ctx, r.importJobKey(id)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get import job: %w", err)
+	}
+
+	job := &models.ImportJob{}
+	if err := json.Unmarshal(val, job); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal import job: %w", err)
+	}
+	return job, nil
+}
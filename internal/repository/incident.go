@@ -5,87 +5,222 @@ import (
 	"encoding/json" // New import for JSON serialization
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
 	"time" // New import for cache expiration
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/redis/go-redis/v9"
 	"github.com/shenikar/geo_broadcasting_system/internal/models"
 	"github.com/shenikar/geo_broadcasting_system/internal/service"
+	"github.com/shenikar/geo_broadcasting_system/pkg/geo"
+	redisclient "github.com/shenikar/geo_broadcasting_system/pkg/redis"
 )
 
+// incidentColumns - список колонок инцидента в порядке, ожидаемом scanIncident
+const incidentColumns = `
+			id,
+			name,
+			description,
+			ST_Y(location::geometry) as latitude,
+			ST_X(location::geometry) as longitude,
+			radius_meters,
+			status,
+			media_urls,
+			source,
+			confidence,
+			silent,
+			public,
+			severity,
+			COALESCE(category, '') as category,
+			COALESCE(external_id, '') as external_id,
+			COALESCE(resolution_reason, '') as resolution_reason,
+			COALESCE(created_by, '') as created_by,
+			created_at,
+			updated_at`
+
+// statusNames переводит статусы в значения для параметра text[] (status = ANY($n::text[]))
+func statusNames(statuses []models.IncidentStatus) []string {
+	names := make([]string, len(statuses))
+	for i, status := range statuses {
+		names[i] = string(status)
+	}
+	return names
+}
+
+// confidenceExpr - достоверность инцидента для фильтрации: неоцененные инциденты считаются полностью достоверными
+const confidenceExpr = "COALESCE(confidence, 100)"
+
 type IncidentRepository struct {
 	db          *pgxpool.Pool
 	redisClient *redis.Client
+	keys        redisclient.KeyBuilder
 }
 
-func NewIncidentRepository(db *pgxpool.Pool, redisClient *redis.Client) service.IncidentRepository {
+func NewIncidentRepository(db *pgxpool.Pool, redisClient *redis.Client, keys redisclient.KeyBuilder) service.IncidentRepository {
 	return &IncidentRepository{
 		db:          db,
 		redisClient: redisClient,
+		keys:        keys,
+	}
+}
+
+// incidentCacheKey возвращает ключ кэша для инцидента
+func (r *IncidentRepository) incidentCacheKey(id uuid.UUID) string {
+	return r.keys.Key("incident", id.String())
+}
+
+// scanIncident считывает инцидент из строки результата, выбранной с incidentColumns
+func scanIncident(row pgx.Row, extra ...any) (*models.Incident, error) {
+	incident := &models.Incident{}
+	dest := []any{
+		&incident.ID,
+		&incident.Name,
+		&incident.Description,
+		&incident.Latitude,
+		&incident.Longitude,
+		&incident.RadiusMeters,
+		&incident.Status,
+		&incident.MediaURLs,
+		&incident.Source,
+		&incident.Confidence,
+		&incident.Silent,
+		&incident.Public,
+		&incident.Severity,
+		&incident.Category,
+		&incident.ExternalID,
+		&incident.ResolutionReason,
+		&incident.CreatedBy,
+		&incident.CreatedAt,
+		&incident.UpdatedAt,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
 	}
+	return incident, nil
+}
+
+// whereClause собирает условия выборки в WHERE через AND
+func whereClause(conditions []string) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+	return "\n\t\tWHERE " + strings.Join(conditions, " AND ")
 }
 
-// Create создает новую запись об инциденте в бд
+// Create создает новую запись об инциденте в бд и первую версию его снимка
 func (r *IncidentRepository) Create(ctx context.Context, incident *models.Incident) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin create incident transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
 	query := `
-		INSERT INTO incidents (name, description, location, radius_meters, status)
-		VALUES ($1, $2, ST_SetSRID(ST_MakePoint($3, $4), 4326), $5, $6) RETURNING id, created_at, updated_at;	
+		INSERT INTO incidents (name, description, location, radius_meters, status, media_urls, source, confidence, silent, category, external_id, public, severity, created_by)
+		VALUES ($1, $2, ST_SetSRID(ST_MakePoint($3, $4), 4326), $5, $6, COALESCE($7::text[], '{}'), $8, $9, $10, NULLIF($11, ''), NULLIF($12, ''), $13, $14, NULLIF($15, ''))
+		RETURNING ` + incidentColumns + `;
 	`
-	err := r.db.QueryRow(ctx, query,
+	created, err := scanIncident(tx.QueryRow(ctx, query,
 		incident.Name,
 		incident.Description,
 		incident.Longitude,
 		incident.Latitude,
 		incident.RadiusMeters,
 		incident.Status,
-	).Scan(&incident.ID, &incident.CreatedAt, &incident.UpdatedAt)
+		incident.MediaURLs,
+		incident.Source,
+		incident.Confidence,
+		incident.Silent,
+		incident.Category,
+		incident.ExternalID,
+		incident.Public,
+		incident.Severity,
+		incident.CreatedBy,
+	))
 	if err != nil {
-		return fmt.Errorf("failed to create incident: %w", err)
+		return incidentWriteError(err, "failed to create incident", incident.ExternalID)
+	}
+	if err := insertIncidentVersion(ctx, tx, created); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit create incident: %w", err)
 	}
+
+	incident.ID = created.ID
+	incident.CreatedAt = created.CreatedAt
+	incident.UpdatedAt = created.UpdatedAt
 	return nil
 }
 
+// incidentWriteError переводит нарушение уникальности внешнего ID в service.ErrExternalIDExists
+func incidentWriteError(err error, msg, externalID string) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation {
+		return fmt.Errorf("external id %q: %w", externalID, service.ErrExternalIDExists)
+	}
+	return fmt.Errorf("%s: %w", msg, err)
+}
+
 // GetByID возвращает инцидент по его UUID
 func (r *IncidentRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Incident, error) {
-	incident := &models.Incident{}
 	query := `
-		SELECT 
-			id,
-			name,
-			description,
-			ST_Y(location::geometry) as latitude,
-			ST_X(location::geometry) as longitude,
-			radius_meters,
-			status,
-			created_at,
-			updated_at
+		SELECT ` + incidentColumns + `
 		FROM incidents
 		WHERE id = $1;
 	`
-	err := r.db.QueryRow(ctx, query, id).Scan(
-		&incident.ID,
-		&incident.Name,
-		&incident.Description,
-		&incident.Latitude,
-		&incident.Longitude,
-		&incident.RadiusMeters,
-		&incident.Status,
-		&incident.CreatedAt,
-		&incident.UpdatedAt,
-	)
-
+	incident, err := scanIncident(r.db.QueryRow(ctx, query, id))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, fmt.Errorf("incident with id %s not found", id)
+			return nil, fmt.Errorf("incident with id %s: %w", id, service.ErrIncidentNotFound)
 		}
 		return nil, fmt.Errorf("failed to get incident by id: %w", err)
 	}
 	return incident, nil
 }
 
+// GetByExternalID возвращает инцидент по идентификатору во внешней системе
+func (r *IncidentRepository) GetByExternalID(ctx context.Context, externalID string) (*models.Incident, error) {
+	query := `
+		SELECT ` + incidentColumns + `
+		FROM incidents
+		WHERE external_id = $1;
+	`
+	incident, err := scanIncident(r.db.QueryRow(ctx, query, externalID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("incident with external id %q: %w", externalID, service.ErrIncidentNotFound)
+		}
+		return nil, fmt.Errorf("failed to get incident by external id: %w", err)
+	}
+	return incident, nil
+}
+
+// Update сохраняет изменения инцидента и записывает новую версию его снимка
 func (r *IncidentRepository) Update(ctx context.Context, incident *models.Incident) error {
+	return r.update(ctx, incident, nil)
+}
+
+// Revert сохраняет инцидент, возвращенный к прежней версии, как Update. Если откат меняет статус,
+// переход change записывается в историю статусов в той же транзакции.
+func (r *IncidentRepository) Revert(ctx context.Context, incident *models.Incident, change *models.StatusChange) error {
+	return r.update(ctx, incident, change)
+}
+
+// update сохраняет изменения инцидента, его новую версию и, если change не nil, переход в историю статусов
+func (r *IncidentRepository) update(ctx context.Context, incident *models.Incident, change *models.StatusChange) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin update incident transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
 	query := `
 		UPDATE incidents SET 
 			name = $1,
@@ -93,69 +228,291 @@ func (r *IncidentRepository) Update(ctx context.Context, incident *models.Incide
 			location = ST_SetSRID(ST_MakePoint($3, $4), 4326),
 			radius_meters = $5,
 			status = $6,
+			media_urls = COALESCE($7::text[], '{}'),
+			source = $8,
+			confidence = $9,
+			silent = $10,
+			resolution_reason = NULLIF($11, ''),
+			category = NULLIF($12, ''),
+			external_id = NULLIF($13, ''),
+			public = $14,
+			severity = $15,
 			updated_at = NOW()
-		WHERE id = $7;
+		WHERE id = $16
+		RETURNING ` + incidentColumns + `;
 		`
-	cmdTag, err := r.db.Exec(ctx, query,
+	updated, err := scanIncident(tx.QueryRow(ctx, query,
 		incident.Name,
 		incident.Description,
 		incident.Longitude,
 		incident.Latitude,
 		incident.RadiusMeters,
 		incident.Status,
+		incident.MediaURLs,
+		incident.Source,
+		incident.Confidence,
+		incident.Silent,
+		incident.ResolutionReason,
+		incident.Category,
+		incident.ExternalID,
+		incident.Public,
+		incident.Severity,
 		incident.ID,
-	)
+	))
 	if err != nil {
-		return fmt.Errorf("failed to update incident: %w", err)
+		// Ни одна строка не обновлена - инцидента с таким id не существует
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("incident with id %s not found for update", incident.ID)
+		}
+		return incidentWriteError(err, "failed to update incident", incident.ExternalID)
 	}
-
-	// Проверка, была ли хоть обновление одной строки, если RowsAffected() == 0, значит инцидента с таким id не существует
-	if cmdTag.RowsAffected() == 0 {
-		return fmt.Errorf("incident with id %s not found for update", incident.ID)
+	if err := insertIncidentVersion(ctx, tx, updated); err != nil {
+		return err
+	}
+	if change != nil {
+		if err := insertStatusChange(ctx, tx, change); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit update incident: %w", err)
 	}
 	return nil
 }
 
-// Delete(деактивация) устанавливает статус 'inactive' для инцидента
-func (r *IncidentRepository) Delete(ctx context.Context, id uuid.UUID) error {
+// Delete(деактивация) атомарно переводит инцидент из статуса from в 'inactive' или 'resolved' с причиной завершения.
+// Если инцидент уже не находится в статусе from, возвращается service.ErrStatusConflict.
+func (r *IncidentRepository) Delete(ctx context.Context, id uuid.UUID, from models.IncidentStatus, resolution models.Resolution) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin deactivate incident transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
 	query := `
 		UPDATE incidents SET
-			status = 'inactive',
+			status = $2,
+			resolution_reason = NULLIF($3, ''),
 			updated_at = NOW()
-		WHERE id = $1;
+		WHERE id = $1 AND status = $4
+		RETURNING ` + incidentColumns + `;
 	`
-	cmdTag, err := r.db.Exec(ctx, query, id)
+	deactivated, err := scanIncident(tx.QueryRow(ctx, query, id, resolution.Status(), resolution.Reason, from))
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("incident with id %s is not in status %s: %w", id, from, service.ErrStatusConflict)
+		}
 		return fmt.Errorf("failed to deactivate incident: %w", err)
 	}
+	if err := insertIncidentVersion(ctx, tx, deactivated); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit deactivate incident: %w", err)
+	}
+	return nil
+}
+
+// ChangeStatus атомарно переводит инцидент из FromStatus в ToStatus и записывает переход в историю статусов.
+// Если инцидент не находится в статусе FromStatus, возвращается service.ErrStatusConflict.
+func (r *IncidentRepository) ChangeStatus(ctx context.Context, change *models.StatusChange) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin status change transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
 
-	if cmdTag.RowsAffected() == 0 {
-		return fmt.Errorf("incident with id %s not found for deactivate", id)
+	updateQuery := `
+		UPDATE incidents SET
+			status = $1,
+			updated_at = NOW()
+		WHERE id = $2 AND status = $3
+		RETURNING ` + incidentColumns + `;
+	`
+	changed, err := scanIncident(tx.QueryRow(ctx, updateQuery, change.ToStatus, change.IncidentID, change.FromStatus))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("incident with id %s is not in status %s: %w", change.IncidentID, change.FromStatus, service.ErrStatusConflict)
+		}
+		return fmt.Errorf("failed to change incident status: %w", err)
+	}
+	if err := insertIncidentVersion(ctx, tx, changed); err != nil {
+		return err
+	}
+	if err := insertStatusChange(ctx, tx, change); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit status change: %w", err)
+	}
+	return nil
+}
+
+// insertStatusChange записывает переход статуса инцидента в историю статусов и заполняет ID и время перехода
+func insertStatusChange(ctx context.Context, tx pgx.Tx, change *models.StatusChange) error {
+	historyQuery := `
+		INSERT INTO incident_status_history (incident_id, from_status, to_status, changed_by)
+		VALUES ($1, $2, $3, $4) RETURNING id, changed_at;
+	`
+	err := tx.QueryRow(ctx, historyQuery,
+		change.IncidentID,
+		change.FromStatus,
+		change.ToStatus,
+		change.ChangedBy,
+	).Scan(&change.ID, &change.ChangedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save incident status history: %w", err)
 	}
 	return nil
 }
 
-// List возвращает список инцидентов с пагинацией
-func (r *IncidentRepository) ListIncidents(ctx context.Context, page, pageSize int) ([]*models.Incident, error) {
+// DeactivateIncidents одним запросом деактивирует инциденты по списку ID или фильтру.
+// Затрагиваются только действующие инциденты: черновики и отклоненные снимаются модерацией,
+// а уже неактивные и завершенные пропускаются, поэтому операция идемпотентна.
+// Возвращает фактически деактивированные инциденты.
+func (r *IncidentRepository) DeactivateIncidents(ctx context.Context, target models.BulkDeactivation) ([]*models.Incident, error) {
+	args := []any{models.StatusInactive, models.StatusActive, target.Reason}
+	conditions := []string{"status = $2"}
+	if len(target.IDs) > 0 {
+		args = append(args, target.IDs)
+		conditions = append(conditions, fmt.Sprintf("id = ANY($%d::uuid[])", len(args)))
+	}
+	if target.Status != "" {
+		args = append(args, target.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if target.OlderThan != nil {
+		args = append(args, *target.OlderThan)
+		conditions = append(conditions, fmt.Sprintf("created_at < $%d", len(args)))
+	}
+
+	query := `
+		UPDATE incidents SET
+			status = $1,
+			resolution_reason = CASE WHEN $3::text = '' THEN resolution_reason ELSE $3::text END,
+			updated_at = NOW()` + whereClause(conditions) + `
+		RETURNING ` + incidentColumns + `;
+	`
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin deactivate incidents transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to deactivate incidents: %w", err)
+	}
+	incidents := make([]*models.Incident, 0)
+	for rows.Next() {
+		incident, err := scanIncident(rows)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan deactivated incident row: %w", err)
+		}
+		incidents = append(incidents, incident)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error list iteration in DeactivateIncidents: %w", err)
+	}
+
+	for _, incident := range incidents {
+		if err := insertIncidentVersion(ctx, tx, incident); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit deactivate incidents: %w", err)
+	}
+	return incidents, nil
+}
+
+// PurgeInactiveIncidents безвозвратно удаляет не более limit инцидентов, неактивных или завершенных с момента до before.
+// Связанные записи (совпадения проверок, история статусов) удаляются каскадно.
+// Возвращает ID удаленных инцидентов.
+func (r *IncidentRepository) PurgeInactiveIncidents(ctx context.Context, before time.Time, limit int) ([]uuid.UUID, error) {
+	query := `
+		DELETE FROM incidents
+		WHERE id IN (
+			SELECT id
+			FROM incidents
+			WHERE status = ANY($3::text[]) AND updated_at < $1
+			ORDER BY updated_at
+			LIMIT $2
+		)
+		RETURNING id;
+	`
+	rows, err := r.db.Query(ctx, query, before, limit, statusNames(models.FinishedStatuses))
+	if err != nil {
+		return nil, fmt.Errorf("failed to purge inactive incidents: %w", err)
+	}
+	defer rows.Close()
+
+	ids := make([]uuid.UUID, 0)
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan purged incident id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error list iteration in PurgeInactiveIncidents: %w", err)
+	}
+	return ids, nil
+}
+
+// List возвращает список инцидентов с пагинацией и необязательными фильтрами
+func (r *IncidentRepository) ListIncidents(ctx context.Context, page, pageSize int, filter models.IncidentFilter) ([]*models.Incident, error) {
 	// рассчитываем смещение
 	offset := (page - 1) * pageSize
 
+	var (
+		conditions []string
+		args       []any
+		orderBy    = "created_at DESC"
+	)
+	if near := filter.Near; near != nil {
+		args = append(args, near.Longitude, near.Latitude, near.WithinMeters)
+		point := fmt.Sprintf("ST_SetSRID(ST_MakePoint($%d, $%d), 4326)::geography", len(args)-2, len(args)-1)
+		conditions = append(conditions, fmt.Sprintf("ST_DWithin(location::geography, %s, $%d)", point, len(args)))
+		orderBy = fmt.Sprintf("location::geography <-> %s", point)
+	}
+	if filter.MinConfidence > 0 {
+		args = append(args, filter.MinConfidence)
+		conditions = append(conditions, fmt.Sprintf("%s >= $%d", confidenceExpr, len(args)))
+	}
+	if after := filter.ChangedAfter; after != nil {
+		args = append(args, after.UpdatedAt, after.ID)
+		conditions = append(conditions, fmt.Sprintf("(updated_at, id) > ($%d, $%d)", len(args)-1, len(args)))
+		orderBy = "updated_at, id"
+	}
+	if filter.CreatedBefore != nil {
+		args = append(args, *filter.CreatedBefore)
+		conditions = append(conditions, fmt.Sprintf("created_at <= $%d", len(args)))
+	}
+	if filter.CreatedBy != "" {
+		args = append(args, filter.CreatedBy)
+		conditions = append(conditions, fmt.Sprintf("created_by = $%d", len(args)))
+	}
+	if filter.Sort != nil {
+		sortOrder, err := incidentOrderBy(*filter.Sort)
+		if err != nil {
+			return nil, err
+		}
+		orderBy = sortOrder
+	}
+
+	args = append(args, pageSize, offset)
 	query := `
-		SELECT 
-			id,
-			name,
-			description,
-			ST_Y(location::geometry) as latitude,
-			ST_X(location::geometry) as longitude,
-			radius_meters,
-			status,
-			created_at,
-			updated_at
-		FROM incidents
-		ORDER BY created_at DESC
-		LIMIT $1 OFFSET $2;
-	`
-	rows, err := r.db.Query(ctx, query, pageSize, offset)
+		SELECT ` + incidentColumns + `
+		FROM incidents` + whereClause(conditions) + `
+		ORDER BY ` + orderBy + fmt.Sprintf(`
+		LIMIT $%d OFFSET $%d;
+	`, len(args)-1, len(args))
+	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list incidents: %w", err)
 	}
@@ -163,18 +520,7 @@ func (r *IncidentRepository) ListIncidents(ctx context.Context, page, pageSize i
 
 	incidents := make([]*models.Incident, 0)
 	for rows.Next() {
-		incident := &models.Incident{}
-		err := rows.Scan(
-			&incident.ID,
-			&incident.Name,
-			&incident.Description,
-			&incident.Latitude,
-			&incident.Longitude,
-			&incident.RadiusMeters,
-			&incident.Status,
-			&incident.CreatedAt,
-			&incident.UpdatedAt,
-		)
+		incident, err := scanIncident(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan incident row: %w", err)
 		}
@@ -186,47 +532,251 @@ func (r *IncidentRepository) ListIncidents(ctx context.Context, page, pageSize i
 	return incidents, nil
 }
 
-// FindActiveByLocation находит активные инциденты, в радиус которых попадает точка
-func (r *IncidentRepository) FindActiveLocation(ctx context.Context, lat, lon float64) ([]*models.Incident, error) {
+// incidentSortColumns - выражения, по которым разрешена сортировка списка инцидентов.
+// Выражение подставляется в запрос только из этого списка. Уровень опасности хранится строкой,
+// поэтому сортируется по рангу (см. models.IncidentSeverities), а не по алфавиту.
+var incidentSortColumns = map[models.IncidentSortField]string{
+	models.SortByCreatedAt: "created_at",
+	models.SortByUpdatedAt: "updated_at",
+	models.SortByName:      "name",
+	models.SortBySeverity:  "CASE severity WHEN 'minor' THEN 0 WHEN 'moderate' THEN 1 WHEN 'major' THEN 2 WHEN 'critical' THEN 3 END",
+}
+
+// incidentOrderBy возвращает выражение ORDER BY для запрошенной сортировки.
+// id добавляется последним, чтобы порядок равных значений не менялся между страницами.
+func incidentOrderBy(sort models.IncidentSort) (string, error) {
+	column, ok := incidentSortColumns[sort.Field]
+	if !ok {
+		return "", fmt.Errorf("unsupported incident sort field %q", sort.Field)
+	}
+	direction := "ASC"
+	if sort.Descending {
+		direction = "DESC"
+	}
+	return fmt.Sprintf("%s %s, id %s", column, direction, direction), nil
+}
+
+// MaxUpdatedAt возвращает время последнего изменения инцидентов или нулевое время, если инцидентов нет.
+// Запрос обслуживается индексом idx_incidents_updated_at_id и не читает таблицу.
+func (r *IncidentRepository) MaxUpdatedAt(ctx context.Context) (time.Time, error) {
+	var updatedAt *time.Time
+	if err := r.db.QueryRow(ctx, `SELECT MAX(updated_at) FROM incidents;`).Scan(&updatedAt); err != nil {
+		return time.Time{}, fmt.Errorf("failed to get max incident updated_at: %w", err)
+	}
+	if updatedAt == nil {
+		return time.Time{}, nil
+	}
+	return *updatedAt, nil
+}
+
+// CurrentTime возвращает текущее время базы - то, которым проставляются created_at и updated_at
+func (r *IncidentRepository) CurrentTime(ctx context.Context) (time.Time, error) {
+	var now time.Time
+	if err := r.db.QueryRow(ctx, `SELECT NOW();`).Scan(&now); err != nil {
+		return time.Time{}, fmt.Errorf("failed to get database time: %w", err)
+	}
+	return now, nil
+}
+
+// bboxFilter возвращает условие попадания центра инцидента в область и его аргументы, нумерация которых
+// начинается с $start. Область, пересекающая 180-й меридиан, проверяется двумя прямоугольниками по обе стороны
+// от него: ST_MakeEnvelope с min_lon > max_lon описал бы всю долготу между ними, а не переход через ±180.
+func bboxFilter(bbox geo.BBox, start int) (string, []any) {
+	parts := bbox.SplitAntimeridian()
+	conditions := make([]string, len(parts))
+	args := make([]any, 0, 4*len(parts))
+	for i, part := range parts {
+		n := start + 4*i
+		conditions[i] = fmt.Sprintf("location::geometry && ST_MakeEnvelope($%d, $%d, $%d, $%d, 4326)", n, n+1, n+2, n+3)
+		args = append(args, part.MinLon, part.MinLat, part.MaxLon, part.MaxLat)
+	}
+	return "(" + strings.Join(conditions, " OR ") + ")", args
+}
+
+// ClusterActiveIncidents группирует активные инциденты внутри области по ячейкам сетки размером gridSize градусов
+func (r *IncidentRepository) ClusterActiveIncidents(ctx context.Context, bbox geo.BBox, gridSize float64) ([]*models.IncidentCluster, error) {
+	bboxCondition, bboxArgs := bboxFilter(bbox, 2)
 	query := `
-		SELECT 
-			id,
-			name,
-			description,
-			ST_Y(location::geometry) as latitude,
-			ST_X(location::geometry) as longitude,
-			radius_meters,
-			status,
-			created_at,
-			updated_at
+		SELECT
+			AVG(ST_Y(location::geometry)) as latitude,
+			AVG(ST_X(location::geometry)) as longitude,
+			COUNT(*) as count,
+			CASE WHEN COUNT(*) = 1 THEN (array_agg(id))[1] END as incident_id
 		FROM incidents
 		WHERE
 			status = 'active'
+			AND ` + bboxCondition + `
+		GROUP BY ST_SnapToGrid(location::geometry, $1)
+		ORDER BY count DESC;
+	`
+	rows, err := r.db.Query(ctx, query, append([]any{gridSize}, bboxArgs...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cluster incidents: %w", err)
+	}
+	defer rows.Close()
+
+	clusters := make([]*models.IncidentCluster, 0)
+	for rows.Next() {
+		cluster := &models.IncidentCluster{}
+		if err := rows.Scan(&cluster.Latitude, &cluster.Longitude, &cluster.Count, &cluster.IncidentID); err != nil {
+			return nil, fmt.Errorf("failed to scan incident cluster row: %w", err)
+		}
+		clusters = append(clusters, cluster)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error list iteration in ClusterActiveIncidents: %w", err)
+	}
+	return clusters, nil
+}
+
+// FindHotspots группирует активные инциденты в границах bbox по сетке с ячейкой latSize x lonSize градусов
+// и возвращает ячейки, в которые попало не меньше minCount инцидентов. Координаты результата - узел сетки,
+// к которому привязаны центры инцидентов ячейки (ее центр). Ячейки упорядочены по убыванию числа инцидентов.
+func (r *IncidentRepository) FindHotspots(ctx context.Context, bbox geo.BBox, latSize, lonSize float64, minCount int) ([]*models.IncidentHotspot, error) {
+	bboxCondition, bboxArgs := bboxFilter(bbox, 4)
+	query := `
+		SELECT
+			ST_Y(cell) AS latitude,
+			ST_X(cell) AS longitude,
+			COUNT(*) AS count
+		FROM (
+			SELECT ST_SnapToGrid(location::geometry, 0, 0, $1, $2) AS cell
+			FROM incidents
+			WHERE
+				status = 'active'
+				AND ` + bboxCondition + `
+		) cells
+		GROUP BY cell
+		HAVING COUNT(*) >= $3
+		ORDER BY count DESC;
+	`
+	rows, err := r.db.Query(ctx, query, append([]any{lonSize, latSize, minCount}, bboxArgs...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find incident hotspots: %w", err)
+	}
+	defer rows.Close()
+
+	hotspots := make([]*models.IncidentHotspot, 0)
+	for rows.Next() {
+		hotspot := &models.IncidentHotspot{}
+		if err := rows.Scan(&hotspot.Latitude, &hotspot.Longitude, &hotspot.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan incident hotspot row: %w", err)
+		}
+		hotspots = append(hotspots, hotspot)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error list iteration in FindHotspots: %w", err)
+	}
+	return hotspots, nil
+}
+
+// IncidentTimeline считает инциденты, созданные в [From, To), по корзинам размера Interval (границы в UTC).
+// Возвращаются только непустые корзины в порядке времени.
+func (r *IncidentRepository) IncidentTimeline(ctx context.Context, q models.TimelineQuery) ([]*models.TimelineBucket, error) {
+	conditions := []string{"created_at >= $2", "created_at < $3"}
+	args := []any{q.Interval, q.From, q.To}
+	if q.Source != "" {
+		args = append(args, q.Source)
+		conditions = append(conditions, "source = $"+strconv.Itoa(len(args)))
+	}
+	if q.Status != "" {
+		args = append(args, q.Status)
+		conditions = append(conditions, "status = $"+strconv.Itoa(len(args)))
+	}
+
+	query := `
+		SELECT
+			date_trunc($1, created_at AT TIME ZONE 'UTC') AT TIME ZONE 'UTC' AS bucket,
+			COUNT(*) AS count
+		FROM incidents
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		GROUP BY bucket
+		ORDER BY bucket;
+	`
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build incident timeline: %w", err)
+	}
+	defer rows.Close()
+
+	buckets := make([]*models.TimelineBucket, 0)
+	for rows.Next() {
+		bucket := &models.TimelineBucket{}
+		if err := rows.Scan(&bucket.Start, &bucket.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan incident timeline row: %w", err)
+		}
+		buckets = append(buckets, bucket)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error list iteration in IncidentTimeline: %w", err)
+	}
+	return buckets, nil
+}
+
+// ValidateGeofence проверяет зону радиусом radiusMeters вокруг точки средствами PostGIS (ST_IsValid)
+// и вычисляет ее площадь и границы. Зона строится так же, как при проверке местоположения.
+func (r *IncidentRepository) ValidateGeofence(ctx context.Context, lat, lon float64, radiusMeters int) (*models.GeofenceValidation, error) {
+	query := `
+		WITH zone AS (
+			SELECT ST_Buffer(ST_SetSRID(ST_MakePoint($1, $2), 4326)::geography, $3) AS area
+		)
+		SELECT
+			ST_IsValid(area::geometry),
+			COALESCE(ST_IsValidReason(area::geometry), ''),
+			ST_Area(area),
+			ST_YMin(area::geometry),
+			ST_XMin(area::geometry),
+			ST_YMax(area::geometry),
+			ST_XMax(area::geometry)
+		FROM zone;
+	`
+	result := &models.GeofenceValidation{}
+	var reason string
+	err := r.db.QueryRow(ctx, query, lon, lat, radiusMeters).Scan(
+		&result.Valid,
+		&reason,
+		&result.AreaSquareMeters,
+		&result.Bounds.MinLat,
+		&result.Bounds.MinLon,
+		&result.Bounds.MaxLat,
+		&result.Bounds.MaxLon,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate geofence: %w", err)
+	}
+	if !result.Valid {
+		result.Errors = append(result.Errors, reason)
+	}
+	return result, nil
+}
+
+// FindActiveByLocation находит активные инциденты, в радиус которых (с учетом буфера) попадает точка.
+// При minConfidence > 0 исключаются инциденты с меньшей достоверностью.
+//
+// Обе стороны ST_DWithin явно приводятся к geography: тогда расстояние считается по эллипсоиду в метрах.
+// Для geometry в SRID 4326 тот же вызов сравнивал бы градусы, и радиус в метрах тем сильнее расходился
+// бы с реальным, чем дальше точка от экватора (градус долготы на 70° с.ш. - около 38 км вместо 111 км).
+func (r *IncidentRepository) FindActiveLocation(ctx context.Context, lat, lon float64, bufferMeters, minConfidence int) ([]*models.Incident, error) {
+	query := `
+		SELECT ` + incidentColumns + `
+		FROM incidents
+		WHERE
+			status = 'active'
+			AND ` + confidenceExpr + ` >= $4
 			AND ST_DWithin(
-				location,
+				location::geography,
 				ST_SetSRID(ST_MakePoint($1, $2), 4326)::geography,
-				radius_meters
+				radius_meters + $3
 			);
 		`
-	rows, err := r.db.Query(ctx, query, lon, lat)
+	rows, err := r.db.Query(ctx, query, lon, lat, bufferMeters, minConfidence)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find active incidents by location: %w", err)
 	}
 	defer rows.Close()
 	incidents := make([]*models.Incident, 0)
 	for rows.Next() {
-		incident := &models.Incident{}
-		err := rows.Scan(
-			&incident.ID,
-			&incident.Name,
-			&incident.Description,
-			&incident.Latitude,
-			&incident.Longitude,
-			&incident.RadiusMeters,
-			&incident.Status,
-			&incident.CreatedAt,
-			&incident.UpdatedAt,
-		)
+		incident, err := scanIncident(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan incident row in FindActiveLocation: %w", err)
 		}
@@ -238,6 +788,58 @@ func (r *IncidentRepository) FindActiveLocation(ctx context.Context, lat, lon fl
 	return incidents, nil
 }
 
+// FindActiveLocationForAlert выполняет двухступенчатое сопоставление проверки местоположения в PostGIS.
+// inZone - инциденты, в зону которых с учетом bufferMeters попадает точка (как в FindActiveLocation);
+// alerted - инциденты, до зоны которых не больше bufferMeters плюс буфер оповещения их уровня опасности.
+func (r *IncidentRepository) FindActiveLocationForAlert(ctx context.Context, lat, lon float64, bufferMeters, minConfidence int, alertBuffers map[models.IncidentSeverity]int) (inZone, alerted []*models.Incident, err error) {
+	args := []any{lon, lat, bufferMeters, minConfidence}
+	severities := slices.Sorted(maps.Keys(alertBuffers))
+	alertBufferExpr := "0"
+	if len(severities) > 0 {
+		alertBufferExpr = "CASE severity"
+		for _, severity := range severities {
+			args = append(args, string(severity), alertBuffers[severity])
+			alertBufferExpr += fmt.Sprintf(" WHEN $%d THEN $%d", len(args)-1, len(args))
+		}
+		alertBufferExpr += " ELSE 0 END"
+	}
+
+	query := `
+		SELECT ` + incidentColumns + `,
+			ST_DWithin(location::geography, ST_SetSRID(ST_MakePoint($1, $2), 4326)::geography, radius_meters + $3) AS in_zone
+		FROM incidents
+		WHERE
+			status = 'active'
+			AND ` + confidenceExpr + ` >= $4
+			AND ST_DWithin(
+				location::geography,
+				ST_SetSRID(ST_MakePoint($1, $2), 4326)::geography,
+				radius_meters + $3 + ` + alertBufferExpr + `
+			);
+		`
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to find active incidents for alert: %w", err)
+	}
+	defer rows.Close()
+	inZone, alerted = make([]*models.Incident, 0), make([]*models.Incident, 0)
+	for rows.Next() {
+		var matched bool
+		incident, err := scanIncident(rows, &matched)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to scan incident row in FindActiveLocationForAlert: %w", err)
+		}
+		alerted = append(alerted, incident)
+		if matched {
+			inZone = append(inZone, incident)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("error list iteration in FindActiveLocationForAlert: %w", err)
+	}
+	return inZone, alerted, nil
+}
+
 // GetLocationCheckStats возвращает количество уникальных пользователей, проверивших геолокацию
 func (r *IncidentRepository) GetLocationCheckStats(ctx context.Context, minutes int) (int, error) {
 	query := `
@@ -256,27 +858,163 @@ func (r *IncidentRepository) GetLocationCheckStats(ctx context.Context, minutes
 	return count, nil
 }
 
-// SaveLocationCheck сохраняет запись о проверке местоположения в бд
-func (r *IncidentRepository) SaveLocationCheck(ctx context.Context, check *models.LocationCheck) error {
+// SaveLocationCheck сохраняет запись о проверке местоположения и совпавшие инциденты в одной транзакции.
+// Если outbox не nil, в той же транзакции записывается сообщение вебхука о проверке.
+func (r *IncidentRepository) SaveLocationCheck(ctx context.Context, check *models.LocationCheck, outbox *models.OutboxMessage) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin location check transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
 	query := `
-		INSERT INTO location_checks (user_id, location, is_dangerous)
-		VALUES ($1, ST_SetSRID(ST_MakePoint($2, $3), 4326), $4) RETURNING id, checked_at;
+		INSERT INTO location_checks (user_id, location, is_dangerous, authenticated)
+		VALUES ($1, ST_SetSRID(ST_MakePoint($2, $3), 4326), $4, $5) RETURNING id, checked_at;
 	`
-	err := r.db.QueryRow(ctx, query,
+	err = tx.QueryRow(ctx, query,
 		check.UserID,
 		check.Longitude,
 		check.Latitude,
 		check.IsDangerous,
+		check.Authenticated,
 	).Scan(&check.ID, &check.CheckedAt)
 	if err != nil {
 		return fmt.Errorf("failed to save location check: %w", err)
 	}
+
+	if len(check.IncidentIDs) > 0 {
+		linkQuery := `
+			INSERT INTO location_check_incidents (location_check_id, incident_id)
+			SELECT $1, unnest($2::uuid[]);
+		`
+		if _, err := tx.Exec(ctx, linkQuery, check.ID, check.IncidentIDs); err != nil {
+			return fmt.Errorf("failed to save location check incidents: %w", err)
+		}
+	}
+	if outbox != nil {
+		if err := insertOutboxMessage(ctx, tx, outbox); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit location check: %w", err)
+	}
 	return nil
 }
 
+// ListLocationChecksByUser возвращает историю проверок пользователя (новые первыми) с совпавшими инцидентами
+func (r *IncidentRepository) ListLocationChecksByUser(ctx context.Context, userID string, page, pageSize int) ([]*models.LocationCheck, error) {
+	offset := (page - 1) * pageSize
+
+	query := `
+		SELECT
+			lc.id,
+			lc.user_id,
+			ST_Y(lc.location::geometry) as latitude,
+			ST_X(lc.location::geometry) as longitude,
+			lc.is_dangerous,
+			lc.authenticated,
+			lc.checked_at,
+			COALESCE(array_agg(lci.incident_id) FILTER (WHERE lci.incident_id IS NOT NULL), '{}') as incident_ids
+		FROM location_checks lc
+		LEFT JOIN location_check_incidents lci ON lci.location_check_id = lc.id
+		WHERE lc.user_id = $1
+		GROUP BY lc.id
+		ORDER BY lc.checked_at DESC
+		LIMIT $2 OFFSET $3;
+	`
+	rows, err := r.db.Query(ctx, query, userID, pageSize, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list location checks: %w", err)
+	}
+	defer rows.Close()
+
+	checks := make([]*models.LocationCheck, 0)
+	for rows.Next() {
+		check := &models.LocationCheck{}
+		err := rows.Scan(
+			&check.ID,
+			&check.UserID,
+			&check.Latitude,
+			&check.Longitude,
+			&check.IsDangerous,
+			&check.Authenticated,
+			&check.CheckedAt,
+			&check.IncidentIDs,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan location check row: %w", err)
+		}
+		checks = append(checks, check)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error list iteration in ListLocationChecksByUser: %w", err)
+	}
+	return checks, nil
+}
+
+// GetIncidentImpact возвращает количество уникальных пользователей, чьи проверки
+// совпали с инцидентом за последние minutes минут
+func (r *IncidentRepository) GetIncidentImpact(ctx context.Context, incidentID uuid.UUID, minutes int) (int, error) {
+	query := `
+		SELECT COUNT(DISTINCT lc.user_id)
+		FROM location_checks lc
+		JOIN location_check_incidents lci ON lci.location_check_id = lc.id
+		WHERE lci.incident_id = $1
+			AND lc.checked_at >= NOW() - ($2 * INTERVAL '1 minute');
+	`
+	var count int
+	if err := r.db.QueryRow(ctx, query, incidentID, minutes).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to get incident impact: %w", err)
+	}
+	return count, nil
+}
+
+// CountRecentUsersInArea возвращает количество уникальных пользователей, чьи проверки за последние
+// minutes минут попали в круг радиусом radiusMeters вокруг точки
+func (r *IncidentRepository) CountRecentUsersInArea(ctx context.Context, lat, lon float64, radiusMeters, minutes int) (int, error) {
+	query := `
+		SELECT COUNT(DISTINCT user_id)
+		FROM location_checks
+		WHERE checked_at >= NOW() - ($4 * INTERVAL '1 minute')
+			AND ST_DWithin(
+				location::geography,
+				ST_SetSRID(ST_MakePoint($1, $2), 4326)::geography,
+				$3
+			);
+	`
+	var count int
+	if err := r.db.QueryRow(ctx, query, lon, lat, radiusMeters, minutes).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count recent users in area: %w", err)
+	}
+	return count, nil
+}
+
+// storedIncident - инцидент в кэше Redis и в снимках версий. JSON модели не содержит created_by,
+// поэтому метка создателя хранится отдельным полем, чтобы не теряться при чтении из кэша.
+type storedIncident struct {
+	*models.Incident
+	CreatedBy string `json:"created_by,omitempty"`
+}
+
+// newStoredIncident подготавливает инцидент к сохранению
+func newStoredIncident(incident *models.Incident) storedIncident {
+	return storedIncident{Incident: incident, CreatedBy: incident.CreatedBy}
+}
+
+// model возвращает прочитанный инцидент с восстановленной меткой создателя
+func (s storedIncident) model() *models.Incident {
+	if s.Incident == nil {
+		s.Incident = &models.Incident{}
+	}
+	s.Incident.CreatedBy = s.CreatedBy
+	return s.Incident
+}
+
 // GetIncidentFromCache пытается получить инцидент из Redis
 func (r *IncidentRepository) GetIncidentFromCache(ctx context.Context, id uuid.UUID) (*models.Incident, error) {
-	key := fmt.Sprintf("incident:%s", id.String())
+	key := r.incidentCacheKey(id)
 	val, err := r.redisClient.Get(ctx, key).Bytes()
 	if err != nil {
 		if errors.Is(err, redis.Nil) {
@@ -285,17 +1023,17 @@ func (r *IncidentRepository) GetIncidentFromCache(ctx context.Context, id uuid.U
 		return nil, fmt.Errorf("failed to get incident from cache: %w", err)
 	}
 
-	incident := &models.Incident{}
-	if err := json.Unmarshal(val, incident); err != nil {
+	var stored storedIncident
+	if err := json.Unmarshal(val, &stored); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal incident from cache: %w", err)
 	}
-	return incident, nil
+	return stored.model(), nil
 }
 
 // SetIncidentCache сохраняет инцидент в Redis
 func (r *IncidentRepository) SetIncidentCache(ctx context.Context, incident *models.Incident) error {
-	key := fmt.Sprintf("incident:%s", incident.ID.String())
-	val, err := json.Marshal(incident)
+	key := r.incidentCacheKey(incident.ID)
+	val, err := json.Marshal(newStoredIncident(incident))
 	if err != nil {
 		return fmt.Errorf("failed to marshal incident for cache: %w", err)
 	}
@@ -306,11 +1044,64 @@ func (r *IncidentRepository) SetIncidentCache(ctx context.Context, incident *mod
 	return nil
 }
 
-// InvalidateIncidentCache удаляет инцидент из Redis кэша
-func (r *IncidentRepository) InvalidateIncidentCache(ctx context.Context, id uuid.UUID) error {
-	key := fmt.Sprintf("incident:%s", id.String())
-	if err := r.redisClient.Del(ctx, key).Err(); err != nil {
+// InvalidateIncidentCache удаляет инциденты из Redis кэша одной командой DEL
+func (r *IncidentRepository) InvalidateIncidentCache(ctx context.Context, ids ...uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = r.incidentCacheKey(id)
+	}
+	if err := r.redisClient.Del(ctx, keys...).Err(); err != nil {
 		return fmt.Errorf("failed to invalidate incident cache: %w", err)
 	}
 	return nil
 }
+
+// ExpireIncidentCache сокращает срок жизни записей кэша инцидентов до ttl, если они есть, одним запросом.
+// Используется, когда записи не удалось удалить после изменения инцидентов.
+func (r *IncidentRepository) ExpireIncidentCache(ctx context.Context, ttl time.Duration, ids ...uuid.UUID) error {
+	pipe := r.redisClient.Pipeline()
+	for _, id := range ids {
+		pipe.Expire(ctx, r.incidentCacheKey(id), ttl)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to expire incident cache: %w", err)
+	}
+	return nil
+}
+
+// statsCacheKey возвращает ключ кэша статистики для окна в минутах
+func (r *IncidentRepository) statsCacheKey(minutes int) string {
+	return r.keys.Key("stats", "location_checks", strconv.Itoa(minutes))
+}
+
+// GetStatsFromCache возвращает закэшированную статистику проверок или nil, если ее нет в кэше
+func (r *IncidentRepository) GetStatsFromCache(ctx context.Context, minutes int) (*models.StatsSnapshot, error) {
+	val, err := r.redisClient.Get(ctx, r.statsCacheKey(minutes)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get stats from cache: %w", err)
+	}
+
+	snapshot := &models.StatsSnapshot{}
+	if err := json.Unmarshal(val, snapshot); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal stats from cache: %w", err)
+	}
+	return snapshot, nil
+}
+
+// SetStatsCache сохраняет статистику проверок в Redis на время ttl
+func (r *IncidentRepository) SetStatsCache(ctx context.Context, minutes int, snapshot *models.StatsSnapshot, ttl time.Duration) error {
+	val, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to marshal stats for cache: %w", err)
+	}
+	if err := r.redisClient.Set(ctx, r.statsCacheKey(minutes), val, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to set stats in cache: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,358 @@
+//go:build integration
+
+package repository
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shenikar/geo_broadcasting_system/internal/models"
+	"github.com/shenikar/geo_broadcasting_system/pkg/geo"
+	redisclient "github.com/shenikar/geo_broadcasting_system/pkg/redis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// metersPerDegreeLat - длина градуса широты (и градуса долготы на экваторе) в метрах
+const metersPerDegreeLat = 111320.0
+
+// offsetPoint смещает точку на north метров к северу и east метров к востоку.
+// Для смещений порядка километра погрешность приближения - сотые доли процента.
+func offsetPoint(lat, lon, north, east float64) (float64, float64) {
+	return lat + north/metersPerDegreeLat, lon + east/(metersPerDegreeLat*math.Cos(lat*math.Pi/180))
+}
+
+// TestFindActiveLocation_RadiusInMeters проверяет, что радиус инцидента считается в метрах по эллипсоиду
+// на разных широтах: точки чуть внутри радиуса находятся, чуть снаружи - нет. Запас в 2% перекрывает
+// расхождение между сферой в offsetPoint и эллипсоидом PostGIS, но не ошибку сравнения в градусах.
+func TestFindActiveLocation_RadiusInMeters(t *testing.T) {
+	pool := startPostGIS(t)
+	repo := NewIncidentRepository(pool, nil, redisclient.NewKeyBuilder(""))
+	ctx := context.Background()
+
+	const radius = 1000
+	const margin = 0.02
+
+	for _, lat := range []float64{0, 45, 60, 70} {
+		incident := &models.Incident{
+			Name:         fmt.Sprintf("Incident at %g", lat),
+			Latitude:     lat,
+			Longitude:    30,
+			RadiusMeters: radius,
+			Status:       models.StatusActive,
+			Source:       models.SourceOfficial,
+			Severity:     models.SeverityMinor,
+		}
+		require.NoError(t, repo.Create(ctx, incident))
+
+		for _, dir := range []struct {
+			name        string
+			north, east float64
+		}{
+			{"north", 1, 0},
+			{"east", 0, 1},
+			{"southwest", -math.Sqrt2 / 2, -math.Sqrt2 / 2},
+		} {
+			t.Run(fmt.Sprintf("lat %g %s", lat, dir.name), func(t *testing.T) {
+				inside := radius * (1 - margin)
+				pLat, pLon := offsetPoint(lat, 30, dir.north*inside, dir.east*inside)
+				found, err := repo.FindActiveLocation(ctx, pLat, pLon, 0, 0)
+				require.NoError(t, err)
+				assert.True(t, containsIncident(found, incident), "point %.0fm away must be inside", inside)
+
+				outside := radius * (1 + margin)
+				pLat, pLon = offsetPoint(lat, 30, dir.north*outside, dir.east*outside)
+				found, err = repo.FindActiveLocation(ctx, pLat, pLon, 0, 0)
+				require.NoError(t, err)
+				assert.False(t, containsIncident(found, incident), "point %.0fm away must be outside", outside)
+			})
+		}
+	}
+}
+
+// TestFindActiveLocationForAlert проверяет, что буфер оповещения зависит от уровня опасности инцидента,
+// а попадание в зону считается тем же запросом без него
+func TestFindActiveLocationForAlert(t *testing.T) {
+	pool := startPostGIS(t)
+	repo := NewIncidentRepository(pool, nil, redisclient.NewKeyBuilder(""))
+	ctx := context.Background()
+
+	newIncident := func(severity models.IncidentSeverity) *models.Incident {
+		incident := &models.Incident{
+			Name:         "Incident " + string(severity),
+			Latitude:     10,
+			Longitude:    30,
+			RadiusMeters: 1000,
+			Status:       models.StatusActive,
+			Source:       models.SourceOfficial,
+			Severity:     severity,
+		}
+		require.NoError(t, repo.Create(ctx, incident))
+		return incident
+	}
+	critical, minor := newIncident(models.SeverityCritical), newIncident(models.SeverityMinor)
+	alertBuffers := map[models.IncidentSeverity]int{models.SeverityCritical: 500}
+
+	// Точка в 1300 м от центров: вне обеих зон, но в буфере оповещения критического инцидента
+	pLat, pLon := offsetPoint(10, 30, 1300, 0)
+	inZone, alerted, err := repo.FindActiveLocationForAlert(ctx, pLat, pLon, 0, 0, alertBuffers)
+	require.NoError(t, err)
+	assert.Empty(t, inZone)
+	assert.True(t, containsIncident(alerted, critical))
+	assert.False(t, containsIncident(alerted, minor))
+
+	// Точка внутри зон попадает в оба набора
+	pLat, pLon = offsetPoint(10, 30, 900, 0)
+	inZone, alerted, err = repo.FindActiveLocationForAlert(ctx, pLat, pLon, 0, 0, alertBuffers)
+	require.NoError(t, err)
+	assert.True(t, containsIncident(inZone, critical) && containsIncident(inZone, minor))
+	assert.True(t, containsIncident(alerted, critical) && containsIncident(alerted, minor))
+}
+
+// TestListIncidents_ChangedAfterSameTimestamp проверяет, что постраничное чтение ленты изменений по курсору
+// (updated_at, id) не теряет инциденты, измененные в одну и ту же микросекунду
+func TestListIncidents_ChangedAfterSameTimestamp(t *testing.T) {
+	pool := startPostGIS(t)
+	repo := NewIncidentRepository(pool, nil, redisclient.NewKeyBuilder(""))
+	ctx := context.Background()
+
+	since := time.Now().Add(-time.Minute)
+	for i := range 5 {
+		require.NoError(t, repo.Create(ctx, &models.Incident{
+			Name:         fmt.Sprintf("Incident %d", i),
+			Latitude:     10,
+			Longitude:    30,
+			RadiusMeters: 100,
+			Status:       models.StatusActive,
+			Source:       models.SourceOfficial,
+			Severity:     models.SeverityMinor,
+		}))
+	}
+	// Одна транзакция - одно значение NOW() у всех изменений
+	_, err := pool.Exec(ctx, `UPDATE incidents SET updated_at = NOW()`)
+	require.NoError(t, err)
+
+	after := models.ChangeCursor{UpdatedAt: since}
+	seen := make(map[uuid.UUID]bool)
+	for range 5 {
+		page, err := repo.ListIncidents(ctx, 1, 2, models.IncidentFilter{ChangedAfter: &after})
+		require.NoError(t, err)
+		if len(page) == 0 {
+			break
+		}
+		for _, incident := range page {
+			assert.False(t, seen[incident.ID], "incident %s returned twice", incident.ID)
+			seen[incident.ID] = true
+		}
+		last := page[len(page)-1]
+		after = models.ChangeCursor{UpdatedAt: last.UpdatedAt, ID: last.ID}
+	}
+	assert.Len(t, seen, 5)
+}
+
+// containsIncident сообщает, есть ли инцидент среди найденных
+func containsIncident(found []*models.Incident, incident *models.Incident) bool {
+	for _, f := range found {
+		if f.ID == incident.ID {
+			return true
+		}
+	}
+	return false
+}
+
+// TestFindMissedLocationAlerts_StatusAtCheckTime проверяет, что повторная оценка находит безопасные проверки
+// в зоне инцидента, только если инцидент уже существовал и был активен в момент проверки
+func TestFindMissedLocationAlerts_StatusAtCheckTime(t *testing.T) {
+	pool := startPostGIS(t)
+	repo := NewIncidentRepository(pool, nil, redisclient.NewKeyBuilder(""))
+	ctx := context.Background()
+
+	incident := &models.Incident{
+		Name:         "Прорыв трубы",
+		Latitude:     55.75,
+		Longitude:    37.61,
+		RadiusMeters: 500,
+		Status:       models.StatusActive,
+		Source:       models.SourceOfficial,
+		Severity:     models.SeverityMinor,
+	}
+	require.NoError(t, repo.Create(ctx, incident))
+
+	// Проверка до создания инцидента
+	_, err := pool.Exec(ctx, `
+		INSERT INTO location_checks (user_id, location, is_dangerous, checked_at)
+		VALUES ('before', ST_SetSRID(ST_MakePoint($1, $2), 4326), FALSE, NOW() - INTERVAL '1 hour');
+	`, incident.Longitude, incident.Latitude)
+	require.NoError(t, err)
+
+	// Пока инцидент активен: пропущенное предупреждение, верно обработанная опасность и точка вне зоны
+	missed := &models.LocationCheck{UserID: "missed", Latitude: 55.751, Longitude: 37.611}
+	require.NoError(t, repo.SaveLocationCheck(ctx, missed, nil))
+	alerted := &models.LocationCheck{UserID: "alerted", Latitude: 55.751, Longitude: 37.611, IsDangerous: true}
+	require.NoError(t, repo.SaveLocationCheck(ctx, alerted, nil))
+	far := &models.LocationCheck{UserID: "far", Latitude: 55.80, Longitude: 37.61}
+	require.NoError(t, repo.SaveLocationCheck(ctx, far, nil))
+
+	// После деактивации инцидента проверка безопасна
+	require.NoError(t, repo.Delete(ctx, incident.ID, models.StatusActive, models.Resolution{}))
+	after := &models.LocationCheck{UserID: "after", Latitude: 55.751, Longitude: 37.611}
+	require.NoError(t, repo.SaveLocationCheck(ctx, after, nil))
+
+	from, to := missed.CheckedAt.Add(-2*time.Hour), after.CheckedAt.Add(time.Minute)
+	found, err := repo.FindMissedLocationAlerts(ctx, from, to, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, "missed", found[0].UserID)
+	assert.Equal(t, []uuid.UUID{incident.ID}, found[0].IncidentIDs)
+
+	// Курсор пропускает уже просмотренные проверки
+	found, err = repo.FindMissedLocationAlerts(ctx, from, to, missed.ID, 10)
+	require.NoError(t, err)
+	assert.Empty(t, found)
+
+	count, err := repo.CountLocationChecks(ctx, from, to)
+	require.NoError(t, err)
+	assert.Equal(t, 5, count)
+}
+
+// TestDeactivateIncidents_OnlyActive проверяет, что массовая деактивация не трогает черновики,
+// отклоненные и уже завершенные инциденты, даже если они перечислены явно
+func TestDeactivateIncidents_OnlyActive(t *testing.T) {
+	pool := startPostGIS(t)
+	repo := NewIncidentRepository(pool, nil, redisclient.NewKeyBuilder(""))
+	ctx := context.Background()
+
+	ids := make([]uuid.UUID, 0, len(models.IncidentStatuses))
+	var activeID uuid.UUID
+	for _, status := range models.IncidentStatuses {
+		incident := &models.Incident{
+			Name:         "Инцидент " + string(status),
+			Latitude:     55.75,
+			Longitude:    37.61,
+			RadiusMeters: 500,
+			Status:       status,
+			Source:       models.SourceOfficial,
+			Severity:     models.SeverityMinor,
+		}
+		require.NoError(t, repo.Create(ctx, incident))
+		ids = append(ids, incident.ID)
+		if status == models.StatusActive {
+			activeID = incident.ID
+		}
+	}
+
+	deactivated, err := repo.DeactivateIncidents(ctx, models.BulkDeactivation{IDs: ids})
+	require.NoError(t, err)
+	require.Len(t, deactivated, 1)
+	assert.Equal(t, activeID, deactivated[0].ID)
+	assert.Equal(t, models.StatusInactive, deactivated[0].Status)
+
+	// Повторный вызов ничего не меняет
+	deactivated, err = repo.DeactivateIncidents(ctx, models.BulkDeactivation{IDs: ids})
+	require.NoError(t, err)
+	assert.Empty(t, deactivated)
+}
+
+// TestMergeIncidents_RedirectsDuplicateData проверяет, что данные дубликата переходят к каноническому инциденту:
+// проверка, совпавшая с обоими, не дублируется, а дубликат деактивируется
+func TestMergeIncidents_RedirectsDuplicateData(t *testing.T) {
+	pool := startPostGIS(t)
+	repo := NewIncidentRepository(pool, nil, redisclient.NewKeyBuilder(""))
+	ctx := context.Background()
+
+	newIncident := func(name string) *models.Incident {
+		incident := &models.Incident{
+			Name:         name,
+			Latitude:     55.75,
+			Longitude:    37.61,
+			RadiusMeters: 500,
+			Status:       models.StatusActive,
+			Source:       models.SourceOfficial,
+			Severity:     models.SeverityMinor,
+		}
+		require.NoError(t, repo.Create(ctx, incident))
+		return incident
+	}
+	canonical, duplicate := newIncident("Пожар"), newIncident("Пожар (дубль)")
+
+	both := &models.LocationCheck{UserID: "both", Latitude: 55.75, Longitude: 37.61, IncidentIDs: []uuid.UUID{canonical.ID, duplicate.ID}}
+	require.NoError(t, repo.SaveLocationCheck(ctx, both, nil))
+	onlyDuplicate := &models.LocationCheck{UserID: "duplicate", Latitude: 55.75, Longitude: 37.61, IncidentIDs: []uuid.UUID{duplicate.ID}}
+	require.NoError(t, repo.SaveLocationCheck(ctx, onlyDuplicate, nil))
+	attachment := &models.IncidentAttachment{IncidentID: duplicate.ID, ObjectKey: "incidents/duplicate/photo.jpg", Filename: "photo.jpg", ContentType: "image/jpeg"}
+	require.NoError(t, repo.CreateIncidentAttachment(ctx, attachment))
+	_, err := pool.Exec(ctx, `INSERT INTO incident_views (incident_id, views) VALUES ($1, 3), ($2, 5);`, canonical.ID, duplicate.ID)
+	require.NoError(t, err)
+	require.NoError(t, repo.CreateIncidentWatcher(ctx, &models.IncidentWatcher{IncidentID: canonical.ID, URL: "https://shared.example.org/hook"}))
+	require.NoError(t, repo.CreateIncidentWatcher(ctx, &models.IncidentWatcher{IncidentID: duplicate.ID, URL: "https://shared.example.org/hook"}))
+	watcher := &models.IncidentWatcher{IncidentID: duplicate.ID, URL: "https://only-duplicate.example.org/hook"}
+	require.NoError(t, repo.CreateIncidentWatcher(ctx, watcher))
+
+	result, err := repo.MergeIncidents(ctx, models.IncidentMerge{CanonicalID: canonical.ID, DuplicateIDs: []uuid.UUID{duplicate.ID}})
+	require.NoError(t, err)
+	require.Len(t, result.Merged, 1)
+	assert.Equal(t, models.StatusInactive, result.Merged[0].Status)
+	assert.Equal(t, int64(1), result.RedirectedChecks)
+
+	var canonicalChecks, duplicateChecks int
+	require.NoError(t, pool.QueryRow(ctx, `SELECT COUNT(*) FROM location_check_incidents WHERE incident_id = $1;`, canonical.ID).Scan(&canonicalChecks))
+	require.NoError(t, pool.QueryRow(ctx, `SELECT COUNT(*) FROM location_check_incidents WHERE incident_id = $1;`, duplicate.ID).Scan(&duplicateChecks))
+	assert.Equal(t, 2, canonicalChecks)
+	assert.Zero(t, duplicateChecks)
+
+	attachments, err := repo.ListIncidentAttachments(ctx, canonical.ID)
+	require.NoError(t, err)
+	require.Len(t, attachments, 1)
+	assert.Equal(t, attachment.ID, attachments[0].ID)
+
+	views, err := repo.GetIncidentViews(ctx, canonical.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(8), views)
+	views, err = repo.GetIncidentViews(ctx, duplicate.ID)
+	require.NoError(t, err)
+	assert.Zero(t, views)
+
+	// Наблюдатель дубликата переходит к каноническому инциденту с тем же ID, общий адрес не дублируется
+	watchers, err := repo.ListIncidentWatchers(ctx, canonical.ID)
+	require.NoError(t, err)
+	require.Len(t, watchers, 2)
+	assert.Equal(t, watcher.ID, watchers[1].ID)
+}
+
+// TestAntimeridian проверяет выборки у 180-го меридиана: область с min_lon > max_lon охватывает обе стороны
+// от него, а зона инцидента по одну сторону находит точку по другую
+func TestAntimeridian(t *testing.T) {
+	pool := startPostGIS(t)
+	repo := NewIncidentRepository(pool, nil, redisclient.NewKeyBuilder(""))
+	ctx := context.Background()
+
+	for _, lon := range []float64{179.99, -179.99, 0} {
+		require.NoError(t, repo.Create(ctx, &models.Incident{
+			Name:         fmt.Sprintf("Incident at %g", lon),
+			Latitude:     -17.5,
+			Longitude:    lon,
+			RadiusMeters: 3000,
+			Status:       models.StatusActive,
+			Source:       models.SourceOfficial,
+			Severity:     models.SeverityMinor,
+		}))
+	}
+	bbox := geo.BBox{MinLat: -19, MinLon: 177, MaxLat: -16, MaxLon: -179}
+
+	clusters, err := repo.ClusterActiveIncidents(ctx, bbox, 0.001)
+	require.NoError(t, err)
+	assert.Len(t, clusters, 2)
+
+	hotspots, err := repo.FindHotspots(ctx, bbox, 0.001, 0.001, 1)
+	require.NoError(t, err)
+	assert.Len(t, hotspots, 2)
+
+	// Точка на 180-м меридиане в 1 км от обоих инцидентов
+	found, err := repo.FindActiveLocation(ctx, -17.5, 180, 0, 0)
+	require.NoError(t, err)
+	assert.Len(t, found, 2)
+}
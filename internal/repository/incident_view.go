@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/redis/go-redis/v9"
+)
+
+// takeHashScript атомарно читает хеш и удаляет его, чтобы просмотры, учтенные во время переноса
+// в PostgreSQL, попали в новый хеш и не потерялись. KEYS[1] - ключ хеша.
+var takeHashScript = redis.NewScript(`
+local values = redis.call('HGETALL', KEYS[1])
+redis.call('DEL', KEYS[1])
+return values
+`)
+
+// incidentViewsKey - хеш счетчиков просмотров, еще не перенесенных в PostgreSQL: поле - ID инцидента
+func (r *IncidentRepository) incidentViewsKey() string {
+	return r.keys.Key("incident_views")
+}
+
+// IncrementIncidentViews увеличивает счетчики просмотров инцидентов в Redis одним запросом
+func (r *IncidentRepository) IncrementIncidentViews(ctx context.Context, counts map[uuid.UUID]int64) error {
+	if len(counts) == 0 {
+		return nil
+	}
+	key := r.incidentViewsKey()
+	pipe := r.redisClient.Pipeline()
+	for id, count := range counts {
+		pipe.HIncrBy(ctx, key, id.String(), count)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to increment incident views: %w", err)
+	}
+	return nil
+}
+
+// GetPendingIncidentViews возвращает число просмотров инцидента, еще не перенесенных из Redis в PostgreSQL
+func (r *IncidentRepository) GetPendingIncidentViews(ctx context.Context, id uuid.UUID) (int64, error) {
+	views, err := r.redisClient.HGet(ctx, r.incidentViewsKey(), id.String()).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get pending incident views: %w", err)
+	}
+	return views, nil
+}
+
+// GetIncidentViews возвращает число просмотров инцидента, сохраненных в PostgreSQL
+func (r *IncidentRepository) GetIncidentViews(ctx context.Context, id uuid.UUID) (int64, error) {
+	var views int64
+	err := r.db.QueryRow(ctx, `SELECT views FROM incident_views WHERE incident_id = $1;`, id).Scan(&views)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get incident views: %w", err)
+	}
+	return views, nil
+}
+
+// PersistIncidentViews переносит счетчики просмотров из Redis в PostgreSQL и возвращает число
+// обновленных инцидентов. Просмотры удаленных инцидентов отбрасываются. Если запись в PostgreSQL
+// не удалась, счетчики возвращаются в Redis до следующего переноса.
+func (r *IncidentRepository) PersistIncidentViews(ctx context.Context) (int, error) {
+	key := r.incidentViewsKey()
+	values, err := takeHashScript.Run(ctx, r.redisClient, []string{key}).StringSlice()
+	if err != nil {
+		return 0, fmt.Errorf("failed to take incident views: %w", err)
+	}
+	if len(values) == 0 {
+		return 0, nil
+	}
+
+	counts := make(map[uuid.UUID]int64, len(values)/2)
+	ids := make([]uuid.UUID, 0, len(values)/2)
+	views := make([]int64, 0, len(values)/2)
+	for i := 0; i+1 < len(values); i += 2 {
+		id, err := uuid.Parse(values[i])
+		if err != nil {
+			continue
+		}
+		count, err := strconv.ParseInt(values[i+1], 10, 64)
+		if err != nil {
+			continue
+		}
+		counts[id] = count
+		ids = append(ids, id)
+		views = append(views, count)
+	}
+
+	query := `
+		INSERT INTO incident_views (incident_id, views)
+		SELECT v.incident_id, v.views
+		FROM UNNEST($1::uuid[], $2::bigint[]) AS v (incident_id, views)
+		JOIN incidents i ON i.id = v.incident_id
+		ON CONFLICT (incident_id) DO UPDATE
+		SET views = incident_views.views + EXCLUDED.views, updated_at = NOW();
+	`
+	tag, err := r.db.Exec(ctx, query, ids, views)
+	if err != nil {
+		if restoreErr := r.IncrementIncidentViews(context.WithoutCancel(ctx), counts); restoreErr != nil {
+			return 0, fmt.Errorf("failed to persist incident views: %w (%d counters lost: %v)", err, len(counts), restoreErr)
+		}
+		return 0, fmt.Errorf("failed to persist incident views: %w", err)
+	}
+	return int(tag.RowsAffected()), nil
+}
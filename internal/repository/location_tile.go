@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/shenikar/geo_broadcasting_system/internal/models"
+	"github.com/shenikar/geo_broadcasting_system/pkg/geo"
+)
+
+// locationTileGenerationKey возвращает ключ счетчика поколений кэша ячеек.
+// Увеличение счетчика делает недоступными все ранее закэшированные ячейки.
+func (r *IncidentRepository) locationTileGenerationKey() string {
+	return r.keys.Key("location_tiles", "generation")
+}
+
+// locationTileCacheKey возвращает ключ кэша ячейки geohash для поколения generation
+func (r *IncidentRepository) locationTileCacheKey(generation int64, geohash string) string {
+	return r.keys.Key("location_tiles", strconv.FormatInt(generation, 10), geohash)
+}
+
+// FindActiveInArea возвращает активные инциденты, чья зона, расширенная на bufferMeters, пересекает область.
+// Расстояние считается в метрах по geography, как в FindActiveLocation.
+func (r *IncidentRepository) FindActiveInArea(ctx context.Context, bbox geo.BBox, bufferMeters int) ([]*models.Incident, error) {
+	query := `
+		SELECT ` + incidentColumns + `
+		FROM incidents
+		WHERE
+			status = 'active'
+			AND ST_DWithin(
+				location::geography,
+				ST_MakeEnvelope($1, $2, $3, $4, 4326)::geography,
+				radius_meters + $5
+			);
+		`
+	rows, err := r.db.Query(ctx, query, bbox.MinLon, bbox.MinLat, bbox.MaxLon, bbox.MaxLat, bufferMeters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find active incidents in area: %w", err)
+	}
+	defer rows.Close()
+	incidents := make([]*models.Incident, 0)
+	for rows.Next() {
+		incident, err := scanIncident(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan incident row in FindActiveInArea: %w", err)
+		}
+		incidents = append(incidents, incident)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error list iteration in FindActiveInArea: %w", err)
+	}
+	return incidents, nil
+}
+
+// ListActiveIncidents возвращает все активные инциденты (для построения индекса в памяти)
+func (r *IncidentRepository) ListActiveIncidents(ctx context.Context) ([]*models.Incident, error) {
+	query := `
+		SELECT ` + incidentColumns + `
+		FROM incidents
+		WHERE status = 'active';
+	`
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list active incidents: %w", err)
+	}
+	defer rows.Close()
+	incidents := make([]*models.Incident, 0)
+	for rows.Next() {
+		incident, err := scanIncident(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan incident row in ListActiveIncidents: %w", err)
+		}
+		incidents = append(incidents, incident)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error list iteration in ListActiveIncidents: %w", err)
+	}
+	return incidents, nil
+}
+
+// GetLocationTileGeneration возвращает текущее поколение кэша ячеек
+func (r *IncidentRepository) GetLocationTileGeneration(ctx context.Context) (int64, error) {
+	generation, err := r.redisClient.Get(ctx, r.locationTileGenerationKey()).Int64()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to get location tile generation: %w", err)
+	}
+	return generation, nil
+}
+
+// GetLocationTileFromCache возвращает инциденты ячейки из кэша или nil, если ячейки нет в кэше
+func (r *IncidentRepository) GetLocationTileFromCache(ctx context.Context, generation int64, geohash string) ([]*models.Incident, error) {
+	val, err := r.redisClient.Get(ctx, r.locationTileCacheKey(generation, geohash)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get location tile from cache: %w", err)
+	}
+
+	var stored []storedIncident
+	if err := json.Unmarshal(val, &stored); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal location tile from cache: %w", err)
+	}
+	incidents := make([]*models.Incident, len(stored))
+	for i := range stored {
+		incidents[i] = stored[i].model()
+	}
+	return incidents, nil
+}
+
+// SetLocationTileCache сохраняет инциденты ячейки в Redis на время ttl
+func (r *IncidentRepository) SetLocationTileCache(ctx context.Context, generation int64, geohash string, incidents []*models.Incident, ttl time.Duration) error {
+	stored := make([]storedIncident, len(incidents))
+	for i, incident := range incidents {
+		stored[i] = newStoredIncident(incident)
+	}
+	val, err := json.Marshal(stored)
+	if err != nil {
+		return fmt.Errorf("failed to marshal location tile for cache: %w", err)
+	}
+	if err := r.redisClient.Set(ctx, r.locationTileCacheKey(generation, geohash), val, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to set location tile in cache: %w", err)
+	}
+	return nil
+}
+
+// InvalidateLocationTiles сбрасывает кэш всех ячеек, переходя к новому поколению.
+// Ключи прошлых поколений удаляются Redis по истечении TTL.
+func (r *IncidentRepository) InvalidateLocationTiles(ctx context.Context) error {
+	if err := r.redisClient.Incr(ctx, r.locationTileGenerationKey()).Err(); err != nil {
+		return fmt.Errorf("failed to invalidate location tiles: %w", err)
+	}
+	return nil
+}
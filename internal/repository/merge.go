@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/shenikar/geo_broadcasting_system/internal/models"
+	"github.com/shenikar/geo_broadcasting_system/internal/service"
+)
+
+// mergeRedirect - перенос записей одной таблицы, привязанных к дубликату, на канонический инцидент
+type mergeRedirect struct {
+	// records - что переносится, для сообщения об ошибке
+	records string
+	// query переносит записи дубликата $2 на канонический инцидент $1
+	query string
+}
+
+// mergeRedirects перечисляет переносы данных дубликата при слиянии, кроме совпадений проверок местоположения,
+// которые учитываются отдельно. Таблица, ссылающаяся на incidents, добавляет сюда свой перенос,
+// иначе ее записи остаются на деактивированном дубликате.
+var mergeRedirects = []mergeRedirect{
+	{
+		records: "attachments",
+		query:   `UPDATE incident_attachments SET incident_id = $1 WHERE incident_id = $2;`,
+	},
+	{
+		// Сохраненные просмотры дубликата прибавляются к каноническому; еще не перенесенные из Redis остаются за дубликатом
+		records: "views",
+		query: `
+			WITH moved AS (
+				DELETE FROM incident_views
+				WHERE incident_id = $2
+				RETURNING views
+			)
+			INSERT INTO incident_views (incident_id, views)
+			SELECT $1, views FROM moved
+			ON CONFLICT (incident_id) DO UPDATE
+			SET views = incident_views.views + EXCLUDED.views, updated_at = NOW();
+		`,
+	},
+	{
+		// Адрес, уже наблюдающий за каноническим инцидентом, остается у дубликата, чтобы не получать события дважды
+		records: "watchers",
+		query: `
+			UPDATE incident_watchers w SET incident_id = $1
+			WHERE w.incident_id = $2
+				AND NOT EXISTS (
+					SELECT 1 FROM incident_watchers c
+					WHERE c.incident_id = $1 AND c.url = w.url
+				);
+		`,
+	},
+}
+
+// MergeIncidents в одной транзакции поглощает дубликаты каноническим инцидентом:
+// зона канонического инцидента заменяется кругом, покрывающим объединение (ST_Union) зон всех участников,
+// совпадения проверок местоположения и прочие данные дубликатов (mergeRedirects) переносятся
+// на канонический инцидент, дубликаты деактивируются,
+// а слияние записывается в историю статусов и журнал incident_merges.
+// Если какого-либо инцидента нет, возвращается service.ErrIncidentNotFound,
+// если какой-либо уже завершен или отклонен - service.ErrStatusConflict.
+func (r *IncidentRepository) MergeIncidents(ctx context.Context, merge models.IncidentMerge) (*models.IncidentMergeResult, error) {
+	ids := append([]uuid.UUID{merge.CanonicalID}, merge.DuplicateIDs...)
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin merge incidents transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// Блокировка участников в порядке id исключает взаимоблокировку параллельных слияний
+	lockQuery := `
+		SELECT ` + incidentColumns + `
+		FROM incidents
+		WHERE id = ANY($1::uuid[])
+		ORDER BY id
+		FOR UPDATE;
+	`
+	rows, err := tx.Query(ctx, lockQuery, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock incidents for merge: %w", err)
+	}
+	locked := make(map[uuid.UUID]*models.Incident, len(ids))
+	for rows.Next() {
+		incident, err := scanIncident(rows)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan merged incident row: %w", err)
+		}
+		locked[incident.ID] = incident
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error list iteration in MergeIncidents: %w", err)
+	}
+	for _, id := range ids {
+		incident, ok := locked[id]
+		if !ok {
+			return nil, fmt.Errorf("incident with id %s: %w", id, service.ErrIncidentNotFound)
+		}
+		if !incident.Status.IsMergeable() {
+			return nil, fmt.Errorf("incident %s is in status %s and cannot be merged: %w", id, incident.Status, service.ErrStatusConflict)
+		}
+	}
+
+	// Центр - центроид объединения зон, радиус - расстояние до самой дальней границы зоны участника,
+	// поэтому новый круг целиком покрывает каждую из исходных зон
+	zoneQuery := `
+		WITH zone AS (
+			SELECT ST_Centroid(ST_Union(ST_Buffer(location, radius_meters)::geometry))::geography AS center
+			FROM incidents
+			WHERE id = ANY($2::uuid[])
+		)
+		UPDATE incidents SET
+			location = zone.center,
+			radius_meters = (
+				SELECT CEIL(MAX(ST_Distance(zone.center, m.location) + m.radius_meters))::int
+				FROM incidents m
+				WHERE m.id = ANY($2::uuid[])
+			),
+			updated_at = NOW()
+		FROM zone
+		WHERE incidents.id = $1
+		RETURNING ` + incidentColumns + `;
+	`
+	canonical, err := scanIncident(tx.QueryRow(ctx, zoneQuery, merge.CanonicalID, ids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to update canonical incident zone: %w", err)
+	}
+	if err := insertIncidentVersion(ctx, tx, canonical); err != nil {
+		return nil, err
+	}
+	result := &models.IncidentMergeResult{
+		Before:   locked[merge.CanonicalID],
+		Incident: canonical,
+		Merged:   make([]*models.Incident, 0, len(merge.DuplicateIDs)),
+	}
+
+	// Проверка, совпавшая и с дубликатом, и с каноническим инцидентом, уже учтена и не дублируется
+	redirectQuery := `
+		WITH moved AS (
+			DELETE FROM location_check_incidents
+			WHERE incident_id = $2
+			RETURNING location_check_id
+		)
+		INSERT INTO location_check_incidents (location_check_id, incident_id)
+		SELECT location_check_id, $1 FROM moved
+		ON CONFLICT DO NOTHING;
+	`
+	deactivateQuery := `
+		UPDATE incidents SET
+			status = $2,
+			resolution_reason = $3,
+			updated_at = NOW()
+		WHERE id = $1
+		RETURNING ` + incidentColumns + `;
+	`
+	historyQuery := `
+		INSERT INTO incident_status_history (incident_id, from_status, to_status, changed_by)
+		VALUES ($1, $2, $3, $4);
+	`
+	journalQuery := `
+		INSERT INTO incident_merges (canonical_id, merged_id, redirected_checks, merged_by)
+		VALUES ($1, $2, $3, $4);
+	`
+	reason := fmt.Sprintf("merged into %s", merge.CanonicalID)
+	for _, id := range merge.DuplicateIDs {
+		tag, err := tx.Exec(ctx, redirectQuery, merge.CanonicalID, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to redirect location checks of incident %s: %w", id, err)
+		}
+		result.RedirectedChecks += tag.RowsAffected()
+		for _, redirect := range mergeRedirects {
+			if _, err := tx.Exec(ctx, redirect.query, merge.CanonicalID, id); err != nil {
+				return nil, fmt.Errorf("failed to redirect %s of incident %s: %w", redirect.records, id, err)
+			}
+		}
+
+		deactivated, err := scanIncident(tx.QueryRow(ctx, deactivateQuery, id, models.StatusInactive, reason))
+		if err != nil {
+			return nil, fmt.Errorf("failed to deactivate merged incident %s: %w", id, err)
+		}
+		if err := insertIncidentVersion(ctx, tx, deactivated); err != nil {
+			return nil, err
+		}
+		if _, err := tx.Exec(ctx, historyQuery, id, locked[id].Status, models.StatusInactive, merge.MergedBy); err != nil {
+			return nil, fmt.Errorf("failed to save merged incident status history: %w", err)
+		}
+		if _, err := tx.Exec(ctx, journalQuery, merge.CanonicalID, id, tag.RowsAffected(), merge.MergedBy); err != nil {
+			return nil, fmt.Errorf("failed to save incident merge: %w", err)
+		}
+		result.Merged = append(result.Merged, deactivated)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit merge incidents: %w", err)
+	}
+	return result, nil
+}
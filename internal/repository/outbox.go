@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/shenikar/geo_broadcasting_system/internal/models"
+)
+
+// insertOutboxMessage записывает сообщение в outbox в рамках транзакции вызывающего
+func insertOutboxMessage(ctx context.Context, tx pgx.Tx, message *models.OutboxMessage) error {
+	query := `
+		INSERT INTO webhook_outbox (payload)
+		VALUES ($1) RETURNING id, created_at;
+	`
+	if err := tx.QueryRow(ctx, query, message.Payload).Scan(&message.ID, &message.CreatedAt); err != nil {
+		return fmt.Errorf("failed to save outbox message: %w", err)
+	}
+	return nil
+}
+
+// RelayOutbox блокирует до limit неотправленных сообщений outbox (старые первыми), передает их send
+// и помечает отправленными те, что send принял. Обработка останавливается на первой ошибке send,
+// непереданные сообщения остаются в outbox. Строки, заблокированные другим экземпляром, пропускаются.
+// Возвращает число отправленных сообщений.
+func (r *IncidentRepository) RelayOutbox(ctx context.Context, limit int, send func(*models.OutboxMessage) error) (int, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin outbox transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	query := `
+		SELECT id, payload, created_at
+		FROM webhook_outbox
+		WHERE sent_at IS NULL
+		ORDER BY id
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED;
+	`
+	rows, err := tx.Query(ctx, query, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to select outbox messages: %w", err)
+	}
+	messages := make([]*models.OutboxMessage, 0)
+	for rows.Next() {
+		message := &models.OutboxMessage{}
+		if err := rows.Scan(&message.ID, &message.Payload, &message.CreatedAt); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan outbox message: %w", err)
+		}
+		messages = append(messages, message)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("error list iteration in RelayOutbox: %w", err)
+	}
+
+	sent := make([]int64, 0, len(messages))
+	var sendErr error
+	for _, message := range messages {
+		if sendErr = send(message); sendErr != nil {
+			break
+		}
+		sent = append(sent, message.ID)
+	}
+
+	if len(sent) > 0 {
+		if _, err := tx.Exec(ctx, `UPDATE webhook_outbox SET sent_at = NOW() WHERE id = ANY($1::bigint[]);`, sent); err != nil {
+			return 0, fmt.Errorf("failed to mark outbox messages as sent: %w", err)
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return 0, fmt.Errorf("failed to commit outbox transaction: %w", err)
+		}
+	}
+	return len(sent), sendErr
+}
+
+// PurgeSentOutbox удаляет сообщения outbox, отправленные раньше before
+func (r *IncidentRepository) PurgeSentOutbox(ctx context.Context, before time.Time) (int64, error) {
+	cmdTag, err := r.db.Exec(ctx, `DELETE FROM webhook_outbox WHERE sent_at < $1;`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge sent outbox messages: %w", err)
+	}
+	return cmdTag.RowsAffected(), nil
+}
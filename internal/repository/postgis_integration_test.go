@@ -0,0 +1,62 @@
+//go:build integration
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/pgx/v5"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// postgisImage - образ PostGIS, совпадающий с docker-compose.yml
+const postgisImage = "postgis/postgis:15-3.3"
+
+// startPostGIS запускает контейнер PostgreSQL с PostGIS, применяет миграции из migrations/
+// и возвращает пул соединений к нему. Контейнер и пул закрываются по завершении теста.
+// Без доступного Docker тест пропускается.
+func startPostGIS(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+	testcontainers.SkipIfProviderIsNotHealthy(t)
+	ctx := context.Background()
+
+	container, err := postgres.Run(ctx, postgisImage,
+		postgres.WithDatabase("geo_test"),
+		postgres.WithUsername("geo"),
+		postgres.WithPassword("geo"),
+		testcontainers.WithWaitStrategy(
+			wait.ForLog("database system is ready to accept connections").
+				WithOccurrence(2).
+				WithStartupTimeout(time.Minute),
+		),
+	)
+	testcontainers.CleanupContainer(t, container)
+	require.NoError(t, err, "failed to start PostGIS container (is Docker running?)")
+
+	dsn, err := container.ConnectionString(ctx, "sslmode=disable")
+	require.NoError(t, err)
+
+	m, err := migrate.New("file://../../migrations", strings.Replace(dsn, "postgres://", "pgx5://", 1))
+	require.NoError(t, err)
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		require.NoError(t, err, "failed to apply migrations")
+	}
+	srcErr, dbErr := m.Close()
+	require.NoError(t, srcErr)
+	require.NoError(t, dbErr)
+
+	pool, err := pgxpool.New(ctx, dsn)
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+	return pool
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shenikar/geo_broadcasting_system/internal/models"
+)
+
+// ListPublicIncidents возвращает страницу активных инцидентов, помеченных для публикации: без silent
+// и без категорий из excludedCategories. Свежие изменения идут первыми.
+func (r *IncidentRepository) ListPublicIncidents(ctx context.Context, excludedCategories []string, page, pageSize int) ([]*models.Incident, error) {
+	offset := (page - 1) * pageSize
+
+	query := `
+		SELECT ` + incidentColumns + `
+		FROM incidents
+		WHERE public
+			AND status = 'active'
+			AND NOT silent
+			AND (category IS NULL OR category <> ALL(COALESCE($1::text[], '{}')))
+		ORDER BY updated_at DESC, id
+		LIMIT $2 OFFSET $3;
+	`
+	rows, err := r.db.Query(ctx, query, excludedCategories, pageSize, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list public incidents: %w", err)
+	}
+	defer rows.Close()
+	incidents := make([]*models.Incident, 0)
+	for rows.Next() {
+		incident, err := scanIncident(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan incident row in ListPublicIncidents: %w", err)
+		}
+		incidents = append(incidents, incident)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error list iteration in ListPublicIncidents: %w", err)
+	}
+	return incidents, nil
+}
+
+// ReservePublicFeedRequest учитывает запрос к публичной ленте с адреса clientIP в скользящем окне window.
+// Возвращает время до освобождения места в окне или 0, если запрос разрешен.
+func (r *IncidentRepository) ReservePublicFeedRequest(ctx context.Context, clientIP string, limit int, window time.Duration) (time.Duration, error) {
+	wait, err := r.reserveSlidingWindow(ctx, r.keys.Key("public_feed_quota", clientIP), limit, window)
+	if err != nil {
+		return 0, fmt.Errorf("failed to reserve public feed request: %w", err)
+	}
+	return wait, nil
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shenikar/geo_broadcasting_system/internal/models"
+)
+
+// CountLocationChecks возвращает число проверок местоположения в полуинтервале [from, to)
+func (r *IncidentRepository) CountLocationChecks(ctx context.Context, from, to time.Time) (int, error) {
+	var count int
+	err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM location_checks WHERE checked_at >= $1 AND checked_at < $2;`, from, to).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count location checks: %w", err)
+	}
+	return count, nil
+}
+
+// FindMissedLocationAlerts повторно оценивает безопасные проверки местоположения из полуинтервала [from, to)
+// с ID больше afterID и возвращает до limit проверок, которые попали в зону инцидента, активного в момент проверки.
+// Инцидент считается активным, если он был создан до проверки и в его последней версии до проверки статус active
+// (для инцидентов без версий - текущий статус). Зона инцидента берется текущей, как в FindActiveLocation,
+// без буфера погрешности GPS: он при проверке не сохраняется. IncidentIDs проверки - найденные инциденты.
+func (r *IncidentRepository) FindMissedLocationAlerts(ctx context.Context, from, to time.Time, afterID int64, limit int) ([]*models.LocationCheck, error) {
+	query := `
+		SELECT
+			lc.id,
+			lc.user_id,
+			ST_Y(lc.location::geometry) AS latitude,
+			ST_X(lc.location::geometry) AS longitude,
+			lc.is_dangerous,
+			lc.authenticated,
+			lc.checked_at,
+			matched.incident_ids
+		FROM location_checks lc
+		CROSS JOIN LATERAL (
+			SELECT array_agg(i.id ORDER BY i.id) AS incident_ids
+			FROM incidents i
+			WHERE i.created_at <= lc.checked_at
+				AND ST_DWithin(i.location::geography, lc.location::geography, i.radius_meters)
+				AND COALESCE((
+					SELECT v.snapshot->>'status'
+					FROM incident_versions v
+					WHERE v.incident_id = i.id AND v.created_at <= lc.checked_at
+					ORDER BY v.version DESC
+					LIMIT 1
+				), i.status) = 'active'
+		) matched
+		WHERE lc.checked_at >= $1 AND lc.checked_at < $2
+			AND lc.id > $3
+			AND NOT lc.is_dangerous
+			AND matched.incident_ids IS NOT NULL
+		ORDER BY lc.id
+		LIMIT $4;
+	`
+	rows, err := r.db.Query(ctx, query, from, to, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reevaluate location checks: %w", err)
+	}
+	defer rows.Close()
+
+	checks := make([]*models.LocationCheck, 0)
+	for rows.Next() {
+		check := &models.LocationCheck{}
+		err := rows.Scan(
+			&check.ID,
+			&check.UserID,
+			&check.Latitude,
+			&check.Longitude,
+			&check.IsDangerous,
+			&check.Authenticated,
+			&check.CheckedAt,
+			&check.IncidentIDs,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan reevaluated location check row: %w", err)
+		}
+		checks = append(checks, check)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error list iteration in FindMissedLocationAlerts: %w", err)
+	}
+	return checks, nil
+}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/shenikar/geo_broadcasting_system/internal/models"
+	"github.com/shenikar/geo_broadcasting_system/internal/service"
+)
+
+// templateColumns - список колонок шаблона в порядке, ожидаемом scanIncidentTemplate
+const templateColumns = `
+			id,
+			name,
+			description,
+			COALESCE(category, '') as category,
+			radius_meters,
+			COALESCE(source, '') as source,
+			created_at,
+			updated_at`
+
+// scanIncidentTemplate читает шаблон из строки результата
+func scanIncidentTemplate(row pgx.Row) (*models.IncidentTemplate, error) {
+	template := &models.IncidentTemplate{}
+	err := row.Scan(
+		&template.ID,
+		&template.Name,
+		&template.Description,
+		&template.Category,
+		&template.RadiusMeters,
+		&template.Source,
+		&template.CreatedAt,
+		&template.UpdatedAt,
+	)
+	return template, err
+}
+
+// templateWriteError переводит нарушение уникальности названия в service.ErrTemplateExists
+func templateWriteError(err error, name string) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation {
+		return fmt.Errorf("template %q: %w", name, service.ErrTemplateExists)
+	}
+	return fmt.Errorf("failed to save incident template: %w", err)
+}
+
+// CreateIncidentTemplate сохраняет шаблон инцидента. Название шаблона уникально.
+func (r *IncidentRepository) CreateIncidentTemplate(ctx context.Context, template *models.IncidentTemplate) error {
+	query := `
+		INSERT INTO incident_templates (name, description, category, radius_meters, source)
+		VALUES ($1, $2, NULLIF($3, ''), $4, NULLIF($5, ''))
+		RETURNING id, created_at, updated_at;
+	`
+	err := r.db.QueryRow(ctx, query,
+		template.Name,
+		template.Description,
+		template.Category,
+		template.RadiusMeters,
+		template.Source,
+	).Scan(&template.ID, &template.CreatedAt, &template.UpdatedAt)
+	if err != nil {
+		return templateWriteError(err, template.Name)
+	}
+	return nil
+}
+
+// GetIncidentTemplate возвращает шаблон по ID
+func (r *IncidentRepository) GetIncidentTemplate(ctx context.Context, id uuid.UUID) (*models.IncidentTemplate, error) {
+	query := `
+		SELECT ` + templateColumns + `
+		FROM incident_templates
+		WHERE id = $1;
+	`
+	template, err := scanIncidentTemplate(r.db.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("template with id %s: %w", id, service.ErrTemplateNotFound)
+		}
+		return nil, fmt.Errorf("failed to get incident template: %w", err)
+	}
+	return template, nil
+}
+
+// ListIncidentTemplates возвращает все шаблоны в порядке названий
+func (r *IncidentRepository) ListIncidentTemplates(ctx context.Context) ([]*models.IncidentTemplate, error) {
+	query := `
+		SELECT ` + templateColumns + `
+		FROM incident_templates
+		ORDER BY name;
+	`
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list incident templates: %w", err)
+	}
+	defer rows.Close()
+
+	templates := make([]*models.IncidentTemplate, 0)
+	for rows.Next() {
+		template, err := scanIncidentTemplate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan incident template row: %w", err)
+		}
+		templates = append(templates, template)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error list iteration in ListIncidentTemplates: %w", err)
+	}
+	return templates, nil
+}
+
+// UpdateIncidentTemplate обновляет шаблон целиком
+func (r *IncidentRepository) UpdateIncidentTemplate(ctx context.Context, template *models.IncidentTemplate) error {
+	query := `
+		UPDATE incident_templates SET
+			name = $1,
+			description = $2,
+			category = NULLIF($3, ''),
+			radius_meters = $4,
+			source = NULLIF($5, ''),
+			updated_at = NOW()
+		WHERE id = $6
+		RETURNING created_at, updated_at;
+	`
+	err := r.db.QueryRow(ctx, query,
+		template.Name,
+		template.Description,
+		template.Category,
+		template.RadiusMeters,
+		template.Source,
+		template.ID,
+	).Scan(&template.CreatedAt, &template.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("template with id %s: %w", template.ID, service.ErrTemplateNotFound)
+		}
+		return templateWriteError(err, template.Name)
+	}
+	return nil
+}
+
+// DeleteIncidentTemplate удаляет шаблон. Созданные по нему инциденты не затрагиваются.
+func (r *IncidentRepository) DeleteIncidentTemplate(ctx context.Context, id uuid.UUID) error {
+	cmdTag, err := r.db.Exec(ctx, `DELETE FROM incident_templates WHERE id = $1;`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete incident template: %w", err)
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("template with id %s: %w", id, service.ErrTemplateNotFound)
+	}
+	return nil
+}
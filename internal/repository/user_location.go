@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/shenikar/geo_broadcasting_system/internal/models"
+	"github.com/shenikar/geo_broadcasting_system/internal/service"
+)
+
+// pgUniqueViolation - код ошибки PostgreSQL при нарушении ограничения уникальности
+const pgUniqueViolation = "23505"
+
+// CreateUserLocation сохраняет место пользователя. Метка места уникальна в пределах пользователя.
+func (r *IncidentRepository) CreateUserLocation(ctx context.Context, location *models.UserLocation) error {
+	query := `
+		INSERT INTO user_locations (user_id, label, location)
+		VALUES ($1, $2, ST_SetSRID(ST_MakePoint($3, $4), 4326))
+		RETURNING id, created_at;
+	`
+	err := r.db.QueryRow(ctx, query,
+		location.UserID,
+		location.Label,
+		location.Longitude,
+		location.Latitude,
+	).Scan(&location.ID, &location.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation {
+			return fmt.Errorf("location %q of user %s: %w", location.Label, location.UserID, service.ErrUserLocationExists)
+		}
+		return fmt.Errorf("failed to create user location: %w", err)
+	}
+	return nil
+}
+
+// ListUserLocations возвращает сохраненные места пользователя в порядке добавления
+func (r *IncidentRepository) ListUserLocations(ctx context.Context, userID string) ([]*models.UserLocation, error) {
+	query := `
+		SELECT
+			id,
+			user_id,
+			label,
+			ST_Y(location::geometry) as latitude,
+			ST_X(location::geometry) as longitude,
+			created_at
+		FROM user_locations
+		WHERE user_id = $1
+		ORDER BY created_at;
+	`
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list user locations: %w", err)
+	}
+	defer rows.Close()
+
+	locations := make([]*models.UserLocation, 0)
+	for rows.Next() {
+		location := &models.UserLocation{}
+		err := rows.Scan(
+			&location.ID,
+			&location.UserID,
+			&location.Label,
+			&location.Latitude,
+			&location.Longitude,
+			&location.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user location row: %w", err)
+		}
+		locations = append(locations, location)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error list iteration in ListUserLocations: %w", err)
+	}
+	return locations, nil
+}
+
+// DeleteUserLocation удаляет сохраненное место пользователя
+func (r *IncidentRepository) DeleteUserLocation(ctx context.Context, userID string, id uuid.UUID) error {
+	query := `
+		DELETE FROM user_locations
+		WHERE id = $1 AND user_id = $2;
+	`
+	cmdTag, err := r.db.Exec(ctx, query, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete user location: %w", err)
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("location %s of user %s: %w", id, userID, service.ErrUserLocationNotFound)
+	}
+	return nil
+}
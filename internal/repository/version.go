@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/shenikar/geo_broadcasting_system/internal/models"
+	"github.com/shenikar/geo_broadcasting_system/internal/service"
+)
+
+// insertIncidentVersion записывает снимок инцидента следующей по порядку версией в транзакции изменения.
+// Изменяющий запрос уже заблокировал строку инцидента, поэтому номера версий не конфликтуют.
+func insertIncidentVersion(ctx context.Context, tx pgx.Tx, incident *models.Incident) error {
+	snapshot, err := json.Marshal(newStoredIncident(incident))
+	if err != nil {
+		return fmt.Errorf("failed to marshal incident snapshot: %w", err)
+	}
+	query := `
+		INSERT INTO incident_versions (incident_id, version, snapshot)
+		VALUES ($1, COALESCE((SELECT MAX(version) FROM incident_versions WHERE incident_id = $1), 0) + 1, $2);
+	`
+	if _, err := tx.Exec(ctx, query, incident.ID, snapshot); err != nil {
+		return fmt.Errorf("failed to save incident version: %w", err)
+	}
+	return nil
+}
+
+// scanIncidentVersion читает версию инцидента из строки (version, snapshot, created_at)
+func scanIncidentVersion(row pgx.Row, incidentID uuid.UUID) (*models.IncidentVersion, error) {
+	version := &models.IncidentVersion{IncidentID: incidentID}
+	var snapshot []byte
+	if err := row.Scan(&version.Version, &snapshot, &version.CreatedAt); err != nil {
+		return nil, err
+	}
+	var stored storedIncident
+	if err := json.Unmarshal(snapshot, &stored); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot of version %d: %w", version.Version, err)
+	}
+	version.Incident = stored.model()
+	return version, nil
+}
+
+// ListIncidentVersions возвращает все версии инцидента, начиная с последней
+func (r *IncidentRepository) ListIncidentVersions(ctx context.Context, incidentID uuid.UUID) ([]*models.IncidentVersion, error) {
+	query := `
+		SELECT version, snapshot, created_at
+		FROM incident_versions
+		WHERE incident_id = $1
+		ORDER BY version DESC;
+	`
+	rows, err := r.db.Query(ctx, query, incidentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list incident versions: %w", err)
+	}
+	defer rows.Close()
+
+	versions := make([]*models.IncidentVersion, 0)
+	for rows.Next() {
+		version, err := scanIncidentVersion(rows, incidentID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan incident version row: %w", err)
+		}
+		versions = append(versions, version)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error list iteration in ListIncidentVersions: %w", err)
+	}
+	return versions, nil
+}
+
+// GetIncidentVersion возвращает одну версию инцидента
+func (r *IncidentRepository) GetIncidentVersion(ctx context.Context, incidentID uuid.UUID, version int) (*models.IncidentVersion, error) {
+	query := `
+		SELECT version, snapshot, created_at
+		FROM incident_versions
+		WHERE incident_id = $1 AND version = $2;
+	`
+	result, err := scanIncidentVersion(r.db.QueryRow(ctx, query, incidentID, version), incidentID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("incident %s version %d: %w", incidentID, version, service.ErrVersionNotFound)
+		}
+		return nil, fmt.Errorf("failed to get incident version: %w", err)
+	}
+	return result, nil
+}
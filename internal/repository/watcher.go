@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/shenikar/geo_broadcasting_system/internal/models"
+	"github.com/shenikar/geo_broadcasting_system/internal/service"
+)
+
+// watcherColumns - список колонок наблюдателя в порядке, ожидаемом scanWatchers
+const watcherColumns = `id, incident_id, url, secret, created_by, created_at`
+
+// scanWatchers читает всех наблюдателей из результата запроса
+func scanWatchers(rows pgx.Rows) ([]*models.IncidentWatcher, error) {
+	defer rows.Close()
+	watchers := make([]*models.IncidentWatcher, 0)
+	for rows.Next() {
+		watcher := &models.IncidentWatcher{}
+		err := rows.Scan(
+			&watcher.ID,
+			&watcher.IncidentID,
+			&watcher.URL,
+			&watcher.Secret,
+			&watcher.CreatedBy,
+			&watcher.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan incident watcher row: %w", err)
+		}
+		watchers = append(watchers, watcher)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error list iteration in incident watchers: %w", err)
+	}
+	return watchers, nil
+}
+
+// CreateIncidentWatcher добавляет наблюдателя инцидента. Адрес уникален в пределах инцидента.
+func (r *IncidentRepository) CreateIncidentWatcher(ctx context.Context, watcher *models.IncidentWatcher) error {
+	query := `
+		INSERT INTO incident_watchers (incident_id, url, secret, created_by)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at;
+	`
+	err := r.db.QueryRow(ctx, query, watcher.IncidentID, watcher.URL, watcher.Secret, watcher.CreatedBy).
+		Scan(&watcher.ID, &watcher.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation {
+			return fmt.Errorf("watcher %q of incident %s: %w", watcher.URL, watcher.IncidentID, service.ErrWatcherExists)
+		}
+		return fmt.Errorf("failed to create incident watcher: %w", err)
+	}
+	return nil
+}
+
+// ListIncidentWatchers возвращает наблюдателей инцидента в порядке добавления
+func (r *IncidentRepository) ListIncidentWatchers(ctx context.Context, incidentID uuid.UUID) ([]*models.IncidentWatcher, error) {
+	query := `
+		SELECT ` + watcherColumns + `
+		FROM incident_watchers
+		WHERE incident_id = $1
+		ORDER BY created_at;
+	`
+	rows, err := r.db.Query(ctx, query, incidentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list incident watchers: %w", err)
+	}
+	return scanWatchers(rows)
+}
+
+// ListWatchersByIncidents возвращает наблюдателей всех перечисленных инцидентов (для доставки вебхуков)
+func (r *IncidentRepository) ListWatchersByIncidents(ctx context.Context, incidentIDs []uuid.UUID) ([]*models.IncidentWatcher, error) {
+	query := `
+		SELECT ` + watcherColumns + `
+		FROM incident_watchers
+		WHERE incident_id = ANY($1::uuid[])
+		ORDER BY created_at;
+	`
+	rows, err := r.db.Query(ctx, query, incidentIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list watchers by incidents: %w", err)
+	}
+	return scanWatchers(rows)
+}
+
+// DeleteIncidentWatcher удаляет наблюдателя инцидента
+func (r *IncidentRepository) DeleteIncidentWatcher(ctx context.Context, incidentID, id uuid.UUID) error {
+	query := `
+		DELETE FROM incident_watchers
+		WHERE id = $1 AND incident_id = $2;
+	`
+	cmdTag, err := r.db.Exec(ctx, query, id, incidentID)
+	if err != nil {
+		return fmt.Errorf("failed to delete incident watcher: %w", err)
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("watcher %s of incident %s: %w", id, incidentID, service.ErrWatcherNotFound)
+	}
+	return nil
+}
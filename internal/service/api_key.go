@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/shenikar/geo_broadcasting_system/internal/models"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// generatedAPIKeyBytes - количество случайных байт в ключе, сгенерированном сервисом
+	generatedAPIKeyBytes = 32
+	// apiKeyUsageBucket - шаг скользящего окна учета запросов по API-ключам
+	apiKeyUsageBucket = time.Minute
+)
+
+// apiKeyCache хранит набор хэшей ключей, выпущенных через API, чтобы не обращаться к Redis на каждый запрос
+type apiKeyCache struct {
+	mu       sync.Mutex
+	hashes   map[string]struct{}
+	loadedAt time.Time
+}
+
+// invalidate сбрасывает кэш, следующий запрос перечитает ключи из хранилища
+func (c *apiKeyCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.hashes = nil
+}
+
+// hashAPIKey возвращает хэш API-ключа, под которым он хранится в Redis. Сами ключи не сохраняются.
+func hashAPIKey(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:])
+}
+
+// AddAPIKey выпускает API-ключ, действующий без перезапуска сервиса.
+// Если ключ не передан, генерируется случайный. Возвращает добавленный ключ.
+func (s *incidentService) AddAPIKey(ctx context.Context, apiKey string) (string, error) {
+	if apiKey == "" {
+		buf := make([]byte, generatedAPIKeyBytes)
+		if _, err := rand.Read(buf); err != nil {
+			return "", fmt.Errorf("service: could not generate api key: %w", err)
+		}
+		apiKey = hex.EncodeToString(buf)
+	}
+
+	hash := hashAPIKey(apiKey)
+	log := s.logger.WithFields(logrus.Fields{
+		"service": "incident",
+		"method":  "AddAPIKey",
+		"api_key": "key_" + hash[:8],
+	})
+
+	if err := s.repo.AddAPIKey(ctx, hash); err != nil {
+		log.WithError(err).Error("Failed to add api key in repository")
+		return "", fmt.Errorf("service: could not add api key: %w", err)
+	}
+	s.apiKeys.invalidate()
+
+	log.Info("API key added")
+	return apiKey, nil
+}
+
+// RevokeAPIKey отзывает API-ключ, выпущенный через API. На этом инстансе ключ перестает
+// приниматься сразу, на остальных - после истечения API_KEY_CACHE_TTL.
+func (s *incidentService) RevokeAPIKey(ctx context.Context, apiKey string) error {
+	hash := hashAPIKey(apiKey)
+	log := s.logger.WithFields(logrus.Fields{
+		"service": "incident",
+		"method":  "RevokeAPIKey",
+		"api_key": "key_" + hash[:8],
+	})
+
+	if err := s.repo.RemoveAPIKey(ctx, hash); err != nil {
+		log.WithError(err).Warn("Failed to revoke api key in repository")
+		return fmt.Errorf("service: could not revoke api key: %w", err)
+	}
+	s.apiKeys.invalidate()
+
+	log.Info("API key revoked")
+	return nil
+}
+
+// IsRuntimeAPIKey проверяет, выпущен ли ключ через API. Набор ключей кэшируется в памяти
+// на API_KEY_CACHE_TTL; если Redis недоступен, используется последний загруженный набор.
+func (s *incidentService) IsRuntimeAPIKey(ctx context.Context, apiKey string) (bool, error) {
+	s.apiKeys.mu.Lock()
+	defer s.apiKeys.mu.Unlock()
+
+	if s.apiKeys.hashes == nil || time.Since(s.apiKeys.loadedAt) >= s.cfg.APIKeyCacheTTL {
+		hashes, err := s.repo.ListAPIKeys(ctx)
+		if err != nil {
+			if s.apiKeys.hashes == nil {
+				return false, fmt.Errorf("service: could not load api keys: %w", err)
+			}
+			s.logger.WithFields(logrus.Fields{
+				"service": "incident",
+				"method":  "IsRuntimeAPIKey",
+			}).WithError(err).Warn("Failed to reload api keys, using cached set")
+			// Следующая попытка - через TTL, чтобы не упираться в недоступный Redis на каждом запросе
+			s.apiKeys.loadedAt = time.Now()
+		} else {
+			s.apiKeys.hashes = make(map[string]struct{}, len(hashes))
+			for _, hash := range hashes {
+				s.apiKeys.hashes[hash] = struct{}{}
+			}
+			s.apiKeys.loadedAt = time.Now()
+		}
+	}
+
+	_, ok := s.apiKeys.hashes[hashAPIKey(apiKey)]
+	return ok, nil
+}
+
+// RecordAPIKeyUsage учитывает запрос с API-ключом с меткой label в текущем интервале окна учета
+func (s *incidentService) RecordAPIKeyUsage(ctx context.Context, label string) error {
+	bucket := time.Now().Truncate(apiKeyUsageBucket)
+	// Интервал хранится, пока может попасть в окно
+	ttl := s.cfg.APIKeyUsageWindow + apiKeyUsageBucket
+	if err := s.repo.IncrementAPIKeyUsage(ctx, label, bucket, ttl); err != nil {
+		return fmt.Errorf("service: could not record api key usage: %w", err)
+	}
+	return nil
+}
+
+// GetAPIKeyUsage возвращает количество запросов по каждому API-ключу за окно API_KEY_USAGE_WINDOW,
+// начиная с самых активных ключей
+func (s *incidentService) GetAPIKeyUsage(ctx context.Context) ([]*models.APIKeyUsage, error) {
+	now := time.Now().Truncate(apiKeyUsageBucket)
+	count := int(s.cfg.APIKeyUsageWindow / apiKeyUsageBucket)
+	buckets := make([]time.Time, count)
+	for i := range buckets {
+		buckets[i] = now.Add(-time.Duration(i) * apiKeyUsageBucket)
+	}
+
+	counts, err := s.repo.CountAPIKeyUsage(ctx, buckets)
+	if err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"service": "incident",
+			"method":  "GetAPIKeyUsage",
+		}).WithError(err).Error("Failed to get api key usage from repository")
+		return nil, fmt.Errorf("service: could not get api key usage: %w", err)
+	}
+
+	usage := make([]*models.APIKeyUsage, 0, len(counts))
+	for label, requests := range counts {
+		usage = append(usage, &models.APIKeyUsage{Label: label, Requests: requests})
+	}
+	sort.Slice(usage, func(i, j int) bool {
+		if usage[i].Requests != usage[j].Requests {
+			return usage[i].Requests > usage[j].Requests
+		}
+		return usage[i].Label < usage[j].Label
+	})
+	return usage, nil
+}
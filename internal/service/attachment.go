@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shenikar/geo_broadcasting_system/internal/config"
+	"github.com/shenikar/geo_broadcasting_system/internal/models"
+	"github.com/shenikar/geo_broadcasting_system/pkg/objectstore"
+	"github.com/sirupsen/logrus"
+)
+
+// attachmentExtPattern - расширение имени файла, переносимое в ключ объекта
+var attachmentExtPattern = regexp.MustCompile(`^\.[a-z0-9]{1,10}$`)
+
+// AttachmentStorage подписывает URL объектного хранилища вложений
+type AttachmentStorage interface {
+	PresignPut(key, contentType string, ttl time.Duration) (string, error)
+	PresignGet(key string, ttl time.Duration) (string, error)
+}
+
+// newAttachmentStorage создает подписчик URL S3-совместимого хранилища; nil, если S3_BUCKET не задан
+func newAttachmentStorage(cfg *config.Config, logger *logrus.Logger) AttachmentStorage {
+	if cfg.S3Bucket == "" {
+		return nil
+	}
+	presigner, err := objectstore.NewPresigner(objectstore.Config{
+		Endpoint:        cfg.S3Endpoint,
+		Region:          cfg.S3Region,
+		Bucket:          cfg.S3Bucket,
+		AccessKeyID:     cfg.S3AccessKeyID,
+		SecretAccessKey: cfg.S3SecretAccessKey,
+		PathStyle:       cfg.S3PathStyle,
+	})
+	if err != nil {
+		logger.WithError(err).Error("Invalid object storage configuration, incident attachments are disabled")
+		return nil
+	}
+	return presigner
+}
+
+// CreateAttachmentUploadURL сохраняет ссылку на новое вложение инцидента и возвращает presigned URL,
+// по которому клиент загружает файл методом PUT с заголовком Content-Type, равным contentType.
+// Ключ объекта строится сервисом (incidents/<id инцидента>/<id объекта>.<расширение>), поэтому имя файла
+// от клиента в путь хранилища не попадает.
+func (s *incidentService) CreateAttachmentUploadURL(ctx context.Context, incidentID uuid.UUID, filename, contentType, createdBy string) (*models.PresignedAttachment, error) {
+	log := s.logger.WithFields(logrus.Fields{
+		"service":      "incident",
+		"method":       "CreateAttachmentUploadURL",
+		"incident_id":  incidentID,
+		"content_type": contentType,
+	})
+
+	if s.attachments == nil {
+		return nil, ErrAttachmentsDisabled
+	}
+	filename = path.Base(strings.ReplaceAll(strings.TrimSpace(filename), `\`, "/"))
+	if filename == "" || filename == "." || filename == "/" {
+		return nil, fmt.Errorf("service: attachment filename is empty: %w", ErrInvalidAttachment)
+	}
+	if _, _, err := mime.ParseMediaType(contentType); err != nil {
+		return nil, fmt.Errorf("service: attachment content type %q: %w", contentType, ErrInvalidAttachment)
+	}
+	if _, err := s.repo.GetByID(ctx, incidentID); err != nil {
+		log.WithError(err).Warn("Attempted to attach a file to a non-existent incident")
+		return nil, fmt.Errorf("service: could not get incident: %w", err)
+	}
+
+	key := fmt.Sprintf("incidents/%s/%s", incidentID, uuid.New())
+	if ext := strings.ToLower(path.Ext(filename)); attachmentExtPattern.MatchString(ext) {
+		key += ext
+	}
+	attachment := &models.IncidentAttachment{
+		IncidentID:  incidentID,
+		ObjectKey:   key,
+		Filename:    filename,
+		ContentType: contentType,
+		CreatedBy:   createdBy,
+	}
+	uploadURL, err := s.attachments.PresignPut(key, contentType, s.cfg.S3PresignTTL)
+	if err != nil {
+		log.WithError(err).Error("Failed to presign attachment upload URL")
+		return nil, fmt.Errorf("service: could not presign upload url: %w", err)
+	}
+	if err := s.repo.CreateIncidentAttachment(ctx, attachment); err != nil {
+		log.WithError(err).Error("Failed to save incident attachment")
+		return nil, fmt.Errorf("service: could not save attachment: %w", err)
+	}
+
+	log.WithField("object_key", key).Info("Attachment upload URL issued")
+	return &models.PresignedAttachment{
+		Attachment: attachment,
+		URL:        uploadURL,
+		ExpiresAt:  time.Now().UTC().Add(s.cfg.S3PresignTTL),
+	}, nil
+}
+
+// ListIncidentAttachments возвращает вложения инцидента с presigned URL для скачивания.
+// Вложение, файл которого клиент так и не загрузил, тоже попадает в список: хранилище ответит на его URL 404.
+func (s *incidentService) ListIncidentAttachments(ctx context.Context, incidentID uuid.UUID) ([]*models.PresignedAttachment, error) {
+	log := s.logger.WithFields(logrus.Fields{
+		"service":     "incident",
+		"method":      "ListIncidentAttachments",
+		"incident_id": incidentID,
+	})
+
+	if s.attachments == nil {
+		return nil, ErrAttachmentsDisabled
+	}
+	if _, err := s.repo.GetByID(ctx, incidentID); err != nil {
+		return nil, fmt.Errorf("service: could not get incident: %w", err)
+	}
+	attachments, err := s.repo.ListIncidentAttachments(ctx, incidentID)
+	if err != nil {
+		log.WithError(err).Error("Failed to list incident attachments from repository")
+		return nil, fmt.Errorf("service: could not list attachments: %w", err)
+	}
+
+	expiresAt := time.Now().UTC().Add(s.cfg.S3PresignTTL)
+	presigned := make([]*models.PresignedAttachment, len(attachments))
+	for i, attachment := range attachments {
+		downloadURL, err := s.attachments.PresignGet(attachment.ObjectKey, s.cfg.S3PresignTTL)
+		if err != nil {
+			log.WithError(err).Error("Failed to presign attachment download URL")
+			return nil, fmt.Errorf("service: could not presign download url: %w", err)
+		}
+		presigned[i] = &models.PresignedAttachment{Attachment: attachment, URL: downloadURL, ExpiresAt: expiresAt}
+	}
+	return presigned, nil
+}
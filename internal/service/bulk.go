@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shenikar/geo_broadcasting_system/internal/models"
+	"github.com/shenikar/geo_broadcasting_system/internal/webhook"
+	"github.com/sirupsen/logrus"
+)
+
+// BulkDeactivateIncidents деактивирует набор инцидентов в одной транзакции,
+// инвалидирует их кэш и публикует вебхук incident_deactivated для каждого.
+// Возвращает количество фактически деактивированных инцидентов.
+func (s *incidentService) BulkDeactivateIncidents(ctx context.Context, target models.BulkDeactivation) (int, error) {
+	log := s.logger.WithFields(logrus.Fields{
+		"service":   "incident",
+		"method":    "BulkDeactivateIncidents",
+		"ids_count": len(target.IDs),
+		"status":    target.Status,
+	})
+	log.Info("Attempting to bulk deactivate incidents")
+
+	if target.IsFilter() && target.Status == "" && target.OlderThan == nil {
+		log.Warn("Refusing bulk deactivation without ids or filter")
+		return 0, ErrEmptyBulkTarget
+	}
+
+	deactivated, err := s.repo.DeactivateIncidents(ctx, target)
+	if err != nil {
+		log.WithError(err).Error("Failed to bulk deactivate incidents in repository")
+		return 0, fmt.Errorf("service: could not bulk deactivate incidents: %w", err)
+	}
+
+	deactivatedIDs := make([]uuid.UUID, len(deactivated))
+	for i, incident := range deactivated {
+		deactivatedIDs[i] = incident.ID
+	}
+	invalidateIncidentCache(ctx, s.repo, log, deactivatedIDs...)
+
+	now := time.Now()
+	for _, incident := range deactivated {
+
+		event := webhook.WebhookEvent{
+			SchemaVersion: webhook.SchemaVersion,
+			Type:          webhook.EventTypeIncidentDeactivated,
+			Latitude:      incident.Latitude,
+			Longitude:     incident.Longitude,
+			Timestamp:     now,
+			Incidents:     []*models.Incident{incident},
+		}
+		if err := s.webhookPublisher.Publish(ctx, event); err != nil {
+			log.WithError(err).WithField("incident_id", incident.ID).Error("Failed to publish incident deactivated webhook")
+		}
+	}
+
+	if len(deactivated) > 0 {
+		s.invalidateLocationTiles(ctx, log)
+	}
+
+	log.WithField("count", len(deactivated)).Info("Incidents bulk deactivated successfully")
+	return len(deactivated), nil
+}
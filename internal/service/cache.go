@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// cacheInvalidationAttempts - сколько раз пытаться удалить запись кэша инцидента после изменения
+	cacheInvalidationAttempts = 3
+	// cacheInvalidationRetryDelay - пауза перед повтором удаления, растет линейно с номером попытки
+	cacheInvalidationRetryDelay = 50 * time.Millisecond
+	// staleIncidentCacheTTL - срок жизни, выставляемый записи кэша, которую не удалось удалить
+	staleIncidentCacheTTL = 5 * time.Second
+)
+
+// invalidateIncidentCache удаляет инциденты из кэша после успешной записи в БД; записи нескольких инцидентов
+// удаляются одним запросом. Неудачное удаление повторяется; если все попытки не удались, записям выставляется
+// короткий срок жизни staleIncidentCacheTTL, чтобы устаревшие данные истекли сами. Отмена ctx прерывает
+// ожидание повтора. Ошибки не прерывают операцию: они логируются и учитываются в метрике.
+func invalidateIncidentCache(ctx context.Context, repo IncidentRepository, log *logrus.Entry, ids ...uuid.UUID) {
+	if len(ids) == 0 {
+		return
+	}
+	if len(ids) == 1 {
+		log = log.WithField("incident_id", ids[0])
+	} else {
+		log = log.WithField("incidents_count", len(ids))
+	}
+
+	var err error
+	for attempt := 1; attempt <= cacheInvalidationAttempts; attempt++ {
+		if err = repo.InvalidateIncidentCache(ctx, ids...); err == nil {
+			return
+		}
+		log.WithError(err).WithField("attempt", attempt).Warn("Failed to invalidate incident cache")
+		if attempt == cacheInvalidationAttempts {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			cacheInvalidationFailures.WithLabelValues("failed").Inc()
+			log.WithError(ctx.Err()).Error("Incident cache invalidation canceled, stale entry may be served until it expires")
+			return
+		case <-time.After(time.Duration(attempt) * cacheInvalidationRetryDelay):
+		}
+	}
+
+	if expireErr := repo.ExpireIncidentCache(ctx, staleIncidentCacheTTL, ids...); expireErr != nil {
+		cacheInvalidationFailures.WithLabelValues("failed").Inc()
+		log.WithError(err).WithField("expire_error", expireErr.Error()).
+			Error("Failed to invalidate incident cache, stale entry may be served until it expires")
+		return
+	}
+	cacheInvalidationFailures.WithLabelValues("expired").Inc()
+	log.WithError(err).WithField("ttl", staleIncidentCacheTTL).
+		Warn("Failed to invalidate incident cache, shortened its TTL instead")
+}
+
+// InvalidateCaches сбрасывает все кэши после изменения данных в обход API (например, исправления прямо в БД):
+// ключи кэша в Redis, поколение ячеек проверки местоположения, индекс активных инцидентов и набор
+// выпущенных API-ключей. Индекс и набор ключей других инстансов обновятся по своим интервалам.
+// Возвращает число удаленных ключей Redis.
+func (s *incidentService) InvalidateCaches(ctx context.Context) (int64, error) {
+	log := s.logger.WithFields(logrus.Fields{
+		"service": "incident",
+		"method":  "InvalidateCaches",
+	})
+	log.Info("Invalidating caches")
+
+	removed, err := s.repo.FlushCaches(ctx)
+	if err != nil {
+		log.WithError(err).WithField("removed", removed).Error("Failed to flush caches in repository")
+		return removed, fmt.Errorf("service: could not flush caches: %w", err)
+	}
+	s.invalidateLocationTiles(ctx, log)
+	s.apiKeys.invalidate()
+
+	log.WithField("removed", removed).Info("Caches invalidated")
+	return removed, nil
+}
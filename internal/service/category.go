@@ -0,0 +1,45 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shenikar/geo_broadcasting_system/internal/models"
+)
+
+// applyCategoryRules приводит категорию к нижнему регистру, подставляет радиус по умолчанию категории,
+// если радиус не задан, и проверяет радиус по правилам INCIDENT_CATEGORY_RULES.
+// Категории без правил принимаются с любым радиусом; без радиуса - только при наличии радиуса по умолчанию.
+func (s *incidentService) applyCategoryRules(incident *models.Incident) error {
+	incident.Category = strings.ToLower(strings.TrimSpace(incident.Category))
+	rule, hasRule := s.cfg.IncidentCategoryRules[incident.Category]
+
+	if incident.RadiusMeters == 0 && hasRule {
+		incident.RadiusMeters = rule.DefaultRadiusMeters
+	}
+	if incident.RadiusMeters <= 0 && incident.Category != "" {
+		return fmt.Errorf("service: radius_meters is required, category %q has no default radius: %w", incident.Category, ErrInvalidIncident)
+	}
+	if !hasRule {
+		return nil
+	}
+
+	if (rule.MinRadiusMeters > 0 && incident.RadiusMeters < rule.MinRadiusMeters) ||
+		(rule.MaxRadiusMeters > 0 && incident.RadiusMeters > rule.MaxRadiusMeters) {
+		return fmt.Errorf("service: radius %d m is outside the allowed range %s for category %q: %w",
+			incident.RadiusMeters, radiusRange(rule.MinRadiusMeters, rule.MaxRadiusMeters), incident.Category, ErrInvalidIncident)
+	}
+	return nil
+}
+
+// radiusRange форматирует диапазон радиуса для сообщения об ошибке
+func radiusRange(minRadius, maxRadius int) string {
+	switch {
+	case maxRadius == 0:
+		return fmt.Sprintf(">= %d m", minRadius)
+	case minRadius == 0:
+		return fmt.Sprintf("<= %d m", maxRadius)
+	default:
+		return fmt.Sprintf("%d-%d m", minRadius, maxRadius)
+	}
+}
@@ -0,0 +1,29 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// userCheckQuotaWindow - окно квоты проверок местоположения (USER_CHECK_QUOTA_PER_MINUTE)
+const userCheckQuotaWindow = time.Minute
+
+// reserveUserCheck учитывает проверку в квоте пользователя и возвращает *RateLimitError, если квота исчерпана.
+// Квота - защита от зациклившихся клиентов, а не часть проверки: при недоступности Redis проверка пропускается.
+func (s *incidentService) reserveUserCheck(ctx context.Context, userID string, log *logrus.Entry) error {
+	if s.cfg.UserCheckQuotaPerMinute <= 0 {
+		return nil
+	}
+	wait, err := s.repo.ReserveUserCheck(ctx, userID, s.cfg.UserCheckQuotaPerMinute, userCheckQuotaWindow)
+	if err != nil {
+		log.WithError(err).Warn("Failed to check user location check quota, allowing the check")
+		return nil
+	}
+	if wait > 0 {
+		log.WithField("retry_after", wait).Warn("User location check quota exceeded")
+		return &RateLimitError{Err: ErrCheckQuotaExceeded, RetryAfter: wait}
+	}
+	return nil
+}
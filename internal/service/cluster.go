@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/shenikar/geo_broadcasting_system/internal/models"
+	"github.com/shenikar/geo_broadcasting_system/pkg/geo"
+	"github.com/sirupsen/logrus"
+)
+
+// Допустимые уровни масштаба карты (как у тайлов веб-карт)
+const (
+	MinClusterZoom = 0
+	MaxClusterZoom = 20
+)
+
+// Допустимый размер ячейки сетки очагов в метрах
+const (
+	MinHotspotCellMeters = 100
+	MaxHotspotCellMeters = 100000
+)
+
+// metersPerDegree - длина градуса широты (и градуса долготы на экваторе) в метрах
+const metersPerDegree = 111320.0
+
+// clusterCellsPerTile - число ячеек сетки кластеризации по ширине одного тайла карты
+const clusterCellsPerTile = 4
+
+// clusterGridSize возвращает размер ячейки сетки в градусах для уровня масштаба:
+// тайл на уровне zoom занимает 360/2^zoom градусов долготы
+func clusterGridSize(zoom int) float64 {
+	return 360 / math.Pow(2, float64(zoom)) / clusterCellsPerTile
+}
+
+// ClusterIncidents группирует активные инциденты в видимой области карты по сетке,
+// размер ячейки которой уменьшается с ростом масштаба
+func (s *incidentService) ClusterIncidents(ctx context.Context, bbox geo.BBox, zoom int) ([]*models.IncidentCluster, error) {
+	gridSize := clusterGridSize(zoom)
+	log := s.logger.WithFields(logrus.Fields{
+		"service":   "incident",
+		"method":    "ClusterIncidents",
+		"zoom":      zoom,
+		"grid_size": gridSize,
+	})
+	log.Info("Clustering incidents")
+
+	clusters, err := s.repo.ClusterActiveIncidents(ctx, bbox, gridSize)
+	if err != nil {
+		log.WithError(err).Error("Failed to cluster incidents in repository")
+		return nil, fmt.Errorf("service: could not cluster incidents: %w", err)
+	}
+
+	log.WithField("count", len(clusters)).Info("Incidents clustered successfully")
+	return clusters, nil
+}
+
+// hotspotGridSize переводит размер ячейки в метрах в размеры по широте и долготе в градусах.
+// Градус долготы сокращается к полюсам, поэтому его размер берется на средней широте области:
+// ячейки остаются близкими к квадратным со стороной cellMeters.
+func hotspotGridSize(bbox geo.BBox, cellMeters float64) (latSize, lonSize float64) {
+	latSize = cellMeters / metersPerDegree
+	midLat := (bbox.MinLat + bbox.MaxLat) / 2
+	// Не даем ячейке по долготе расти неограниченно у полюсов
+	cosLat := math.Max(math.Cos(midLat*math.Pi/180), 0.01)
+	return latSize, math.Min(latSize/cosLat, 360)
+}
+
+// FindHotspots возвращает ячейки сетки размером cellMeters в области bbox, в которые попало не меньше
+// minCount активных инцидентов: скопление мелких инцидентов может указывать на более крупную ситуацию.
+// minCount меньше 1 заменяется на HOTSPOT_MIN_INCIDENTS.
+func (s *incidentService) FindHotspots(ctx context.Context, bbox geo.BBox, cellMeters float64, minCount int) ([]*models.IncidentHotspot, error) {
+	if minCount < 1 {
+		minCount = s.cfg.HotspotMinIncidents
+	}
+	latSize, lonSize := hotspotGridSize(bbox, cellMeters)
+	log := s.logger.WithFields(logrus.Fields{
+		"service":     "incident",
+		"method":      "FindHotspots",
+		"cell_meters": cellMeters,
+		"min_count":   minCount,
+	})
+
+	hotspots, err := s.repo.FindHotspots(ctx, bbox, latSize, lonSize, minCount)
+	if err != nil {
+		log.WithError(err).Error("Failed to find hotspots in repository")
+		return nil, fmt.Errorf("service: could not find incident hotspots: %w", err)
+	}
+	for _, hotspot := range hotspots {
+		hotspot.Bounds = geo.BBox{
+			MinLat: hotspot.Latitude - latSize/2,
+			MinLon: geo.NormalizeLon(hotspot.Longitude - lonSize/2),
+			MaxLat: hotspot.Latitude + latSize/2,
+			MaxLon: geo.NormalizeLon(hotspot.Longitude + lonSize/2),
+		}
+	}
+
+	log.WithField("count", len(hotspots)).Info("Incident hotspots found")
+	return hotspots, nil
+}
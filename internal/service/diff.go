@@ -0,0 +1,35 @@
+package service
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/shenikar/geo_broadcasting_system/internal/models"
+	"github.com/shenikar/geo_broadcasting_system/internal/webhook"
+)
+
+// diffIgnoredFields - поля инцидента, не попадающие в changed_fields: идентификатор и автор не меняются,
+// а временные метки обновляются при каждом сохранении
+var diffIgnoredFields = map[string]struct{}{"id": {}, "created_by": {}, "created_at": {}, "updated_at": {}}
+
+// diffIncidents сравнивает инцидент до и после изменения и возвращает изменившиеся поля
+// по их JSON-именам со старым и новым значением. Пустой результат - инцидент не изменился.
+func diffIncidents(before, after *models.Incident) map[string]webhook.FieldChange {
+	changes := make(map[string]webhook.FieldChange)
+	old, updated := reflect.ValueOf(before).Elem(), reflect.ValueOf(after).Elem()
+	for i := 0; i < old.NumField(); i++ {
+		field := old.Type().Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		if _, ok := diffIgnoredFields[name]; ok {
+			continue
+		}
+		oldValue, newValue := old.Field(i).Interface(), updated.Field(i).Interface()
+		if !reflect.DeepEqual(oldValue, newValue) {
+			changes[name] = webhook.FieldChange{Old: oldValue, New: newValue}
+		}
+	}
+	return changes
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrIncidentNotFound возвращается, когда инцидент с указанным ID не существует
+	ErrIncidentNotFound = errors.New("incident not found")
+	// ErrExternalIDExists возвращается, когда инцидент с таким внешним ID уже существует
+	ErrExternalIDExists = errors.New("incident with this external id already exists")
+	// ErrCoordinatesNotAllowed возвращается, когда координаты вне разрешенных областей или внутри запрещенной
+	ErrCoordinatesNotAllowed = errors.New("coordinates are outside of the service area")
+	// ErrVersionNotFound возвращается, когда у инцидента нет версии с указанным номером
+	ErrVersionNotFound = errors.New("incident version not found")
+	// ErrStatusConflict возвращается, когда текущий статус инцидента не допускает запрошенный переход
+	ErrStatusConflict = errors.New("incident status conflict")
+	// ErrSeverityConflict возвращается, когда уровень опасности инцидента нельзя повысить (уже critical или изменен параллельно)
+	ErrSeverityConflict = errors.New("incident severity conflict")
+	// ErrInvalidIncident возвращается, когда данные инцидента не проходят проверку сервиса
+	ErrInvalidIncident = errors.New("invalid incident")
+	// ErrEmptyBulkTarget возвращается, когда массовая операция не ограничена ни списком ID, ни фильтром
+	ErrEmptyBulkTarget = errors.New("bulk operation requires ids or at least one filter")
+	// ErrInvalidMerge возвращается, когда для слияния передано меньше двух разных инцидентов
+	ErrInvalidMerge = errors.New("merge requires at least two distinct incidents")
+	// ErrImportURLNotAllowed возвращается, когда URL ленты для импорта не http(s), его хост не входит в IMPORT_URL_ALLOWED_HOSTS
+	// или указывает на непубличный адрес
+	ErrImportURLNotAllowed = errors.New("import url is not allowed")
+	// ErrImportLimitReached возвращается, когда одновременно выполняется IMPORT_MAX_CONCURRENT импортов
+	ErrImportLimitReached = errors.New("too many imports in progress")
+	// ErrImportJobNotFound возвращается, когда задачи импорта нет или срок хранения ее состояния истек
+	ErrImportJobNotFound = errors.New("import job not found")
+	// ErrImportFeedNotFound возвращается, когда зарегистрированной ленты с указанным ID не существует
+	ErrImportFeedNotFound = errors.New("import feed not found")
+	// ErrImportFeedExists возвращается, когда лента с таким URL уже зарегистрирована
+	ErrImportFeedExists = errors.New("import feed with this url already exists")
+	// ErrWatcherNotFound возвращается, когда у инцидента нет наблюдателя с указанным ID
+	ErrWatcherNotFound = errors.New("incident watcher not found")
+	// ErrWatcherExists возвращается, когда адрес уже наблюдает за инцидентом
+	ErrWatcherExists = errors.New("incident watcher with this url already exists")
+	// ErrWatcherForbidden возвращается, когда наблюдателя удаляет не добавивший его и не административный API-ключ
+	ErrWatcherForbidden = errors.New("incident watcher belongs to another api key")
+	// ErrWatchURLNotAllowed возвращается, когда адрес наблюдателя не http(s), его хост не входит в WATCH_URL_ALLOWED_HOSTS
+	// или указывает на непубличный адрес
+	ErrWatchURLNotAllowed = errors.New("watch url is not allowed")
+	// ErrAttachmentsDisabled возвращается, когда объектное хранилище для вложений не настроено (S3_BUCKET пуст)
+	ErrAttachmentsDisabled = errors.New("incident attachments are disabled")
+	// ErrInvalidAttachment возвращается, когда имя файла или тип содержимого вложения некорректны
+	ErrInvalidAttachment = errors.New("invalid attachment")
+	// ErrUserLocationNotFound возвращается, когда сохраненное место пользователя не существует
+	ErrUserLocationNotFound = errors.New("user location not found")
+	// ErrUserLocationExists возвращается, когда у пользователя уже есть место с такой меткой
+	ErrUserLocationExists = errors.New("user location with this label already exists")
+	// ErrNoLocationCheck возвращается, когда у пользователя нет ни одной проверки местоположения
+	ErrNoLocationCheck = errors.New("user has no location checks")
+	// ErrTemplateNotFound возвращается, когда шаблон инцидента с указанным ID не существует
+	ErrTemplateNotFound = errors.New("incident template not found")
+	// ErrTemplateExists возвращается, когда шаблон с таким названием уже существует
+	ErrTemplateExists = errors.New("incident template with this name already exists")
+	// ErrInvalidTimeline возвращается, когда период временной шкалы пуст или содержит слишком много корзин
+	ErrInvalidTimeline = errors.New("invalid timeline range")
+	// ErrInvalidReevaluation возвращается, когда период повторной оценки проверок местоположения пуст
+	ErrInvalidReevaluation = errors.New("invalid reevaluation range")
+	// ErrAPIKeyNotFound возвращается при отзыве API-ключа, который не выпускался через API
+	ErrAPIKeyNotFound = errors.New("api key not found")
+	// ErrCheckQuotaExceeded возвращается, когда пользователь исчерпал квоту проверок местоположения (см. RateLimitError)
+	ErrCheckQuotaExceeded = errors.New("location check quota exceeded")
+	// ErrPublicFeedRateLimited возвращается, когда клиент превысил лимит запросов к публичной ленте (см. RateLimitError)
+	ErrPublicFeedRateLimited = errors.New("public feed rate limit exceeded")
+)
+
+// RateLimitError - ошибка превышения квоты или лимита запросов с временем до освобождения места в окне.
+// Err - одна из ErrCheckQuotaExceeded и ErrPublicFeedRateLimited.
+type RateLimitError struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("%s, retry after %s", e.Err, e.RetryAfter)
+}
+
+// Unwrap позволяет сравнивать ошибку с Err через errors.Is
+func (e *RateLimitError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shenikar/geo_broadcasting_system/internal/config"
+	"github.com/shenikar/geo_broadcasting_system/internal/models"
+	"github.com/shenikar/geo_broadcasting_system/internal/webhook"
+	"github.com/sirupsen/logrus"
+)
+
+// autoEscalatedBy - инициатор эскалации, выполненной IncidentEscalator
+const autoEscalatedBy = "auto-escalation"
+
+// EscalateIncident повышает уровень опасности активного инцидента на одну ступень, записывает версию
+// и публикует вебхук incident_escalated. Инцидент уровня critical не повышается (ErrSeverityConflict).
+func (s *incidentService) EscalateIncident(ctx context.Context, id uuid.UUID, escalatedBy string) (*models.Incident, error) {
+	log := s.logger.WithFields(logrus.Fields{
+		"service":      "incident",
+		"method":       "EscalateIncident",
+		"incident_id":  id,
+		"escalated_by": escalatedBy,
+	})
+	log.Info("Attempting to escalate incident")
+
+	incident, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		log.WithError(err).Warn("Attempted to escalate a non-existent incident")
+		return nil, fmt.Errorf("service: could not get incident: %w", err)
+	}
+	if incident.Status != models.StatusActive {
+		log.WithField("status", incident.Status).Warn("Attempted to escalate an incident that is not active")
+		return nil, fmt.Errorf("service: incident is in status %s, not active: %w", incident.Status, ErrStatusConflict)
+	}
+	next, ok := incident.Severity.Next()
+	if !ok {
+		log.WithField("severity", incident.Severity).Warn("Incident is already at the highest severity")
+		return nil, fmt.Errorf("service: incident severity %s cannot be raised: %w", incident.Severity, ErrSeverityConflict)
+	}
+
+	escalated, err := s.repo.EscalateIncident(ctx, id, incident.Severity, next)
+	if err != nil {
+		log.WithError(err).Error("Failed to escalate incident in repository")
+		return nil, fmt.Errorf("service: could not escalate incident: %w", err)
+	}
+	log.WithFields(logrus.Fields{"from": incident.Severity, "to": next}).Info("Incident escalated successfully")
+
+	invalidateIncidentCache(ctx, s.repo, log, id)
+	s.invalidateLocationTiles(ctx, log)
+
+	event := webhook.WebhookEvent{
+		SchemaVersion: webhook.SchemaVersion,
+		Type:          webhook.EventTypeIncidentEscalated,
+		Latitude:      escalated.Latitude,
+		Longitude:     escalated.Longitude,
+		Timestamp:     time.Now(),
+		Incidents:     []*models.Incident{escalated},
+	}
+	if err := s.webhookPublisher.Publish(ctx, event); err != nil {
+		log.WithError(err).Error("Failed to publish incident escalated webhook")
+		// Это не критическая ошибка, продолжаем выполнение
+	}
+	return escalated, nil
+}
+
+// IncidentEscalator - фоновая задача, повышающая уровень опасности активных инцидентов, в зону которых
+// за AUTO_ESCALATE_WINDOW попало не менее AUTO_ESCALATE_DANGEROUS_USERS разных пользователей
+type IncidentEscalator struct {
+	repo    IncidentRepository
+	service IncidentService
+	logger  *logrus.Logger
+	cfg     *config.Config
+}
+
+// NewIncidentEscalator создает задачу автоэскалации; эскалация выполняется через service.EscalateIncident
+func NewIncidentEscalator(repo IncidentRepository, service IncidentService, logger *logrus.Logger, cfg *config.Config) *IncidentEscalator {
+	return &IncidentEscalator{
+		repo:    repo,
+		service: service,
+		logger:  logger,
+		cfg:     cfg,
+	}
+}
+
+// Start запускает горутину автоэскалации с периодом AUTO_ESCALATE_INTERVAL.
+// При AUTO_ESCALATE_DANGEROUS_USERS = 0 автоэскалация отключена и горутина не запускается.
+func (e *IncidentEscalator) Start(ctx context.Context) {
+	if e.cfg.AutoEscalateDangerousUsers <= 0 {
+		e.logger.Info("Incident auto-escalation is disabled (AUTO_ESCALATE_DANGEROUS_USERS=0)")
+		return
+	}
+
+	e.logger.WithFields(logrus.Fields{
+		"dangerous_users": e.cfg.AutoEscalateDangerousUsers,
+		"window":          e.cfg.AutoEscalateWindow,
+	}).Info("Starting incident auto-escalation job...")
+	go func() {
+		ticker := time.NewTicker(e.cfg.AutoEscalateInterval)
+		defer ticker.Stop()
+		for {
+			e.Run(ctx)
+			select {
+			case <-ctx.Done():
+				e.logger.Info("Stopping incident auto-escalation job.")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Run выполняет один проход автоэскалации и возвращает количество повышенных инцидентов
+func (e *IncidentEscalator) Run(ctx context.Context) int {
+	log := e.logger.WithFields(logrus.Fields{
+		"service": "incident",
+		"method":  "AutoEscalateIncidents",
+	})
+
+	ids, err := e.repo.ListEscalationCandidates(ctx, e.cfg.AutoEscalateDangerousUsers, e.cfg.AutoEscalateWindow)
+	if err != nil {
+		log.WithError(err).Error("Failed to list incidents for auto-escalation")
+		return 0
+	}
+
+	escalated := 0
+	for _, id := range ids {
+		if _, err := e.service.EscalateIncident(ctx, id, autoEscalatedBy); err != nil {
+			log.WithError(err).WithField("incident_id", id).Warn("Failed to auto-escalate incident")
+			continue
+		}
+		escalated++
+	}
+
+	if escalated > 0 {
+		log.WithField("escalated", escalated).Info("Incidents auto-escalated")
+	}
+	return escalated
+}
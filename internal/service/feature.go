@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Имена функций, которые можно отключать флагами (FEATURE_FLAGS и хеш feature_flags в Redis)
+const (
+	FeatureClusters      = "clusters"
+	FeatureTimeline      = "timeline"
+	FeaturePreviewImpact = "preview_impact"
+	FeaturePublicFeed    = "public_feed"
+	FeatureEscalation    = "escalation"
+	FeatureHotspots      = "hotspots"
+)
+
+// Features - все функции, управляемые флагами
+var Features = []string{FeatureClusters, FeatureTimeline, FeaturePreviewImpact, FeaturePublicFeed, FeatureEscalation, FeatureHotspots}
+
+// FeatureEnabled сообщает, включена ли функция. Значение из Redis (при FEATURE_FLAGS_REDIS) имеет приоритет
+// над FEATURE_FLAGS; функция, не упомянутая ни там, ни там, включена. Если Redis недоступен,
+// используется значение из конфигурации.
+func (s *incidentService) FeatureEnabled(ctx context.Context, name string) bool {
+	enabled, ok := s.cfg.FeatureFlags[name]
+	if !ok {
+		enabled = true
+	}
+	if !s.cfg.FeatureFlagsRedis {
+		return enabled
+	}
+
+	override, found, err := s.repo.GetFeatureFlag(ctx, name)
+	if err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"service": "incident",
+			"method":  "FeatureEnabled",
+			"feature": name,
+		}).WithError(err).Warn("Failed to read feature flag from Redis, using configured value")
+		return enabled
+	}
+	if found {
+		return override
+	}
+	return enabled
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shenikar/geo_broadcasting_system/internal/models"
+	"github.com/shenikar/geo_broadcasting_system/pkg/geo"
+	"github.com/sirupsen/logrus"
+)
+
+// ValidateIncident выполняет для инцидента те же проверки, что и CreateIncident, и дополнительно
+// проверяет геометрию зоны в PostGIS. Инцидент не сохраняется и не изменяется.
+// Ошибки проверки возвращаются в результате, ошибка метода означает сбой хранилища.
+func (s *incidentService) ValidateIncident(ctx context.Context, incident *models.Incident) (*models.GeofenceValidation, error) {
+	log := s.logger.WithFields(logrus.Fields{
+		"service": "incident",
+		"method":  "ValidateIncident",
+		"name":    incident.Name,
+	})
+
+	candidate := *incident
+	var problems []string
+	if err := s.checkCoordinates(candidate.Latitude, candidate.Longitude); err != nil {
+		problems = append(problems, err.Error())
+	}
+	if err := s.sanitizeIncident(&candidate); err != nil {
+		if !errors.Is(err, ErrInvalidIncident) {
+			return nil, err
+		}
+		problems = append(problems, err.Error())
+	}
+	if err := s.applyCategoryRules(&candidate); err != nil {
+		// Без радиуса зону не построить, и проверять в PostGIS нечего
+		if candidate.RadiusMeters <= 0 {
+			log.WithError(err).Warn("Incident has no radius to validate")
+			return nil, err
+		}
+		problems = append(problems, err.Error())
+	}
+
+	result, err := s.repo.ValidateGeofence(ctx, candidate.Latitude, candidate.Longitude, candidate.RadiusMeters)
+	if err != nil {
+		log.WithError(err).Error("Failed to validate geofence in repository")
+		return nil, fmt.Errorf("service: could not validate geofence: %w", err)
+	}
+	// Для зоны, пересекающей 180-й меридиан, границы геометрии PostGIS охватывают всю долготу от -180 до 180,
+	// поэтому они заменяются прямоугольником с переходом через ±180
+	if bounds := geo.CircleBBox(geo.Point{Lat: candidate.Latitude, Lon: candidate.Longitude}, float64(candidate.RadiusMeters)); bounds.CrossesAntimeridian() {
+		result.Bounds = bounds
+		result.CrossesAntimeridian = true
+	}
+	result.Errors = append(problems, result.Errors...)
+	result.Valid = len(result.Errors) == 0
+
+	log.WithFields(logrus.Fields{
+		"valid":  result.Valid,
+		"errors": len(result.Errors),
+	}).Info("Incident geofence validated")
+	return result, nil
+}
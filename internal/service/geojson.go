@@ -0,0 +1,128 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/shenikar/geo_broadcasting_system/internal/models"
+)
+
+// geoJSONFeatureCollection - лента инцидентов в формате GeoJSON (RFC 7946)
+type geoJSONFeatureCollection struct {
+	Type     string           `json:"type"`
+	Features []geoJSONFeature `json:"features"`
+}
+
+// geoJSONFeature - объект ленты. Инцидент описывается геометрией Point и свойствами;
+// внешний ID берется из properties.external_id, а если его нет - из id объекта.
+type geoJSONFeature struct {
+	Type       string            `json:"type"`
+	ID         json.RawMessage   `json:"id"`
+	Geometry   *geoJSONGeometry  `json:"geometry"`
+	Properties geoJSONProperties `json:"properties"`
+}
+
+// geoJSONGeometry - геометрия объекта; координаты разбираются только для Point
+type geoJSONGeometry struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+}
+
+// geoJSONProperties - свойства объекта, переносимые в инцидент
+type geoJSONProperties struct {
+	ExternalID   string `json:"external_id"`
+	Name         string `json:"name"`
+	Description  string `json:"description"`
+	RadiusMeters int    `json:"radius_meters"`
+	Category     string `json:"category"`
+	Severity     string `json:"severity"`
+	Status       string `json:"status"`
+	Source       string `json:"source"`
+}
+
+// parseGeoJSONFeed разбирает ленту; ошибка возвращается, только если документ не является FeatureCollection
+func parseGeoJSONFeed(data []byte) ([]geoJSONFeature, error) {
+	var collection geoJSONFeatureCollection
+	if err := json.Unmarshal(data, &collection); err != nil {
+		return nil, fmt.Errorf("invalid GeoJSON: %w", err)
+	}
+	if collection.Type != "FeatureCollection" {
+		return nil, fmt.Errorf("GeoJSON type must be FeatureCollection, got %q", collection.Type)
+	}
+	return collection.Features, nil
+}
+
+// externalID возвращает внешний ID объекта: properties.external_id или id объекта (строка или число)
+func (f geoJSONFeature) externalID() string {
+	if id := strings.TrimSpace(f.Properties.ExternalID); id != "" {
+		return id
+	}
+	var id any
+	if err := json.Unmarshal(f.ID, &id); err != nil {
+		return ""
+	}
+	switch v := id.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return ""
+	}
+}
+
+// incident переводит объект ленты в инцидент. Статус, уровень опасности и источник пустые, если не заданы:
+// при создании подставляются значения по умолчанию, при обновлении сохраняются текущие.
+func (f geoJSONFeature) incident() (*models.Incident, error) {
+	externalID := f.externalID()
+	if externalID == "" {
+		return nil, errors.New("feature has no external_id property or id")
+	}
+	if f.Geometry == nil || f.Geometry.Type != "Point" {
+		return nil, errors.New("only Point geometry is supported")
+	}
+	var coordinates []float64
+	if err := json.Unmarshal(f.Geometry.Coordinates, &coordinates); err != nil || len(coordinates) < 2 {
+		return nil, errors.New("point coordinates must be [longitude, latitude]")
+	}
+	lon, lat := coordinates[0], coordinates[1]
+	if lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+		return nil, fmt.Errorf("coordinates [%g, %g] are out of range", lon, lat)
+	}
+	if strings.TrimSpace(f.Properties.Name) == "" {
+		return nil, errors.New("name property is required")
+	}
+	// Без радиуса инцидент принимается, только если радиус по умолчанию задает категория (см. applyCategoryRules)
+	if f.Properties.RadiusMeters < 0 || (f.Properties.RadiusMeters == 0 && f.Properties.Category == "") {
+		return nil, errors.New("radius_meters must be positive unless category provides a default")
+	}
+
+	severity := models.IncidentSeverity(f.Properties.Severity)
+	if severity != "" && !severity.IsValid() {
+		return nil, fmt.Errorf("unknown severity %q", f.Properties.Severity)
+	}
+	if f.Properties.Source != "" && !slices.Contains(models.IncidentSources, f.Properties.Source) {
+		return nil, fmt.Errorf("unknown source %q", f.Properties.Source)
+	}
+	status := models.IncidentStatus(f.Properties.Status)
+	if status != "" && !status.IsValid() {
+		return nil, fmt.Errorf("unknown status %q", f.Properties.Status)
+	}
+
+	return &models.Incident{
+		ExternalID:   externalID,
+		Name:         f.Properties.Name,
+		Description:  f.Properties.Description,
+		Latitude:     lat,
+		Longitude:    lon,
+		RadiusMeters: f.Properties.RadiusMeters,
+		Category:     f.Properties.Category,
+		Severity:     severity,
+		Status:       status,
+		Source:       f.Properties.Source,
+	}, nil
+}
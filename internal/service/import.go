@@ -0,0 +1,304 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shenikar/geo_broadcasting_system/internal/models"
+	"github.com/shenikar/geo_broadcasting_system/pkg/netguard"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// importJobTTL - сколько хранится состояние задачи импорта после последнего обновления
+	importJobTTL = 24 * time.Hour
+	// importProgressEvery - через сколько обработанных объектов сохраняется промежуточное состояние задачи
+	importProgressEvery = 100
+	// maxImportErrors - наибольшее число ошибок объектов в состоянии задачи
+	maxImportErrors = 100
+)
+
+// StartIncidentImport проверяет URL GeoJSON-ленты и запускает ее импорт в фоне.
+// Возвращает задачу в статусе running; ее состояние доступно через GetImportJob.
+func (s *incidentService) StartIncidentImport(ctx context.Context, rawURL, startedBy string) (*models.ImportJob, error) {
+	log := s.logger.WithFields(logrus.Fields{
+		"service":    "incident",
+		"method":     "StartIncidentImport",
+		"url":        rawURL,
+		"started_by": startedBy,
+	})
+
+	if err := s.checkImportURL(ctx, rawURL); err != nil {
+		log.WithError(err).Warn("Import URL rejected")
+		return nil, err
+	}
+	// Слот занимается до сохранения задачи, чтобы отклоненный импорт не оставлял задачу в статусе running
+	select {
+	case s.importSlots <- struct{}{}:
+	default:
+		log.Warn("Too many incident imports in progress")
+		return nil, ErrImportLimitReached
+	}
+
+	job := &models.ImportJob{
+		ID:        uuid.New(),
+		URL:       rawURL,
+		Status:    models.ImportJobRunning,
+		StartedBy: startedBy,
+		Errors:    []models.ImportFeatureError{},
+		StartedAt: time.Now().UTC(),
+	}
+	if err := s.repo.SaveImportJob(ctx, job, importJobTTL); err != nil {
+		<-s.importSlots
+		log.WithError(err).Error("Failed to save import job")
+		return nil, fmt.Errorf("service: could not start import: %w", err)
+	}
+
+	started := *job
+	log = log.WithField("job_id", job.ID)
+	log.Info("Incident import started")
+	// Импорт переживает запрос, который его запустил; при остановке процесса его дожидается WaitImports
+	s.imports.Add(1)
+	go func() {
+		defer s.imports.Done()
+		defer func() { <-s.importSlots }()
+		s.runImport(context.WithoutCancel(ctx), job, log)
+	}()
+	return &started, nil
+}
+
+// WaitImports ждет завершения фоновых импортов, запущенных StartIncidentImport.
+// Возвращает ошибку контекста, если импорты не завершились до его отмены.
+func (s *incidentService) WaitImports(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.imports.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// GetImportJob возвращает состояние задачи импорта
+func (s *incidentService) GetImportJob(ctx context.Context, id uuid.UUID) (*models.ImportJob, error) {
+	job, err := s.repo.GetImportJob(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("service: could not get import job: %w", err)
+	}
+	if job == nil {
+		return nil, ErrImportJobNotFound
+	}
+	return job, nil
+}
+
+// checkImportURL разрешает только http(s) и, если задан IMPORT_URL_ALLOWED_HOSTS, только хосты из него.
+// Хост должен разрешаться только в публичные адреса; при загрузке адрес проверяется еще раз при соединении.
+func (s *incidentService) checkImportURL(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("service: %q must be an absolute http(s) URL: %w", rawURL, ErrImportURLNotAllowed)
+	}
+	if len(s.cfg.ImportURLAllowedHosts) > 0 && !slices.Contains(s.cfg.ImportURLAllowedHosts, strings.ToLower(u.Hostname())) {
+		return fmt.Errorf("service: host %q is not in IMPORT_URL_ALLOWED_HOSTS: %w", u.Hostname(), ErrImportURLNotAllowed)
+	}
+	if err := netguard.CheckHost(ctx, s.resolver, u.Hostname()); err != nil {
+		return fmt.Errorf("service: %v: %w", err, ErrImportURLNotAllowed)
+	}
+	return nil
+}
+
+// newImportTransport создает транспорт загрузки GeoJSON-лент: их адреса задают клиенты API, поэтому соединения
+// с непубличными адресами запрещаются при каждом подключении, в том числе после перенаправления или смены DNS-записи,
+// а прокси из окружения не используется
+func newImportTransport() http.RoundTripper {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = (&net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   netguard.Control,
+	}).DialContext
+	return transport
+}
+
+// runImport загружает ленту и создает или обновляет инциденты по внешнему ID.
+// Ошибка отдельного объекта не прерывает импорт и записывается в состояние задачи.
+func (s *incidentService) runImport(ctx context.Context, job *models.ImportJob, log *logrus.Entry) {
+	features, err := s.fetchGeoJSONFeed(ctx, job.URL)
+	if err != nil {
+		log.WithError(err).Warn("Failed to fetch import feed")
+		job.Status = models.ImportJobFailed
+		job.Error = err.Error()
+		s.finishImport(ctx, job, log)
+		return
+	}
+
+	s.importFeatures(ctx, job, features, "", func(done int) {
+		if done%importProgressEvery != 0 {
+			return
+		}
+		if err := s.repo.SaveImportJob(ctx, job, importJobTTL); err != nil {
+			log.WithError(err).Warn("Failed to save import progress")
+		}
+	})
+	job.Status = models.ImportJobCompleted
+	s.finishImport(ctx, job, log)
+}
+
+// importFeatures импортирует объекты ленты, подсчитывая результат в job; progress вызывается
+// после каждого объекта с числом обработанных. Непустой scope - пространство внешних ID ленты (см. scopedExternalID).
+func (s *incidentService) importFeatures(ctx context.Context, job *models.ImportJob, features []geoJSONFeature, scope string, progress func(done int)) {
+	job.Features = len(features)
+	for i, feature := range features {
+		outcome, err := s.importFeature(ctx, feature, job.StartedBy, scope)
+		switch {
+		case err != nil:
+			if len(job.Errors) < maxImportErrors {
+				job.Errors = append(job.Errors, models.ImportFeatureError{Index: i, ExternalID: feature.externalID(), Error: err.Error()})
+			}
+		case outcome == importCreated:
+			job.Created++
+		case outcome == importUpdated:
+			job.Updated++
+		default:
+			job.Unchanged++
+		}
+		progress(i + 1)
+	}
+}
+
+// finishImport сохраняет итоговое состояние задачи
+func (s *incidentService) finishImport(ctx context.Context, job *models.ImportJob, log *logrus.Entry) {
+	finishedAt := time.Now().UTC()
+	job.FinishedAt = &finishedAt
+	if err := s.repo.SaveImportJob(ctx, job, importJobTTL); err != nil {
+		log.WithError(err).Error("Failed to save finished import job")
+	}
+	log.WithFields(logrus.Fields{
+		"status":    job.Status,
+		"features":  job.Features,
+		"created":   job.Created,
+		"updated":   job.Updated,
+		"unchanged": job.Unchanged,
+		"errors":    len(job.Errors),
+	}).Info("Incident import finished")
+}
+
+// fetchGeoJSONFeed загружает и разбирает ленту с ограничениями IMPORT_URL_TIMEOUT и IMPORT_URL_MAX_BYTES.
+// Перенаправления проверяются так же, как исходный URL.
+func (s *incidentService) fetchGeoJSONFeed(ctx context.Context, rawURL string) ([]geoJSONFeature, error) {
+	client := &http.Client{
+		Timeout:   s.cfg.ImportURLTimeout,
+		Transport: s.importTransport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return errors.New("stopped after 10 redirects")
+			}
+			return s.checkImportURL(req.Context(), req.URL.String())
+		},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build feed request: %w", err)
+	}
+	req.Header.Set("Accept", "application/geo+json, application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch feed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("feed responded with status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, int64(s.cfg.ImportURLMaxBytes)+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read feed: %w", err)
+	}
+	if len(data) > s.cfg.ImportURLMaxBytes {
+		return nil, fmt.Errorf("feed exceeds %d bytes", s.cfg.ImportURLMaxBytes)
+	}
+	return parseGeoJSONFeed(data)
+}
+
+// importOutcome - что сделал импорт с объектом ленты
+type importOutcome int
+
+const (
+	importCreated importOutcome = iota
+	importUpdated
+	// importUnchanged - инцидент уже совпадает с объектом ленты и не сохранялся
+	importUnchanged
+)
+
+// importFeature создает инцидент объекта ленты или обновляет инцидент с тем же внешним ID.
+// При обновлении незаданные в ленте статус, уровень опасности и источник сохраняются;
+// инцидент, который уже совпадает с объектом, не сохраняется, чтобы повторный импорт не создавал версий.
+// Поэтому объект сравнивается с инцидентом после той же очистки текста и правил категорий, что и при сохранении.
+// Инцидент, деактивированный синхронизацией из-за пропажи из ленты, при возвращении объекта снова становится активным.
+// createdBy записывается только в новые инциденты.
+func (s *incidentService) importFeature(ctx context.Context, feature geoJSONFeature, createdBy, scope string) (importOutcome, error) {
+	incident, err := feature.incident()
+	if err != nil {
+		return 0, err
+	}
+	incident.CreatedBy = createdBy
+	incident.ExternalID = scopedExternalID(scope, incident.ExternalID)
+	if err := s.sanitizeIncident(incident); err != nil {
+		return 0, err
+	}
+	if err := s.applyCategoryRules(incident); err != nil {
+		return 0, err
+	}
+
+	existing, err := s.repo.GetByExternalID(ctx, incident.ExternalID)
+	if errors.Is(err, ErrIncidentNotFound) {
+		return importCreated, s.CreateIncident(ctx, incident)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	update := *existing
+	update.Name = incident.Name
+	update.Description = incident.Description
+	update.Latitude = incident.Latitude
+	update.Longitude = incident.Longitude
+	update.RadiusMeters = incident.RadiusMeters
+	update.Category = incident.Category
+	if incident.Status != "" {
+		update.Status = incident.Status
+	}
+	if incident.Severity != "" {
+		update.Severity = incident.Severity
+	}
+	if incident.Source != "" {
+		update.Source = incident.Source
+	}
+	if existing.Status == models.StatusInactive && existing.ResolutionReason == importFeedRemovedReason {
+		if incident.Status == "" {
+			update.Status = models.StatusActive
+		}
+		if update.Status == models.StatusActive {
+			update.ResolutionReason = ""
+		}
+	}
+	if len(diffIncidents(existing, &update)) == 0 {
+		return importUnchanged, nil
+	}
+	return importUpdated, s.UpdateIncident(ctx, &update)
+}
@@ -0,0 +1,238 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shenikar/geo_broadcasting_system/internal/config"
+	"github.com/shenikar/geo_broadcasting_system/internal/models"
+	"github.com/sirupsen/logrus"
+)
+
+// importFeedCheckInterval - как часто синхронизатор ищет ленты, которым пора синхронизироваться;
+// поэтому новая лента синхронизируется в течение минуты после регистрации
+const importFeedCheckInterval = time.Minute
+
+// importFeedRemovedReason - причина деактивации инцидента, пропавшего из ленты. По ней синхронизация отличает
+// такие инциденты от деактивированных вручную и возвращает их в работу, когда объект снова появляется в ленте.
+const importFeedRemovedReason = "removed from import feed"
+
+// scopedExternalID возвращает внешний ID инцидента для объекта ленты: "<ID ленты>:<ID объекта>". Объекты разных лент
+// и импортов по URL с одинаковыми ID не перезаписывают инциденты друг друга. Пустой scope - импорт по URL, ID как есть.
+func scopedExternalID(scope, externalID string) string {
+	if scope == "" {
+		return externalID
+	}
+	return scope + ":" + externalID
+}
+
+// RegisterImportFeed проверяет URL GeoJSON-ленты так же, как импорт по URL, и регистрирует ее
+// для периодической синхронизации. Первая синхронизация выполняется при ближайшей проверке ImportFeedSyncer.
+func (s *incidentService) RegisterImportFeed(ctx context.Context, rawURL, createdBy string) (*models.ImportFeed, error) {
+	log := s.logger.WithFields(logrus.Fields{
+		"service":    "incident",
+		"method":     "RegisterImportFeed",
+		"url":        rawURL,
+		"created_by": createdBy,
+	})
+
+	if err := s.checkImportURL(ctx, rawURL); err != nil {
+		log.WithError(err).Warn("Import feed URL rejected")
+		return nil, err
+	}
+
+	feed := &models.ImportFeed{URL: rawURL, CreatedBy: createdBy}
+	if err := s.repo.CreateImportFeed(ctx, feed); err != nil {
+		log.WithError(err).Warn("Failed to register import feed")
+		return nil, fmt.Errorf("service: could not register import feed: %w", err)
+	}
+	log.WithField("feed_id", feed.ID).Info("Import feed registered")
+	return feed, nil
+}
+
+// ListImportFeeds возвращает зарегистрированные ленты с итогом последней синхронизации
+func (s *incidentService) ListImportFeeds(ctx context.Context) ([]*models.ImportFeed, error) {
+	feeds, err := s.repo.ListImportFeeds(ctx)
+	if err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"service": "incident",
+			"method":  "ListImportFeeds",
+		}).WithError(err).Error("Failed to list import feeds from repository")
+		return nil, fmt.Errorf("service: could not list import feeds: %w", err)
+	}
+	return feeds, nil
+}
+
+// DeleteImportFeed снимает ленту с синхронизации; импортированные из нее инциденты остаются как есть
+func (s *incidentService) DeleteImportFeed(ctx context.Context, id uuid.UUID) error {
+	log := s.logger.WithFields(logrus.Fields{
+		"service": "incident",
+		"method":  "DeleteImportFeed",
+		"feed_id": id,
+	})
+	if err := s.repo.DeleteImportFeed(ctx, id); err != nil {
+		log.WithError(err).Warn("Failed to delete import feed")
+		return fmt.Errorf("service: could not delete import feed: %w", err)
+	}
+	log.Info("Import feed deleted")
+	return nil
+}
+
+// SyncImportFeed синхронизирует инциденты с лентой: новые и измененные объекты импортируются так же,
+// как при импорте по URL, но с внешними ID в пространстве ленты (см. scopedExternalID), а инциденты, которые были
+// в ленте при прошлой синхронизации и пропали из нее, деактивируются с причиной importFeedRemovedReason.
+// Вебхуки публикуются теми же операциями, что и при ручных изменениях.
+// Если ленту не удалось загрузить, инциденты не изменяются, а итог failed записывается в ленту.
+func (s *incidentService) SyncImportFeed(ctx context.Context, feed *models.ImportFeed) (*models.ImportFeedSync, error) {
+	log := s.logger.WithFields(logrus.Fields{
+		"service": "incident",
+		"method":  "SyncImportFeed",
+		"feed_id": feed.ID,
+		"url":     feed.URL,
+	})
+
+	sync := &models.ImportFeedSync{Status: models.ImportJobCompleted}
+	features, err := s.fetchGeoJSONFeed(ctx, feed.URL)
+	if err != nil {
+		log.WithError(err).Warn("Failed to fetch import feed")
+		sync.Status = models.ImportJobFailed
+		sync.Error = err.Error()
+		sync.SyncedAt = time.Now().UTC()
+		if err := s.repo.SaveImportFeedSync(ctx, feed.ID, nil, *sync); err != nil {
+			return nil, fmt.Errorf("service: could not save import feed sync: %w", err)
+		}
+		return sync, nil
+	}
+
+	job := &models.ImportJob{StartedBy: feed.CreatedBy, Errors: []models.ImportFeatureError{}}
+	s.importFeatures(ctx, job, features, feed.ID.String(), func(int) {})
+	sync.Created, sync.Updated = job.Created, job.Updated
+	if len(job.Errors) > 0 {
+		sync.Error = fmt.Sprintf("%d of %d features failed, first: %s", len(job.Errors), job.Features, job.Errors[0].Error)
+	}
+
+	// Объект, который есть в ленте, но не прошел проверку, не считается пропавшим
+	present := make(map[string]struct{}, len(features))
+	for _, feature := range features {
+		if id := feature.externalID(); id != "" {
+			present[id] = struct{}{}
+		}
+	}
+	missing := make([]uuid.UUID, 0)
+	for _, externalID := range feed.ExternalIDs {
+		if _, ok := present[externalID]; ok {
+			continue
+		}
+		incident, err := s.repo.GetByExternalID(ctx, scopedExternalID(feed.ID.String(), externalID))
+		if err != nil {
+			if !errors.Is(err, ErrIncidentNotFound) {
+				log.WithError(err).WithField("external_id", externalID).Warn("Failed to get incident missing from feed")
+				present[externalID] = struct{}{}
+			}
+			continue
+		}
+		missing = append(missing, incident.ID)
+	}
+	if len(missing) > 0 {
+		deactivated, err := s.BulkDeactivateIncidents(ctx, models.BulkDeactivation{IDs: missing, Reason: importFeedRemovedReason})
+		if err != nil {
+			// Пропавшие объекты остаются в наборе ленты, чтобы деактивация повторилась при следующей синхронизации
+			log.WithError(err).Warn("Failed to deactivate incidents missing from feed")
+			for _, externalID := range feed.ExternalIDs {
+				present[externalID] = struct{}{}
+			}
+		}
+		sync.Deactivated = deactivated
+	}
+
+	sync.SyncedAt = time.Now().UTC()
+	if err := s.repo.SaveImportFeedSync(ctx, feed.ID, slices.Sorted(maps.Keys(present)), *sync); err != nil {
+		log.WithError(err).Error("Failed to save import feed sync")
+		return nil, fmt.Errorf("service: could not save import feed sync: %w", err)
+	}
+	log.WithFields(logrus.Fields{
+		"features":    job.Features,
+		"created":     sync.Created,
+		"updated":     sync.Updated,
+		"unchanged":   job.Unchanged,
+		"deactivated": sync.Deactivated,
+		"errors":      len(job.Errors),
+	}).Info("Import feed synced")
+	return sync, nil
+}
+
+// ImportFeedSyncer - фоновая задача, синхронизирующая зарегистрированные GeoJSON-ленты
+// с периодом IMPORT_FEED_SYNC_INTERVAL
+type ImportFeedSyncer struct {
+	repo    IncidentRepository
+	service IncidentService
+	logger  *logrus.Logger
+	cfg     *config.Config
+}
+
+// NewImportFeedSyncer создает задачу синхронизации лент; синхронизация выполняется через service.SyncImportFeed
+func NewImportFeedSyncer(repo IncidentRepository, service IncidentService, logger *logrus.Logger, cfg *config.Config) *ImportFeedSyncer {
+	return &ImportFeedSyncer{
+		repo:    repo,
+		service: service,
+		logger:  logger,
+		cfg:     cfg,
+	}
+}
+
+// Start запускает горутину синхронизации лент. При IMPORT_FEED_SYNC_INTERVAL = 0 синхронизация отключена
+// и горутина не запускается.
+func (f *ImportFeedSyncer) Start(ctx context.Context) {
+	if f.cfg.ImportFeedSyncInterval <= 0 {
+		f.logger.Info("Import feed sync is disabled (IMPORT_FEED_SYNC_INTERVAL=0)")
+		return
+	}
+
+	f.logger.WithField("interval", f.cfg.ImportFeedSyncInterval).Info("Starting import feed sync job...")
+	go func() {
+		ticker := time.NewTicker(min(importFeedCheckInterval, f.cfg.ImportFeedSyncInterval))
+		defer ticker.Stop()
+		for {
+			f.Run(ctx)
+			select {
+			case <-ctx.Done():
+				f.logger.Info("Stopping import feed sync job.")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Run синхронизирует ленты, которые не синхронизировались дольше IMPORT_FEED_SYNC_INTERVAL,
+// и возвращает количество успешно синхронизированных
+func (f *ImportFeedSyncer) Run(ctx context.Context) int {
+	log := f.logger.WithFields(logrus.Fields{
+		"service": "incident",
+		"method":  "SyncImportFeeds",
+	})
+
+	feeds, err := f.repo.ClaimDueImportFeeds(ctx, f.cfg.ImportFeedSyncInterval)
+	if err != nil {
+		log.WithError(err).Error("Failed to claim import feeds for sync")
+		return 0
+	}
+
+	synced := 0
+	for _, feed := range feeds {
+		sync, err := f.service.SyncImportFeed(ctx, feed)
+		if err != nil {
+			log.WithError(err).WithField("feed_id", feed.ID).Warn("Failed to sync import feed")
+			continue
+		}
+		if sync.Status == models.ImportJobCompleted {
+			synced++
+		}
+	}
+	return synced
+}
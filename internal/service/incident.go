@@ -2,13 +2,20 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/shenikar/geo_broadcasting_system/internal/config"
 	"github.com/shenikar/geo_broadcasting_system/internal/models"
 	"github.com/shenikar/geo_broadcasting_system/internal/webhook"
+	"github.com/shenikar/geo_broadcasting_system/pkg/geo"
+	"github.com/shenikar/geo_broadcasting_system/pkg/netguard"
 	"github.com/sirupsen/logrus"
 )
 
@@ -16,47 +23,207 @@ import (
 type IncidentRepository interface {
 	Create(ctx context.Context, incident *models.Incident) error
 	GetByID(ctx context.Context, id uuid.UUID) (*models.Incident, error)
+	GetByExternalID(ctx context.Context, externalID string) (*models.Incident, error)
 	Update(ctx context.Context, incident *models.Incident) error
-	Delete(ctx context.Context, id uuid.UUID) error
-	ListIncidents(ctx context.Context, page, pageSize int) ([]*models.Incident, error)
-	FindActiveLocation(ctx context.Context, lat, lon float64) ([]*models.Incident, error)
+	Revert(ctx context.Context, incident *models.Incident, change *models.StatusChange) error
+	Delete(ctx context.Context, id uuid.UUID, from models.IncidentStatus, resolution models.Resolution) error
+	ChangeStatus(ctx context.Context, change *models.StatusChange) error
+	ListIncidentVersions(ctx context.Context, incidentID uuid.UUID) ([]*models.IncidentVersion, error)
+	GetIncidentVersion(ctx context.Context, incidentID uuid.UUID, version int) (*models.IncidentVersion, error)
+	DeactivateIncidents(ctx context.Context, target models.BulkDeactivation) ([]*models.Incident, error)
+	MergeIncidents(ctx context.Context, merge models.IncidentMerge) (*models.IncidentMergeResult, error)
+	EscalateIncident(ctx context.Context, id uuid.UUID, from, to models.IncidentSeverity) (*models.Incident, error)
+	ListEscalationCandidates(ctx context.Context, minUsers int, window time.Duration) ([]uuid.UUID, error)
+	PurgeInactiveIncidents(ctx context.Context, before time.Time, limit int) ([]uuid.UUID, error)
+	ListIncidents(ctx context.Context, page, pageSize int, filter models.IncidentFilter) ([]*models.Incident, error)
+	MaxUpdatedAt(ctx context.Context) (time.Time, error)
+	CurrentTime(ctx context.Context) (time.Time, error)
+	FindActiveLocation(ctx context.Context, lat, lon float64, bufferMeters, minConfidence int) ([]*models.Incident, error)
+	FindActiveLocationForAlert(ctx context.Context, lat, lon float64, bufferMeters, minConfidence int, alertBuffers map[models.IncidentSeverity]int) (inZone, alerted []*models.Incident, err error)
+	FindActiveInArea(ctx context.Context, bbox geo.BBox, bufferMeters int) ([]*models.Incident, error)
+	ListActiveIncidents(ctx context.Context) ([]*models.Incident, error)
+	ValidateGeofence(ctx context.Context, lat, lon float64, radiusMeters int) (*models.GeofenceValidation, error)
+	ClusterActiveIncidents(ctx context.Context, bbox geo.BBox, gridSize float64) ([]*models.IncidentCluster, error)
+	FindHotspots(ctx context.Context, bbox geo.BBox, latSize, lonSize float64, minCount int) ([]*models.IncidentHotspot, error)
+	IncidentTimeline(ctx context.Context, q models.TimelineQuery) ([]*models.TimelineBucket, error)
 	GetLocationCheckStats(ctx context.Context, minutes int) (int, error)
-	SaveLocationCheck(ctx context.Context, check *models.LocationCheck) error
+	SaveLocationCheck(ctx context.Context, check *models.LocationCheck, outbox *models.OutboxMessage) error
+	RelayOutbox(ctx context.Context, limit int, send func(*models.OutboxMessage) error) (int, error)
+	PurgeSentOutbox(ctx context.Context, before time.Time) (int64, error)
+	GetIncidentImpact(ctx context.Context, incidentID uuid.UUID, minutes int) (int, error)
+	CountRecentUsersInArea(ctx context.Context, lat, lon float64, radiusMeters, minutes int) (int, error)
+	ReserveUserCheck(ctx context.Context, userID string, limit int, window time.Duration) (time.Duration, error)
+	ListPublicIncidents(ctx context.Context, excludedCategories []string, page, pageSize int) ([]*models.Incident, error)
+	ReservePublicFeedRequest(ctx context.Context, clientIP string, limit int, window time.Duration) (time.Duration, error)
+	GetFeatureFlag(ctx context.Context, name string) (enabled, found bool, err error)
+	ListLocationChecksByUser(ctx context.Context, userID string, page, pageSize int) ([]*models.LocationCheck, error)
+	CountLocationChecks(ctx context.Context, from, to time.Time) (int, error)
+	FindMissedLocationAlerts(ctx context.Context, from, to time.Time, afterID int64, limit int) ([]*models.LocationCheck, error)
+	CreateUserLocation(ctx context.Context, location *models.UserLocation) error
+	ListUserLocations(ctx context.Context, userID string) ([]*models.UserLocation, error)
+	DeleteUserLocation(ctx context.Context, userID string, id uuid.UUID) error
+	CreateIncidentTemplate(ctx context.Context, template *models.IncidentTemplate) error
+	GetIncidentTemplate(ctx context.Context, id uuid.UUID) (*models.IncidentTemplate, error)
+	ListIncidentTemplates(ctx context.Context) ([]*models.IncidentTemplate, error)
+	UpdateIncidentTemplate(ctx context.Context, template *models.IncidentTemplate) error
+	DeleteIncidentTemplate(ctx context.Context, id uuid.UUID) error
+	AddAPIKey(ctx context.Context, keyHash string) error
+	RemoveAPIKey(ctx context.Context, keyHash string) error
+	ListAPIKeys(ctx context.Context) ([]string, error)
+	IncrementAPIKeyUsage(ctx context.Context, label string, bucket time.Time, ttl time.Duration) error
+	SaveImportJob(ctx context.Context, job *models.ImportJob, ttl time.Duration) error
+	GetImportJob(ctx context.Context, id uuid.UUID) (*models.ImportJob, error)
+	CreateImportFeed(ctx context.Context, feed *models.ImportFeed) error
+	ListImportFeeds(ctx context.Context) ([]*models.ImportFeed, error)
+	DeleteImportFeed(ctx context.Context, id uuid.UUID) error
+	ClaimDueImportFeeds(ctx context.Context, interval time.Duration) ([]*models.ImportFeed, error)
+	SaveImportFeedSync(ctx context.Context, id uuid.UUID, externalIDs []string, sync models.ImportFeedSync) error
+	CreateIncidentWatcher(ctx context.Context, watcher *models.IncidentWatcher) error
+	ListIncidentWatchers(ctx context.Context, incidentID uuid.UUID) ([]*models.IncidentWatcher, error)
+	DeleteIncidentWatcher(ctx context.Context, incidentID, id uuid.UUID) error
+	ListWatchersByIncidents(ctx context.Context, incidentIDs []uuid.UUID) ([]*models.IncidentWatcher, error)
+	CreateIncidentAttachment(ctx context.Context, attachment *models.IncidentAttachment) error
+	ListIncidentAttachments(ctx context.Context, incidentID uuid.UUID) ([]*models.IncidentAttachment, error)
+	IncrementIncidentViews(ctx context.Context, counts map[uuid.UUID]int64) error
+	GetIncidentViews(ctx context.Context, id uuid.UUID) (int64, error)
+	GetPendingIncidentViews(ctx context.Context, id uuid.UUID) (int64, error)
+	PersistIncidentViews(ctx context.Context) (int, error)
+	CountAPIKeyUsage(ctx context.Context, buckets []time.Time) (map[string]int64, error)
 
 	// Методы кэширования
 	GetIncidentFromCache(ctx context.Context, id uuid.UUID) (*models.Incident, error)
 	SetIncidentCache(ctx context.Context, incident *models.Incident) error
-	InvalidateIncidentCache(ctx context.Context, id uuid.UUID) error
+	InvalidateIncidentCache(ctx context.Context, ids ...uuid.UUID) error
+	ExpireIncidentCache(ctx context.Context, ttl time.Duration, ids ...uuid.UUID) error
+	GetStatsFromCache(ctx context.Context, minutes int) (*models.StatsSnapshot, error)
+	SetStatsCache(ctx context.Context, minutes int, snapshot *models.StatsSnapshot, ttl time.Duration) error
+	GetLocationTileGeneration(ctx context.Context) (int64, error)
+	GetLocationTileFromCache(ctx context.Context, generation int64, geohash string) ([]*models.Incident, error)
+	SetLocationTileCache(ctx context.Context, generation int64, geohash string, incidents []*models.Incident, ttl time.Duration) error
+	InvalidateLocationTiles(ctx context.Context) error
+	FlushCaches(ctx context.Context) (int64, error)
 }
 
 // IncidentService определяет контрак для бизнес-логики управления инцидентами
 type IncidentService interface {
 	CreateIncident(ctx context.Context, incident *models.Incident) error
+	ValidateIncident(ctx context.Context, incident *models.Incident) (*models.GeofenceValidation, error)
 	GetIncident(ctx context.Context, id uuid.UUID) (*models.Incident, error)
+	GetIncidentByExternalID(ctx context.Context, externalID string) (*models.Incident, error)
 	UpdateIncident(ctx context.Context, incident *models.Incident) error
-	DeactivateIncident(ctx context.Context, id uuid.UUID) error
-	ListIncidents(ctx context.Context, page, pageSize int) ([]*models.Incident, error)
-	CheckLocation(ctx context.Context, userID string, lat, lon float64) ([]*models.Incident, error)
+	DeactivateIncident(ctx context.Context, id uuid.UUID, resolution models.Resolution) error
+	ListIncidentVersions(ctx context.Context, id uuid.UUID) ([]*models.IncidentVersion, error)
+	RevertIncident(ctx context.Context, id uuid.UUID, version int, revertedBy string) (*models.Incident, error)
+	BulkDeactivateIncidents(ctx context.Context, target models.BulkDeactivation) (int, error)
+	MergeIncidents(ctx context.Context, ids []uuid.UUID, mergedBy string) (*models.IncidentMergeResult, error)
+	EscalateIncident(ctx context.Context, id uuid.UUID, escalatedBy string) (*models.Incident, error)
+	ApproveIncident(ctx context.Context, id uuid.UUID, reviewer string) (*models.Incident, error)
+	RejectIncident(ctx context.Context, id uuid.UUID, reviewer string) (*models.Incident, error)
+	ListIncidents(ctx context.Context, page, pageSize int, filter models.IncidentFilter) ([]*models.Incident, error)
+	ListIncidentChanges(ctx context.Context, after models.ChangeCursor, limit int) ([]*models.Incident, models.ChangeCursor, bool, error)
+	PollIncidentChanges(ctx context.Context, after models.ChangeCursor, timeout time.Duration) ([]*models.Incident, models.ChangeCursor, error)
+	ListPublicIncidents(ctx context.Context, clientIP string, page, pageSize int) ([]*models.Incident, error)
+	IncidentsLastModified(ctx context.Context) (time.Time, error)
+	IncidentsSnapshotTime(ctx context.Context) (time.Time, error)
+	CheckLocation(ctx context.Context, userID string, authenticated bool, lat, lon float64, bufferMeters, minConfidence int) ([]*models.Incident, int, error)
+	ClusterIncidents(ctx context.Context, bbox geo.BBox, zoom int) ([]*models.IncidentCluster, error)
+	FindHotspots(ctx context.Context, bbox geo.BBox, cellMeters float64, minCount int) ([]*models.IncidentHotspot, error)
+	GetIncidentTimeline(ctx context.Context, q models.TimelineQuery) ([]*models.TimelineBucket, error)
 	GetStats(ctx context.Context) (int, error)
+	GetIncidentImpact(ctx context.Context, id uuid.UUID) (int, error)
+	PreviewIncidentImpact(ctx context.Context, lat, lon float64, radiusMeters int) (int, error)
+	GetOverlappingIncidents(ctx context.Context, id uuid.UUID) ([]*models.IncidentOverlap, error)
+	GetUserLocationHistory(ctx context.Context, userID string, page, pageSize int) ([]*models.LocationCheck, error)
+	ReevaluateLocationChecks(ctx context.Context, q models.LocationCheckReevaluationQuery) (*models.LocationCheckReevaluation, error)
+	AddUserLocation(ctx context.Context, location *models.UserLocation) error
+	ListUserLocations(ctx context.Context, userID string) ([]*models.UserLocation, error)
+	DeleteUserLocation(ctx context.Context, userID string, id uuid.UUID) error
+	GetUserAlerts(ctx context.Context, userID string) ([]*models.LocationAlert, error)
+	GetUserStatus(ctx context.Context, userID string) (*models.UserStatus, error)
+	CreateIncidentTemplate(ctx context.Context, template *models.IncidentTemplate) error
+	GetIncidentTemplate(ctx context.Context, id uuid.UUID) (*models.IncidentTemplate, error)
+	ListIncidentTemplates(ctx context.Context) ([]*models.IncidentTemplate, error)
+	UpdateIncidentTemplate(ctx context.Context, template *models.IncidentTemplate) error
+	DeleteIncidentTemplate(ctx context.Context, id uuid.UUID) error
+	CreateIncidentFromTemplate(ctx context.Context, templateID uuid.UUID, incident *models.Incident) error
+	StartIncidentImport(ctx context.Context, rawURL, startedBy string) (*models.ImportJob, error)
+	WaitImports(ctx context.Context) error
+	GetImportJob(ctx context.Context, id uuid.UUID) (*models.ImportJob, error)
+	RegisterImportFeed(ctx context.Context, rawURL, createdBy string) (*models.ImportFeed, error)
+	ListImportFeeds(ctx context.Context) ([]*models.ImportFeed, error)
+	DeleteImportFeed(ctx context.Context, id uuid.UUID) error
+	SyncImportFeed(ctx context.Context, feed *models.ImportFeed) (*models.ImportFeedSync, error)
+	WatchIncident(ctx context.Context, watcher *models.IncidentWatcher) error
+	ListIncidentWatchers(ctx context.Context, incidentID uuid.UUID) ([]*models.IncidentWatcher, error)
+	UnwatchIncident(ctx context.Context, incidentID, watcherID uuid.UUID, requestedBy string, admin bool) error
+	CreateAttachmentUploadURL(ctx context.Context, incidentID uuid.UUID, filename, contentType, createdBy string) (*models.PresignedAttachment, error)
+	ListIncidentAttachments(ctx context.Context, incidentID uuid.UUID) ([]*models.PresignedAttachment, error)
+	RecordIncidentView(id uuid.UUID)
+	FlushIncidentViews(ctx context.Context) error
+	GetIncidentViews(ctx context.Context, id uuid.UUID) (int64, error)
+	AddAPIKey(ctx context.Context, apiKey string) (string, error)
+	RevokeAPIKey(ctx context.Context, apiKey string) error
+	IsRuntimeAPIKey(ctx context.Context, apiKey string) (bool, error)
+	RecordAPIKeyUsage(ctx context.Context, label string) error
+	InvalidateCaches(ctx context.Context) (int64, error)
+	GetAPIKeyUsage(ctx context.Context) ([]*models.APIKeyUsage, error)
+	GetWebhookQueueDepth(ctx context.Context) (int64, error)
+	GetWebhookCircuits(ctx context.Context) ([]webhook.CircuitStatus, int64, error)
+	FeatureEnabled(ctx context.Context, name string) bool
 }
 
+// statsRefreshTimeout - максимальная длительность фонового обновления кэша статистики
+const statsRefreshTimeout = 30 * time.Second
+
+// MaxPageSize - наибольший размер страницы списков; больший или неположительный размер заменяется на 20
+const MaxPageSize = 100
+
 type incidentService struct {
 	repo             IncidentRepository
 	logger           *logrus.Logger
 	cfg              *config.Config
 	webhookPublisher webhook.WebhookPublisher
+	// statsRefreshing - признак выполняющегося фонового обновления кэша статистики
+	statsRefreshing atomic.Bool
+	// apiKeys - кэш в памяти процесса для ключей, выпущенных через API
+	apiKeys apiKeyCache
+	// activeIndex - необязательный индекс активных инцидентов в памяти для проверки местоположения
+	activeIndex *ActiveIncidentIndex
+	// changes будит запросы long polling после изменения инцидентов этим процессом
+	changes *changeNotifier
+	// attachments подписывает URL объектного хранилища вложений; nil, если хранилище не настроено
+	attachments AttachmentStorage
+	// views копит просмотры инцидентов до переноса в Redis (см. FlushIncidentViews)
+	views viewCounter
+	// resolver разрешает хосты адресов, заданных клиентами API, для защиты от SSRF (см. checkWatchURL, checkImportURL)
+	resolver netguard.Resolver
+	// importTransport загружает GeoJSON-ленты только с публичных адресов (см. newImportTransport)
+	importTransport http.RoundTripper
+	// importSlots ограничивает число одновременных фоновых импортов (IMPORT_MAX_CONCURRENT)
+	importSlots chan struct{}
+	// imports - незавершенные фоновые импорты, которых дожидается WaitImports
+	imports sync.WaitGroup
 }
 
-func NewIncidentService(repo IncidentRepository, logger *logrus.Logger, cfg *config.Config, publisher webhook.WebhookPublisher) IncidentService {
+// NewIncidentService создает сервис инцидентов. activeIndex может быть nil: тогда проверка
+// местоположения всегда идет через кэш ячеек или PostGIS.
+func NewIncidentService(repo IncidentRepository, logger *logrus.Logger, cfg *config.Config, publisher webhook.WebhookPublisher, activeIndex *ActiveIncidentIndex) IncidentService {
 	return &incidentService{
 		repo:             repo,
 		logger:           logger,
 		cfg:              cfg,
 		webhookPublisher: publisher,
+		activeIndex:      activeIndex,
+		changes:          newChangeNotifier(),
+		attachments:      newAttachmentStorage(cfg, logger),
+		resolver:         net.DefaultResolver,
+		importTransport:  newImportTransport(),
+		importSlots:      make(chan struct{}, max(cfg.ImportMaxConcurrent, 1)),
 	}
 }
 
-// CreateIncident создает инцидент
+// CreateIncident создает инцидент. Если статус не задан, используется статус по умолчанию из конфигурации.
+// Инциденты в статусе draft не участвуют в проверке местоположения до активации.
 func (s *incidentService) CreateIncident(ctx context.Context, incident *models.Incident) error {
 	log := s.logger.WithFields(logrus.Fields{
 		"service": "incident",
@@ -65,17 +232,40 @@ func (s *incidentService) CreateIncident(ctx context.Context, incident *models.I
 	})
 	log.Info("Attempting to create a new incident")
 
-	incident.Status = "active"
+	if err := s.checkCoordinates(incident.Latitude, incident.Longitude); err != nil {
+		log.WithError(err).Warn("Incident rejected by coordinate regions")
+		return err
+	}
+	if err := s.sanitizeIncident(incident); err != nil {
+		log.WithError(err).Warn("Incident rejected after sanitization")
+		return err
+	}
+	if err := s.applyCategoryRules(incident); err != nil {
+		log.WithError(err).Warn("Incident rejected by category rules")
+		return err
+	}
+	if incident.Status == "" {
+		incident.Status = s.cfg.DefaultIncidentStatus
+	}
+	if incident.Source == "" {
+		incident.Source = models.SourceOfficial
+	}
+	if incident.Severity == "" {
+		incident.Severity = models.SeverityMinor
+	}
 	if err := s.repo.Create(ctx, incident); err != nil {
 		log.WithError(err).Error("Failed to create incident in repository")
 		return fmt.Errorf("service: could not create incident: %w", err)
 	}
 
 	log.WithField("incident_id", incident.ID).Info("Incident created successfully")
-	// Инвалидируем кэш для этого инцидента (на всякий случай, хотя его еще нет)
-	if err := s.repo.InvalidateIncidentCache(ctx, incident.ID); err != nil {
-		log.WithError(err).Warn("Failed to invalidate incident cache after creation")
+	// Зона хранится как центр и радиус в geography, поэтому переход через 180-й меридиан на поиск не влияет
+	if geo.CircleBBox(geo.Point{Lat: incident.Latitude, Lon: incident.Longitude}, float64(incident.RadiusMeters)).CrossesAntimeridian() {
+		log.WithField("incident_id", incident.ID).Info("Incident zone crosses the antimeridian")
 	}
+	// Инвалидируем кэш для этого инцидента (на всякий случай, хотя его еще нет)
+	invalidateIncidentCache(ctx, s.repo, log, incident.ID)
+	s.invalidateLocationTiles(ctx, log)
 	// TODO: Инвалидировать кеш для списка инцидентов, если он будет реализован
 	return nil
 }
@@ -118,6 +308,21 @@ func (s *incidentService) GetIncident(ctx context.Context, id uuid.UUID) (*model
 	return incident, nil
 }
 
+// GetIncidentByExternalID возвращает инцидент по идентификатору во внешней системе.
+// Кэш инцидентов индексирован по UUID, поэтому запрос всегда идет в БД.
+func (s *incidentService) GetIncidentByExternalID(ctx context.Context, externalID string) (*models.Incident, error) {
+	incident, err := s.repo.GetByExternalID(ctx, externalID)
+	if err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"service":     "incident",
+			"method":      "GetIncidentByExternalID",
+			"external_id": externalID,
+		}).WithError(err).Warn("Failed to get incident by external id from repository")
+		return nil, fmt.Errorf("service: could not get incident by external id: %w", err)
+	}
+	return incident, nil
+}
+
 // UpdateIncident обновляет существующий инцидент.
 func (s *incidentService) UpdateIncident(ctx context.Context, incident *models.Incident) error {
 	log := s.logger.WithFields(logrus.Fields{
@@ -131,13 +336,46 @@ func (s *incidentService) UpdateIncident(ctx context.Context, incident *models.I
 		log.WithError(err).Warn("Attempted to update a non-existent incident")
 		return fmt.Errorf("service: incident with id %s not found for update: %w", incident.ID, err)
 	}
+	if err := s.checkCoordinates(incident.Latitude, incident.Longitude); err != nil {
+		log.WithError(err).Warn("Incident update rejected by coordinate regions")
+		return err
+	}
+	if err := checkStatusTransition(existing.Status, incident.Status); err != nil {
+		log.WithError(err).Warn("Incident update rejected by status transition rules")
+		return err
+	}
 
+	if err := s.sanitizeIncident(incident); err != nil {
+		log.WithError(err).Warn("Incident rejected after sanitization")
+		return err
+	}
+	if err := s.applyCategoryRules(incident); err != nil {
+		log.WithError(err).Warn("Incident rejected by category rules")
+		return err
+	}
+
+	before := *existing
 	existing.Name = incident.Name
 	existing.Description = incident.Description
 	existing.Latitude = incident.Latitude
 	existing.Longitude = incident.Longitude
 	existing.RadiusMeters = incident.RadiusMeters
 	existing.Status = incident.Status
+	existing.MediaURLs = incident.MediaURLs
+	if incident.Source != "" {
+		existing.Source = incident.Source
+	}
+	if incident.ExternalID != "" {
+		existing.ExternalID = incident.ExternalID
+	}
+	if incident.Severity != "" {
+		existing.Severity = incident.Severity
+	}
+	existing.Confidence = incident.Confidence
+	existing.Silent = incident.Silent
+	existing.Public = incident.Public
+	existing.ResolutionReason = incident.ResolutionReason
+	existing.Category = incident.Category
 
 	if err := s.repo.Update(ctx, existing); err != nil {
 		log.WithError(err).Error("Failed to update incident in repository")
@@ -146,47 +384,88 @@ func (s *incidentService) UpdateIncident(ctx context.Context, incident *models.I
 	log.Info("Incident updated successfully")
 
 	// Инвалидируем кэш для обновленного инцидента
-	if err := s.repo.InvalidateIncidentCache(ctx, incident.ID); err != nil {
-		log.WithError(err).Warn("Failed to invalidate incident cache after update")
+	invalidateIncidentCache(ctx, s.repo, log, incident.ID)
+	s.invalidateLocationTiles(ctx, log)
+
+	// Вебхук отправляется, только если значения полей действительно изменились
+	changes := diffIncidents(&before, existing)
+	if len(changes) == 0 {
+		return nil
+	}
+	event := webhook.WebhookEvent{
+		SchemaVersion: webhook.SchemaVersion,
+		Type:          webhook.EventTypeIncidentUpdated,
+		Latitude:      existing.Latitude,
+		Longitude:     existing.Longitude,
+		Timestamp:     time.Now(),
+		Incidents:     []*models.Incident{existing},
+		ChangedFields: changes,
+	}
+	if err := s.webhookPublisher.Publish(ctx, event); err != nil {
+		log.WithError(err).Error("Failed to publish incident updated webhook")
+		// Это не критическая ошибка, продолжаем выполнение
 	}
 	return nil
 }
 
-// DeactivateIncident дективирует инцидент
-func (s *incidentService) DeactivateIncident(ctx context.Context, id uuid.UUID) error {
+// DeactivateIncident дективирует инцидент: переводит его в статус inactive или, если инцидент
+// завершился естественным образом, в resolved с необязательной причиной, и публикует вебхук incident_deactivated.
+// Переход подчиняется тем же правилам, что и ручное обновление статуса (checkStatusTransition):
+// недопустимый переход, в том числе из resolved, возвращает ErrStatusConflict.
+func (s *incidentService) DeactivateIncident(ctx context.Context, id uuid.UUID, resolution models.Resolution) error {
 	log := s.logger.WithFields(logrus.Fields{
 		"service":     "incident",
 		"method":      "DeactivateIncident",
 		"incident_id": id,
+		"to_status":   resolution.Status(),
 	})
 	log.Info("Attempting to deactivate incident")
 
-	if _, err := s.repo.GetByID(ctx, id); err != nil {
+	incident, err := s.repo.GetByID(ctx, id)
+	if err != nil {
 		log.WithError(err).Warn("Attempted to deactivate a non-existent incident")
 		return fmt.Errorf("service: incident with id %s not found for deactivate: %w", id, err)
 	}
 
-	if err := s.repo.Delete(ctx, id); err != nil {
+	if err := checkStatusTransition(incident.Status, resolution.Status()); err != nil {
+		log.WithError(err).WithField("from_status", incident.Status).Warn("Incident deactivation rejected by status transition rules")
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, id, incident.Status, resolution); err != nil {
 		log.WithError(err).Error("Failed to deactivate incident in repository")
 		return fmt.Errorf("service: could not deactivate incident: %w", err)
 	}
 
 	log.Info("Incident deactivated successfully")
 	// Инвалидируем кэш для деактивированного инцидента
-	if err := s.repo.InvalidateIncidentCache(ctx, id); err != nil {
-		log.WithError(err).Warn("Failed to invalidate incident cache after deactivation")
+	invalidateIncidentCache(ctx, s.repo, log, id)
+	s.invalidateLocationTiles(ctx, log)
+
+	incident.Status = resolution.Status()
+	incident.ResolutionReason = resolution.Reason
+	event := webhook.WebhookEvent{
+		SchemaVersion: webhook.SchemaVersion,
+		Type:          webhook.EventTypeIncidentDeactivated,
+		Latitude:      incident.Latitude,
+		Longitude:     incident.Longitude,
+		Timestamp:     time.Now(),
+		Incidents:     []*models.Incident{incident},
+	}
+	if err := s.webhookPublisher.Publish(ctx, event); err != nil {
+		log.WithError(err).Error("Failed to publish incident deactivated webhook")
+		// Это не критическая ошибка, продолжаем выполнение
 	}
 	return nil
-
 }
 
-// ListIncidents возвращает список инцидентов с пагинацией
-func (s *incidentService) ListIncidents(ctx context.Context, page, pageSize int) ([]*models.Incident, error) {
+// ListIncidents возвращает список инцидентов с пагинацией и фильтрами
+func (s *incidentService) ListIncidents(ctx context.Context, page, pageSize int, filter models.IncidentFilter) ([]*models.Incident, error) {
 	if page < 1 {
 		page = 1
 	}
 
-	if pageSize < 1 || pageSize > 100 {
+	if pageSize < 1 || pageSize > MaxPageSize {
 		pageSize = 20
 	}
 
@@ -198,7 +477,13 @@ func (s *incidentService) ListIncidents(ctx context.Context, page, pageSize int)
 	})
 	log.Info("Listing incidents")
 
-	incidents, err := s.repo.ListIncidents(ctx, page, pageSize)
+	if filter.Near != nil {
+		log = log.WithField("near", *filter.Near)
+	}
+	if filter.CreatedBy != "" {
+		log = log.WithField("created_by", filter.CreatedBy)
+	}
+	incidents, err := s.repo.ListIncidents(ctx, page, pageSize, filter)
 	if err != nil {
 		log.WithError(err).Error("Failed to list incidents from repository")
 		return nil, fmt.Errorf("service: could not list incidents: %w", err)
@@ -208,47 +493,130 @@ func (s *incidentService) ListIncidents(ctx context.Context, page, pageSize int)
 	return incidents, nil
 }
 
-// CheckLocation находит активные инциденты и публикует вебхук при наличии опасности
-func (s *incidentService) CheckLocation(ctx context.Context, userID string, lat, lon float64) ([]*models.Incident, error) {
+// IncidentsLastModified возвращает время последнего изменения списка инцидентов (нулевое, если инцидентов нет).
+// Удаление старых неактивных инцидентов очисткой это время не сдвигает.
+func (s *incidentService) IncidentsLastModified(ctx context.Context) (time.Time, error) {
+	updatedAt, err := s.repo.MaxUpdatedAt(ctx)
+	if err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"service": "incident",
+			"method":  "IncidentsLastModified",
+		}).WithError(err).Error("Failed to get last incident modification time")
+		return time.Time{}, fmt.Errorf("service: could not get last modification time: %w", err)
+	}
+	return updatedAt, nil
+}
+
+// IncidentsSnapshotTime возвращает момент снимка для постраничного чтения списка инцидентов. Время берется у базы,
+// которая проставляет created_at, поэтому расхождение часов сервиса и базы не сдвигает страницы.
+func (s *incidentService) IncidentsSnapshotTime(ctx context.Context) (time.Time, error) {
+	now, err := s.repo.CurrentTime(ctx)
+	if err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"service": "incident",
+			"method":  "IncidentsSnapshotTime",
+		}).WithError(err).Error("Failed to get database time")
+		return time.Time{}, fmt.Errorf("service: could not get snapshot time: %w", err)
+	}
+	return now, nil
+}
+
+// CheckLocation находит активные инциденты и публикует вебхук при наличии опасности.
+// bufferMeters расширяет радиус каждого инцидента для компенсации погрешности GPS
+// и ограничивается сверху значением MaxCheckBufferMeters из конфигурации.
+// Инциденты возвращаются по убыванию уровня опасности, а среди равных - по удаленности; при MAX_MATCHED_INCIDENTS
+// больше нуля возвращается не больше этого числа. Вторым значением возвращается число всех совпавших инцидентов.
+func (s *incidentService) CheckLocation(ctx context.Context, userID string, authenticated bool, lat, lon float64, bufferMeters, minConfidence int) ([]*models.Incident, int, error) {
 	log := s.logger.WithFields(logrus.Fields{
-		"service": "incident",
-		"method":  "CheckLocation",
-		"user_id": userID,
+		"service":       "incident",
+		"method":        "CheckLocation",
+		"user_id":       userID,
+		"authenticated": authenticated,
 	})
 	log.Info("Checking user location")
+	start := time.Now()
 
-	activeIncident, err := s.repo.FindActiveLocation(ctx, lat, lon)
+	if err := s.checkCoordinates(lat, lon); err != nil {
+		log.WithError(err).Warn("Location check rejected by coordinate regions")
+		return nil, 0, err
+	}
+	if err := s.reserveUserCheck(ctx, userID, log); err != nil {
+		return nil, 0, err
+	}
+	if bufferMeters < 0 {
+		bufferMeters = 0
+	}
+	if bufferMeters > s.cfg.MaxCheckBufferMeters {
+		log.WithField("buffer_meters", bufferMeters).Debug("Buffer exceeds configured maximum, capping")
+		bufferMeters = s.cfg.MaxCheckBufferMeters
+	}
+
+	// Сопоставление двухступенчатое: ответ и сохраненная проверка содержат инциденты, в зону которых попадает точка,
+	// а вебхук - еще и инциденты, до зоны которых не больше буфера оповещения их уровня опасности
+	activeIncident, alerted, err := s.findActiveIncidents(ctx, lat, lon, bufferMeters, minConfidence)
 	if err != nil {
 		log.WithError(err).Error("Failed to find active incidents by location")
-		return nil, fmt.Errorf("service: failed to find active incidents: %w", err)
+		return nil, 0, fmt.Errorf("service: failed to find active incidents: %w", err)
 	}
+	point := geo.Point{Lat: lat, Lon: lon}
 	isDanger := len(activeIncident) > 0
 
-	// Сохраняем факт проверки местоположения
+	// Сохраняем факт проверки местоположения вместе с совпавшими инцидентами
+	incidentIDs := make([]uuid.UUID, len(activeIncident))
+	for i, incident := range activeIncident {
+		incidentIDs[i] = incident.ID
+	}
 	locationCheck := &models.LocationCheck{
-		UserID:      userID,
-		Latitude:    lat,
-		Longitude:   lon,
-		IsDangerous: isDanger,
+		UserID:        userID,
+		Latitude:      lat,
+		Longitude:     lon,
+		IsDangerous:   isDanger,
+		Authenticated: authenticated,
+		IncidentIDs:   incidentIDs,
+	}
+
+	// Вебхук отправляется, если обнаружена опасность (с учетом буферов оповещения). Беззвучные инциденты в вебхук не попадают.
+	notifiable := make([]*models.Incident, 0, len(alerted))
+	for _, incident := range alerted {
+		if !incident.Silent {
+			notifiable = append(notifiable, incident)
+		}
+	}
+	var webhookEvent *webhook.WebhookEvent
+	if len(notifiable) > 0 {
+		webhookEvent = &webhook.WebhookEvent{
+			SchemaVersion: webhook.SchemaVersion,
+			Type:          webhook.EventTypeLocationCheck,
+			UserID:        userID,
+			Latitude:      lat,
+			Longitude:     lon,
+			IsDangerous:   true,
+			Timestamp:     time.Now(),
+			Incidents:     notifiable,
+		}
 	}
-	if err := s.repo.SaveLocationCheck(ctx, locationCheck); err != nil {
+
+	// С outbox событие сохраняется в одной транзакции с проверкой и публикуется ретранслятором
+	var outbox *models.OutboxMessage
+	if webhookEvent != nil && s.cfg.WebhookOutboxEnabled {
+		payload, err := json.Marshal(webhookEvent)
+		if err != nil {
+			log.WithError(err).Error("Failed to marshal webhook event for outbox")
+		} else {
+			outbox = &models.OutboxMessage{Payload: payload}
+		}
+	}
+	if err := s.repo.SaveLocationCheck(ctx, locationCheck, outbox); err != nil {
 		log.WithError(err).Error("Failed to save location check to repository")
-		// Это не критическая ошибка, продолжаем выполнение
+		// Это не критическая ошибка, продолжаем выполнение. Событие не попало в outbox вместе с проверкой,
+		// поэтому публикуется напрямую, иначе оповещение об опасности было бы потеряно
+		outbox = nil
 	}
 
 	log.WithField("is_danger", isDanger).Info("Location check completed")
 
-	// Публикуем вебхук, если обнаружена опасность
-	if isDanger {
-		webhookEvent := webhook.WebhookEvent{
-			UserID:      userID,
-			Latitude:    lat,
-			Longitude:   lon,
-			IsDangerous: isDanger,
-			Timestamp:   time.Now(),
-			Incidents:   activeIncident,
-		}
-		if err := s.webhookPublisher.Publish(ctx, webhookEvent); err != nil {
+	if webhookEvent != nil && outbox == nil {
+		if err := s.webhookPublisher.Publish(ctx, *webhookEvent); err != nil {
 			log.WithError(err).Error("Failed to publish webhook event")
 			// Это не критическая ошибка, продолжаем выполнение
 		} else {
@@ -256,7 +624,16 @@ func (s *incidentService) CheckLocation(ctx context.Context, userID string, lat,
 		}
 	}
 
-	return activeIncident, nil
+	matched := len(activeIncident)
+	locationCheckDuration.WithLabelValues(matchedIncidentsBucket(matched)).Observe(time.Since(start).Seconds())
+
+	// Ответ ограничивается после сохранения проверки и публикации вебхука: они содержат все совпавшие инциденты
+	sortIncidentsByPriority(activeIncident, point)
+	if limit := s.cfg.MaxMatchedIncidents; limit > 0 && matched > limit {
+		log.WithFields(logrus.Fields{"matched": matched, "limit": limit}).Debug("Truncating matched incidents")
+		activeIncident = activeIncident[:limit]
+	}
+	return activeIncident, matched, nil
 }
 
 // GetStats возвращает количество уникальных пользователей, проверивших геолокацию
@@ -267,12 +644,203 @@ func (s *incidentService) GetStats(ctx context.Context) (int, error) {
 	})
 	log.Info("Getting location check stats")
 
+	ttl := s.cfg.StatsCacheTTL
+	if ttl <= 0 {
+		snapshot, err := s.computeStats(ctx)
+		if err != nil {
+			return 0, err
+		}
+		return snapshot.UserCount, nil
+	}
+
+	cached, err := s.repo.GetStatsFromCache(ctx, s.cfg.StatsTimeWindowMinutes)
+	if err != nil {
+		log.WithError(err).Warn("Failed to get stats from cache")
+	}
+	if cached != nil {
+		// Во второй половине срока жизни отдаем кэш и обновляем его в фоне (stale-while-revalidate)
+		if time.Since(cached.ComputedAt) >= ttl/2 {
+			s.refreshStatsInBackground(ctx)
+		}
+		log.WithField("user_count", cached.UserCount).Debug("Location check stats served from cache")
+		return cached.UserCount, nil
+	}
+
+	snapshot, err := s.computeStats(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if err := s.repo.SetStatsCache(ctx, s.cfg.StatsTimeWindowMinutes, snapshot, ttl); err != nil {
+		log.WithError(err).Warn("Failed to set stats cache")
+	}
+	return snapshot.UserCount, nil
+}
+
+// computeStats вычисляет статистику проверок запросом к базе данных
+func (s *incidentService) computeStats(ctx context.Context) (*models.StatsSnapshot, error) {
+	log := s.logger.WithFields(logrus.Fields{
+		"service": "incident",
+		"method":  "computeStats",
+	})
+
 	userCount, err := s.repo.GetLocationCheckStats(ctx, s.cfg.StatsTimeWindowMinutes)
 	if err != nil {
 		log.WithError(err).Error("Failed to get location check stats from repository")
-		return 0, fmt.Errorf("service: failed to get location check stats: %w", err)
+		return nil, fmt.Errorf("service: failed to get location check stats: %w", err)
 	}
 
 	log.WithField("user_count", userCount).Info("Location check stats retrieved successfully")
+	return &models.StatsSnapshot{UserCount: userCount, ComputedAt: time.Now()}, nil
+}
+
+// refreshStatsInBackground пересчитывает статистику и обновляет кэш в отдельной горутине.
+// Одновременно выполняется не более одного обновления; контекст запроса не используется
+// для отмены, так как ответ клиенту уже отправлен из кэша.
+func (s *incidentService) refreshStatsInBackground(ctx context.Context) {
+	if !s.statsRefreshing.CompareAndSwap(false, true) {
+		return
+	}
+	go func() {
+		defer s.statsRefreshing.Store(false)
+		refreshCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), statsRefreshTimeout)
+		defer cancel()
+
+		snapshot, err := s.computeStats(refreshCtx)
+		if err != nil {
+			return
+		}
+		if err := s.repo.SetStatsCache(refreshCtx, s.cfg.StatsTimeWindowMinutes, snapshot, s.cfg.StatsCacheTTL); err != nil {
+			s.logger.WithError(err).Warn("Failed to refresh stats cache")
+		}
+	}()
+}
+
+// GetIncidentImpact возвращает оценку числа затронутых инцидентом пользователей:
+// количество уникальных пользователей, чьи проверки попали в зону инцидента за окно статистики.
+// Учитываются только пользователи, которые сами выполняли проверку местоположения.
+func (s *incidentService) GetIncidentImpact(ctx context.Context, id uuid.UUID) (int, error) {
+	log := s.logger.WithFields(logrus.Fields{
+		"service":     "incident",
+		"method":      "GetIncidentImpact",
+		"incident_id": id,
+	})
+	log.Info("Getting incident impact")
+
+	if _, err := s.repo.GetByID(ctx, id); err != nil {
+		log.WithError(err).Warn("Failed to get incident for impact estimate")
+		return 0, fmt.Errorf("service: could not get incident: %w", err)
+	}
+
+	userCount, err := s.repo.GetIncidentImpact(ctx, id, s.cfg.StatsTimeWindowMinutes)
+	if err != nil {
+		log.WithError(err).Error("Failed to get incident impact from repository")
+		return 0, fmt.Errorf("service: failed to get incident impact: %w", err)
+	}
+
+	log.WithField("user_count", userCount).Info("Incident impact retrieved successfully")
+	return userCount, nil
+}
+
+// PreviewIncidentImpact оценивает, скольких пользователей затронул бы гипотетический инцидент:
+// количество уникальных пользователей, чьи проверки за окно статистики попали в зону радиусом radiusMeters.
+// В отличие от GetIncidentImpact, зона не обязана существовать, и проверки берутся по координатам, а не по совпадениям.
+func (s *incidentService) PreviewIncidentImpact(ctx context.Context, lat, lon float64, radiusMeters int) (int, error) {
+	log := s.logger.WithFields(logrus.Fields{
+		"service":       "incident",
+		"method":        "PreviewIncidentImpact",
+		"latitude":      lat,
+		"longitude":     lon,
+		"radius_meters": radiusMeters,
+	})
+	log.Info("Previewing incident impact")
+
+	if err := s.checkCoordinates(lat, lon); err != nil {
+		log.WithError(err).Warn("Impact preview rejected")
+		return 0, err
+	}
+
+	userCount, err := s.repo.CountRecentUsersInArea(ctx, lat, lon, radiusMeters, s.cfg.StatsTimeWindowMinutes)
+	if err != nil {
+		log.WithError(err).Error("Failed to count users in area from repository")
+		return 0, fmt.Errorf("service: failed to preview incident impact: %w", err)
+	}
+
+	log.WithField("user_count", userCount).Info("Incident impact preview retrieved successfully")
 	return userCount, nil
 }
+
+// GetWebhookQueueDepth возвращает число событий вебхуков, ожидающих доставки
+func (s *incidentService) GetWebhookQueueDepth(ctx context.Context) (int64, error) {
+	depth, err := s.webhookPublisher.QueueDepth(ctx)
+	if err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"service": "incident",
+			"method":  "GetWebhookQueueDepth",
+		}).WithError(err).Error("Failed to get webhook queue depth")
+		return 0, fmt.Errorf("service: could not get webhook queue depth: %w", err)
+	}
+	return depth, nil
+}
+
+// GetWebhookCircuits возвращает состояния цепей адресов доставки вебхуков и число недоставленных вебхуков
+func (s *incidentService) GetWebhookCircuits(ctx context.Context) ([]webhook.CircuitStatus, int64, error) {
+	log := s.logger.WithFields(logrus.Fields{
+		"service": "incident",
+		"method":  "GetWebhookCircuits",
+	})
+
+	circuits, err := s.webhookPublisher.CircuitStates(ctx)
+	if err != nil {
+		log.WithError(err).Error("Failed to get webhook circuit states")
+		return nil, 0, fmt.Errorf("service: could not get webhook circuit states: %w", err)
+	}
+	deadLetters, err := s.webhookPublisher.DeadLetterDepth(ctx)
+	if err != nil {
+		log.WithError(err).Error("Failed to get webhook dead letter depth")
+		return nil, 0, fmt.Errorf("service: could not get webhook dead letter depth: %w", err)
+	}
+	return circuits, deadLetters, nil
+}
+
+// GetUserLocationHistory возвращает историю проверок местоположения пользователя с пагинацией
+func (s *incidentService) GetUserLocationHistory(ctx context.Context, userID string, page, pageSize int) ([]*models.LocationCheck, error) {
+	if page < 1 {
+		page = 1
+	}
+
+	if pageSize < 1 || pageSize > MaxPageSize {
+		pageSize = 20
+	}
+
+	log := s.logger.WithFields(logrus.Fields{
+		"service":   "incident",
+		"method":    "GetUserLocationHistory",
+		"user_id":   userID,
+		"page":      page,
+		"page_size": pageSize,
+	})
+	log.Info("Listing user location checks")
+
+	checks, err := s.repo.ListLocationChecksByUser(ctx, userID, page, pageSize)
+	if err != nil {
+		log.WithError(err).Error("Failed to list location checks from repository")
+		return nil, fmt.Errorf("service: could not list location checks: %w", err)
+	}
+
+	log.WithField("count", len(checks)).Info("User location checks listed successfully")
+	return checks, nil
+}
+
+// sanitizeIncident очищает название и описание инцидента от HTML и управляющих символов,
+// если это включено в конфигурации (SANITIZE_INCIDENT_TEXT)
+func (s *incidentService) sanitizeIncident(incident *models.Incident) error {
+	if !s.cfg.SanitizeIncidentText {
+		return nil
+	}
+	incident.Name = sanitizeText(incident.Name, false)
+	incident.Description = sanitizeText(incident.Description, true)
+	if incident.Name == "" {
+		return fmt.Errorf("service: incident name is empty after sanitization: %w", ErrInvalidIncident)
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/shenikar/geo_broadcasting_system/internal/config"
+	"github.com/shenikar/geo_broadcasting_system/internal/models"
+	"github.com/shenikar/geo_broadcasting_system/pkg/geo"
+	"github.com/sirupsen/logrus"
+)
+
+// indexStaleIntervals - через сколько периодов обновления без успешного перестроения снимок индекса считается устаревшим
+const indexStaleIntervals = 2
+
+// ActiveIncidentIndex - индекс активных инцидентов в памяти процесса (R-дерево по зонам инцидентов с максимальным буфером),
+// перестраиваемый в фоне с периодом LOCATION_INDEX_REFRESH_INTERVAL. Проверка местоположения сначала обращается к индексу
+// и идет в кэш ячеек или PostGIS, пока индекс не построен, сброшен после изменения инцидентов или устарел.
+// Изменения, сделанные другими экземплярами сервиса, становятся видны не позже следующего перестроения.
+// Индекс только сужает набор кандидатов: прямоугольники зон покрывают их и на эллипсоиде, а попадание точки в зону
+// проверяется по геодезической WGS84 (filterIncidentsByLocation), как в ST_DWithin по geography.
+type ActiveIncidentIndex struct {
+	repo   IncidentRepository
+	logger *logrus.Logger
+	cfg    *config.Config
+
+	current atomic.Pointer[incidentIndexSnapshot]
+	// version увеличивается при каждом сбросе: снимок, начатый до сброса, не публикуется
+	version atomic.Int64
+	// refresh - запрос внеочередного перестроения после изменения инцидентов этим процессом
+	refresh chan struct{}
+}
+
+// incidentIndexSnapshot - неизменяемый снимок активных инцидентов с деревом их зон
+type incidentIndexSnapshot struct {
+	tree      *geo.RTree
+	incidents []*models.Incident
+	builtAt   time.Time
+}
+
+// NewActiveIncidentIndex создает индекс активных инцидентов; до вызова Start индекс пуст и не используется
+func NewActiveIncidentIndex(repo IncidentRepository, logger *logrus.Logger, cfg *config.Config) *ActiveIncidentIndex {
+	return &ActiveIncidentIndex{
+		repo:    repo,
+		logger:  logger,
+		cfg:     cfg,
+		refresh: make(chan struct{}, 1),
+	}
+}
+
+// Start запускает горутину перестроения индекса с периодом LOCATION_INDEX_REFRESH_INTERVAL.
+// При нулевом периоде индекс отключен и горутина не запускается.
+func (i *ActiveIncidentIndex) Start(ctx context.Context) {
+	if i.cfg.LocationIndexRefreshInterval <= 0 {
+		i.logger.Info("Active incident index is disabled (LOCATION_INDEX_REFRESH_INTERVAL=0)")
+		return
+	}
+
+	i.logger.WithField("interval", i.cfg.LocationIndexRefreshInterval).Info("Starting active incident index refresher...")
+	go func() {
+		ticker := time.NewTicker(i.cfg.LocationIndexRefreshInterval)
+		defer ticker.Stop()
+		for {
+			if err := i.Refresh(ctx); err != nil {
+				i.logger.WithError(err).Warn("Failed to refresh active incident index")
+			}
+			select {
+			case <-ctx.Done():
+				i.logger.Info("Stopping active incident index refresher.")
+				return
+			case <-ticker.C:
+			case <-i.refresh:
+			}
+		}
+	}()
+}
+
+// Refresh загружает активные инциденты и публикует новый снимок индекса
+func (i *ActiveIncidentIndex) Refresh(ctx context.Context) error {
+	version := i.version.Load()
+	incidents, err := i.repo.ListActiveIncidents(ctx)
+	if err != nil {
+		return fmt.Errorf("service: could not load active incidents: %w", err)
+	}
+
+	boxes := make([]geo.BBox, len(incidents))
+	for n, incident := range incidents {
+		// Запас в 1 м компенсирует погрешность вычислений на границе зоны; буфер оповещения нужен
+		// для отбора инцидентов вебхука (см. CheckLocation)
+		reach := float64(incident.RadiusMeters + i.cfg.MaxCheckBufferMeters + i.cfg.MaxAlertBufferMeters() + 1)
+		boxes[n] = geo.CircleBBox(geo.Point{Lat: incident.Latitude, Lon: incident.Longitude}, reach)
+	}
+	snapshot := &incidentIndexSnapshot{
+		tree:      geo.NewRTree(boxes),
+		incidents: incidents,
+		builtAt:   time.Now(),
+	}
+
+	if i.version.Load() != version {
+		// Инциденты изменились во время загрузки: снимок мог их не увидеть, перестроение уже запрошено
+		return nil
+	}
+	i.current.Store(snapshot)
+	i.logger.WithField("count", len(incidents)).Debug("Active incident index refreshed")
+	return nil
+}
+
+// Invalidate сбрасывает снимок после изменения инцидентов и запрашивает внеочередное перестроение.
+// До перестроения проверки идут мимо индекса.
+func (i *ActiveIncidentIndex) Invalidate() {
+	if i == nil {
+		return
+	}
+	i.version.Add(1)
+	i.current.Store(nil)
+	select {
+	case i.refresh <- struct{}{}:
+	default:
+	}
+}
+
+// candidates возвращает инциденты, чьи зоны с максимальным буфером могут содержать точку.
+// ok = false, если индекс отключен, не построен или устарел.
+func (i *ActiveIncidentIndex) candidates(p geo.Point) (incidents []*models.Incident, ok bool) {
+	if i == nil {
+		return nil, false
+	}
+	snapshot := i.current.Load()
+	if snapshot == nil || time.Since(snapshot.builtAt) > indexStaleIntervals*i.cfg.LocationIndexRefreshInterval {
+		return nil, false
+	}
+
+	ids := snapshot.tree.Search(p)
+	incidents = make([]*models.Incident, len(ids))
+	for n, id := range ids {
+		incidents[n] = snapshot.incidents[id]
+	}
+	return incidents, true
+}
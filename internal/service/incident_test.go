@@ -3,15 +3,27 @@ package service
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
 	"github.com/shenikar/geo_broadcasting_system/internal/config"
 	"github.com/shenikar/geo_broadcasting_system/internal/models"
 	"github.com/shenikar/geo_broadcasting_system/internal/service/mocks"
 	"github.com/shenikar/geo_broadcasting_system/internal/webhook"
 	webhook_mocks "github.com/shenikar/geo_broadcasting_system/internal/webhook/mocks"
+	"github.com/shenikar/geo_broadcasting_system/pkg/geo"
+	"github.com/shenikar/geo_broadcasting_system/pkg/netguard"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -29,10 +41,30 @@ func newTestIncidentService(t *testing.T) (*incidentService, *mocks.MockIncident
 
 	cfg := &config.Config{
 		StatsTimeWindowMinutes: 60,
+		MaxCheckBufferMeters:   100,
+		DefaultIncidentStatus:  "active",
+		SanitizeIncidentText:   true,
 	}
 
-	service := NewIncidentService(repoMock, logger, cfg, webhookMock)
-	return service.(*incidentService), repoMock, webhookMock
+	service := NewIncidentService(repoMock, logger, cfg, webhookMock, nil).(*incidentService)
+	service.resolver = testResolver{
+		"hooks.example.org": "203.0.113.10",
+		"internal.example":  "10.0.0.5",
+	}
+	// Тестовые ленты раздает httptest-сервер на loopback, который защищенный транспорт не пропускает
+	service.importTransport = http.DefaultTransport
+	return service, repoMock, webhookMock
+}
+
+// testResolver разрешает хосты по таблице без обращения к DNS
+type testResolver map[string]string
+
+func (r testResolver) LookupIPAddr(_ context.Context, host string) ([]net.IPAddr, error) {
+	ip, ok := r[strings.ToLower(host)]
+	if !ok {
+		return nil, fmt.Errorf("lookup %s: no such host", host)
+	}
+	return []net.IPAddr{{IP: net.ParseIP(ip)}}, nil
 }
 
 func TestGetIncident_Success_FromCache(t *testing.T) {
@@ -155,13 +187,157 @@ func TestCreateIncident_Success(t *testing.T) {
 
 	// Проверки
 	require.NoError(t, err)
-	assert.Equal(t, "active", incidentToCreate.Status)
+	assert.Equal(t, models.StatusActive, incidentToCreate.Status)
 	assert.NotEqual(t, uuid.Nil, incidentToCreate.ID)
 }
 
-func TestUpdateIncident_Success(t *testing.T) {
+func TestCreateIncident_ExplicitDraftStatus(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	ctx := context.Background()
+	incidentToCreate := &models.Incident{
+		Name:   "Черновик",
+		Status: "draft",
+	}
+
+	// Ожидания
+	repoMock.EXPECT().
+		Create(ctx, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, inc *models.Incident) error {
+			// Явно указанный статус не перезаписывается значением по умолчанию
+			assert.Equal(t, models.StatusDraft, inc.Status)
+			inc.ID = uuid.New()
+			return nil
+		}).Times(1)
+	repoMock.EXPECT().InvalidateIncidentCache(ctx, gomock.Any()).Return(nil).Times(1)
+
+	// Действие
+	err := service.CreateIncident(ctx, incidentToCreate)
+
+	// Проверки
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusDraft, incidentToCreate.Status)
+}
+
+func TestCreateIncident_DefaultSource(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	ctx := context.Background()
+	incidentToCreate := &models.Incident{Name: "Без источника"}
+
+	// Ожидания
+	repoMock.EXPECT().
+		Create(ctx, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, inc *models.Incident) error {
+			// Незаданный источник считается официальным
+			assert.Equal(t, models.SourceOfficial, inc.Source)
+			inc.ID = uuid.New()
+			return nil
+		}).Times(1)
+	repoMock.EXPECT().InvalidateIncidentCache(ctx, gomock.Any()).Return(nil).Times(1)
+
+	// Действие
+	err := service.CreateIncident(ctx, incidentToCreate)
+
+	// Проверки
+	require.NoError(t, err)
+	assert.Equal(t, models.SourceOfficial, incidentToCreate.Source)
+}
+
+func TestSanitizeText_MaliciousPayloads(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		multiline bool
+		expected  string
+	}{
+		{name: "script tag", input: `<script>alert('xss')</script>Пожар`, expected: "alert('xss')Пожар"},
+		{name: "img onerror", input: `<img src=x onerror=alert(1)>Зона`, expected: "Зона"},
+		{name: "unclosed tag", input: `<img src=x onerror=alert(1)`, expected: "&lt;img src=x onerror=alert(1)"},
+		{name: "html comment", input: `Зона<!-- <script>evil()</script> -->А`, expected: "ЗонаА"},
+		{name: "control characters", input: "Зона\x00\x07\x1bА", expected: "ЗонаА"},
+		{name: "newline in name", input: "Зона\nА", expected: "ЗонаА"},
+		{name: "newline in description", input: "Строка 1\n\tСтрока 2", multiline: true, expected: "Строка 1\n\tСтрока 2"},
+		{name: "plain comparison", input: "уровень воды > 2 м & растет", expected: "уровень воды &gt; 2 м & растет"},
+		{name: "idempotent", input: "уровень воды &gt; 2 м & растет", expected: "уровень воды &gt; 2 м & растет"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, sanitizeText(tt.input, tt.multiline))
+		})
+	}
+}
+
+func TestCreateIncident_SanitizesText(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	ctx := context.Background()
+	incidentToCreate := &models.Incident{
+		Name:        `<b onclick="evil()">Пожар</b>`,
+		Description: "<script>steal(document.cookie)</script>Эвакуация\x00",
+	}
+
+	// Ожидания
+	repoMock.EXPECT().
+		Create(ctx, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, inc *models.Incident) error {
+			assert.Equal(t, "Пожар", inc.Name)
+			assert.Equal(t, "steal(document.cookie)Эвакуация", inc.Description)
+			inc.ID = uuid.New()
+			return nil
+		}).Times(1)
+	repoMock.EXPECT().InvalidateIncidentCache(ctx, gomock.Any()).Return(nil).Times(1)
+
+	// Действие
+	err := service.CreateIncident(ctx, incidentToCreate)
+
+	// Проверки
+	require.NoError(t, err)
+}
+
+func TestCreateIncident_EmptyNameAfterSanitization(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	ctx := context.Background()
+
+	// Ожидания
+	repoMock.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
+
+	// Действие
+	err := service.CreateIncident(ctx, &models.Incident{Name: "<script></script>"})
+
+	// Проверки
+	assert.ErrorIs(t, err, ErrInvalidIncident)
+}
+
+func TestCreateIncident_SanitizationDisabled(t *testing.T) {
 	// Подготовка
 	service, repoMock, _ := newTestIncidentService(t)
+	service.cfg.SanitizeIncidentText = false
+	ctx := context.Background()
+	rawName := "<b>Пожар</b>"
+
+	// Ожидания
+	repoMock.EXPECT().
+		Create(ctx, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, inc *models.Incident) error {
+			assert.Equal(t, rawName, inc.Name)
+			inc.ID = uuid.New()
+			return nil
+		}).Times(1)
+	repoMock.EXPECT().InvalidateIncidentCache(ctx, gomock.Any()).Return(nil).Times(1)
+
+	// Действие
+	err := service.CreateIncident(ctx, &models.Incident{Name: rawName})
+
+	// Проверки
+	require.NoError(t, err)
+}
+
+func TestUpdateIncident_Success(t *testing.T) {
+	// Подготовка
+	service, repoMock, webhookMock := newTestIncidentService(t)
 	ctx := context.Background()
 	incidentID := uuid.New()
 	incidentToUpdate := &models.Incident{
@@ -177,6 +353,14 @@ func TestUpdateIncident_Success(t *testing.T) {
 	repoMock.EXPECT().GetByID(ctx, incidentID).Return(existingIncident, nil).Times(1)
 	repoMock.EXPECT().Update(ctx, gomock.Any()).Return(nil).Times(1)
 	repoMock.EXPECT().InvalidateIncidentCache(ctx, incidentID).Return(nil).Times(1)
+	webhookMock.EXPECT().
+		Publish(ctx, gomock.Any()).
+		Do(func(ctx context.Context, event webhook.WebhookEvent) {
+			assert.Equal(t, webhook.EventTypeIncidentUpdated, event.Type)
+			assert.Equal(t, map[string]webhook.FieldChange{
+				"name": {Old: "Старое имя", New: "Обновленное имя"},
+			}, event.ChangedFields)
+		}).Return(nil).Times(1)
 
 	// Действие
 	err := service.UpdateIncident(ctx, incidentToUpdate)
@@ -185,6 +369,28 @@ func TestUpdateIncident_Success(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestUpdateIncident_NoChangesSkipsWebhook(t *testing.T) {
+	// Подготовка
+	service, repoMock, webhookMock := newTestIncidentService(t)
+	ctx := context.Background()
+	incidentID := uuid.New()
+	confidence := 80
+	existing := &models.Incident{ID: incidentID, Name: "Пожар", Status: models.StatusActive, Severity: models.SeverityMinor, Confidence: &confidence}
+	sameConfidence := 80
+
+	// Ожидания
+	repoMock.EXPECT().GetByID(ctx, incidentID).Return(existing, nil).Times(1)
+	repoMock.EXPECT().Update(ctx, gomock.Any()).Return(nil).Times(1)
+	repoMock.EXPECT().InvalidateIncidentCache(ctx, incidentID).Return(nil).Times(1)
+	webhookMock.EXPECT().Publish(gomock.Any(), gomock.Any()).Times(0)
+
+	// Действие
+	err := service.UpdateIncident(ctx, &models.Incident{ID: incidentID, Name: "Пожар", Status: models.StatusActive, Confidence: &sameConfidence})
+
+	// Проверки
+	require.NoError(t, err)
+}
+
 func TestUpdateIncident_NotFound(t *testing.T) {
 	// Подготовка
 	service, repoMock, _ := newTestIncidentService(t)
@@ -206,18 +412,27 @@ func TestUpdateIncident_NotFound(t *testing.T) {
 
 func TestDeactivateIncident_Success(t *testing.T) {
 	// Подготовка
-	service, repoMock, _ := newTestIncidentService(t)
+	service, repoMock, webhookMock := newTestIncidentService(t)
 	ctx := context.Background()
 	incidentID := uuid.New()
-	existingIncident := &models.Incident{ID: incidentID}
+	existingIncident := &models.Incident{ID: incidentID, Status: models.StatusActive}
 
 	// Ожидания
 	repoMock.EXPECT().GetByID(ctx, incidentID).Return(existingIncident, nil).Times(1)
-	repoMock.EXPECT().Delete(ctx, incidentID).Return(nil).Times(1)
+	repoMock.EXPECT().Delete(ctx, incidentID, models.StatusActive, models.Resolution{}).Return(nil).Times(1)
 	repoMock.EXPECT().InvalidateIncidentCache(ctx, incidentID).Return(nil).Times(1)
+	// Наблюдатели и подписчики узнают о деактивации из вебхука
+	webhookMock.EXPECT().
+		Publish(ctx, gomock.Any()).
+		Do(func(ctx context.Context, event webhook.WebhookEvent) {
+			assert.Equal(t, webhook.EventTypeIncidentDeactivated, event.Type)
+			require.Len(t, event.Incidents, 1)
+			assert.Equal(t, incidentID, event.Incidents[0].ID)
+			assert.Equal(t, models.StatusInactive, event.Incidents[0].Status)
+		}).Return(nil).Times(1)
 
 	// Действие
-	err := service.DeactivateIncident(ctx, incidentID)
+	err := service.DeactivateIncident(ctx, incidentID, models.Resolution{})
 
 	// Проверки
 	require.NoError(t, err)
@@ -234,13 +449,92 @@ func TestDeactivateIncident_NotFound(t *testing.T) {
 	repoMock.EXPECT().GetByID(ctx, incidentID).Return(nil, repoError).Times(1)
 
 	// Действие
-	err := service.DeactivateIncident(ctx, incidentID)
+	err := service.DeactivateIncident(ctx, incidentID, models.Resolution{})
 
 	// Проверки
 	require.Error(t, err)
 	assert.ErrorContains(t, err, "not found for deactivate")
 }
 
+func TestDeactivateIncident_AlreadyResolved(t *testing.T) {
+	// Подготовка
+	service, repoMock, webhookMock := newTestIncidentService(t)
+	ctx := context.Background()
+	incidentID := uuid.New()
+
+	// Ожидания: resolved - конечный статус, инцидент не снимается повторно
+	repoMock.EXPECT().GetByID(ctx, incidentID).Return(&models.Incident{ID: incidentID, Status: models.StatusResolved}, nil).Times(1)
+	repoMock.EXPECT().Delete(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	webhookMock.EXPECT().Publish(gomock.Any(), gomock.Any()).Times(0)
+
+	// Действие
+	err := service.DeactivateIncident(ctx, incidentID, models.Resolution{})
+
+	// Проверки
+	assert.ErrorIs(t, err, ErrStatusConflict)
+}
+
+func TestDeactivateIncident_Draft(t *testing.T) {
+	// Подготовка
+	service, repoMock, webhookMock := newTestIncidentService(t)
+	ctx := context.Background()
+	incidentID := uuid.New()
+	draft := &models.Incident{ID: incidentID, Status: models.StatusDraft}
+
+	// Ожидания: черновик можно только снять (inactive), но не завершить (resolved)
+	repoMock.EXPECT().GetByID(ctx, incidentID).Return(draft, nil).Times(2)
+	repoMock.EXPECT().Delete(ctx, incidentID, models.StatusDraft, models.Resolution{}).Return(nil).Times(1)
+	repoMock.EXPECT().InvalidateIncidentCache(ctx, incidentID).Return(nil).Times(1)
+	webhookMock.EXPECT().Publish(ctx, gomock.Any()).Return(nil).Times(1)
+
+	// Действие
+	errResolved := service.DeactivateIncident(ctx, incidentID, models.Resolution{Resolved: true})
+	errWithdrawn := service.DeactivateIncident(ctx, incidentID, models.Resolution{})
+
+	// Проверки
+	assert.ErrorIs(t, errResolved, ErrStatusConflict)
+	require.NoError(t, errWithdrawn)
+}
+
+func TestDeactivateIncident_Resolved(t *testing.T) {
+	// Подготовка
+	service, repoMock, webhookMock := newTestIncidentService(t)
+	ctx := context.Background()
+	incidentID := uuid.New()
+	resolution := models.Resolution{Resolved: true, Reason: "пожар потушен"}
+
+	// Ожидания
+	repoMock.EXPECT().GetByID(ctx, incidentID).Return(&models.Incident{ID: incidentID, Status: models.StatusActive}, nil).Times(1)
+	repoMock.EXPECT().Delete(ctx, incidentID, models.StatusActive, resolution).Return(nil).Times(1)
+	repoMock.EXPECT().InvalidateIncidentCache(ctx, incidentID).Return(nil).Times(1)
+	webhookMock.EXPECT().
+		Publish(ctx, gomock.Any()).
+		Do(func(ctx context.Context, event webhook.WebhookEvent) {
+			assert.Equal(t, models.StatusResolved, event.Incidents[0].Status)
+			assert.Equal(t, "пожар потушен", event.Incidents[0].ResolutionReason)
+		}).Return(nil).Times(1)
+
+	// Действие
+	err := service.DeactivateIncident(ctx, incidentID, resolution)
+
+	// Проверки
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusResolved, resolution.Status())
+	assert.Equal(t, models.StatusInactive, models.Resolution{}.Status())
+}
+
+func TestIncidentStatus(t *testing.T) {
+	for _, status := range models.IncidentStatuses {
+		assert.True(t, status.IsValid(), status)
+	}
+	assert.False(t, models.IncidentStatus("closed").IsValid())
+	assert.False(t, models.IncidentStatus("Active").IsValid())
+
+	assert.True(t, models.StatusResolved.IsFinished())
+	assert.True(t, models.StatusInactive.IsFinished())
+	assert.False(t, models.StatusDraft.IsFinished())
+}
+
 func TestListIncidents_Success(t *testing.T) {
 	// Подготовка
 	service, repoMock, _ := newTestIncidentService(t)
@@ -252,10 +546,10 @@ func TestListIncidents_Success(t *testing.T) {
 	}
 
 	// Ожидания
-	repoMock.EXPECT().ListIncidents(ctx, page, pageSize).Return(expectedIncidents, nil).Times(1)
+	repoMock.EXPECT().ListIncidents(ctx, page, pageSize, models.IncidentFilter{}).Return(expectedIncidents, nil).Times(1)
 
 	// Действие
-	incidents, err := service.ListIncidents(ctx, page, pageSize)
+	incidents, err := service.ListIncidents(ctx, page, pageSize, models.IncidentFilter{})
 
 	// Проверки
 	require.NoError(t, err)
@@ -275,17 +569,18 @@ func TestCheckLocation_Danger(t *testing.T) {
 	// Ожидания
 	// 1. Поиск активной локации
 	repoMock.EXPECT().
-		FindActiveLocation(ctx, lat, lon).
+		FindActiveLocation(ctx, lat, lon, 0, 0).
 		Return(foundIncidents, nil).
 		Times(1)
 
 	// 2. Сохранение факта проверки
 	repoMock.EXPECT().
-		SaveLocationCheck(ctx, gomock.Any()).
+		SaveLocationCheck(ctx, gomock.Any(), nil).
 		// Проверяем, что сохраняем "опасную" проверку
-		Do(func(ctx context.Context, check *models.LocationCheck) {
+		Do(func(ctx context.Context, check *models.LocationCheck, _ *models.OutboxMessage) {
 			assert.True(t, check.IsDangerous)
 			assert.Equal(t, userID, check.UserID)
+			assert.Equal(t, []uuid.UUID{foundIncidents[0].ID}, check.IncidentIDs)
 		}).Return(nil).Times(1)
 
 	// 3. Публикация вебхука
@@ -296,10 +591,11 @@ func TestCheckLocation_Danger(t *testing.T) {
 			assert.True(t, event.IsDangerous)
 			assert.Equal(t, userID, event.UserID)
 			assert.Equal(t, foundIncidents, event.Incidents)
+			assert.Equal(t, webhook.SchemaVersion, event.SchemaVersion)
 		}).Return(nil).Times(1)
 
 	// Действие
-	incidents, err := service.CheckLocation(ctx, userID, lat, lon)
+	incidents, _, err := service.CheckLocation(ctx, userID, false, lat, lon, 0, 0)
 
 	// Проверки
 	require.NoError(t, err)
@@ -317,14 +613,14 @@ func TestCheckLocation_Safe(t *testing.T) {
 	// Ожидания
 	// 1. Поиск активной локации ничего не возвращает
 	repoMock.EXPECT().
-		FindActiveLocation(ctx, lat, lon).
+		FindActiveLocation(ctx, lat, lon, 0, 0).
 		Return(foundIncidents, nil).
 		Times(1)
 
 	// 2. Сохранение факта проверки
 	repoMock.EXPECT().
-		SaveLocationCheck(ctx, gomock.Any()).
-		Do(func(ctx context.Context, check *models.LocationCheck) {
+		SaveLocationCheck(ctx, gomock.Any(), nil).
+		Do(func(ctx context.Context, check *models.LocationCheck, _ *models.OutboxMessage) {
 			assert.False(t, check.IsDangerous)
 			assert.Equal(t, userID, check.UserID)
 		}).Return(nil).Times(1)
@@ -333,13 +629,107 @@ func TestCheckLocation_Safe(t *testing.T) {
 	webhookMock.EXPECT().Publish(gomock.Any(), gomock.Any()).Times(0)
 
 	// Действие
-	incidents, err := service.CheckLocation(ctx, userID, lat, lon)
+	incidents, _, err := service.CheckLocation(ctx, userID, false, lat, lon, 0, 0)
 
 	// Проверки
 	require.NoError(t, err)
 	assert.Empty(t, incidents)
 }
 
+func TestCheckLocation_Authenticated(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	ctx := context.Background()
+	lat, lon := 50.0, 50.0
+
+	// Ожидания
+	repoMock.EXPECT().FindActiveLocation(ctx, lat, lon, 0, 0).Return(nil, nil).Times(1)
+	repoMock.EXPECT().
+		SaveLocationCheck(ctx, gomock.Any(), nil).
+		Do(func(ctx context.Context, check *models.LocationCheck, _ *models.OutboxMessage) {
+			assert.True(t, check.Authenticated)
+		}).Return(nil).Times(1)
+
+	// Действие
+	_, _, err := service.CheckLocation(ctx, "user-456", true, lat, lon, 0, 0)
+
+	// Проверки
+	require.NoError(t, err)
+}
+
+func TestMatchedIncidentsBucket(t *testing.T) {
+	cases := map[int]string{0: "0", 1: "1", 2: "2-5", 5: "2-5", 6: "6+", 40: "6+"}
+	for count, expected := range cases {
+		assert.Equal(t, expected, matchedIncidentsBucket(count), count)
+	}
+}
+
+func TestCheckLocation_RecordsDuration(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	ctx := context.Background()
+	lat, lon := 50.0, 50.0
+	samples := func() uint64 {
+		var metric dto.Metric
+		require.NoError(t, locationCheckDuration.WithLabelValues("0").(prometheus.Metric).Write(&metric))
+		return metric.GetHistogram().GetSampleCount()
+	}
+	before := samples()
+
+	// Ожидания
+	repoMock.EXPECT().FindActiveLocation(ctx, lat, lon, 0, 0).Return(nil, nil).Times(1)
+	repoMock.EXPECT().SaveLocationCheck(ctx, gomock.Any(), nil).Return(nil).Times(1)
+
+	// Действие
+	_, _, err := service.CheckLocation(ctx, "user-1", false, lat, lon, 0, 0)
+
+	// Проверки
+	require.NoError(t, err)
+	assert.Equal(t, before+1, samples())
+}
+
+func TestCheckLocation_BufferCapped(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	ctx := context.Background()
+	lat, lon := 50.0, 50.0
+
+	// Ожидания
+	// Буфер больше допустимого обрезается до MaxCheckBufferMeters
+	repoMock.EXPECT().
+		FindActiveLocation(ctx, lat, lon, service.cfg.MaxCheckBufferMeters, 0).
+		Return(nil, nil).
+		Times(1)
+	repoMock.EXPECT().SaveLocationCheck(ctx, gomock.Any(), nil).Return(nil).Times(1)
+
+	// Действие
+	_, _, err := service.CheckLocation(ctx, "user-789", false, lat, lon, 5000, 0)
+
+	// Проверки
+	require.NoError(t, err)
+}
+
+func TestCheckLocation_MinConfidence(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	ctx := context.Background()
+	lat, lon := 50.0, 50.0
+
+	// Ожидания
+	// Порог уверенности передаётся в репозиторий без изменений
+	repoMock.EXPECT().
+		FindActiveLocation(ctx, lat, lon, 0, 80).
+		Return(nil, nil).
+		Times(1)
+	repoMock.EXPECT().SaveLocationCheck(ctx, gomock.Any(), nil).Return(nil).Times(1)
+
+	// Действие
+	_, _, err := service.CheckLocation(ctx, "user-789", false, lat, lon, 0, 80)
+
+	// Проверки
+	require.NoError(t, err)
+}
+
 func TestGetStats_Success(t *testing.T) {
 	// Подготовка
 	service, repoMock, _ := newTestIncidentService(t)
@@ -356,3 +746,2698 @@ func TestGetStats_Success(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, expectedUserCount, count)
 }
+
+func TestGetIncidentImpact_Success(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	ctx := context.Background()
+	incidentID := uuid.New()
+
+	// Ожидания
+	repoMock.EXPECT().GetByID(ctx, incidentID).Return(&models.Incident{ID: incidentID}, nil).Times(1)
+	repoMock.EXPECT().GetIncidentImpact(ctx, incidentID, service.cfg.StatsTimeWindowMinutes).Return(7, nil).Times(1)
+
+	// Действие
+	count, err := service.GetIncidentImpact(ctx, incidentID)
+
+	// Проверки
+	require.NoError(t, err)
+	assert.Equal(t, 7, count)
+}
+
+func TestPreviewIncidentImpact_Success(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	ctx := context.Background()
+
+	// Ожидания
+	repoMock.EXPECT().CountRecentUsersInArea(ctx, 55.75, 37.61, 500, service.cfg.StatsTimeWindowMinutes).Return(12, nil).Times(1)
+
+	// Действие
+	count, err := service.PreviewIncidentImpact(ctx, 55.75, 37.61, 500)
+
+	// Проверки
+	require.NoError(t, err)
+	assert.Equal(t, 12, count)
+}
+
+func TestPreviewIncidentImpact_CoordinatesNotAllowed(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	service.cfg.CoordinateDenyRegions = []geo.Region{{BBox: &geo.BBox{MinLat: 55, MinLon: 37, MaxLat: 56, MaxLon: 38}}}
+	ctx := context.Background()
+
+	// Ожидания
+	repoMock.EXPECT().CountRecentUsersInArea(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	// Действие
+	_, err := service.PreviewIncidentImpact(ctx, 55.75, 37.61, 500)
+
+	// Проверки
+	assert.ErrorIs(t, err, ErrCoordinatesNotAllowed)
+}
+
+func TestGetIncidentImpact_NotFound(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	ctx := context.Background()
+	incidentID := uuid.New()
+
+	// Ожидания
+	repoMock.EXPECT().GetByID(ctx, incidentID).Return(nil, fmt.Errorf("incident with id %s: %w", incidentID, ErrIncidentNotFound)).Times(1)
+	repoMock.EXPECT().GetIncidentImpact(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	// Действие
+	_, err := service.GetIncidentImpact(ctx, incidentID)
+
+	// Проверки
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrIncidentNotFound)
+}
+
+func TestGetOverlappingIncidents_ExcludesSelfSortedByArea(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	ctx := context.Background()
+	base := &models.Incident{ID: uuid.New(), Latitude: 55.75, Longitude: 37.61, RadiusMeters: 1000}
+	// ~0.009° широты - около 1 км
+	edge := &models.Incident{ID: uuid.New(), Latitude: 55.759, Longitude: 37.61, RadiusMeters: 200}
+	inside := &models.Incident{ID: uuid.New(), Latitude: 55.75, Longitude: 37.61, RadiusMeters: 500}
+
+	// Ожидания
+	// Поиск активных зон вокруг центра с буфером, равным радиусу инцидента
+	repoMock.EXPECT().GetByID(ctx, base.ID).Return(base, nil).Times(1)
+	repoMock.EXPECT().FindActiveLocation(ctx, base.Latitude, base.Longitude, base.RadiusMeters, 0).
+		Return([]*models.Incident{edge, base, inside}, nil).Times(1)
+
+	// Действие
+	overlaps, err := service.GetOverlappingIncidents(ctx, base.ID)
+
+	// Проверки
+	require.NoError(t, err)
+	require.Len(t, overlaps, 2)
+	assert.Equal(t, inside.ID, overlaps[0].Incident.ID)
+	assert.InDelta(t, geo.CircleAreaSqMeters(500), overlaps[0].OverlapAreaSqMeters, 0.01)
+	assert.Equal(t, edge.ID, overlaps[1].Incident.ID)
+	assert.Greater(t, overlaps[1].OverlapAreaSqMeters, 0.0)
+}
+
+func TestGetOverlappingIncidents_NotFound(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	ctx := context.Background()
+	incidentID := uuid.New()
+
+	// Ожидания
+	repoMock.EXPECT().GetByID(ctx, incidentID).Return(nil, fmt.Errorf("incident with id %s: %w", incidentID, ErrIncidentNotFound)).Times(1)
+	repoMock.EXPECT().FindActiveLocation(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	// Действие
+	_, err := service.GetOverlappingIncidents(ctx, incidentID)
+
+	// Проверки
+	assert.ErrorIs(t, err, ErrIncidentNotFound)
+}
+
+func TestApproveIncident_Success(t *testing.T) {
+	// Подготовка
+	service, repoMock, webhookMock := newTestIncidentService(t)
+	ctx := context.Background()
+	incidentID := uuid.New()
+	draft := &models.Incident{ID: incidentID, Name: "Черновик", Status: "draft"}
+
+	// Ожидания
+	repoMock.EXPECT().GetByID(ctx, incidentID).Return(draft, nil).Times(1)
+	repoMock.EXPECT().
+		ChangeStatus(ctx, gomock.Any()).
+		Do(func(ctx context.Context, change *models.StatusChange) {
+			assert.Equal(t, models.StatusDraft, change.FromStatus)
+			assert.Equal(t, models.StatusActive, change.ToStatus)
+			assert.Equal(t, "moderator", change.ChangedBy)
+		}).Return(nil).Times(1)
+	repoMock.EXPECT().InvalidateIncidentCache(ctx, incidentID).Return(nil).Times(1)
+	webhookMock.EXPECT().
+		Publish(ctx, gomock.Any()).
+		Do(func(ctx context.Context, event webhook.WebhookEvent) {
+			assert.Equal(t, webhook.EventTypeIncidentApproved, event.Type)
+			require.Len(t, event.Incidents, 1)
+			assert.Equal(t, incidentID, event.Incidents[0].ID)
+		}).Return(nil).Times(1)
+
+	// Действие
+	incident, err := service.ApproveIncident(ctx, incidentID, "moderator")
+
+	// Проверки
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusActive, incident.Status)
+}
+
+func TestRejectIncident_PublishesStatusChange(t *testing.T) {
+	// Подготовка
+	service, repoMock, webhookMock := newTestIncidentService(t)
+	ctx := context.Background()
+	incidentID := uuid.New()
+
+	// Ожидания
+	repoMock.EXPECT().GetByID(ctx, incidentID).Return(&models.Incident{ID: incidentID, Status: models.StatusDraft}, nil).Times(1)
+	repoMock.EXPECT().ChangeStatus(ctx, gomock.Any()).Return(nil).Times(1)
+	repoMock.EXPECT().InvalidateIncidentCache(ctx, incidentID).Return(nil).Times(1)
+	webhookMock.EXPECT().
+		Publish(ctx, gomock.Any()).
+		Do(func(ctx context.Context, event webhook.WebhookEvent) {
+			assert.Equal(t, webhook.EventTypeIncidentUpdated, event.Type)
+			assert.Equal(t, webhook.FieldChange{Old: models.StatusDraft, New: models.StatusRejected}, event.ChangedFields["status"])
+		}).Return(nil).Times(1)
+
+	// Действие
+	incident, err := service.RejectIncident(ctx, incidentID, "moderator")
+
+	// Проверки
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusRejected, incident.Status)
+}
+
+func TestRejectIncident_NotDraft(t *testing.T) {
+	// Подготовка
+	service, repoMock, webhookMock := newTestIncidentService(t)
+	ctx := context.Background()
+	incidentID := uuid.New()
+
+	// Ожидания
+	repoMock.EXPECT().GetByID(ctx, incidentID).Return(&models.Incident{ID: incidentID, Status: "active"}, nil).Times(1)
+	repoMock.EXPECT().ChangeStatus(gomock.Any(), gomock.Any()).Times(0)
+	webhookMock.EXPECT().Publish(gomock.Any(), gomock.Any()).Times(0)
+
+	// Действие
+	_, err := service.RejectIncident(ctx, incidentID, "moderator")
+
+	// Проверки
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrStatusConflict)
+}
+
+func TestBulkDeactivateIncidents_Success(t *testing.T) {
+	// Подготовка
+	service, repoMock, webhookMock := newTestIncidentService(t)
+	ctx := context.Background()
+	target := models.BulkDeactivation{Status: "active"}
+	deactivated := []*models.Incident{
+		{ID: uuid.New(), Status: "inactive"},
+		{ID: uuid.New(), Status: "inactive"},
+	}
+
+	// Ожидания
+	repoMock.EXPECT().DeactivateIncidents(ctx, target).Return(deactivated, nil).Times(1)
+	// Кэш всех деактивированных инцидентов сбрасывается одним запросом
+	repoMock.EXPECT().InvalidateIncidentCache(ctx, deactivated[0].ID, deactivated[1].ID).Return(nil).Times(1)
+	webhookMock.EXPECT().
+		Publish(ctx, gomock.Any()).
+		Do(func(ctx context.Context, event webhook.WebhookEvent) {
+			assert.Equal(t, webhook.EventTypeIncidentDeactivated, event.Type)
+		}).Return(nil).Times(2)
+
+	// Действие
+	count, err := service.BulkDeactivateIncidents(ctx, target)
+
+	// Проверки
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
+
+func TestBulkDeactivateIncidents_EmptyTarget(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	ctx := context.Background()
+
+	// Ожидания
+	repoMock.EXPECT().DeactivateIncidents(gomock.Any(), gomock.Any()).Times(0)
+
+	// Действие
+	_, err := service.BulkDeactivateIncidents(ctx, models.BulkDeactivation{})
+
+	// Проверки
+	assert.ErrorIs(t, err, ErrEmptyBulkTarget)
+}
+
+func TestGeoJSONFeature_Incident(t *testing.T) {
+	feed := `{"type": "FeatureCollection", "features": [
+		{"type": "Feature", "id": 42, "geometry": {"type": "Point", "coordinates": [37.61, 55.75]},
+		 "properties": {"name": "Пожар", "radius_meters": 500, "severity": "major"}},
+		{"type": "Feature", "geometry": {"type": "Point", "coordinates": [37.61, 55.75]},
+		 "properties": {"external_id": "ext-2", "name": "Пожар"}},
+		{"type": "Feature", "geometry": {"type": "Polygon", "coordinates": [[[0, 0], [1, 0], [1, 1], [0, 0]]]},
+		 "properties": {"external_id": "ext-3", "name": "Зона", "radius_meters": 100}},
+		{"type": "Feature", "geometry": {"type": "Point", "coordinates": [37.61, 95]},
+		 "properties": {"external_id": "ext-4", "name": "Зона", "radius_meters": 100}},
+		{"type": "Feature", "geometry": {"type": "Point", "coordinates": [37.61, 55.75]},
+		 "properties": {"name": "Без ID", "radius_meters": 100}}
+	]}`
+
+	features, err := parseGeoJSONFeed([]byte(feed))
+	require.NoError(t, err)
+	require.Len(t, features, 5)
+
+	incident, err := features[0].incident()
+	require.NoError(t, err)
+	assert.Equal(t, "42", incident.ExternalID)
+	assert.Equal(t, 55.75, incident.Latitude)
+	assert.Equal(t, 37.61, incident.Longitude)
+	assert.Equal(t, 500, incident.RadiusMeters)
+	assert.Equal(t, models.SeverityMajor, incident.Severity)
+	assert.Empty(t, incident.Status)
+
+	for i, wantErr := range map[int]string{1: "radius_meters", 2: "Point", 3: "out of range", 4: "external_id"} {
+		_, err := features[i].incident()
+		assert.ErrorContains(t, err, wantErr, "feature %d", i)
+	}
+
+	_, err = parseGeoJSONFeed([]byte(`{"type": "Feature"}`))
+	assert.Error(t, err)
+}
+
+func TestRunImport_UpsertsByExternalID(t *testing.T) {
+	// Подготовка
+	service, repoMock, webhookMock := newTestIncidentService(t)
+	service.cfg.ImportURLMaxBytes = 1 << 20
+	service.cfg.ImportURLTimeout = 5 * time.Second
+	feed := `{"type": "FeatureCollection", "features": [
+		{"type": "Feature", "geometry": {"type": "Point", "coordinates": [37.61, 55.75]},
+		 "properties": {"external_id": "new-1", "name": "Пожар", "radius_meters": 500}},
+		{"type": "Feature", "geometry": {"type": "Point", "coordinates": [30.31, 59.93]},
+		 "properties": {"external_id": "old-1", "name": "Наводнение", "radius_meters": 2000}},
+		{"type": "Feature", "geometry": {"type": "LineString", "coordinates": [[0, 0], [1, 1]]},
+		 "properties": {"external_id": "bad-1", "name": "Линия"}}
+	]}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(feed))
+	}))
+	defer server.Close()
+	existing := &models.Incident{ID: uuid.New(), ExternalID: "old-1", Name: "Наводнение", Latitude: 59.9, Longitude: 30.3, RadiusMeters: 1000, Status: "active", Severity: "major"}
+	job := &models.ImportJob{ID: uuid.New(), URL: server.URL, Status: models.ImportJobRunning}
+
+	// Ожидания
+	repoMock.EXPECT().GetByExternalID(gomock.Any(), "new-1").Return(nil, fmt.Errorf("incident with external id: %w", ErrIncidentNotFound))
+	repoMock.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, inc *models.Incident) error {
+		inc.ID = uuid.New()
+		return nil
+	})
+	repoMock.EXPECT().GetByExternalID(gomock.Any(), "old-1").Return(existing, nil)
+	repoMock.EXPECT().GetByID(gomock.Any(), existing.ID).Return(existing, nil)
+	repoMock.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, inc *models.Incident) error {
+		assert.Equal(t, 2000, inc.RadiusMeters)
+		// Не заданный в ленте уровень опасности сохраняется
+		assert.Equal(t, models.SeverityMajor, inc.Severity)
+		return nil
+	})
+	repoMock.EXPECT().InvalidateIncidentCache(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+	webhookMock.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	var saved *models.ImportJob
+	repoMock.EXPECT().SaveImportJob(gomock.Any(), gomock.Any(), importJobTTL).
+		DoAndReturn(func(_ context.Context, j *models.ImportJob, _ time.Duration) error {
+			copied := *j
+			saved = &copied
+			return nil
+		}).Times(1)
+
+	// Действие
+	service.runImport(context.Background(), job, logrus.NewEntry(service.logger))
+
+	// Проверки
+	require.NotNil(t, saved)
+	assert.Equal(t, models.ImportJobCompleted, saved.Status)
+	assert.Equal(t, 3, saved.Features)
+	assert.Equal(t, 1, saved.Created)
+	assert.Equal(t, 1, saved.Updated)
+	require.Len(t, saved.Errors, 1)
+	assert.Equal(t, 2, saved.Errors[0].Index)
+	assert.Equal(t, "bad-1", saved.Errors[0].ExternalID)
+	assert.NotNil(t, saved.FinishedAt)
+}
+
+func TestRunImport_FeedTooLarge(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	service.cfg.ImportURLMaxBytes = 16
+	service.cfg.ImportURLTimeout = 5 * time.Second
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"type": "FeatureCollection", "features": []}`))
+	}))
+	defer server.Close()
+	job := &models.ImportJob{ID: uuid.New(), URL: server.URL, Status: models.ImportJobRunning}
+
+	// Ожидания
+	repoMock.EXPECT().GetByExternalID(gomock.Any(), gomock.Any()).Times(0)
+	repoMock.EXPECT().SaveImportJob(gomock.Any(), job, importJobTTL).Return(nil).Times(1)
+
+	// Действие
+	service.runImport(context.Background(), job, logrus.NewEntry(service.logger))
+
+	// Проверки
+	assert.Equal(t, models.ImportJobFailed, job.Status)
+	assert.Contains(t, job.Error, "exceeds 16 bytes")
+}
+
+func TestSyncImportFeed_UpsertsAndDeactivatesMissing(t *testing.T) {
+	// Подготовка
+	service, repoMock, webhookMock := newTestIncidentService(t)
+	service.cfg.ImportURLMaxBytes = 1 << 20
+	service.cfg.ImportURLTimeout = 5 * time.Second
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"type": "FeatureCollection", "features": [
+			{"type": "Feature", "geometry": {"type": "Point", "coordinates": [37.61, 55.75]},
+			 "properties": {"external_id": "keep-1", "name": "Пожар", "radius_meters": 500}},
+			{"type": "Feature", "geometry": {"type": "Point", "coordinates": [30.31, 59.93]},
+			 "properties": {"external_id": "new-1", "name": "Наводнение", "radius_meters": 2000}}
+		]}`))
+	}))
+	defer server.Close()
+	feed := &models.ImportFeed{ID: uuid.New(), URL: server.URL, ExternalIDs: []string{"gone-1", "keep-1", "purged-1"}}
+	// Внешние ID инцидентов ленты начинаются с ID ленты
+	scoped := func(id string) string { return feed.ID.String() + ":" + id }
+	kept := &models.Incident{ID: uuid.New(), ExternalID: scoped("keep-1"), Name: "Пожар", Latitude: 55.75, Longitude: 37.61, RadiusMeters: 500, Status: "active"}
+	gone := &models.Incident{ID: uuid.New(), ExternalID: scoped("gone-1"), Name: "Завал", Status: "active"}
+
+	// Ожидания
+	repoMock.EXPECT().GetByExternalID(gomock.Any(), scoped("keep-1")).Return(kept, nil)
+	// Инцидент, совпадающий с объектом ленты, не сохраняется
+	repoMock.EXPECT().Update(gomock.Any(), gomock.Any()).Times(0)
+	repoMock.EXPECT().GetByExternalID(gomock.Any(), scoped("new-1")).Return(nil, fmt.Errorf("incident with external id: %w", ErrIncidentNotFound))
+	repoMock.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, inc *models.Incident) error {
+		assert.Equal(t, scoped("new-1"), inc.ExternalID)
+		return nil
+	})
+	repoMock.EXPECT().GetByExternalID(gomock.Any(), scoped("gone-1")).Return(gone, nil)
+	repoMock.EXPECT().GetByExternalID(gomock.Any(), scoped("purged-1")).Return(nil, fmt.Errorf("incident with external id: %w", ErrIncidentNotFound))
+	repoMock.EXPECT().DeactivateIncidents(gomock.Any(), models.BulkDeactivation{IDs: []uuid.UUID{gone.ID}, Reason: importFeedRemovedReason}).
+		Return([]*models.Incident{gone}, nil)
+	repoMock.EXPECT().InvalidateIncidentCache(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	webhookMock.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	var saved models.ImportFeedSync
+	repoMock.EXPECT().SaveImportFeedSync(gomock.Any(), feed.ID, []string{"keep-1", "new-1"}, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ uuid.UUID, _ []string, sync models.ImportFeedSync) error {
+			saved = sync
+			return nil
+		})
+
+	// Действие
+	sync, err := service.SyncImportFeed(context.Background(), feed)
+
+	// Проверки
+	require.NoError(t, err)
+	assert.Equal(t, models.ImportJobCompleted, sync.Status)
+	assert.Equal(t, 1, sync.Created)
+	assert.Equal(t, 0, sync.Updated)
+	assert.Equal(t, 1, sync.Deactivated)
+	assert.Equal(t, *sync, saved)
+}
+
+func TestSyncImportFeed_ReactivatesReturnedIncident(t *testing.T) {
+	// Подготовка: объект вернулся в ленту после деактивации синхронизацией
+	service, repoMock, webhookMock := newTestIncidentService(t)
+	service.cfg.ImportURLMaxBytes = 1 << 20
+	service.cfg.ImportURLTimeout = 5 * time.Second
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"type": "FeatureCollection", "features": [
+			{"type": "Feature", "geometry": {"type": "Point", "coordinates": [37.61, 55.75]},
+			 "properties": {"external_id": "back-1", "name": "Пожар", "radius_meters": 500}}
+		]}`))
+	}))
+	defer server.Close()
+	feed := &models.ImportFeed{ID: uuid.New(), URL: server.URL}
+	removed := &models.Incident{
+		ID: uuid.New(), ExternalID: feed.ID.String() + ":back-1", Name: "Пожар", Latitude: 55.75, Longitude: 37.61,
+		RadiusMeters: 500, Status: models.StatusInactive, ResolutionReason: importFeedRemovedReason,
+	}
+
+	// Ожидания
+	repoMock.EXPECT().GetByExternalID(gomock.Any(), removed.ExternalID).Return(removed, nil)
+	repoMock.EXPECT().GetByID(gomock.Any(), removed.ID).Return(removed, nil)
+	repoMock.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, inc *models.Incident) error {
+		assert.Equal(t, models.StatusActive, inc.Status)
+		assert.Empty(t, inc.ResolutionReason)
+		return nil
+	})
+	repoMock.EXPECT().InvalidateIncidentCache(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	webhookMock.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	repoMock.EXPECT().SaveImportFeedSync(gomock.Any(), feed.ID, []string{"back-1"}, gomock.Any()).Return(nil)
+
+	// Действие
+	sync, err := service.SyncImportFeed(context.Background(), feed)
+
+	// Проверки
+	require.NoError(t, err)
+	assert.Equal(t, 1, sync.Updated)
+}
+
+func TestSyncImportFeed_KeepsManuallyDeactivated(t *testing.T) {
+	// Подготовка: инцидент снят вручную, а не из-за пропажи из ленты; текст объекта нормализуется до сравнения
+	service, repoMock, _ := newTestIncidentService(t)
+	service.cfg.ImportURLMaxBytes = 1 << 20
+	service.cfg.ImportURLTimeout = 5 * time.Second
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"type": "FeatureCollection", "features": [
+			{"type": "Feature", "geometry": {"type": "Point", "coordinates": [37.61, 55.75]},
+			 "properties": {"external_id": "closed-1", "name": "<b>Пожар</b>", "radius_meters": 500, "category": " Fire "}}
+		]}`))
+	}))
+	defer server.Close()
+	feed := &models.ImportFeed{ID: uuid.New(), URL: server.URL, ExternalIDs: []string{"closed-1"}}
+	closed := &models.Incident{
+		ID: uuid.New(), ExternalID: feed.ID.String() + ":closed-1", Name: "Пожар", Latitude: 55.75, Longitude: 37.61,
+		RadiusMeters: 500, Category: "fire", Status: models.StatusInactive, ResolutionReason: "false alarm",
+	}
+
+	// Ожидания: инцидент совпадает с нормализованным объектом и не сохраняется
+	repoMock.EXPECT().GetByExternalID(gomock.Any(), closed.ExternalID).Return(closed, nil)
+	repoMock.EXPECT().Update(gomock.Any(), gomock.Any()).Times(0)
+	repoMock.EXPECT().SaveImportFeedSync(gomock.Any(), feed.ID, []string{"closed-1"}, gomock.Any()).Return(nil)
+
+	// Действие
+	sync, err := service.SyncImportFeed(context.Background(), feed)
+
+	// Проверки
+	require.NoError(t, err)
+	assert.Equal(t, 0, sync.Updated)
+	assert.Empty(t, sync.Error)
+}
+
+func TestSyncImportFeed_FetchFailedKeepsIncidents(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	service.cfg.ImportURLMaxBytes = 1 << 20
+	service.cfg.ImportURLTimeout = 5 * time.Second
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+	feed := &models.ImportFeed{ID: uuid.New(), URL: server.URL, ExternalIDs: []string{"keep-1"}}
+
+	// Ожидания
+	repoMock.EXPECT().GetByExternalID(gomock.Any(), gomock.Any()).Times(0)
+	repoMock.EXPECT().DeactivateIncidents(gomock.Any(), gomock.Any()).Times(0)
+	// nil сохраняет прежний набор внешних ID ленты
+	repoMock.EXPECT().SaveImportFeedSync(gomock.Any(), feed.ID, nil, gomock.Any()).Return(nil)
+
+	// Действие
+	sync, err := service.SyncImportFeed(context.Background(), feed)
+
+	// Проверки
+	require.NoError(t, err)
+	assert.Equal(t, models.ImportJobFailed, sync.Status)
+	assert.Contains(t, sync.Error, "status 502")
+}
+
+func TestStartIncidentImport_RejectsURL(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	service.cfg.ImportURLAllowedHosts = []string{"feeds.example.org"}
+	ctx := context.Background()
+
+	// Ожидания
+	repoMock.EXPECT().SaveImportJob(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	// Действие и проверки
+	for _, rawURL := range []string{"file:///etc/passwd", "http://169.254.169.254/latest", "feeds.example.org/incidents"} {
+		_, err := service.StartIncidentImport(ctx, rawURL, "admin")
+		assert.ErrorIs(t, err, ErrImportURLNotAllowed, rawURL)
+	}
+}
+
+func TestStartIncidentImport_RejectsPrivateHost(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	ctx := context.Background()
+
+	// Ожидания
+	repoMock.EXPECT().SaveImportJob(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	// Действие
+	_, err := service.StartIncidentImport(ctx, "https://internal.example/feed.geojson", "admin")
+
+	// Проверки
+	assert.ErrorIs(t, err, ErrImportURLNotAllowed)
+	assert.Contains(t, err.Error(), "10.0.0.5")
+}
+
+func TestNewImportTransport_RefusesLoopback(t *testing.T) {
+	// Подготовка: ответ сервера на loopback не должен быть получен
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request reached loopback server")
+	}))
+	defer server.Close()
+	client := &http.Client{Transport: newImportTransport()}
+
+	// Действие
+	_, err := client.Get(server.URL)
+
+	// Проверки
+	assert.ErrorIs(t, err, netguard.ErrForbiddenAddress)
+}
+
+func TestStartIncidentImport_LimitsConcurrentImports(t *testing.T) {
+	// Подготовка: лента отвечает только после проверки лимита
+	service, repoMock, _ := newTestIncidentService(t)
+	service.cfg.ImportURLMaxBytes = 1 << 20
+	service.cfg.ImportURLTimeout = 5 * time.Second
+	service.importSlots = make(chan struct{}, 1)
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		_, _ = w.Write([]byte(`{"type":"FeatureCollection","features":[]}`))
+	}))
+	defer server.Close()
+	// Публичный хост ленты обслуживает тестовый сервер
+	service.resolver = testResolver{"feeds.example.org": "203.0.113.20"}
+	service.importTransport = &http.Transport{
+		DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, network, server.Listener.Addr().String())
+		},
+	}
+	feedURL := "http://feeds.example.org/incidents.geojson"
+	ctx := context.Background()
+
+	// Ожидания: сохранение задачи при старте и по завершении первого импорта
+	repoMock.EXPECT().SaveImportJob(gomock.Any(), gomock.Any(), importJobTTL).Return(nil).Times(2)
+
+	// Действие
+	first, err := service.StartIncidentImport(ctx, feedURL, "admin")
+	require.NoError(t, err)
+	_, err = service.StartIncidentImport(ctx, feedURL, "admin")
+
+	// Проверки: второй импорт отклонен, пока выполняется первый; WaitImports дожидается первого
+	assert.ErrorIs(t, err, ErrImportLimitReached)
+	assert.NotNil(t, first)
+	waitCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, service.WaitImports(waitCtx), context.DeadlineExceeded)
+	close(release)
+	require.NoError(t, service.WaitImports(ctx))
+	assert.Len(t, service.importSlots, 0)
+}
+
+func TestListIncidentChanges_HasMore(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	ctx := context.Background()
+	after := models.ChangeCursor{UpdatedAt: time.Now().Add(-time.Hour)}
+	changed := make([]*models.Incident, 3)
+	for i := range changed {
+		changed[i] = &models.Incident{ID: uuid.New(), UpdatedAt: after.UpdatedAt.Add(time.Duration(i+1) * time.Second)}
+	}
+
+	// Ожидания: запрашивается на одну запись больше страницы
+	repoMock.EXPECT().ListIncidents(ctx, 1, 3, models.IncidentFilter{ChangedAfter: &after}).Return(changed, nil).Times(1)
+
+	// Действие
+	incidents, cursor, hasMore, err := service.ListIncidentChanges(ctx, after, 2)
+
+	// Проверки
+	require.NoError(t, err)
+	assert.Equal(t, changed[:2], incidents)
+	assert.True(t, hasMore)
+	assert.Equal(t, models.ChangeCursor{UpdatedAt: changed[1].UpdatedAt, ID: changed[1].ID}, cursor)
+}
+
+func TestListIncidentChanges_LastPage(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	ctx := context.Background()
+	after := models.ChangeCursor{UpdatedAt: time.Now().Add(-time.Hour), ID: uuid.New()}
+
+	// Ожидания
+	repoMock.EXPECT().ListIncidents(ctx, 1, 3, gomock.Any()).Return([]*models.Incident{}, nil).Times(1)
+
+	// Действие
+	incidents, cursor, hasMore, err := service.ListIncidentChanges(ctx, after, 2)
+
+	// Проверки: без изменений курсор не сдвигается
+	require.NoError(t, err)
+	assert.Empty(t, incidents)
+	assert.False(t, hasMore)
+	assert.Equal(t, after, cursor)
+}
+
+func TestPollIncidentChanges_WakesOnChange(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	ctx := context.Background()
+	after := models.ChangeCursor{UpdatedAt: time.Now().Add(-time.Minute)}
+	changed := []*models.Incident{{ID: uuid.New(), Name: "Пожар", UpdatedAt: time.Now()}}
+
+	// Ожидания
+	gomock.InOrder(
+		repoMock.EXPECT().ListIncidents(ctx, 1, MaxPageSize, models.IncidentFilter{ChangedAfter: &after}).
+			DoAndReturn(func(context.Context, int, int, models.IncidentFilter) ([]*models.Incident, error) {
+				// Изменение инцидента этим процессом, пока запрос ждет
+				go func() {
+					time.Sleep(20 * time.Millisecond)
+					service.changes.notify()
+				}()
+				return []*models.Incident{}, nil
+			}),
+		repoMock.EXPECT().ListIncidents(ctx, 1, MaxPageSize, models.IncidentFilter{ChangedAfter: &after}).Return(changed, nil),
+	)
+
+	// Действие
+	start := time.Now()
+	incidents, cursor, err := service.PollIncidentChanges(ctx, after, 10*time.Second)
+
+	// Проверки
+	require.NoError(t, err)
+	assert.Equal(t, changed, incidents)
+	assert.Equal(t, models.ChangeCursor{UpdatedAt: changed[0].UpdatedAt, ID: changed[0].ID}, cursor)
+	assert.Less(t, time.Since(start), pollRecheckInterval)
+}
+
+func TestPollIncidentChanges_Timeout(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	ctx := context.Background()
+	after := models.ChangeCursor{UpdatedAt: time.Now().Add(-time.Minute), ID: uuid.New()}
+
+	// Ожидания
+	repoMock.EXPECT().ListIncidents(ctx, 1, MaxPageSize, gomock.Any()).Return([]*models.Incident{}, nil).Times(1)
+
+	// Действие
+	incidents, cursor, err := service.PollIncidentChanges(ctx, after, 30*time.Millisecond)
+
+	// Проверки
+	require.NoError(t, err)
+	assert.Empty(t, incidents)
+	assert.Equal(t, after, cursor)
+}
+
+func TestPollIncidentChanges_CursorFromLastChange(t *testing.T) {
+	// Подготовка: все изменения в одну микросекунду различаются только по ID
+	service, repoMock, _ := newTestIncidentService(t)
+	ctx := context.Background()
+	after := models.ChangeCursor{UpdatedAt: time.Now().Add(-time.Hour)}
+	updatedAt := after.UpdatedAt.Add(time.Second)
+	changed := make([]*models.Incident, MaxPageSize)
+	for i := range changed {
+		changed[i] = &models.Incident{ID: uuid.New(), UpdatedAt: updatedAt}
+	}
+
+	// Ожидания
+	repoMock.EXPECT().ListIncidents(ctx, 1, MaxPageSize, gomock.Any()).Return(changed, nil).Times(1)
+
+	// Действие
+	_, cursor, err := service.PollIncidentChanges(ctx, after, time.Second)
+
+	// Проверки: остальные изменения той же микросекунды вернутся следующим запросом с этим курсором
+	require.NoError(t, err)
+	assert.Equal(t, models.ChangeCursor{UpdatedAt: updatedAt, ID: changed[MaxPageSize-1].ID}, cursor)
+}
+
+func TestMergeIncidents_Success(t *testing.T) {
+	// Подготовка
+	service, repoMock, webhookMock := newTestIncidentService(t)
+	ctx := context.Background()
+	ids := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	before := &models.Incident{ID: ids[0], Latitude: 55.75, Longitude: 37.61, RadiusMeters: 500, Status: "active"}
+	after := &models.Incident{ID: ids[0], Latitude: 55.751, Longitude: 37.612, RadiusMeters: 900, Status: "active"}
+	merged := []*models.Incident{
+		{ID: ids[1], Status: "inactive"},
+		{ID: ids[2], Status: "inactive"},
+	}
+	expectedMerge := models.IncidentMerge{CanonicalID: ids[0], DuplicateIDs: ids[1:], MergedBy: "admin"}
+
+	// Ожидания
+	repoMock.EXPECT().MergeIncidents(ctx, expectedMerge).
+		Return(&models.IncidentMergeResult{Before: before, Incident: after, Merged: merged, RedirectedChecks: 4}, nil).Times(1)
+	repoMock.EXPECT().InvalidateIncidentCache(ctx, ids[0], ids[1], ids[2]).Return(nil).Times(1)
+	var events []webhook.WebhookEvent
+	webhookMock.EXPECT().
+		Publish(ctx, gomock.Any()).
+		Do(func(ctx context.Context, event webhook.WebhookEvent) {
+			events = append(events, event)
+		}).Return(nil).Times(3)
+
+	// Действие
+	result, err := service.MergeIncidents(ctx, ids, "admin")
+
+	// Проверки
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), result.RedirectedChecks)
+	require.Len(t, events, 3)
+	assert.Equal(t, webhook.EventTypeIncidentUpdated, events[0].Type)
+	assert.Equal(t, webhook.FieldChange{Old: 500, New: 900}, events[0].ChangedFields["radius_meters"])
+	assert.Equal(t, webhook.EventTypeIncidentDeactivated, events[1].Type)
+	assert.Equal(t, webhook.EventTypeIncidentDeactivated, events[2].Type)
+}
+
+func TestMergeIncidents_RequiresDistinctIncidents(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	ctx := context.Background()
+	id := uuid.New()
+
+	// Ожидания
+	repoMock.EXPECT().MergeIncidents(gomock.Any(), gomock.Any()).Times(0)
+
+	// Действие
+	_, errSingle := service.MergeIncidents(ctx, []uuid.UUID{id}, "admin")
+	_, errDuplicate := service.MergeIncidents(ctx, []uuid.UUID{id, id}, "admin")
+
+	// Проверки
+	assert.ErrorIs(t, errSingle, ErrInvalidMerge)
+	assert.ErrorIs(t, errDuplicate, ErrInvalidMerge)
+}
+
+func TestGetUserAlerts_GroupsByLocation(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	ctx := context.Background()
+	home := &models.UserLocation{ID: uuid.New(), UserID: "user123", Label: "home", Latitude: 55.75, Longitude: 37.61}
+	work := &models.UserLocation{ID: uuid.New(), UserID: "user123", Label: "work", Latitude: 55.80, Longitude: 37.50}
+	incident := &models.Incident{ID: uuid.New(), Name: "Пожар", Status: "active"}
+
+	// Ожидания
+	repoMock.EXPECT().ListUserLocations(ctx, "user123").Return([]*models.UserLocation{home, work}, nil).Times(1)
+	repoMock.EXPECT().FindActiveLocation(ctx, home.Latitude, home.Longitude, 0, 0).Return([]*models.Incident{incident}, nil).Times(1)
+	repoMock.EXPECT().FindActiveLocation(ctx, work.Latitude, work.Longitude, 0, 0).Return([]*models.Incident{}, nil).Times(1)
+
+	// Действие
+	alerts, err := service.GetUserAlerts(ctx, "user123")
+
+	// Проверки
+	require.NoError(t, err)
+	require.Len(t, alerts, 1)
+	assert.Equal(t, "home", alerts[0].Location.Label)
+	assert.Equal(t, []*models.Incident{incident}, alerts[0].Incidents)
+}
+
+func TestGetUserAlerts_RepositoryError(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	ctx := context.Background()
+	home := &models.UserLocation{ID: uuid.New(), UserID: "user123", Label: "home"}
+	repoError := errors.New("database error")
+
+	// Ожидания
+	repoMock.EXPECT().ListUserLocations(ctx, "user123").Return([]*models.UserLocation{home}, nil).Times(1)
+	repoMock.EXPECT().FindActiveLocation(ctx, home.Latitude, home.Longitude, 0, 0).Return(nil, repoError).Times(1)
+
+	// Действие
+	alerts, err := service.GetUserAlerts(ctx, "user123")
+
+	// Проверки
+	assert.ErrorIs(t, err, repoError)
+	assert.Nil(t, alerts)
+}
+
+func TestGetUserStatus_Danger(t *testing.T) {
+	// Подготовка
+	service, repoMock, webhookMock := newTestIncidentService(t)
+	ctx := context.Background()
+	lastCheck := &models.LocationCheck{ID: 7, UserID: "user123", Latitude: 55.75, Longitude: 37.61}
+	incident := &models.Incident{ID: uuid.New(), Name: "Пожар", Status: "active"}
+
+	// Ожидания
+	// Переоценка не сохраняет проверку и не отправляет вебхук
+	repoMock.EXPECT().ListLocationChecksByUser(ctx, "user123", 1, 1).Return([]*models.LocationCheck{lastCheck}, nil).Times(1)
+	repoMock.EXPECT().FindActiveLocation(ctx, lastCheck.Latitude, lastCheck.Longitude, 0, 0).Return([]*models.Incident{incident}, nil).Times(1)
+	repoMock.EXPECT().SaveLocationCheck(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	webhookMock.EXPECT().Publish(gomock.Any(), gomock.Any()).Times(0)
+
+	// Действие
+	status, err := service.GetUserStatus(ctx, "user123")
+
+	// Проверки
+	require.NoError(t, err)
+	assert.True(t, status.IsDangerous())
+	assert.Equal(t, lastCheck, status.LastCheck)
+	assert.Equal(t, []*models.Incident{incident}, status.Incidents)
+}
+
+func TestGetUserStatus_NoChecks(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	ctx := context.Background()
+
+	// Ожидания
+	repoMock.EXPECT().ListLocationChecksByUser(ctx, "user123", 1, 1).Return([]*models.LocationCheck{}, nil).Times(1)
+	repoMock.EXPECT().FindActiveLocation(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	// Действие
+	status, err := service.GetUserStatus(ctx, "user123")
+
+	// Проверки
+	assert.ErrorIs(t, err, ErrNoLocationCheck)
+	assert.Nil(t, status)
+}
+
+func TestDeleteUserLocation_NotFound(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	ctx := context.Background()
+	locationID := uuid.New()
+
+	// Ожидания
+	repoMock.EXPECT().
+		DeleteUserLocation(ctx, "user123", locationID).
+		Return(fmt.Errorf("location %s: %w", locationID, ErrUserLocationNotFound)).Times(1)
+
+	// Действие
+	err := service.DeleteUserLocation(ctx, "user123", locationID)
+
+	// Проверки
+	assert.ErrorIs(t, err, ErrUserLocationNotFound)
+}
+
+func TestIncidentPurger_Run_PurgesInBatches(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	service.cfg.IncidentRetentionDays = 30
+	service.cfg.IncidentPurgeBatchSize = 2
+	purger := NewIncidentPurger(repoMock, service.logger, service.cfg)
+	ctx := context.Background()
+	firstBatch := []uuid.UUID{uuid.New(), uuid.New()}
+	secondBatch := []uuid.UUID{uuid.New()}
+
+	// Ожидания
+	gomock.InOrder(
+		repoMock.EXPECT().PurgeInactiveIncidents(ctx, gomock.Any(), 2).
+			DoAndReturn(func(ctx context.Context, before time.Time, limit int) ([]uuid.UUID, error) {
+				assert.WithinDuration(t, time.Now().AddDate(0, 0, -30), before, time.Minute)
+				return firstBatch, nil
+			}),
+		repoMock.EXPECT().PurgeInactiveIncidents(ctx, gomock.Any(), 2).Return(secondBatch, nil),
+	)
+	// Кэш каждой пачки сбрасывается одним запросом
+	repoMock.EXPECT().InvalidateIncidentCache(ctx, firstBatch[0], firstBatch[1]).Return(nil).Times(1)
+	repoMock.EXPECT().InvalidateIncidentCache(ctx, secondBatch[0]).Return(nil).Times(1)
+
+	// Действие
+	purged := purger.Run(ctx)
+
+	// Проверки
+	assert.Equal(t, 3, purged)
+}
+
+func TestIncidentPurger_Run_StopsOnError(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	service.cfg.IncidentRetentionDays = 30
+	service.cfg.IncidentPurgeBatchSize = 2
+	purger := NewIncidentPurger(repoMock, service.logger, service.cfg)
+	ctx := context.Background()
+
+	// Ожидания
+	repoMock.EXPECT().PurgeInactiveIncidents(ctx, gomock.Any(), 2).Return(nil, errors.New("database error")).Times(1)
+
+	// Действие
+	purged := purger.Run(ctx)
+
+	// Проверки
+	assert.Equal(t, 0, purged)
+}
+
+func TestCheckLocation_WritesWebhookToOutbox(t *testing.T) {
+	// Подготовка
+	service, repoMock, webhookMock := newTestIncidentService(t)
+	service.cfg.WebhookOutboxEnabled = true
+	ctx := context.Background()
+	lat, lon := 50.0, 50.0
+	incident := &models.Incident{ID: uuid.New(), Name: "Пожар"}
+
+	// Ожидания
+	// Событие сохраняется вместе с проверкой, а не публикуется напрямую
+	repoMock.EXPECT().FindActiveLocation(ctx, lat, lon, 0, 0).Return([]*models.Incident{incident}, nil).Times(1)
+	repoMock.EXPECT().
+		SaveLocationCheck(ctx, gomock.Any(), gomock.Not(gomock.Nil())).
+		Do(func(ctx context.Context, check *models.LocationCheck, outbox *models.OutboxMessage) {
+			var event webhook.WebhookEvent
+			require.NoError(t, json.Unmarshal(outbox.Payload, &event))
+			assert.Equal(t, webhook.EventTypeLocationCheck, event.Type)
+			assert.Equal(t, "user-123", event.UserID)
+			require.Len(t, event.Incidents, 1)
+			assert.Equal(t, incident.ID, event.Incidents[0].ID)
+		}).Return(nil).Times(1)
+	webhookMock.EXPECT().Publish(gomock.Any(), gomock.Any()).Times(0)
+
+	// Действие
+	incidents, _, err := service.CheckLocation(ctx, "user-123", false, lat, lon, 0, 0)
+
+	// Проверки
+	require.NoError(t, err)
+	assert.Len(t, incidents, 1)
+}
+
+func TestCheckLocation_PublishesDirectlyWhenOutboxSaveFails(t *testing.T) {
+	// Подготовка
+	service, repoMock, webhookMock := newTestIncidentService(t)
+	service.cfg.WebhookOutboxEnabled = true
+	ctx := context.Background()
+	lat, lon := 50.0, 50.0
+	incident := &models.Incident{ID: uuid.New(), Name: "Пожар"}
+
+	// Ожидания: проверка вместе с событием не сохранилась, событие публикуется напрямую
+	repoMock.EXPECT().FindActiveLocation(ctx, lat, lon, 0, 0).Return([]*models.Incident{incident}, nil).Times(1)
+	repoMock.EXPECT().SaveLocationCheck(ctx, gomock.Any(), gomock.Not(gomock.Nil())).Return(errors.New("connection refused")).Times(1)
+	webhookMock.EXPECT().Publish(gomock.Any(), gomock.Any()).
+		Do(func(_ context.Context, event webhook.WebhookEvent) {
+			assert.Equal(t, webhook.EventTypeLocationCheck, event.Type)
+			require.Len(t, event.Incidents, 1)
+			assert.Equal(t, incident.ID, event.Incidents[0].ID)
+		}).Return(nil).Times(1)
+
+	// Действие
+	incidents, _, err := service.CheckLocation(ctx, "user-123", false, lat, lon, 0, 0)
+
+	// Проверки
+	require.NoError(t, err)
+	assert.Len(t, incidents, 1)
+}
+
+func TestOutboxRelay_Run(t *testing.T) {
+	// Подготовка
+	service, repoMock, webhookMock := newTestIncidentService(t)
+	service.cfg.WebhookOutboxBatchSize = 2
+	relay := NewOutboxRelay(repoMock, webhookMock, service.logger, service.cfg)
+	ctx := context.Background()
+	messages := []*models.OutboxMessage{
+		{ID: 1, Payload: []byte(`{"type":"location_check","user_id":"user-1"}`)},
+		{ID: 2, Payload: []byte(`not json`)},
+		{ID: 3, Payload: []byte(`{"type":"location_check","user_id":"user-3"}`)},
+	}
+	relayBatch := func(batch []*models.OutboxMessage) func(context.Context, int, func(*models.OutboxMessage) error) (int, error) {
+		return func(ctx context.Context, limit int, send func(*models.OutboxMessage) error) (int, error) {
+			for i, message := range batch {
+				if err := send(message); err != nil {
+					return i, err
+				}
+			}
+			return len(batch), nil
+		}
+	}
+
+	// Ожидания
+	// Полный пакет запрашивает следующий; нераскодируемое сообщение пропускается без публикации
+	gomock.InOrder(
+		repoMock.EXPECT().RelayOutbox(ctx, 2, gomock.Any()).DoAndReturn(relayBatch(messages[:2])),
+		repoMock.EXPECT().RelayOutbox(ctx, 2, gomock.Any()).DoAndReturn(relayBatch(messages[2:])),
+	)
+	webhookMock.EXPECT().
+		Publish(ctx, gomock.Any()).
+		Do(func(ctx context.Context, event webhook.WebhookEvent) {
+			assert.Contains(t, []string{"user-1", "user-3"}, event.UserID)
+		}).Return(nil).Times(2)
+
+	// Действие
+	relayed := relay.Run(ctx)
+
+	// Проверки
+	assert.Equal(t, 3, relayed)
+}
+
+func TestOutboxRelay_Run_StopsOnPublishError(t *testing.T) {
+	// Подготовка
+	service, repoMock, webhookMock := newTestIncidentService(t)
+	service.cfg.WebhookOutboxBatchSize = 2
+	relay := NewOutboxRelay(repoMock, webhookMock, service.logger, service.cfg)
+	ctx := context.Background()
+	message := &models.OutboxMessage{ID: 1, Payload: []byte(`{"type":"location_check"}`)}
+
+	// Ожидания
+	// Сообщение не помечается отправленным и остается в outbox до следующего прохода
+	repoMock.EXPECT().
+		RelayOutbox(ctx, 2, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, limit int, send func(*models.OutboxMessage) error) (int, error) {
+			return 0, send(message)
+		}).Times(1)
+	webhookMock.EXPECT().Publish(ctx, gomock.Any()).Return(errors.New("redis unavailable")).Times(1)
+
+	// Действие
+	relayed := relay.Run(ctx)
+
+	// Проверки
+	assert.Equal(t, 0, relayed)
+}
+
+func TestGetStats_CacheMiss(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	service.cfg.StatsCacheTTL = time.Minute
+	ctx := context.Background()
+
+	// Ожидания
+	repoMock.EXPECT().GetStatsFromCache(ctx, 60).Return(nil, nil).Times(1)
+	repoMock.EXPECT().GetLocationCheckStats(ctx, 60).Return(42, nil).Times(1)
+	repoMock.EXPECT().
+		SetStatsCache(ctx, 60, gomock.Any(), time.Minute).
+		DoAndReturn(func(ctx context.Context, minutes int, snapshot *models.StatsSnapshot, ttl time.Duration) error {
+			assert.Equal(t, 42, snapshot.UserCount)
+			return nil
+		}).Times(1)
+
+	// Действие
+	count, err := service.GetStats(ctx)
+
+	// Проверки
+	require.NoError(t, err)
+	assert.Equal(t, 42, count)
+}
+
+func TestGetStats_FreshCacheHit(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	service.cfg.StatsCacheTTL = time.Minute
+	ctx := context.Background()
+	cached := &models.StatsSnapshot{UserCount: 7, ComputedAt: time.Now()}
+
+	// Ожидания
+	repoMock.EXPECT().GetStatsFromCache(ctx, 60).Return(cached, nil).Times(1)
+	repoMock.EXPECT().GetLocationCheckStats(gomock.Any(), gomock.Any()).Times(0)
+
+	// Действие
+	count, err := service.GetStats(ctx)
+
+	// Проверки
+	require.NoError(t, err)
+	assert.Equal(t, 7, count)
+}
+
+func TestGetStats_StaleCacheHitRefreshesInBackground(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	service.cfg.StatsCacheTTL = time.Minute
+	ctx := context.Background()
+	cached := &models.StatsSnapshot{UserCount: 7, ComputedAt: time.Now().Add(-45 * time.Second)}
+	refreshed := make(chan *models.StatsSnapshot, 1)
+
+	// Ожидания
+	repoMock.EXPECT().GetStatsFromCache(ctx, 60).Return(cached, nil).Times(1)
+	repoMock.EXPECT().GetLocationCheckStats(gomock.Any(), 60).Return(9, nil).Times(1)
+	repoMock.EXPECT().
+		SetStatsCache(gomock.Any(), 60, gomock.Any(), time.Minute).
+		DoAndReturn(func(ctx context.Context, minutes int, snapshot *models.StatsSnapshot, ttl time.Duration) error {
+			refreshed <- snapshot
+			return nil
+		}).Times(1)
+
+	// Действие
+	count, err := service.GetStats(ctx)
+
+	// Проверки: клиент сразу получает кэшированное значение, кэш обновляется в фоне
+	require.NoError(t, err)
+	assert.Equal(t, 7, count)
+	select {
+	case snapshot := <-refreshed:
+		assert.Equal(t, 9, snapshot.UserCount)
+	case <-time.After(time.Second):
+		t.Fatal("stats cache was not refreshed in background")
+	}
+}
+
+func TestClusterGridSize(t *testing.T) {
+	assert.Equal(t, 90.0, clusterGridSize(0))
+	assert.InDelta(t, 0.0879, clusterGridSize(10), 0.0001)
+	assert.Less(t, clusterGridSize(MaxClusterZoom), clusterGridSize(MaxClusterZoom-1))
+}
+
+func TestHotspotGridSize(t *testing.T) {
+	latSize, lonSize := hotspotGridSize(geo.BBox{MinLat: -1, MaxLat: 1}, 1000)
+	assert.InDelta(t, 0.00898, latSize, 0.00001)
+	assert.InDelta(t, latSize, lonSize, 0.00001)
+
+	// На 60° с.ш. градус долготы вдвое короче, поэтому ячейка по долготе вдвое шире в градусах
+	latSize, lonSize = hotspotGridSize(geo.BBox{MinLat: 59.5, MaxLat: 60.5}, 1000)
+	assert.InDelta(t, 2*latSize, lonSize, 0.0001)
+}
+
+func TestFindHotspots_DefaultThresholdAndBounds(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	service.cfg.HotspotMinIncidents = 4
+	ctx := context.Background()
+	bbox := geo.BBox{MinLat: -1, MinLon: 10, MaxLat: 1, MaxLon: 11}
+	latSize, lonSize := hotspotGridSize(bbox, 1000)
+
+	// Ожидания
+	repoMock.EXPECT().
+		FindHotspots(ctx, bbox, latSize, lonSize, 4).
+		Return([]*models.IncidentHotspot{{Latitude: 0.5, Longitude: 10.5, Count: 7}}, nil).
+		Times(1)
+
+	// Действие
+	hotspots, err := service.FindHotspots(ctx, bbox, 1000, 0)
+
+	// Проверки
+	require.NoError(t, err)
+	require.Len(t, hotspots, 1)
+	assert.Equal(t, 7, hotspots[0].Count)
+	assert.InDelta(t, 0.5-latSize/2, hotspots[0].Bounds.MinLat, 1e-9)
+	assert.InDelta(t, 10.5+lonSize/2, hotspots[0].Bounds.MaxLon, 1e-9)
+}
+
+func TestFindHotspots_BoundsAcrossAntimeridian(t *testing.T) {
+	// Подготовка: узел сетки на 180-м меридиане
+	service, repoMock, _ := newTestIncidentService(t)
+	ctx := context.Background()
+	bbox := geo.BBox{MinLat: -19, MinLon: 177, MaxLat: -16, MaxLon: -179}
+	latSize, lonSize := hotspotGridSize(bbox, 1000)
+
+	// Ожидания
+	repoMock.EXPECT().
+		FindHotspots(ctx, bbox, latSize, lonSize, 3).
+		Return([]*models.IncidentHotspot{{Latitude: -17.5, Longitude: 180, Count: 3}}, nil).
+		Times(1)
+
+	// Действие
+	hotspots, err := service.FindHotspots(ctx, bbox, 1000, 3)
+
+	// Проверки: ячейка переходит через ±180 и остается в допустимом диапазоне долгот
+	require.NoError(t, err)
+	require.Len(t, hotspots, 1)
+	assert.True(t, hotspots[0].Bounds.CrossesAntimeridian())
+	assert.NoError(t, hotspots[0].Bounds.Validate())
+	assert.InDelta(t, -180+lonSize/2, hotspots[0].Bounds.MaxLon, 1e-9)
+}
+
+func TestClusterIncidents_Success(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	ctx := context.Background()
+	bbox := geo.BBox{MinLat: 55.5, MinLon: 37.3, MaxLat: 56.0, MaxLon: 37.9}
+	expected := []*models.IncidentCluster{{Latitude: 55.7, Longitude: 37.6, Count: 3}}
+
+	// Ожидания
+	repoMock.EXPECT().ClusterActiveIncidents(ctx, bbox, clusterGridSize(12)).Return(expected, nil).Times(1)
+
+	// Действие
+	clusters, err := service.ClusterIncidents(ctx, bbox, 12)
+
+	// Проверки
+	require.NoError(t, err)
+	assert.Equal(t, expected, clusters)
+}
+
+func TestIsRuntimeAPIKey_CachesKeySet(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	service.cfg.APIKeyCacheTTL = time.Minute
+	ctx := context.Background()
+
+	// Ожидания
+	// Набор ключей читается из хранилища один раз за TTL
+	repoMock.EXPECT().ListAPIKeys(ctx).Return([]string{hashAPIKey("runtime-key-0001")}, nil).Times(1)
+
+	// Действие и проверки
+	ok, err := service.IsRuntimeAPIKey(ctx, "runtime-key-0001")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = service.IsRuntimeAPIKey(ctx, "unknown-key")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestIsRuntimeAPIKey_UsesStaleSetOnError(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	ctx := context.Background()
+
+	// Ожидания
+	// При нулевом TTL ключи перечитываются на каждый запрос; ошибка хранилища не сбрасывает загруженный набор
+	gomock.InOrder(
+		repoMock.EXPECT().ListAPIKeys(ctx).Return([]string{hashAPIKey("runtime-key-0001")}, nil),
+		repoMock.EXPECT().ListAPIKeys(ctx).Return(nil, errors.New("redis down")),
+	)
+
+	// Действие и проверки
+	ok, err := service.IsRuntimeAPIKey(ctx, "runtime-key-0001")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = service.IsRuntimeAPIKey(ctx, "runtime-key-0001")
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestRevokeAPIKey_InvalidatesCache(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	service.cfg.APIKeyCacheTTL = time.Minute
+	ctx := context.Background()
+	hash := hashAPIKey("runtime-key-0001")
+
+	// Ожидания
+	gomock.InOrder(
+		repoMock.EXPECT().ListAPIKeys(ctx).Return([]string{hash}, nil),
+		repoMock.EXPECT().RemoveAPIKey(ctx, hash).Return(nil),
+		repoMock.EXPECT().ListAPIKeys(ctx).Return([]string{}, nil),
+	)
+
+	// Действие и проверки
+	ok, err := service.IsRuntimeAPIKey(ctx, "runtime-key-0001")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	require.NoError(t, service.RevokeAPIKey(ctx, "runtime-key-0001"))
+
+	// Отозванный ключ перестает приниматься сразу, не дожидаясь TTL
+	ok, err = service.IsRuntimeAPIKey(ctx, "runtime-key-0001")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestAddAPIKey_GeneratesKey(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	ctx := context.Background()
+	var storedHash string
+
+	// Ожидания
+	repoMock.EXPECT().AddAPIKey(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, hash string) error {
+		storedHash = hash
+		return nil
+	}).Times(1)
+
+	// Действие
+	apiKey, err := service.AddAPIKey(ctx, "")
+
+	// Проверки
+	require.NoError(t, err)
+	assert.Len(t, apiKey, 2*generatedAPIKeyBytes)
+	// В хранилище попадает только хэш ключа
+	assert.Equal(t, hashAPIKey(apiKey), storedHash)
+}
+
+func TestGetAPIKeyUsage_SumsWindowSorted(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	service.cfg.APIKeyUsageWindow = 15 * time.Minute
+	ctx := context.Background()
+
+	// Ожидания
+	// Окно 15 минут читается по поминутным интервалам
+	repoMock.EXPECT().CountAPIKeyUsage(ctx, gomock.Len(15)).
+		Return(map[string]int64{"key_b": 3, "key_a": 10, "key_c": 3}, nil).Times(1)
+
+	// Действие
+	usage, err := service.GetAPIKeyUsage(ctx)
+
+	// Проверки
+	require.NoError(t, err)
+	assert.Equal(t, []*models.APIKeyUsage{
+		{Label: "key_a", Requests: 10},
+		{Label: "key_b", Requests: 3},
+		{Label: "key_c", Requests: 3},
+	}, usage)
+}
+
+func TestCheckLocation_TileCacheHit(t *testing.T) {
+	// Подготовка
+	service, repoMock, webhookMock := newTestIncidentService(t)
+	service.cfg.LocationTileCacheTTL = 30 * time.Second
+	service.cfg.LocationTilePrecision = 6
+	ctx := context.Background()
+	lat, lon := 55.7512, 37.6184
+	hash, _ := geo.Geohash(geo.Point{Lat: lat, Lon: lon}, 6)
+	near := &models.Incident{ID: uuid.New(), Latitude: 55.7520, Longitude: 37.6184, RadiusMeters: 200}
+	far := &models.Incident{ID: uuid.New(), Latitude: 55.7600, Longitude: 37.6184, RadiusMeters: 200}
+
+	// Ожидания
+	// Кандидаты берутся из кэша ячейки, PostGIS не опрашивается
+	repoMock.EXPECT().GetLocationTileGeneration(ctx).Return(int64(3), nil).Times(1)
+	repoMock.EXPECT().GetLocationTileFromCache(ctx, int64(3), hash).Return([]*models.Incident{near, far}, nil).Times(1)
+	repoMock.EXPECT().FindActiveLocation(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	repoMock.EXPECT().SaveLocationCheck(ctx, gomock.Any(), nil).Return(nil).Times(1)
+	webhookMock.EXPECT().Publish(ctx, gomock.Any()).Return(nil).Times(1)
+
+	// Действие
+	incidents, _, err := service.CheckLocation(ctx, "user-123", false, lat, lon, 0, 0)
+
+	// Проверки
+	// Дальний инцидент пересекает ячейку, но точка в его зону не попадает
+	require.NoError(t, err)
+	assert.Equal(t, []*models.Incident{near}, incidents)
+}
+
+func TestCheckLocation_TileCacheMiss(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	service.cfg.LocationTileCacheTTL = 30 * time.Second
+	service.cfg.LocationTilePrecision = 6
+	ctx := context.Background()
+	lat, lon := 55.7512, 37.6184
+	hash, cell := geo.Geohash(geo.Point{Lat: lat, Lon: lon}, 6)
+	candidates := []*models.Incident{}
+
+	// Ожидания
+	// При промахе кандидаты ищутся по всей ячейке с максимальным буфером и кэшируются
+	repoMock.EXPECT().GetLocationTileGeneration(ctx).Return(int64(0), nil).Times(1)
+	repoMock.EXPECT().GetLocationTileFromCache(ctx, int64(0), hash).Return(nil, nil).Times(1)
+	repoMock.EXPECT().FindActiveInArea(ctx, cell, service.cfg.MaxCheckBufferMeters).Return(candidates, nil).Times(1)
+	repoMock.EXPECT().SetLocationTileCache(ctx, int64(0), hash, candidates, 30*time.Second).Return(nil).Times(1)
+	repoMock.EXPECT().SaveLocationCheck(ctx, gomock.Any(), nil).Return(nil).Times(1)
+
+	// Действие
+	incidents, _, err := service.CheckLocation(ctx, "user-123", false, lat, lon, 0, 0)
+
+	// Проверки
+	require.NoError(t, err)
+	assert.Empty(t, incidents)
+}
+
+func TestFilterIncidentsByLocation(t *testing.T) {
+	point := geo.Point{Lat: 55.7512, Lon: 37.6184}
+	low := 40
+	// Центр примерно в 111 м к северу от точки
+	edge := &models.Incident{Latitude: 55.7522, Longitude: 37.6184, RadiusMeters: 100}
+	unrated := &models.Incident{Latitude: 55.7512, Longitude: 37.6184, RadiusMeters: 50}
+	uncertain := &models.Incident{Latitude: 55.7512, Longitude: 37.6184, RadiusMeters: 50, Confidence: &low}
+
+	assert.Equal(t, []*models.Incident{unrated, uncertain}, filterIncidentsByLocation([]*models.Incident{edge, unrated, uncertain}, point, 0, 0))
+	// Буфер расширяет зону, порог достоверности отсекает неуверенные инциденты
+	assert.Equal(t, []*models.Incident{edge, unrated}, filterIncidentsByLocation([]*models.Incident{edge, unrated, uncertain}, point, 20, 50))
+}
+
+func TestFilterIncidentsByLocation_HighLatitude(t *testing.T) {
+	// Тромсё, 69.65° с.ш.: 0.01° долготы - около 387 м, хотя на экваторе это 1.1 км
+	point := geo.Point{Lat: 69.65, Lon: 18.95}
+	inside := &models.Incident{Latitude: 69.65, Longitude: 18.96, RadiusMeters: 400}
+	outside := &models.Incident{Latitude: 69.65, Longitude: 18.96, RadiusMeters: 350}
+	// 0.004° широты - около 445 м на любой широте
+	north := &models.Incident{Latitude: 69.654, Longitude: 18.95, RadiusMeters: 400}
+
+	assert.Equal(t, []*models.Incident{inside}, filterIncidentsByLocation([]*models.Incident{inside, outside, north}, point, 0, 0))
+}
+
+func TestFilterIncidentsByLocation_Ellipsoid(t *testing.T) {
+	// У экватора градус меридиана на эллипсоиде - около 110574 м, а по большому кругу - 111195 м.
+	// Граница зоны проходит там же, где ее проводит ST_DWithin по geography.
+	point := geo.Point{Lat: 1, Lon: 10}
+	inside := &models.Incident{Latitude: 0, Longitude: 10, RadiusMeters: 110580}
+	outside := &models.Incident{Latitude: 0, Longitude: 10, RadiusMeters: 110570}
+
+	assert.Equal(t, []*models.Incident{inside}, filterIncidentsByLocation([]*models.Incident{inside, outside}, point, 0, 0))
+}
+
+func TestCreateIncident_InvalidatesLocationTiles(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	service.cfg.LocationTileCacheTTL = 30 * time.Second
+	ctx := context.Background()
+
+	// Ожидания
+	repoMock.EXPECT().Create(ctx, gomock.Any()).Return(nil).Times(1)
+	repoMock.EXPECT().InvalidateIncidentCache(ctx, gomock.Any()).Return(nil).Times(1)
+	repoMock.EXPECT().InvalidateLocationTiles(ctx).Return(nil).Times(1)
+
+	// Действие
+	err := service.CreateIncident(ctx, &models.Incident{Name: "Новая зона"})
+
+	// Проверки
+	require.NoError(t, err)
+}
+
+func TestInvalidateCaches_FlushesRedisAndLocationTiles(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	service.cfg.LocationTileCacheTTL = 30 * time.Second
+	ctx := context.Background()
+
+	// Ожидания
+	repoMock.EXPECT().FlushCaches(ctx).Return(int64(12), nil).Times(1)
+	repoMock.EXPECT().InvalidateLocationTiles(ctx).Return(nil).Times(1)
+
+	// Действие
+	removed, err := service.InvalidateCaches(ctx)
+
+	// Проверки
+	require.NoError(t, err)
+	assert.Equal(t, int64(12), removed)
+}
+
+func TestInvalidateCaches_RepositoryError(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	ctx := context.Background()
+	flushErr := errors.New("redis down")
+
+	// Ожидания
+	repoMock.EXPECT().FlushCaches(ctx).Return(int64(0), flushErr).Times(1)
+
+	// Действие
+	_, err := service.InvalidateCaches(ctx)
+
+	// Проверки
+	assert.ErrorIs(t, err, flushErr)
+}
+
+func TestUpdateIncident_RetriesCacheInvalidation(t *testing.T) {
+	// Подготовка
+	service, repoMock, webhookMock := newTestIncidentService(t)
+	ctx := context.Background()
+	incidentID := uuid.New()
+	existing := &models.Incident{ID: incidentID, Name: "Пожар", Status: models.StatusActive}
+
+	// Ожидания
+	repoMock.EXPECT().GetByID(ctx, incidentID).Return(existing, nil).Times(1)
+	repoMock.EXPECT().Update(ctx, gomock.Any()).Return(nil).Times(1)
+	gomock.InOrder(
+		repoMock.EXPECT().InvalidateIncidentCache(ctx, incidentID).Return(errors.New("redis timeout")).Times(1),
+		repoMock.EXPECT().InvalidateIncidentCache(ctx, incidentID).Return(nil).Times(1),
+	)
+	repoMock.EXPECT().ExpireIncidentCache(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	webhookMock.EXPECT().Publish(ctx, gomock.Any()).Return(nil).Times(1)
+
+	// Действие
+	err := service.UpdateIncident(ctx, &models.Incident{ID: incidentID, Name: "Большой пожар", Status: models.StatusActive})
+
+	// Проверки
+	require.NoError(t, err)
+}
+
+func TestInvalidateIncidentCache_ShortensTTLOnPersistentFailure(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	ctx := context.Background()
+	incidentID := uuid.New()
+	before := testutil.ToFloat64(cacheInvalidationFailures.WithLabelValues("expired"))
+
+	// Ожидания
+	repoMock.EXPECT().
+		InvalidateIncidentCache(ctx, incidentID).
+		Return(errors.New("redis timeout")).
+		Times(cacheInvalidationAttempts)
+	repoMock.EXPECT().ExpireIncidentCache(ctx, staleIncidentCacheTTL, incidentID).Return(nil).Times(1)
+
+	// Действие
+	invalidateIncidentCache(ctx, service.repo, service.logger.WithField("test", true), incidentID)
+
+	// Проверки
+	assert.Equal(t, before+1, testutil.ToFloat64(cacheInvalidationFailures.WithLabelValues("expired")))
+}
+
+func TestInvalidateIncidentCache_StopsRetryingOnCanceledContext(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	incidentID := uuid.New()
+	before := testutil.ToFloat64(cacheInvalidationFailures.WithLabelValues("failed"))
+
+	// Ожидания
+	// Отмененный контекст прерывает ожидание после первой попытки, без повторов и сокращения TTL
+	repoMock.EXPECT().InvalidateIncidentCache(ctx, incidentID).Return(context.Canceled).Times(1)
+	repoMock.EXPECT().ExpireIncidentCache(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	// Действие
+	invalidateIncidentCache(ctx, service.repo, service.logger.WithField("test", true), incidentID)
+
+	// Проверки
+	assert.Equal(t, before+1, testutil.ToFloat64(cacheInvalidationFailures.WithLabelValues("failed")))
+}
+
+func TestCheckLocation_ActiveIndexHit(t *testing.T) {
+	// Подготовка
+	service, repoMock, webhookMock := newTestIncidentService(t)
+	service.cfg.LocationIndexRefreshInterval = time.Minute
+	ctx := context.Background()
+	lat, lon := 55.7512, 37.6184
+	near := &models.Incident{ID: uuid.New(), Latitude: 55.7520, Longitude: 37.6184, RadiusMeters: 200}
+	far := &models.Incident{ID: uuid.New(), Latitude: 55.7600, Longitude: 37.6184, RadiusMeters: 200}
+	service.activeIndex = NewActiveIncidentIndex(repoMock, service.logger, service.cfg)
+
+	// Ожидания
+	// После построения индекса PostGIS при проверке не опрашивается
+	repoMock.EXPECT().ListActiveIncidents(ctx).Return([]*models.Incident{near, far}, nil).Times(1)
+	repoMock.EXPECT().FindActiveLocation(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	repoMock.EXPECT().SaveLocationCheck(ctx, gomock.Any(), nil).Return(nil).Times(1)
+	webhookMock.EXPECT().Publish(ctx, gomock.Any()).Return(nil).Times(1)
+
+	// Действие
+	require.NoError(t, service.activeIndex.Refresh(ctx))
+	incidents, _, err := service.CheckLocation(ctx, "user-123", false, lat, lon, 0, 0)
+
+	// Проверки
+	require.NoError(t, err)
+	assert.Equal(t, []*models.Incident{near}, incidents)
+}
+
+func TestCheckLocation_ActiveIndexFallback(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	service.cfg.LocationIndexRefreshInterval = time.Minute
+	ctx := context.Background()
+	lat, lon := 55.7512, 37.6184
+	service.activeIndex = NewActiveIncidentIndex(repoMock, service.logger, service.cfg)
+
+	// Ожидания
+	// Индекс сброшен изменением инцидента - проверка идет в PostGIS
+	repoMock.EXPECT().ListActiveIncidents(ctx).Return([]*models.Incident{}, nil).Times(1)
+	repoMock.EXPECT().Create(ctx, gomock.Any()).Return(nil).Times(1)
+	repoMock.EXPECT().InvalidateIncidentCache(ctx, gomock.Any()).Return(nil).Times(1)
+	repoMock.EXPECT().FindActiveLocation(ctx, lat, lon, 0, 0).Return([]*models.Incident{}, nil).Times(1)
+	repoMock.EXPECT().SaveLocationCheck(ctx, gomock.Any(), nil).Return(nil).Times(1)
+
+	// Действие
+	require.NoError(t, service.activeIndex.Refresh(ctx))
+	require.NoError(t, service.CreateIncident(ctx, &models.Incident{Name: "Новая зона"}))
+	incidents, _, err := service.CheckLocation(ctx, "user-123", false, lat, lon, 0, 0)
+
+	// Проверки
+	require.NoError(t, err)
+	assert.Empty(t, incidents)
+}
+
+func TestActiveIncidentIndex_EllipsoidBoundary(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	service.cfg.LocationIndexRefreshInterval = time.Minute
+	ctx := context.Background()
+	index := NewActiveIncidentIndex(repoMock, service.logger, service.cfg)
+	// У экватора точка в 110574 м к северу от центра по эллипсоиду (111195 м по большому кругу)
+	// лежит в зоне радиусом 110580 м, как и по ST_DWithin
+	point := geo.Point{Lat: 1, Lon: 10}
+	incident := &models.Incident{ID: uuid.New(), Latitude: 0, Longitude: 10, RadiusMeters: 110580}
+
+	// Ожидания
+	repoMock.EXPECT().ListActiveIncidents(ctx).Return([]*models.Incident{incident}, nil).Times(1)
+
+	// Действие
+	require.NoError(t, index.Refresh(ctx))
+	candidates, ok := index.candidates(point)
+
+	// Проверки
+	// Индекс только сужает набор кандидатов и не теряет инциденты, зона которых на эллипсоиде шире, чем на сфере
+	require.True(t, ok)
+	assert.Equal(t, []*models.Incident{incident}, filterIncidentsByLocation(candidates, point, 0, 0))
+}
+
+func TestActiveIncidentIndex_Stale(t *testing.T) {
+	service, repoMock, _ := newTestIncidentService(t)
+	service.cfg.LocationIndexRefreshInterval = time.Minute
+	index := NewActiveIncidentIndex(repoMock, service.logger, service.cfg)
+	point := geo.Point{Lat: 55.7512, Lon: 37.6184}
+
+	// Индекс не построен
+	_, ok := index.candidates(point)
+	assert.False(t, ok)
+
+	// Снимок старше двух периодов обновления не используется
+	index.current.Store(&incidentIndexSnapshot{tree: geo.NewRTree(nil), builtAt: time.Now().Add(-3 * time.Minute)})
+	_, ok = index.candidates(point)
+	assert.False(t, ok)
+
+	index.current.Store(&incidentIndexSnapshot{tree: geo.NewRTree(nil), builtAt: time.Now()})
+	_, ok = index.candidates(point)
+	assert.True(t, ok)
+}
+
+func TestCheckLocation_SilentIncidentSuppressesWebhook(t *testing.T) {
+	// Подготовка
+	service, repoMock, webhookMock := newTestIncidentService(t)
+	ctx := context.Background()
+	lat, lon := 50.0, 50.0
+	silent := &models.Incident{ID: uuid.New(), Name: "Учебная зона", Silent: true}
+	loud := &models.Incident{ID: uuid.New(), Name: "Пожар"}
+
+	// Ожидания
+	repoMock.EXPECT().FindActiveLocation(ctx, lat, lon, 0, 0).Return([]*models.Incident{silent, loud}, nil).Times(1)
+	repoMock.EXPECT().
+		SaveLocationCheck(ctx, gomock.Any(), nil).
+		Do(func(ctx context.Context, check *models.LocationCheck, _ *models.OutboxMessage) {
+			// Проверка фиксирует все совпавшие инциденты, включая беззвучные
+			assert.True(t, check.IsDangerous)
+			assert.Equal(t, []uuid.UUID{silent.ID, loud.ID}, check.IncidentIDs)
+		}).Return(nil).Times(1)
+	webhookMock.EXPECT().
+		Publish(ctx, gomock.Any()).
+		Do(func(ctx context.Context, event webhook.WebhookEvent) {
+			assert.Equal(t, []*models.Incident{loud}, event.Incidents)
+		}).Return(nil).Times(1)
+
+	// Действие
+	incidents, _, err := service.CheckLocation(ctx, "user-123", false, lat, lon, 0, 0)
+
+	// Проверки
+	require.NoError(t, err)
+	assert.Len(t, incidents, 2)
+}
+
+func TestCheckLocation_OnlySilentIncidents(t *testing.T) {
+	// Подготовка
+	service, repoMock, webhookMock := newTestIncidentService(t)
+	ctx := context.Background()
+	lat, lon := 50.0, 50.0
+	silent := &models.Incident{ID: uuid.New(), Name: "Учебная зона", Silent: true}
+
+	// Ожидания
+	repoMock.EXPECT().FindActiveLocation(ctx, lat, lon, 0, 0).Return([]*models.Incident{silent}, nil).Times(1)
+	repoMock.EXPECT().SaveLocationCheck(ctx, gomock.Any(), nil).Return(nil).Times(1)
+	webhookMock.EXPECT().Publish(gomock.Any(), gomock.Any()).Times(0)
+
+	// Действие
+	incidents, _, err := service.CheckLocation(ctx, "user-123", false, lat, lon, 0, 0)
+
+	// Проверки
+	require.NoError(t, err)
+	assert.Equal(t, []*models.Incident{silent}, incidents)
+}
+
+func TestValidateIncident_Valid(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	ctx := context.Background()
+	incident := &models.Incident{Name: "Пожар", Latitude: 55.75, Longitude: 37.61, RadiusMeters: 500}
+	geometry := &models.GeofenceValidation{
+		Valid:            true,
+		AreaSquareMeters: 785000,
+		Bounds:           geo.BBox{MinLat: 55.745, MinLon: 37.60, MaxLat: 55.755, MaxLon: 37.62},
+	}
+
+	// Ожидания: проверка не сохраняет инцидент
+	repoMock.EXPECT().ValidateGeofence(ctx, 55.75, 37.61, 500).Return(geometry, nil).Times(1)
+	repoMock.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
+
+	// Действие
+	result, err := service.ValidateIncident(ctx, incident)
+
+	// Проверки
+	require.NoError(t, err)
+	assert.True(t, result.Valid)
+	assert.Empty(t, result.Errors)
+	assert.Equal(t, geometry.Bounds, result.Bounds)
+}
+
+func TestValidateIncident_AcrossAntimeridian(t *testing.T) {
+	// Подготовка: границы геометрии PostGIS для такой зоны охватывают всю долготу
+	service, repoMock, _ := newTestIncidentService(t)
+	ctx := context.Background()
+	incident := &models.Incident{Name: "Циклон", Latitude: -17.5, Longitude: 179.99, RadiusMeters: 5000}
+	geometry := &models.GeofenceValidation{
+		Valid:  true,
+		Bounds: geo.BBox{MinLat: -17.55, MinLon: -180, MaxLat: -17.45, MaxLon: 180},
+	}
+
+	// Ожидания
+	repoMock.EXPECT().ValidateGeofence(ctx, -17.5, 179.99, 5000).Return(geometry, nil).Times(1)
+
+	// Действие
+	result, err := service.ValidateIncident(ctx, incident)
+
+	// Проверки
+	require.NoError(t, err)
+	assert.True(t, result.Valid)
+	assert.True(t, result.CrossesAntimeridian)
+	assert.True(t, result.Bounds.Contains(geo.Point{Lat: -17.5, Lon: -179.99}))
+	assert.False(t, result.Bounds.Contains(geo.Point{Lat: -17.5, Lon: 0}))
+}
+
+func TestValidateIncident_CollectsErrors(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	ctx := context.Background()
+	incident := &models.Incident{Name: "<b></b>", Latitude: 55.75, Longitude: 37.61, RadiusMeters: 500}
+
+	// Ожидания
+	repoMock.EXPECT().ValidateGeofence(ctx, 55.75, 37.61, 500).
+		Return(&models.GeofenceValidation{Valid: false, Errors: []string{"Self-intersection"}}, nil).Times(1)
+
+	// Действие
+	result, err := service.ValidateIncident(ctx, incident)
+
+	// Проверки
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	require.Len(t, result.Errors, 2)
+	assert.Contains(t, result.Errors[0], "empty after sanitization")
+	assert.Equal(t, "Self-intersection", result.Errors[1])
+	// Исходный инцидент не изменяется
+	assert.Equal(t, "<b></b>", incident.Name)
+}
+
+func TestValidateIncident_CategoryWithoutDefaultRadius(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	ctx := context.Background()
+	incident := &models.Incident{Name: "Утечка", Latitude: 55.75, Longitude: 37.61, Category: "leak"}
+
+	// Ожидания
+	// Без радиуса зона не строится, PostGIS не запрашивается
+	repoMock.EXPECT().ValidateGeofence(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	// Действие
+	result, err := service.ValidateIncident(ctx, incident)
+
+	// Проверки
+	require.ErrorIs(t, err, ErrInvalidIncident)
+	assert.Nil(t, result)
+}
+
+func TestGetIncidentTimeline_FillsEmptyBuckets(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	ctx := context.Background()
+	from := time.Date(2026, 3, 1, 10, 30, 0, 0, time.UTC)
+	q := models.TimelineQuery{From: from, To: from.Add(3 * time.Hour), Interval: models.TimelineHour, Source: models.SourceSensor}
+
+	// Ожидания: репозиторий возвращает только непустые корзины
+	repoMock.EXPECT().IncidentTimeline(ctx, q).Return([]*models.TimelineBucket{
+		{Start: time.Date(2026, 3, 1, 11, 0, 0, 0, time.UTC), Count: 4},
+	}, nil).Times(1)
+
+	// Действие
+	buckets, err := service.GetIncidentTimeline(ctx, q)
+
+	// Проверки: корзины выровнены по часу и покрывают весь период
+	require.NoError(t, err)
+	require.Len(t, buckets, 4)
+	assert.Equal(t, time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC), buckets[0].Start)
+	assert.Equal(t, []int{0, 4, 0, 0}, []int{buckets[0].Count, buckets[1].Count, buckets[2].Count, buckets[3].Count})
+}
+
+func TestGetIncidentTimeline_InvalidRange(t *testing.T) {
+	service, _, _ := newTestIncidentService(t)
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := service.GetIncidentTimeline(context.Background(), models.TimelineQuery{From: from, To: from, Interval: models.TimelineDay})
+	assert.ErrorIs(t, err, ErrInvalidTimeline)
+
+	_, err = service.GetIncidentTimeline(context.Background(), models.TimelineQuery{From: from, To: from.AddDate(0, 0, 50), Interval: models.TimelineHour})
+	assert.ErrorIs(t, err, ErrInvalidTimeline, "50 дней по часу превышают лимит корзин")
+}
+
+func TestCreateIncident_CategoryRules(t *testing.T) {
+	service, repoMock, _ := newTestIncidentService(t)
+	service.cfg.IncidentCategoryRules = map[string]config.CategoryRule{
+		"fire":     {MinRadiusMeters: 50, MaxRadiusMeters: 5000, DefaultRadiusMeters: 500},
+		"chemical": {MinRadiusMeters: 1000},
+	}
+	ctx := context.Background()
+
+	t.Run("default radius", func(t *testing.T) {
+		repoMock.EXPECT().Create(ctx, gomock.Any()).Return(nil).Times(1)
+		repoMock.EXPECT().InvalidateIncidentCache(ctx, gomock.Any()).Return(nil).Times(1)
+
+		incident := &models.Incident{Name: "Пожар", Category: " Fire "}
+		require.NoError(t, service.CreateIncident(ctx, incident))
+		assert.Equal(t, "fire", incident.Category)
+		assert.Equal(t, 500, incident.RadiusMeters)
+	})
+
+	t.Run("out of range", func(t *testing.T) {
+		err := service.CreateIncident(ctx, &models.Incident{Name: "Пожар", Category: "fire", RadiusMeters: 20000})
+		assert.ErrorIs(t, err, ErrInvalidIncident)
+		assert.ErrorContains(t, err, "50-5000 m")
+
+		err = service.CreateIncident(ctx, &models.Incident{Name: "Выброс", Category: "chemical", RadiusMeters: 100})
+		assert.ErrorIs(t, err, ErrInvalidIncident)
+		assert.ErrorContains(t, err, ">= 1000 m")
+	})
+
+	t.Run("no default radius", func(t *testing.T) {
+		err := service.CreateIncident(ctx, &models.Incident{Name: "Выброс", Category: "chemical"})
+		assert.ErrorIs(t, err, ErrInvalidIncident)
+	})
+}
+
+func TestCreateIncidentFromTemplate(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	ctx := context.Background()
+	templateID := uuid.New()
+	template := &models.IncidentTemplate{
+		ID:           templateID,
+		Name:         "Зона эвакуации",
+		Description:  "Стандартная зона эвакуации 500 м",
+		Category:     "evacuation",
+		RadiusMeters: 500,
+	}
+
+	// Ожидания
+	repoMock.EXPECT().GetIncidentTemplate(ctx, templateID).Return(template, nil).Times(1)
+	repoMock.EXPECT().Create(ctx, gomock.Any()).Return(nil).Times(1)
+	repoMock.EXPECT().InvalidateIncidentCache(ctx, gomock.Any()).Return(nil).Times(1)
+
+	// Действие
+	incident := &models.Incident{Latitude: 55.75, Longitude: 37.61, Description: "Утечка газа"}
+	err := service.CreateIncidentFromTemplate(ctx, templateID, incident)
+
+	// Проверки
+	require.NoError(t, err)
+	assert.Equal(t, "Зона эвакуации", incident.Name)
+	assert.Equal(t, "Утечка газа", incident.Description)
+	assert.Equal(t, "evacuation", incident.Category)
+	assert.Equal(t, 500, incident.RadiusMeters)
+	assert.Equal(t, models.StatusActive, incident.Status)
+}
+
+func TestCreateIncidentFromTemplate_NotFound(t *testing.T) {
+	service, repoMock, _ := newTestIncidentService(t)
+	ctx := context.Background()
+	templateID := uuid.New()
+
+	repoMock.EXPECT().GetIncidentTemplate(ctx, templateID).Return(nil, ErrTemplateNotFound).Times(1)
+	repoMock.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
+
+	err := service.CreateIncidentFromTemplate(ctx, templateID, &models.Incident{Latitude: 55.75, Longitude: 37.61})
+
+	assert.ErrorIs(t, err, ErrTemplateNotFound)
+}
+
+func TestCreateIncidentTemplate_Validation(t *testing.T) {
+	service, repoMock, _ := newTestIncidentService(t)
+	service.cfg.IncidentCategoryRules = map[string]config.CategoryRule{
+		"fire": {MinRadiusMeters: 50, MaxRadiusMeters: 5000, DefaultRadiusMeters: 500},
+	}
+	ctx := context.Background()
+
+	t.Run("category default radius", func(t *testing.T) {
+		repoMock.EXPECT().CreateIncidentTemplate(ctx, gomock.Any()).Return(nil).Times(1)
+
+		template := &models.IncidentTemplate{Name: "Пожар", Category: " Fire "}
+		require.NoError(t, service.CreateIncidentTemplate(ctx, template))
+		assert.Equal(t, "fire", template.Category)
+		assert.Equal(t, 0, template.RadiusMeters)
+	})
+
+	t.Run("no radius", func(t *testing.T) {
+		err := service.CreateIncidentTemplate(ctx, &models.IncidentTemplate{Name: "Без радиуса"})
+		assert.ErrorIs(t, err, ErrInvalidIncident)
+	})
+
+	t.Run("radius out of range", func(t *testing.T) {
+		err := service.CreateIncidentTemplate(ctx, &models.IncidentTemplate{Name: "Пожар", Category: "fire", RadiusMeters: 20000})
+		assert.ErrorIs(t, err, ErrInvalidIncident)
+	})
+}
+
+func TestCheckStatusTransition(t *testing.T) {
+	tests := []struct {
+		from, to models.IncidentStatus
+		allowed  bool
+	}{
+		{models.StatusActive, models.StatusActive, true},
+		{models.StatusActive, models.StatusInactive, true},
+		{models.StatusActive, models.StatusResolved, true},
+		{models.StatusActive, models.StatusDraft, false},
+		{models.StatusInactive, models.StatusActive, true},
+		{models.StatusDraft, models.StatusInactive, true},
+		{models.StatusDraft, models.StatusActive, false},
+		{models.StatusRejected, models.StatusDraft, true},
+		{models.StatusResolved, models.StatusActive, false},
+		{models.StatusResolved, models.StatusInactive, false},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s->%s", tt.from, tt.to), func(t *testing.T) {
+			err := checkStatusTransition(tt.from, tt.to)
+			if tt.allowed {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrStatusConflict)
+			}
+		})
+	}
+}
+
+func TestUpdateIncident_InvalidStatusTransition(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	ctx := context.Background()
+	incidentID := uuid.New()
+
+	// Ожидания
+	repoMock.EXPECT().GetByID(ctx, incidentID).Return(&models.Incident{ID: incidentID, Status: models.StatusResolved}, nil).Times(1)
+	repoMock.EXPECT().Update(gomock.Any(), gomock.Any()).Times(0)
+
+	// Действие
+	err := service.UpdateIncident(ctx, &models.Incident{ID: incidentID, Name: "Пожар", Status: models.StatusActive})
+
+	// Проверки
+	assert.ErrorIs(t, err, ErrStatusConflict)
+	assert.ErrorContains(t, err, "from resolved to active")
+}
+
+func TestGetIncidentByExternalID(t *testing.T) {
+	service, repoMock, _ := newTestIncidentService(t)
+	ctx := context.Background()
+	incident := &models.Incident{ID: uuid.New(), ExternalID: "feed-42"}
+
+	repoMock.EXPECT().GetByExternalID(ctx, "feed-42").Return(incident, nil).Times(1)
+	repoMock.EXPECT().GetByExternalID(ctx, "missing").Return(nil, ErrIncidentNotFound).Times(1)
+
+	got, err := service.GetIncidentByExternalID(ctx, "feed-42")
+	require.NoError(t, err)
+	assert.Equal(t, incident.ID, got.ID)
+
+	_, err = service.GetIncidentByExternalID(ctx, "missing")
+	assert.ErrorIs(t, err, ErrIncidentNotFound)
+}
+
+func TestCoordinateRegions(t *testing.T) {
+	service, repoMock, _ := newTestIncidentService(t)
+	service.cfg.CoordinateAllowRegions = []geo.Region{{BBox: &geo.BBox{MinLat: 41, MinLon: 19, MaxLat: 82, MaxLon: -169}}}
+	ctx := context.Background()
+
+	repoMock.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
+	repoMock.EXPECT().FindActiveLocation(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	err := service.CreateIncident(ctx, &models.Incident{Name: "Пожар", Latitude: 48.85, Longitude: 2.35, RadiusMeters: 100})
+	assert.ErrorIs(t, err, ErrCoordinatesNotAllowed)
+
+	_, _, err = service.CheckLocation(ctx, "user-1", false, 48.85, 2.35, 0, 0)
+	assert.ErrorIs(t, err, ErrCoordinatesNotAllowed)
+}
+
+func TestRevertIncident(t *testing.T) {
+	// Подготовка
+	service, repoMock, webhookMock := newTestIncidentService(t)
+	ctx := context.Background()
+	incidentID := uuid.New()
+	current := &models.Incident{ID: incidentID, Name: "Пожар (уточнено)", RadiusMeters: 800, Status: models.StatusResolved}
+	version := &models.IncidentVersion{
+		IncidentID: incidentID,
+		Version:    1,
+		Incident:   &models.Incident{ID: incidentID, Name: "Пожар", RadiusMeters: 500, Status: models.StatusActive},
+	}
+
+	// Ожидания
+	repoMock.EXPECT().GetByID(ctx, incidentID).Return(current, nil).Times(1)
+	repoMock.EXPECT().GetIncidentVersion(ctx, incidentID, 1).Return(version, nil).Times(1)
+	repoMock.EXPECT().Revert(ctx, gomock.Any(), &models.StatusChange{
+		IncidentID: incidentID,
+		FromStatus: models.StatusResolved,
+		ToStatus:   models.StatusActive,
+		ChangedBy:  "admin",
+	}).
+		DoAndReturn(func(_ context.Context, inc *models.Incident, _ *models.StatusChange) error {
+			assert.Equal(t, "Пожар", inc.Name)
+			assert.Equal(t, 500, inc.RadiusMeters)
+			return nil
+		}).Times(1)
+	repoMock.EXPECT().InvalidateIncidentCache(ctx, incidentID).Return(nil).Times(1)
+	webhookMock.EXPECT().Publish(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, event webhook.WebhookEvent) error {
+			assert.Equal(t, webhook.EventTypeIncidentUpdated, event.Type)
+			assert.Equal(t, map[string]webhook.FieldChange{
+				"name":          {Old: "Пожар (уточнено)", New: "Пожар"},
+				"radius_meters": {Old: 800, New: 500},
+				"status":        {Old: models.StatusResolved, New: models.StatusActive},
+			}, event.ChangedFields)
+			return nil
+		}).Times(1)
+
+	// Действие
+	reverted, err := service.RevertIncident(ctx, incidentID, 1, "admin")
+
+	// Проверки: откат не подчиняется правилам переходов статуса
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusActive, reverted.Status)
+}
+
+func TestRevertIncident_NoChanges(t *testing.T) {
+	service, repoMock, webhookMock := newTestIncidentService(t)
+	ctx := context.Background()
+	incidentID := uuid.New()
+	current := &models.Incident{ID: incidentID, Name: "Пожар", RadiusMeters: 500, Status: models.StatusActive}
+	snapshot := *current
+
+	repoMock.EXPECT().GetByID(ctx, incidentID).Return(current, nil).Times(1)
+	repoMock.EXPECT().GetIncidentVersion(ctx, incidentID, 2).Return(&models.IncidentVersion{IncidentID: incidentID, Version: 2, Incident: &snapshot}, nil).Times(1)
+	// Статус не меняется - переход в историю статусов не записывается
+	repoMock.EXPECT().Revert(ctx, gomock.Any(), (*models.StatusChange)(nil)).Return(nil).Times(1)
+	repoMock.EXPECT().InvalidateIncidentCache(ctx, incidentID).Return(nil).Times(1)
+	webhookMock.EXPECT().Publish(gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := service.RevertIncident(ctx, incidentID, 2, "admin")
+
+	require.NoError(t, err)
+}
+
+func TestRevertIncident_VersionNotFound(t *testing.T) {
+	service, repoMock, _ := newTestIncidentService(t)
+	ctx := context.Background()
+	incidentID := uuid.New()
+
+	repoMock.EXPECT().GetByID(ctx, incidentID).Return(&models.Incident{ID: incidentID}, nil).Times(1)
+	repoMock.EXPECT().GetIncidentVersion(ctx, incidentID, 7).Return(nil, ErrVersionNotFound).Times(1)
+	repoMock.EXPECT().Revert(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := service.RevertIncident(ctx, incidentID, 7, "admin")
+
+	assert.ErrorIs(t, err, ErrVersionNotFound)
+}
+
+func TestCheckLocation_UserQuotaExceeded(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	service.cfg.UserCheckQuotaPerMinute = 10
+	ctx := context.Background()
+
+	// Ожидания
+	// Квота исчерпана - поиск инцидентов и сохранение проверки не выполняются
+	repoMock.EXPECT().
+		ReserveUserCheck(ctx, "user-123", 10, time.Minute).
+		Return(30*time.Second, nil).
+		Times(1)
+	repoMock.EXPECT().FindActiveLocation(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	repoMock.EXPECT().SaveLocationCheck(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	// Действие
+	_, _, err := service.CheckLocation(ctx, "user-123", false, 50.0, 50.0, 0, 0)
+
+	// Проверки
+	require.ErrorIs(t, err, ErrCheckQuotaExceeded)
+	var quotaErr *RateLimitError
+	require.ErrorAs(t, err, &quotaErr)
+	assert.Equal(t, 30*time.Second, quotaErr.RetryAfter)
+}
+
+func TestCheckLocation_UserQuotaFailsOpen(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	service.cfg.UserCheckQuotaPerMinute = 10
+	ctx := context.Background()
+
+	// Ожидания
+	// Ошибка Redis при учете квоты не блокирует проверку
+	repoMock.EXPECT().
+		ReserveUserCheck(ctx, "user-123", 10, time.Minute).
+		Return(time.Duration(0), errors.New("redis down")).
+		Times(1)
+	repoMock.EXPECT().FindActiveLocation(ctx, 50.0, 50.0, 0, 0).Return(nil, nil).Times(1)
+	repoMock.EXPECT().SaveLocationCheck(ctx, gomock.Any(), nil).Return(nil).Times(1)
+
+	// Действие
+	incidents, _, err := service.CheckLocation(ctx, "user-123", false, 50.0, 50.0, 0, 0)
+
+	// Проверки
+	require.NoError(t, err)
+	assert.Empty(t, incidents)
+}
+
+func TestListPublicIncidents_ExcludesCategories(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	service.cfg.PublicFeedRateLimitPerMinute = 60
+	service.cfg.PublicFeedExcludedCategories = []string{"internal"}
+	ctx := context.Background()
+	expected := []*models.Incident{{ID: uuid.New(), Name: "Flood", Public: true, Status: models.StatusActive}}
+
+	// Ожидания
+	repoMock.EXPECT().
+		ReservePublicFeedRequest(ctx, "203.0.113.7", 60, time.Minute).
+		Return(time.Duration(0), nil).
+		Times(1)
+	// Размер страницы сверх MaxPageSize заменяется значением по умолчанию
+	repoMock.EXPECT().ListPublicIncidents(ctx, []string{"internal"}, 1, 20).Return(expected, nil).Times(1)
+
+	// Действие
+	incidents, err := service.ListPublicIncidents(ctx, "203.0.113.7", 0, MaxPageSize+1)
+
+	// Проверки
+	require.NoError(t, err)
+	assert.Equal(t, expected, incidents)
+}
+
+func TestListPublicIncidents_RateLimited(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	service.cfg.PublicFeedRateLimitPerMinute = 60
+	ctx := context.Background()
+
+	// Ожидания
+	// Лимит исчерпан - лента не запрашивается
+	repoMock.EXPECT().
+		ReservePublicFeedRequest(ctx, "203.0.113.7", 60, time.Minute).
+		Return(10*time.Second, nil).
+		Times(1)
+	repoMock.EXPECT().ListPublicIncidents(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	// Действие
+	_, err := service.ListPublicIncidents(ctx, "203.0.113.7", 1, 10)
+
+	// Проверки
+	require.ErrorIs(t, err, ErrPublicFeedRateLimited)
+	var limitErr *RateLimitError
+	require.ErrorAs(t, err, &limitErr)
+	assert.Equal(t, 10*time.Second, limitErr.RetryAfter)
+}
+
+func TestEscalateIncident_Success(t *testing.T) {
+	// Подготовка
+	service, repoMock, webhookMock := newTestIncidentService(t)
+	ctx := context.Background()
+	incidentID := uuid.New()
+	current := &models.Incident{ID: incidentID, Status: models.StatusActive, Severity: models.SeverityModerate}
+	escalated := &models.Incident{ID: incidentID, Status: models.StatusActive, Severity: models.SeverityMajor}
+
+	// Ожидания
+	repoMock.EXPECT().GetByID(ctx, incidentID).Return(current, nil).Times(1)
+	repoMock.EXPECT().
+		EscalateIncident(ctx, incidentID, models.SeverityModerate, models.SeverityMajor).
+		Return(escalated, nil).
+		Times(1)
+	repoMock.EXPECT().InvalidateIncidentCache(ctx, incidentID).Return(nil).Times(1)
+	webhookMock.EXPECT().
+		Publish(ctx, gomock.Any()).
+		Do(func(ctx context.Context, event webhook.WebhookEvent) {
+			assert.Equal(t, webhook.EventTypeIncidentEscalated, event.Type)
+			require.Len(t, event.Incidents, 1)
+			assert.Equal(t, models.SeverityMajor, event.Incidents[0].Severity)
+		}).Return(nil).Times(1)
+
+	// Действие
+	incident, err := service.EscalateIncident(ctx, incidentID, "operator")
+
+	// Проверки
+	require.NoError(t, err)
+	assert.Equal(t, models.SeverityMajor, incident.Severity)
+}
+
+func TestEscalateIncident_AlreadyCritical(t *testing.T) {
+	// Подготовка
+	service, repoMock, webhookMock := newTestIncidentService(t)
+	ctx := context.Background()
+	incidentID := uuid.New()
+
+	// Ожидания
+	repoMock.EXPECT().
+		GetByID(ctx, incidentID).
+		Return(&models.Incident{ID: incidentID, Status: models.StatusActive, Severity: models.SeverityCritical}, nil).
+		Times(1)
+	repoMock.EXPECT().EscalateIncident(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	webhookMock.EXPECT().Publish(gomock.Any(), gomock.Any()).Times(0)
+
+	// Действие
+	_, err := service.EscalateIncident(ctx, incidentID, "operator")
+
+	// Проверки
+	assert.ErrorIs(t, err, ErrSeverityConflict)
+}
+
+func TestEscalateIncident_NotActive(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	ctx := context.Background()
+	incidentID := uuid.New()
+
+	// Ожидания
+	repoMock.EXPECT().
+		GetByID(ctx, incidentID).
+		Return(&models.Incident{ID: incidentID, Status: models.StatusResolved, Severity: models.SeverityMinor}, nil).
+		Times(1)
+	repoMock.EXPECT().EscalateIncident(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	// Действие
+	_, err := service.EscalateIncident(ctx, incidentID, "operator")
+
+	// Проверки
+	assert.ErrorIs(t, err, ErrStatusConflict)
+}
+
+func TestIncidentEscalator_Run(t *testing.T) {
+	// Подготовка
+	service, repoMock, webhookMock := newTestIncidentService(t)
+	service.cfg.AutoEscalateDangerousUsers = 25
+	service.cfg.AutoEscalateWindow = 15 * time.Minute
+	escalator := NewIncidentEscalator(repoMock, service, service.logger, service.cfg)
+	ctx := context.Background()
+	incidentID := uuid.New()
+
+	// Ожидания
+	repoMock.EXPECT().ListEscalationCandidates(ctx, 25, 15*time.Minute).Return([]uuid.UUID{incidentID}, nil).Times(1)
+	repoMock.EXPECT().
+		GetByID(ctx, incidentID).
+		Return(&models.Incident{ID: incidentID, Status: models.StatusActive, Severity: models.SeverityMinor}, nil).
+		Times(1)
+	repoMock.EXPECT().
+		EscalateIncident(ctx, incidentID, models.SeverityMinor, models.SeverityModerate).
+		Return(&models.Incident{ID: incidentID, Status: models.StatusActive, Severity: models.SeverityModerate}, nil).
+		Times(1)
+	repoMock.EXPECT().InvalidateIncidentCache(ctx, incidentID).Return(nil).Times(1)
+	webhookMock.EXPECT().Publish(ctx, gomock.Any()).Return(nil).Times(1)
+
+	// Действие
+	escalated := escalator.Run(ctx)
+
+	// Проверки
+	assert.Equal(t, 1, escalated)
+}
+
+func TestCreateIncident_DefaultSeverity(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	ctx := context.Background()
+	incident := &models.Incident{Name: "Пожар", Latitude: 55.75, Longitude: 37.61, RadiusMeters: 500}
+
+	// Ожидания
+	repoMock.EXPECT().
+		Create(ctx, gomock.Any()).
+		Do(func(ctx context.Context, created *models.Incident) {
+			assert.Equal(t, models.SeverityMinor, created.Severity)
+		}).Return(nil).Times(1)
+	repoMock.EXPECT().InvalidateIncidentCache(ctx, gomock.Any()).Return(nil).Times(1)
+
+	// Действие
+	err := service.CreateIncident(ctx, incident)
+
+	// Проверки
+	require.NoError(t, err)
+}
+
+func TestFeatureEnabled_Config(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	service.cfg.FeatureFlags = map[string]bool{FeatureClusters: false}
+	ctx := context.Background()
+
+	// Ожидания
+	// Без FEATURE_FLAGS_REDIS флаги в Redis не читаются
+	repoMock.EXPECT().GetFeatureFlag(gomock.Any(), gomock.Any()).Times(0)
+
+	// Действие и проверки
+	assert.False(t, service.FeatureEnabled(ctx, FeatureClusters))
+	assert.True(t, service.FeatureEnabled(ctx, FeatureTimeline))
+}
+
+func TestFeatureEnabled_RedisOverride(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	service.cfg.FeatureFlags = map[string]bool{FeatureClusters: false}
+	service.cfg.FeatureFlagsRedis = true
+	ctx := context.Background()
+
+	// Ожидания
+	repoMock.EXPECT().GetFeatureFlag(ctx, FeatureClusters).Return(true, true, nil).Times(1)
+	repoMock.EXPECT().GetFeatureFlag(ctx, FeatureTimeline).Return(false, false, nil).Times(1)
+	repoMock.EXPECT().GetFeatureFlag(ctx, FeaturePublicFeed).Return(false, false, errors.New("redis down")).Times(1)
+
+	// Действие и проверки
+	assert.True(t, service.FeatureEnabled(ctx, FeatureClusters))
+	assert.True(t, service.FeatureEnabled(ctx, FeatureTimeline))
+	assert.True(t, service.FeatureEnabled(ctx, FeaturePublicFeed))
+}
+
+// fakeAttachmentStorage подписывает URL без хранилища: URL содержит метод и ключ объекта
+type fakeAttachmentStorage struct{}
+
+func (fakeAttachmentStorage) PresignPut(key, contentType string, ttl time.Duration) (string, error) {
+	return "https://storage.example/put/" + key, nil
+}
+
+func (fakeAttachmentStorage) PresignGet(key string, ttl time.Duration) (string, error) {
+	return "https://storage.example/get/" + key, nil
+}
+
+func TestCreateAttachmentUploadURL_BuildsObjectKey(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	service.attachments = fakeAttachmentStorage{}
+	service.cfg.S3PresignTTL = 15 * time.Minute
+	incident := &models.Incident{ID: uuid.New(), Status: "active"}
+
+	// Ожидания
+	repoMock.EXPECT().GetByID(gomock.Any(), incident.ID).Return(incident, nil)
+	var saved *models.IncidentAttachment
+	repoMock.EXPECT().CreateIncidentAttachment(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, a *models.IncidentAttachment) error {
+			a.ID = uuid.New()
+			saved = a
+			return nil
+		})
+
+	// Действие
+	presigned, err := service.CreateAttachmentUploadURL(context.Background(), incident.ID, `C:\photos\../Пожар.JPG`, "image/jpeg", "ops")
+
+	// Проверки
+	require.NoError(t, err)
+	require.NotNil(t, saved)
+	// Имя файла клиента не попадает в ключ объекта, переносится только расширение
+	assert.Regexp(t, "^incidents/"+incident.ID.String()+"/[0-9a-f-]{36}\\.jpg$", saved.ObjectKey)
+	assert.Equal(t, "Пожар.JPG", saved.Filename)
+	assert.Equal(t, "ops", saved.CreatedBy)
+	assert.Equal(t, "https://storage.example/put/"+saved.ObjectKey, presigned.URL)
+	assert.WithinDuration(t, time.Now().Add(15*time.Minute), presigned.ExpiresAt, time.Minute)
+}
+
+func TestCreateAttachmentUploadURL_Errors(t *testing.T) {
+	service, repoMock, _ := newTestIncidentService(t)
+	ctx := context.Background()
+	id := uuid.New()
+
+	// Хранилище не настроено
+	_, err := service.CreateAttachmentUploadURL(ctx, id, "photo.jpg", "image/jpeg", "ops")
+	assert.ErrorIs(t, err, ErrAttachmentsDisabled)
+
+	// Некорректный тип содержимого
+	service.attachments = fakeAttachmentStorage{}
+	_, err = service.CreateAttachmentUploadURL(ctx, id, "photo.jpg", "not a type", "ops")
+	assert.ErrorIs(t, err, ErrInvalidAttachment)
+
+	// Инцидент не найден
+	repoMock.EXPECT().GetByID(gomock.Any(), id).Return(nil, fmt.Errorf("incident with id %s: %w", id, ErrIncidentNotFound))
+	repoMock.EXPECT().CreateIncidentAttachment(gomock.Any(), gomock.Any()).Times(0)
+	_, err = service.CreateAttachmentUploadURL(ctx, id, "photo.jpg", "image/jpeg", "ops")
+	assert.ErrorIs(t, err, ErrIncidentNotFound)
+}
+
+func TestListIncidentAttachments_PresignsDownloads(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	service.attachments = fakeAttachmentStorage{}
+	service.cfg.S3PresignTTL = time.Minute
+	incidentID := uuid.New()
+	attachment := &models.IncidentAttachment{ID: uuid.New(), IncidentID: incidentID, ObjectKey: "incidents/a/b.jpg"}
+
+	// Ожидания
+	repoMock.EXPECT().GetByID(gomock.Any(), incidentID).Return(&models.Incident{ID: incidentID}, nil)
+	repoMock.EXPECT().ListIncidentAttachments(gomock.Any(), incidentID).Return([]*models.IncidentAttachment{attachment}, nil)
+
+	// Действие
+	presigned, err := service.ListIncidentAttachments(context.Background(), incidentID)
+
+	// Проверки
+	require.NoError(t, err)
+	require.Len(t, presigned, 1)
+	assert.Equal(t, "https://storage.example/get/incidents/a/b.jpg", presigned[0].URL)
+}
+
+func TestIncidentViews_RecordAndFlush(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	service.cfg.IncidentViewFlushInterval = time.Minute
+	ctx := context.Background()
+	first, second := uuid.New(), uuid.New()
+	service.RecordIncidentView(first)
+	service.RecordIncidentView(first)
+	service.RecordIncidentView(second)
+
+	// Ожидания: первая попытка не удалась, просмотры остаются в памяти и переносятся второй
+	repoMock.EXPECT().IncrementIncidentViews(ctx, map[uuid.UUID]int64{first: 2, second: 1}).Return(errors.New("redis down")).Times(1)
+	repoMock.EXPECT().IncrementIncidentViews(ctx, map[uuid.UUID]int64{first: 2, second: 1}).Return(nil).Times(1)
+
+	// Действие
+	errFailed := service.FlushIncidentViews(ctx)
+	errFlushed := service.FlushIncidentViews(ctx)
+
+	// Проверки: после переноса в памяти ничего не осталось, повторный перенос не обращается к Redis
+	assert.Error(t, errFailed)
+	assert.NoError(t, errFlushed)
+	assert.NoError(t, service.FlushIncidentViews(ctx))
+}
+
+func TestIncidentViews_DisabledIsNotRecorded(t *testing.T) {
+	// Подготовка
+	service, _, _ := newTestIncidentService(t)
+
+	// Действие
+	service.RecordIncidentView(uuid.New())
+
+	// Проверки: при INCIDENT_VIEW_FLUSH_INTERVAL = 0 перенос не обращается к Redis
+	assert.NoError(t, service.FlushIncidentViews(context.Background()))
+}
+
+func TestGetIncidentViews_SumsPersistedAndPending(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	service.cfg.IncidentViewFlushInterval = time.Minute
+	ctx := context.Background()
+	incidentID := uuid.New()
+	service.RecordIncidentView(incidentID)
+
+	// Ожидания
+	repoMock.EXPECT().GetByID(ctx, incidentID).Return(&models.Incident{ID: incidentID}, nil).Times(1)
+	repoMock.EXPECT().GetIncidentViews(ctx, incidentID).Return(int64(40), nil).Times(1)
+	repoMock.EXPECT().GetPendingIncidentViews(ctx, incidentID).Return(int64(2), nil).Times(1)
+
+	// Действие
+	views, err := service.GetIncidentViews(ctx, incidentID)
+
+	// Проверки
+	require.NoError(t, err)
+	assert.Equal(t, int64(43), views)
+}
+
+func TestGetIncidentViews_RedisUnavailable(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	ctx := context.Background()
+	incidentID := uuid.New()
+
+	// Ожидания
+	repoMock.EXPECT().GetByID(ctx, incidentID).Return(&models.Incident{ID: incidentID}, nil).Times(1)
+	repoMock.EXPECT().GetIncidentViews(ctx, incidentID).Return(int64(40), nil).Times(1)
+	repoMock.EXPECT().GetPendingIncidentViews(ctx, incidentID).Return(int64(0), errors.New("redis down")).Times(1)
+
+	// Действие
+	views, err := service.GetIncidentViews(ctx, incidentID)
+
+	// Проверки: отдается число, сохраненное в PostgreSQL
+	require.NoError(t, err)
+	assert.Equal(t, int64(40), views)
+}
+
+func TestGetIncidentViews_NotFound(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	ctx := context.Background()
+	incidentID := uuid.New()
+
+	// Ожидания
+	repoMock.EXPECT().GetByID(ctx, incidentID).Return(nil, ErrIncidentNotFound).Times(1)
+
+	// Действие
+	_, err := service.GetIncidentViews(ctx, incidentID)
+
+	// Проверки
+	assert.ErrorIs(t, err, ErrIncidentNotFound)
+}
+
+func TestReevaluateLocationChecks_PagesAndNotifies(t *testing.T) {
+	// Подготовка
+	service, repoMock, webhookMock := newTestIncidentService(t)
+	ctx := context.Background()
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+	loud := &models.Incident{ID: uuid.New(), Name: "Пожар"}
+	silent := &models.Incident{ID: uuid.New(), Name: "Учения", Silent: true}
+	checks := []*models.LocationCheck{
+		{ID: 3, UserID: "user-1", CheckedAt: from.Add(time.Hour), IncidentIDs: []uuid.UUID{loud.ID, silent.ID}},
+		{ID: 5, UserID: "user-2", CheckedAt: from.Add(2 * time.Hour), IncidentIDs: []uuid.UUID{silent.ID}},
+		{ID: 8, UserID: "user-3", CheckedAt: from.Add(3 * time.Hour), IncidentIDs: []uuid.UUID{loud.ID}},
+	}
+
+	// Ожидания: запрашивается на одну запись больше страницы, инциденты загружаются по одному разу
+	repoMock.EXPECT().CountLocationChecks(ctx, from, to).Return(40, nil).Times(1)
+	repoMock.EXPECT().FindMissedLocationAlerts(ctx, from, to, int64(0), 3).Return(checks, nil).Times(1)
+	repoMock.EXPECT().GetByID(ctx, loud.ID).Return(loud, nil).Times(1)
+	repoMock.EXPECT().GetByID(ctx, silent.ID).Return(silent, nil).Times(1)
+	webhookMock.EXPECT().Publish(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, event webhook.WebhookEvent) error {
+		assert.Equal(t, webhook.EventTypeLocationCheckReevaluated, event.Type)
+		assert.Equal(t, "user-1", event.UserID)
+		assert.Equal(t, checks[0].CheckedAt, event.Timestamp)
+		assert.True(t, event.IsDangerous)
+		// Беззвучные инциденты в вебхук не попадают
+		assert.Equal(t, []*models.Incident{loud}, event.Incidents)
+		return nil
+	}).Times(1)
+
+	// Действие
+	result, err := service.ReevaluateLocationChecks(ctx, models.LocationCheckReevaluationQuery{From: from, To: to, Limit: 2, Notify: true})
+
+	// Проверки: вторая проверка только с беззвучным инцидентом вебхук не получает
+	require.NoError(t, err)
+	assert.Equal(t, 40, result.Checked)
+	assert.Len(t, result.Discrepancies, 2)
+	assert.Equal(t, int64(5), result.NextAfterID)
+	assert.Equal(t, 1, result.WebhooksPublished)
+}
+
+func TestReevaluateLocationChecks_InvalidRange(t *testing.T) {
+	// Подготовка
+	service, _, _ := newTestIncidentService(t)
+	now := time.Now()
+
+	// Действие
+	_, err := service.ReevaluateLocationChecks(context.Background(), models.LocationCheckReevaluationQuery{From: now, To: now})
+
+	// Проверки
+	assert.ErrorIs(t, err, ErrInvalidReevaluation)
+}
+
+func TestCheckLocation_AlertBufferBySeverity(t *testing.T) {
+	// Подготовка
+	service, repoMock, webhookMock := newTestIncidentService(t)
+	service.cfg.AlertBufferMeters = map[models.IncidentSeverity]int{models.SeverityCritical: 500}
+	ctx := context.Background()
+	lat, lon := 55.7512, 37.6184
+	// Зона заканчивается примерно в 233 м от точки: пользователь вне зоны, но в буфере оповещения критического инцидента
+	critical := &models.Incident{ID: uuid.New(), Latitude: 55.7542, Longitude: 37.6184, RadiusMeters: 100, Severity: models.SeverityCritical}
+
+	// Ожидания
+	// Попадание в зону и в буфер оповещения определяются одним запросом к PostGIS
+	repoMock.EXPECT().
+		FindActiveLocationForAlert(ctx, lat, lon, 0, 0, service.cfg.AlertBufferMeters).
+		Return([]*models.Incident{}, []*models.Incident{critical}, nil).
+		Times(1)
+	repoMock.EXPECT().
+		SaveLocationCheck(ctx, gomock.Any(), nil).
+		Do(func(ctx context.Context, check *models.LocationCheck, _ *models.OutboxMessage) {
+			assert.False(t, check.IsDangerous)
+			assert.Empty(t, check.IncidentIDs)
+		}).Return(nil).Times(1)
+	webhookMock.EXPECT().
+		Publish(ctx, gomock.Any()).
+		Do(func(ctx context.Context, event webhook.WebhookEvent) {
+			assert.True(t, event.IsDangerous)
+			assert.Equal(t, []*models.Incident{critical}, event.Incidents)
+		}).Return(nil).Times(1)
+
+	// Действие
+	incidents, _, err := service.CheckLocation(ctx, "user-123", false, lat, lon, 0, 0)
+
+	// Проверки
+	require.NoError(t, err)
+	assert.Empty(t, incidents)
+}
+
+func TestCheckLocation_AlertBufferFromActiveIndex(t *testing.T) {
+	// Подготовка
+	service, repoMock, webhookMock := newTestIncidentService(t)
+	service.cfg.AlertBufferMeters = map[models.IncidentSeverity]int{models.SeverityCritical: 500}
+	service.cfg.LocationIndexRefreshInterval = time.Minute
+	service.activeIndex = NewActiveIncidentIndex(repoMock, service.logger, service.cfg)
+	ctx := context.Background()
+	lat, lon := 55.7512, 37.6184
+	critical := &models.Incident{ID: uuid.New(), Latitude: 55.7542, Longitude: 37.6184, RadiusMeters: 100, Severity: models.SeverityCritical}
+	minor := &models.Incident{ID: uuid.New(), Latitude: 55.7542, Longitude: 37.6184, RadiusMeters: 100, Severity: models.SeverityMinor}
+
+	// Ожидания
+	// Кандидаты из индекса проверяются в памяти по той же геодезической, что и в PostGIS
+	repoMock.EXPECT().ListActiveIncidents(ctx).Return([]*models.Incident{critical, minor}, nil).Times(1)
+	repoMock.EXPECT().FindActiveLocationForAlert(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	repoMock.EXPECT().SaveLocationCheck(ctx, gomock.Any(), nil).Return(nil).Times(1)
+	webhookMock.EXPECT().
+		Publish(ctx, gomock.Any()).
+		Do(func(ctx context.Context, event webhook.WebhookEvent) {
+			assert.Equal(t, []*models.Incident{critical}, event.Incidents)
+		}).Return(nil).Times(1)
+
+	// Действие
+	require.NoError(t, service.activeIndex.Refresh(ctx))
+	incidents, _, err := service.CheckLocation(ctx, "user-123", false, lat, lon, 0, 0)
+
+	// Проверки
+	require.NoError(t, err)
+	assert.Empty(t, incidents)
+}
+
+func TestCheckLocation_MaxMatchedIncidents(t *testing.T) {
+	// Подготовка
+	service, repoMock, webhookMock := newTestIncidentService(t)
+	service.cfg.MaxMatchedIncidents = 2
+	ctx := context.Background()
+	lat, lon := 55.7512, 37.6184
+	farMinor := &models.Incident{ID: uuid.New(), Latitude: 55.7530, Longitude: 37.6184, RadiusMeters: 500, Severity: models.SeverityMinor}
+	farCritical := &models.Incident{ID: uuid.New(), Latitude: 55.7530, Longitude: 37.6184, RadiusMeters: 500, Severity: models.SeverityCritical}
+	nearCritical := &models.Incident{ID: uuid.New(), Latitude: 55.7513, Longitude: 37.6184, RadiusMeters: 500, Severity: models.SeverityCritical}
+	found := []*models.Incident{farMinor, farCritical, nearCritical}
+
+	// Ожидания
+	// Проверка и вебхук содержат все совпавшие инциденты
+	repoMock.EXPECT().FindActiveLocation(ctx, lat, lon, 0, 0).Return(found, nil).Times(1)
+	repoMock.EXPECT().
+		SaveLocationCheck(ctx, gomock.Any(), nil).
+		Do(func(ctx context.Context, check *models.LocationCheck, _ *models.OutboxMessage) {
+			assert.Len(t, check.IncidentIDs, 3)
+		}).Return(nil).Times(1)
+	webhookMock.EXPECT().
+		Publish(ctx, gomock.Any()).
+		Do(func(ctx context.Context, event webhook.WebhookEvent) {
+			assert.Len(t, event.Incidents, 3)
+		}).Return(nil).Times(1)
+
+	// Действие
+	incidents, matched, err := service.CheckLocation(ctx, "user-123", false, lat, lon, 0, 0)
+
+	// Проверки
+	// Возвращаются самые опасные, а среди равных - ближайшие
+	require.NoError(t, err)
+	assert.Equal(t, 3, matched)
+	assert.Equal(t, []*models.Incident{nearCritical, farCritical}, incidents)
+}
+
+func TestWatchIncident_Success(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	incidentID := uuid.New()
+	watcher := &models.IncidentWatcher{IncidentID: incidentID, URL: "https://hooks.example.org/watch", CreatedBy: "ops"}
+
+	// Ожидания
+	repoMock.EXPECT().GetByID(gomock.Any(), incidentID).Return(&models.Incident{ID: incidentID}, nil)
+	repoMock.EXPECT().CreateIncidentWatcher(gomock.Any(), watcher).Return(nil)
+
+	// Действие
+	err := service.WatchIncident(context.Background(), watcher)
+
+	// Проверки
+	require.NoError(t, err)
+}
+
+func TestWatchIncident_Errors(t *testing.T) {
+	service, repoMock, _ := newTestIncidentService(t)
+	ctx := context.Background()
+	id := uuid.New()
+	repoMock.EXPECT().CreateIncidentWatcher(gomock.Any(), gomock.Any()).Times(0)
+
+	// Адрес не http(s)
+	err := service.WatchIncident(ctx, &models.IncidentWatcher{IncidentID: id, URL: "ftp://hooks.example.org/watch"})
+	assert.ErrorIs(t, err, ErrWatchURLNotAllowed)
+
+	// Хост не входит в WATCH_URL_ALLOWED_HOSTS
+	service.cfg.WatchURLAllowedHosts = []string{"hooks.example.org"}
+	err = service.WatchIncident(ctx, &models.IncidentWatcher{IncidentID: id, URL: "http://169.254.169.254/latest"})
+	assert.ErrorIs(t, err, ErrWatchURLNotAllowed)
+
+	// Инцидент не найден
+	repoMock.EXPECT().GetByID(gomock.Any(), id).Return(nil, fmt.Errorf("incident with id %s: %w", id, ErrIncidentNotFound))
+	err = service.WatchIncident(ctx, &models.IncidentWatcher{IncidentID: id, URL: "https://Hooks.Example.org/watch"})
+	assert.ErrorIs(t, err, ErrIncidentNotFound)
+
+	// Хост разрешается во внутренний адрес, адрес - loopback или метаданные облака
+	service.cfg.WatchURLAllowedHosts = nil
+	for _, rawURL := range []string{"https://internal.example/watch", "http://127.0.0.1:8080/watch", "http://169.254.169.254/latest", "http://unknown.example/watch"} {
+		err = service.WatchIncident(ctx, &models.IncidentWatcher{IncidentID: id, URL: rawURL})
+		assert.ErrorIs(t, err, ErrWatchURLNotAllowed, rawURL)
+	}
+}
+
+func TestUnwatchIncident_NotFound(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	incidentID, watcherID := uuid.New(), uuid.New()
+
+	// Ожидания
+	repoMock.EXPECT().DeleteIncidentWatcher(gomock.Any(), incidentID, watcherID).
+		Return(fmt.Errorf("watcher %s of incident %s: %w", watcherID, incidentID, ErrWatcherNotFound))
+
+	// Действие
+	err := service.UnwatchIncident(context.Background(), incidentID, watcherID, "admin", true)
+
+	// Проверки
+	assert.ErrorIs(t, err, ErrWatcherNotFound)
+}
+
+func TestUnwatchIncident_OnlyOwner(t *testing.T) {
+	// Подготовка
+	service, repoMock, _ := newTestIncidentService(t)
+	ctx := context.Background()
+	incidentID, watcherID := uuid.New(), uuid.New()
+	watchers := []*models.IncidentWatcher{{ID: watcherID, IncidentID: incidentID, CreatedBy: "ops"}}
+
+	// Ожидания
+	repoMock.EXPECT().ListIncidentWatchers(ctx, incidentID).Return(watchers, nil).Times(3)
+	repoMock.EXPECT().DeleteIncidentWatcher(ctx, incidentID, watcherID).Return(nil).Times(1)
+
+	// Действие и проверки
+	// Чужой ключ не может удалить наблюдателя; наблюдателя с другим ID у инцидента нет
+	assert.ErrorIs(t, service.UnwatchIncident(ctx, incidentID, watcherID, "other", false), ErrWatcherForbidden)
+	assert.ErrorIs(t, service.UnwatchIncident(ctx, incidentID, uuid.New(), "ops", false), ErrWatcherNotFound)
+	assert.NoError(t, service.UnwatchIncident(ctx, incidentID, watcherID, "ops", false))
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shenikar/geo_broadcasting_system/internal/config"
+	"github.com/sirupsen/logrus"
+)
+
+// incidentViewBatchInterval - как часто просмотры, накопленные в памяти процесса, переносятся в счетчики Redis
+const incidentViewBatchInterval = time.Second
+
+// viewCounter копит просмотры инцидентов в памяти процесса, чтобы запрос инцидента не ждал Redis
+type viewCounter struct {
+	mu      sync.Mutex
+	pending map[uuid.UUID]int64
+}
+
+// add учитывает count просмотров инцидента
+func (c *viewCounter) add(id uuid.UUID, count int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.pending == nil {
+		c.pending = make(map[uuid.UUID]int64)
+	}
+	c.pending[id] += count
+}
+
+// take забирает накопленные просмотры
+func (c *viewCounter) take() map[uuid.UUID]int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	pending := c.pending
+	c.pending = nil
+	return pending
+}
+
+// get возвращает накопленные просмотры инцидента
+func (c *viewCounter) get(id uuid.UUID) int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.pending[id]
+}
+
+// RecordIncidentView учитывает просмотр инцидента. Просмотр только запоминается в памяти процесса:
+// в Redis его переносит FlushIncidentViews, поэтому учет не добавляет задержки запросу.
+func (s *incidentService) RecordIncidentView(id uuid.UUID) {
+	if s.cfg.IncidentViewFlushInterval <= 0 {
+		return
+	}
+	s.views.add(id, 1)
+}
+
+// FlushIncidentViews переносит просмотры, накопленные в памяти процесса, в счетчики Redis.
+// Если Redis недоступен, просмотры остаются в памяти до следующей попытки.
+func (s *incidentService) FlushIncidentViews(ctx context.Context) error {
+	pending := s.views.take()
+	if len(pending) == 0 {
+		return nil
+	}
+	if err := s.repo.IncrementIncidentViews(ctx, pending); err != nil {
+		for id, count := range pending {
+			s.views.add(id, count)
+		}
+		return fmt.Errorf("service: could not flush incident views: %w", err)
+	}
+	return nil
+}
+
+// GetIncidentViews возвращает число просмотров инцидента: сохраненные в PostgreSQL вместе с еще
+// не перенесенными из Redis и из памяти этого процесса. Просмотры, накопленные другими экземплярами
+// сервиса, учитываются не позже чем через секунду.
+func (s *incidentService) GetIncidentViews(ctx context.Context, id uuid.UUID) (int64, error) {
+	log := s.logger.WithFields(logrus.Fields{
+		"service":     "incident",
+		"method":      "GetIncidentViews",
+		"incident_id": id,
+	})
+
+	if _, err := s.repo.GetByID(ctx, id); err != nil {
+		return 0, fmt.Errorf("service: could not get incident: %w", err)
+	}
+	views, err := s.repo.GetIncidentViews(ctx, id)
+	if err != nil {
+		log.WithError(err).Error("Failed to get incident views from repository")
+		return 0, fmt.Errorf("service: could not get incident views: %w", err)
+	}
+	// Без Redis отдается число без просмотров, еще не перенесенных в PostgreSQL
+	pending, err := s.repo.GetPendingIncidentViews(ctx, id)
+	if err != nil {
+		log.WithError(err).Warn("Failed to get pending incident views, returning persisted count")
+	}
+	return views + pending + s.views.get(id), nil
+}
+
+// IncidentViewFlusher - фоновая задача учета просмотров инцидентов: каждую секунду переносит просмотры
+// из памяти процесса в Redis, а с периодом INCIDENT_VIEW_FLUSH_INTERVAL - из Redis в PostgreSQL
+type IncidentViewFlusher struct {
+	repo    IncidentRepository
+	service IncidentService
+	logger  *logrus.Logger
+	cfg     *config.Config
+}
+
+// NewIncidentViewFlusher создает задачу переноса просмотров инцидентов
+func NewIncidentViewFlusher(repo IncidentRepository, service IncidentService, logger *logrus.Logger, cfg *config.Config) *IncidentViewFlusher {
+	return &IncidentViewFlusher{
+		repo:    repo,
+		service: service,
+		logger:  logger,
+		cfg:     cfg,
+	}
+}
+
+// Start запускает горутину переноса просмотров. При INCIDENT_VIEW_FLUSH_INTERVAL = 0 просмотры
+// не учитываются и горутина не запускается.
+func (f *IncidentViewFlusher) Start(ctx context.Context) {
+	if f.cfg.IncidentViewFlushInterval <= 0 {
+		f.logger.Info("Incident view counting is disabled (INCIDENT_VIEW_FLUSH_INTERVAL=0)")
+		return
+	}
+
+	f.logger.WithField("interval", f.cfg.IncidentViewFlushInterval).Info("Starting incident view flush job...")
+	go func() {
+		batch := time.NewTicker(incidentViewBatchInterval)
+		defer batch.Stop()
+		persist := time.NewTicker(f.cfg.IncidentViewFlushInterval)
+		defer persist.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				// Просмотры последней секунды переносятся в Redis, чтобы не потерять их при остановке
+				flushCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), incidentViewBatchInterval)
+				if err := f.service.FlushIncidentViews(flushCtx); err != nil {
+					f.logger.WithError(err).Warn("Failed to flush incident views on shutdown")
+				}
+				cancel()
+				f.logger.Info("Stopping incident view flush job.")
+				return
+			case <-batch.C:
+				if err := f.service.FlushIncidentViews(ctx); err != nil {
+					f.logger.WithError(err).Debug("Failed to flush incident views to Redis, will retry")
+				}
+			case <-persist.C:
+				f.Run(ctx)
+			}
+		}
+	}()
+}
+
+// Run переносит счетчики просмотров из Redis в PostgreSQL и возвращает число обновленных инцидентов
+func (f *IncidentViewFlusher) Run(ctx context.Context) int {
+	log := f.logger.WithFields(logrus.Fields{
+		"service": "incident",
+		"method":  "PersistIncidentViews",
+	})
+
+	persisted, err := f.repo.PersistIncidentViews(ctx)
+	if err != nil {
+		log.WithError(err).Error("Failed to persist incident views")
+		return 0
+	}
+	if persisted > 0 {
+		log.WithField("incidents", persisted).Debug("Incident views persisted")
+	}
+	return persisted
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/shenikar/geo_broadcasting_system/internal/models"
+	"github.com/shenikar/geo_broadcasting_system/pkg/geo"
+	"github.com/sirupsen/logrus"
+)
+
+// findActiveIncidents возвращает активные инциденты, в зону которых (с учетом буфера) попадает точка, и инциденты для вебхука:
+// до их зоны не больше буфера плюс буфер оповещения их уровня опасности (ALERT_BUFFER_BY_SEVERITY). Без буферов оповещения
+// оба набора совпадают. Без индекса и кэша обе ступени сопоставления выполняются в PostGIS.
+// При включенном LOCATION_TILE_CACHE_TTL в Redis кэшируются инциденты, чьи зоны пересекают geohash-ячейку точки
+// (с максимальными буферами проверки и оповещения), а точная проверка расстояния выполняется в памяти по геодезической
+// на эллипсоиде WGS84, как в PostGIS. Результат расходится с FindActiveLocation не больше чем на миллиметры на границе зоны,
+// а размер ячейки влияет только на долю попаданий в кэш. Актуальный индекс в памяти (LOCATION_INDEX_REFRESH_INTERVAL)
+// используется раньше кэша ячеек.
+func (s *incidentService) findActiveIncidents(ctx context.Context, lat, lon float64, bufferMeters, minConfidence int) (matched, alerted []*models.Incident, err error) {
+	point := geo.Point{Lat: lat, Lon: lon}
+	if candidates, ok := s.activeIndex.candidates(point); ok {
+		return s.matchIncidents(candidates, point, bufferMeters, minConfidence)
+	}
+	if s.cfg.LocationTileCacheTTL <= 0 {
+		return s.findActiveLocation(ctx, lat, lon, bufferMeters, minConfidence)
+	}
+
+	hash, cell := geo.Geohash(point, s.cfg.LocationTilePrecision)
+	log := s.logger.WithFields(logrus.Fields{
+		"service": "incident",
+		"method":  "findActiveIncidents",
+		"geohash": hash,
+	})
+
+	generation, err := s.repo.GetLocationTileGeneration(ctx)
+	if err != nil {
+		// Кэш - только оптимизация: при недоступности Redis проверяем напрямую в PostGIS
+		log.WithError(err).Warn("Failed to get location tile generation, querying database")
+		return s.findActiveLocation(ctx, lat, lon, bufferMeters, minConfidence)
+	}
+
+	candidates, err := s.repo.GetLocationTileFromCache(ctx, generation, hash)
+	if err != nil {
+		log.WithError(err).Warn("Failed to get location tile from cache")
+	}
+	if candidates == nil {
+		candidates, err = s.repo.FindActiveInArea(ctx, cell, s.cfg.MaxCheckBufferMeters+s.cfg.MaxAlertBufferMeters())
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := s.repo.SetLocationTileCache(ctx, generation, hash, candidates, s.cfg.LocationTileCacheTTL); err != nil {
+			log.WithError(err).Warn("Failed to set location tile cache")
+		}
+	}
+
+	return s.matchIncidents(candidates, point, bufferMeters, minConfidence)
+}
+
+// findActiveLocation сопоставляет точку с активными инцидентами в PostGIS; флаг оповещения по буферам уровней опасности
+// считается тем же запросом, что и попадание в зону
+func (s *incidentService) findActiveLocation(ctx context.Context, lat, lon float64, bufferMeters, minConfidence int) (matched, alerted []*models.Incident, err error) {
+	if s.cfg.MaxAlertBufferMeters() == 0 {
+		matched, err = s.repo.FindActiveLocation(ctx, lat, lon, bufferMeters, minConfidence)
+		return matched, matched, err
+	}
+	return s.repo.FindActiveLocationForAlert(ctx, lat, lon, bufferMeters, minConfidence, s.cfg.AlertBufferMeters)
+}
+
+// matchIncidents сопоставляет точку с кандидатами из индекса или кэша ячеек в памяти
+func (s *incidentService) matchIncidents(candidates []*models.Incident, point geo.Point, bufferMeters, minConfidence int) (matched, alerted []*models.Incident, err error) {
+	matched = filterIncidentsByLocation(candidates, point, bufferMeters, minConfidence)
+	if s.cfg.MaxAlertBufferMeters() == 0 {
+		return matched, matched, nil
+	}
+	return matched, filterIncidentsForAlert(candidates, point, bufferMeters, minConfidence, s.cfg.AlertBufferMeters), nil
+}
+
+// filterIncidentsByLocation оставляет инциденты достаточной достоверности, в зону которых (с учетом буфера) попадает точка.
+// Неоцененные инциденты считаются полностью достоверными, как и при фильтрации в репозитории.
+// Расстояние считается по эллипсоиду (geo.GeodesicDistanceMeters), чтобы результат совпадал с ST_DWithin по geography.
+func filterIncidentsByLocation(candidates []*models.Incident, point geo.Point, bufferMeters, minConfidence int) []*models.Incident {
+	matched := make([]*models.Incident, 0)
+	for _, incident := range candidates {
+		if incident.Confidence != nil && *incident.Confidence < minConfidence {
+			continue
+		}
+		center := geo.Point{Lat: incident.Latitude, Lon: incident.Longitude}
+		if geo.GeodesicDistanceMeters(point, center) <= float64(incident.RadiusMeters+bufferMeters) {
+			matched = append(matched, incident)
+		}
+	}
+	return matched
+}
+
+// filterIncidentsForAlert оставляет инциденты, в зону которых с учетом буфера проверки и буфера оповещения
+// по уровню опасности (ALERT_BUFFER_BY_SEVERITY) попадает точка. Это вторая ступень сопоставления:
+// ее результат определяет только содержимое вебхука. Расстояние считается по эллипсоиду, как в FindActiveLocationForAlert.
+func filterIncidentsForAlert(candidates []*models.Incident, point geo.Point, bufferMeters, minConfidence int, alertBuffers map[models.IncidentSeverity]int) []*models.Incident {
+	matched := make([]*models.Incident, 0)
+	for _, incident := range candidates {
+		if incident.Confidence != nil && *incident.Confidence < minConfidence {
+			continue
+		}
+		center := geo.Point{Lat: incident.Latitude, Lon: incident.Longitude}
+		if geo.GeodesicDistanceMeters(point, center) <= float64(incident.RadiusMeters+bufferMeters+alertBuffers[incident.Severity]) {
+			matched = append(matched, incident)
+		}
+	}
+	return matched
+}
+
+// sortIncidentsByPriority упорядочивает инциденты по убыванию уровня опасности, а инциденты одного уровня -
+// по расстоянию от точки до центра зоны
+func sortIncidentsByPriority(incidents []*models.Incident, point geo.Point) {
+	slices.SortStableFunc(incidents, func(a, b *models.Incident) int {
+		if byRank := cmp.Compare(b.Severity.Rank(), a.Severity.Rank()); byRank != 0 {
+			return byRank
+		}
+		return cmp.Compare(
+			geo.DistanceMeters(point, geo.Point{Lat: a.Latitude, Lon: a.Longitude}),
+			geo.DistanceMeters(point, geo.Point{Lat: b.Latitude, Lon: b.Longitude}),
+		)
+	})
+}
+
+// invalidateLocationTiles сбрасывает индекс в памяти и кэш ячеек (если он включен) после изменения инцидентов
+// и будит запросы long polling, ожидающие изменений
+func (s *incidentService) invalidateLocationTiles(ctx context.Context, log *logrus.Entry) {
+	s.activeIndex.Invalidate()
+	s.changes.notify()
+	if s.cfg.LocationTileCacheTTL <= 0 {
+		return
+	}
+	if err := s.repo.InvalidateLocationTiles(ctx); err != nil {
+		log.WithError(err).Warn("Failed to invalidate location tile cache")
+	}
+}
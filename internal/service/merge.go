@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shenikar/geo_broadcasting_system/internal/models"
+	"github.com/shenikar/geo_broadcasting_system/internal/webhook"
+	"github.com/sirupsen/logrus"
+)
+
+// MergeIncidents сливает дубликаты одного события: первый из ids остается каноническим инцидентом
+// с зоной, покрывающей зоны всех участников, остальные деактивируются, а их совпадения проверок
+// переносятся на канонический. Публикует incident_updated для канонического инцидента
+// и incident_deactivated для каждого поглощенного.
+func (s *incidentService) MergeIncidents(ctx context.Context, ids []uuid.UUID, mergedBy string) (*models.IncidentMergeResult, error) {
+	log := s.logger.WithFields(logrus.Fields{
+		"service":   "incident",
+		"method":    "MergeIncidents",
+		"ids_count": len(ids),
+		"merged_by": mergedBy,
+	})
+	log.Info("Attempting to merge incidents")
+
+	seen := make(map[uuid.UUID]struct{}, len(ids))
+	for _, id := range ids {
+		seen[id] = struct{}{}
+	}
+	if len(ids) < 2 || len(seen) != len(ids) {
+		log.Warn("Refusing merge without at least two distinct incidents")
+		return nil, ErrInvalidMerge
+	}
+
+	merge := models.IncidentMerge{CanonicalID: ids[0], DuplicateIDs: ids[1:], MergedBy: mergedBy}
+	result, err := s.repo.MergeIncidents(ctx, merge)
+	if err != nil {
+		log.WithError(err).Error("Failed to merge incidents in repository")
+		return nil, fmt.Errorf("service: could not merge incidents: %w", err)
+	}
+
+	invalidateIncidentCache(ctx, s.repo, log, ids...)
+	s.invalidateLocationTiles(ctx, log)
+
+	now := time.Now()
+	canonical := result.Incident
+	if changes := diffIncidents(result.Before, canonical); len(changes) > 0 {
+		event := webhook.WebhookEvent{
+			SchemaVersion: webhook.SchemaVersion,
+			Type:          webhook.EventTypeIncidentUpdated,
+			Latitude:      canonical.Latitude,
+			Longitude:     canonical.Longitude,
+			Timestamp:     now,
+			Incidents:     []*models.Incident{canonical},
+			ChangedFields: changes,
+		}
+		if err := s.webhookPublisher.Publish(ctx, event); err != nil {
+			log.WithError(err).WithField("incident_id", canonical.ID).Error("Failed to publish incident updated webhook")
+		}
+	}
+	for _, incident := range result.Merged {
+		event := webhook.WebhookEvent{
+			SchemaVersion: webhook.SchemaVersion,
+			Type:          webhook.EventTypeIncidentDeactivated,
+			Latitude:      incident.Latitude,
+			Longitude:     incident.Longitude,
+			Timestamp:     now,
+			Incidents:     []*models.Incident{incident},
+		}
+		if err := s.webhookPublisher.Publish(ctx, event); err != nil {
+			log.WithError(err).WithField("incident_id", incident.ID).Error("Failed to publish incident deactivated webhook")
+		}
+	}
+
+	log.WithFields(logrus.Fields{
+		"canonical_id":      canonical.ID,
+		"radius_meters":     canonical.RadiusMeters,
+		"redirected_checks": result.RedirectedChecks,
+	}).Info("Incidents merged successfully")
+	return result, nil
+}
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// locationCheckDuration - длительность проверки местоположения по числу совпавших инцидентов.
+// Число инцидентов сводится к фиксированным группам (см. matchedIncidentsBucket), чтобы ограничить число рядов.
+var locationCheckDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "geo_location_check_duration_seconds",
+	Help:    "Duration of location checks, by number of matched incidents.",
+	Buckets: prometheus.DefBuckets,
+}, []string{"matched"})
+
+// cacheInvalidationFailures - число инцидентов, кэш которых не удалось удалить после изменения.
+// Метка fallback: expired - записи кэша выставлен короткий срок жизни, failed - не удалось и это.
+var cacheInvalidationFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "geo_incident_cache_invalidation_failures_total",
+	Help: "Incident cache invalidations that failed after retries, by fallback outcome.",
+}, []string{"fallback"})
+
+// matchedIncidentsBucket возвращает значение метки matched для числа совпавших инцидентов
+func matchedIncidentsBucket(count int) string {
+	switch {
+	case count == 0:
+		return "0"
+	case count == 1:
+		return "1"
+	case count <= 5:
+		return "2-5"
+	default:
+		return "6+"
+	}
+}
@@ -12,9 +12,12 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	uuid "github.com/google/uuid"
 	models "github.com/shenikar/geo_broadcasting_system/internal/models"
+	webhook "github.com/shenikar/geo_broadcasting_system/internal/webhook"
+	geo "github.com/shenikar/geo_broadcasting_system/pkg/geo"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -42,287 +45,2140 @@ func (m *MockIncidentRepository) EXPECT() *MockIncidentRepositoryMockRecorder {
 	return m.recorder
 }
 
-// Create mocks base method.
-func (m *MockIncidentRepository) Create(ctx context.Context, incident *models.Incident) error {
+// AddAPIKey mocks base method.
+func (m *MockIncidentRepository) AddAPIKey(ctx context.Context, keyHash string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", ctx, incident)
+	ret := m.ctrl.Call(m, "AddAPIKey", ctx, keyHash)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// Create indicates an expected call of Create.
-func (mr *MockIncidentRepositoryMockRecorder) Create(ctx, incident any) *gomock.Call {
+// AddAPIKey indicates an expected call of AddAPIKey.
+func (mr *MockIncidentRepositoryMockRecorder) AddAPIKey(ctx, keyHash any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockIncidentRepository)(nil).Create), ctx, incident)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAPIKey", reflect.TypeOf((*MockIncidentRepository)(nil).AddAPIKey), ctx, keyHash)
 }
 
-// Delete mocks base method.
-func (m *MockIncidentRepository) Delete(ctx context.Context, id uuid.UUID) error {
+// ChangeStatus mocks base method.
+func (m *MockIncidentRepository) ChangeStatus(ctx context.Context, change *models.StatusChange) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret := m.ctrl.Call(m, "ChangeStatus", ctx, change)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// Delete indicates an expected call of Delete.
-func (mr *MockIncidentRepositoryMockRecorder) Delete(ctx, id any) *gomock.Call {
+// ChangeStatus indicates an expected call of ChangeStatus.
+func (mr *MockIncidentRepositoryMockRecorder) ChangeStatus(ctx, change any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockIncidentRepository)(nil).Delete), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeStatus", reflect.TypeOf((*MockIncidentRepository)(nil).ChangeStatus), ctx, change)
 }
 
-// FindActiveLocation mocks base method.
-func (m *MockIncidentRepository) FindActiveLocation(ctx context.Context, lat, lon float64) ([]*models.Incident, error) {
+// ClaimDueImportFeeds mocks base method.
+func (m *MockIncidentRepository) ClaimDueImportFeeds(ctx context.Context, interval time.Duration) ([]*models.ImportFeed, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindActiveLocation", ctx, lat, lon)
-	ret0, _ := ret[0].([]*models.Incident)
+	ret := m.ctrl.Call(m, "ClaimDueImportFeeds", ctx, interval)
+	ret0, _ := ret[0].([]*models.ImportFeed)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// FindActiveLocation indicates an expected call of FindActiveLocation.
-func (mr *MockIncidentRepositoryMockRecorder) FindActiveLocation(ctx, lat, lon any) *gomock.Call {
+// ClaimDueImportFeeds indicates an expected call of ClaimDueImportFeeds.
+func (mr *MockIncidentRepositoryMockRecorder) ClaimDueImportFeeds(ctx, interval any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindActiveLocation", reflect.TypeOf((*MockIncidentRepository)(nil).FindActiveLocation), ctx, lat, lon)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDueImportFeeds", reflect.TypeOf((*MockIncidentRepository)(nil).ClaimDueImportFeeds), ctx, interval)
 }
 
-// GetByID mocks base method.
-func (m *MockIncidentRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Incident, error) {
+// ClusterActiveIncidents mocks base method.
+func (m *MockIncidentRepository) ClusterActiveIncidents(ctx context.Context, bbox geo.BBox, gridSize float64) ([]*models.IncidentCluster, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByID", ctx, id)
-	ret0, _ := ret[0].(*models.Incident)
+	ret := m.ctrl.Call(m, "ClusterActiveIncidents", ctx, bbox, gridSize)
+	ret0, _ := ret[0].([]*models.IncidentCluster)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetByID indicates an expected call of GetByID.
-func (mr *MockIncidentRepositoryMockRecorder) GetByID(ctx, id any) *gomock.Call {
+// ClusterActiveIncidents indicates an expected call of ClusterActiveIncidents.
+func (mr *MockIncidentRepositoryMockRecorder) ClusterActiveIncidents(ctx, bbox, gridSize any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockIncidentRepository)(nil).GetByID), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClusterActiveIncidents", reflect.TypeOf((*MockIncidentRepository)(nil).ClusterActiveIncidents), ctx, bbox, gridSize)
 }
 
-// GetIncidentFromCache mocks base method.
-func (m *MockIncidentRepository) GetIncidentFromCache(ctx context.Context, id uuid.UUID) (*models.Incident, error) {
+// CountAPIKeyUsage mocks base method.
+func (m *MockIncidentRepository) CountAPIKeyUsage(ctx context.Context, buckets []time.Time) (map[string]int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetIncidentFromCache", ctx, id)
-	ret0, _ := ret[0].(*models.Incident)
+	ret := m.ctrl.Call(m, "CountAPIKeyUsage", ctx, buckets)
+	ret0, _ := ret[0].(map[string]int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetIncidentFromCache indicates an expected call of GetIncidentFromCache.
-func (mr *MockIncidentRepositoryMockRecorder) GetIncidentFromCache(ctx, id any) *gomock.Call {
+// CountAPIKeyUsage indicates an expected call of CountAPIKeyUsage.
+func (mr *MockIncidentRepositoryMockRecorder) CountAPIKeyUsage(ctx, buckets any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIncidentFromCache", reflect.TypeOf((*MockIncidentRepository)(nil).GetIncidentFromCache), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountAPIKeyUsage", reflect.TypeOf((*MockIncidentRepository)(nil).CountAPIKeyUsage), ctx, buckets)
 }
 
-// GetLocationCheckStats mocks base method.
-func (m *MockIncidentRepository) GetLocationCheckStats(ctx context.Context, minutes int) (int, error) {
+// CountLocationChecks mocks base method.
+func (m *MockIncidentRepository) CountLocationChecks(ctx context.Context, from, to time.Time) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetLocationCheckStats", ctx, minutes)
+	ret := m.ctrl.Call(m, "CountLocationChecks", ctx, from, to)
 	ret0, _ := ret[0].(int)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetLocationCheckStats indicates an expected call of GetLocationCheckStats.
-func (mr *MockIncidentRepositoryMockRecorder) GetLocationCheckStats(ctx, minutes any) *gomock.Call {
+// CountLocationChecks indicates an expected call of CountLocationChecks.
+func (mr *MockIncidentRepositoryMockRecorder) CountLocationChecks(ctx, from, to any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLocationCheckStats", reflect.TypeOf((*MockIncidentRepository)(nil).GetLocationCheckStats), ctx, minutes)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountLocationChecks", reflect.TypeOf((*MockIncidentRepository)(nil).CountLocationChecks), ctx, from, to)
 }
 
-// InvalidateIncidentCache mocks base method.
-func (m *MockIncidentRepository) InvalidateIncidentCache(ctx context.Context, id uuid.UUID) error {
+// CountRecentUsersInArea mocks base method.
+func (m *MockIncidentRepository) CountRecentUsersInArea(ctx context.Context, lat, lon float64, radiusMeters, minutes int) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "InvalidateIncidentCache", ctx, id)
+	ret := m.ctrl.Call(m, "CountRecentUsersInArea", ctx, lat, lon, radiusMeters, minutes)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountRecentUsersInArea indicates an expected call of CountRecentUsersInArea.
+func (mr *MockIncidentRepositoryMockRecorder) CountRecentUsersInArea(ctx, lat, lon, radiusMeters, minutes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountRecentUsersInArea", reflect.TypeOf((*MockIncidentRepository)(nil).CountRecentUsersInArea), ctx, lat, lon, radiusMeters, minutes)
+}
+
+// Create mocks base method.
+func (m *MockIncidentRepository) Create(ctx context.Context, incident *models.Incident) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, incident)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// InvalidateIncidentCache indicates an expected call of InvalidateIncidentCache.
-func (mr *MockIncidentRepositoryMockRecorder) InvalidateIncidentCache(ctx, id any) *gomock.Call {
+// Create indicates an expected call of Create.
+func (mr *MockIncidentRepositoryMockRecorder) Create(ctx, incident any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvalidateIncidentCache", reflect.TypeOf((*MockIncidentRepository)(nil).InvalidateIncidentCache), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockIncidentRepository)(nil).Create), ctx, incident)
 }
 
-// ListIncidents mocks base method.
-func (m *MockIncidentRepository) ListIncidents(ctx context.Context, page, pageSize int) ([]*models.Incident, error) {
+// CreateImportFeed mocks base method.
+func (m *MockIncidentRepository) CreateImportFeed(ctx context.Context, feed *models.ImportFeed) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListIncidents", ctx, page, pageSize)
-	ret0, _ := ret[0].([]*models.Incident)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "CreateImportFeed", ctx, feed)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// ListIncidents indicates an expected call of ListIncidents.
-func (mr *MockIncidentRepositoryMockRecorder) ListIncidents(ctx, page, pageSize any) *gomock.Call {
+// CreateImportFeed indicates an expected call of CreateImportFeed.
+func (mr *MockIncidentRepositoryMockRecorder) CreateImportFeed(ctx, feed any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIncidents", reflect.TypeOf((*MockIncidentRepository)(nil).ListIncidents), ctx, page, pageSize)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateImportFeed", reflect.TypeOf((*MockIncidentRepository)(nil).CreateImportFeed), ctx, feed)
 }
 
-// SaveLocationCheck mocks base method.
-func (m *MockIncidentRepository) SaveLocationCheck(ctx context.Context, check *models.LocationCheck) error {
+// CreateIncidentAttachment mocks base method.
+func (m *MockIncidentRepository) CreateIncidentAttachment(ctx context.Context, attachment *models.IncidentAttachment) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SaveLocationCheck", ctx, check)
+	ret := m.ctrl.Call(m, "CreateIncidentAttachment", ctx, attachment)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// SaveLocationCheck indicates an expected call of SaveLocationCheck.
-func (mr *MockIncidentRepositoryMockRecorder) SaveLocationCheck(ctx, check any) *gomock.Call {
+// CreateIncidentAttachment indicates an expected call of CreateIncidentAttachment.
+func (mr *MockIncidentRepositoryMockRecorder) CreateIncidentAttachment(ctx, attachment any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveLocationCheck", reflect.TypeOf((*MockIncidentRepository)(nil).SaveLocationCheck), ctx, check)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIncidentAttachment", reflect.TypeOf((*MockIncidentRepository)(nil).CreateIncidentAttachment), ctx, attachment)
 }
 
-// SetIncidentCache mocks base method.
-func (m *MockIncidentRepository) SetIncidentCache(ctx context.Context, incident *models.Incident) error {
+// CreateIncidentTemplate mocks base method.
+func (m *MockIncidentRepository) CreateIncidentTemplate(ctx context.Context, template *models.IncidentTemplate) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetIncidentCache", ctx, incident)
+	ret := m.ctrl.Call(m, "CreateIncidentTemplate", ctx, template)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// SetIncidentCache indicates an expected call of SetIncidentCache.
-func (mr *MockIncidentRepositoryMockRecorder) SetIncidentCache(ctx, incident any) *gomock.Call {
+// CreateIncidentTemplate indicates an expected call of CreateIncidentTemplate.
+func (mr *MockIncidentRepositoryMockRecorder) CreateIncidentTemplate(ctx, template any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetIncidentCache", reflect.TypeOf((*MockIncidentRepository)(nil).SetIncidentCache), ctx, incident)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIncidentTemplate", reflect.TypeOf((*MockIncidentRepository)(nil).CreateIncidentTemplate), ctx, template)
 }
 
-// Update mocks base method.
-func (m *MockIncidentRepository) Update(ctx context.Context, incident *models.Incident) error {
+// CreateIncidentWatcher mocks base method.
+func (m *MockIncidentRepository) CreateIncidentWatcher(ctx context.Context, watcher *models.IncidentWatcher) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", ctx, incident)
+	ret := m.ctrl.Call(m, "CreateIncidentWatcher", ctx, watcher)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// Update indicates an expected call of Update.
-func (mr *MockIncidentRepositoryMockRecorder) Update(ctx, incident any) *gomock.Call {
+// CreateIncidentWatcher indicates an expected call of CreateIncidentWatcher.
+func (mr *MockIncidentRepositoryMockRecorder) CreateIncidentWatcher(ctx, watcher any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockIncidentRepository)(nil).Update), ctx, incident)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIncidentWatcher", reflect.TypeOf((*MockIncidentRepository)(nil).CreateIncidentWatcher), ctx, watcher)
 }
 
-// MockIncidentService is a mock of IncidentService interface.
-type MockIncidentService struct {
-	ctrl     *gomock.Controller
-	recorder *MockIncidentServiceMockRecorder
-	isgomock struct{}
+// CreateUserLocation mocks base method.
+func (m *MockIncidentRepository) CreateUserLocation(ctx context.Context, location *models.UserLocation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUserLocation", ctx, location)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// MockIncidentServiceMockRecorder is the mock recorder for MockIncidentService.
-type MockIncidentServiceMockRecorder struct {
-	mock *MockIncidentService
+// CreateUserLocation indicates an expected call of CreateUserLocation.
+func (mr *MockIncidentRepositoryMockRecorder) CreateUserLocation(ctx, location any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserLocation", reflect.TypeOf((*MockIncidentRepository)(nil).CreateUserLocation), ctx, location)
 }
 
-// NewMockIncidentService creates a new mock instance.
-func NewMockIncidentService(ctrl *gomock.Controller) *MockIncidentService {
-	mock := &MockIncidentService{ctrl: ctrl}
-	mock.recorder = &MockIncidentServiceMockRecorder{mock}
-	return mock
+// CurrentTime mocks base method.
+func (m *MockIncidentRepository) CurrentTime(ctx context.Context) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CurrentTime", ctx)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockIncidentService) EXPECT() *MockIncidentServiceMockRecorder {
-	return m.recorder
+// CurrentTime indicates an expected call of CurrentTime.
+func (mr *MockIncidentRepositoryMockRecorder) CurrentTime(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CurrentTime", reflect.TypeOf((*MockIncidentRepository)(nil).CurrentTime), ctx)
 }
 
-// CheckLocation mocks base method.
-func (m *MockIncidentService) CheckLocation(ctx context.Context, userID string, lat, lon float64) ([]*models.Incident, error) {
+// DeactivateIncidents mocks base method.
+func (m *MockIncidentRepository) DeactivateIncidents(ctx context.Context, target models.BulkDeactivation) ([]*models.Incident, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CheckLocation", ctx, userID, lat, lon)
+	ret := m.ctrl.Call(m, "DeactivateIncidents", ctx, target)
 	ret0, _ := ret[0].([]*models.Incident)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CheckLocation indicates an expected call of CheckLocation.
-func (mr *MockIncidentServiceMockRecorder) CheckLocation(ctx, userID, lat, lon any) *gomock.Call {
+// DeactivateIncidents indicates an expected call of DeactivateIncidents.
+func (mr *MockIncidentRepositoryMockRecorder) DeactivateIncidents(ctx, target any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckLocation", reflect.TypeOf((*MockIncidentService)(nil).CheckLocation), ctx, userID, lat, lon)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeactivateIncidents", reflect.TypeOf((*MockIncidentRepository)(nil).DeactivateIncidents), ctx, target)
 }
 
-// CreateIncident mocks base method.
-func (m *MockIncidentService) CreateIncident(ctx context.Context, incident *models.Incident) error {
+// Delete mocks base method.
+func (m *MockIncidentRepository) Delete(ctx context.Context, id uuid.UUID, from models.IncidentStatus, resolution models.Resolution) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateIncident", ctx, incident)
+	ret := m.ctrl.Call(m, "Delete", ctx, id, from, resolution)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// CreateIncident indicates an expected call of CreateIncident.
-func (mr *MockIncidentServiceMockRecorder) CreateIncident(ctx, incident any) *gomock.Call {
+// Delete indicates an expected call of Delete.
+func (mr *MockIncidentRepositoryMockRecorder) Delete(ctx, id, from, resolution any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIncident", reflect.TypeOf((*MockIncidentService)(nil).CreateIncident), ctx, incident)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockIncidentRepository)(nil).Delete), ctx, id, from, resolution)
 }
 
-// DeactivateIncident mocks base method.
-func (m *MockIncidentService) DeactivateIncident(ctx context.Context, id uuid.UUID) error {
+// DeleteImportFeed mocks base method.
+func (m *MockIncidentRepository) DeleteImportFeed(ctx context.Context, id uuid.UUID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeactivateIncident", ctx, id)
+	ret := m.ctrl.Call(m, "DeleteImportFeed", ctx, id)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// DeactivateIncident indicates an expected call of DeactivateIncident.
-func (mr *MockIncidentServiceMockRecorder) DeactivateIncident(ctx, id any) *gomock.Call {
+// DeleteImportFeed indicates an expected call of DeleteImportFeed.
+func (mr *MockIncidentRepositoryMockRecorder) DeleteImportFeed(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeactivateIncident", reflect.TypeOf((*MockIncidentService)(nil).DeactivateIncident), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteImportFeed", reflect.TypeOf((*MockIncidentRepository)(nil).DeleteImportFeed), ctx, id)
 }
 
-// GetIncident mocks base method.
-func (m *MockIncidentService) GetIncident(ctx context.Context, id uuid.UUID) (*models.Incident, error) {
+// DeleteIncidentTemplate mocks base method.
+func (m *MockIncidentRepository) DeleteIncidentTemplate(ctx context.Context, id uuid.UUID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetIncident", ctx, id)
+	ret := m.ctrl.Call(m, "DeleteIncidentTemplate", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteIncidentTemplate indicates an expected call of DeleteIncidentTemplate.
+func (mr *MockIncidentRepositoryMockRecorder) DeleteIncidentTemplate(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteIncidentTemplate", reflect.TypeOf((*MockIncidentRepository)(nil).DeleteIncidentTemplate), ctx, id)
+}
+
+// DeleteIncidentWatcher mocks base method.
+func (m *MockIncidentRepository) DeleteIncidentWatcher(ctx context.Context, incidentID, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteIncidentWatcher", ctx, incidentID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteIncidentWatcher indicates an expected call of DeleteIncidentWatcher.
+func (mr *MockIncidentRepositoryMockRecorder) DeleteIncidentWatcher(ctx, incidentID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteIncidentWatcher", reflect.TypeOf((*MockIncidentRepository)(nil).DeleteIncidentWatcher), ctx, incidentID, id)
+}
+
+// DeleteUserLocation mocks base method.
+func (m *MockIncidentRepository) DeleteUserLocation(ctx context.Context, userID string, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserLocation", ctx, userID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserLocation indicates an expected call of DeleteUserLocation.
+func (mr *MockIncidentRepositoryMockRecorder) DeleteUserLocation(ctx, userID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserLocation", reflect.TypeOf((*MockIncidentRepository)(nil).DeleteUserLocation), ctx, userID, id)
+}
+
+// EscalateIncident mocks base method.
+func (m *MockIncidentRepository) EscalateIncident(ctx context.Context, id uuid.UUID, from, to models.IncidentSeverity) (*models.Incident, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EscalateIncident", ctx, id, from, to)
 	ret0, _ := ret[0].(*models.Incident)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetIncident indicates an expected call of GetIncident.
-func (mr *MockIncidentServiceMockRecorder) GetIncident(ctx, id any) *gomock.Call {
+// EscalateIncident indicates an expected call of EscalateIncident.
+func (mr *MockIncidentRepositoryMockRecorder) EscalateIncident(ctx, id, from, to any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIncident", reflect.TypeOf((*MockIncidentService)(nil).GetIncident), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EscalateIncident", reflect.TypeOf((*MockIncidentRepository)(nil).EscalateIncident), ctx, id, from, to)
 }
 
-// GetStats mocks base method.
-func (m *MockIncidentService) GetStats(ctx context.Context) (int, error) {
+// ExpireIncidentCache mocks base method.
+func (m *MockIncidentRepository) ExpireIncidentCache(ctx context.Context, ttl time.Duration, ids ...uuid.UUID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetStats", ctx)
-	ret0, _ := ret[0].(int)
+	varargs := []any{ctx, ttl}
+	for _, a := range ids {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ExpireIncidentCache", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExpireIncidentCache indicates an expected call of ExpireIncidentCache.
+func (mr *MockIncidentRepositoryMockRecorder) ExpireIncidentCache(ctx, ttl any, ids ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, ttl}, ids...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireIncidentCache", reflect.TypeOf((*MockIncidentRepository)(nil).ExpireIncidentCache), varargs...)
+}
+
+// FindActiveInArea mocks base method.
+func (m *MockIncidentRepository) FindActiveInArea(ctx context.Context, bbox geo.BBox, bufferMeters int) ([]*models.Incident, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindActiveInArea", ctx, bbox, bufferMeters)
+	ret0, _ := ret[0].([]*models.Incident)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetStats indicates an expected call of GetStats.
-func (mr *MockIncidentServiceMockRecorder) GetStats(ctx any) *gomock.Call {
+// FindActiveInArea indicates an expected call of FindActiveInArea.
+func (mr *MockIncidentRepositoryMockRecorder) FindActiveInArea(ctx, bbox, bufferMeters any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStats", reflect.TypeOf((*MockIncidentService)(nil).GetStats), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindActiveInArea", reflect.TypeOf((*MockIncidentRepository)(nil).FindActiveInArea), ctx, bbox, bufferMeters)
 }
 
-// ListIncidents mocks base method.
-func (m *MockIncidentService) ListIncidents(ctx context.Context, page, pageSize int) ([]*models.Incident, error) {
+// FindActiveLocation mocks base method.
+func (m *MockIncidentRepository) FindActiveLocation(ctx context.Context, lat, lon float64, bufferMeters, minConfidence int) ([]*models.Incident, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListIncidents", ctx, page, pageSize)
+	ret := m.ctrl.Call(m, "FindActiveLocation", ctx, lat, lon, bufferMeters, minConfidence)
 	ret0, _ := ret[0].([]*models.Incident)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListIncidents indicates an expected call of ListIncidents.
-func (mr *MockIncidentServiceMockRecorder) ListIncidents(ctx, page, pageSize any) *gomock.Call {
+// FindActiveLocation indicates an expected call of FindActiveLocation.
+func (mr *MockIncidentRepositoryMockRecorder) FindActiveLocation(ctx, lat, lon, bufferMeters, minConfidence any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIncidents", reflect.TypeOf((*MockIncidentService)(nil).ListIncidents), ctx, page, pageSize)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindActiveLocation", reflect.TypeOf((*MockIncidentRepository)(nil).FindActiveLocation), ctx, lat, lon, bufferMeters, minConfidence)
 }
 
-// UpdateIncident mocks base method.
-func (m *MockIncidentService) UpdateIncident(ctx context.Context, incident *models.Incident) error {
+// FindActiveLocationForAlert mocks base method.
+func (m *MockIncidentRepository) FindActiveLocationForAlert(ctx context.Context, lat, lon float64, bufferMeters, minConfidence int, alertBuffers map[models.IncidentSeverity]int) ([]*models.Incident, []*models.Incident, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateIncident", ctx, incident)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "FindActiveLocationForAlert", ctx, lat, lon, bufferMeters, minConfidence, alertBuffers)
+	ret0, _ := ret[0].([]*models.Incident)
+	ret1, _ := ret[1].([]*models.Incident)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
-// UpdateIncident indicates an expected call of UpdateIncident.
-func (mr *MockIncidentServiceMockRecorder) UpdateIncident(ctx, incident any) *gomock.Call {
+// FindActiveLocationForAlert indicates an expected call of FindActiveLocationForAlert.
+func (mr *MockIncidentRepositoryMockRecorder) FindActiveLocationForAlert(ctx, lat, lon, bufferMeters, minConfidence, alertBuffers any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateIncident", reflect.TypeOf((*MockIncidentService)(nil).UpdateIncident), ctx, incident)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindActiveLocationForAlert", reflect.TypeOf((*MockIncidentRepository)(nil).FindActiveLocationForAlert), ctx, lat, lon, bufferMeters, minConfidence, alertBuffers)
+}
+
+// FindHotspots mocks base method.
+func (m *MockIncidentRepository) FindHotspots(ctx context.Context, bbox geo.BBox, latSize, lonSize float64, minCount int) ([]*models.IncidentHotspot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindHotspots", ctx, bbox, latSize, lonSize, minCount)
+	ret0, _ := ret[0].([]*models.IncidentHotspot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindHotspots indicates an expected call of FindHotspots.
+func (mr *MockIncidentRepositoryMockRecorder) FindHotspots(ctx, bbox, latSize, lonSize, minCount any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindHotspots", reflect.TypeOf((*MockIncidentRepository)(nil).FindHotspots), ctx, bbox, latSize, lonSize, minCount)
+}
+
+// FindMissedLocationAlerts mocks base method.
+func (m *MockIncidentRepository) FindMissedLocationAlerts(ctx context.Context, from, to time.Time, afterID int64, limit int) ([]*models.LocationCheck, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindMissedLocationAlerts", ctx, from, to, afterID, limit)
+	ret0, _ := ret[0].([]*models.LocationCheck)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindMissedLocationAlerts indicates an expected call of FindMissedLocationAlerts.
+func (mr *MockIncidentRepositoryMockRecorder) FindMissedLocationAlerts(ctx, from, to, afterID, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindMissedLocationAlerts", reflect.TypeOf((*MockIncidentRepository)(nil).FindMissedLocationAlerts), ctx, from, to, afterID, limit)
+}
+
+// FlushCaches mocks base method.
+func (m *MockIncidentRepository) FlushCaches(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FlushCaches", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FlushCaches indicates an expected call of FlushCaches.
+func (mr *MockIncidentRepositoryMockRecorder) FlushCaches(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FlushCaches", reflect.TypeOf((*MockIncidentRepository)(nil).FlushCaches), ctx)
+}
+
+// GetByExternalID mocks base method.
+func (m *MockIncidentRepository) GetByExternalID(ctx context.Context, externalID string) (*models.Incident, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByExternalID", ctx, externalID)
+	ret0, _ := ret[0].(*models.Incident)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByExternalID indicates an expected call of GetByExternalID.
+func (mr *MockIncidentRepositoryMockRecorder) GetByExternalID(ctx, externalID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByExternalID", reflect.TypeOf((*MockIncidentRepository)(nil).GetByExternalID), ctx, externalID)
+}
+
+// GetByID mocks base method.
+func (m *MockIncidentRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Incident, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(*models.Incident)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockIncidentRepositoryMockRecorder) GetByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockIncidentRepository)(nil).GetByID), ctx, id)
+}
+
+// GetFeatureFlag mocks base method.
+func (m *MockIncidentRepository) GetFeatureFlag(ctx context.Context, name string) (bool, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFeatureFlag", ctx, name)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetFeatureFlag indicates an expected call of GetFeatureFlag.
+func (mr *MockIncidentRepositoryMockRecorder) GetFeatureFlag(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFeatureFlag", reflect.TypeOf((*MockIncidentRepository)(nil).GetFeatureFlag), ctx, name)
+}
+
+// GetImportJob mocks base method.
+func (m *MockIncidentRepository) GetImportJob(ctx context.Context, id uuid.UUID) (*models.ImportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetImportJob", ctx, id)
+	ret0, _ := ret[0].(*models.ImportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetImportJob indicates an expected call of GetImportJob.
+func (mr *MockIncidentRepositoryMockRecorder) GetImportJob(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImportJob", reflect.TypeOf((*MockIncidentRepository)(nil).GetImportJob), ctx, id)
+}
+
+// GetIncidentFromCache mocks base method.
+func (m *MockIncidentRepository) GetIncidentFromCache(ctx context.Context, id uuid.UUID) (*models.Incident, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIncidentFromCache", ctx, id)
+	ret0, _ := ret[0].(*models.Incident)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIncidentFromCache indicates an expected call of GetIncidentFromCache.
+func (mr *MockIncidentRepositoryMockRecorder) GetIncidentFromCache(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIncidentFromCache", reflect.TypeOf((*MockIncidentRepository)(nil).GetIncidentFromCache), ctx, id)
+}
+
+// GetIncidentImpact mocks base method.
+func (m *MockIncidentRepository) GetIncidentImpact(ctx context.Context, incidentID uuid.UUID, minutes int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIncidentImpact", ctx, incidentID, minutes)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIncidentImpact indicates an expected call of GetIncidentImpact.
+func (mr *MockIncidentRepositoryMockRecorder) GetIncidentImpact(ctx, incidentID, minutes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIncidentImpact", reflect.TypeOf((*MockIncidentRepository)(nil).GetIncidentImpact), ctx, incidentID, minutes)
+}
+
+// GetIncidentTemplate mocks base method.
+func (m *MockIncidentRepository) GetIncidentTemplate(ctx context.Context, id uuid.UUID) (*models.IncidentTemplate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIncidentTemplate", ctx, id)
+	ret0, _ := ret[0].(*models.IncidentTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIncidentTemplate indicates an expected call of GetIncidentTemplate.
+func (mr *MockIncidentRepositoryMockRecorder) GetIncidentTemplate(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIncidentTemplate", reflect.TypeOf((*MockIncidentRepository)(nil).GetIncidentTemplate), ctx, id)
+}
+
+// GetIncidentVersion mocks base method.
+func (m *MockIncidentRepository) GetIncidentVersion(ctx context.Context, incidentID uuid.UUID, version int) (*models.IncidentVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIncidentVersion", ctx, incidentID, version)
+	ret0, _ := ret[0].(*models.IncidentVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIncidentVersion indicates an expected call of GetIncidentVersion.
+func (mr *MockIncidentRepositoryMockRecorder) GetIncidentVersion(ctx, incidentID, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIncidentVersion", reflect.TypeOf((*MockIncidentRepository)(nil).GetIncidentVersion), ctx, incidentID, version)
+}
+
+// GetIncidentViews mocks base method.
+func (m *MockIncidentRepository) GetIncidentViews(ctx context.Context, id uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIncidentViews", ctx, id)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIncidentViews indicates an expected call of GetIncidentViews.
+func (mr *MockIncidentRepositoryMockRecorder) GetIncidentViews(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIncidentViews", reflect.TypeOf((*MockIncidentRepository)(nil).GetIncidentViews), ctx, id)
+}
+
+// GetLocationCheckStats mocks base method.
+func (m *MockIncidentRepository) GetLocationCheckStats(ctx context.Context, minutes int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLocationCheckStats", ctx, minutes)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLocationCheckStats indicates an expected call of GetLocationCheckStats.
+func (mr *MockIncidentRepositoryMockRecorder) GetLocationCheckStats(ctx, minutes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLocationCheckStats", reflect.TypeOf((*MockIncidentRepository)(nil).GetLocationCheckStats), ctx, minutes)
+}
+
+// GetLocationTileFromCache mocks base method.
+func (m *MockIncidentRepository) GetLocationTileFromCache(ctx context.Context, generation int64, geohash string) ([]*models.Incident, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLocationTileFromCache", ctx, generation, geohash)
+	ret0, _ := ret[0].([]*models.Incident)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLocationTileFromCache indicates an expected call of GetLocationTileFromCache.
+func (mr *MockIncidentRepositoryMockRecorder) GetLocationTileFromCache(ctx, generation, geohash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLocationTileFromCache", reflect.TypeOf((*MockIncidentRepository)(nil).GetLocationTileFromCache), ctx, generation, geohash)
+}
+
+// GetLocationTileGeneration mocks base method.
+func (m *MockIncidentRepository) GetLocationTileGeneration(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLocationTileGeneration", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLocationTileGeneration indicates an expected call of GetLocationTileGeneration.
+func (mr *MockIncidentRepositoryMockRecorder) GetLocationTileGeneration(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLocationTileGeneration", reflect.TypeOf((*MockIncidentRepository)(nil).GetLocationTileGeneration), ctx)
+}
+
+// GetPendingIncidentViews mocks base method.
+func (m *MockIncidentRepository) GetPendingIncidentViews(ctx context.Context, id uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingIncidentViews", ctx, id)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingIncidentViews indicates an expected call of GetPendingIncidentViews.
+func (mr *MockIncidentRepositoryMockRecorder) GetPendingIncidentViews(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingIncidentViews", reflect.TypeOf((*MockIncidentRepository)(nil).GetPendingIncidentViews), ctx, id)
+}
+
+// GetStatsFromCache mocks base method.
+func (m *MockIncidentRepository) GetStatsFromCache(ctx context.Context, minutes int) (*models.StatsSnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatsFromCache", ctx, minutes)
+	ret0, _ := ret[0].(*models.StatsSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStatsFromCache indicates an expected call of GetStatsFromCache.
+func (mr *MockIncidentRepositoryMockRecorder) GetStatsFromCache(ctx, minutes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatsFromCache", reflect.TypeOf((*MockIncidentRepository)(nil).GetStatsFromCache), ctx, minutes)
+}
+
+// IncidentTimeline mocks base method.
+func (m *MockIncidentRepository) IncidentTimeline(ctx context.Context, q models.TimelineQuery) ([]*models.TimelineBucket, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncidentTimeline", ctx, q)
+	ret0, _ := ret[0].([]*models.TimelineBucket)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncidentTimeline indicates an expected call of IncidentTimeline.
+func (mr *MockIncidentRepositoryMockRecorder) IncidentTimeline(ctx, q any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncidentTimeline", reflect.TypeOf((*MockIncidentRepository)(nil).IncidentTimeline), ctx, q)
+}
+
+// IncrementAPIKeyUsage mocks base method.
+func (m *MockIncidentRepository) IncrementAPIKeyUsage(ctx context.Context, label string, bucket time.Time, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementAPIKeyUsage", ctx, label, bucket, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IncrementAPIKeyUsage indicates an expected call of IncrementAPIKeyUsage.
+func (mr *MockIncidentRepositoryMockRecorder) IncrementAPIKeyUsage(ctx, label, bucket, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementAPIKeyUsage", reflect.TypeOf((*MockIncidentRepository)(nil).IncrementAPIKeyUsage), ctx, label, bucket, ttl)
+}
+
+// IncrementIncidentViews mocks base method.
+func (m *MockIncidentRepository) IncrementIncidentViews(ctx context.Context, counts map[uuid.UUID]int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementIncidentViews", ctx, counts)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IncrementIncidentViews indicates an expected call of IncrementIncidentViews.
+func (mr *MockIncidentRepositoryMockRecorder) IncrementIncidentViews(ctx, counts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementIncidentViews", reflect.TypeOf((*MockIncidentRepository)(nil).IncrementIncidentViews), ctx, counts)
+}
+
+// InvalidateIncidentCache mocks base method.
+func (m *MockIncidentRepository) InvalidateIncidentCache(ctx context.Context, ids ...uuid.UUID) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range ids {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "InvalidateIncidentCache", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InvalidateIncidentCache indicates an expected call of InvalidateIncidentCache.
+func (mr *MockIncidentRepositoryMockRecorder) InvalidateIncidentCache(ctx any, ids ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, ids...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvalidateIncidentCache", reflect.TypeOf((*MockIncidentRepository)(nil).InvalidateIncidentCache), varargs...)
+}
+
+// InvalidateLocationTiles mocks base method.
+func (m *MockIncidentRepository) InvalidateLocationTiles(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InvalidateLocationTiles", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InvalidateLocationTiles indicates an expected call of InvalidateLocationTiles.
+func (mr *MockIncidentRepositoryMockRecorder) InvalidateLocationTiles(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvalidateLocationTiles", reflect.TypeOf((*MockIncidentRepository)(nil).InvalidateLocationTiles), ctx)
+}
+
+// ListAPIKeys mocks base method.
+func (m *MockIncidentRepository) ListAPIKeys(ctx context.Context) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAPIKeys", ctx)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAPIKeys indicates an expected call of ListAPIKeys.
+func (mr *MockIncidentRepositoryMockRecorder) ListAPIKeys(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAPIKeys", reflect.TypeOf((*MockIncidentRepository)(nil).ListAPIKeys), ctx)
+}
+
+// ListActiveIncidents mocks base method.
+func (m *MockIncidentRepository) ListActiveIncidents(ctx context.Context) ([]*models.Incident, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListActiveIncidents", ctx)
+	ret0, _ := ret[0].([]*models.Incident)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListActiveIncidents indicates an expected call of ListActiveIncidents.
+func (mr *MockIncidentRepositoryMockRecorder) ListActiveIncidents(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListActiveIncidents", reflect.TypeOf((*MockIncidentRepository)(nil).ListActiveIncidents), ctx)
+}
+
+// ListEscalationCandidates mocks base method.
+func (m *MockIncidentRepository) ListEscalationCandidates(ctx context.Context, minUsers int, window time.Duration) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEscalationCandidates", ctx, minUsers, window)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEscalationCandidates indicates an expected call of ListEscalationCandidates.
+func (mr *MockIncidentRepositoryMockRecorder) ListEscalationCandidates(ctx, minUsers, window any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEscalationCandidates", reflect.TypeOf((*MockIncidentRepository)(nil).ListEscalationCandidates), ctx, minUsers, window)
+}
+
+// ListImportFeeds mocks base method.
+func (m *MockIncidentRepository) ListImportFeeds(ctx context.Context) ([]*models.ImportFeed, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListImportFeeds", ctx)
+	ret0, _ := ret[0].([]*models.ImportFeed)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListImportFeeds indicates an expected call of ListImportFeeds.
+func (mr *MockIncidentRepositoryMockRecorder) ListImportFeeds(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListImportFeeds", reflect.TypeOf((*MockIncidentRepository)(nil).ListImportFeeds), ctx)
+}
+
+// ListIncidentAttachments mocks base method.
+func (m *MockIncidentRepository) ListIncidentAttachments(ctx context.Context, incidentID uuid.UUID) ([]*models.IncidentAttachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListIncidentAttachments", ctx, incidentID)
+	ret0, _ := ret[0].([]*models.IncidentAttachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListIncidentAttachments indicates an expected call of ListIncidentAttachments.
+func (mr *MockIncidentRepositoryMockRecorder) ListIncidentAttachments(ctx, incidentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIncidentAttachments", reflect.TypeOf((*MockIncidentRepository)(nil).ListIncidentAttachments), ctx, incidentID)
+}
+
+// ListIncidentTemplates mocks base method.
+func (m *MockIncidentRepository) ListIncidentTemplates(ctx context.Context) ([]*models.IncidentTemplate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListIncidentTemplates", ctx)
+	ret0, _ := ret[0].([]*models.IncidentTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListIncidentTemplates indicates an expected call of ListIncidentTemplates.
+func (mr *MockIncidentRepositoryMockRecorder) ListIncidentTemplates(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIncidentTemplates", reflect.TypeOf((*MockIncidentRepository)(nil).ListIncidentTemplates), ctx)
+}
+
+// ListIncidentVersions mocks base method.
+func (m *MockIncidentRepository) ListIncidentVersions(ctx context.Context, incidentID uuid.UUID) ([]*models.IncidentVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListIncidentVersions", ctx, incidentID)
+	ret0, _ := ret[0].([]*models.IncidentVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListIncidentVersions indicates an expected call of ListIncidentVersions.
+func (mr *MockIncidentRepositoryMockRecorder) ListIncidentVersions(ctx, incidentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIncidentVersions", reflect.TypeOf((*MockIncidentRepository)(nil).ListIncidentVersions), ctx, incidentID)
+}
+
+// ListIncidentWatchers mocks base method.
+func (m *MockIncidentRepository) ListIncidentWatchers(ctx context.Context, incidentID uuid.UUID) ([]*models.IncidentWatcher, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListIncidentWatchers", ctx, incidentID)
+	ret0, _ := ret[0].([]*models.IncidentWatcher)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListIncidentWatchers indicates an expected call of ListIncidentWatchers.
+func (mr *MockIncidentRepositoryMockRecorder) ListIncidentWatchers(ctx, incidentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIncidentWatchers", reflect.TypeOf((*MockIncidentRepository)(nil).ListIncidentWatchers), ctx, incidentID)
+}
+
+// ListIncidents mocks base method.
+func (m *MockIncidentRepository) ListIncidents(ctx context.Context, page, pageSize int, filter models.IncidentFilter) ([]*models.Incident, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListIncidents", ctx, page, pageSize, filter)
+	ret0, _ := ret[0].([]*models.Incident)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListIncidents indicates an expected call of ListIncidents.
+func (mr *MockIncidentRepositoryMockRecorder) ListIncidents(ctx, page, pageSize, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIncidents", reflect.TypeOf((*MockIncidentRepository)(nil).ListIncidents), ctx, page, pageSize, filter)
+}
+
+// ListLocationChecksByUser mocks base method.
+func (m *MockIncidentRepository) ListLocationChecksByUser(ctx context.Context, userID string, page, pageSize int) ([]*models.LocationCheck, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLocationChecksByUser", ctx, userID, page, pageSize)
+	ret0, _ := ret[0].([]*models.LocationCheck)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLocationChecksByUser indicates an expected call of ListLocationChecksByUser.
+func (mr *MockIncidentRepositoryMockRecorder) ListLocationChecksByUser(ctx, userID, page, pageSize any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLocationChecksByUser", reflect.TypeOf((*MockIncidentRepository)(nil).ListLocationChecksByUser), ctx, userID, page, pageSize)
+}
+
+// ListPublicIncidents mocks base method.
+func (m *MockIncidentRepository) ListPublicIncidents(ctx context.Context, excludedCategories []string, page, pageSize int) ([]*models.Incident, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPublicIncidents", ctx, excludedCategories, page, pageSize)
+	ret0, _ := ret[0].([]*models.Incident)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPublicIncidents indicates an expected call of ListPublicIncidents.
+func (mr *MockIncidentRepositoryMockRecorder) ListPublicIncidents(ctx, excludedCategories, page, pageSize any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPublicIncidents", reflect.TypeOf((*MockIncidentRepository)(nil).ListPublicIncidents), ctx, excludedCategories, page, pageSize)
+}
+
+// ListUserLocations mocks base method.
+func (m *MockIncidentRepository) ListUserLocations(ctx context.Context, userID string) ([]*models.UserLocation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserLocations", ctx, userID)
+	ret0, _ := ret[0].([]*models.UserLocation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserLocations indicates an expected call of ListUserLocations.
+func (mr *MockIncidentRepositoryMockRecorder) ListUserLocations(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserLocations", reflect.TypeOf((*MockIncidentRepository)(nil).ListUserLocations), ctx, userID)
+}
+
+// ListWatchersByIncidents mocks base method.
+func (m *MockIncidentRepository) ListWatchersByIncidents(ctx context.Context, incidentIDs []uuid.UUID) ([]*models.IncidentWatcher, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWatchersByIncidents", ctx, incidentIDs)
+	ret0, _ := ret[0].([]*models.IncidentWatcher)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWatchersByIncidents indicates an expected call of ListWatchersByIncidents.
+func (mr *MockIncidentRepositoryMockRecorder) ListWatchersByIncidents(ctx, incidentIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWatchersByIncidents", reflect.TypeOf((*MockIncidentRepository)(nil).ListWatchersByIncidents), ctx, incidentIDs)
+}
+
+// MaxUpdatedAt mocks base method.
+func (m *MockIncidentRepository) MaxUpdatedAt(ctx context.Context) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MaxUpdatedAt", ctx)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MaxUpdatedAt indicates an expected call of MaxUpdatedAt.
+func (mr *MockIncidentRepositoryMockRecorder) MaxUpdatedAt(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MaxUpdatedAt", reflect.TypeOf((*MockIncidentRepository)(nil).MaxUpdatedAt), ctx)
+}
+
+// MergeIncidents mocks base method.
+func (m *MockIncidentRepository) MergeIncidents(ctx context.Context, merge models.IncidentMerge) (*models.IncidentMergeResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeIncidents", ctx, merge)
+	ret0, _ := ret[0].(*models.IncidentMergeResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MergeIncidents indicates an expected call of MergeIncidents.
+func (mr *MockIncidentRepositoryMockRecorder) MergeIncidents(ctx, merge any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeIncidents", reflect.TypeOf((*MockIncidentRepository)(nil).MergeIncidents), ctx, merge)
+}
+
+// PersistIncidentViews mocks base method.
+func (m *MockIncidentRepository) PersistIncidentViews(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PersistIncidentViews", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PersistIncidentViews indicates an expected call of PersistIncidentViews.
+func (mr *MockIncidentRepositoryMockRecorder) PersistIncidentViews(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PersistIncidentViews", reflect.TypeOf((*MockIncidentRepository)(nil).PersistIncidentViews), ctx)
+}
+
+// PurgeInactiveIncidents mocks base method.
+func (m *MockIncidentRepository) PurgeInactiveIncidents(ctx context.Context, before time.Time, limit int) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeInactiveIncidents", ctx, before, limit)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeInactiveIncidents indicates an expected call of PurgeInactiveIncidents.
+func (mr *MockIncidentRepositoryMockRecorder) PurgeInactiveIncidents(ctx, before, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeInactiveIncidents", reflect.TypeOf((*MockIncidentRepository)(nil).PurgeInactiveIncidents), ctx, before, limit)
+}
+
+// PurgeSentOutbox mocks base method.
+func (m *MockIncidentRepository) PurgeSentOutbox(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeSentOutbox", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeSentOutbox indicates an expected call of PurgeSentOutbox.
+func (mr *MockIncidentRepositoryMockRecorder) PurgeSentOutbox(ctx, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeSentOutbox", reflect.TypeOf((*MockIncidentRepository)(nil).PurgeSentOutbox), ctx, before)
+}
+
+// RelayOutbox mocks base method.
+func (m *MockIncidentRepository) RelayOutbox(ctx context.Context, limit int, send func(*models.OutboxMessage) error) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RelayOutbox", ctx, limit, send)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RelayOutbox indicates an expected call of RelayOutbox.
+func (mr *MockIncidentRepositoryMockRecorder) RelayOutbox(ctx, limit, send any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RelayOutbox", reflect.TypeOf((*MockIncidentRepository)(nil).RelayOutbox), ctx, limit, send)
+}
+
+// RemoveAPIKey mocks base method.
+func (m *MockIncidentRepository) RemoveAPIKey(ctx context.Context, keyHash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveAPIKey", ctx, keyHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveAPIKey indicates an expected call of RemoveAPIKey.
+func (mr *MockIncidentRepositoryMockRecorder) RemoveAPIKey(ctx, keyHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveAPIKey", reflect.TypeOf((*MockIncidentRepository)(nil).RemoveAPIKey), ctx, keyHash)
+}
+
+// ReservePublicFeedRequest mocks base method.
+func (m *MockIncidentRepository) ReservePublicFeedRequest(ctx context.Context, clientIP string, limit int, window time.Duration) (time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReservePublicFeedRequest", ctx, clientIP, limit, window)
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReservePublicFeedRequest indicates an expected call of ReservePublicFeedRequest.
+func (mr *MockIncidentRepositoryMockRecorder) ReservePublicFeedRequest(ctx, clientIP, limit, window any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReservePublicFeedRequest", reflect.TypeOf((*MockIncidentRepository)(nil).ReservePublicFeedRequest), ctx, clientIP, limit, window)
+}
+
+// ReserveUserCheck mocks base method.
+func (m *MockIncidentRepository) ReserveUserCheck(ctx context.Context, userID string, limit int, window time.Duration) (time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReserveUserCheck", ctx, userID, limit, window)
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReserveUserCheck indicates an expected call of ReserveUserCheck.
+func (mr *MockIncidentRepositoryMockRecorder) ReserveUserCheck(ctx, userID, limit, window any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveUserCheck", reflect.TypeOf((*MockIncidentRepository)(nil).ReserveUserCheck), ctx, userID, limit, window)
+}
+
+// Revert mocks base method.
+func (m *MockIncidentRepository) Revert(ctx context.Context, incident *models.Incident, change *models.StatusChange) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revert", ctx, incident, change)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revert indicates an expected call of Revert.
+func (mr *MockIncidentRepositoryMockRecorder) Revert(ctx, incident, change any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revert", reflect.TypeOf((*MockIncidentRepository)(nil).Revert), ctx, incident, change)
+}
+
+// SaveImportFeedSync mocks base method.
+func (m *MockIncidentRepository) SaveImportFeedSync(ctx context.Context, id uuid.UUID, externalIDs []string, sync models.ImportFeedSync) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveImportFeedSync", ctx, id, externalIDs, sync)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveImportFeedSync indicates an expected call of SaveImportFeedSync.
+func (mr *MockIncidentRepositoryMockRecorder) SaveImportFeedSync(ctx, id, externalIDs, sync any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveImportFeedSync", reflect.TypeOf((*MockIncidentRepository)(nil).SaveImportFeedSync), ctx, id, externalIDs, sync)
+}
+
+// SaveImportJob mocks base method.
+func (m *MockIncidentRepository) SaveImportJob(ctx context.Context, job *models.ImportJob, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveImportJob", ctx, job, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveImportJob indicates an expected call of SaveImportJob.
+func (mr *MockIncidentRepositoryMockRecorder) SaveImportJob(ctx, job, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveImportJob", reflect.TypeOf((*MockIncidentRepository)(nil).SaveImportJob), ctx, job, ttl)
+}
+
+// SaveLocationCheck mocks base method.
+func (m *MockIncidentRepository) SaveLocationCheck(ctx context.Context, check *models.LocationCheck, outbox *models.OutboxMessage) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveLocationCheck", ctx, check, outbox)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveLocationCheck indicates an expected call of SaveLocationCheck.
+func (mr *MockIncidentRepositoryMockRecorder) SaveLocationCheck(ctx, check, outbox any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveLocationCheck", reflect.TypeOf((*MockIncidentRepository)(nil).SaveLocationCheck), ctx, check, outbox)
+}
+
+// SetIncidentCache mocks base method.
+func (m *MockIncidentRepository) SetIncidentCache(ctx context.Context, incident *models.Incident) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetIncidentCache", ctx, incident)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetIncidentCache indicates an expected call of SetIncidentCache.
+func (mr *MockIncidentRepositoryMockRecorder) SetIncidentCache(ctx, incident any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetIncidentCache", reflect.TypeOf((*MockIncidentRepository)(nil).SetIncidentCache), ctx, incident)
+}
+
+// SetLocationTileCache mocks base method.
+func (m *MockIncidentRepository) SetLocationTileCache(ctx context.Context, generation int64, geohash string, incidents []*models.Incident, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLocationTileCache", ctx, generation, geohash, incidents, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetLocationTileCache indicates an expected call of SetLocationTileCache.
+func (mr *MockIncidentRepositoryMockRecorder) SetLocationTileCache(ctx, generation, geohash, incidents, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLocationTileCache", reflect.TypeOf((*MockIncidentRepository)(nil).SetLocationTileCache), ctx, generation, geohash, incidents, ttl)
+}
+
+// SetStatsCache mocks base method.
+func (m *MockIncidentRepository) SetStatsCache(ctx context.Context, minutes int, snapshot *models.StatsSnapshot, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetStatsCache", ctx, minutes, snapshot, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetStatsCache indicates an expected call of SetStatsCache.
+func (mr *MockIncidentRepositoryMockRecorder) SetStatsCache(ctx, minutes, snapshot, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetStatsCache", reflect.TypeOf((*MockIncidentRepository)(nil).SetStatsCache), ctx, minutes, snapshot, ttl)
+}
+
+// Update mocks base method.
+func (m *MockIncidentRepository) Update(ctx context.Context, incident *models.Incident) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, incident)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockIncidentRepositoryMockRecorder) Update(ctx, incident any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockIncidentRepository)(nil).Update), ctx, incident)
+}
+
+// UpdateIncidentTemplate mocks base method.
+func (m *MockIncidentRepository) UpdateIncidentTemplate(ctx context.Context, template *models.IncidentTemplate) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateIncidentTemplate", ctx, template)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateIncidentTemplate indicates an expected call of UpdateIncidentTemplate.
+func (mr *MockIncidentRepositoryMockRecorder) UpdateIncidentTemplate(ctx, template any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateIncidentTemplate", reflect.TypeOf((*MockIncidentRepository)(nil).UpdateIncidentTemplate), ctx, template)
+}
+
+// ValidateGeofence mocks base method.
+func (m *MockIncidentRepository) ValidateGeofence(ctx context.Context, lat, lon float64, radiusMeters int) (*models.GeofenceValidation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateGeofence", ctx, lat, lon, radiusMeters)
+	ret0, _ := ret[0].(*models.GeofenceValidation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ValidateGeofence indicates an expected call of ValidateGeofence.
+func (mr *MockIncidentRepositoryMockRecorder) ValidateGeofence(ctx, lat, lon, radiusMeters any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateGeofence", reflect.TypeOf((*MockIncidentRepository)(nil).ValidateGeofence), ctx, lat, lon, radiusMeters)
+}
+
+// MockIncidentService is a mock of IncidentService interface.
+type MockIncidentService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIncidentServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockIncidentServiceMockRecorder is the mock recorder for MockIncidentService.
+type MockIncidentServiceMockRecorder struct {
+	mock *MockIncidentService
+}
+
+// NewMockIncidentService creates a new mock instance.
+func NewMockIncidentService(ctrl *gomock.Controller) *MockIncidentService {
+	mock := &MockIncidentService{ctrl: ctrl}
+	mock.recorder = &MockIncidentServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIncidentService) EXPECT() *MockIncidentServiceMockRecorder {
+	return m.recorder
+}
+
+// AddAPIKey mocks base method.
+func (m *MockIncidentService) AddAPIKey(ctx context.Context, apiKey string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddAPIKey", ctx, apiKey)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddAPIKey indicates an expected call of AddAPIKey.
+func (mr *MockIncidentServiceMockRecorder) AddAPIKey(ctx, apiKey any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAPIKey", reflect.TypeOf((*MockIncidentService)(nil).AddAPIKey), ctx, apiKey)
+}
+
+// AddUserLocation mocks base method.
+func (m *MockIncidentService) AddUserLocation(ctx context.Context, location *models.UserLocation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddUserLocation", ctx, location)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddUserLocation indicates an expected call of AddUserLocation.
+func (mr *MockIncidentServiceMockRecorder) AddUserLocation(ctx, location any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddUserLocation", reflect.TypeOf((*MockIncidentService)(nil).AddUserLocation), ctx, location)
+}
+
+// ApproveIncident mocks base method.
+func (m *MockIncidentService) ApproveIncident(ctx context.Context, id uuid.UUID, reviewer string) (*models.Incident, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApproveIncident", ctx, id, reviewer)
+	ret0, _ := ret[0].(*models.Incident)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApproveIncident indicates an expected call of ApproveIncident.
+func (mr *MockIncidentServiceMockRecorder) ApproveIncident(ctx, id, reviewer any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApproveIncident", reflect.TypeOf((*MockIncidentService)(nil).ApproveIncident), ctx, id, reviewer)
+}
+
+// BulkDeactivateIncidents mocks base method.
+func (m *MockIncidentService) BulkDeactivateIncidents(ctx context.Context, target models.BulkDeactivation) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BulkDeactivateIncidents", ctx, target)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BulkDeactivateIncidents indicates an expected call of BulkDeactivateIncidents.
+func (mr *MockIncidentServiceMockRecorder) BulkDeactivateIncidents(ctx, target any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkDeactivateIncidents", reflect.TypeOf((*MockIncidentService)(nil).BulkDeactivateIncidents), ctx, target)
+}
+
+// CheckLocation mocks base method.
+func (m *MockIncidentService) CheckLocation(ctx context.Context, userID string, authenticated bool, lat, lon float64, bufferMeters, minConfidence int) ([]*models.Incident, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckLocation", ctx, userID, authenticated, lat, lon, bufferMeters, minConfidence)
+	ret0, _ := ret[0].([]*models.Incident)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CheckLocation indicates an expected call of CheckLocation.
+func (mr *MockIncidentServiceMockRecorder) CheckLocation(ctx, userID, authenticated, lat, lon, bufferMeters, minConfidence any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckLocation", reflect.TypeOf((*MockIncidentService)(nil).CheckLocation), ctx, userID, authenticated, lat, lon, bufferMeters, minConfidence)
+}
+
+// ClusterIncidents mocks base method.
+func (m *MockIncidentService) ClusterIncidents(ctx context.Context, bbox geo.BBox, zoom int) ([]*models.IncidentCluster, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClusterIncidents", ctx, bbox, zoom)
+	ret0, _ := ret[0].([]*models.IncidentCluster)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClusterIncidents indicates an expected call of ClusterIncidents.
+func (mr *MockIncidentServiceMockRecorder) ClusterIncidents(ctx, bbox, zoom any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClusterIncidents", reflect.TypeOf((*MockIncidentService)(nil).ClusterIncidents), ctx, bbox, zoom)
+}
+
+// CreateAttachmentUploadURL mocks base method.
+func (m *MockIncidentService) CreateAttachmentUploadURL(ctx context.Context, incidentID uuid.UUID, filename, contentType, createdBy string) (*models.PresignedAttachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAttachmentUploadURL", ctx, incidentID, filename, contentType, createdBy)
+	ret0, _ := ret[0].(*models.PresignedAttachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAttachmentUploadURL indicates an expected call of CreateAttachmentUploadURL.
+func (mr *MockIncidentServiceMockRecorder) CreateAttachmentUploadURL(ctx, incidentID, filename, contentType, createdBy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAttachmentUploadURL", reflect.TypeOf((*MockIncidentService)(nil).CreateAttachmentUploadURL), ctx, incidentID, filename, contentType, createdBy)
+}
+
+// CreateIncident mocks base method.
+func (m *MockIncidentService) CreateIncident(ctx context.Context, incident *models.Incident) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateIncident", ctx, incident)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateIncident indicates an expected call of CreateIncident.
+func (mr *MockIncidentServiceMockRecorder) CreateIncident(ctx, incident any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIncident", reflect.TypeOf((*MockIncidentService)(nil).CreateIncident), ctx, incident)
+}
+
+// CreateIncidentFromTemplate mocks base method.
+func (m *MockIncidentService) CreateIncidentFromTemplate(ctx context.Context, templateID uuid.UUID, incident *models.Incident) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateIncidentFromTemplate", ctx, templateID, incident)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateIncidentFromTemplate indicates an expected call of CreateIncidentFromTemplate.
+func (mr *MockIncidentServiceMockRecorder) CreateIncidentFromTemplate(ctx, templateID, incident any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIncidentFromTemplate", reflect.TypeOf((*MockIncidentService)(nil).CreateIncidentFromTemplate), ctx, templateID, incident)
+}
+
+// CreateIncidentTemplate mocks base method.
+func (m *MockIncidentService) CreateIncidentTemplate(ctx context.Context, template *models.IncidentTemplate) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateIncidentTemplate", ctx, template)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateIncidentTemplate indicates an expected call of CreateIncidentTemplate.
+func (mr *MockIncidentServiceMockRecorder) CreateIncidentTemplate(ctx, template any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIncidentTemplate", reflect.TypeOf((*MockIncidentService)(nil).CreateIncidentTemplate), ctx, template)
+}
+
+// DeactivateIncident mocks base method.
+func (m *MockIncidentService) DeactivateIncident(ctx context.Context, id uuid.UUID, resolution models.Resolution) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeactivateIncident", ctx, id, resolution)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeactivateIncident indicates an expected call of DeactivateIncident.
+func (mr *MockIncidentServiceMockRecorder) DeactivateIncident(ctx, id, resolution any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeactivateIncident", reflect.TypeOf((*MockIncidentService)(nil).DeactivateIncident), ctx, id, resolution)
+}
+
+// DeleteImportFeed mocks base method.
+func (m *MockIncidentService) DeleteImportFeed(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteImportFeed", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteImportFeed indicates an expected call of DeleteImportFeed.
+func (mr *MockIncidentServiceMockRecorder) DeleteImportFeed(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteImportFeed", reflect.TypeOf((*MockIncidentService)(nil).DeleteImportFeed), ctx, id)
+}
+
+// DeleteIncidentTemplate mocks base method.
+func (m *MockIncidentService) DeleteIncidentTemplate(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteIncidentTemplate", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteIncidentTemplate indicates an expected call of DeleteIncidentTemplate.
+func (mr *MockIncidentServiceMockRecorder) DeleteIncidentTemplate(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteIncidentTemplate", reflect.TypeOf((*MockIncidentService)(nil).DeleteIncidentTemplate), ctx, id)
+}
+
+// DeleteUserLocation mocks base method.
+func (m *MockIncidentService) DeleteUserLocation(ctx context.Context, userID string, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserLocation", ctx, userID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserLocation indicates an expected call of DeleteUserLocation.
+func (mr *MockIncidentServiceMockRecorder) DeleteUserLocation(ctx, userID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserLocation", reflect.TypeOf((*MockIncidentService)(nil).DeleteUserLocation), ctx, userID, id)
+}
+
+// EscalateIncident mocks base method.
+func (m *MockIncidentService) EscalateIncident(ctx context.Context, id uuid.UUID, escalatedBy string) (*models.Incident, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EscalateIncident", ctx, id, escalatedBy)
+	ret0, _ := ret[0].(*models.Incident)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EscalateIncident indicates an expected call of EscalateIncident.
+func (mr *MockIncidentServiceMockRecorder) EscalateIncident(ctx, id, escalatedBy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EscalateIncident", reflect.TypeOf((*MockIncidentService)(nil).EscalateIncident), ctx, id, escalatedBy)
+}
+
+// FeatureEnabled mocks base method.
+func (m *MockIncidentService) FeatureEnabled(ctx context.Context, name string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FeatureEnabled", ctx, name)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// FeatureEnabled indicates an expected call of FeatureEnabled.
+func (mr *MockIncidentServiceMockRecorder) FeatureEnabled(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FeatureEnabled", reflect.TypeOf((*MockIncidentService)(nil).FeatureEnabled), ctx, name)
+}
+
+// FindHotspots mocks base method.
+func (m *MockIncidentService) FindHotspots(ctx context.Context, bbox geo.BBox, cellMeters float64, minCount int) ([]*models.IncidentHotspot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindHotspots", ctx, bbox, cellMeters, minCount)
+	ret0, _ := ret[0].([]*models.IncidentHotspot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindHotspots indicates an expected call of FindHotspots.
+func (mr *MockIncidentServiceMockRecorder) FindHotspots(ctx, bbox, cellMeters, minCount any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindHotspots", reflect.TypeOf((*MockIncidentService)(nil).FindHotspots), ctx, bbox, cellMeters, minCount)
+}
+
+// FlushIncidentViews mocks base method.
+func (m *MockIncidentService) FlushIncidentViews(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FlushIncidentViews", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FlushIncidentViews indicates an expected call of FlushIncidentViews.
+func (mr *MockIncidentServiceMockRecorder) FlushIncidentViews(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FlushIncidentViews", reflect.TypeOf((*MockIncidentService)(nil).FlushIncidentViews), ctx)
+}
+
+// GetAPIKeyUsage mocks base method.
+func (m *MockIncidentService) GetAPIKeyUsage(ctx context.Context) ([]*models.APIKeyUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPIKeyUsage", ctx)
+	ret0, _ := ret[0].([]*models.APIKeyUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAPIKeyUsage indicates an expected call of GetAPIKeyUsage.
+func (mr *MockIncidentServiceMockRecorder) GetAPIKeyUsage(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIKeyUsage", reflect.TypeOf((*MockIncidentService)(nil).GetAPIKeyUsage), ctx)
+}
+
+// GetImportJob mocks base method.
+func (m *MockIncidentService) GetImportJob(ctx context.Context, id uuid.UUID) (*models.ImportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetImportJob", ctx, id)
+	ret0, _ := ret[0].(*models.ImportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetImportJob indicates an expected call of GetImportJob.
+func (mr *MockIncidentServiceMockRecorder) GetImportJob(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImportJob", reflect.TypeOf((*MockIncidentService)(nil).GetImportJob), ctx, id)
+}
+
+// GetIncident mocks base method.
+func (m *MockIncidentService) GetIncident(ctx context.Context, id uuid.UUID) (*models.Incident, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIncident", ctx, id)
+	ret0, _ := ret[0].(*models.Incident)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIncident indicates an expected call of GetIncident.
+func (mr *MockIncidentServiceMockRecorder) GetIncident(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIncident", reflect.TypeOf((*MockIncidentService)(nil).GetIncident), ctx, id)
+}
+
+// GetIncidentByExternalID mocks base method.
+func (m *MockIncidentService) GetIncidentByExternalID(ctx context.Context, externalID string) (*models.Incident, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIncidentByExternalID", ctx, externalID)
+	ret0, _ := ret[0].(*models.Incident)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIncidentByExternalID indicates an expected call of GetIncidentByExternalID.
+func (mr *MockIncidentServiceMockRecorder) GetIncidentByExternalID(ctx, externalID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIncidentByExternalID", reflect.TypeOf((*MockIncidentService)(nil).GetIncidentByExternalID), ctx, externalID)
+}
+
+// GetIncidentImpact mocks base method.
+func (m *MockIncidentService) GetIncidentImpact(ctx context.Context, id uuid.UUID) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIncidentImpact", ctx, id)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIncidentImpact indicates an expected call of GetIncidentImpact.
+func (mr *MockIncidentServiceMockRecorder) GetIncidentImpact(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIncidentImpact", reflect.TypeOf((*MockIncidentService)(nil).GetIncidentImpact), ctx, id)
+}
+
+// GetIncidentTemplate mocks base method.
+func (m *MockIncidentService) GetIncidentTemplate(ctx context.Context, id uuid.UUID) (*models.IncidentTemplate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIncidentTemplate", ctx, id)
+	ret0, _ := ret[0].(*models.IncidentTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIncidentTemplate indicates an expected call of GetIncidentTemplate.
+func (mr *MockIncidentServiceMockRecorder) GetIncidentTemplate(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIncidentTemplate", reflect.TypeOf((*MockIncidentService)(nil).GetIncidentTemplate), ctx, id)
+}
+
+// GetIncidentTimeline mocks base method.
+func (m *MockIncidentService) GetIncidentTimeline(ctx context.Context, q models.TimelineQuery) ([]*models.TimelineBucket, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIncidentTimeline", ctx, q)
+	ret0, _ := ret[0].([]*models.TimelineBucket)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIncidentTimeline indicates an expected call of GetIncidentTimeline.
+func (mr *MockIncidentServiceMockRecorder) GetIncidentTimeline(ctx, q any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIncidentTimeline", reflect.TypeOf((*MockIncidentService)(nil).GetIncidentTimeline), ctx, q)
+}
+
+// GetIncidentViews mocks base method.
+func (m *MockIncidentService) GetIncidentViews(ctx context.Context, id uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIncidentViews", ctx, id)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIncidentViews indicates an expected call of GetIncidentViews.
+func (mr *MockIncidentServiceMockRecorder) GetIncidentViews(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIncidentViews", reflect.TypeOf((*MockIncidentService)(nil).GetIncidentViews), ctx, id)
+}
+
+// GetOverlappingIncidents mocks base method.
+func (m *MockIncidentService) GetOverlappingIncidents(ctx context.Context, id uuid.UUID) ([]*models.IncidentOverlap, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOverlappingIncidents", ctx, id)
+	ret0, _ := ret[0].([]*models.IncidentOverlap)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOverlappingIncidents indicates an expected call of GetOverlappingIncidents.
+func (mr *MockIncidentServiceMockRecorder) GetOverlappingIncidents(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOverlappingIncidents", reflect.TypeOf((*MockIncidentService)(nil).GetOverlappingIncidents), ctx, id)
+}
+
+// GetStats mocks base method.
+func (m *MockIncidentService) GetStats(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStats", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStats indicates an expected call of GetStats.
+func (mr *MockIncidentServiceMockRecorder) GetStats(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStats", reflect.TypeOf((*MockIncidentService)(nil).GetStats), ctx)
+}
+
+// GetUserAlerts mocks base method.
+func (m *MockIncidentService) GetUserAlerts(ctx context.Context, userID string) ([]*models.LocationAlert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserAlerts", ctx, userID)
+	ret0, _ := ret[0].([]*models.LocationAlert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserAlerts indicates an expected call of GetUserAlerts.
+func (mr *MockIncidentServiceMockRecorder) GetUserAlerts(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserAlerts", reflect.TypeOf((*MockIncidentService)(nil).GetUserAlerts), ctx, userID)
+}
+
+// GetUserLocationHistory mocks base method.
+func (m *MockIncidentService) GetUserLocationHistory(ctx context.Context, userID string, page, pageSize int) ([]*models.LocationCheck, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserLocationHistory", ctx, userID, page, pageSize)
+	ret0, _ := ret[0].([]*models.LocationCheck)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserLocationHistory indicates an expected call of GetUserLocationHistory.
+func (mr *MockIncidentServiceMockRecorder) GetUserLocationHistory(ctx, userID, page, pageSize any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserLocationHistory", reflect.TypeOf((*MockIncidentService)(nil).GetUserLocationHistory), ctx, userID, page, pageSize)
+}
+
+// GetUserStatus mocks base method.
+func (m *MockIncidentService) GetUserStatus(ctx context.Context, userID string) (*models.UserStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserStatus", ctx, userID)
+	ret0, _ := ret[0].(*models.UserStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserStatus indicates an expected call of GetUserStatus.
+func (mr *MockIncidentServiceMockRecorder) GetUserStatus(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserStatus", reflect.TypeOf((*MockIncidentService)(nil).GetUserStatus), ctx, userID)
+}
+
+// GetWebhookCircuits mocks base method.
+func (m *MockIncidentService) GetWebhookCircuits(ctx context.Context) ([]webhook.CircuitStatus, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookCircuits", ctx)
+	ret0, _ := ret[0].([]webhook.CircuitStatus)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetWebhookCircuits indicates an expected call of GetWebhookCircuits.
+func (mr *MockIncidentServiceMockRecorder) GetWebhookCircuits(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookCircuits", reflect.TypeOf((*MockIncidentService)(nil).GetWebhookCircuits), ctx)
+}
+
+// GetWebhookQueueDepth mocks base method.
+func (m *MockIncidentService) GetWebhookQueueDepth(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookQueueDepth", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookQueueDepth indicates an expected call of GetWebhookQueueDepth.
+func (mr *MockIncidentServiceMockRecorder) GetWebhookQueueDepth(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookQueueDepth", reflect.TypeOf((*MockIncidentService)(nil).GetWebhookQueueDepth), ctx)
+}
+
+// IncidentsLastModified mocks base method.
+func (m *MockIncidentService) IncidentsLastModified(ctx context.Context) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncidentsLastModified", ctx)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncidentsLastModified indicates an expected call of IncidentsLastModified.
+func (mr *MockIncidentServiceMockRecorder) IncidentsLastModified(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncidentsLastModified", reflect.TypeOf((*MockIncidentService)(nil).IncidentsLastModified), ctx)
+}
+
+// IncidentsSnapshotTime mocks base method.
+func (m *MockIncidentService) IncidentsSnapshotTime(ctx context.Context) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncidentsSnapshotTime", ctx)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncidentsSnapshotTime indicates an expected call of IncidentsSnapshotTime.
+func (mr *MockIncidentServiceMockRecorder) IncidentsSnapshotTime(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncidentsSnapshotTime", reflect.TypeOf((*MockIncidentService)(nil).IncidentsSnapshotTime), ctx)
+}
+
+// InvalidateCaches mocks base method.
+func (m *MockIncidentService) InvalidateCaches(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InvalidateCaches", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InvalidateCaches indicates an expected call of InvalidateCaches.
+func (mr *MockIncidentServiceMockRecorder) InvalidateCaches(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvalidateCaches", reflect.TypeOf((*MockIncidentService)(nil).InvalidateCaches), ctx)
+}
+
+// IsRuntimeAPIKey mocks base method.
+func (m *MockIncidentService) IsRuntimeAPIKey(ctx context.Context, apiKey string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsRuntimeAPIKey", ctx, apiKey)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsRuntimeAPIKey indicates an expected call of IsRuntimeAPIKey.
+func (mr *MockIncidentServiceMockRecorder) IsRuntimeAPIKey(ctx, apiKey any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsRuntimeAPIKey", reflect.TypeOf((*MockIncidentService)(nil).IsRuntimeAPIKey), ctx, apiKey)
+}
+
+// ListImportFeeds mocks base method.
+func (m *MockIncidentService) ListImportFeeds(ctx context.Context) ([]*models.ImportFeed, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListImportFeeds", ctx)
+	ret0, _ := ret[0].([]*models.ImportFeed)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListImportFeeds indicates an expected call of ListImportFeeds.
+func (mr *MockIncidentServiceMockRecorder) ListImportFeeds(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListImportFeeds", reflect.TypeOf((*MockIncidentService)(nil).ListImportFeeds), ctx)
+}
+
+// ListIncidentAttachments mocks base method.
+func (m *MockIncidentService) ListIncidentAttachments(ctx context.Context, incidentID uuid.UUID) ([]*models.PresignedAttachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListIncidentAttachments", ctx, incidentID)
+	ret0, _ := ret[0].([]*models.PresignedAttachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListIncidentAttachments indicates an expected call of ListIncidentAttachments.
+func (mr *MockIncidentServiceMockRecorder) ListIncidentAttachments(ctx, incidentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIncidentAttachments", reflect.TypeOf((*MockIncidentService)(nil).ListIncidentAttachments), ctx, incidentID)
+}
+
+// ListIncidentChanges mocks base method.
+func (m *MockIncidentService) ListIncidentChanges(ctx context.Context, after models.ChangeCursor, limit int) ([]*models.Incident, models.ChangeCursor, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListIncidentChanges", ctx, after, limit)
+	ret0, _ := ret[0].([]*models.Incident)
+	ret1, _ := ret[1].(models.ChangeCursor)
+	ret2, _ := ret[2].(bool)
+	ret3, _ := ret[3].(error)
+	return ret0, ret1, ret2, ret3
+}
+
+// ListIncidentChanges indicates an expected call of ListIncidentChanges.
+func (mr *MockIncidentServiceMockRecorder) ListIncidentChanges(ctx, after, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIncidentChanges", reflect.TypeOf((*MockIncidentService)(nil).ListIncidentChanges), ctx, after, limit)
+}
+
+// ListIncidentTemplates mocks base method.
+func (m *MockIncidentService) ListIncidentTemplates(ctx context.Context) ([]*models.IncidentTemplate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListIncidentTemplates", ctx)
+	ret0, _ := ret[0].([]*models.IncidentTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListIncidentTemplates indicates an expected call of ListIncidentTemplates.
+func (mr *MockIncidentServiceMockRecorder) ListIncidentTemplates(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIncidentTemplates", reflect.TypeOf((*MockIncidentService)(nil).ListIncidentTemplates), ctx)
+}
+
+// ListIncidentVersions mocks base method.
+func (m *MockIncidentService) ListIncidentVersions(ctx context.Context, id uuid.UUID) ([]*models.IncidentVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListIncidentVersions", ctx, id)
+	ret0, _ := ret[0].([]*models.IncidentVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListIncidentVersions indicates an expected call of ListIncidentVersions.
+func (mr *MockIncidentServiceMockRecorder) ListIncidentVersions(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIncidentVersions", reflect.TypeOf((*MockIncidentService)(nil).ListIncidentVersions), ctx, id)
+}
+
+// ListIncidentWatchers mocks base method.
+func (m *MockIncidentService) ListIncidentWatchers(ctx context.Context, incidentID uuid.UUID) ([]*models.IncidentWatcher, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListIncidentWatchers", ctx, incidentID)
+	ret0, _ := ret[0].([]*models.IncidentWatcher)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListIncidentWatchers indicates an expected call of ListIncidentWatchers.
+func (mr *MockIncidentServiceMockRecorder) ListIncidentWatchers(ctx, incidentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIncidentWatchers", reflect.TypeOf((*MockIncidentService)(nil).ListIncidentWatchers), ctx, incidentID)
+}
+
+// ListIncidents mocks base method.
+func (m *MockIncidentService) ListIncidents(ctx context.Context, page, pageSize int, filter models.IncidentFilter) ([]*models.Incident, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListIncidents", ctx, page, pageSize, filter)
+	ret0, _ := ret[0].([]*models.Incident)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListIncidents indicates an expected call of ListIncidents.
+func (mr *MockIncidentServiceMockRecorder) ListIncidents(ctx, page, pageSize, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIncidents", reflect.TypeOf((*MockIncidentService)(nil).ListIncidents), ctx, page, pageSize, filter)
+}
+
+// ListPublicIncidents mocks base method.
+func (m *MockIncidentService) ListPublicIncidents(ctx context.Context, clientIP string, page, pageSize int) ([]*models.Incident, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPublicIncidents", ctx, clientIP, page, pageSize)
+	ret0, _ := ret[0].([]*models.Incident)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPublicIncidents indicates an expected call of ListPublicIncidents.
+func (mr *MockIncidentServiceMockRecorder) ListPublicIncidents(ctx, clientIP, page, pageSize any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPublicIncidents", reflect.TypeOf((*MockIncidentService)(nil).ListPublicIncidents), ctx, clientIP, page, pageSize)
+}
+
+// ListUserLocations mocks base method.
+func (m *MockIncidentService) ListUserLocations(ctx context.Context, userID string) ([]*models.UserLocation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserLocations", ctx, userID)
+	ret0, _ := ret[0].([]*models.UserLocation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserLocations indicates an expected call of ListUserLocations.
+func (mr *MockIncidentServiceMockRecorder) ListUserLocations(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserLocations", reflect.TypeOf((*MockIncidentService)(nil).ListUserLocations), ctx, userID)
+}
+
+// MergeIncidents mocks base method.
+func (m *MockIncidentService) MergeIncidents(ctx context.Context, ids []uuid.UUID, mergedBy string) (*models.IncidentMergeResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeIncidents", ctx, ids, mergedBy)
+	ret0, _ := ret[0].(*models.IncidentMergeResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MergeIncidents indicates an expected call of MergeIncidents.
+func (mr *MockIncidentServiceMockRecorder) MergeIncidents(ctx, ids, mergedBy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeIncidents", reflect.TypeOf((*MockIncidentService)(nil).MergeIncidents), ctx, ids, mergedBy)
+}
+
+// PollIncidentChanges mocks base method.
+func (m *MockIncidentService) PollIncidentChanges(ctx context.Context, after models.ChangeCursor, timeout time.Duration) ([]*models.Incident, models.ChangeCursor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PollIncidentChanges", ctx, after, timeout)
+	ret0, _ := ret[0].([]*models.Incident)
+	ret1, _ := ret[1].(models.ChangeCursor)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// PollIncidentChanges indicates an expected call of PollIncidentChanges.
+func (mr *MockIncidentServiceMockRecorder) PollIncidentChanges(ctx, after, timeout any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PollIncidentChanges", reflect.TypeOf((*MockIncidentService)(nil).PollIncidentChanges), ctx, after, timeout)
+}
+
+// PreviewIncidentImpact mocks base method.
+func (m *MockIncidentService) PreviewIncidentImpact(ctx context.Context, lat, lon float64, radiusMeters int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PreviewIncidentImpact", ctx, lat, lon, radiusMeters)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PreviewIncidentImpact indicates an expected call of PreviewIncidentImpact.
+func (mr *MockIncidentServiceMockRecorder) PreviewIncidentImpact(ctx, lat, lon, radiusMeters any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PreviewIncidentImpact", reflect.TypeOf((*MockIncidentService)(nil).PreviewIncidentImpact), ctx, lat, lon, radiusMeters)
+}
+
+// RecordAPIKeyUsage mocks base method.
+func (m *MockIncidentService) RecordAPIKeyUsage(ctx context.Context, label string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordAPIKeyUsage", ctx, label)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordAPIKeyUsage indicates an expected call of RecordAPIKeyUsage.
+func (mr *MockIncidentServiceMockRecorder) RecordAPIKeyUsage(ctx, label any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordAPIKeyUsage", reflect.TypeOf((*MockIncidentService)(nil).RecordAPIKeyUsage), ctx, label)
+}
+
+// RecordIncidentView mocks base method.
+func (m *MockIncidentService) RecordIncidentView(id uuid.UUID) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RecordIncidentView", id)
+}
+
+// RecordIncidentView indicates an expected call of RecordIncidentView.
+func (mr *MockIncidentServiceMockRecorder) RecordIncidentView(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordIncidentView", reflect.TypeOf((*MockIncidentService)(nil).RecordIncidentView), id)
+}
+
+// ReevaluateLocationChecks mocks base method.
+func (m *MockIncidentService) ReevaluateLocationChecks(ctx context.Context, q models.LocationCheckReevaluationQuery) (*models.LocationCheckReevaluation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReevaluateLocationChecks", ctx, q)
+	ret0, _ := ret[0].(*models.LocationCheckReevaluation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReevaluateLocationChecks indicates an expected call of ReevaluateLocationChecks.
+func (mr *MockIncidentServiceMockRecorder) ReevaluateLocationChecks(ctx, q any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReevaluateLocationChecks", reflect.TypeOf((*MockIncidentService)(nil).ReevaluateLocationChecks), ctx, q)
+}
+
+// RegisterImportFeed mocks base method.
+func (m *MockIncidentService) RegisterImportFeed(ctx context.Context, rawURL, createdBy string) (*models.ImportFeed, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterImportFeed", ctx, rawURL, createdBy)
+	ret0, _ := ret[0].(*models.ImportFeed)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegisterImportFeed indicates an expected call of RegisterImportFeed.
+func (mr *MockIncidentServiceMockRecorder) RegisterImportFeed(ctx, rawURL, createdBy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterImportFeed", reflect.TypeOf((*MockIncidentService)(nil).RegisterImportFeed), ctx, rawURL, createdBy)
+}
+
+// RejectIncident mocks base method.
+func (m *MockIncidentService) RejectIncident(ctx context.Context, id uuid.UUID, reviewer string) (*models.Incident, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RejectIncident", ctx, id, reviewer)
+	ret0, _ := ret[0].(*models.Incident)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RejectIncident indicates an expected call of RejectIncident.
+func (mr *MockIncidentServiceMockRecorder) RejectIncident(ctx, id, reviewer any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RejectIncident", reflect.TypeOf((*MockIncidentService)(nil).RejectIncident), ctx, id, reviewer)
+}
+
+// RevertIncident mocks base method.
+func (m *MockIncidentService) RevertIncident(ctx context.Context, id uuid.UUID, version int, revertedBy string) (*models.Incident, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevertIncident", ctx, id, version, revertedBy)
+	ret0, _ := ret[0].(*models.Incident)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevertIncident indicates an expected call of RevertIncident.
+func (mr *MockIncidentServiceMockRecorder) RevertIncident(ctx, id, version, revertedBy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevertIncident", reflect.TypeOf((*MockIncidentService)(nil).RevertIncident), ctx, id, version, revertedBy)
+}
+
+// RevokeAPIKey mocks base method.
+func (m *MockIncidentService) RevokeAPIKey(ctx context.Context, apiKey string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAPIKey", ctx, apiKey)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAPIKey indicates an expected call of RevokeAPIKey.
+func (mr *MockIncidentServiceMockRecorder) RevokeAPIKey(ctx, apiKey any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAPIKey", reflect.TypeOf((*MockIncidentService)(nil).RevokeAPIKey), ctx, apiKey)
+}
+
+// StartIncidentImport mocks base method.
+func (m *MockIncidentService) StartIncidentImport(ctx context.Context, rawURL, startedBy string) (*models.ImportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartIncidentImport", ctx, rawURL, startedBy)
+	ret0, _ := ret[0].(*models.ImportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartIncidentImport indicates an expected call of StartIncidentImport.
+func (mr *MockIncidentServiceMockRecorder) StartIncidentImport(ctx, rawURL, startedBy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartIncidentImport", reflect.TypeOf((*MockIncidentService)(nil).StartIncidentImport), ctx, rawURL, startedBy)
+}
+
+// SyncImportFeed mocks base method.
+func (m *MockIncidentService) SyncImportFeed(ctx context.Context, feed *models.ImportFeed) (*models.ImportFeedSync, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncImportFeed", ctx, feed)
+	ret0, _ := ret[0].(*models.ImportFeedSync)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SyncImportFeed indicates an expected call of SyncImportFeed.
+func (mr *MockIncidentServiceMockRecorder) SyncImportFeed(ctx, feed any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncImportFeed", reflect.TypeOf((*MockIncidentService)(nil).SyncImportFeed), ctx, feed)
+}
+
+// UnwatchIncident mocks base method.
+func (m *MockIncidentService) UnwatchIncident(ctx context.Context, incidentID, watcherID uuid.UUID, requestedBy string, admin bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnwatchIncident", ctx, incidentID, watcherID, requestedBy, admin)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnwatchIncident indicates an expected call of UnwatchIncident.
+func (mr *MockIncidentServiceMockRecorder) UnwatchIncident(ctx, incidentID, watcherID, requestedBy, admin any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnwatchIncident", reflect.TypeOf((*MockIncidentService)(nil).UnwatchIncident), ctx, incidentID, watcherID, requestedBy, admin)
+}
+
+// UpdateIncident mocks base method.
+func (m *MockIncidentService) UpdateIncident(ctx context.Context, incident *models.Incident) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateIncident", ctx, incident)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateIncident indicates an expected call of UpdateIncident.
+func (mr *MockIncidentServiceMockRecorder) UpdateIncident(ctx, incident any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateIncident", reflect.TypeOf((*MockIncidentService)(nil).UpdateIncident), ctx, incident)
+}
+
+// UpdateIncidentTemplate mocks base method.
+func (m *MockIncidentService) UpdateIncidentTemplate(ctx context.Context, template *models.IncidentTemplate) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateIncidentTemplate", ctx, template)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateIncidentTemplate indicates an expected call of UpdateIncidentTemplate.
+func (mr *MockIncidentServiceMockRecorder) UpdateIncidentTemplate(ctx, template any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateIncidentTemplate", reflect.TypeOf((*MockIncidentService)(nil).UpdateIncidentTemplate), ctx, template)
+}
+
+// ValidateIncident mocks base method.
+func (m *MockIncidentService) ValidateIncident(ctx context.Context, incident *models.Incident) (*models.GeofenceValidation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateIncident", ctx, incident)
+	ret0, _ := ret[0].(*models.GeofenceValidation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ValidateIncident indicates an expected call of ValidateIncident.
+func (mr *MockIncidentServiceMockRecorder) ValidateIncident(ctx, incident any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateIncident", reflect.TypeOf((*MockIncidentService)(nil).ValidateIncident), ctx, incident)
+}
+
+// WaitImports mocks base method.
+func (m *MockIncidentService) WaitImports(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WaitImports", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WaitImports indicates an expected call of WaitImports.
+func (mr *MockIncidentServiceMockRecorder) WaitImports(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitImports", reflect.TypeOf((*MockIncidentService)(nil).WaitImports), ctx)
+}
+
+// WatchIncident mocks base method.
+func (m *MockIncidentService) WatchIncident(ctx context.Context, watcher *models.IncidentWatcher) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WatchIncident", ctx, watcher)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WatchIncident indicates an expected call of WatchIncident.
+func (mr *MockIncidentServiceMockRecorder) WatchIncident(ctx, watcher any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchIncident", reflect.TypeOf((*MockIncidentService)(nil).WatchIncident), ctx, watcher)
 }
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shenikar/geo_broadcasting_system/internal/models"
+	"github.com/shenikar/geo_broadcasting_system/internal/webhook"
+	"github.com/sirupsen/logrus"
+)
+
+// ApproveIncident переводит черновик инцидента в статус active и публикует вебхук incident_approved
+func (s *incidentService) ApproveIncident(ctx context.Context, id uuid.UUID, reviewer string) (*models.Incident, error) {
+	incident, err := s.reviewDraft(ctx, id, models.StatusActive, reviewer)
+	if err != nil {
+		return nil, err
+	}
+
+	event := webhook.WebhookEvent{
+		SchemaVersion: webhook.SchemaVersion,
+		Type:          webhook.EventTypeIncidentApproved,
+		Latitude:      incident.Latitude,
+		Longitude:     incident.Longitude,
+		Timestamp:     time.Now(),
+		Incidents:     []*models.Incident{incident},
+	}
+	if err := s.webhookPublisher.Publish(ctx, event); err != nil {
+		s.logger.WithError(err).WithField("incident_id", id).Error("Failed to publish incident approved webhook")
+		// Это не критическая ошибка, продолжаем выполнение
+	}
+	return incident, nil
+}
+
+// RejectIncident переводит черновик инцидента в статус rejected и публикует вебхук incident_updated со сменой статуса.
+// Отклоненные инциденты никогда не участвуют в проверке местоположения.
+func (s *incidentService) RejectIncident(ctx context.Context, id uuid.UUID, reviewer string) (*models.Incident, error) {
+	incident, err := s.reviewDraft(ctx, id, models.StatusRejected, reviewer)
+	if err != nil {
+		return nil, err
+	}
+
+	event := webhook.WebhookEvent{
+		SchemaVersion: webhook.SchemaVersion,
+		Type:          webhook.EventTypeIncidentUpdated,
+		Latitude:      incident.Latitude,
+		Longitude:     incident.Longitude,
+		Timestamp:     time.Now(),
+		Incidents:     []*models.Incident{incident},
+		ChangedFields: map[string]webhook.FieldChange{
+			"status": {Old: models.StatusDraft, New: models.StatusRejected},
+		},
+	}
+	if err := s.webhookPublisher.Publish(ctx, event); err != nil {
+		s.logger.WithError(err).WithField("incident_id", id).Error("Failed to publish incident rejected webhook")
+		// Это не критическая ошибка, продолжаем выполнение
+	}
+	return incident, nil
+}
+
+// reviewDraft выполняет переход черновика в целевой статус с записью в историю статусов
+func (s *incidentService) reviewDraft(ctx context.Context, id uuid.UUID, toStatus models.IncidentStatus, reviewer string) (*models.Incident, error) {
+	log := s.logger.WithFields(logrus.Fields{
+		"service":     "incident",
+		"method":      "reviewDraft",
+		"incident_id": id,
+		"to_status":   toStatus,
+		"reviewer":    reviewer,
+	})
+	log.Info("Attempting to review draft incident")
+
+	incident, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		log.WithError(err).Warn("Attempted to review a non-existent incident")
+		return nil, fmt.Errorf("service: could not get incident: %w", err)
+	}
+
+	if incident.Status != models.StatusDraft {
+		log.WithField("status", incident.Status).Warn("Attempted to review an incident that is not a draft")
+		return nil, fmt.Errorf("service: incident is in status %s, not draft: %w", incident.Status, ErrStatusConflict)
+	}
+
+	change := &models.StatusChange{
+		IncidentID: id,
+		FromStatus: incident.Status,
+		ToStatus:   toStatus,
+		ChangedBy:  reviewer,
+	}
+	if err := s.repo.ChangeStatus(ctx, change); err != nil {
+		log.WithError(err).Error("Failed to change incident status in repository")
+		return nil, fmt.Errorf("service: could not change incident status: %w", err)
+	}
+	incident.Status = toStatus
+	incident.UpdatedAt = change.ChangedAt
+
+	log.Info("Draft incident reviewed successfully")
+	invalidateIncidentCache(ctx, s.repo, log, id)
+	s.invalidateLocationTiles(ctx, log)
+	return incident, nil
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/shenikar/geo_broadcasting_system/internal/config"
+	"github.com/shenikar/geo_broadcasting_system/internal/models"
+	"github.com/shenikar/geo_broadcasting_system/internal/webhook"
+	"github.com/sirupsen/logrus"
+)
+
+// outboxRetention - сколько хранятся отправленные сообщения outbox (для разбора инцидентов доставки)
+const outboxRetention = 24 * time.Hour
+
+// OutboxRelay - фоновая задача, переносящая зафиксированные сообщения outbox в очередь вебхуков
+// и помечающая их отправленными. Если процесс упадет после публикации, но до отметки, сообщение
+// будет опубликовано повторно: доставка выполняется по принципу at-least-once.
+type OutboxRelay struct {
+	repo      IncidentRepository
+	publisher webhook.WebhookPublisher
+	logger    *logrus.Logger
+	cfg       *config.Config
+}
+
+// NewOutboxRelay создает ретранслятор outbox
+func NewOutboxRelay(repo IncidentRepository, publisher webhook.WebhookPublisher, logger *logrus.Logger, cfg *config.Config) *OutboxRelay {
+	return &OutboxRelay{
+		repo:      repo,
+		publisher: publisher,
+		logger:    logger,
+		cfg:       cfg,
+	}
+}
+
+// Start запускает горутину переноса с периодом WEBHOOK_OUTBOX_POLL_INTERVAL.
+// При WEBHOOK_OUTBOX_ENABLED=false горутина не запускается.
+func (r *OutboxRelay) Start(ctx context.Context) {
+	if !r.cfg.WebhookOutboxEnabled {
+		r.logger.Info("Webhook outbox is disabled (WEBHOOK_OUTBOX_ENABLED=false)")
+		return
+	}
+
+	r.logger.WithField("interval", r.cfg.WebhookOutboxPollInterval).Info("Starting webhook outbox relay...")
+	go func() {
+		ticker := time.NewTicker(r.cfg.WebhookOutboxPollInterval)
+		defer ticker.Stop()
+		lastPurge := time.Time{}
+		for {
+			r.Run(ctx)
+			if time.Since(lastPurge) >= time.Hour {
+				r.purgeSent(ctx)
+				lastPurge = time.Now()
+			}
+			select {
+			case <-ctx.Done():
+				r.logger.Info("Stopping webhook outbox relay.")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Run переносит пакеты сообщений outbox в очередь, пока очередной пакет не окажется неполным
+// или публикация не завершится ошибкой. Возвращает количество перенесенных сообщений.
+func (r *OutboxRelay) Run(ctx context.Context) int {
+	log := r.logger.WithFields(logrus.Fields{
+		"service": "incident",
+		"method":  "RelayOutbox",
+	})
+
+	relayed := 0
+	for ctx.Err() == nil {
+		sent, err := r.repo.RelayOutbox(ctx, r.cfg.WebhookOutboxBatchSize, func(message *models.OutboxMessage) error {
+			var event webhook.WebhookEvent
+			if err := json.Unmarshal(message.Payload, &event); err != nil {
+				// Повтор не поможет: сообщение помечается отправленным, чтобы не блокировать очередь
+				log.WithError(err).WithField("outbox_id", message.ID).Error("Dropping undecodable outbox message")
+				return nil
+			}
+			return r.publisher.Publish(ctx, event)
+		})
+		relayed += sent
+		if err != nil {
+			log.WithError(err).Error("Failed to relay outbox messages")
+			break
+		}
+		if sent < r.cfg.WebhookOutboxBatchSize {
+			break
+		}
+	}
+
+	if relayed > 0 {
+		log.WithField("relayed", relayed).Debug("Outbox messages relayed to webhook queue")
+	}
+	return relayed
+}
+
+// purgeSent удаляет отправленные сообщения старше outboxRetention
+func (r *OutboxRelay) purgeSent(ctx context.Context) {
+	purged, err := r.repo.PurgeSentOutbox(ctx, time.Now().Add(-outboxRetention))
+	if err != nil {
+		r.logger.WithError(err).Warn("Failed to purge sent outbox messages")
+		return
+	}
+	if purged > 0 {
+		r.logger.WithField("purged", purged).Info("Sent outbox messages purged")
+	}
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/shenikar/geo_broadcasting_system/internal/models"
+	"github.com/shenikar/geo_broadcasting_system/pkg/geo"
+	"github.com/sirupsen/logrus"
+)
+
+// GetOverlappingIncidents возвращает активные инциденты, зоны которых пересекаются с зоной инцидента id
+// (сам инцидент не включается), в порядке убывания площади пересечения
+func (s *incidentService) GetOverlappingIncidents(ctx context.Context, id uuid.UUID) ([]*models.IncidentOverlap, error) {
+	log := s.logger.WithFields(logrus.Fields{
+		"service":     "incident",
+		"method":      "GetOverlappingIncidents",
+		"incident_id": id,
+	})
+	log.Info("Getting overlapping incidents")
+
+	base, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		log.WithError(err).Warn("Failed to get incident for overlap search")
+		return nil, fmt.Errorf("service: could not get incident: %w", err)
+	}
+
+	// Круги пересекаются, если расстояние между центрами не больше суммы радиусов:
+	// это поиск активных зон вокруг центра инцидента с буфером, равным его радиусу
+	candidates, err := s.repo.FindActiveLocation(ctx, base.Latitude, base.Longitude, base.RadiusMeters, 0)
+	if err != nil {
+		log.WithError(err).Error("Failed to find overlapping incidents in repository")
+		return nil, fmt.Errorf("service: failed to find overlapping incidents: %w", err)
+	}
+
+	center := geo.Point{Lat: base.Latitude, Lon: base.Longitude}
+	overlaps := make([]*models.IncidentOverlap, 0, len(candidates))
+	for _, incident := range candidates {
+		if incident.ID == base.ID {
+			continue
+		}
+		distance := geo.DistanceMeters(center, geo.Point{Lat: incident.Latitude, Lon: incident.Longitude})
+		overlaps = append(overlaps, &models.IncidentOverlap{
+			Incident:            incident,
+			OverlapAreaSqMeters: geo.CircleOverlapAreaSqMeters(distance, float64(base.RadiusMeters), float64(incident.RadiusMeters)),
+		})
+	}
+	sort.SliceStable(overlaps, func(i, j int) bool {
+		return overlaps[i].OverlapAreaSqMeters > overlaps[j].OverlapAreaSqMeters
+	})
+
+	log.WithField("count", len(overlaps)).Info("Overlapping incidents retrieved successfully")
+	return overlaps, nil
+}
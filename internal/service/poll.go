@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/shenikar/geo_broadcasting_system/internal/models"
+	"github.com/sirupsen/logrus"
+)
+
+// pollRecheckInterval - период перепроверки изменений при long polling. Изменения, сделанные другими
+// экземплярами сервиса, не будят ожидающих этого процесса и становятся видны не позже следующей перепроверки.
+const pollRecheckInterval = 5 * time.Second
+
+// changeNotifier сообщает ожидающим об изменении инцидентов: notify закрывает текущий канал и заменяет его новым,
+// поэтому один вызов будит всех, кто получил канал через wait до него
+type changeNotifier struct {
+	mu sync.Mutex
+	ch chan struct{}
+}
+
+func newChangeNotifier() *changeNotifier {
+	return &changeNotifier{ch: make(chan struct{})}
+}
+
+// wait возвращает канал, который закроется при следующем изменении инцидентов
+func (n *changeNotifier) wait() <-chan struct{} {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.ch
+}
+
+// notify будит всех ожидающих
+func (n *changeNotifier) notify() {
+	if n == nil {
+		return
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	close(n.ch)
+	n.ch = make(chan struct{})
+}
+
+// ListIncidentChanges возвращает не больше limit инцидентов (включая неактивные), измененных после позиции after,
+// в порядке (updated_at, id), курсор следующей страницы и признак того, что после нее есть еще изменения.
+// Курсор - время изменения и ID последнего инцидента страницы; на пустой странице он равен after.
+func (s *incidentService) ListIncidentChanges(ctx context.Context, after models.ChangeCursor, limit int) ([]*models.Incident, models.ChangeCursor, bool, error) {
+	if limit < 1 || limit > MaxPageSize {
+		limit = 20
+	}
+	log := s.logger.WithFields(logrus.Fields{
+		"service":  "incident",
+		"method":   "ListIncidentChanges",
+		"since":    after.UpdatedAt,
+		"after_id": after.ID,
+		"limit":    limit,
+	})
+
+	// Лишняя запись показывает, есть ли изменения после страницы, и в ответ не попадает
+	incidents, err := s.repo.ListIncidents(ctx, 1, limit+1, models.IncidentFilter{ChangedAfter: &after})
+	if err != nil {
+		log.WithError(err).Error("Failed to list incident changes from repository")
+		return nil, after, false, fmt.Errorf("service: could not list incident changes: %w", err)
+	}
+	hasMore := len(incidents) > limit
+	if hasMore {
+		incidents = incidents[:limit]
+	}
+	cursor := after
+	if len(incidents) > 0 {
+		last := incidents[len(incidents)-1]
+		cursor = models.ChangeCursor{UpdatedAt: last.UpdatedAt, ID: last.ID}
+	}
+	return incidents, cursor, hasMore, nil
+}
+
+// PollIncidentChanges возвращает инциденты, измененные после позиции after, дожидаясь их появления не дольше timeout.
+// Вместе с изменениями возвращается курсор для следующего запроса - время изменения и ID последнего из них, взятые
+// из базы, поэтому расхождение часов сервиса и базы не приводит к пропуску изменений. Если изменений больше,
+// чем помещается в ответ (MaxPageSize), остальные вернутся следующим запросом с этим курсором.
+// По истечении timeout без изменений возвращается пустой список и курсор, равный after.
+func (s *incidentService) PollIncidentChanges(ctx context.Context, after models.ChangeCursor, timeout time.Duration) ([]*models.Incident, models.ChangeCursor, error) {
+	log := s.logger.WithFields(logrus.Fields{
+		"service":  "incident",
+		"method":   "PollIncidentChanges",
+		"since":    after.UpdatedAt,
+		"after_id": after.ID,
+		"timeout":  timeout,
+	})
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	recheck := time.NewTicker(pollRecheckInterval)
+	defer recheck.Stop()
+
+	for {
+		// Канал берется до выборки, чтобы изменение между выборкой и ожиданием не было пропущено
+		changed := s.changes.wait()
+		incidents, err := s.repo.ListIncidents(ctx, 1, MaxPageSize, models.IncidentFilter{ChangedAfter: &after})
+		if err != nil {
+			log.WithError(err).Error("Failed to list incident changes from repository")
+			return nil, after, fmt.Errorf("service: could not poll incident changes: %w", err)
+		}
+		if len(incidents) > 0 {
+			last := incidents[len(incidents)-1]
+			return incidents, models.ChangeCursor{UpdatedAt: last.UpdatedAt, ID: last.ID}, nil
+		}
+
+		select {
+		case <-changed:
+		case <-recheck.C:
+		case <-deadline.C:
+			return incidents, after, nil
+		case <-ctx.Done():
+			return incidents, after, nil
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shenikar/geo_broadcasting_system/internal/models"
+	"github.com/sirupsen/logrus"
+)
+
+// publicFeedRateLimitWindow - окно лимита запросов к публичной ленте (PUBLIC_FEED_RATE_LIMIT_PER_MINUTE)
+const publicFeedRateLimitWindow = time.Minute
+
+// ListPublicIncidents возвращает страницу активных инцидентов для публичной ленты: только с флагом public,
+// без silent и без служебных категорий из PUBLIC_FEED_EXCLUDED_CATEGORIES.
+// Запросы ограничиваются по clientIP; при превышении лимита возвращается *RateLimitError.
+func (s *incidentService) ListPublicIncidents(ctx context.Context, clientIP string, page, pageSize int) ([]*models.Incident, error) {
+	if page < 1 {
+		page = 1
+	}
+
+	if pageSize < 1 || pageSize > MaxPageSize {
+		pageSize = 20
+	}
+
+	log := s.logger.WithFields(logrus.Fields{
+		"service":   "incident",
+		"method":    "ListPublicIncidents",
+		"client_ip": clientIP,
+		"page":      page,
+		"page_size": pageSize,
+	})
+
+	if err := s.reservePublicFeedRequest(ctx, clientIP, log); err != nil {
+		return nil, err
+	}
+
+	incidents, err := s.repo.ListPublicIncidents(ctx, s.cfg.PublicFeedExcludedCategories, page, pageSize)
+	if err != nil {
+		log.WithError(err).Error("Failed to list public incidents from repository")
+		return nil, fmt.Errorf("service: could not list public incidents: %w", err)
+	}
+	return incidents, nil
+}
+
+// reservePublicFeedRequest учитывает запрос в лимите клиента и возвращает *RateLimitError, если лимит исчерпан.
+// Как и квота проверок, лимит не блокирует ленту при недоступности Redis.
+func (s *incidentService) reservePublicFeedRequest(ctx context.Context, clientIP string, log *logrus.Entry) error {
+	if s.cfg.PublicFeedRateLimitPerMinute <= 0 {
+		return nil
+	}
+	wait, err := s.repo.ReservePublicFeedRequest(ctx, clientIP, s.cfg.PublicFeedRateLimitPerMinute, publicFeedRateLimitWindow)
+	if err != nil {
+		log.WithError(err).Warn("Failed to check public feed rate limit, allowing the request")
+		return nil
+	}
+	if wait > 0 {
+		log.WithField("retry_after", wait).Warn("Public feed rate limit exceeded")
+		return &RateLimitError{Err: ErrPublicFeedRateLimited, RetryAfter: wait}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/shenikar/geo_broadcasting_system/internal/config"
+	"github.com/sirupsen/logrus"
+)
+
+// IncidentPurger - фоновая задача, безвозвратно удаляющая инциденты, которые неактивны
+// дольше INCIDENT_RETENTION_DAYS. Удаление выполняется пакетами, чтобы не держать долгих блокировок.
+type IncidentPurger struct {
+	repo   IncidentRepository
+	logger *logrus.Logger
+	cfg    *config.Config
+}
+
+// NewIncidentPurger создает задачу очистки неактивных инцидентов
+func NewIncidentPurger(repo IncidentRepository, logger *logrus.Logger, cfg *config.Config) *IncidentPurger {
+	return &IncidentPurger{
+		repo:   repo,
+		logger: logger,
+		cfg:    cfg,
+	}
+}
+
+// Start запускает горутину очистки с периодом INCIDENT_PURGE_INTERVAL.
+// При INCIDENT_RETENTION_DAYS = 0 очистка отключена и горутина не запускается.
+func (p *IncidentPurger) Start(ctx context.Context) {
+	if p.cfg.IncidentRetentionDays <= 0 {
+		p.logger.Info("Incident purge is disabled (INCIDENT_RETENTION_DAYS=0)")
+		return
+	}
+
+	p.logger.WithField("retention_days", p.cfg.IncidentRetentionDays).Info("Starting incident purge job...")
+	go func() {
+		ticker := time.NewTicker(p.cfg.IncidentPurgeInterval)
+		defer ticker.Stop()
+		for {
+			p.Run(ctx)
+			select {
+			case <-ctx.Done():
+				p.logger.Info("Stopping incident purge job.")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Run выполняет один проход очистки: удаляет пакеты, пока очередной пакет не окажется неполным,
+// и инвалидирует кэш удаленных инцидентов. Возвращает количество удаленных инцидентов.
+func (p *IncidentPurger) Run(ctx context.Context) int {
+	log := p.logger.WithFields(logrus.Fields{
+		"service": "incident",
+		"method":  "PurgeInactiveIncidents",
+	})
+	before := time.Now().AddDate(0, 0, -p.cfg.IncidentRetentionDays)
+
+	purged := 0
+	for ctx.Err() == nil {
+		ids, err := p.repo.PurgeInactiveIncidents(ctx, before, p.cfg.IncidentPurgeBatchSize)
+		if err != nil {
+			log.WithError(err).Error("Failed to purge inactive incidents")
+			break
+		}
+		invalidateIncidentCache(ctx, p.repo, log, ids...)
+		purged += len(ids)
+		if len(ids) < p.cfg.IncidentPurgeBatchSize {
+			break
+		}
+	}
+
+	log.WithFields(logrus.Fields{"purged": purged, "before": before}).Info("Inactive incidents purge finished")
+	return purged
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/shenikar/geo_broadcasting_system/internal/models"
+	"github.com/shenikar/geo_broadcasting_system/internal/webhook"
+	"github.com/sirupsen/logrus"
+)
+
+// MaxReevaluationLimit - наибольшее число расхождений в одном ответе повторной оценки проверок
+const MaxReevaluationLimit = 1000
+
+// ReevaluateLocationChecks повторно оценивает сохраненные проверки местоположения за период: находит проверки,
+// признанные безопасными, в которых пользователь был в зоне инцидента, активного в момент проверки
+// (см. IncidentRepository.FindMissedLocationAlerts). Сохраненные проверки не меняются.
+// С q.Notify по каждому расхождению публикуется вебхук location_check_reevaluated с небеззвучными инцидентами;
+// повторный запуск по тому же периоду отправит их снова, поэтому страницы следует проходить курсором NextAfterID.
+func (s *incidentService) ReevaluateLocationChecks(ctx context.Context, q models.LocationCheckReevaluationQuery) (*models.LocationCheckReevaluation, error) {
+	log := s.logger.WithFields(logrus.Fields{
+		"service":  "incident",
+		"method":   "ReevaluateLocationChecks",
+		"from":     q.From,
+		"to":       q.To,
+		"after_id": q.AfterID,
+		"notify":   q.Notify,
+	})
+
+	if !q.To.After(q.From) {
+		return nil, fmt.Errorf("service: reevaluation 'to' must be after 'from': %w", ErrInvalidReevaluation)
+	}
+	if q.Limit < 1 || q.Limit > MaxReevaluationLimit {
+		q.Limit = MaxReevaluationLimit
+	}
+
+	checked, err := s.repo.CountLocationChecks(ctx, q.From, q.To)
+	if err != nil {
+		log.WithError(err).Error("Failed to count location checks")
+		return nil, fmt.Errorf("service: could not count location checks: %w", err)
+	}
+	// Лишняя запись показывает, что за страницей есть еще расхождения
+	discrepancies, err := s.repo.FindMissedLocationAlerts(ctx, q.From, q.To, q.AfterID, q.Limit+1)
+	if err != nil {
+		log.WithError(err).Error("Failed to reevaluate location checks")
+		return nil, fmt.Errorf("service: could not reevaluate location checks: %w", err)
+	}
+
+	result := &models.LocationCheckReevaluation{Checked: checked, Discrepancies: discrepancies}
+	if len(discrepancies) > q.Limit {
+		result.Discrepancies = discrepancies[:q.Limit]
+		result.NextAfterID = result.Discrepancies[q.Limit-1].ID
+	}
+	if q.Notify {
+		result.WebhooksPublished = s.publishMissedAlerts(ctx, result.Discrepancies, log)
+	}
+
+	log.WithFields(logrus.Fields{
+		"checked":       checked,
+		"discrepancies": len(result.Discrepancies),
+		"webhooks":      result.WebhooksPublished,
+	}).Info("Location checks reevaluated")
+	return result, nil
+}
+
+// publishMissedAlerts публикует вебхуки о пропущенных предупреждениях и возвращает число опубликованных.
+// В вебхук попадают инциденты в их текущем состоянии; беззвучные инциденты не попадают, как и при проверке.
+func (s *incidentService) publishMissedAlerts(ctx context.Context, checks []*models.LocationCheck, log *logrus.Entry) int {
+	incidents := make(map[uuid.UUID]*models.Incident)
+	published := 0
+	for _, check := range checks {
+		notifiable := make([]*models.Incident, 0, len(check.IncidentIDs))
+		for _, id := range check.IncidentIDs {
+			incident, ok := incidents[id]
+			if !ok {
+				var err error
+				incident, err = s.repo.GetByID(ctx, id)
+				if err != nil {
+					log.WithError(err).WithField("incident_id", id).Warn("Failed to get incident for missed alert webhook")
+				}
+				incidents[id] = incident
+			}
+			if incident != nil && !incident.Silent {
+				notifiable = append(notifiable, incident)
+			}
+		}
+		if len(notifiable) == 0 {
+			continue
+		}
+
+		event := webhook.WebhookEvent{
+			SchemaVersion: webhook.SchemaVersion,
+			Type:          webhook.EventTypeLocationCheckReevaluated,
+			UserID:        check.UserID,
+			Latitude:      check.Latitude,
+			Longitude:     check.Longitude,
+			IsDangerous:   true,
+			Timestamp:     check.CheckedAt,
+			Incidents:     notifiable,
+		}
+		if err := s.webhookPublisher.Publish(ctx, event); err != nil {
+			log.WithError(err).WithField("location_check_id", check.ID).Error("Failed to publish missed alert webhook")
+			continue
+		}
+		published++
+	}
+	return published
+}
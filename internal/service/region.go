@@ -0,0 +1,17 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/shenikar/geo_broadcasting_system/pkg/geo"
+)
+
+// checkCoordinates проверяет координаты по областям COORDINATE_ALLOW_REGIONS и COORDINATE_DENY_REGIONS.
+// Координаты вне разрешенных областей или внутри запрещенной возвращают ErrCoordinatesNotAllowed.
+func (s *incidentService) checkCoordinates(lat, lon float64) error {
+	policy := geo.RegionPolicy{Allow: s.cfg.CoordinateAllowRegions, Deny: s.cfg.CoordinateDenyRegions}
+	if policy.Permits(geo.Point{Lat: lat, Lon: lon}) {
+		return nil
+	}
+	return fmt.Errorf("service: coordinates (%g, %g) are outside of the service area: %w", lat, lon, ErrCoordinatesNotAllowed)
+}
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// htmlTagPattern находит HTML-теги и комментарии
+var htmlTagPattern = regexp.MustCompile(`(?s)<!--.*?-->|</?[a-zA-Z][^<>]*>`)
+
+// angleBracketReplacer экранирует угловые скобки, оставшиеся после удаления тегов.
+// Амперсанд не экранируется, чтобы повторная очистка не меняла уже очищенный текст.
+var angleBracketReplacer = strings.NewReplacer("<", "&lt;", ">", "&gt;")
+
+// sanitizeText удаляет HTML-теги и управляющие символы из пользовательского текста
+// и экранирует оставшиеся угловые скобки. При multiline сохраняются переводы строк и табуляция.
+func sanitizeText(s string, multiline bool) string {
+	s = htmlTagPattern.ReplaceAllString(s, "")
+	s = strings.Map(func(r rune) rune {
+		if multiline && (r == '\n' || r == '\t') {
+			return r
+		}
+		if unicode.IsControl(r) || r == unicode.ReplacementChar {
+			return -1
+		}
+		return r
+	}, s)
+	s = angleBracketReplacer.Replace(s)
+	return strings.TrimSpace(s)
+}
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/shenikar/geo_broadcasting_system/internal/models"
+)
+
+// manualStatusTransitions - матрица переходов статуса, допустимых при ручном обновлении инцидента.
+// Черновик становится активным только через модерацию (approve), а resolved - конечный статус:
+// завершившийся инцидент не возвращается в работу, вместо этого создается новый.
+var manualStatusTransitions = map[models.IncidentStatus][]models.IncidentStatus{
+	models.StatusActive:   {models.StatusInactive, models.StatusResolved},
+	models.StatusDraft:    {models.StatusInactive},
+	models.StatusRejected: {models.StatusDraft},
+	models.StatusInactive: {models.StatusActive, models.StatusResolved},
+	models.StatusResolved: {},
+}
+
+// checkStatusTransition проверяет, допустим ли ручной переход из статуса from в статус to.
+// Сохранение текущего статуса допустимо всегда. Недопустимый переход возвращает ErrStatusConflict.
+func checkStatusTransition(from, to models.IncidentStatus) error {
+	if from == to || slices.Contains(manualStatusTransitions[from], to) {
+		return nil
+	}
+	if from == models.StatusDraft && to == models.StatusActive {
+		return fmt.Errorf("service: draft incident can only become active through approval: %w", ErrStatusConflict)
+	}
+	return fmt.Errorf("service: status transition from %s to %s is not allowed: %w", from, to, ErrStatusConflict)
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/shenikar/geo_broadcasting_system/internal/models"
+	"github.com/sirupsen/logrus"
+)
+
+// validateTemplate проверяет, что по шаблону можно создать инцидент: радиус задан явно
+// или берется из правил категории и укладывается в их диапазон. Категория приводится к нижнему регистру.
+func (s *incidentService) validateTemplate(template *models.IncidentTemplate) error {
+	probe := &models.Incident{Category: template.Category, RadiusMeters: template.RadiusMeters}
+	if err := s.applyCategoryRules(probe); err != nil {
+		return err
+	}
+	if probe.RadiusMeters <= 0 {
+		return fmt.Errorf("service: template needs radius_meters or a category with a default radius: %w", ErrInvalidIncident)
+	}
+	template.Category = probe.Category
+	return nil
+}
+
+// CreateIncidentTemplate сохраняет новый шаблон инцидента
+func (s *incidentService) CreateIncidentTemplate(ctx context.Context, template *models.IncidentTemplate) error {
+	log := s.logger.WithFields(logrus.Fields{
+		"service": "incident",
+		"method":  "CreateIncidentTemplate",
+		"name":    template.Name,
+	})
+
+	if err := s.validateTemplate(template); err != nil {
+		log.WithError(err).Warn("Incident template rejected")
+		return err
+	}
+	if err := s.repo.CreateIncidentTemplate(ctx, template); err != nil {
+		log.WithError(err).Error("Failed to create incident template in repository")
+		return fmt.Errorf("service: could not create incident template: %w", err)
+	}
+
+	log.WithField("template_id", template.ID).Info("Incident template created successfully")
+	return nil
+}
+
+// GetIncidentTemplate возвращает шаблон инцидента по ID
+func (s *incidentService) GetIncidentTemplate(ctx context.Context, id uuid.UUID) (*models.IncidentTemplate, error) {
+	template, err := s.repo.GetIncidentTemplate(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("service: could not get incident template: %w", err)
+	}
+	return template, nil
+}
+
+// ListIncidentTemplates возвращает все шаблоны инцидентов
+func (s *incidentService) ListIncidentTemplates(ctx context.Context) ([]*models.IncidentTemplate, error) {
+	templates, err := s.repo.ListIncidentTemplates(ctx)
+	if err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"service": "incident",
+			"method":  "ListIncidentTemplates",
+		}).WithError(err).Error("Failed to list incident templates from repository")
+		return nil, fmt.Errorf("service: could not list incident templates: %w", err)
+	}
+	return templates, nil
+}
+
+// UpdateIncidentTemplate обновляет шаблон инцидента. Созданные ранее инциденты не меняются.
+func (s *incidentService) UpdateIncidentTemplate(ctx context.Context, template *models.IncidentTemplate) error {
+	log := s.logger.WithFields(logrus.Fields{
+		"service":     "incident",
+		"method":      "UpdateIncidentTemplate",
+		"template_id": template.ID,
+	})
+
+	if err := s.validateTemplate(template); err != nil {
+		log.WithError(err).Warn("Incident template rejected")
+		return err
+	}
+	if err := s.repo.UpdateIncidentTemplate(ctx, template); err != nil {
+		log.WithError(err).Warn("Failed to update incident template in repository")
+		return fmt.Errorf("service: could not update incident template: %w", err)
+	}
+
+	log.Info("Incident template updated successfully")
+	return nil
+}
+
+// DeleteIncidentTemplate удаляет шаблон инцидента
+func (s *incidentService) DeleteIncidentTemplate(ctx context.Context, id uuid.UUID) error {
+	log := s.logger.WithFields(logrus.Fields{
+		"service":     "incident",
+		"method":      "DeleteIncidentTemplate",
+		"template_id": id,
+	})
+
+	if err := s.repo.DeleteIncidentTemplate(ctx, id); err != nil {
+		log.WithError(err).Warn("Failed to delete incident template in repository")
+		return fmt.Errorf("service: could not delete incident template: %w", err)
+	}
+
+	log.Info("Incident template deleted successfully")
+	return nil
+}
+
+// CreateIncidentFromTemplate создает инцидент в переданной точке по шаблону templateID.
+// Инцидент проходит те же проверки, что и при обычном создании (правила категорий, очистка текста).
+func (s *incidentService) CreateIncidentFromTemplate(ctx context.Context, templateID uuid.UUID, incident *models.Incident) error {
+	template, err := s.repo.GetIncidentTemplate(ctx, templateID)
+	if err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"service":     "incident",
+			"method":      "CreateIncidentFromTemplate",
+			"template_id": templateID,
+		}).WithError(err).Warn("Failed to get incident template")
+		return fmt.Errorf("service: could not get incident template: %w", err)
+	}
+
+	template.Apply(incident)
+	return s.CreateIncident(ctx, incident)
+}
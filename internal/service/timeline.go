@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shenikar/geo_broadcasting_system/internal/models"
+	"github.com/sirupsen/logrus"
+)
+
+// MaxTimelineBuckets - максимальное число корзин временной шкалы в одном запросе
+const MaxTimelineBuckets = 1000
+
+// timelineStep возвращает длительность корзины временной шкалы
+func timelineStep(interval string) time.Duration {
+	if interval == models.TimelineHour {
+		return time.Hour
+	}
+	return 24 * time.Hour
+}
+
+// GetIncidentTimeline возвращает число созданных инцидентов по корзинам интервала.
+// Корзины без инцидентов заполняются нулями, чтобы клиенту не приходилось достраивать шкалу.
+func (s *incidentService) GetIncidentTimeline(ctx context.Context, q models.TimelineQuery) ([]*models.TimelineBucket, error) {
+	log := s.logger.WithFields(logrus.Fields{
+		"service":  "incident",
+		"method":   "GetIncidentTimeline",
+		"from":     q.From,
+		"to":       q.To,
+		"interval": q.Interval,
+	})
+
+	step := timelineStep(q.Interval)
+	start := q.From.UTC().Truncate(step)
+	if !q.To.After(q.From) {
+		return nil, fmt.Errorf("service: timeline 'to' must be after 'from': %w", ErrInvalidTimeline)
+	}
+	if q.To.Sub(start) > step*MaxTimelineBuckets {
+		return nil, fmt.Errorf("service: timeline spans more than %d buckets: %w", MaxTimelineBuckets, ErrInvalidTimeline)
+	}
+
+	counts, err := s.repo.IncidentTimeline(ctx, q)
+	if err != nil {
+		log.WithError(err).Error("Failed to build incident timeline in repository")
+		return nil, fmt.Errorf("service: could not build incident timeline: %w", err)
+	}
+
+	byStart := make(map[time.Time]int, len(counts))
+	for _, bucket := range counts {
+		byStart[bucket.Start.UTC()] = bucket.Count
+	}
+	buckets := make([]*models.TimelineBucket, 0)
+	for t := start; t.Before(q.To); t = t.Add(step) {
+		buckets = append(buckets, &models.TimelineBucket{Start: t, Count: byStart[t]})
+	}
+
+	log.WithField("buckets", len(buckets)).Debug("Incident timeline built")
+	return buckets, nil
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/shenikar/geo_broadcasting_system/internal/models"
+	"github.com/sirupsen/logrus"
+)
+
+// AddUserLocation сохраняет место пользователя (дом, работа и т.п.) для проактивных оповещений
+func (s *incidentService) AddUserLocation(ctx context.Context, location *models.UserLocation) error {
+	log := s.logger.WithFields(logrus.Fields{
+		"service": "incident",
+		"method":  "AddUserLocation",
+		"user_id": location.UserID,
+		"label":   location.Label,
+	})
+	log.Info("Attempting to save user location")
+
+	if err := s.repo.CreateUserLocation(ctx, location); err != nil {
+		log.WithError(err).Error("Failed to save user location in repository")
+		return fmt.Errorf("service: could not save user location: %w", err)
+	}
+
+	log.WithField("location_id", location.ID).Info("User location saved successfully")
+	return nil
+}
+
+// ListUserLocations возвращает сохраненные места пользователя
+func (s *incidentService) ListUserLocations(ctx context.Context, userID string) ([]*models.UserLocation, error) {
+	log := s.logger.WithFields(logrus.Fields{
+		"service": "incident",
+		"method":  "ListUserLocations",
+		"user_id": userID,
+	})
+
+	locations, err := s.repo.ListUserLocations(ctx, userID)
+	if err != nil {
+		log.WithError(err).Error("Failed to list user locations from repository")
+		return nil, fmt.Errorf("service: could not list user locations: %w", err)
+	}
+	return locations, nil
+}
+
+// DeleteUserLocation удаляет сохраненное место пользователя
+func (s *incidentService) DeleteUserLocation(ctx context.Context, userID string, id uuid.UUID) error {
+	log := s.logger.WithFields(logrus.Fields{
+		"service":     "incident",
+		"method":      "DeleteUserLocation",
+		"user_id":     userID,
+		"location_id": id,
+	})
+	log.Info("Attempting to delete user location")
+
+	if err := s.repo.DeleteUserLocation(ctx, userID, id); err != nil {
+		log.WithError(err).Warn("Failed to delete user location in repository")
+		return fmt.Errorf("service: could not delete user location: %w", err)
+	}
+
+	log.Info("User location deleted successfully")
+	return nil
+}
+
+// GetUserAlerts проверяет каждое сохраненное место пользователя на попадание в зону активных инцидентов.
+// Возвращаются только места, для которых найден хотя бы один инцидент. В отличие от CheckLocation,
+// проверка не записывается в историю проверок и не порождает вебхук.
+func (s *incidentService) GetUserAlerts(ctx context.Context, userID string) ([]*models.LocationAlert, error) {
+	log := s.logger.WithFields(logrus.Fields{
+		"service": "incident",
+		"method":  "GetUserAlerts",
+		"user_id": userID,
+	})
+
+	locations, err := s.repo.ListUserLocations(ctx, userID)
+	if err != nil {
+		log.WithError(err).Error("Failed to list user locations from repository")
+		return nil, fmt.Errorf("service: could not list user locations: %w", err)
+	}
+
+	alerts := make([]*models.LocationAlert, 0)
+	for _, location := range locations {
+		incidents, err := s.repo.FindActiveLocation(ctx, location.Latitude, location.Longitude, 0, 0)
+		if err != nil {
+			log.WithError(err).WithField("label", location.Label).Error("Failed to find active incidents for user location")
+			return nil, fmt.Errorf("service: could not check location %q: %w", location.Label, err)
+		}
+		if len(incidents) > 0 {
+			alerts = append(alerts, &models.LocationAlert{Location: location, Incidents: incidents})
+		}
+	}
+
+	log.WithFields(logrus.Fields{"locations": len(locations), "alerts": len(alerts)}).Info("User alerts computed")
+	return alerts, nil
+}
+
+// GetUserStatus переоценивает координаты последней проверки пользователя по активным сейчас инцидентам.
+// Как и GetUserAlerts, не записывает проверку в историю и не порождает вебхук.
+// Если пользователь ни разу не проверялся, возвращается ErrNoLocationCheck.
+func (s *incidentService) GetUserStatus(ctx context.Context, userID string) (*models.UserStatus, error) {
+	log := s.logger.WithFields(logrus.Fields{
+		"service": "incident",
+		"method":  "GetUserStatus",
+		"user_id": userID,
+	})
+
+	checks, err := s.repo.ListLocationChecksByUser(ctx, userID, 1, 1)
+	if err != nil {
+		log.WithError(err).Error("Failed to get last location check from repository")
+		return nil, fmt.Errorf("service: could not get last location check: %w", err)
+	}
+	if len(checks) == 0 {
+		return nil, fmt.Errorf("service: user %s: %w", userID, ErrNoLocationCheck)
+	}
+	lastCheck := checks[0]
+
+	incidents, err := s.repo.FindActiveLocation(ctx, lastCheck.Latitude, lastCheck.Longitude, 0, 0)
+	if err != nil {
+		log.WithError(err).Error("Failed to find active incidents for last user location")
+		return nil, fmt.Errorf("service: could not check last user location: %w", err)
+	}
+
+	status := &models.UserStatus{LastCheck: lastCheck, Incidents: incidents}
+	log.WithField("is_danger", status.IsDangerous()).Info("User status computed")
+	return status, nil
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shenikar/geo_broadcasting_system/internal/models"
+	"github.com/shenikar/geo_broadcasting_system/internal/webhook"
+	"github.com/sirupsen/logrus"
+)
+
+// ListIncidentVersions возвращает снимки всех версий инцидента, начиная с последней
+func (s *incidentService) ListIncidentVersions(ctx context.Context, id uuid.UUID) ([]*models.IncidentVersion, error) {
+	log := s.logger.WithFields(logrus.Fields{
+		"service":     "incident",
+		"method":      "ListIncidentVersions",
+		"incident_id": id,
+	})
+
+	if _, err := s.repo.GetByID(ctx, id); err != nil {
+		log.WithError(err).Warn("Attempted to list versions of a non-existent incident")
+		return nil, fmt.Errorf("service: could not get incident: %w", err)
+	}
+	versions, err := s.repo.ListIncidentVersions(ctx, id)
+	if err != nil {
+		log.WithError(err).Error("Failed to list incident versions from repository")
+		return nil, fmt.Errorf("service: could not list incident versions: %w", err)
+	}
+	return versions, nil
+}
+
+// RevertIncident возвращает инцидент к состоянию версии version. Откат сохраняется как новая версия,
+// поэтому история не теряется и сам откат тоже можно отменить. Правила переходов статуса при откате
+// не применяются: восстанавливается ранее существовавшее состояние. Смена статуса записывается в историю
+// статусов от имени revertedBy, а подписчики получают вебхук incident_updated с измененными полями.
+func (s *incidentService) RevertIncident(ctx context.Context, id uuid.UUID, version int, revertedBy string) (*models.Incident, error) {
+	log := s.logger.WithFields(logrus.Fields{
+		"service":     "incident",
+		"method":      "RevertIncident",
+		"incident_id": id,
+		"version":     version,
+		"reverted_by": revertedBy,
+	})
+	log.Info("Attempting to revert incident")
+
+	existing, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		log.WithError(err).Warn("Attempted to revert a non-existent incident")
+		return nil, fmt.Errorf("service: could not get incident: %w", err)
+	}
+	target, err := s.repo.GetIncidentVersion(ctx, id, version)
+	if err != nil {
+		log.WithError(err).Warn("Failed to get incident version")
+		return nil, fmt.Errorf("service: could not get incident version: %w", err)
+	}
+
+	snapshot := target.Incident
+	before := *existing
+	existing.Name = snapshot.Name
+	existing.Description = snapshot.Description
+	existing.Latitude = snapshot.Latitude
+	existing.Longitude = snapshot.Longitude
+	existing.RadiusMeters = snapshot.RadiusMeters
+	existing.Status = snapshot.Status
+	existing.MediaURLs = snapshot.MediaURLs
+	existing.Source = snapshot.Source
+	existing.Confidence = snapshot.Confidence
+	existing.Silent = snapshot.Silent
+	existing.Public = snapshot.Public
+	if snapshot.Severity != "" {
+		existing.Severity = snapshot.Severity
+	}
+	existing.Category = snapshot.Category
+	existing.ExternalID = snapshot.ExternalID
+	existing.ResolutionReason = snapshot.ResolutionReason
+
+	var change *models.StatusChange
+	if existing.Status != before.Status {
+		change = &models.StatusChange{IncidentID: id, FromStatus: before.Status, ToStatus: existing.Status, ChangedBy: revertedBy}
+	}
+	if err := s.repo.Revert(ctx, existing, change); err != nil {
+		log.WithError(err).Error("Failed to save reverted incident in repository")
+		return nil, fmt.Errorf("service: could not revert incident: %w", err)
+	}
+	log.Info("Incident reverted successfully")
+
+	invalidateIncidentCache(ctx, s.repo, log, id)
+	s.invalidateLocationTiles(ctx, log)
+
+	changes := diffIncidents(&before, existing)
+	if len(changes) == 0 {
+		return existing, nil
+	}
+	event := webhook.WebhookEvent{
+		SchemaVersion: webhook.SchemaVersion,
+		Type:          webhook.EventTypeIncidentUpdated,
+		Latitude:      existing.Latitude,
+		Longitude:     existing.Longitude,
+		Timestamp:     time.Now(),
+		Incidents:     []*models.Incident{existing},
+		ChangedFields: changes,
+	}
+	if err := s.webhookPublisher.Publish(ctx, event); err != nil {
+		log.WithError(err).Error("Failed to publish incident updated webhook")
+		// Это не критическая ошибка, продолжаем выполнение
+	}
+	return existing, nil
+}
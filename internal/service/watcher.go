@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/shenikar/geo_broadcasting_system/internal/models"
+	"github.com/shenikar/geo_broadcasting_system/pkg/netguard"
+	"github.com/sirupsen/logrus"
+)
+
+// WatchIncident добавляет наблюдателя инцидента: воркер вебхуков доставляет ему каждое событие жизненного цикла
+// инцидента в дополнение к подпискам WEBHOOK_URL и WEBHOOK_SUBSCRIPTIONS_FILE: одобрение, изменение (в том числе
+// отклонение черновика и обновление из импорта или ленты), эскалацию и деактивацию (вручную, массовую, при слиянии
+// или исчезновении из ленты). Адрес проверяется по WATCH_URL_ALLOWED_HOSTS, а его хост должен разрешаться
+// только в публичные адреса.
+func (s *incidentService) WatchIncident(ctx context.Context, watcher *models.IncidentWatcher) error {
+	log := s.logger.WithFields(logrus.Fields{
+		"service":     "incident",
+		"method":      "WatchIncident",
+		"incident_id": watcher.IncidentID,
+		"url":         watcher.URL,
+		"created_by":  watcher.CreatedBy,
+	})
+
+	if err := s.checkWatchURL(ctx, watcher.URL); err != nil {
+		log.WithError(err).Warn("Watch URL rejected")
+		return err
+	}
+	if _, err := s.repo.GetByID(ctx, watcher.IncidentID); err != nil {
+		log.WithError(err).Warn("Attempted to watch a non-existent incident")
+		return fmt.Errorf("service: could not get incident: %w", err)
+	}
+
+	if err := s.repo.CreateIncidentWatcher(ctx, watcher); err != nil {
+		log.WithError(err).Warn("Failed to create incident watcher")
+		return fmt.Errorf("service: could not create incident watcher: %w", err)
+	}
+	log.WithField("watcher_id", watcher.ID).Info("Incident watcher added")
+	return nil
+}
+
+// ListIncidentWatchers возвращает наблюдателей инцидента
+func (s *incidentService) ListIncidentWatchers(ctx context.Context, incidentID uuid.UUID) ([]*models.IncidentWatcher, error) {
+	log := s.logger.WithFields(logrus.Fields{
+		"service":     "incident",
+		"method":      "ListIncidentWatchers",
+		"incident_id": incidentID,
+	})
+
+	if _, err := s.repo.GetByID(ctx, incidentID); err != nil {
+		log.WithError(err).Warn("Attempted to list watchers of a non-existent incident")
+		return nil, fmt.Errorf("service: could not get incident: %w", err)
+	}
+	watchers, err := s.repo.ListIncidentWatchers(ctx, incidentID)
+	if err != nil {
+		log.WithError(err).Error("Failed to list incident watchers from repository")
+		return nil, fmt.Errorf("service: could not list incident watchers: %w", err)
+	}
+	return watchers, nil
+}
+
+// UnwatchIncident удаляет наблюдателя инцидента. Не административный ключ может удалить только добавленных им наблюдателей.
+func (s *incidentService) UnwatchIncident(ctx context.Context, incidentID, watcherID uuid.UUID, requestedBy string, admin bool) error {
+	log := s.logger.WithFields(logrus.Fields{
+		"service":      "incident",
+		"method":       "UnwatchIncident",
+		"incident_id":  incidentID,
+		"watcher_id":   watcherID,
+		"requested_by": requestedBy,
+	})
+
+	if !admin {
+		watchers, err := s.repo.ListIncidentWatchers(ctx, incidentID)
+		if err != nil {
+			log.WithError(err).Error("Failed to list incident watchers from repository")
+			return fmt.Errorf("service: could not list incident watchers: %w", err)
+		}
+		index := slices.IndexFunc(watchers, func(w *models.IncidentWatcher) bool { return w.ID == watcherID })
+		if index < 0 {
+			return fmt.Errorf("service: watcher %s of incident %s: %w", watcherID, incidentID, ErrWatcherNotFound)
+		}
+		if createdBy := watchers[index].CreatedBy; createdBy == "" || createdBy != requestedBy {
+			log.WithField("created_by", createdBy).Warn("Attempted to remove a watcher added by another API key")
+			return ErrWatcherForbidden
+		}
+	}
+
+	if err := s.repo.DeleteIncidentWatcher(ctx, incidentID, watcherID); err != nil {
+		log.WithError(err).Warn("Failed to delete incident watcher")
+		return fmt.Errorf("service: could not delete incident watcher: %w", err)
+	}
+	log.Info("Incident watcher removed")
+	return nil
+}
+
+// checkWatchURL разрешает только http(s) и, если задан WATCH_URL_ALLOWED_HOSTS, только хосты из него.
+// Хост должен разрешаться только в публичные адреса; при доставке адрес проверяется еще раз при соединении.
+func (s *incidentService) checkWatchURL(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("service: %q must be an absolute http(s) URL: %w", rawURL, ErrWatchURLNotAllowed)
+	}
+	if len(s.cfg.WatchURLAllowedHosts) > 0 && !slices.Contains(s.cfg.WatchURLAllowedHosts, strings.ToLower(u.Hostname())) {
+		return fmt.Errorf("service: host %q is not in WATCH_URL_ALLOWED_HOSTS: %w", u.Hostname(), ErrWatchURLNotAllowed)
+	}
+	if err := netguard.CheckHost(ctx, s.resolver, u.Hostname()); err != nil {
+		return fmt.Errorf("service: %v: %w", err, ErrWatchURLNotAllowed)
+	}
+	return nil
+}
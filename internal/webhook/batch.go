@@ -0,0 +1,211 @@
+package webhook
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shenikar/geo_broadcasting_system/internal/models"
+)
+
+// pendingEvent - событие, прочитанное из очереди и ожидающее отправки в составе пакета
+type pendingEvent struct {
+	event   WebhookEvent
+	payload string
+	// onDone вызывается после завершения всех доставок пакета с контекстом доставки (подтверждение сообщения потока)
+	onDone func(ctx context.Context)
+}
+
+// eventBatcher накапливает события и передает их в flush пакетом, когда набрано size событий
+// или прошло window с момента первого события пакета
+type eventBatcher struct {
+	size   int
+	window time.Duration
+	flush  func([]pendingEvent)
+
+	mu     sync.Mutex
+	events []pendingEvent
+	timer  *time.Timer
+}
+
+// newEventBatcher создает накопитель пакетов событий
+func newEventBatcher(size int, window time.Duration, flush func([]pendingEvent)) *eventBatcher {
+	return &eventBatcher{size: size, window: window, flush: flush}
+}
+
+// add добавляет событие в пакет. Заполненный пакет отправляется синхронно, поэтому
+// переполненные адреса, как и без пакетов, приостанавливают чтение очереди.
+func (b *eventBatcher) add(event pendingEvent) {
+	b.mu.Lock()
+	b.events = append(b.events, event)
+	if len(b.events) >= b.size {
+		batch := b.take()
+		b.mu.Unlock()
+		b.flush(batch)
+		return
+	}
+	if len(b.events) == 1 {
+		b.timer = time.AfterFunc(b.window, b.flushPending)
+	}
+	b.mu.Unlock()
+}
+
+// flushPending отправляет неполный пакет по истечении окна
+func (b *eventBatcher) flushPending() {
+	b.mu.Lock()
+	batch := b.take()
+	b.mu.Unlock()
+	if len(batch) > 0 {
+		b.flush(batch)
+	}
+}
+
+// drain забирает накопленный пакет, не дожидаясь окна; используется при остановке воркера
+func (b *eventBatcher) drain() []pendingEvent {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.take()
+}
+
+// take забирает накопленный пакет и останавливает таймер окна; вызывается под mu
+func (b *eventBatcher) take() []pendingEvent {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	batch := b.events
+	b.events = nil
+	return batch
+}
+
+// submit передает прочитанное из очереди событие на доставку: сразу или в составе пакета (WEBHOOK_BATCH_SIZE > 1)
+func (w *WebhookWorker) submit(ctx context.Context, event WebhookEvent, payload string, onDone func(ctx context.Context)) {
+	if w.batcher == nil {
+		w.processWebhookEvent(ctx, event, payload, onDone)
+		return
+	}
+	w.batcher.add(pendingEvent{event: event, payload: payload, onDone: onDone})
+}
+
+// processBatch доставляет пакет событий. Каждая подписка получает один запрос с JSON-массивом
+// подходящих ей событий; подпись HMAC считается по всему массиву, X-Webhook-Event-Id
+// перечисляет идентификаторы событий массива через запятую. Пакет из одного события
+// отправляется как обычное событие.
+func (w *WebhookWorker) processBatch(ctx context.Context, batch []pendingEvent) {
+	onDone := func(ctx context.Context) {
+		for _, pending := range batch {
+			if pending.onDone != nil {
+				pending.onDone(ctx)
+			}
+		}
+	}
+	if len(batch) == 1 {
+		w.processWebhookEvent(ctx, batch[0].event, batch[0].payload, onDone)
+		return
+	}
+
+	log := w.logger.WithField("batch_size", len(batch))
+	log.Debug("Processing webhook event batch...")
+
+	var wg sync.WaitGroup
+	defer func() {
+		go func() {
+			wg.Wait()
+			onDone(ctx)
+		}()
+	}()
+
+	bodies := make([]string, 0, len(batch))
+	events := make([]WebhookEvent, 0, len(batch))
+	for _, pending := range batch {
+		w.notify(ctx, pending.event, log, &wg)
+
+		body, err := renderPayload(w.payloadTemplate, pending.event, pending.payload)
+		if err != nil {
+			log.WithError(err).WithField("event_user_id", pending.event.UserID).Error("Failed to render webhook payload. Skipping event.")
+			continue
+		}
+		bodies = append(bodies, body)
+		events = append(events, pending.event)
+	}
+
+	w.subscriptionsMu.RLock()
+	subscriptions := w.subscriptions
+	w.subscriptionsMu.RUnlock()
+	watchers := w.watcherSubscriptions(ctx, events, log)
+
+	if len(subscriptions) == 0 && len(watchers) == 0 {
+		log.Warn("Webhook URL is not configured. Skipping webhook delivery.")
+		return
+	}
+
+	w.dispatch(ctx, batchDeliveries(subscriptions, events, bodies), log, &wg)
+	// Наблюдатели доставляются отдельно, как и в processWebhookEvent
+	w.dispatch(ctx, batchDeliveries(watchers, events, bodies), log, &wg)
+}
+
+// flushBatch при остановке воркера доставляет накопленный неполный пакет и дожидается окончания доставок,
+// чтобы события, уже извлеченные из очереди, не терялись. ctx воркера к этому моменту отменен,
+// поэтому пакет доставляется с отдельным контекстом, ограниченным SHUTDOWN_TIMEOUT.
+func (w *WebhookWorker) flushBatch(ctx context.Context) {
+	if w.batcher == nil {
+		return
+	}
+	batch := w.batcher.drain()
+	if len(batch) == 0 {
+		return
+	}
+
+	flushCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), w.cfg.ShutdownTimeout)
+	defer cancel()
+	done := make(chan struct{})
+	last := batch[len(batch)-1].onDone
+	batch[len(batch)-1].onDone = func(ctx context.Context) {
+		if last != nil {
+			last(ctx)
+		}
+		close(done)
+	}
+
+	log := w.logger.WithField("batch_size", len(batch))
+	log.Info("Flushing pending webhook batch before stopping")
+	w.processBatch(flushCtx, batch)
+	select {
+	case <-done:
+	case <-flushCtx.Done():
+		log.Warn("Shutdown timeout reached with webhook batch still being delivered")
+	}
+}
+
+// batchDeliveries возвращает для каждой подписки доставку JSON-массива подходящих ей событий пакета
+func batchDeliveries(subscriptions []Subscription, events []WebhookEvent, bodies []string) []delivery {
+	var deliveries []delivery
+	for _, sub := range subscriptions {
+		var matched, ids []string
+		// Заголовки пакета описывают самое опасное из вошедших в него событий
+		var severity models.IncidentSeverity
+		dangerous := false
+		for i, event := range events {
+			if sub.Matches(event) {
+				matched = append(matched, bodies[i])
+				if event.EventID != "" {
+					ids = append(ids, event.EventID)
+				}
+				severity = higherSeverity(severity, event.MaxSeverity())
+				dangerous = dangerous || event.IsDangerous
+			}
+		}
+		if len(matched) == 0 {
+			continue
+		}
+		deliveries = append(deliveries, delivery{
+			sub:       sub,
+			body:      "[" + strings.Join(matched, ",") + "]",
+			eventIDs:  strings.Join(ids, ","),
+			severity:  severity,
+			dangerous: dangerous,
+		})
+	}
+	return deliveries
+}
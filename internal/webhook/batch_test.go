@@ -0,0 +1,158 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shenikar/geo_broadcasting_system/internal/config"
+	"github.com/shenikar/geo_broadcasting_system/internal/models"
+	"github.com/shenikar/geo_broadcasting_system/pkg/geo"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventBatcher(t *testing.T) {
+	flushed := make(chan []pendingEvent, 4)
+	batcher := newEventBatcher(3, 50*time.Millisecond, func(batch []pendingEvent) { flushed <- batch })
+
+	// Пакет отправляется сразу, как только набран размер
+	for i := 0; i < 3; i++ {
+		batcher.add(pendingEvent{payload: `{}`})
+	}
+	select {
+	case batch := <-flushed:
+		assert.Len(t, batch, 3)
+	default:
+		t.Fatal("full batch was not flushed synchronously")
+	}
+
+	// Неполный пакет отправляется по истечении окна
+	batcher.add(pendingEvent{payload: `{}`})
+	select {
+	case batch := <-flushed:
+		assert.Len(t, batch, 1)
+	case <-time.After(time.Second):
+		t.Fatal("partial batch was not flushed after the window")
+	}
+}
+
+func TestProcessBatch_SendsArrayPerSubscription(t *testing.T) {
+	var mu sync.Mutex
+	bodies := make(map[string][]byte)
+	signatures := make(map[string]string)
+	versions := make(map[string]string)
+	eventIDs := make(map[string]string)
+	severities := make(map[string]string)
+	dangerous := make(map[string]string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies[r.URL.Path] = body
+		signatures[r.URL.Path] = r.Header.Get("X-Webhook-Signature")
+		versions[r.URL.Path] = r.Header.Get("X-Webhook-Schema-Version")
+		eventIDs[r.URL.Path] = r.Header.Get("X-Webhook-Event-Id")
+		severities[r.URL.Path] = r.Header.Get("X-Incident-Severity")
+		dangerous[r.URL.Path] = r.Header.Get("X-Event-Dangerous")
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	logger := logrus.New()
+	logger.SetOutput(&bytes.Buffer{})
+	worker := &WebhookWorker{
+		logger:     logger,
+		breaker:    newCircuitBreaker(0, 0, nil),
+		cfg:        &config.Config{WebhookMaxRetries: 1, WebhookSecret: "secret"},
+		httpClient: server.Client(),
+		subscriptions: []Subscription{
+			{URL: server.URL + "/all"},
+			{URL: server.URL + "/spb", Region: &geo.Region{BBox: &geo.BBox{MinLat: 59.8, MinLon: 30.1, MaxLat: 60.1, MaxLon: 30.6}}},
+		},
+		limiter: newDeliveryLimiter(1),
+	}
+
+	done := make(chan struct{})
+	var acked int
+	ack := func(context.Context) {
+		mu.Lock()
+		acked++
+		if acked == 2 {
+			close(done)
+		}
+		mu.Unlock()
+	}
+	worker.processBatch(context.Background(), []pendingEvent{
+		{event: WebhookEvent{EventID: "e1", UserID: "spb", Latitude: 59.93, Longitude: 30.31,
+			Incidents: []*models.Incident{{Severity: models.SeverityMinor}}}, payload: `{"user_id":"spb"}`, onDone: ack},
+		{event: WebhookEvent{EventID: "e2", UserID: "msk", Latitude: 55.75, Longitude: 37.61, IsDangerous: true,
+			Incidents: []*models.Incident{{Severity: models.SeverityCritical}}}, payload: `{"user_id":"msk"}`, onDone: ack},
+	})
+	<-done
+
+	mu.Lock()
+	defer mu.Unlock()
+	var all []map[string]string
+	require.NoError(t, json.Unmarshal(bodies["/all"], &all))
+	assert.Len(t, all, 2)
+	assert.JSONEq(t, `[{"user_id":"spb"}]`, string(bodies["/spb"]))
+	// Подпись покрывает весь массив
+	assert.Equal(t, generateHMACSHA256(string(bodies["/all"]), "secret"), signatures["/all"])
+	assert.Equal(t, SchemaVersion, versions["/all"])
+	assert.Equal(t, "e1,e2", eventIDs["/all"])
+	assert.Equal(t, "e1", eventIDs["/spb"])
+	// Заголовки маршрутизации описывают самое опасное событие пакета
+	assert.Equal(t, "critical", severities["/all"])
+	assert.Equal(t, "true", dangerous["/all"])
+	assert.Equal(t, "minor", severities["/spb"])
+	assert.Equal(t, "false", dangerous["/spb"])
+}
+
+func TestFlushBatch_DeliversPendingBatchOnShutdown(t *testing.T) {
+	var received atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	logger := logrus.New()
+	logger.SetOutput(&bytes.Buffer{})
+	worker := &WebhookWorker{
+		logger:        logger,
+		breaker:       newCircuitBreaker(0, 0, nil),
+		cfg:           &config.Config{WebhookMaxRetries: 1, ShutdownTimeout: 5 * time.Second},
+		httpClient:    server.Client(),
+		subscriptions: []Subscription{{URL: server.URL}},
+		limiter:       newDeliveryLimiter(1),
+	}
+	worker.batcher = newEventBatcher(10, time.Hour, func(batch []pendingEvent) {
+		t.Error("batch must not be flushed before shutdown")
+	})
+
+	var ackErr error
+	acked := false
+	worker.batcher.add(pendingEvent{payload: `{}`, onDone: func(ctx context.Context) {
+		acked = true
+		ackErr = ctx.Err()
+	}})
+
+	// Контекст воркера уже отменен, но накопленный пакет все равно доставляется и подтверждается
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	worker.flushBatch(ctx)
+
+	assert.Equal(t, int32(1), received.Load())
+	assert.True(t, acked)
+	assert.NoError(t, ackErr)
+	assert.Empty(t, worker.batcher.drain())
+}
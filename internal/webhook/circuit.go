@@ -0,0 +1,174 @@
+package webhook
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Состояния цепи адреса доставки
+const (
+	// CircuitClosed - доставки выполняются как обычно
+	CircuitClosed = "closed"
+	// CircuitOpen - адрес считается недоступным, доставки сразу уходят в очередь недоставленных
+	CircuitOpen = "open"
+	// CircuitHalfOpen - время ожидания истекло, выполняется одна пробная доставка
+	CircuitHalfOpen = "half_open"
+)
+
+// circuitStateGauge - состояние цепи по адресам: 1 - half_open, 2 - open. Адрес передается меткой destination
+// (см. DestinationLabel), а не самим URL: в адресе могут быть токены, а адреса наблюдателей задают клиенты API.
+// Серия удаляется при замыкании цепи, поэтому их число ограничено адресами с неисправной доставкой.
+var circuitStateGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "geo_webhook_circuit_state",
+	Help: "Webhook destination circuit state: 1 - half-open, 2 - open; closed circuits have no series.",
+}, []string{"destination"})
+
+// circuitStateValues - значения circuitStateGauge по состояниям
+var circuitStateValues = map[string]float64{CircuitClosed: 0, CircuitHalfOpen: 1, CircuitOpen: 2}
+
+// DestinationLabel возвращает короткий хеш адреса доставки для меток метрик. Тот же хеш показывается
+// в GET /admin/webhooks/circuits, чтобы сопоставить серию с адресом.
+func DestinationLabel(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return hex.EncodeToString(sum[:8])
+}
+
+// CircuitStatus - снимок состояния цепи адреса доставки
+type CircuitStatus struct {
+	URL   string `json:"url"`
+	State string `json:"state"`
+	// ConsecutiveFailures - число неудачных попыток подряд
+	ConsecutiveFailures int `json:"consecutive_failures"`
+	// OpenedAt - время последнего размыкания цепи
+	OpenedAt *time.Time `json:"opened_at,omitempty"`
+}
+
+// circuitBreaker размыкает цепь адреса после threshold неудачных попыток подряд. Через cooldown
+// цепь полуразомкнута: пропускается одна пробная доставка, успех замыкает цепь, неудача снова размыкает.
+type circuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	circuits  map[string]*CircuitStatus
+	// onChange вызывается при смене состояния цепи (вне блокировки)
+	onChange func(status CircuitStatus)
+	// now возвращает текущее время; подменяется в тестах
+	now func() time.Time
+	// probing - адреса, по которым выполняется пробная доставка
+	probing map[string]bool
+}
+
+// newCircuitBreaker создает размыкатель; threshold меньше 1 отключает размыкание
+func newCircuitBreaker(threshold int, cooldown time.Duration, onChange func(status CircuitStatus)) *circuitBreaker {
+	return &circuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		circuits:  make(map[string]*CircuitStatus),
+		onChange:  onChange,
+		now:       time.Now,
+		probing:   make(map[string]bool),
+	}
+}
+
+// circuit возвращает цепь адреса, создавая замкнутую при первом обращении. Вызывается под mu.
+func (b *circuitBreaker) circuit(url string) *CircuitStatus {
+	c, ok := b.circuits[url]
+	if !ok {
+		c = &CircuitStatus{URL: url, State: CircuitClosed}
+		b.circuits[url] = c
+	}
+	return c
+}
+
+// allow сообщает, можно ли выполнить попытку доставки на адрес. По истечении cooldown
+// разомкнутая цепь становится полуразомкнутой и пропускает ровно одну пробную попытку.
+func (b *circuitBreaker) allow(url string) bool {
+	if b.threshold < 1 {
+		return true
+	}
+	b.mu.Lock()
+	c := b.circuit(url)
+	var changed *CircuitStatus
+	if c.State == CircuitOpen && c.OpenedAt != nil && b.now().Sub(*c.OpenedAt) >= b.cooldown {
+		c.State = CircuitHalfOpen
+		snapshot := *c
+		changed = &snapshot
+	}
+	allowed := true
+	switch c.State {
+	case CircuitOpen:
+		allowed = false
+	case CircuitHalfOpen:
+		allowed = !b.probing[url]
+		b.probing[url] = true
+	}
+	b.mu.Unlock()
+
+	b.notify(changed)
+	return allowed
+}
+
+// success замыкает цепь адреса после успешной доставки
+func (b *circuitBreaker) success(url string) {
+	if b.threshold < 1 {
+		return
+	}
+	b.mu.Lock()
+	c := b.circuit(url)
+	var changed *CircuitStatus
+	if c.State != CircuitClosed {
+		c.State = CircuitClosed
+		c.OpenedAt = nil
+		snapshot := *c
+		changed = &snapshot
+	}
+	c.ConsecutiveFailures = 0
+	delete(b.probing, url)
+	b.mu.Unlock()
+
+	b.notify(changed)
+}
+
+// failure учитывает неудачную попытку и сообщает, разомкнута ли после нее цепь
+func (b *circuitBreaker) failure(url string) bool {
+	if b.threshold < 1 {
+		return false
+	}
+	b.mu.Lock()
+	c := b.circuit(url)
+	c.ConsecutiveFailures++
+	var changed *CircuitStatus
+	if c.State == CircuitHalfOpen || (c.State == CircuitClosed && c.ConsecutiveFailures >= b.threshold) {
+		openedAt := b.now()
+		c.State = CircuitOpen
+		c.OpenedAt = &openedAt
+		snapshot := *c
+		changed = &snapshot
+	}
+	delete(b.probing, url)
+	open := c.State == CircuitOpen
+	b.mu.Unlock()
+
+	b.notify(changed)
+	return open
+}
+
+// notify публикует метрику и передает новое состояние в onChange
+func (b *circuitBreaker) notify(status *CircuitStatus) {
+	if status == nil {
+		return
+	}
+	if status.State == CircuitClosed {
+		circuitStateGauge.DeleteLabelValues(DestinationLabel(status.URL))
+	} else {
+		circuitStateGauge.WithLabelValues(DestinationLabel(status.URL)).Set(circuitStateValues[status.State])
+	}
+	if b.onChange != nil {
+		b.onChange(*status)
+	}
+}
@@ -0,0 +1,100 @@
+package webhook
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestCircuitBreaker создает размыкатель с управляемым временем и журналом смен состояния
+func newTestCircuitBreaker(threshold int, cooldown time.Duration) (*circuitBreaker, *time.Time, *[]string) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	var changes []string
+	b := newCircuitBreaker(threshold, cooldown, func(status CircuitStatus) {
+		changes = append(changes, status.State)
+	})
+	b.now = func() time.Time { return now }
+	return b, &now, &changes
+}
+
+func TestCircuitBreaker_OpensAfterThreshold(t *testing.T) {
+	b, _, changes := newTestCircuitBreaker(3, time.Minute)
+
+	assert.False(t, b.failure("http://a"))
+	assert.False(t, b.failure("http://a"))
+	assert.True(t, b.failure("http://a"))
+
+	assert.False(t, b.allow("http://a"))
+	assert.True(t, b.allow("http://b"), "other destinations are not affected")
+	assert.Equal(t, []string{CircuitOpen}, *changes)
+}
+
+func TestCircuitBreaker_SuccessResetsFailures(t *testing.T) {
+	b, _, changes := newTestCircuitBreaker(2, time.Minute)
+
+	assert.False(t, b.failure("http://a"))
+	b.success("http://a")
+	assert.False(t, b.failure("http://a"))
+
+	assert.True(t, b.allow("http://a"))
+	assert.Empty(t, *changes)
+}
+
+func TestCircuitBreaker_HalfOpenAllowsSingleProbe(t *testing.T) {
+	b, now, changes := newTestCircuitBreaker(1, time.Minute)
+	assert.True(t, b.failure("http://a"))
+
+	*now = now.Add(30 * time.Second)
+	assert.False(t, b.allow("http://a"))
+
+	*now = now.Add(30 * time.Second)
+	assert.True(t, b.allow("http://a"))
+	assert.False(t, b.allow("http://a"), "only one probe while half-open")
+
+	b.success("http://a")
+	assert.True(t, b.allow("http://a"))
+	assert.Equal(t, []string{CircuitOpen, CircuitHalfOpen, CircuitClosed}, *changes)
+}
+
+func TestCircuitBreaker_FailedProbeReopens(t *testing.T) {
+	b, now, changes := newTestCircuitBreaker(3, time.Minute)
+	for i := 0; i < 3; i++ {
+		b.failure("http://a")
+	}
+
+	*now = now.Add(time.Minute)
+	assert.True(t, b.allow("http://a"))
+	assert.True(t, b.failure("http://a"))
+
+	assert.False(t, b.allow("http://a"))
+	assert.Equal(t, []string{CircuitOpen, CircuitHalfOpen, CircuitOpen}, *changes)
+}
+
+func TestCircuitBreaker_DisabledWithZeroThreshold(t *testing.T) {
+	b, _, changes := newTestCircuitBreaker(0, time.Minute)
+
+	for i := 0; i < 10; i++ {
+		assert.False(t, b.failure("http://a"))
+	}
+
+	assert.True(t, b.allow("http://a"))
+	assert.Empty(t, *changes)
+}
+
+func TestCircuitBreaker_MetricUsesDestinationLabel(t *testing.T) {
+	b, now, _ := newTestCircuitBreaker(1, time.Minute)
+	url := "https://hooks.example.org/alerts?token=secret"
+
+	b.failure(url)
+	assert.Equal(t, 2.0, testutil.ToFloat64(circuitStateGauge.WithLabelValues(DestinationLabel(url))))
+
+	// Замкнутая цепь не держит серию метрики
+	*now = now.Add(time.Minute)
+	b.allow(url)
+	b.success(url)
+	assert.False(t, circuitStateGauge.DeleteLabelValues(DestinationLabel(url)))
+	assert.Len(t, DestinationLabel(url), 16)
+	assert.NotContains(t, DestinationLabel(url), "secret")
+}
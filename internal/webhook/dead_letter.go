@@ -0,0 +1,62 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DeadLetter - вебхук, который не был доставлен подписчику
+type DeadLetter struct {
+	URL string `json:"url"`
+	// EventIDs - идентификаторы событий в теле (через запятую для пакета)
+	EventIDs string    `json:"event_ids,omitempty"`
+	Body     string    `json:"body"`
+	Reason   string    `json:"reason"`
+	FailedAt time.Time `json:"failed_at"`
+}
+
+// deadLetter сохраняет недоставленный вебхук в список webhook_dead_letter, оставляя не более
+// WEBHOOK_DEAD_LETTER_MAX_LEN последних записей. Ошибка сохранения только логируется.
+func (w *WebhookWorker) deadLetter(ctx context.Context, d delivery, reason string, log *logrus.Entry) {
+	entry, err := json.Marshal(DeadLetter{
+		URL:      d.sub.URL,
+		EventIDs: d.eventIDs,
+		Body:     d.body,
+		Reason:   reason,
+		FailedAt: time.Now(),
+	})
+	if err != nil {
+		log.WithError(err).Error("Failed to marshal dead letter webhook")
+		return
+	}
+
+	key := w.keys.Key(webhookDeadLetterKey)
+	pipe := w.redisClient.TxPipeline()
+	pipe.LPush(ctx, key, entry)
+	pipe.LTrim(ctx, key, 0, int64(w.cfg.WebhookDeadLetterMaxLen-1))
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.WithError(err).Error("Failed to save webhook to dead letter queue")
+		return
+	}
+	log.WithField("reason", reason).Warn("Webhook moved to dead letter queue")
+}
+
+// saveCircuit сохраняет новое состояние цепи в хеш webhook_circuits, откуда его читает административный API
+func (w *WebhookWorker) saveCircuit(status CircuitStatus) {
+	log := w.logger.WithFields(logrus.Fields{"url": status.URL, "circuit_state": status.State})
+	log.Warn("Webhook destination circuit state changed")
+
+	value, err := json.Marshal(status)
+	if err != nil {
+		log.WithError(err).Error("Failed to marshal webhook circuit state")
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), w.cfg.WebhookTimeout)
+	defer cancel()
+	if err := w.redisClient.HSet(ctx, w.keys.Key(webhookCircuitsKey), status.URL, value).Err(); err != nil {
+		log.WithError(err).Error("Failed to save webhook circuit state")
+	}
+}
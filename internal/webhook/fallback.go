@@ -0,0 +1,135 @@
+package webhook
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// fallbackFlushInterval - как часто FallbackPublisher пытается перенести накопленные события в очередь Redis
+const fallbackFlushInterval = time.Second
+
+// Availability сообщает, доступен ли Redis (см. monitoring.RedisHealth)
+type Availability interface {
+	Available() bool
+}
+
+// FallbackPublisher - WebhookPublisher для режима деградации (REDIS_REQUIRED=false): пока Redis недоступен,
+// события копятся в ограниченной очереди в памяти процесса и после восстановления Redis переносятся в его очередь
+// в порядке публикации. При переполнении отбрасываются самые старые события; накопленное теряется при остановке процесса.
+type FallbackPublisher struct {
+	next      WebhookPublisher
+	redis     Availability
+	logger    *logrus.Logger
+	maxEvents int
+
+	mu     sync.Mutex
+	buffer []WebhookEvent
+}
+
+// NewFallbackPublisher создает FallbackPublisher поверх издателя Redis; maxEvents - емкость очереди в памяти
+func NewFallbackPublisher(next WebhookPublisher, redis Availability, logger *logrus.Logger, maxEvents int) *FallbackPublisher {
+	return &FallbackPublisher{next: next, redis: redis, logger: logger, maxEvents: maxEvents}
+}
+
+// Start запускает горутину переноса накопленных событий в Redis
+func (p *FallbackPublisher) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(fallbackFlushInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				if n := p.Buffered(); n > 0 {
+					p.logger.WithField("events", n).Warn("Stopping with undelivered webhook events buffered in memory")
+				}
+				return
+			case <-ticker.C:
+				p.flush(ctx)
+			}
+		}
+	}()
+}
+
+// Publish публикует событие в Redis, а если Redis недоступен или уже накоплены события - в очередь в памяти,
+// чтобы порядок событий сохранялся
+func (p *FallbackPublisher) Publish(ctx context.Context, event WebhookEvent) error {
+	if p.redis.Available() && p.Buffered() == 0 {
+		err := p.next.Publish(ctx, event)
+		if err == nil || p.redis.Available() {
+			return err
+		}
+	}
+	p.enqueue(event)
+	return nil
+}
+
+// enqueue добавляет событие в очередь в памяти, отбрасывая самое старое при переполнении
+func (p *FallbackPublisher) enqueue(event WebhookEvent) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.buffer) >= p.maxEvents {
+		dropped := p.buffer[0]
+		p.buffer = p.buffer[1:]
+		p.logger.WithFields(logrus.Fields{
+			"event_type": dropped.Type,
+			"capacity":   p.maxEvents,
+		}).Error("Webhook fallback queue is full, dropping the oldest event")
+	}
+	p.buffer = append(p.buffer, event)
+}
+
+// flush переносит накопленные события в Redis, пока он доступен; событие, которое не удалось опубликовать,
+// остается в начале очереди
+func (p *FallbackPublisher) flush(ctx context.Context) {
+	flushed := 0
+	for p.redis.Available() {
+		p.mu.Lock()
+		if len(p.buffer) == 0 {
+			p.mu.Unlock()
+			break
+		}
+		event := p.buffer[0]
+		p.mu.Unlock()
+
+		if err := p.next.Publish(ctx, event); err != nil {
+			p.logger.WithError(err).Warn("Failed to move buffered webhook event to Redis")
+			break
+		}
+		p.mu.Lock()
+		p.buffer = p.buffer[1:]
+		p.mu.Unlock()
+		flushed++
+	}
+	if flushed > 0 {
+		p.logger.WithField("events", flushed).Info("Buffered webhook events moved to Redis")
+	}
+}
+
+// Buffered возвращает число событий в очереди в памяти
+func (p *FallbackPublisher) Buffered() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.buffer)
+}
+
+// QueueDepth возвращает глубину очереди Redis вместе с событиями, накопленными в памяти
+func (p *FallbackPublisher) QueueDepth(ctx context.Context) (int64, error) {
+	depth, err := p.next.QueueDepth(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return depth + int64(p.Buffered()), nil
+}
+
+// CircuitStates возвращает состояния цепей из Redis
+func (p *FallbackPublisher) CircuitStates(ctx context.Context) ([]CircuitStatus, error) {
+	return p.next.CircuitStates(ctx)
+}
+
+// DeadLetterDepth возвращает длину списка недоставленных вебхуков из Redis
+func (p *FallbackPublisher) DeadLetterDepth(ctx context.Context) (int64, error) {
+	return p.next.DeadLetterDepth(ctx)
+}
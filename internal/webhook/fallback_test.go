@@ -0,0 +1,97 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAvailability - переключаемая доступность Redis
+type fakeAvailability struct{ available atomic.Bool }
+
+func (a *fakeAvailability) Available() bool { return a.available.Load() }
+
+// recordingPublisher запоминает опубликованные события и может отказывать
+type recordingPublisher struct {
+	events []WebhookEvent
+	fail   bool
+}
+
+func (p *recordingPublisher) Publish(_ context.Context, event WebhookEvent) error {
+	if p.fail {
+		return errors.New("connection refused")
+	}
+	p.events = append(p.events, event)
+	return nil
+}
+
+func (p *recordingPublisher) QueueDepth(context.Context) (int64, error) {
+	return int64(len(p.events)), nil
+}
+
+func (p *recordingPublisher) CircuitStates(context.Context) ([]CircuitStatus, error) { return nil, nil }
+
+func (p *recordingPublisher) DeadLetterDepth(context.Context) (int64, error) { return 0, nil }
+
+func TestFallbackPublisher_BuffersWhileRedisUnavailable(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+	redis := &fakeAvailability{}
+	next := &recordingPublisher{}
+	publisher := NewFallbackPublisher(next, redis, logger, 10)
+	ctx := context.Background()
+
+	require.NoError(t, publisher.Publish(ctx, WebhookEvent{Type: "first"}))
+	require.NoError(t, publisher.Publish(ctx, WebhookEvent{Type: "second"}))
+	assert.Empty(t, next.events)
+	depth, err := publisher.QueueDepth(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), depth)
+
+	// Пока накопленные события не перенесены, новые встают за ними, чтобы сохранить порядок
+	redis.available.Store(true)
+	require.NoError(t, publisher.Publish(ctx, WebhookEvent{Type: "third"}))
+	assert.Empty(t, next.events)
+
+	publisher.flush(ctx)
+	require.Len(t, next.events, 3)
+	assert.Equal(t, []string{"first", "second", "third"}, []string{next.events[0].Type, next.events[1].Type, next.events[2].Type})
+	assert.Zero(t, publisher.Buffered())
+
+	// После переноса события снова публикуются напрямую
+	require.NoError(t, publisher.Publish(ctx, WebhookEvent{Type: "fourth"}))
+	assert.Len(t, next.events, 4)
+}
+
+func TestFallbackPublisher_DropsOldestWhenFull(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	publisher := NewFallbackPublisher(&recordingPublisher{}, &fakeAvailability{}, logger, 2)
+	ctx := context.Background()
+
+	for _, eventType := range []string{"a", "b", "c"} {
+		require.NoError(t, publisher.Publish(ctx, WebhookEvent{Type: eventType}))
+	}
+
+	assert.Equal(t, 2, publisher.Buffered())
+	assert.Equal(t, "b", publisher.buffer[0].Type)
+	require.NotNil(t, hook.LastEntry())
+	assert.Equal(t, "a", hook.LastEntry().Data["event_type"])
+}
+
+func TestFallbackPublisher_KeepsEventOnFailedFlush(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+	redis := &fakeAvailability{}
+	next := &recordingPublisher{fail: true}
+	publisher := NewFallbackPublisher(next, redis, logger, 10)
+	ctx := context.Background()
+
+	require.NoError(t, publisher.Publish(ctx, WebhookEvent{Type: "first"}))
+	redis.available.Store(true)
+	publisher.flush(ctx)
+
+	assert.Equal(t, 1, publisher.Buffered())
+}
@@ -0,0 +1,53 @@
+package webhook
+
+import (
+	"context"
+	"sync"
+)
+
+// deliveryLimiter ограничивает число одновременных доставок на один адрес назначения.
+// Для каждого адреса создается отдельный семафор емкостью limit.
+type deliveryLimiter struct {
+	mu    sync.Mutex
+	limit int
+	slots map[string]chan struct{}
+}
+
+// newDeliveryLimiter создает ограничитель; limit меньше 1 трактуется как 1
+func newDeliveryLimiter(limit int) *deliveryLimiter {
+	if limit < 1 {
+		limit = 1
+	}
+	return &deliveryLimiter{
+		limit: limit,
+		slots: make(map[string]chan struct{}),
+	}
+}
+
+// semaphore возвращает семафор адреса, создавая его при первом обращении
+func (l *deliveryLimiter) semaphore(destination string) chan struct{} {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	sem, ok := l.slots[destination]
+	if !ok {
+		sem = make(chan struct{}, l.limit)
+		l.slots[destination] = sem
+	}
+	return sem
+}
+
+// acquire занимает слот доставки для адреса, ожидая освобождения, если все слоты заняты.
+// Возвращает ошибку контекста, если ожидание прервано.
+func (l *deliveryLimiter) acquire(ctx context.Context, destination string) error {
+	select {
+	case l.semaphore(destination) <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// release освобождает слот доставки, занятый acquire
+func (l *deliveryLimiter) release(destination string) {
+	<-l.semaphore(destination)
+}
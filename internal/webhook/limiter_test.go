@@ -0,0 +1,56 @@
+package webhook
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeliveryLimiter_LimitsConcurrencyPerDestination(t *testing.T) {
+	limiter := newDeliveryLimiter(2)
+	ctx := context.Background()
+
+	var current, peak int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		require.NoError(t, limiter.acquire(ctx, "http://a"))
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer limiter.release("http://a")
+			n := atomic.AddInt32(&current, 1)
+			for {
+				p := atomic.LoadInt32(&peak)
+				if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			atomic.AddInt32(&current, -1)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(2), peak)
+}
+
+func TestDeliveryLimiter_DestinationsAreIndependent(t *testing.T) {
+	limiter := newDeliveryLimiter(1)
+	ctx := context.Background()
+
+	require.NoError(t, limiter.acquire(ctx, "http://a"))
+	// Другой адрес не блокируется занятым слотом первого
+	require.NoError(t, limiter.acquire(ctx, "http://b"))
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, limiter.acquire(timeoutCtx, "http://a"), context.DeadlineExceeded)
+
+	limiter.release("http://a")
+	assert.NoError(t, limiter.acquire(ctx, "http://a"))
+}
@@ -41,6 +41,36 @@ func (m *MockWebhookPublisher) EXPECT() *MockWebhookPublisherMockRecorder {
 	return m.recorder
 }
 
+// CircuitStates mocks base method.
+func (m *MockWebhookPublisher) CircuitStates(ctx context.Context) ([]webhook.CircuitStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CircuitStates", ctx)
+	ret0, _ := ret[0].([]webhook.CircuitStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CircuitStates indicates an expected call of CircuitStates.
+func (mr *MockWebhookPublisherMockRecorder) CircuitStates(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CircuitStates", reflect.TypeOf((*MockWebhookPublisher)(nil).CircuitStates), ctx)
+}
+
+// DeadLetterDepth mocks base method.
+func (m *MockWebhookPublisher) DeadLetterDepth(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeadLetterDepth", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeadLetterDepth indicates an expected call of DeadLetterDepth.
+func (mr *MockWebhookPublisherMockRecorder) DeadLetterDepth(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeadLetterDepth", reflect.TypeOf((*MockWebhookPublisher)(nil).DeadLetterDepth), ctx)
+}
+
 // Publish mocks base method.
 func (m *MockWebhookPublisher) Publish(ctx context.Context, event webhook.WebhookEvent) error {
 	m.ctrl.T.Helper()
@@ -54,3 +84,18 @@ func (mr *MockWebhookPublisherMockRecorder) Publish(ctx, event any) *gomock.Call
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockWebhookPublisher)(nil).Publish), ctx, event)
 }
+
+// QueueDepth mocks base method.
+func (m *MockWebhookPublisher) QueueDepth(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueueDepth", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueueDepth indicates an expected call of QueueDepth.
+func (mr *MockWebhookPublisherMockRecorder) QueueDepth(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueDepth", reflect.TypeOf((*MockWebhookPublisher)(nil).QueueDepth), ctx)
+}
@@ -0,0 +1,58 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/shenikar/geo_broadcasting_system/internal/config"
+)
+
+// Провайдеры уведомлений (NOTIFIER)
+const (
+	// NotifierSMTP - уведомления по электронной почте через SMTP
+	NotifierSMTP = "smtp"
+	// NotifierTwilio - SMS-уведомления через Twilio
+	NotifierTwilio = "twilio"
+)
+
+// Notifier - канал уведомлений людей (email, SMS) об опасных событиях.
+// Вызывается воркером в дополнение к вебхукам для событий с IsDangerous.
+type Notifier interface {
+	Notify(ctx context.Context, event WebhookEvent) error
+}
+
+// newNotifier создает уведомитель, выбранный в конфигурации. Если NOTIFIER не задан, возвращает nil.
+func newNotifier(cfg *config.Config) (Notifier, error) {
+	switch cfg.Notifier {
+	case "":
+		return nil, nil
+	case NotifierSMTP:
+		return newSMTPNotifier(cfg), nil
+	case NotifierTwilio:
+		return newTwilioNotifier(cfg), nil
+	default:
+		return nil, fmt.Errorf("unknown notifier %q", cfg.Notifier)
+	}
+}
+
+// formatNotification формирует тему и человекочитаемый текст уведомления об опасном событии
+func formatNotification(event WebhookEvent) (subject, body string) {
+	subject = "Danger zone alert"
+
+	var b strings.Builder
+	if event.UserID != "" {
+		fmt.Fprintf(&b, "User %s is inside a danger zone", event.UserID)
+	} else {
+		b.WriteString("A location is inside a danger zone")
+	}
+	fmt.Fprintf(&b, " at %.5f, %.5f (%s).", event.Latitude, event.Longitude, event.Timestamp.UTC().Format("2006-01-02 15:04 MST"))
+
+	if len(event.Incidents) > 0 {
+		b.WriteString("\nIncidents:")
+		for _, incident := range event.Incidents {
+			fmt.Fprintf(&b, "\n- %s (radius %d m)", incident.Name, incident.RadiusMeters)
+		}
+	}
+	return subject, b.String()
+}
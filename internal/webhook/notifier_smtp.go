@@ -0,0 +1,123 @@
+package webhook
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/shenikar/geo_broadcasting_system/internal/config"
+)
+
+// SMTPNotifier отправляет уведомления по электронной почте
+type SMTPNotifier struct {
+	addr       string
+	auth       smtp.Auth
+	from       string
+	recipients []string
+	// timeout - предел времени на всю отправку письма, от соединения до QUIT (WEBHOOK_TIMEOUT)
+	timeout time.Duration
+}
+
+// newSMTPNotifier создает SMTPNotifier. Аутентификация PLAIN используется, если задан SMTP_USERNAME.
+func newSMTPNotifier(cfg *config.Config) *SMTPNotifier {
+	var auth smtp.Auth
+	if cfg.SMTPUsername != "" {
+		host, _, _ := net.SplitHostPort(cfg.SMTPAddr)
+		auth = smtp.PlainAuth("", cfg.SMTPUsername, cfg.SMTPPassword, host)
+	}
+	return &SMTPNotifier{
+		addr:       cfg.SMTPAddr,
+		auth:       auth,
+		from:       cfg.SMTPFrom,
+		recipients: cfg.NotifyRecipients,
+		timeout:    cfg.WebhookTimeout,
+	}
+}
+
+// Notify отправляет одно письмо всем получателям. Отправка ограничена timeout и прерывается отменой ctx,
+// поэтому зависший SMTP-сервер не задерживает доставку событий дольше таймаута.
+func (n *SMTPNotifier) Notify(ctx context.Context, event WebhookEvent) error {
+	subject, body := formatNotification(event)
+	msg := strings.Join([]string{
+		"From: " + n.from,
+		"To: " + strings.Join(n.recipients, ", "),
+		"Subject: " + subject,
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		body,
+	}, "\r\n")
+
+	if n.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, n.timeout)
+		defer cancel()
+	}
+	if err := n.send(ctx, []byte(msg)); err != nil {
+		return fmt.Errorf("failed to send notification email: %w", err)
+	}
+	return nil
+}
+
+// send повторяет smtp.SendMail, но соединяется с учетом ctx и выставляет соединению срок ctx,
+// чтобы чтение и запись не блокировались бесконечно. Отмена ctx прерывает начатую отправку.
+func (n *SMTPNotifier) send(ctx context.Context, msg []byte) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", n.addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return err
+		}
+	}
+	stop := context.AfterFunc(ctx, func() {
+		_ = conn.SetDeadline(time.Now())
+	})
+	defer stop()
+
+	host, _, _ := net.SplitHostPort(n.addr)
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if n.auth != nil {
+		if ok, _ := client.Extension("AUTH"); ok {
+			if err := client.Auth(n.auth); err != nil {
+				return err
+			}
+		}
+	}
+	if err := client.Mail(n.from); err != nil {
+		return err
+	}
+	for _, recipient := range n.recipients {
+		if err := client.Rcpt(recipient); err != nil {
+			return err
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
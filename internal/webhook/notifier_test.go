@@ -0,0 +1,212 @@
+package webhook
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shenikar/geo_broadcasting_system/internal/config"
+	"github.com/shenikar/geo_broadcasting_system/internal/models"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeNotifier запоминает события, переданные в Notify
+type fakeNotifier struct {
+	mu     sync.Mutex
+	events []WebhookEvent
+}
+
+func (n *fakeNotifier) Notify(ctx context.Context, event WebhookEvent) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.events = append(n.events, event)
+	return nil
+}
+
+func TestFormatNotification(t *testing.T) {
+	event := WebhookEvent{
+		UserID:      "user-1",
+		Latitude:    55.75,
+		Longitude:   37.61,
+		IsDangerous: true,
+		Timestamp:   time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC),
+		Incidents:   []*models.Incident{{Name: "Пожар", RadiusMeters: 500}},
+	}
+
+	subject, body := formatNotification(event)
+
+	assert.Equal(t, "Danger zone alert", subject)
+	assert.Equal(t, "User user-1 is inside a danger zone at 55.75000, 37.61000 (2024-05-01 12:30 UTC).\nIncidents:\n- Пожар (radius 500 m)", body)
+}
+
+func TestTwilioNotifier_Notify(t *testing.T) {
+	var requests []*http.Request
+	var recipients []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		requests = append(requests, r)
+		recipients = append(recipients, r.PostForm.Get("To"))
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	notifier := newTwilioNotifier(&config.Config{
+		TwilioAccountSID: "AC123",
+		TwilioAuthToken:  "token",
+		TwilioFrom:       "+15005550006",
+		NotifyRecipients: []string{"+79990000001", "+79990000002"},
+		WebhookTimeout:   time.Second,
+	})
+	notifier.baseURL = server.URL
+
+	err := notifier.Notify(context.Background(), WebhookEvent{UserID: "user-1", IsDangerous: true})
+
+	require.NoError(t, err)
+	require.Len(t, requests, 2)
+	assert.Equal(t, "/Accounts/AC123/Messages.json", requests[0].URL.Path)
+	user, pass, ok := requests[0].BasicAuth()
+	assert.True(t, ok)
+	assert.Equal(t, "AC123", user)
+	assert.Equal(t, "token", pass)
+	assert.Equal(t, []string{"+79990000001", "+79990000002"}, recipients)
+}
+
+func TestTwilioNotifier_Notify_Rejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	notifier := newTwilioNotifier(&config.Config{NotifyRecipients: []string{"+79990000001"}, WebhookTimeout: time.Second})
+	notifier.baseURL = server.URL
+
+	err := notifier.Notify(context.Background(), WebhookEvent{IsDangerous: true})
+
+	assert.ErrorContains(t, err, "status code 400")
+}
+
+// serveSMTP принимает одно соединение на listener и ведет минимальный SMTP-диалог, возвращая полученное письмо
+func serveSMTP(t *testing.T, listener net.Listener) <-chan string {
+	t.Helper()
+	received := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		fmt.Fprint(conn, "220 test ESMTP\r\n")
+		var data strings.Builder
+		inData := false
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			if inData {
+				if line == ".\r\n" {
+					inData = false
+					received <- data.String()
+					fmt.Fprint(conn, "250 OK\r\n")
+					continue
+				}
+				data.WriteString(line)
+				continue
+			}
+			switch command := strings.ToUpper(strings.TrimSpace(line)); {
+			case strings.HasPrefix(command, "EHLO"):
+				fmt.Fprint(conn, "250 test\r\n")
+			case command == "DATA":
+				inData = true
+				fmt.Fprint(conn, "354 Go ahead\r\n")
+			case command == "QUIT":
+				fmt.Fprint(conn, "221 Bye\r\n")
+				return
+			default:
+				fmt.Fprint(conn, "250 OK\r\n")
+			}
+		}
+	}()
+	return received
+}
+
+func TestSMTPNotifier_Notify(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	received := serveSMTP(t, listener)
+
+	notifier := newSMTPNotifier(&config.Config{
+		SMTPAddr:         listener.Addr().String(),
+		SMTPFrom:         "alerts@example.com",
+		NotifyRecipients: []string{"ops@example.com"},
+		WebhookTimeout:   time.Second,
+	})
+
+	err = notifier.Notify(context.Background(), WebhookEvent{UserID: "user-1", IsDangerous: true})
+
+	require.NoError(t, err)
+	assert.Contains(t, <-received, "Subject: Danger zone alert")
+}
+
+func TestSMTPNotifier_Notify_StalledServer(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	// Сервер принимает соединение, но не отправляет приветствие
+	go func() {
+		conn, err := listener.Accept()
+		if err == nil {
+			defer conn.Close()
+			_, _ = io.Copy(io.Discard, conn)
+		}
+	}()
+
+	notifier := newSMTPNotifier(&config.Config{
+		SMTPAddr:         listener.Addr().String(),
+		SMTPFrom:         "alerts@example.com",
+		NotifyRecipients: []string{"ops@example.com"},
+		WebhookTimeout:   5 * time.Second,
+	})
+	notifier.timeout = 100 * time.Millisecond
+
+	started := time.Now()
+	err = notifier.Notify(context.Background(), WebhookEvent{IsDangerous: true})
+
+	assert.Error(t, err)
+	assert.Less(t, time.Since(started), 2*time.Second)
+}
+
+func TestProcessWebhookEvent_NotifiesOnlyDangerousEvents(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(&bytes.Buffer{})
+	notifier := &fakeNotifier{}
+	worker := &WebhookWorker{
+		logger:   logger,
+		breaker:  newCircuitBreaker(0, 0, nil),
+		cfg:      &config.Config{},
+		notifier: notifier,
+		limiter:  newDeliveryLimiter(1),
+	}
+
+	for _, dangerous := range []bool{true, false} {
+		done := make(chan struct{})
+		worker.processWebhookEvent(context.Background(), WebhookEvent{UserID: "user-1", IsDangerous: dangerous}, `{}`, func(context.Context) { close(done) })
+		<-done
+	}
+
+	require.Len(t, notifier.events, 1)
+	assert.True(t, notifier.events[0].IsDangerous)
+}
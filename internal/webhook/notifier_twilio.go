@@ -0,0 +1,70 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/shenikar/geo_broadcasting_system/internal/config"
+)
+
+// twilioAPIURL - базовый адрес REST API Twilio
+const twilioAPIURL = "https://api.twilio.com/2010-04-01"
+
+// TwilioNotifier отправляет SMS-уведомления через Twilio
+type TwilioNotifier struct {
+	baseURL    string
+	accountSID string
+	authToken  string
+	from       string
+	recipients []string
+	httpClient *http.Client
+}
+
+// newTwilioNotifier создает TwilioNotifier
+func newTwilioNotifier(cfg *config.Config) *TwilioNotifier {
+	return &TwilioNotifier{
+		baseURL:    twilioAPIURL,
+		accountSID: cfg.TwilioAccountSID,
+		authToken:  cfg.TwilioAuthToken,
+		from:       cfg.TwilioFrom,
+		recipients: cfg.NotifyRecipients,
+		httpClient: &http.Client{Timeout: cfg.WebhookTimeout},
+	}
+}
+
+// Notify отправляет SMS каждому получателю. Ошибки по отдельным получателям объединяются.
+func (n *TwilioNotifier) Notify(ctx context.Context, event WebhookEvent) error {
+	_, body := formatNotification(event)
+	endpoint := fmt.Sprintf("%s/Accounts/%s/Messages.json", n.baseURL, n.accountSID)
+
+	var errs []error
+	for _, to := range n.recipients {
+		form := url.Values{}
+		form.Set("To", to)
+		form.Set("From", n.from)
+		form.Set("Body", body)
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to create twilio request for %s: %w", to, err))
+			continue
+		}
+		req.SetBasicAuth(n.accountSID, n.authToken)
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		resp, err := n.httpClient.Do(req)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to send sms to %s: %w", to, err))
+			continue
+		}
+		resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			errs = append(errs, fmt.Errorf("twilio rejected sms to %s with status code %d", to, resp.StatusCode))
+		}
+	}
+	return errors.Join(errs...)
+}
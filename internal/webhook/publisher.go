@@ -4,53 +4,257 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
+	"github.com/shenikar/geo_broadcasting_system/internal/config"
 	"github.com/shenikar/geo_broadcasting_system/internal/models"
+	redisclient "github.com/shenikar/geo_broadcasting_system/pkg/redis"
 )
 
 const (
 	webhookQueueKey = "webhook_events"
+	// webhookStreamKey - ключ потока Redis Streams для бэкенда streams
+	webhookStreamKey = "webhook_events_stream"
+	// streamPayloadField - поле сообщения потока, содержащее JSON события
+	streamPayloadField = "payload"
+	// webhookScheduledKey - отсортированное множество отложенных событий (оценка - Unix-время доставки)
+	webhookScheduledKey = "webhook_scheduled"
+	// webhookCircuitsKey - хеш состояний цепей адресов доставки (поле - адрес, значение - JSON CircuitStatus)
+	webhookCircuitsKey = "webhook_circuits"
+	// webhookDeadLetterKey - список недоставленных вебхуков (JSON DeadLetter, новые слева)
+	webhookDeadLetterKey = "webhook_dead_letter"
 )
 
+// Бэкенды очереди вебхуков (WEBHOOK_QUEUE_BACKEND)
+const (
+	// QueueBackendList - список Redis (LPUSH/BRPOP), без подтверждения доставки
+	QueueBackendList = "list"
+	// QueueBackendStreams - Redis Streams с группой потребителей и XACK (at-least-once)
+	QueueBackendStreams = "streams"
+)
+
+// Типы событий вебхуков
+const (
+	// EventTypeLocationCheck - пользователь оказался в опасной зоне при проверке местоположения
+	EventTypeLocationCheck = "location_check"
+	// EventTypeIncidentApproved - черновик инцидента одобрен и стал активным
+	EventTypeIncidentApproved = "incident_approved"
+	// EventTypeIncidentDeactivated - инцидент деактивирован
+	EventTypeIncidentDeactivated = "incident_deactivated"
+	// EventTypeIncidentEscalated - уровень опасности инцидента повышен
+	EventTypeIncidentEscalated = "incident_escalated"
+	// EventTypeIncidentUpdated - инцидент изменен; изменившиеся поля перечислены в changed_fields
+	EventTypeIncidentUpdated = "incident_updated"
+	// EventTypeLocationCheckReevaluated - повторная оценка показала, что при прошлой проверке местоположения
+	// пользователь был в опасной зоне, но не был предупрежден; timestamp - время исходной проверки
+	EventTypeLocationCheckReevaluated = "location_check_reevaluated"
+)
+
+// SchemaVersion - версия формата WebhookEvent, передается в поле schema_version и заголовке
+// X-Webhook-Schema-Version. Повышается при любом изменении полей события:
+// минорная часть - при добавлении полей, мажорная - при удалении или изменении смысла существующих.
+//
+// История версий:
+//   - 1.0 - type, user_id, latitude, longitude, is_dangerous, timestamp, incidents
+//   - 1.1 - event_id
+//   - 1.2 - changed_fields
+//   - 1.3 - severity у инцидентов в incidents
+//   - 1.4 - public у инцидентов в incidents
+//   - 1.5 - created_by у инцидентов в incidents больше не передается (метки API-ключей не раскрываются)
+const SchemaVersion = "1.5"
+
+// FieldChange - старое и новое значение изменившегося поля инцидента
+type FieldChange struct {
+	Old any `json:"old"`
+	New any `json:"new"`
+}
+
 // WebhookEvent - структура для данных вебхука
 type WebhookEvent struct {
+	// SchemaVersion - версия формата события (см. SchemaVersion); события без нее соответствуют 1.0
+	SchemaVersion string `json:"schema_version"`
+	// EventID - уникальный идентификатор события, присваивается при публикации и сохраняется
+	// при повторных доставках; получатель использует его для отбрасывания дубликатов
+	EventID     string             `json:"event_id"`
+	Type        string             `json:"type"`
 	UserID      string             `json:"user_id"`
 	Latitude    float64            `json:"latitude"`
 	Longitude   float64            `json:"longitude"`
 	IsDangerous bool               `json:"is_dangerous"`
 	Timestamp   time.Time          `json:"timestamp"`
 	Incidents   []*models.Incident `json:"incidents,omitempty"` // Список инцидентов, если пользователь в опасной зоне
+	// ChangedFields - изменившиеся поля инцидента по JSON-именам (только для incident_updated)
+	ChangedFields map[string]FieldChange `json:"changed_fields,omitempty"`
+}
+
+// MaxSeverity возвращает наибольший уровень опасности среди инцидентов события;
+// пусто, если у инцидентов события уровень не задан
+func (e WebhookEvent) MaxSeverity() models.IncidentSeverity {
+	var severity models.IncidentSeverity
+	for _, incident := range e.Incidents {
+		severity = higherSeverity(severity, incident.Severity)
+	}
+	return severity
+}
+
+// higherSeverity возвращает больший из уровней опасности; неизвестный или пустой уровень считается наименьшим
+func higherSeverity(a, b models.IncidentSeverity) models.IncidentSeverity {
+	if slices.Index(models.IncidentSeverities, b) > slices.Index(models.IncidentSeverities, a) {
+		return b
+	}
+	return a
 }
 
 // WebhookPublisher - интерфейс для публикации вебхуков
 type WebhookPublisher interface {
 	Publish(ctx context.Context, event WebhookEvent) error
+	// QueueDepth возвращает число событий, ожидающих доставки
+	QueueDepth(ctx context.Context) (int64, error)
+	// CircuitStates возвращает последние известные состояния цепей адресов доставки
+	CircuitStates(ctx context.Context) ([]CircuitStatus, error)
+	// DeadLetterDepth возвращает число вебхуков в очереди недоставленных
+	DeadLetterDepth(ctx context.Context) (int64, error)
 }
 
 // RedisWebhookPublisher - реализация WebhookPublisher, использующая Redis
 type RedisWebhookPublisher struct {
 	redisClient *redis.Client
+	keys        redisclient.KeyBuilder
+	backend     string
+	// quietHours - окно тишины для опасных событий (WEBHOOK_QUIET_HOURS)
+	quietHours config.QuietHours
+	// bypassCategories - категории инцидентов, не откладываемые окном тишины
+	bypassCategories []string
+	// now возвращает текущее время; подменяется в тестах
+	now func() time.Time
 }
 
-// NewRedisWebhookPublisher создает новый RedisWebhookPublisher
-func NewRedisWebhookPublisher(client *redis.Client) *RedisWebhookPublisher {
+// NewRedisWebhookPublisher создает новый RedisWebhookPublisher с бэкендом очереди и окном тишины из конфигурации
+func NewRedisWebhookPublisher(client *redis.Client, keys redisclient.KeyBuilder, cfg *config.Config) *RedisWebhookPublisher {
 	return &RedisWebhookPublisher{
-		redisClient: client,
+		redisClient:      client,
+		keys:             keys,
+		backend:          cfg.WebhookQueueBackend,
+		quietHours:       cfg.WebhookQuietHours,
+		bypassCategories: cfg.WebhookQuietHoursBypassCategories,
+		now:              time.Now,
 	}
 }
 
-// Publish публикует событие вебхука в очередь Redis
+// Publish публикует событие вебхука в очередь Redis, присваивая ему EventID, если он не задан.
+// Опасное событие, опубликованное в окно тишины, откладывается до его окончания: оно попадает
+// в отсортированное множество, откуда воркер переносит его в очередь (см. WebhookWorker.startScheduled).
 func (p *RedisWebhookPublisher) Publish(ctx context.Context, event WebhookEvent) error {
+	if event.SchemaVersion == "" {
+		event.SchemaVersion = SchemaVersion
+	}
+	if event.EventID == "" {
+		event.EventID = uuid.NewString()
+	}
 	payload, err := json.Marshal(event)
 	if err != nil {
 		return fmt.Errorf("failed to marshal webhook event: %w", err)
 	}
 
+	if deliverAt, ok := p.deferUntil(event); ok {
+		member := redis.Z{Score: float64(deliverAt.Unix()), Member: payload}
+		if err := p.redisClient.ZAdd(ctx, p.keys.Key(webhookScheduledKey), member).Err(); err != nil {
+			return fmt.Errorf("failed to schedule webhook event in Redis: %w", err)
+		}
+		return nil
+	}
+
+	if p.backend == QueueBackendStreams {
+		args := &redis.XAddArgs{
+			Stream: p.keys.Key(webhookStreamKey),
+			Values: map[string]any{streamPayloadField: payload},
+		}
+		if err := p.redisClient.XAdd(ctx, args).Err(); err != nil {
+			return fmt.Errorf("failed to publish webhook event to Redis stream: %w", err)
+		}
+		return nil
+	}
+
 	// Используем LPUSH для добавления события в левую часть списка (очереди)
-	if err := p.redisClient.LPush(ctx, webhookQueueKey, payload).Err(); err != nil {
+	if err := p.redisClient.LPush(ctx, p.keys.Key(webhookQueueKey), payload).Err(); err != nil {
 		return fmt.Errorf("failed to publish webhook event to Redis: %w", err)
 	}
 	return nil
 }
+
+// QueueDepth возвращает число событий, ожидающих доставки.
+// Для списка это его длина, для потока - непрочитанные группой сообщения плюс прочитанные, но не подтвержденные.
+func (p *RedisWebhookPublisher) QueueDepth(ctx context.Context) (int64, error) {
+	if p.backend == QueueBackendStreams {
+		groups, err := p.redisClient.XInfoGroups(ctx, p.keys.Key(webhookStreamKey)).Result()
+		if err != nil {
+			// Поток еще не создан - очередь пуста
+			if strings.Contains(err.Error(), "no such key") {
+				return 0, nil
+			}
+			return 0, fmt.Errorf("failed to get webhook stream groups: %w", err)
+		}
+		for _, group := range groups {
+			if group.Name == webhookConsumerGroup {
+				return group.Pending + max(group.Lag, 0), nil
+			}
+		}
+		// Группа еще не создана воркером - ожидают все сообщения потока
+		depth, err := p.redisClient.XLen(ctx, p.keys.Key(webhookStreamKey)).Result()
+		if err != nil {
+			return 0, fmt.Errorf("failed to get webhook stream length: %w", err)
+		}
+		return depth, nil
+	}
+
+	depth, err := p.redisClient.LLen(ctx, p.keys.Key(webhookQueueKey)).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get webhook queue length: %w", err)
+	}
+	return depth, nil
+}
+
+// CircuitStates возвращает состояния цепей, сохраненные воркерами при их изменении, в порядке адресов
+func (p *RedisWebhookPublisher) CircuitStates(ctx context.Context) ([]CircuitStatus, error) {
+	values, err := p.redisClient.HGetAll(ctx, p.keys.Key(webhookCircuitsKey)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook circuit states: %w", err)
+	}
+	states := make([]CircuitStatus, 0, len(values))
+	for url, value := range values {
+		var status CircuitStatus
+		if err := json.Unmarshal([]byte(value), &status); err != nil {
+			return nil, fmt.Errorf("failed to decode circuit state of %s: %w", url, err)
+		}
+		states = append(states, status)
+	}
+	slices.SortFunc(states, func(a, b CircuitStatus) int { return strings.Compare(a.URL, b.URL) })
+	return states, nil
+}
+
+// DeadLetterDepth возвращает длину списка недоставленных вебхуков
+func (p *RedisWebhookPublisher) DeadLetterDepth(ctx context.Context) (int64, error) {
+	depth, err := p.redisClient.LLen(ctx, p.keys.Key(webhookDeadLetterKey)).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get webhook dead letter length: %w", err)
+	}
+	return depth, nil
+}
+
+// deferUntil возвращает время, до которого откладывается доставка события. Откладываются только
+// опасные события в окно тишины, если среди их инцидентов нет критических и относящихся к категориям-исключениям.
+func (p *RedisWebhookPublisher) deferUntil(event WebhookEvent) (time.Time, bool) {
+	if !event.IsDangerous || event.MaxSeverity() == models.SeverityCritical {
+		return time.Time{}, false
+	}
+	for _, incident := range event.Incidents {
+		if slices.Contains(p.bypassCategories, incident.Category) {
+			return time.Time{}, false
+		}
+	}
+	return p.quietHours.Until(p.now())
+}
@@ -0,0 +1,60 @@
+package webhook
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/shenikar/geo_broadcasting_system/internal/config"
+	"github.com/shenikar/geo_broadcasting_system/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisWebhookPublisher_DeferUntil(t *testing.T) {
+	now := time.Date(2026, time.March, 10, 23, 0, 0, 0, time.UTC)
+	publisher := &RedisWebhookPublisher{
+		quietHours:       config.QuietHours{Start: 22 * time.Hour, End: 7 * time.Hour, Location: time.UTC},
+		bypassCategories: []string{"fire"},
+		now:              func() time.Time { return now },
+	}
+	morning := time.Date(2026, time.March, 11, 7, 0, 0, 0, time.UTC)
+
+	// Опасное событие в окно тишины откладывается до его окончания
+	until, ok := publisher.deferUntil(WebhookEvent{IsDangerous: true, Incidents: []*models.Incident{{Category: "flood"}}})
+	assert.True(t, ok)
+	assert.Equal(t, morning, until)
+
+	// Безопасные события и инциденты категорий-исключений доставляются сразу
+	_, ok = publisher.deferUntil(WebhookEvent{IsDangerous: false})
+	assert.False(t, ok)
+	_, ok = publisher.deferUntil(WebhookEvent{IsDangerous: true, Incidents: []*models.Incident{{Category: "flood"}, {Category: "fire"}}})
+	assert.False(t, ok)
+
+	// Критический инцидент проходит окно тишины независимо от категории
+	_, ok = publisher.deferUntil(WebhookEvent{IsDangerous: true, Incidents: []*models.Incident{
+		{Category: "flood", Severity: models.SeverityMinor},
+		{Category: "flood", Severity: models.SeverityCritical},
+	}})
+	assert.False(t, ok)
+
+	// Вне окна тишины ничего не откладывается
+	now = time.Date(2026, time.March, 11, 12, 0, 0, 0, time.UTC)
+	_, ok = publisher.deferUntil(WebhookEvent{IsDangerous: true})
+	assert.False(t, ok)
+}
+
+func TestWebhookEvent_OmitsCreatedBy(t *testing.T) {
+	event := WebhookEvent{
+		SchemaVersion: SchemaVersion,
+		Type:          EventTypeIncidentApproved,
+		Incidents:     []*models.Incident{{Name: "Flood", CreatedBy: "key_1a2b3c4d"}},
+	}
+
+	body, err := json.Marshal(event)
+	require.NoError(t, err)
+
+	// Метки API-ключей операторов не раскрываются подписчикам
+	assert.NotContains(t, string(body), "created_by")
+	assert.NotContains(t, string(body), "key_1a2b3c4d")
+}
@@ -0,0 +1,89 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sirupsen/logrus"
+)
+
+// queueDepthGauge - глубина очереди вебхуков на момент последней проверки
+var queueDepthGauge = promauto.NewGauge(prometheus.GaugeOpts{
+	Name: "geo_webhook_queue_depth",
+	Help: "Number of webhook events waiting for delivery.",
+})
+
+// QueueMonitor периодически проверяет глубину очереди вебхуков, публикует ее как метрику
+// и предупреждает в логе, когда очередь растет выше порога (например, если получатель недоступен).
+type QueueMonitor struct {
+	publisher WebhookPublisher
+	logger    *logrus.Logger
+	interval  time.Duration
+	threshold int64
+	// alarmed - глубина была выше порога при предыдущей проверке
+	alarmed bool
+}
+
+// NewQueueMonitor создает QueueMonitor. Нулевой threshold отключает предупреждения, метрика обновляется всегда.
+func NewQueueMonitor(publisher WebhookPublisher, logger *logrus.Logger, interval time.Duration, threshold int) *QueueMonitor {
+	return &QueueMonitor{
+		publisher: publisher,
+		logger:    logger,
+		interval:  interval,
+		threshold: int64(threshold),
+	}
+}
+
+// Start запускает горутину периодической проверки очереди
+func (m *QueueMonitor) Start(ctx context.Context) {
+	m.logger.WithFields(logrus.Fields{
+		"interval":  m.interval,
+		"threshold": m.threshold,
+	}).Info("Starting webhook queue monitor...")
+
+	go func() {
+		ticker := time.NewTicker(m.interval)
+		defer ticker.Stop()
+
+		for {
+			m.check(ctx)
+			select {
+			case <-ctx.Done():
+				m.logger.Info("Stopping webhook queue monitor.")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// check выполняет одну проверку глубины очереди
+func (m *QueueMonitor) check(ctx context.Context) {
+	log := m.logger.WithField("component", "webhook_queue_monitor")
+
+	depth, err := m.publisher.QueueDepth(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.WithError(err).Error("Failed to get webhook queue depth")
+		}
+		return
+	}
+	queueDepthGauge.Set(float64(depth))
+
+	if m.threshold <= 0 {
+		return
+	}
+	switch {
+	case depth > m.threshold:
+		log.WithFields(logrus.Fields{
+			"depth":     depth,
+			"threshold": m.threshold,
+		}).Warn("Webhook queue depth exceeds threshold, deliveries may be failing")
+		m.alarmed = true
+	case m.alarmed:
+		log.WithField("depth", depth).Info("Webhook queue depth back below threshold")
+		m.alarmed = false
+	}
+}
@@ -0,0 +1,72 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubQueuePublisher возвращает заданную глубину очереди
+type stubQueuePublisher struct {
+	depth int64
+	err   error
+}
+
+func (p *stubQueuePublisher) Publish(context.Context, WebhookEvent) error { return nil }
+
+func (p *stubQueuePublisher) QueueDepth(context.Context) (int64, error) { return p.depth, p.err }
+
+func (p *stubQueuePublisher) CircuitStates(context.Context) ([]CircuitStatus, error) { return nil, nil }
+
+func (p *stubQueuePublisher) DeadLetterDepth(context.Context) (int64, error) { return 0, nil }
+
+func TestQueueMonitor_WarnsAboveThreshold(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	publisher := &stubQueuePublisher{depth: 150}
+	monitor := NewQueueMonitor(publisher, logger, 0, 100)
+
+	monitor.check(context.Background())
+
+	assert.Equal(t, 150.0, testutil.ToFloat64(queueDepthGauge))
+	entry := hook.LastEntry()
+	require.NotNil(t, entry)
+	assert.Equal(t, logrus.WarnLevel, entry.Level)
+	assert.Equal(t, int64(150), entry.Data["depth"])
+
+	// После возврата ниже порога пишется одно сообщение о восстановлении
+	hook.Reset()
+	publisher.depth = 10
+	monitor.check(context.Background())
+	monitor.check(context.Background())
+
+	assert.Equal(t, 10.0, testutil.ToFloat64(queueDepthGauge))
+	require.Len(t, hook.AllEntries(), 1)
+	assert.Equal(t, logrus.InfoLevel, hook.LastEntry().Level)
+}
+
+func TestQueueMonitor_ThresholdDisabled(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	monitor := NewQueueMonitor(&stubQueuePublisher{depth: 5000}, logger, 0, 0)
+
+	monitor.check(context.Background())
+
+	assert.Equal(t, 5000.0, testutil.ToFloat64(queueDepthGauge))
+	assert.Empty(t, hook.AllEntries())
+}
+
+func TestQueueMonitor_DepthError(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	monitor := NewQueueMonitor(&stubQueuePublisher{err: errors.New("redis down")}, logger, 0, 100)
+
+	monitor.check(context.Background())
+
+	entry := hook.LastEntry()
+	require.NotNil(t, entry)
+	assert.Equal(t, logrus.ErrorLevel, entry.Level)
+}
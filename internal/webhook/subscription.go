@@ -0,0 +1,108 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/shenikar/geo_broadcasting_system/pkg/geo"
+)
+
+// Subscription - адрес доставки вебхуков с необязательным географическим фильтром
+type Subscription struct {
+	URL string `json:"url"`
+	// Secret - секрет подписи HMAC; если не задан, используется WEBHOOK_SECRET
+	Secret string `json:"secret,omitempty"`
+	// Region - область интереса; без нее подписка получает все события
+	Region *geo.Region `json:"region,omitempty"`
+	// Tier - уровень резервирования: адреса следующего уровня получают событие, только если
+	// ни один адрес предыдущего уровня не принял его после всех попыток. 0 и 1 - основной уровень
+	Tier int `json:"tier,omitempty"`
+	// incidentID - инцидент, за которым следит наблюдатель (см. WatcherSource); у подписок из конфигурации не задан
+	incidentID uuid.UUID
+}
+
+// tier возвращает уровень подписки с учетом значения по умолчанию
+func (s Subscription) tier() int {
+	if s.Tier < 1 {
+		return 1
+	}
+	return s.Tier
+}
+
+// groupByTier разбивает подписки (или доставки) на уровни в порядке возрастания
+func groupByTier[T interface{ tier() int }](subscriptions []T) [][]T {
+	byTier := make(map[int][]T)
+	for _, sub := range subscriptions {
+		byTier[sub.tier()] = append(byTier[sub.tier()], sub)
+	}
+	levels := make([]int, 0, len(byTier))
+	for level := range byTier {
+		levels = append(levels, level)
+	}
+	sort.Ints(levels)
+
+	tiers := make([][]T, 0, len(levels))
+	for _, level := range levels {
+		tiers = append(tiers, byTier[level])
+	}
+	return tiers
+}
+
+// Matches сообщает, должна ли подписка получить событие. Событие подходит, если в область
+// попадает точка события или центр хотя бы одного из инцидентов события. Наблюдатель инцидента
+// получает только события жизненного цикла своего инцидента.
+func (s Subscription) Matches(event WebhookEvent) bool {
+	if s.incidentID != uuid.Nil {
+		return s.watches(event)
+	}
+	if s.Region == nil {
+		return true
+	}
+	if s.Region.Contains(geo.Point{Lat: event.Latitude, Lon: event.Longitude}) {
+		return true
+	}
+	for _, incident := range event.Incidents {
+		if s.Region.Contains(geo.Point{Lat: incident.Latitude, Lon: incident.Longitude}) {
+			return true
+		}
+	}
+	return false
+}
+
+// loadSubscriptions формирует список подписок: WEBHOOK_URL (без фильтра, если задан)
+// и подписки из JSON-файла WEBHOOK_SUBSCRIPTIONS_FILE
+func loadSubscriptions(webhookURL, path string) ([]Subscription, error) {
+	var subscriptions []Subscription
+	if webhookURL != "" {
+		subscriptions = append(subscriptions, Subscription{URL: webhookURL})
+	}
+	if path == "" {
+		return subscriptions, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read webhook subscriptions file: %w", err)
+	}
+	var fromFile []Subscription
+	if err := json.Unmarshal(content, &fromFile); err != nil {
+		return nil, fmt.Errorf("failed to parse webhook subscriptions file: %w", err)
+	}
+	for i, sub := range fromFile {
+		if sub.URL == "" {
+			return nil, fmt.Errorf("webhook subscription #%d: url is required", i)
+		}
+		if sub.Tier < 0 {
+			return nil, fmt.Errorf("webhook subscription #%d (%s): tier must not be negative", i, sub.URL)
+		}
+		if sub.Region != nil {
+			if err := sub.Region.Validate(); err != nil {
+				return nil, fmt.Errorf("webhook subscription #%d (%s): %w", i, sub.URL, err)
+			}
+		}
+	}
+	return append(subscriptions, fromFile...), nil
+}
@@ -0,0 +1,181 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/shenikar/geo_broadcasting_system/internal/config"
+	"github.com/shenikar/geo_broadcasting_system/internal/models"
+	"github.com/shenikar/geo_broadcasting_system/pkg/geo"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscription_Matches(t *testing.T) {
+	spb := Subscription{
+		URL:    "http://spb",
+		Region: &geo.Region{BBox: &geo.BBox{MinLat: 59.8, MinLon: 30.1, MaxLat: 60.1, MaxLon: 30.6}},
+	}
+
+	assert.True(t, spb.Matches(WebhookEvent{Latitude: 59.93, Longitude: 30.31}))
+	assert.False(t, spb.Matches(WebhookEvent{Latitude: 55.75, Longitude: 37.61}))
+	// Точка события вне области, но центр инцидента внутри
+	assert.True(t, spb.Matches(WebhookEvent{
+		Latitude:  59.7,
+		Longitude: 30.3,
+		Incidents: []*models.Incident{{Latitude: 59.85, Longitude: 30.3}},
+	}))
+	assert.True(t, Subscription{URL: "http://all"}.Matches(WebhookEvent{Latitude: 55.75, Longitude: 37.61}))
+}
+
+func writeSubscriptions(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "subscriptions.json")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadSubscriptions(t *testing.T) {
+	path := writeSubscriptions(t, `[
+		{"url": "http://north", "secret": "s1", "region": {"bbox": {"min_lat": 59.8, "min_lon": 30.1, "max_lat": 60.1, "max_lon": 30.6}}},
+		{"url": "http://south", "region": {"polygon": [{"lat": 45.0, "lon": 39.0}, {"lat": 45.2, "lon": 39.3}, {"lat": 44.9, "lon": 39.4}]}}
+	]`)
+
+	subscriptions, err := loadSubscriptions("http://default", path)
+
+	require.NoError(t, err)
+	require.Len(t, subscriptions, 3)
+	assert.Equal(t, "http://default", subscriptions[0].URL)
+	assert.Nil(t, subscriptions[0].Region)
+	assert.Equal(t, "s1", subscriptions[1].Secret)
+	assert.Len(t, subscriptions[2].Region.Polygon, 3)
+}
+
+func TestLoadSubscriptions_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"missing url":   `[{"region": {"bbox": {"min_lat": 1, "max_lat": 2}}}]`,
+		"empty region":  `[{"url": "http://a", "region": {}}]`,
+		"both shapes":   `[{"url": "http://a", "region": {"bbox": {"min_lat": 1, "max_lat": 2}, "polygon": [{"lat": 0, "lon": 0}, {"lat": 1, "lon": 1}, {"lat": 0, "lon": 1}]}}]`,
+		"short polygon": `[{"url": "http://a", "region": {"polygon": [{"lat": 0, "lon": 0}, {"lat": 1, "lon": 1}]}}]`,
+		"bad json":      `{"url": "http://a"}`,
+		"negative tier": `[{"url": "http://a", "tier": -1}]`,
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := loadSubscriptions("", writeSubscriptions(t, content))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestProcessWebhookEvent_DeliversOnlyToMatchingRegions(t *testing.T) {
+	var mu sync.Mutex
+	received := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		received[r.URL.Path]++
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	logger := logrus.New()
+	logger.SetOutput(&bytes.Buffer{})
+	worker := &WebhookWorker{
+		logger:     logger,
+		breaker:    newCircuitBreaker(0, 0, nil),
+		cfg:        &config.Config{WebhookMaxRetries: 1},
+		httpClient: server.Client(),
+		subscriptions: []Subscription{
+			{URL: server.URL + "/all"},
+			{URL: server.URL + "/spb", Region: &geo.Region{BBox: &geo.BBox{MinLat: 59.8, MinLon: 30.1, MaxLat: 60.1, MaxLon: 30.6}}},
+			{URL: server.URL + "/msk", Region: &geo.Region{BBox: &geo.BBox{MinLat: 55.5, MinLon: 37.3, MaxLat: 56.0, MaxLon: 37.9}}},
+		},
+		limiter: newDeliveryLimiter(1),
+	}
+
+	done := make(chan struct{})
+	worker.processWebhookEvent(context.Background(), WebhookEvent{Latitude: 59.93, Longitude: 30.31}, `{}`, func(context.Context) { close(done) })
+	<-done
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, map[string]int{"/all": 1, "/spb": 1}, received)
+}
+
+func newTierTestWorker(subscriptions []Subscription) *WebhookWorker {
+	logger := logrus.New()
+	logger.SetOutput(&bytes.Buffer{})
+	return &WebhookWorker{
+		logger:        logger,
+		breaker:       newCircuitBreaker(0, 0, nil),
+		cfg:           &config.Config{WebhookMaxRetries: 1},
+		httpClient:    http.DefaultClient,
+		subscriptions: subscriptions,
+		limiter:       newDeliveryLimiter(1),
+	}
+}
+
+func TestProcessWebhookEvent_Tiers(t *testing.T) {
+	var mu sync.Mutex
+	received := make(map[string]int)
+	primaryStatus := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		received[r.URL.Path]++
+		if r.URL.Path == "/primary" {
+			w.WriteHeader(primaryStatus)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	worker := newTierTestWorker([]Subscription{
+		{URL: server.URL + "/backup", Tier: 2},
+		{URL: server.URL + "/primary"},
+	})
+	process := func() map[string]int {
+		mu.Lock()
+		clear(received)
+		mu.Unlock()
+		done := make(chan struct{})
+		worker.processWebhookEvent(context.Background(), WebhookEvent{}, `{}`, func(context.Context) { close(done) })
+		<-done
+		mu.Lock()
+		defer mu.Unlock()
+		return maps.Clone(received)
+	}
+
+	// Основной адрес доступен: резервный не вызывается
+	assert.Equal(t, map[string]int{"/primary": 1}, process())
+
+	// Основной адрес не принимает событие: оно уходит на резервный уровень
+	mu.Lock()
+	primaryStatus = http.StatusInternalServerError
+	mu.Unlock()
+	assert.Equal(t, map[string]int{"/primary": 1, "/backup": 1}, process())
+}
+
+func TestGroupByTier(t *testing.T) {
+	tiers := groupByTier([]Subscription{
+		{URL: "http://c", Tier: 3},
+		{URL: "http://a"},
+		{URL: "http://b", Tier: 2},
+		{URL: "http://a2", Tier: 1},
+	})
+
+	require.Len(t, tiers, 3)
+	assert.Equal(t, []Subscription{{URL: "http://a"}, {URL: "http://a2", Tier: 1}}, tiers[0])
+	assert.Equal(t, "http://b", tiers[1][0].URL)
+	assert.Equal(t, "http://c", tiers[2][0].URL)
+}
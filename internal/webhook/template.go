@@ -0,0 +1,54 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/template"
+)
+
+// payloadTemplateFuncs - функции, доступные в шаблоне тела вебхука
+var payloadTemplateFuncs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	},
+}
+
+// loadPayloadTemplate читает и разбирает шаблон тела вебхука из файла.
+// Для пустого пути возвращает nil: события отправляются как есть в JSON.
+func loadPayloadTemplate(path string) (*template.Template, error) {
+	if path == "" {
+		return nil, nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read webhook payload template: %w", err)
+	}
+	tmpl, err := template.New(filepath.Base(path)).Funcs(payloadTemplateFuncs).Option("missingkey=error").Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse webhook payload template: %w", err)
+	}
+	// Пробный рендер на пустом событии выявляет ошибки обращения к несуществующим полям
+	if err := tmpl.Execute(&bytes.Buffer{}, WebhookEvent{}); err != nil {
+		return nil, fmt.Errorf("failed to execute webhook payload template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// renderPayload формирует тело запроса для события. Без шаблона используется исходный JSON.
+func renderPayload(tmpl *template.Template, event WebhookEvent, rawPayload string) (string, error) {
+	if tmpl == nil {
+		return rawPayload, nil
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, event); err != nil {
+		return "", fmt.Errorf("failed to render webhook payload template: %w", err)
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,45 @@
+package webhook
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTemplate(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "webhook.tmpl")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestRenderPayload_NoTemplate(t *testing.T) {
+	body, err := renderPayload(nil, WebhookEvent{UserID: "user-1"}, `{"raw":true}`)
+
+	require.NoError(t, err)
+	assert.Equal(t, `{"raw":true}`, body)
+}
+
+func TestRenderPayload_WithTemplate(t *testing.T) {
+	tmpl, err := loadPayloadTemplate(writeTemplate(t, `{"who":{{json .UserID}},"danger":{{.IsDangerous}}}`))
+	require.NoError(t, err)
+
+	body, err := renderPayload(tmpl, WebhookEvent{UserID: `user "1"`, IsDangerous: true}, "")
+
+	require.NoError(t, err)
+	assert.Equal(t, `{"who":"user \"1\"","danger":true}`, body)
+}
+
+func TestLoadPayloadTemplate_Invalid(t *testing.T) {
+	_, err := loadPayloadTemplate(writeTemplate(t, `{{.Unknown}}`))
+	assert.Error(t, err)
+
+	_, err = loadPayloadTemplate(writeTemplate(t, `{{ if }}`))
+	assert.Error(t, err)
+
+	_, err = loadPayloadTemplate(filepath.Join(t.TempDir(), "missing.tmpl"))
+	assert.Error(t, err)
+}
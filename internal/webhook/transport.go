@@ -0,0 +1,103 @@
+package webhook
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/shenikar/geo_broadcasting_system/internal/config"
+	"github.com/shenikar/geo_broadcasting_system/pkg/netguard"
+)
+
+// newHTTPClient создает HTTP-клиент доставки вебхуков с ограничениями TLS из конфигурации:
+// минимальная версия, наборы шифров, собственные корневые сертификаты и закрепление ключей.
+// При WEBHOOK_FOLLOW_REDIRECTS=false перенаправления не выполняются и ответ 3xx возвращается как есть.
+func newHTTPClient(cfg *config.Config) (*http.Client, error) {
+	tlsConfig := baseTLSConfig(cfg)
+	if cfg.WebhookTLSCAFile != "" {
+		pem, err := os.ReadFile(cfg.WebhookTLSCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read webhook CA file: %w", err)
+		}
+		roots := x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("webhook CA file %s contains no PEM certificates", cfg.WebhookTLSCAFile)
+		}
+		tlsConfig.RootCAs = roots
+	}
+	if len(cfg.WebhookTLSPinnedKeys) > 0 {
+		tlsConfig.VerifyConnection = verifyPinnedKeys(cfg.WebhookTLSPinnedKeys)
+	}
+	return newDeliveryClient(cfg, tlsConfig), nil
+}
+
+// newWatcherHTTPClient создает HTTP-клиент доставки наблюдателям инцидентов (POST /incidents/{id}/watch).
+// Их адреса задают клиенты API, поэтому соединения с непубличными адресами запрещаются при каждом подключении,
+// в том числе после перенаправления или смены DNS-записи, а прокси из окружения не используется.
+// WEBHOOK_TLS_CA_FILE и WEBHOOK_TLS_PINNED_KEYS описывают собственного получателя вебхуков и к наблюдателям
+// не применяются: их сертификаты проверяются по системным корневым сертификатам.
+func newWatcherHTTPClient(cfg *config.Config) *http.Client {
+	client := newDeliveryClient(cfg, baseTLSConfig(cfg))
+	transport := client.Transport.(*http.Transport)
+	transport.Proxy = nil
+	transport.DialContext = (&net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   netguard.Control,
+	}).DialContext
+	return client
+}
+
+// baseTLSConfig возвращает общие для всех доставок ограничения TLS: минимальную версию и наборы шифров
+func baseTLSConfig(cfg *config.Config) *tls.Config {
+	return &tls.Config{
+		MinVersion:   cfg.WebhookTLSMinVersion,
+		CipherSuites: cfg.WebhookTLSCipherSuites,
+	}
+}
+
+// newDeliveryClient создает HTTP-клиент с заданной конфигурацией TLS, таймаутом WEBHOOK_TIMEOUT
+// и правилом перенаправлений WEBHOOK_FOLLOW_REDIRECTS
+func newDeliveryClient(cfg *config.Config, tlsConfig *tls.Config) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+
+	client := &http.Client{
+		Timeout:   cfg.WebhookTimeout,
+		Transport: transport,
+	}
+	if !cfg.WebhookFollowRedirects {
+		client.CheckRedirect = func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	}
+	return client
+}
+
+// verifyPinnedKeys возвращает проверку соединения, требующую, чтобы одна из проверенных цепочек
+// получателя содержала открытый ключ с SHA-256 отпечатком из pins. Выполняется после обычной проверки сертификата.
+func verifyPinnedKeys(pins []string) func(tls.ConnectionState) error {
+	allowed := make(map[string]struct{}, len(pins))
+	for _, pin := range pins {
+		allowed[pin] = struct{}{}
+	}
+
+	return func(state tls.ConnectionState) error {
+		for _, chain := range state.VerifiedChains {
+			for _, cert := range chain {
+				sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+				if _, ok := allowed[hex.EncodeToString(sum[:])]; ok {
+					return nil
+				}
+			}
+		}
+		return errors.New("webhook receiver certificate does not match any pinned public key")
+	}
+}
@@ -0,0 +1,120 @@
+package webhook
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shenikar/geo_broadcasting_system/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTLSTestServer запускает HTTPS-сервер с ограничением версии TLS и возвращает путь к PEM его сертификата
+func newTLSTestServer(t *testing.T, maxVersion uint16, handler http.HandlerFunc) (*httptest.Server, string) {
+	server := httptest.NewUnstartedServer(handler)
+	server.TLS = &tls.Config{MaxVersion: maxVersion}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	require.NoError(t, os.WriteFile(caFile, certPEM, 0o600))
+	return server, caFile
+}
+
+func testTransportConfig(caFile string) *config.Config {
+	return &config.Config{
+		WebhookTimeout:         5 * time.Second,
+		WebhookTLSMinVersion:   tls.VersionTLS12,
+		WebhookTLSCAFile:       caFile,
+		WebhookFollowRedirects: true,
+	}
+}
+
+func TestNewHTTPClient_MinVersion(t *testing.T) {
+	server, caFile := newTLSTestServer(t, tls.VersionTLS11, func(w http.ResponseWriter, r *http.Request) {})
+
+	client, err := newHTTPClient(testTransportConfig(caFile))
+	require.NoError(t, err)
+
+	// Сервер не поддерживает TLS 1.2 - рукопожатие не состоится
+	_, err = client.Post(server.URL, "application/json", nil)
+	assert.Error(t, err)
+}
+
+func TestNewHTTPClient_PinnedKeys(t *testing.T) {
+	server, caFile := newTLSTestServer(t, tls.VersionTLS13, func(w http.ResponseWriter, r *http.Request) {})
+	sum := sha256.Sum256(server.Certificate().RawSubjectPublicKeyInfo)
+
+	cfg := testTransportConfig(caFile)
+	cfg.WebhookTLSPinnedKeys = []string{hex.EncodeToString(sum[:])}
+	client, err := newHTTPClient(cfg)
+	require.NoError(t, err)
+	resp, err := client.Post(server.URL, "application/json", nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	cfg.WebhookTLSPinnedKeys = []string{hex.EncodeToString(make([]byte, 32))}
+	client, err = newHTTPClient(cfg)
+	require.NoError(t, err)
+	_, err = client.Post(server.URL, "application/json", nil)
+	assert.ErrorContains(t, err, "pinned public key")
+}
+
+func TestNewHTTPClient_Redirects(t *testing.T) {
+	server, caFile := newTLSTestServer(t, tls.VersionTLS13, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/hook" {
+			http.Redirect(w, r, "/moved", http.StatusFound)
+		}
+	})
+
+	cfg := testTransportConfig(caFile)
+	cfg.WebhookFollowRedirects = false
+	client, err := newHTTPClient(cfg)
+	require.NoError(t, err)
+
+	resp, err := client.Post(server.URL+"/hook", "application/json", nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusFound, resp.StatusCode)
+}
+
+func TestNewHTTPClient_InvalidCAFile(t *testing.T) {
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, []byte("not a certificate"), 0o600))
+
+	_, err := newHTTPClient(testTransportConfig(caFile))
+	assert.Error(t, err)
+}
+
+func TestNewWatcherHTTPClient_IgnoresReceiverTLSSettings(t *testing.T) {
+	server, caFile := newTLSTestServer(t, tls.VersionTLS13, func(w http.ResponseWriter, r *http.Request) {})
+
+	// Закрепленный ключ заведомо не совпадает с ключом сервера
+	cfg := testTransportConfig(caFile)
+	cfg.WebhookTLSPinnedKeys = []string{hex.EncodeToString(make([]byte, 32))}
+	client := newWatcherHTTPClient(cfg)
+
+	transport := client.Transport.(*http.Transport)
+	assert.Nil(t, transport.TLSClientConfig.VerifyConnection)
+	assert.Nil(t, transport.TLSClientConfig.RootCAs)
+	assert.Equal(t, uint16(tls.VersionTLS12), transport.TLSClientConfig.MinVersion)
+
+	// Разрешаем loopback, чтобы дойти до рукопожатия: сертификат проверяется по системным
+	// корневым сертификатам, а не по WEBHOOK_TLS_CA_FILE, и проверка закрепления не выполняется
+	transport.DialContext = nil
+	_, err := client.Post(server.URL, "application/json", nil)
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "pinned public key")
+	var unknownAuthority x509.UnknownAuthorityError
+	assert.ErrorAs(t, err, &unknownAuthority)
+}
@@ -0,0 +1,73 @@
+package webhook
+
+import (
+	"context"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/shenikar/geo_broadcasting_system/internal/models"
+	"github.com/sirupsen/logrus"
+)
+
+// watchedEventTypes - события жизненного цикла инцидента, которые доставляются его наблюдателям
+var watchedEventTypes = []string{
+	EventTypeIncidentApproved,
+	EventTypeIncidentUpdated,
+	EventTypeIncidentEscalated,
+	EventTypeIncidentDeactivated,
+}
+
+// WatcherSource возвращает наблюдателей инцидентов (POST /incidents/{id}/watch); реализуется репозиторием
+type WatcherSource interface {
+	ListWatchersByIncidents(ctx context.Context, incidentIDs []uuid.UUID) ([]*models.IncidentWatcher, error)
+}
+
+// isWatcher сообщает, что подписка принадлежит наблюдателю инцидента, а не задана в конфигурации
+func (s Subscription) isWatcher() bool {
+	return s.incidentID != uuid.Nil
+}
+
+// watches сообщает, относится ли событие к инциденту, за которым следит подписка наблюдателя
+func (s Subscription) watches(event WebhookEvent) bool {
+	if !slices.Contains(watchedEventTypes, event.Type) {
+		return false
+	}
+	for _, incident := range event.Incidents {
+		if incident.ID == s.incidentID {
+			return true
+		}
+	}
+	return false
+}
+
+// watcherSubscriptions возвращает подписки наблюдателей инцидентов, затронутых событиями жизненного цикла.
+// Каждая подписка получает только события своего инцидента (см. Subscription.Matches).
+// Если наблюдателей не удалось получить, событие доставляется только по обычным подпискам.
+func (w *WebhookWorker) watcherSubscriptions(ctx context.Context, events []WebhookEvent, log *logrus.Entry) []Subscription {
+	if w.watchers == nil {
+		return nil
+	}
+	var incidentIDs []uuid.UUID
+	for _, event := range events {
+		if !slices.Contains(watchedEventTypes, event.Type) {
+			continue
+		}
+		for _, incident := range event.Incidents {
+			incidentIDs = append(incidentIDs, incident.ID)
+		}
+	}
+	if len(incidentIDs) == 0 {
+		return nil
+	}
+
+	watchers, err := w.watchers.ListWatchersByIncidents(ctx, incidentIDs)
+	if err != nil {
+		log.WithError(err).Error("Failed to list incident watchers. Skipping delivery to watchers.")
+		return nil
+	}
+	subscriptions := make([]Subscription, len(watchers))
+	for i, watcher := range watchers {
+		subscriptions[i] = Subscription{URL: watcher.URL, Secret: watcher.Secret, incidentID: watcher.IncidentID}
+	}
+	return subscriptions
+}
@@ -0,0 +1,154 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shenikar/geo_broadcasting_system/internal/config"
+	"github.com/shenikar/geo_broadcasting_system/internal/models"
+	"github.com/shenikar/geo_broadcasting_system/pkg/netguard"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubWatchers - источник наблюдателей для тестов; запоминает запрошенные инциденты
+type stubWatchers struct {
+	watchers  []*models.IncidentWatcher
+	err       error
+	requested []uuid.UUID
+}
+
+func (s *stubWatchers) ListWatchersByIncidents(_ context.Context, incidentIDs []uuid.UUID) ([]*models.IncidentWatcher, error) {
+	s.requested = append(s.requested, incidentIDs...)
+	return s.watchers, s.err
+}
+
+func TestSubscription_MatchesWatchedIncident(t *testing.T) {
+	watched, other := uuid.New(), uuid.New()
+	sub := Subscription{URL: "http://watcher", incidentID: watched}
+
+	assert.True(t, sub.Matches(WebhookEvent{Type: EventTypeIncidentUpdated, Incidents: []*models.Incident{{ID: watched}}}))
+	assert.True(t, sub.Matches(WebhookEvent{Type: EventTypeIncidentDeactivated, Incidents: []*models.Incident{{ID: watched}}}))
+	assert.False(t, sub.Matches(WebhookEvent{Type: EventTypeIncidentUpdated, Incidents: []*models.Incident{{ID: other}}}))
+	// Попадание пользователя в зону - не изменение инцидента
+	assert.False(t, sub.Matches(WebhookEvent{Type: EventTypeLocationCheck, Incidents: []*models.Incident{{ID: watched}}}))
+}
+
+func newWatcherTestWorker(serverURL string, client *http.Client, watchers WatcherSource) *WebhookWorker {
+	logger := logrus.New()
+	logger.SetOutput(&bytes.Buffer{})
+	return &WebhookWorker{
+		logger:        logger,
+		breaker:       newCircuitBreaker(0, 0, nil),
+		cfg:           &config.Config{WebhookMaxRetries: 1},
+		httpClient:    client,
+		watcherClient: client,
+		subscriptions: []Subscription{{URL: serverURL + "/all"}},
+		limiter:       newDeliveryLimiter(1),
+		watchers:      watchers,
+	}
+}
+
+func TestProcessWebhookEvent_DeliversToWatchers(t *testing.T) {
+	var mu sync.Mutex
+	received := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		received[r.URL.Path]++
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	incidentID := uuid.New()
+	source := &stubWatchers{watchers: []*models.IncidentWatcher{{IncidentID: incidentID, URL: server.URL + "/watcher"}}}
+	worker := newWatcherTestWorker(server.URL, server.Client(), source)
+
+	for _, eventType := range []string{EventTypeIncidentEscalated, EventTypeLocationCheck} {
+		done := make(chan struct{})
+		event := WebhookEvent{Type: eventType, Incidents: []*models.Incident{{ID: incidentID}}}
+		worker.processWebhookEvent(context.Background(), event, `{}`, func(context.Context) { close(done) })
+		<-done
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, map[string]int{"/all": 2, "/watcher": 1}, received)
+	// Наблюдатели запрашиваются только для событий жизненного цикла
+	assert.Equal(t, []uuid.UUID{incidentID}, source.requested)
+}
+
+func TestProcessWebhookEvent_WatcherSourceFailure(t *testing.T) {
+	var mu sync.Mutex
+	received := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		received[r.URL.Path]++
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	worker := newWatcherTestWorker(server.URL, server.Client(), &stubWatchers{err: errors.New("connection refused")})
+
+	done := make(chan struct{})
+	event := WebhookEvent{Type: EventTypeIncidentUpdated, Incidents: []*models.Incident{{ID: uuid.New()}}}
+	worker.processWebhookEvent(context.Background(), event, `{}`, func(context.Context) { close(done) })
+	<-done
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, map[string]int{"/all": 1}, received)
+}
+
+func TestProcessWebhookEvent_WatcherNotSignedWithSharedSecret(t *testing.T) {
+	var mu sync.Mutex
+	signatures := make(map[string]string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		signatures[r.URL.Path] = r.Header.Get("X-Webhook-Signature")
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	incidentID := uuid.New()
+	source := &stubWatchers{watchers: []*models.IncidentWatcher{
+		{IncidentID: incidentID, URL: server.URL + "/watcher"},
+		{IncidentID: incidentID, URL: server.URL + "/signed", Secret: "watch-secret"},
+	}}
+	worker := newWatcherTestWorker(server.URL, server.Client(), source)
+	worker.cfg.WebhookSecret = "shared-secret"
+
+	done := make(chan struct{})
+	event := WebhookEvent{Type: EventTypeIncidentUpdated, Incidents: []*models.Incident{{ID: incidentID}}}
+	worker.processWebhookEvent(context.Background(), event, `{}`, func(context.Context) { close(done) })
+	<-done
+
+	mu.Lock()
+	defer mu.Unlock()
+	// WEBHOOK_SECRET подписывает только подписки из конфигурации
+	assert.Equal(t, generateHMACSHA256(`{}`, "shared-secret"), signatures["/all"])
+	assert.Empty(t, signatures["/watcher"])
+	assert.Equal(t, generateHMACSHA256(`{}`, "watch-secret"), signatures["/signed"])
+}
+
+func TestNewWatcherHTTPClient_RefusesPrivateAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := newWatcherHTTPClient(&config.Config{WebhookTimeout: time.Second})
+
+	// Адрес сервера - loopback: соединение запрещается до отправки запроса
+	_, err := client.Get(server.URL)
+	assert.ErrorIs(t, err, netguard.ErrForbiddenAddress)
+}
@@ -8,47 +8,152 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"text/template"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 	"github.com/shenikar/geo_broadcasting_system/internal/config"
+	"github.com/shenikar/geo_broadcasting_system/internal/models"
+	redisclient "github.com/shenikar/geo_broadcasting_system/pkg/redis"
 	"github.com/sirupsen/logrus"
 )
 
 // WebhookWorker - структура для обработки и отправки вебхуков
 type WebhookWorker struct {
 	redisClient *redis.Client
+	keys        redisclient.KeyBuilder
 	logger      *logrus.Logger
 	cfg         *config.Config
 	httpClient  *http.Client
+	// watcherClient доставляет вебхуки наблюдателям инцидентов только на публичные адреса (см. newWatcherHTTPClient)
+	watcherClient *http.Client
+	// payloadTemplate - необязательный шаблон тела вебхука (WEBHOOK_PAYLOAD_TEMPLATE)
+	payloadTemplate *template.Template
+	// subscriptions - адреса доставки с необязательными географическими фильтрами;
+	// заменяются при перезагрузке конфигурации (см. Reload)
+	subscriptions   []Subscription
+	subscriptionsMu sync.RWMutex
+	// notifier - необязательный канал уведомлений людей об опасных событиях (NOTIFIER)
+	notifier Notifier
+	// limiter ограничивает число одновременных доставок на один адрес (WEBHOOK_MAX_CONCURRENT_PER_URL)
+	limiter *deliveryLimiter
+	// batcher накапливает события в пакеты; nil - события отправляются по одному (WEBHOOK_BATCH_SIZE = 1)
+	batcher *eventBatcher
+	// breaker размыкает цепь адреса после серии неудачных попыток (WEBHOOK_CIRCUIT_FAILURE_THRESHOLD)
+	breaker *circuitBreaker
+	// watchers - источник наблюдателей инцидентов; nil - события доставляются только по подпискам
+	watchers WatcherSource
+	// running учитывает горутину чтения очереди до ее остановки вместе с доставкой последнего пакета (см. Wait)
+	running sync.WaitGroup
 }
 
-// NewWebhookWorker создает новый WebhookWorker
-func NewWebhookWorker(redisClient *redis.Client, logger *logrus.Logger, cfg *config.Config) *WebhookWorker {
-	return &WebhookWorker{
-		redisClient: redisClient,
-		logger:      logger,
-		cfg:         cfg,
-		httpClient: &http.Client{
-			Timeout: cfg.WebhookTimeout,
-		},
+// NewWebhookWorker создает новый WebhookWorker; watchers - источник наблюдателей инцидентов.
+// Возвращает ошибку, если настроенный шаблон тела вебхука или файл подписок не удается загрузить.
+func NewWebhookWorker(redisClient *redis.Client, keys redisclient.KeyBuilder, logger *logrus.Logger, cfg *config.Config, watchers WatcherSource) (*WebhookWorker, error) {
+	payloadTemplate, err := loadPayloadTemplate(cfg.WebhookPayloadTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid webhook payload template: %w", err)
 	}
+	subscriptions, err := loadSubscriptions(cfg.WebhookURL, cfg.WebhookSubscriptionsFile)
+	if err != nil {
+		return nil, fmt.Errorf("invalid webhook subscriptions: %w", err)
+	}
+	notifier, err := newNotifier(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("invalid notifier: %w", err)
+	}
+	httpClient, err := newHTTPClient(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("invalid webhook TLS settings: %w", err)
+	}
+	watcherClient := newWatcherHTTPClient(cfg)
+
+	w := &WebhookWorker{
+		redisClient:     redisClient,
+		keys:            keys,
+		logger:          logger,
+		cfg:             cfg,
+		httpClient:      httpClient,
+		watcherClient:   watcherClient,
+		payloadTemplate: payloadTemplate,
+		subscriptions:   subscriptions,
+		notifier:        notifier,
+		limiter:         newDeliveryLimiter(cfg.WebhookMaxConcurrentPerURL),
+		watchers:        watchers,
+	}
+	w.breaker = newCircuitBreaker(cfg.WebhookCircuitFailureThreshold, cfg.WebhookCircuitCooldown, w.saveCircuit)
+	return w, nil
+}
+
+// Reload перечитывает подписки: адрес WEBHOOK_URL берется из обновленной конфигурации next,
+// файл подписок перечитывается по прежнему пути. Секрет и число попыток читаются из конфигурации
+// при каждой доставке и отдельной перезагрузки не требуют. При ошибке продолжают действовать прежние подписки.
+func (w *WebhookWorker) Reload(next *config.Config) error {
+	subscriptions, err := loadSubscriptions(next.WebhookURL, w.cfg.WebhookSubscriptionsFile)
+	if err != nil {
+		return fmt.Errorf("invalid webhook subscriptions: %w", err)
+	}
+	w.subscriptionsMu.Lock()
+	w.subscriptions = subscriptions
+	w.subscriptionsMu.Unlock()
+	return nil
 }
 
 // Start запускает горутину для обработки очереди вебхуков
 func (w *WebhookWorker) Start(ctx context.Context) {
-	w.logger.Info("Starting webhook worker...")
+	w.logger.WithFields(logrus.Fields{
+		"backend":    w.cfg.WebhookQueueBackend,
+		"batch_size": w.cfg.WebhookBatchSize,
+	}).Info("Starting webhook worker...")
+	if w.cfg.WebhookBatchSize > 1 {
+		w.batcher = newEventBatcher(w.cfg.WebhookBatchSize, w.cfg.WebhookBatchWindow, func(batch []pendingEvent) {
+			w.processBatch(ctx, batch)
+		})
+	}
+	w.startScheduled(ctx)
+	if w.cfg.WebhookQueueBackend == QueueBackendStreams {
+		w.startStreams(ctx)
+		return
+	}
+	w.startList(ctx)
+}
+
+// Wait ждет остановки воркера после отмены контекста Start, включая доставку накопленного пакета.
+// Возвращает ошибку контекста, если воркер не остановился до его отмены.
+func (w *WebhookWorker) Wait(ctx context.Context) error {
+	done := make(chan struct{})
 	go func() {
+		w.running.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// startList обрабатывает очередь на основе списка Redis (LPUSH/BRPOP)
+func (w *WebhookWorker) startList(ctx context.Context) {
+	w.running.Add(1)
+	go func() {
+		defer w.running.Done()
 		for {
 			select {
 			case <-ctx.Done():
+				w.flushBatch(ctx)
 				w.logger.Info("Stopping webhook worker.")
 				return
 			default:
 				// BLPOP - блокирующее извлечение из правой части списка (очереди)
 				// 0 означает бесконечное ожидание
-				result, err := w.redisClient.BRPop(ctx, 0, webhookQueueKey).Result()
+				result, err := w.redisClient.BRPop(ctx, 0, w.keys.Key(webhookQueueKey)).Result()
 				if err != nil {
 					if errors.Is(err, context.Canceled) {
 						continue // Контекст отменен, но не ошибка Redis
@@ -66,52 +171,250 @@ func (w *WebhookWorker) Start(ctx context.Context) {
 					continue
 				}
 
-				w.processWebhookEvent(ctx, event, payload)
+				w.submit(ctx, event, payload, nil)
 			}
 		}
 	}()
 }
 
-func (w *WebhookWorker) processWebhookEvent(ctx context.Context, event WebhookEvent, rawPayload string) {
+// processWebhookEvent доставляет событие во все подписки, чей фильтр ему соответствует, и наблюдателям
+// его инцидента. Для каждой доставки сначала занимается слот адреса назначения: пока все слоты заняты,
+// чтение очереди приостанавливается и остальные события ждут в Redis. Сами доставки выполняются
+// в отдельных горутинах; onDone (если задан) вызывается после завершения всех доставок события.
+func (w *WebhookWorker) processWebhookEvent(ctx context.Context, event WebhookEvent, rawPayload string, onDone func(ctx context.Context)) {
 	log := w.logger.WithField("event_user_id", event.UserID).WithField("event_is_dangerous", event.IsDangerous)
 	log.Debug("Processing webhook event...")
 
-	if w.cfg.WebhookURL == "" {
+	var wg sync.WaitGroup
+	defer func() {
+		if onDone != nil {
+			go func() {
+				wg.Wait()
+				onDone(ctx)
+			}()
+		}
+	}()
+
+	w.notify(ctx, event, log, &wg)
+
+	w.subscriptionsMu.RLock()
+	subscriptions := w.subscriptions
+	w.subscriptionsMu.RUnlock()
+	watchers := w.watcherSubscriptions(ctx, []WebhookEvent{event}, log)
+
+	if len(subscriptions) == 0 && len(watchers) == 0 {
 		log.Warn("Webhook URL is not configured. Skipping webhook delivery.")
 		return
 	}
 
-	maxRetries := w.cfg.WebhookMaxRetries
+	body, err := renderPayload(w.payloadTemplate, event, rawPayload)
+	if err != nil {
+		log.WithError(err).Error("Failed to render webhook payload. Skipping webhook delivery.")
+		return
+	}
+
+	w.dispatch(ctx, eventDeliveries(subscriptions, event, body, log), log, &wg)
+	// Наблюдатели доставляются отдельно, чтобы принятое ими событие не отменяло переход подписок к резервному уровню
+	w.dispatch(ctx, eventDeliveries(watchers, event, body, log), log, &wg)
+}
+
+// eventDeliveries возвращает доставки тела события в подписки, чей фильтр ему соответствует
+func eventDeliveries(subscriptions []Subscription, event WebhookEvent, body string, log *logrus.Entry) []delivery {
+	var matched []delivery
+	for _, sub := range subscriptions {
+		if !sub.Matches(event) {
+			log.WithField("url", sub.URL).Debug("Event is outside of the subscription region. Skipping.")
+			continue
+		}
+		matched = append(matched, delivery{
+			sub:       sub,
+			body:      body,
+			eventIDs:  event.EventID,
+			severity:  event.MaxSeverity(),
+			dangerous: event.IsDangerous,
+		})
+	}
+	return matched
+}
+
+// notify отправляет уведомление об опасном событии через notifier (если он настроен).
+// Уведомление не повторяется при ошибке: это дополнительный канал, основной - вебхуки.
+func (w *WebhookWorker) notify(ctx context.Context, event WebhookEvent, log *logrus.Entry, wg *sync.WaitGroup) {
+	if w.notifier == nil || !event.IsDangerous {
+		return
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := w.notifier.Notify(ctx, event); err != nil {
+			log.WithError(err).Error("Failed to send danger notification")
+		}
+	}()
+}
+
+// delivery - тело вебхука, предназначенное одной подписке
+type delivery struct {
+	sub  Subscription
+	body string
+	// eventIDs - идентификаторы событий в теле для заголовка X-Webhook-Event-Id (через запятую для пакета)
+	eventIDs string
+	// severity и dangerous - наибольший уровень опасности и признак опасности событий в теле
+	// для заголовков X-Incident-Severity и X-Event-Dangerous
+	severity  models.IncidentSeverity
+	dangerous bool
+}
+
+// tier возвращает уровень подписки доставки
+func (d delivery) tier() int {
+	return d.sub.tier()
+}
+
+// dispatch доставляет тела подписчикам по уровням резервирования. Слоты основного уровня
+// занимаются синхронно, остальные уровни обрабатываются в фоне; все горутины учитываются в wg.
+func (w *WebhookWorker) dispatch(ctx context.Context, deliveries []delivery, log *logrus.Entry, wg *sync.WaitGroup) {
+	if len(deliveries) == 0 {
+		return
+	}
+
+	// Слоты основного уровня занимаются синхронно, чтобы переполненный адрес приостанавливал чтение очереди
+	tiers := groupByTier(deliveries)
+	wait, err := w.deliverTier(ctx, tiers[0], log, wg)
+	if err != nil {
+		log.WithError(err).Warn("Webhook delivery aborted while waiting for a free slot")
+		return
+	}
+	if len(tiers) == 1 {
+		return
+	}
+
+	// Резервные уровни задействуются, только если ни один адрес предыдущего уровня не принял событие
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for _, tier := range tiers[1:] {
+			if wait() {
+				return
+			}
+			log.WithField("tier", tier[0].tier()).Warn("All webhook endpoints of the previous tier failed, escalating to the next tier")
+			next, err := w.deliverTier(ctx, tier, log, wg)
+			if err != nil {
+				log.WithError(err).Warn("Webhook delivery aborted while waiting for a free slot")
+				return
+			}
+			wait = next
+		}
+		if !wait() {
+			log.Error("Failed to deliver webhook to any tier")
+		}
+	}()
+}
+
+// deliverTier занимает слоты и запускает доставку во все подписки уровня. Возвращает функцию,
+// которая дожидается окончания доставок уровня и сообщает, принял ли событие хотя бы один адрес.
+func (w *WebhookWorker) deliverTier(ctx context.Context, tier []delivery, log *logrus.Entry, wg *sync.WaitGroup) (func() bool, error) {
+	var tierWG sync.WaitGroup
+	var delivered atomic.Bool
+	for _, d := range tier {
+		sub := d.sub
+		if err := w.limiter.acquire(ctx, sub.URL); err != nil {
+			return nil, err
+		}
+		wg.Add(1)
+		tierWG.Add(1)
+		go func() {
+			defer wg.Done()
+			defer tierWG.Done()
+			defer w.limiter.release(sub.URL)
+			if w.deliver(ctx, d, log.WithField("url", sub.URL)) {
+				delivered.Store(true)
+			}
+		}()
+	}
+	return func() bool {
+		tierWG.Wait()
+		return delivered.Load()
+	}, nil
+}
+
+// deliver отправляет тело вебхука подписчику с повторными попытками и экспоненциальной задержкой.
+// Все попытки несут одно и то же тело и X-Webhook-Event-Id: если подписчик получил запрос,
+// но не успел ответить, повтор придет с тем же идентификатором. Пока цепь адреса разомкнута,
+// попытки не выполняются и вебхук сразу попадает в очередь недоставленных.
+// Возвращает true, если подписчик принял вебхук.
+func (w *WebhookWorker) deliver(ctx context.Context, d delivery, log *logrus.Entry) bool {
+	sub, body := d.sub, d.body
+	defaultSecret, maxRetries := w.cfg.WebhookDelivery()
+	secret, client := sub.Secret, w.httpClient
+	if sub.isWatcher() {
+		// Адрес наблюдателя задан клиентом API: общий WEBHOOK_SECRET ему не раскрывается, а соединение
+		// допускается только с публичным адресом
+		client = w.watcherClient
+	} else if secret == "" {
+		secret = defaultSecret
+	}
 	baseDelay := w.cfg.WebhookBaseDelay
 
 	for i := 0; i < maxRetries; i++ {
-		req, err := http.NewRequestWithContext(ctx, "POST", w.cfg.WebhookURL, bytes.NewBufferString(rawPayload))
+		if !w.breaker.allow(sub.URL) {
+			w.deadLetter(ctx, d, "circuit open", log)
+			return false
+		}
+
+		req, err := http.NewRequestWithContext(ctx, "POST", sub.URL, bytes.NewBufferString(body))
 		if err != nil {
+			// Попытка учитывается размыкателем: иначе пробная доставка полуразомкнутой цепи не завершится
+			// и цепь останется полуразомкнутой навсегда
+			if w.breaker.failure(sub.URL) {
+				log.WithError(err).Error("Failed to create webhook request for event, destination circuit is open")
+				w.deadLetter(ctx, d, "circuit open", log)
+				return false
+			}
 			log.WithError(err).Errorf("Failed to create webhook request for event. Retries left: %d", maxRetries-1-i)
 			continue
 		}
 
 		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Webhook-Schema-Version", SchemaVersion)
+		// События, опубликованные до появления event_id, доставляются без заголовка
+		if d.eventIDs != "" {
+			req.Header.Set("X-Webhook-Event-Id", d.eventIDs)
+		}
+		// Заголовки для маршрутизации на стороне получателя без разбора тела
+		req.Header.Set("X-Event-Dangerous", strconv.FormatBool(d.dangerous))
+		if d.severity != "" {
+			req.Header.Set("X-Incident-Severity", string(d.severity))
+		}
 
-		// Добавляем HMAC подпись, если WEBHOOK_SECRET задан
-		if w.cfg.WebhookSecret != "" {
-			signature := generateHMACSHA256(rawPayload, w.cfg.WebhookSecret)
+		// Добавляем HMAC подпись, если секрет задан (подписывается итоговое тело запроса)
+		if secret != "" {
+			signature := generateHMACSHA256(body, secret)
 			req.Header.Set("X-Webhook-Signature", signature)
 		}
 
-		resp, err := w.httpClient.Do(req)
+		resp, err := client.Do(req)
 		if err != nil {
+			if w.breaker.failure(sub.URL) {
+				log.WithError(err).Warn("Failed to send webhook for event, destination circuit is open")
+				w.deadLetter(ctx, d, "circuit open", log)
+				return false
+			}
 			log.WithError(err).Warnf("Failed to send webhook for event. Retrying in %v. Retries left: %d", baseDelay, maxRetries-1-i)
 			time.Sleep(baseDelay)
 			baseDelay *= 2 // Экспоненциальная задержка
 			continue
 		}
-		defer resp.Body.Close()
+		resp.Body.Close()
 
 		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			w.breaker.success(sub.URL)
 			log.Info("Webhook delivered successfully.")
-			return
+			return true
 		} else {
+			if w.breaker.failure(sub.URL) {
+				log.Warnf("Webhook delivery failed with status code %d, destination circuit is open", resp.StatusCode)
+				w.deadLetter(ctx, d, "circuit open", log)
+				return false
+			}
 			log.Warnf("Webhook delivery failed with status code %d. Retrying in %v. Retries left: %d", resp.StatusCode, baseDelay, maxRetries-1-i)
 			time.Sleep(baseDelay)
 			baseDelay *= 2 // Экспоненциальная задержка
@@ -119,6 +422,7 @@ func (w *WebhookWorker) processWebhookEvent(ctx context.Context, event WebhookEv
 	}
 
 	log.Errorf("Failed to deliver webhook for event after %d retries.", maxRetries)
+	return false
 }
 
 // generateHMACSHA256 генерирует HMAC-SHA256 подпись для данных
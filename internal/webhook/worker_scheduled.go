@@ -0,0 +1,76 @@
+package webhook
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// scheduledPollInterval - период проверки отложенных событий, срок доставки которых наступил
+	scheduledPollInterval = 5 * time.Second
+	// scheduledBatchSize - сколько наступивших событий переносится в очередь за один вызов
+	scheduledBatchSize = 100
+)
+
+// releaseScheduledScript атомарно переносит наступившие события из множества отложенных в очередь
+// доставки, поэтому несколько воркеров не перенесут одно событие дважды.
+// KEYS[1] - множество отложенных, KEYS[2] - список или поток очереди;
+// ARGV: текущее Unix-время, размер пакета, бэкенд очереди, поле сообщения потока.
+var releaseScheduledScript = redis.NewScript(`
+local due = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, ARGV[2])
+for _, payload in ipairs(due) do
+	redis.call('ZREM', KEYS[1], payload)
+	if ARGV[3] == 'streams' then
+		redis.call('XADD', KEYS[2], '*', ARGV[4], payload)
+	else
+		redis.call('LPUSH', KEYS[2], payload)
+	end
+end
+return #due
+`)
+
+// startScheduled периодически переносит отложенные окном тишины события в очередь доставки.
+// Запускается всегда: события, отложенные до выключения WEBHOOK_QUIET_HOURS, тоже должны быть доставлены.
+func (w *WebhookWorker) startScheduled(ctx context.Context) {
+	scheduled := w.keys.Key(webhookScheduledKey)
+	queue := w.keys.Key(webhookQueueKey)
+	if w.cfg.WebhookQueueBackend == QueueBackendStreams {
+		queue = w.keys.Key(webhookStreamKey)
+	}
+	log := w.logger.WithField("component", "webhook_scheduler")
+
+	go func() {
+		ticker := time.NewTicker(scheduledPollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			// Переносим пакетами, пока наступившие события не закончатся
+			for {
+				now := strconv.FormatInt(time.Now().Unix(), 10)
+				released, err := releaseScheduledScript.Run(ctx, w.redisClient, []string{scheduled, queue},
+					now, scheduledBatchSize, w.cfg.WebhookQueueBackend, streamPayloadField).Int()
+				if err != nil {
+					if ctx.Err() == nil {
+						log.WithError(err).Error("Failed to release scheduled webhook events")
+					}
+					break
+				}
+				if released > 0 {
+					log.WithField("released", released).Info("Released scheduled webhook events after quiet hours")
+				}
+				if released < scheduledBatchSize {
+					break
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,165 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// webhookConsumerGroup - группа потребителей потока вебхуков
+	webhookConsumerGroup = "webhook_workers"
+	// streamReadBlock - максимальное время блокировки XREADGROUP
+	streamReadBlock = 5 * time.Second
+	// streamBatchSize - количество сообщений, читаемых или забираемых за один вызов
+	streamBatchSize = 10
+)
+
+// startStreams обрабатывает очередь на основе Redis Streams.
+// Сообщение подтверждается (XACK) только после завершения его обработки, поэтому при падении
+// воркера во время доставки оно остается в списке ожидающих и будет забрано повторно
+// (XPENDING + XCLAIM) по истечении WEBHOOK_STREAM_CLAIM_IDLE.
+func (w *WebhookWorker) startStreams(ctx context.Context) {
+	stream := w.keys.Key(webhookStreamKey)
+	consumer := streamConsumerName()
+	log := w.logger.WithField("stream", stream).WithField("consumer", consumer)
+
+	w.createConsumerGroup(ctx, stream, log)
+
+	w.running.Add(1)
+	go func() {
+		defer w.running.Done()
+		var lastRecovery time.Time
+		for {
+			select {
+			case <-ctx.Done():
+				w.flushBatch(ctx)
+				log.Info("Stopping webhook worker.")
+				return
+			default:
+				if time.Since(lastRecovery) >= w.cfg.WebhookStreamClaimIdle {
+					w.recoverPending(ctx, stream, consumer)
+					lastRecovery = time.Now()
+				}
+
+				streams, err := w.redisClient.XReadGroup(ctx, &redis.XReadGroupArgs{
+					Group:    webhookConsumerGroup,
+					Consumer: consumer,
+					Streams:  []string{stream, ">"},
+					Count:    streamBatchSize,
+					Block:    streamReadBlock,
+				}).Result()
+				if err != nil {
+					if errors.Is(err, redis.Nil) || errors.Is(err, context.Canceled) {
+						continue // Нет новых сообщений или контекст отменен
+					}
+					if strings.HasPrefix(err.Error(), "NOGROUP") {
+						// Поток или группа удалены (FLUSHDB, перезапуск Redis без персистентности) - создаем заново
+						log.WithError(err).Warn("Webhook consumer group is missing, recreating")
+						w.createConsumerGroup(ctx, stream, log)
+						continue
+					}
+					log.WithError(err).Error("Failed to read webhook events from Redis stream")
+					time.Sleep(w.cfg.WebhookTimeout) // Ждем перед повторной попыткой
+					continue
+				}
+
+				for _, s := range streams {
+					for _, msg := range s.Messages {
+						w.handleStreamMessage(ctx, stream, msg)
+					}
+				}
+			}
+		}
+	}()
+}
+
+// createConsumerGroup создает группу потребителей вместе с потоком; уже существующая группа (BUSYGROUP) не ошибка
+func (w *WebhookWorker) createConsumerGroup(ctx context.Context, stream string, log *logrus.Entry) {
+	err := w.redisClient.XGroupCreateMkStream(ctx, stream, webhookConsumerGroup, "0").Err()
+	if err != nil && !strings.Contains(err.Error(), "BUSYGROUP") {
+		log.WithError(err).Error("Failed to create webhook consumer group")
+	}
+}
+
+// recoverPending забирает сообщения, которые слишком долго не подтверждены другими потребителями, и обрабатывает их
+func (w *WebhookWorker) recoverPending(ctx context.Context, stream, consumer string) {
+	idle := w.cfg.WebhookStreamClaimIdle
+	pending, err := w.redisClient.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: stream,
+		Group:  webhookConsumerGroup,
+		Idle:   idle,
+		Start:  "-",
+		End:    "+",
+		Count:  streamBatchSize,
+	}).Result()
+	if err != nil {
+		if !errors.Is(err, context.Canceled) {
+			w.logger.WithError(err).Error("Failed to list pending webhook events")
+		}
+		return
+	}
+	if len(pending) == 0 {
+		return
+	}
+
+	ids := make([]string, len(pending))
+	for i, p := range pending {
+		ids[i] = p.ID
+	}
+	messages, err := w.redisClient.XClaim(ctx, &redis.XClaimArgs{
+		Stream:   stream,
+		Group:    webhookConsumerGroup,
+		Consumer: consumer,
+		MinIdle:  idle,
+		Messages: ids,
+	}).Result()
+	if err != nil {
+		w.logger.WithError(err).Error("Failed to claim pending webhook events")
+		return
+	}
+
+	w.logger.WithField("count", len(messages)).Warn("Recovered stuck webhook events from stream")
+	for _, msg := range messages {
+		w.handleStreamMessage(ctx, stream, msg)
+	}
+}
+
+// handleStreamMessage обрабатывает одно сообщение потока и подтверждает его после завершения всех доставок.
+// Подтверждение выполняется с контекстом доставки: пакет, доставленный при остановке (см. flushBatch), подтверждается.
+func (w *WebhookWorker) handleStreamMessage(ctx context.Context, stream string, msg redis.XMessage) {
+	ack := func(ctx context.Context) {
+		if ctx.Err() != nil {
+			return // Обработка прервана остановкой, сообщение будет забрано повторно
+		}
+		if err := w.redisClient.XAck(ctx, stream, webhookConsumerGroup, msg.ID).Err(); err != nil {
+			w.logger.WithError(err).WithField("message_id", msg.ID).Error("Failed to acknowledge webhook event")
+		}
+	}
+
+	payload, _ := msg.Values[streamPayloadField].(string)
+	var event WebhookEvent
+	if err := json.Unmarshal([]byte(payload), &event); err != nil {
+		// Некорректное сообщение никогда не будет обработано, подтверждаем его, чтобы не забирать повторно
+		w.logger.WithError(err).WithField("message_id", msg.ID).Error("Failed to unmarshal webhook event from Redis stream")
+		ack(ctx)
+		return
+	}
+	w.submit(ctx, event, payload, ack)
+}
+
+// streamConsumerName возвращает имя потребителя, уникальное для процесса
+func streamConsumerName() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "worker"
+	}
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
+}
@@ -0,0 +1,163 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/shenikar/geo_broadcasting_system/internal/config"
+	"github.com/shenikar/geo_broadcasting_system/internal/models"
+	redisclient "github.com/shenikar/geo_broadcasting_system/pkg/redis"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeliver_RetriesKeepEventID(t *testing.T) {
+	var mu sync.Mutex
+	var eventIDs []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		eventIDs = append(eventIDs, r.Header.Get("X-Webhook-Event-Id"))
+		// Первая попытка завершается ошибкой, как будто подписчик не успел ответить
+		if len(eventIDs) == 1 {
+			w.WriteHeader(http.StatusGatewayTimeout)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	logger := logrus.New()
+	logger.SetOutput(&bytes.Buffer{})
+	worker := &WebhookWorker{
+		logger:     logger,
+		breaker:    newCircuitBreaker(0, 0, nil),
+		cfg:        &config.Config{WebhookMaxRetries: 3},
+		httpClient: server.Client(),
+	}
+
+	delivered := worker.deliver(context.Background(), delivery{
+		sub:      Subscription{URL: server.URL},
+		body:     `{"event_id":"e1"}`,
+		eventIDs: "e1",
+	}, logger.WithField("test", true))
+
+	assert.True(t, delivered)
+	assert.Equal(t, []string{"e1", "e1"}, eventIDs)
+}
+
+func TestDeliver_InvalidURLEndsHalfOpenProbe(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(&bytes.Buffer{})
+	url := "http://[::1"
+	breaker := newCircuitBreaker(1, 0, nil)
+	breaker.failure(url)
+	worker := &WebhookWorker{
+		logger:      logger,
+		breaker:     breaker,
+		cfg:         &config.Config{WebhookMaxRetries: 3, WebhookDeadLetterMaxLen: 10},
+		redisClient: redis.NewClient(&redis.Options{Addr: "localhost:0", MaxRetries: -1}),
+		keys:        redisclient.NewKeyBuilder(""),
+	}
+
+	delivered := worker.deliver(context.Background(), delivery{sub: Subscription{URL: url}, body: `{}`}, logger.WithField("test", true))
+
+	// Неудачная пробная доставка снова размыкает цепь, и следующая проба возможна после cooldown
+	assert.False(t, delivered)
+	assert.Equal(t, CircuitOpen, breaker.circuits[url].State)
+	assert.False(t, breaker.probing[url])
+	assert.True(t, breaker.allow(url))
+}
+
+func TestDeliver_RoutingHeaders(t *testing.T) {
+	var severity, dangerous string
+	var hasSeverity bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		severity, dangerous = r.Header.Get("X-Incident-Severity"), r.Header.Get("X-Event-Dangerous")
+		_, hasSeverity = r.Header["X-Incident-Severity"]
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	logger := logrus.New()
+	logger.SetOutput(&bytes.Buffer{})
+	worker := &WebhookWorker{
+		logger:     logger,
+		breaker:    newCircuitBreaker(0, 0, nil),
+		cfg:        &config.Config{WebhookMaxRetries: 1},
+		httpClient: server.Client(),
+	}
+	event := WebhookEvent{IsDangerous: true, Incidents: []*models.Incident{
+		{Severity: models.SeverityModerate},
+		{Severity: models.SeverityMajor},
+		{Severity: models.SeverityMinor},
+	}}
+
+	body := `{"is_dangerous":true}`
+	assert.True(t, worker.deliver(context.Background(), delivery{
+		sub:       Subscription{URL: server.URL},
+		body:      body,
+		severity:  event.MaxSeverity(),
+		dangerous: event.IsDangerous,
+	}, logger.WithField("test", true)))
+	assert.Equal(t, "major", severity)
+	assert.Equal(t, "true", dangerous)
+
+	// Без уровня опасности у инцидентов заголовок X-Incident-Severity не передается
+	assert.True(t, worker.deliver(context.Background(), delivery{sub: Subscription{URL: server.URL}, body: body}, logger.WithField("test", true)))
+	assert.False(t, hasSeverity)
+	assert.Equal(t, "false", dangerous)
+}
+
+// scriptedRedisHook отвечает на команды без сервера Redis: XREADGROUP завершается ошибкой NOGROUP,
+// а вызовы XGROUP CREATE подсчитываются
+type scriptedRedisHook struct {
+	groupCreates atomic.Int32
+}
+
+func (h *scriptedRedisHook) DialHook(next redis.DialHook) redis.DialHook { return next }
+
+func (h *scriptedRedisHook) ProcessHook(redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		switch cmd.Name() {
+		case "xgroup":
+			h.groupCreates.Add(1)
+		case "xreadgroup":
+			cmd.SetErr(errors.New("NOGROUP No such key 'webhook_stream' or consumer group 'webhook_workers'"))
+		}
+		return cmd.Err()
+	}
+}
+
+func (h *scriptedRedisHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return next
+}
+
+func TestStartStreams_RecreatesMissingGroup(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(&bytes.Buffer{})
+	hook := &scriptedRedisHook{}
+	client := redis.NewClient(&redis.Options{Addr: "localhost:0"})
+	client.AddHook(hook)
+	worker := &WebhookWorker{
+		redisClient: client,
+		keys:        redisclient.NewKeyBuilder(""),
+		logger:      logger,
+		cfg:         &config.Config{WebhookStreamClaimIdle: time.Hour, WebhookTimeout: time.Millisecond},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	worker.startStreams(ctx)
+
+	// Группа создается при запуске и еще раз после ошибки NOGROUP при чтении
+	assert.Eventually(t, func() bool { return hook.groupCreates.Load() >= 2 }, time.Second, 5*time.Millisecond)
+}
@@ -0,0 +1,2 @@
+-- +migrate Down
+ALTER TABLE incidents DROP COLUMN IF EXISTS media_urls;
@@ -0,0 +1,2 @@
+-- +migrate Up
+ALTER TABLE incidents ADD COLUMN media_urls TEXT[] NOT NULL DEFAULT '{}';
@@ -0,0 +1,8 @@
+-- +migrate Up
+CREATE TABLE location_check_incidents (
+    location_check_id BIGINT NOT NULL REFERENCES location_checks (id) ON DELETE CASCADE,
+    incident_id UUID NOT NULL REFERENCES incidents (id) ON DELETE CASCADE,
+    PRIMARY KEY (location_check_id, incident_id)
+);
+
+CREATE INDEX idx_location_check_incidents_incident_id ON location_check_incidents (incident_id);
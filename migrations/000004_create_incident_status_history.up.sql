@@ -0,0 +1,11 @@
+-- +migrate Up
+CREATE TABLE incident_status_history (
+    id BIGSERIAL PRIMARY KEY,
+    incident_id UUID NOT NULL REFERENCES incidents (id) ON DELETE CASCADE,
+    from_status VARCHAR(50) NOT NULL,
+    to_status VARCHAR(50) NOT NULL,
+    changed_by VARCHAR(255) NOT NULL DEFAULT '',
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_incident_status_history_incident_id ON incident_status_history (incident_id);
@@ -0,0 +1,2 @@
+-- +migrate Up
+CREATE INDEX idx_incidents_updated_at ON incidents (updated_at);
@@ -0,0 +1,4 @@
+-- +migrate Down
+ALTER TABLE incidents
+    DROP COLUMN IF EXISTS confidence,
+    DROP COLUMN IF EXISTS source;
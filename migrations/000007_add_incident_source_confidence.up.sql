@@ -0,0 +1,4 @@
+-- +migrate Up
+ALTER TABLE incidents
+    ADD COLUMN source VARCHAR(50) NOT NULL DEFAULT 'official',
+    ADD COLUMN confidence SMALLINT CHECK (confidence BETWEEN 0 AND 100);
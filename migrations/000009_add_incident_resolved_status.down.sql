@@ -0,0 +1,5 @@
+-- +migrate Down
+UPDATE incidents SET status = 'inactive' WHERE status = 'resolved';
+ALTER TABLE incidents
+    DROP CONSTRAINT IF EXISTS incidents_status_check,
+    DROP COLUMN IF EXISTS resolution_reason;
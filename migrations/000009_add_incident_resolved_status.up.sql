@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE incidents
+    ADD COLUMN resolution_reason TEXT,
+    ADD CONSTRAINT incidents_status_check
+        CHECK (status IN ('active', 'inactive', 'draft', 'rejected', 'resolved'));
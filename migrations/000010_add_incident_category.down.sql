@@ -0,0 +1,3 @@
+-- +migrate Down
+ALTER TABLE incidents
+    DROP COLUMN IF EXISTS category;
@@ -0,0 +1,3 @@
+-- +migrate Up
+ALTER TABLE incidents
+    ADD COLUMN category TEXT;
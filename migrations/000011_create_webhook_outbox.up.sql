@@ -0,0 +1,10 @@
+-- +migrate Up
+CREATE TABLE webhook_outbox (
+    id BIGSERIAL PRIMARY KEY,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    sent_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_webhook_outbox_unsent ON webhook_outbox (id) WHERE sent_at IS NULL;
+CREATE INDEX idx_webhook_outbox_sent_at ON webhook_outbox (sent_at) WHERE sent_at IS NOT NULL;
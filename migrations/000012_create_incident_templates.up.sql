@@ -0,0 +1,11 @@
+-- +migrate Up
+CREATE TABLE incident_templates (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(255) NOT NULL UNIQUE,
+    description TEXT NOT NULL DEFAULT '',
+    category TEXT,
+    radius_meters INTEGER NOT NULL DEFAULT 0 CHECK (radius_meters >= 0),
+    source VARCHAR(32),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,5 @@
+-- +migrate Down
+DROP INDEX IF EXISTS idx_incidents_external_id;
+
+ALTER TABLE incidents
+    DROP COLUMN IF EXISTS external_id;
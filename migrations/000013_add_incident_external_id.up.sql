@@ -0,0 +1,6 @@
+-- +migrate Up
+ALTER TABLE incidents
+    ADD COLUMN external_id VARCHAR(255);
+
+-- Внешний идентификатор уникален среди инцидентов, у которых он задан
+CREATE UNIQUE INDEX idx_incidents_external_id ON incidents (external_id) WHERE external_id IS NOT NULL;
@@ -0,0 +1,8 @@
+-- +migrate Up
+CREATE TABLE incident_versions (
+    incident_id UUID NOT NULL REFERENCES incidents (id) ON DELETE CASCADE,
+    version INT NOT NULL,
+    snapshot JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (incident_id, version)
+);
@@ -0,0 +1,3 @@
+-- +migrate Down
+ALTER TABLE location_checks
+    DROP COLUMN IF EXISTS authenticated;
@@ -0,0 +1,4 @@
+-- +migrate Up
+-- Признак проверки, выполненной клиентом с API-ключом (user_id подтвержден клиентом)
+ALTER TABLE location_checks
+    ADD COLUMN authenticated BOOLEAN NOT NULL DEFAULT FALSE;
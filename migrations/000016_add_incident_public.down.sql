@@ -0,0 +1,4 @@
+-- +migrate Down
+DROP INDEX IF EXISTS idx_incidents_public_active;
+ALTER TABLE incidents
+    DROP COLUMN IF EXISTS public;
@@ -0,0 +1,6 @@
+-- +migrate Up
+-- Признак публикации инцидента в открытой ленте GET /public/incidents
+ALTER TABLE incidents
+    ADD COLUMN public BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX idx_incidents_public_active ON incidents (updated_at DESC) WHERE public AND status = 'active';
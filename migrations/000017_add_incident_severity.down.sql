@@ -0,0 +1,5 @@
+-- +migrate Down
+ALTER TABLE incidents
+    DROP CONSTRAINT IF EXISTS incidents_severity_check,
+    DROP COLUMN IF EXISTS escalated_at,
+    DROP COLUMN IF EXISTS severity;
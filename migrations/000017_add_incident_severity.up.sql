@@ -0,0 +1,7 @@
+-- +migrate Up
+-- Уровень опасности инцидента; escalated_at - время последнего повышения уровня
+ALTER TABLE incidents
+    ADD COLUMN severity VARCHAR(16) NOT NULL DEFAULT 'minor',
+    ADD COLUMN escalated_at TIMESTAMPTZ,
+    ADD CONSTRAINT incidents_severity_check
+        CHECK (severity IN ('minor', 'moderate', 'major', 'critical'));
@@ -0,0 +1,13 @@
+-- +migrate Up
+-- Журнал слияний дубликатов: инцидент merged_id деактивирован и поглощен инцидентом canonical_id
+CREATE TABLE incident_merges (
+    id BIGSERIAL PRIMARY KEY,
+    canonical_id UUID NOT NULL REFERENCES incidents (id) ON DELETE CASCADE,
+    merged_id UUID NOT NULL REFERENCES incidents (id) ON DELETE CASCADE,
+    redirected_checks INT NOT NULL DEFAULT 0,
+    merged_by VARCHAR(255) NOT NULL DEFAULT '',
+    merged_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_incident_merges_canonical_id ON incident_merges (canonical_id);
+CREATE INDEX idx_incident_merges_merged_id ON incident_merges (merged_id);
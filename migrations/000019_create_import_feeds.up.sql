@@ -0,0 +1,18 @@
+-- +migrate Up
+-- Зарегистрированные GeoJSON-ленты, периодически синхронизируемые с инцидентами
+CREATE TABLE import_feeds (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    url TEXT NOT NULL UNIQUE,
+    created_by VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    -- Внешние ID объектов ленты при последней успешной синхронизации; пропавшие из ленты инциденты деактивируются
+    external_ids TEXT[] NOT NULL DEFAULT '{}',
+    -- Время, когда синхронизацию ленты взял экземпляр сервиса; до истечения интервала ее не берут другие
+    sync_started_at TIMESTAMPTZ,
+    last_synced_at TIMESTAMPTZ,
+    last_status VARCHAR(50) NOT NULL DEFAULT '',
+    last_error TEXT NOT NULL DEFAULT '',
+    last_created INT NOT NULL DEFAULT 0,
+    last_updated INT NOT NULL DEFAULT 0,
+    last_deactivated INT NOT NULL DEFAULT 0
+);
@@ -0,0 +1,14 @@
+-- +migrate Up
+-- Вложения инцидентов: сам файл хранится в S3-совместимом хранилище под ключом object_key.
+-- Запись создается при выдаче presigned URL для загрузки, до того как клиент загрузит файл.
+CREATE TABLE incident_attachments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    incident_id UUID NOT NULL REFERENCES incidents (id) ON DELETE CASCADE,
+    object_key TEXT NOT NULL UNIQUE,
+    filename VARCHAR(255) NOT NULL,
+    content_type VARCHAR(255) NOT NULL,
+    created_by VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_incident_attachments_incident_id ON incident_attachments (incident_id, created_at);
@@ -0,0 +1,8 @@
+-- +migrate Up
+-- Число просмотров инцидентов (GET /incidents/{id}). Просмотры сначала копятся в счетчиках Redis
+-- и периодически переносятся сюда (INCIDENT_VIEW_FLUSH_INTERVAL).
+CREATE TABLE incident_views (
+    incident_id UUID PRIMARY KEY REFERENCES incidents (id) ON DELETE CASCADE,
+    views BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
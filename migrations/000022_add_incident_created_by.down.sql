@@ -0,0 +1,5 @@
+-- +migrate Down
+DROP INDEX IF EXISTS idx_incidents_created_by;
+
+ALTER TABLE incidents
+    DROP COLUMN IF EXISTS created_by;
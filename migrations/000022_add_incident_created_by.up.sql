@@ -0,0 +1,6 @@
+-- +migrate Up
+-- Метка API-ключа, создавшего инцидент; у инцидентов, созданных до появления колонки, не задана
+ALTER TABLE incidents
+    ADD COLUMN created_by VARCHAR(255);
+
+CREATE INDEX idx_incidents_created_by ON incidents (created_by, created_at DESC) WHERE created_by IS NOT NULL;
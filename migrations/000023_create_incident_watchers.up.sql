@@ -0,0 +1,12 @@
+-- +migrate Up
+-- Наблюдатели инцидентов: адреса, получающие вебхуки о каждом изменении конкретного инцидента
+CREATE TABLE incident_watchers (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    incident_id UUID NOT NULL REFERENCES incidents (id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    -- Секрет подписи HMAC; пусто - запрос отправляется без подписи
+    secret TEXT NOT NULL DEFAULT '',
+    created_by VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (incident_id, url)
+);
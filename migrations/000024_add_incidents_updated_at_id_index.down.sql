@@ -0,0 +1,3 @@
+-- +migrate Down
+CREATE INDEX idx_incidents_updated_at ON incidents (updated_at);
+DROP INDEX IF EXISTS idx_incidents_updated_at_id;
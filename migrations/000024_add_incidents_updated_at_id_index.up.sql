@@ -0,0 +1,4 @@
+-- +migrate Up
+-- Лента изменений читается по ключу (updated_at, id); индекс по обоим столбцам заменяет индекс по updated_at
+CREATE INDEX idx_incidents_updated_at_id ON incidents (updated_at, id);
+DROP INDEX IF EXISTS idx_incidents_updated_at;
@@ -0,0 +1,5 @@
+-- +migrate Down
+UPDATE incidents AS i
+SET external_id = substr(i.external_id, length(f.id::text) + 2)
+FROM import_feeds AS f
+WHERE i.external_id LIKE f.id::text || ':%';
@@ -0,0 +1,7 @@
+-- +migrate Up
+-- Внешние ID инцидентов, синхронизируемых из лент, переносятся в пространство ленты: "<ID ленты>:<ID объекта>".
+-- Если объект с тем же ID был в нескольких лентах, инцидент остается за одной из них.
+UPDATE incidents AS i
+SET external_id = f.id::text || ':' || i.external_id
+FROM import_feeds AS f
+WHERE i.external_id = ANY(f.external_ids);
@@ -0,0 +1,267 @@
+// Package client - типизированный Go-клиент для API v1 сервиса геооповещений.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	v1 "github.com/shenikar/geo_broadcasting_system/internal/handler/http/v1"
+)
+
+// DTO API v1, используемые клиентом
+type (
+	CreateIncidentRequest      = v1.CreateIncidentRequest
+	UpdateIncidentRequest      = v1.UpdateIncidentRequest
+	IncidentResponse           = v1.IncidentResponse
+	LocationCheckRequest       = v1.LocationCheckRequest
+	DeactivateIncidentRequest  = v1.DeactivateIncidentRequest
+	GeofenceValidationResponse = v1.GeofenceValidationResponse
+	FieldError                 = v1.FieldError
+)
+
+const (
+	// defaultTimeout - таймаут HTTP-клиента по умолчанию
+	defaultTimeout = 10 * time.Second
+	// defaultMaxRetries - число повторов по умолчанию после первой попытки
+	defaultMaxRetries = 2
+	// defaultRetryDelay - базовая задержка экспоненциального backoff
+	defaultRetryDelay = 200 * time.Millisecond
+	// maxRetryDelay - верхняя граница задержки между попытками, в том числе из Retry-After
+	maxRetryDelay = 30 * time.Second
+)
+
+// Client - клиент API v1. Безопасен для конкурентного использования.
+type Client struct {
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+	maxRetries int
+	retryDelay time.Duration
+}
+
+// Option настраивает Client
+type Option func(*Client)
+
+// WithHTTPClient задает HTTP-клиент (транспорт, таймауты, прокси)
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithRetries задает число повторов после первой попытки и базовую задержку между ними. 0 повторов отключает их.
+func WithRetries(maxRetries int, baseDelay time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.retryDelay = baseDelay
+	}
+}
+
+// New создает клиент. baseURL - адрес сервиса с префиксом API, например http://localhost:8080/api/v1.
+func New(baseURL, apiKey string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		apiKey:     apiKey,
+		httpClient: &http.Client{Timeout: defaultTimeout},
+		maxRetries: defaultMaxRetries,
+		retryDelay: defaultRetryDelay,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// ListIncidentsOptions - параметры постраничного списка инцидентов. Нулевые значения не передаются.
+type ListIncidentsOptions struct {
+	Page          int
+	PageSize      int
+	MinConfidence int
+}
+
+// CreateIncident создает инцидент
+func (c *Client) CreateIncident(ctx context.Context, req CreateIncidentRequest) (*IncidentResponse, error) {
+	var incident IncidentResponse
+	if err := c.do(ctx, http.MethodPost, "/incidents", nil, req, &incident); err != nil {
+		return nil, err
+	}
+	return &incident, nil
+}
+
+// ValidateIncident проверяет зону инцидента без сохранения
+func (c *Client) ValidateIncident(ctx context.Context, req CreateIncidentRequest) (*GeofenceValidationResponse, error) {
+	var result GeofenceValidationResponse
+	if err := c.do(ctx, http.MethodPost, "/incidents/validate", nil, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetIncident возвращает инцидент по ID
+func (c *Client) GetIncident(ctx context.Context, id uuid.UUID) (*IncidentResponse, error) {
+	var incident IncidentResponse
+	if err := c.do(ctx, http.MethodGet, "/incidents/"+id.String(), nil, nil, &incident); err != nil {
+		return nil, err
+	}
+	return &incident, nil
+}
+
+// UpdateIncident обновляет инцидент
+func (c *Client) UpdateIncident(ctx context.Context, id uuid.UUID, req UpdateIncidentRequest) error {
+	return c.do(ctx, http.MethodPut, "/incidents/"+id.String(), nil, req, nil)
+}
+
+// DeactivateIncident деактивирует инцидент
+func (c *Client) DeactivateIncident(ctx context.Context, id uuid.UUID) error {
+	return c.do(ctx, http.MethodDelete, "/incidents/"+id.String(), nil, nil, nil)
+}
+
+// ResolveIncident переводит инцидент в статус resolved с необязательной причиной
+func (c *Client) ResolveIncident(ctx context.Context, id uuid.UUID, reason string) error {
+	req := DeactivateIncidentRequest{Resolved: true, Reason: reason}
+	return c.do(ctx, http.MethodDelete, "/incidents/"+id.String(), nil, req, nil)
+}
+
+// ListIncidents возвращает страницу инцидентов
+func (c *Client) ListIncidents(ctx context.Context, opts ListIncidentsOptions) ([]IncidentResponse, error) {
+	query := url.Values{}
+	if opts.Page > 0 {
+		query.Set("page", strconv.Itoa(opts.Page))
+	}
+	if opts.PageSize > 0 {
+		query.Set("pageSize", strconv.Itoa(opts.PageSize))
+	}
+	if opts.MinConfidence > 0 {
+		query.Set("min_confidence", strconv.Itoa(opts.MinConfidence))
+	}
+
+	incidents := make([]IncidentResponse, 0)
+	if err := c.do(ctx, http.MethodGet, "/incidents", query, nil, &incidents); err != nil {
+		return nil, err
+	}
+	return incidents, nil
+}
+
+// CheckLocation проверяет, находится ли пользователь в опасной зоне, и возвращает совпавшие инциденты
+func (c *Client) CheckLocation(ctx context.Context, req LocationCheckRequest) ([]IncidentResponse, error) {
+	incidents := make([]IncidentResponse, 0)
+	if err := c.do(ctx, http.MethodPost, "/location/check", nil, req, &incidents); err != nil {
+		return nil, err
+	}
+	return incidents, nil
+}
+
+// do выполняет запрос с повторами и декодирует ответ в out (если out не nil).
+// GET повторяется при сетевых ошибках и ответах 429/502/503/504; остальные методы - только при 429 и 503,
+// когда сервер гарантированно не выполнил запрос. Заголовок Retry-After учитывается.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out any) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return fmt.Errorf("client: failed to marshal request body: %w", err)
+		}
+	}
+
+	endpoint := c.baseURL + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := c.send(ctx, method, endpoint, payload)
+		if err != nil {
+			if ctx.Err() != nil || method != http.MethodGet || attempt >= c.maxRetries {
+				return fmt.Errorf("client: %s %s: %w", method, path, err)
+			}
+			if err := c.wait(ctx, attempt, 0); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if resp.StatusCode < 300 {
+			return decodeResponse(resp, out)
+		}
+
+		apiErr := decodeAPIError(resp)
+		if !retryableStatus(method, resp.StatusCode) || attempt >= c.maxRetries {
+			return apiErr
+		}
+		if err := c.wait(ctx, attempt, apiErr.RetryAfter); err != nil {
+			return err
+		}
+	}
+}
+
+// send отправляет один HTTP-запрос
+func (c *Client) send(ctx context.Context, method, endpoint string, payload []byte) (*http.Response, error) {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		return nil, err
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("X-API-Key", c.apiKey)
+	}
+	return c.httpClient.Do(req)
+}
+
+// wait ждет перед повторной попыткой: Retry-After, если сервер его указал, иначе экспоненциальный backoff
+func (c *Client) wait(ctx context.Context, attempt int, retryAfter time.Duration) error {
+	delay := retryAfter
+	if delay <= 0 {
+		delay = c.retryDelay << attempt
+	}
+	delay = min(delay, maxRetryDelay)
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// retryableStatus сообщает, можно ли повторить запрос после ответа с указанным статусом
+func retryableStatus(method string, status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		return method == http.MethodGet
+	}
+	return false
+}
+
+// decodeResponse декодирует успешный ответ и закрывает тело
+func decodeResponse(resp *http.Response, out any) error {
+	defer resp.Body.Close()
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("client: failed to decode response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestClient создает клиент к тестовому серверу с быстрыми повторами
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return New(server.URL+"/api/v1/", "test-api-key", WithRetries(2, time.Millisecond))
+}
+
+func TestCreateIncident_Success(t *testing.T) {
+	incidentID := uuid.New()
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/v1/incidents", r.URL.Path)
+		assert.Equal(t, "test-api-key", r.Header.Get("X-API-Key"))
+
+		var req CreateIncidentRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "Пожар", req.Name)
+
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(IncidentResponse{ID: incidentID, Name: req.Name, Status: "active"})
+	})
+
+	incident, err := client.CreateIncident(context.Background(), CreateIncidentRequest{
+		Name:         "Пожар",
+		Latitude:     55.75,
+		Longitude:    37.61,
+		RadiusMeters: 500,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, incidentID, incident.ID)
+	assert.Equal(t, "active", incident.Status)
+}
+
+func TestListIncidents_QueryParams(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "2", r.URL.Query().Get("page"))
+		assert.Equal(t, "50", r.URL.Query().Get("pageSize"))
+		assert.Equal(t, "80", r.URL.Query().Get("min_confidence"))
+		_ = json.NewEncoder(w).Encode([]IncidentResponse{{Name: "A"}, {Name: "B"}})
+	})
+
+	incidents, err := client.ListIncidents(context.Background(), ListIncidentsOptions{Page: 2, PageSize: 50, MinConfidence: 80})
+
+	require.NoError(t, err)
+	assert.Len(t, incidents, 2)
+}
+
+func TestGetIncident_NotFound(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error":"incident not found"}`))
+	})
+
+	_, err := client.GetIncident(context.Background(), uuid.New())
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrNotFound)
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, "incident not found", apiErr.Message)
+}
+
+func TestCheckLocation_ValidationError(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/location/check", r.URL.Path)
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":"validation failed","details":[{"field":"user_id","tag":"required","message":"user_id is required"}]}`))
+	})
+
+	_, err := client.CheckLocation(context.Background(), LocationCheckRequest{Latitude: 50, Longitude: 50})
+
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.ErrorIs(t, err, ErrBadRequest)
+	require.Len(t, apiErr.Details, 1)
+	assert.Equal(t, "user_id", apiErr.Details[0].Field)
+}
+
+func TestRetriesOnServiceUnavailable(t *testing.T) {
+	var calls atomic.Int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"error":"service temporarily unavailable","code":"SERVICE_UNAVAILABLE"}`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(IncidentResponse{Name: "Пожар"})
+	})
+
+	incident, err := client.CreateIncident(context.Background(), CreateIncidentRequest{Name: "Пожар"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Пожар", incident.Name)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestRetries_Exhausted(t *testing.T) {
+	var calls atomic.Int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`{"error":"service temporarily unavailable","code":"SERVICE_UNAVAILABLE"}`))
+	})
+
+	_, err := client.ListIncidents(context.Background(), ListIncidentsOptions{})
+
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.True(t, errors.Is(err, ErrUnavailable))
+	assert.Equal(t, "SERVICE_UNAVAILABLE", apiErr.Code)
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestNoRetryForNonIdempotentOnBadGateway(t *testing.T) {
+	var calls atomic.Int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	})
+
+	_, err := client.CreateIncident(context.Background(), CreateIncidentRequest{Name: "Пожар"})
+
+	require.Error(t, err)
+	// Ответ прокси не гарантирует, что инцидент не создан, поэтому POST не повторяется
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestDeactivateIncident_NoContent(t *testing.T) {
+	id := uuid.New()
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+		assert.Equal(t, "/api/v1/incidents/"+id.String(), r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	require.NoError(t, client.DeactivateIncident(context.Background(), id))
+}
+
+func TestResolveIncident_SendsResolution(t *testing.T) {
+	id := uuid.New()
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+		var req DeactivateIncidentRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.True(t, req.Resolved)
+		assert.Equal(t, "вода ушла", req.Reason)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	require.NoError(t, client.ResolveIncident(context.Background(), id, "вода ушла"))
+}
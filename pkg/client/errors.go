@@ -0,0 +1,74 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Ошибки, с которыми можно сравнивать APIError через errors.Is
+var (
+	ErrBadRequest   = &APIError{StatusCode: http.StatusBadRequest}
+	ErrUnauthorized = &APIError{StatusCode: http.StatusUnauthorized}
+	ErrForbidden    = &APIError{StatusCode: http.StatusForbidden}
+	ErrNotFound     = &APIError{StatusCode: http.StatusNotFound}
+	ErrConflict     = &APIError{StatusCode: http.StatusConflict}
+	ErrUnavailable  = &APIError{StatusCode: http.StatusServiceUnavailable}
+)
+
+// maxErrorBodyBytes - сколько байт тела ответа с ошибкой читается для разбора
+const maxErrorBodyBytes = 64 << 10
+
+// APIError - ответ API с кодом статуса 4xx/5xx
+type APIError struct {
+	StatusCode int
+	// Message - текст из поля error ответа
+	Message string
+	// Code - машиночитаемый код ошибки, если сервер его вернул (например, SERVICE_UNAVAILABLE)
+	Code string
+	// Details - ошибки валидации по полям запроса
+	Details []FieldError
+	// RetryAfter - пауза перед повтором из заголовка Retry-After
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("api error: status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("api error: status %d: %s", e.StatusCode, e.Message)
+}
+
+// Is сравнивает ошибки по коду статуса, чтобы работало errors.Is(err, client.ErrNotFound)
+func (e *APIError) Is(target error) bool {
+	t, ok := target.(*APIError)
+	return ok && t.StatusCode == e.StatusCode
+}
+
+// decodeAPIError разбирает ответ с ошибкой и закрывает тело
+func decodeAPIError(resp *http.Response) *APIError {
+	defer resp.Body.Close()
+
+	apiErr := &APIError{StatusCode: resp.StatusCode}
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		apiErr.RetryAfter = time.Duration(seconds) * time.Second
+	}
+
+	var body struct {
+		Error   string       `json:"error"`
+		Code    string       `json:"code"`
+		Details []FieldError `json:"details"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxErrorBodyBytes)).Decode(&body); err == nil {
+		apiErr.Message = body.Error
+		apiErr.Code = body.Code
+		apiErr.Details = body.Details
+	}
+	if apiErr.Message == "" {
+		apiErr.Message = http.StatusText(resp.StatusCode)
+	}
+	return apiErr
+}
@@ -0,0 +1,125 @@
+package geo
+
+import (
+	"errors"
+	"math"
+)
+
+// Point - точка в географических координатах (градусы WGS 84)
+type Point struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+}
+
+// BBox - прямоугольная область. Если MinLon больше MaxLon, область пересекает 180-й меридиан.
+type BBox struct {
+	MinLat float64 `json:"min_lat"`
+	MinLon float64 `json:"min_lon"`
+	MaxLat float64 `json:"max_lat"`
+	MaxLon float64 `json:"max_lon"`
+}
+
+// Validate проверяет, что границы области являются корректными координатами
+func (b BBox) Validate() error {
+	if b.MinLat < -90 || b.MaxLat > 90 || b.MinLat > b.MaxLat {
+		return errors.New("bbox latitudes must satisfy -90 <= min_lat <= max_lat <= 90")
+	}
+	if b.MinLon < -180 || b.MinLon > 180 || b.MaxLon < -180 || b.MaxLon > 180 {
+		return errors.New("bbox longitudes must be between -180 and 180")
+	}
+	return nil
+}
+
+// CrossesAntimeridian сообщает, пересекает ли область 180-й меридиан
+func (b BBox) CrossesAntimeridian() bool {
+	return b.MinLon > b.MaxLon
+}
+
+// SplitAntimeridian возвращает область одним прямоугольником, а пересекающую 180-й меридиан - двумя частями
+// по обе стороны от него. Части можно передавать в запросы, не знающие о переходе долготы через ±180.
+func (b BBox) SplitAntimeridian() []BBox {
+	if !b.CrossesAntimeridian() {
+		return []BBox{b}
+	}
+	east, west := b, b
+	east.MaxLon, west.MinLon = 180, -180
+	return []BBox{east, west}
+}
+
+// Contains сообщает, находится ли точка внутри области (включая границу)
+func (b BBox) Contains(p Point) bool {
+	if p.Lat < b.MinLat || p.Lat > b.MaxLat {
+		return false
+	}
+	if b.MinLon <= b.MaxLon {
+		return p.Lon >= b.MinLon && p.Lon <= b.MaxLon
+	}
+	return p.Lon >= b.MinLon || p.Lon <= b.MaxLon
+}
+
+// Polygon - простой многоугольник, заданный вершинами по порядку (замыкать контур не требуется).
+// Стороны считаются отрезками на плоскости широта/долгота, что достаточно точно для регионов
+// размером с город или область. Сторона, концы которой отстоят по долготе больше чем на 180°,
+// считается проходящей через 180-й меридиан.
+type Polygon []Point
+
+// Validate проверяет, что многоугольник содержит не меньше трех вершин
+func (pg Polygon) Validate() error {
+	if len(pg) < 3 {
+		return errors.New("polygon must have at least 3 vertices")
+	}
+	return nil
+}
+
+// CrossesAntimeridian сообщает, пересекает ли многоугольник 180-й меридиан
+func (pg Polygon) CrossesAntimeridian() bool {
+	for i, j := 0, len(pg)-1; i < len(pg); j, i = i, i+1 {
+		if math.Abs(pg[i].Lon-pg[j].Lon) > 180 {
+			return true
+		}
+	}
+	return false
+}
+
+// Contains сообщает, находится ли точка внутри многоугольника (алгоритм трассировки луча).
+// У многоугольника, пересекающего 180-й меридиан, западные долготы сдвигаются на 360°,
+// чтобы контур стал непрерывным.
+func (pg Polygon) Contains(p Point) bool {
+	if pg.CrossesAntimeridian() {
+		shifted := make(Polygon, len(pg))
+		for i, vertex := range pg {
+			shifted[i] = Point{Lat: vertex.Lat, Lon: unwrapLon(vertex.Lon)}
+		}
+		pg, p = shifted, Point{Lat: p.Lat, Lon: unwrapLon(p.Lon)}
+	}
+
+	inside := false
+	for i, j := 0, len(pg)-1; i < len(pg); j, i = i, i+1 {
+		a, b := pg[i], pg[j]
+		if (a.Lat > p.Lat) != (b.Lat > p.Lat) &&
+			p.Lon < (b.Lon-a.Lon)*(p.Lat-a.Lat)/(b.Lat-a.Lat)+a.Lon {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// unwrapLon переводит западную долготу в диапазон (180, 360), оставляя восточную без изменений
+func unwrapLon(lon float64) float64 {
+	if lon < 0 {
+		return lon + 360
+	}
+	return lon
+}
+
+// NormalizeLon приводит долготу к диапазону [-180, 180]
+func NormalizeLon(lon float64) float64 {
+	if lon >= -180 && lon <= 180 {
+		return lon
+	}
+	lon = math.Mod(lon+180, 360)
+	if lon < 0 {
+		lon += 360
+	}
+	return lon - 180
+}
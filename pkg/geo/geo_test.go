@@ -0,0 +1,167 @@
+package geo
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBBox_Contains(t *testing.T) {
+	moscow := BBox{MinLat: 55.5, MinLon: 37.3, MaxLat: 56.0, MaxLon: 37.9}
+	assert.True(t, moscow.Contains(Point{Lat: 55.75, Lon: 37.61}))
+	assert.True(t, moscow.Contains(Point{Lat: 55.5, Lon: 37.3}))
+	assert.False(t, moscow.Contains(Point{Lat: 59.93, Lon: 30.31}))
+
+	// Область, пересекающая 180-й меридиан (Чукотка)
+	chukotka := BBox{MinLat: 62, MinLon: 170, MaxLat: 70, MaxLon: -170}
+	assert.True(t, chukotka.Contains(Point{Lat: 65, Lon: 179}))
+	assert.True(t, chukotka.Contains(Point{Lat: 65, Lon: -175}))
+	assert.False(t, chukotka.Contains(Point{Lat: 65, Lon: 0}))
+}
+
+func TestBBox_Validate(t *testing.T) {
+	assert.NoError(t, BBox{MinLat: 10, MinLon: 170, MaxLat: 20, MaxLon: -170}.Validate())
+	assert.Error(t, BBox{MinLat: 20, MaxLat: 10}.Validate())
+	assert.Error(t, BBox{MinLat: -91, MaxLat: 10}.Validate())
+	assert.Error(t, BBox{MinLon: 190}.Validate())
+}
+
+func TestPolygon_Contains(t *testing.T) {
+	// Треугольник вокруг центра Москвы
+	triangle := Polygon{
+		{Lat: 55.6, Lon: 37.4},
+		{Lat: 55.9, Lon: 37.6},
+		{Lat: 55.6, Lon: 37.8},
+	}
+	assert.True(t, triangle.Contains(Point{Lat: 55.7, Lon: 37.6}))
+	assert.False(t, triangle.Contains(Point{Lat: 55.85, Lon: 37.45}))
+	assert.False(t, triangle.Contains(Point{Lat: 59.93, Lon: 30.31}))
+
+	// Невыпуклый многоугольник в форме буквы П
+	uShape := Polygon{
+		{Lat: 0, Lon: 0}, {Lat: 3, Lon: 0}, {Lat: 3, Lon: 1}, {Lat: 1, Lon: 1},
+		{Lat: 1, Lon: 2}, {Lat: 3, Lon: 2}, {Lat: 3, Lon: 3}, {Lat: 0, Lon: 3},
+	}
+	assert.True(t, uShape.Contains(Point{Lat: 2, Lon: 0.5}))
+	assert.False(t, uShape.Contains(Point{Lat: 2, Lon: 1.5}))
+	assert.True(t, uShape.Contains(Point{Lat: 0.5, Lon: 1.5}))
+}
+
+func TestPolygon_Validate(t *testing.T) {
+	assert.Error(t, Polygon{{Lat: 0, Lon: 0}, {Lat: 1, Lon: 1}}.Validate())
+	assert.NoError(t, Polygon{{Lat: 0, Lon: 0}, {Lat: 1, Lon: 1}, {Lat: 0, Lon: 1}}.Validate())
+}
+
+func TestGeohash(t *testing.T) {
+	hash, cell := Geohash(Point{Lat: 57.64911, Lon: 10.40744}, 11)
+	assert.Equal(t, "u4pruydqqvj", hash)
+	assert.True(t, cell.Contains(Point{Lat: 57.64911, Lon: 10.40744}))
+
+	hash, cell = Geohash(Point{Lat: 42.6, Lon: -5.6}, 5)
+	assert.Equal(t, "ezs42", hash)
+	assert.InDelta(t, 0.0439, cell.MaxLon-cell.MinLon, 0.0001)
+
+	// Соседние точки внутри одной ячейки получают одинаковый geohash
+	a, _ := Geohash(Point{Lat: 55.7512, Lon: 37.6184}, 6)
+	b, _ := Geohash(Point{Lat: 55.7515, Lon: 37.6190}, 6)
+	assert.Equal(t, a, b)
+}
+
+func TestDistanceMeters(t *testing.T) {
+	moscow := Point{Lat: 55.7558, Lon: 37.6173}
+	petersburg := Point{Lat: 59.9343, Lon: 30.3351}
+	assert.InDelta(t, 634000, DistanceMeters(moscow, petersburg), 2000)
+	assert.Zero(t, DistanceMeters(moscow, moscow))
+
+	// Градус долготы сжимается с широтой: на 69.65° с.ш. 0.01° - около 387 м, а не 1.1 км
+	assert.InDelta(t, 387, DistanceMeters(Point{Lat: 69.65, Lon: 18.95}, Point{Lat: 69.65, Lon: 18.96}), 2)
+}
+
+func TestGeodesicDistanceMeters(t *testing.T) {
+	// Контрольный пример Винсенти: Flinders Peak - Buninyong, 54972.271 м
+	flinders := Point{Lat: -(37 + 57/60.0 + 3.72030/3600), Lon: 144 + 25/60.0 + 29.52440/3600}
+	buninyong := Point{Lat: -(37 + 39/60.0 + 10.15610/3600), Lon: 143 + 55/60.0 + 35.38390/3600}
+	assert.InDelta(t, 54972.271, GeodesicDistanceMeters(flinders, buninyong), 0.001)
+	assert.Zero(t, GeodesicDistanceMeters(flinders, flinders))
+
+	// Градус меридиана у экватора на эллипсоиде короче, чем на сфере: 110574 м против 111195 м
+	assert.InDelta(t, 110574.4, GeodesicDistanceMeters(Point{Lat: 0, Lon: 10}, Point{Lat: 1, Lon: 10}), 0.5)
+
+	// Для антиподальных точек итерации не сходятся, и используется расстояние по большому кругу
+	assert.InDelta(t, math.Pi*earthRadiusMeters, GeodesicDistanceMeters(Point{Lat: 0, Lon: 0}, Point{Lat: 0, Lon: 180}), 30000)
+}
+
+func TestCircleAreaSqMeters(t *testing.T) {
+	assert.InDelta(t, 3141592.65, CircleAreaSqMeters(1000), 0.01)
+	assert.Zero(t, CircleAreaSqMeters(0))
+}
+
+func TestCircleOverlapAreaSqMeters(t *testing.T) {
+	// Круги не пересекаются или касаются
+	assert.Zero(t, CircleOverlapAreaSqMeters(300, 100, 200))
+	assert.Zero(t, CircleOverlapAreaSqMeters(500, 100, 200))
+	// Малый круг внутри большого
+	assert.InDelta(t, CircleAreaSqMeters(100), CircleOverlapAreaSqMeters(50, 100, 500), 0.01)
+	// Равные круги, центр каждого на границе другого: 2π/3·r² - √3/2·r²
+	r := 100.0
+	assert.InDelta(t, (2*math.Pi/3-math.Sqrt(3)/2)*r*r, CircleOverlapAreaSqMeters(r, r, r), 0.01)
+}
+
+func TestRegionPolicy_Permits(t *testing.T) {
+	russia := Region{BBox: &BBox{MinLat: 41, MinLon: 19, MaxLat: 82, MaxLon: -169}}
+	kremlin := Region{Polygon: Polygon{
+		{Lat: 55.74, Lon: 37.60}, {Lat: 55.76, Lon: 37.60}, {Lat: 55.76, Lon: 37.63}, {Lat: 55.74, Lon: 37.63},
+	}}
+
+	// Пустая политика допускает любые координаты
+	assert.True(t, RegionPolicy{}.Permits(Point{Lat: -33.86, Lon: 151.2}))
+
+	policy := RegionPolicy{Allow: []Region{russia}, Deny: []Region{kremlin}}
+	assert.True(t, policy.Permits(Point{Lat: 59.93, Lon: 30.31}))
+	assert.False(t, policy.Permits(Point{Lat: 55.75, Lon: 37.615}))
+	assert.False(t, policy.Permits(Point{Lat: 48.85, Lon: 2.35}))
+
+	denyOnly := RegionPolicy{Deny: []Region{kremlin}}
+	assert.True(t, denyOnly.Permits(Point{Lat: 48.85, Lon: 2.35}))
+	assert.False(t, denyOnly.Permits(Point{Lat: 55.75, Lon: 37.615}))
+}
+
+func TestRegion_Validate(t *testing.T) {
+	assert.NoError(t, (&Region{BBox: &BBox{MinLat: 10, MaxLat: 20}}).Validate())
+	assert.Error(t, (&Region{}).Validate())
+	assert.Error(t, (&Region{BBox: &BBox{}, Polygon: Polygon{{}, {}, {}}}).Validate())
+}
+
+func TestBBox_SplitAntimeridian(t *testing.T) {
+	moscow := BBox{MinLat: 55.5, MinLon: 37.3, MaxLat: 56.0, MaxLon: 37.9}
+	assert.False(t, moscow.CrossesAntimeridian())
+	assert.Equal(t, []BBox{moscow}, moscow.SplitAntimeridian())
+
+	fiji := BBox{MinLat: -19, MinLon: 177, MaxLat: -16, MaxLon: -179}
+	assert.True(t, fiji.CrossesAntimeridian())
+	assert.Equal(t, []BBox{
+		{MinLat: -19, MinLon: 177, MaxLat: -16, MaxLon: 180},
+		{MinLat: -19, MinLon: -180, MaxLat: -16, MaxLon: -179},
+	}, fiji.SplitAntimeridian())
+}
+
+func TestPolygon_ContainsAcrossAntimeridian(t *testing.T) {
+	// Четырехугольник вокруг островов Фиджи, пересекающий 180-й меридиан
+	fiji := Polygon{
+		{Lat: -19, Lon: 177}, {Lat: -16, Lon: 177},
+		{Lat: -16, Lon: -179}, {Lat: -19, Lon: -179},
+	}
+	assert.True(t, fiji.CrossesAntimeridian())
+	assert.True(t, fiji.Contains(Point{Lat: -17.5, Lon: 179.9}))
+	assert.True(t, fiji.Contains(Point{Lat: -17.5, Lon: -179.9}))
+	assert.False(t, fiji.Contains(Point{Lat: -17.5, Lon: 0}))
+	assert.False(t, fiji.Contains(Point{Lat: -17.5, Lon: -170}))
+}
+
+func TestNormalizeLon(t *testing.T) {
+	assert.Equal(t, 37.6, NormalizeLon(37.6))
+	assert.Equal(t, 180.0, NormalizeLon(180))
+	assert.InDelta(t, -179.5, NormalizeLon(180.5), 1e-9)
+	assert.InDelta(t, 179.5, NormalizeLon(-180.5), 1e-9)
+}
@@ -0,0 +1,59 @@
+package geo
+
+import "math"
+
+// Параметры эллипсоида WGS84, на котором PostGIS считает расстояния для geography
+const (
+	wgs84SemiMajorMeters = 6378137.0
+	wgs84Flattening      = 1 / 298.257223563
+	wgs84SemiMinorMeters = wgs84SemiMajorMeters * (1 - wgs84Flattening)
+)
+
+// vincentyMaxIterations - предел итераций обратной задачи Винсенти; не сходится она только для почти антиподальных точек
+const vincentyMaxIterations = 200
+
+// GeodesicDistanceMeters возвращает длину геодезической между точками на эллипсоиде WGS84 (обратная задача Винсенти).
+// Результат совпадает с расстоянием по geography в PostGIS (ST_Distance, ST_DWithin) с точностью до миллиметра.
+// Для почти антиподальных точек, где итерации не сходятся, возвращается расстояние по большому кругу.
+func GeodesicDistanceMeters(a, b Point) float64 {
+	lonDelta := (b.Lon - a.Lon) * math.Pi / 180
+	u1 := math.Atan((1 - wgs84Flattening) * math.Tan(a.Lat*math.Pi/180))
+	u2 := math.Atan((1 - wgs84Flattening) * math.Tan(b.Lat*math.Pi/180))
+	sinU1, cosU1 := math.Sincos(u1)
+	sinU2, cosU2 := math.Sincos(u2)
+
+	lambda := lonDelta
+	for range vincentyMaxIterations {
+		sinLambda, cosLambda := math.Sincos(lambda)
+		sinSigma := math.Hypot(cosU2*sinLambda, cosU1*sinU2-sinU1*cosU2*cosLambda)
+		if sinSigma == 0 {
+			return 0
+		}
+		cosSigma := sinU1*sinU2 + cosU1*cosU2*cosLambda
+		sigma := math.Atan2(sinSigma, cosSigma)
+		sinAlpha := cosU1 * cosU2 * sinLambda / sinSigma
+		cosSqAlpha := 1 - sinAlpha*sinAlpha
+		cos2SigmaM := 0.0
+		if cosSqAlpha != 0 {
+			// На экваторе cosSqAlpha = 0, и слагаемое не участвует в формуле
+			cos2SigmaM = cosSigma - 2*sinU1*sinU2/cosSqAlpha
+		}
+		c := wgs84Flattening / 16 * cosSqAlpha * (4 + wgs84Flattening*(4-3*cosSqAlpha))
+
+		previous := lambda
+		lambda = lonDelta + (1-c)*wgs84Flattening*sinAlpha*
+			(sigma+c*sinSigma*(cos2SigmaM+c*cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)))
+		if math.Abs(lambda-previous) > 1e-12 {
+			continue
+		}
+
+		uSq := cosSqAlpha * (wgs84SemiMajorMeters*wgs84SemiMajorMeters - wgs84SemiMinorMeters*wgs84SemiMinorMeters) /
+			(wgs84SemiMinorMeters * wgs84SemiMinorMeters)
+		bigA := 1 + uSq/16384*(4096+uSq*(-768+uSq*(320-175*uSq)))
+		bigB := uSq / 1024 * (256 + uSq*(-128+uSq*(74-47*uSq)))
+		sigmaDelta := bigB * sinSigma * (cos2SigmaM + bigB/4*(cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)-
+			bigB/6*cos2SigmaM*(-3+4*sinSigma*sinSigma)*(-3+4*cos2SigmaM*cos2SigmaM)))
+		return wgs84SemiMinorMeters * bigA * (sigma - sigmaDelta)
+	}
+	return DistanceMeters(a, b)
+}
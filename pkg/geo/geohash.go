@@ -0,0 +1,91 @@
+package geo
+
+import (
+	"math"
+	"strings"
+)
+
+// geohashAlphabet - алфавит base32, используемый в geohash
+const geohashAlphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// MaxGeohashPrecision - максимальная поддерживаемая длина geohash (ячейка порядка сантиметров)
+const MaxGeohashPrecision = 12
+
+// earthRadiusMeters - средний радиус Земли
+const earthRadiusMeters = 6371008.8
+
+// Geohash возвращает geohash точки указанной длины и границы соответствующей ячейки.
+// Точки внутри одной ячейки получают одинаковый geohash.
+func Geohash(p Point, precision int) (string, BBox) {
+	precision = max(1, min(precision, MaxGeohashPrecision))
+	cell := BBox{MinLat: -90, MaxLat: 90, MinLon: -180, MaxLon: 180}
+
+	var sb strings.Builder
+	sb.Grow(precision)
+	bits, ch := 0, 0
+	evenBit := true // четные биты кодируют долготу, нечетные - широту
+	for sb.Len() < precision {
+		if evenBit {
+			mid := (cell.MinLon + cell.MaxLon) / 2
+			if p.Lon >= mid {
+				ch = ch<<1 | 1
+				cell.MinLon = mid
+			} else {
+				ch <<= 1
+				cell.MaxLon = mid
+			}
+		} else {
+			mid := (cell.MinLat + cell.MaxLat) / 2
+			if p.Lat >= mid {
+				ch = ch<<1 | 1
+				cell.MinLat = mid
+			} else {
+				ch <<= 1
+				cell.MaxLat = mid
+			}
+		}
+		evenBit = !evenBit
+
+		if bits++; bits == 5 {
+			sb.WriteByte(geohashAlphabet[ch])
+			bits, ch = 0, 0
+		}
+	}
+	return sb.String(), cell
+}
+
+// DistanceMeters возвращает расстояние между точками по большому кругу (формула гаверсинусов).
+// Расхождение с расстоянием по эллипсоиду (geography в PostGIS) не превышает долей процента.
+func DistanceMeters(a, b Point) float64 {
+	lat1, lat2 := a.Lat*math.Pi/180, b.Lat*math.Pi/180
+	dLat := lat2 - lat1
+	dLon := (b.Lon - a.Lon) * math.Pi / 180
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusMeters * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// CircleAreaSqMeters возвращает площадь круга радиусом radiusMeters (π·r²).
+// Для зон до сотен километров отличие от площади сферического сегмента пренебрежимо мало.
+func CircleAreaSqMeters(radiusMeters float64) float64 {
+	return math.Pi * radiusMeters * radiusMeters
+}
+
+// CircleOverlapAreaSqMeters возвращает площадь пересечения двух кругов радиусами r1 и r2,
+// центры которых находятся на расстоянии distance (все величины в метрах).
+// Круги считаются плоскими, как и в CircleAreaSqMeters.
+func CircleOverlapAreaSqMeters(distance, r1, r2 float64) float64 {
+	if distance >= r1+r2 {
+		return 0
+	}
+	if distance <= math.Abs(r1-r2) {
+		// Один круг целиком внутри другого
+		return CircleAreaSqMeters(math.Min(r1, r2))
+	}
+
+	// Площадь линзы как сумма двух круговых сегментов
+	a1 := r1 * r1 * math.Acos((distance*distance+r1*r1-r2*r2)/(2*distance*r1))
+	a2 := r2 * r2 * math.Acos((distance*distance+r2*r2-r1*r1)/(2*distance*r2))
+	triangle := 0.5 * math.Sqrt((-distance+r1+r2)*(distance+r1-r2)*(distance-r1+r2)*(distance+r1+r2))
+	return a1 + a2 - triangle
+}
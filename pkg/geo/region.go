@@ -0,0 +1,57 @@
+package geo
+
+import "errors"
+
+// Region - географическая область: прямоугольник или многоугольник
+type Region struct {
+	BBox    *BBox   `json:"bbox,omitempty"`
+	Polygon Polygon `json:"polygon,omitempty"`
+}
+
+// Validate проверяет, что задан ровно один корректный вид области
+func (r *Region) Validate() error {
+	switch {
+	case r.BBox != nil && r.Polygon != nil:
+		return errors.New("region must define either bbox or polygon, not both")
+	case r.BBox != nil:
+		return r.BBox.Validate()
+	case r.Polygon != nil:
+		return r.Polygon.Validate()
+	default:
+		return errors.New("region must define bbox or polygon")
+	}
+}
+
+// Contains сообщает, находится ли точка внутри области
+func (r *Region) Contains(p Point) bool {
+	if r.BBox != nil {
+		return r.BBox.Contains(p)
+	}
+	return r.Polygon.Contains(p)
+}
+
+// RegionPolicy ограничивает допустимые координаты: точка должна попасть хотя бы в одну
+// из областей Allow (если они заданы) и не попасть ни в одну из областей Deny.
+// Пустая политика допускает любые координаты.
+type RegionPolicy struct {
+	Allow []Region
+	Deny  []Region
+}
+
+// Permits сообщает, допускает ли политика точку
+func (rp RegionPolicy) Permits(p Point) bool {
+	for i := range rp.Deny {
+		if rp.Deny[i].Contains(p) {
+			return false
+		}
+	}
+	if len(rp.Allow) == 0 {
+		return true
+	}
+	for i := range rp.Allow {
+		if rp.Allow[i].Contains(p) {
+			return true
+		}
+	}
+	return false
+}
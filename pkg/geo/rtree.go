@@ -0,0 +1,154 @@
+package geo
+
+import (
+	"math"
+	"sort"
+)
+
+// rtreeNodeCapacity - максимальное число элементов в узле R-дерева
+const rtreeNodeCapacity = 16
+
+// RTree - неизменяемое R-дерево прямоугольников, построенное пакетной загрузкой (Sort-Tile-Recursive).
+// Элементы идентифицируются индексами во входном слайсе. Дерево безопасно для одновременного чтения.
+type RTree struct {
+	root *rtreeNode
+}
+
+type rtreeEntry struct {
+	box BBox
+	id  int
+}
+
+type rtreeNode struct {
+	box      BBox
+	children []*rtreeNode
+	// entries заполнены только у листьев
+	entries []rtreeEntry
+}
+
+// NewRTree строит дерево по прямоугольникам boxes. Прямоугольники, пересекающие 180-й меридиан
+// (MinLon > MaxLon), разбиваются на две части с тем же индексом.
+func NewRTree(boxes []BBox) *RTree {
+	entries := make([]rtreeEntry, 0, len(boxes))
+	for id, box := range boxes {
+		if box.MinLon > box.MaxLon {
+			east, west := box, box
+			east.MaxLon, west.MinLon = 180, -180
+			entries = append(entries, rtreeEntry{box: east, id: id}, rtreeEntry{box: west, id: id})
+			continue
+		}
+		entries = append(entries, rtreeEntry{box: box, id: id})
+	}
+	if len(entries) == 0 {
+		return &RTree{}
+	}
+
+	leaves := make([]*rtreeNode, 0, len(entries)/rtreeNodeCapacity+1)
+	for _, group := range strTiles(entries, func(e rtreeEntry) BBox { return e.box }) {
+		leaves = append(leaves, &rtreeNode{box: unionBoxes(group, func(e rtreeEntry) BBox { return e.box }), entries: group})
+	}
+
+	level := leaves
+	for len(level) > 1 {
+		parents := make([]*rtreeNode, 0, len(level)/rtreeNodeCapacity+1)
+		for _, group := range strTiles(level, func(n *rtreeNode) BBox { return n.box }) {
+			parents = append(parents, &rtreeNode{box: unionBoxes(group, func(n *rtreeNode) BBox { return n.box }), children: group})
+		}
+		level = parents
+	}
+	return &RTree{root: level[0]}
+}
+
+// Search возвращает индексы прямоугольников, содержащих точку
+func (t *RTree) Search(p Point) []int {
+	var ids []int
+	if t.root == nil {
+		return ids
+	}
+	stack := []*rtreeNode{t.root}
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if !node.box.Contains(p) {
+			continue
+		}
+		for _, entry := range node.entries {
+			if entry.box.Contains(p) {
+				ids = append(ids, entry.id)
+			}
+		}
+		stack = append(stack, node.children...)
+	}
+	return ids
+}
+
+// strTiles группирует элементы по rtreeNodeCapacity: сортирует по долготе центра,
+// режет на вертикальные полосы и внутри полосы группирует по широте центра
+func strTiles[T any](items []T, boxOf func(T) BBox) [][]T {
+	leafCount := int(math.Ceil(float64(len(items)) / rtreeNodeCapacity))
+	sliceCount := int(math.Ceil(math.Sqrt(float64(leafCount))))
+	sliceSize := sliceCount * rtreeNodeCapacity
+
+	sort.Slice(items, func(i, j int) bool {
+		a, b := boxOf(items[i]), boxOf(items[j])
+		return a.MinLon+a.MaxLon < b.MinLon+b.MaxLon
+	})
+
+	var groups [][]T
+	for start := 0; start < len(items); start += sliceSize {
+		slice := items[start:min(start+sliceSize, len(items))]
+		sort.Slice(slice, func(i, j int) bool {
+			a, b := boxOf(slice[i]), boxOf(slice[j])
+			return a.MinLat+a.MaxLat < b.MinLat+b.MaxLat
+		})
+		for from := 0; from < len(slice); from += rtreeNodeCapacity {
+			groups = append(groups, slice[from:min(from+rtreeNodeCapacity, len(slice))])
+		}
+	}
+	return groups
+}
+
+// unionBoxes возвращает прямоугольник, охватывающий прямоугольники всех элементов
+func unionBoxes[T any](items []T, boxOf func(T) BBox) BBox {
+	union := boxOf(items[0])
+	for _, item := range items[1:] {
+		box := boxOf(item)
+		union.MinLat = math.Min(union.MinLat, box.MinLat)
+		union.MinLon = math.Min(union.MinLon, box.MinLon)
+		union.MaxLat = math.Max(union.MaxLat, box.MaxLat)
+		union.MaxLon = math.Max(union.MaxLon, box.MaxLon)
+	}
+	return union
+}
+
+// minCurvatureRadiusMeters - наименьший радиус кривизны эллипсоида WGS84 (меридиан на экваторе).
+// Угловой радиус круга, посчитанный по нему, не меньше углового радиуса того же круга и на сфере, и на эллипсоиде.
+const minCurvatureRadiusMeters = wgs84SemiMajorMeters * (1 - wgs84Flattening) * (1 - wgs84Flattening)
+
+// CircleBBox возвращает прямоугольник, описанный вокруг круга радиусом radiusMeters. Прямоугольник с небольшим
+// запасом покрывает круг как на сфере (DistanceMeters), так и на эллипсоиде WGS84 (GeodesicDistanceMeters, PostGIS).
+// Если круг пересекает 180-й меридиан, MinLon больше MaxLon; если он накрывает полюс - долгота не ограничена.
+func CircleBBox(center Point, radiusMeters float64) BBox {
+	angular := radiusMeters / minCurvatureRadiusMeters
+	latDelta := angular * 180 / math.Pi
+	box := BBox{
+		MinLat: math.Max(center.Lat-latDelta, -90),
+		MaxLat: math.Min(center.Lat+latDelta, 90),
+		MinLon: -180,
+		MaxLon: 180,
+	}
+
+	sinAngular, cosLat := math.Sin(angular), math.Cos(center.Lat*math.Pi/180)
+	if angular >= math.Pi/2 || sinAngular >= cosLat {
+		return box
+	}
+	lonDelta := math.Asin(sinAngular/cosLat) * 180 / math.Pi
+	box.MinLon, box.MaxLon = center.Lon-lonDelta, center.Lon+lonDelta
+	if box.MinLon < -180 {
+		box.MinLon += 360
+	}
+	if box.MaxLon > 180 {
+		box.MaxLon -= 360
+	}
+	return box
+}
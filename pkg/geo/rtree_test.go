@@ -0,0 +1,67 @@
+package geo
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRTree_SearchMatchesBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	boxes := make([]BBox, 500)
+	for i := range boxes {
+		center := Point{Lat: rng.Float64()*10 + 50, Lon: rng.Float64()*10 + 30}
+		boxes[i] = CircleBBox(center, rng.Float64()*20000+100)
+	}
+	tree := NewRTree(boxes)
+
+	for range 200 {
+		p := Point{Lat: rng.Float64()*10 + 50, Lon: rng.Float64()*10 + 30}
+		var expected []int
+		for id, box := range boxes {
+			if box.Contains(p) {
+				expected = append(expected, id)
+			}
+		}
+		got := tree.Search(p)
+		sort.Ints(got)
+		assert.Equal(t, expected, got)
+	}
+}
+
+func TestRTree_AntimeridianAndEmpty(t *testing.T) {
+	assert.Empty(t, NewRTree(nil).Search(Point{Lat: 0, Lon: 0}))
+
+	chukotka := CircleBBox(Point{Lat: 65, Lon: 179.9}, 20000)
+	assert.Greater(t, chukotka.MinLon, chukotka.MaxLon)
+
+	tree := NewRTree([]BBox{chukotka})
+	assert.Equal(t, []int{0}, tree.Search(Point{Lat: 65, Lon: -179.9}))
+	assert.Equal(t, []int{0}, tree.Search(Point{Lat: 65, Lon: 179.95}))
+	assert.Empty(t, tree.Search(Point{Lat: 65, Lon: 170}))
+}
+
+func TestCircleBBox_ContainsCircle(t *testing.T) {
+	center := Point{Lat: 55.75, Lon: 37.61}
+	box := CircleBBox(center, 1000)
+
+	// Края прямоугольника лежат не ближе радиуса ни на сфере, ни на эллипсоиде, но с запасом не больше процента
+	for _, p := range []Point{{Lat: box.MaxLat, Lon: center.Lon}, {Lat: center.Lat, Lon: box.MinLon}} {
+		assert.GreaterOrEqual(t, DistanceMeters(center, p), 1000.0)
+		assert.GreaterOrEqual(t, GeodesicDistanceMeters(center, p), 1000.0)
+		assert.Less(t, DistanceMeters(center, p), 1010.0)
+	}
+	assert.False(t, box.Contains(Point{Lat: box.MaxLat + 1e-4, Lon: center.Lon}))
+
+	// У экватора градус меридиана на эллипсоиде короче, чем на сфере, и круг на эллипсоиде выше описанного по сфере
+	equator := Point{Lat: 0, Lon: 10}
+	wide := CircleBBox(equator, 100000)
+	assert.GreaterOrEqual(t, GeodesicDistanceMeters(equator, Point{Lat: wide.MaxLat, Lon: equator.Lon}), 100000.0)
+
+	// Круг, накрывающий полюс, не ограничен по долготе
+	polar := CircleBBox(Point{Lat: 89.99, Lon: 0}, 5000)
+	assert.Equal(t, 90.0, polar.MaxLat)
+	assert.Equal(t, -180.0, polar.MinLon)
+}
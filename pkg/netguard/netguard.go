@@ -0,0 +1,70 @@
+package netguard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"syscall"
+)
+
+// ErrForbiddenAddress возвращается, когда хост указывает на непубличный адрес. Запросы по адресам, заданным
+// клиентами API, не должны достигать внутренней сети, loopback и метаданных облака (SSRF).
+var ErrForbiddenAddress = errors.New("address is not public")
+
+// Resolver разрешает имя хоста в IP-адреса; реализуется net.Resolver
+type Resolver interface {
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+// sharedAddressSpace - диапазон адресов операторского NAT (RFC 6598), не маршрутизируемый в интернете
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// IsPublic сообщает, что адрес публичный: не loopback, не частный (RFC 1918, RFC 4193, RFC 6598),
+// не link-local (в том числе 169.254.169.254), не multicast и не неопределенный
+func IsPublic(ip net.IP) bool {
+	addr, ok := netip.AddrFromSlice(ip)
+	if !ok {
+		return false
+	}
+	addr = addr.Unmap()
+	return addr.IsGlobalUnicast() && !addr.IsPrivate() && !sharedAddressSpace.Contains(addr)
+}
+
+// CheckHost разрешает хост и возвращает ErrForbiddenAddress, если хотя бы один из его адресов не публичный.
+// IP-адрес в качестве хоста проверяется без обращения к DNS.
+func CheckHost(ctx context.Context, resolver Resolver, host string) error {
+	if ip := net.ParseIP(host); ip != nil {
+		if !IsPublic(ip) {
+			return fmt.Errorf("host %s: %w", host, ErrForbiddenAddress)
+		}
+		return nil
+	}
+	addrs, err := resolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return fmt.Errorf("failed to resolve host %s: %w", host, err)
+	}
+	if len(addrs) == 0 {
+		return fmt.Errorf("host %s has no addresses: %w", host, ErrForbiddenAddress)
+	}
+	for _, addr := range addrs {
+		if !IsPublic(addr.IP) {
+			return fmt.Errorf("host %s resolves to %s: %w", host, addr.IP, ErrForbiddenAddress)
+		}
+	}
+	return nil
+}
+
+// Control проверяет адрес непосредственно перед соединением (net.Dialer.Control), уже после разрешения имени.
+// В отличие от CheckHost, не дает обойти проверку сменой DNS-записи между проверкой и запросом.
+func Control(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || !IsPublic(ip) {
+		return fmt.Errorf("dial %s: %w", address, ErrForbiddenAddress)
+	}
+	return nil
+}
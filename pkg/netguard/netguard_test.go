@@ -0,0 +1,59 @@
+package netguard
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// staticResolver разрешает имена по таблице без обращения к DNS
+type staticResolver map[string][]string
+
+func (r staticResolver) LookupIPAddr(_ context.Context, host string) ([]net.IPAddr, error) {
+	ips, ok := r[host]
+	if !ok {
+		return nil, errors.New("no such host")
+	}
+	addrs := make([]net.IPAddr, len(ips))
+	for i, ip := range ips {
+		addrs[i] = net.IPAddr{IP: net.ParseIP(ip)}
+	}
+	return addrs, nil
+}
+
+func TestIsPublic(t *testing.T) {
+	for _, ip := range []string{"8.8.8.8", "203.0.113.10", "2001:4860:4860::8888"} {
+		assert.True(t, IsPublic(net.ParseIP(ip)), ip)
+	}
+	for _, ip := range []string{
+		"127.0.0.1", "10.1.2.3", "172.16.0.1", "192.168.1.1", "169.254.169.254", "100.64.0.1",
+		"0.0.0.0", "224.0.0.1", "::1", "fe80::1", "fd00::1", "::ffff:127.0.0.1",
+	} {
+		assert.False(t, IsPublic(net.ParseIP(ip)), ip)
+	}
+}
+
+func TestCheckHost(t *testing.T) {
+	ctx := context.Background()
+	resolver := staticResolver{
+		"hooks.example.org": {"203.0.113.10"},
+		"internal.example":  {"203.0.113.10", "10.0.0.5"},
+	}
+
+	require.NoError(t, CheckHost(ctx, resolver, "hooks.example.org"))
+	require.NoError(t, CheckHost(ctx, resolver, "203.0.113.10"))
+	// Достаточно одного непубличного адреса среди записей
+	assert.ErrorIs(t, CheckHost(ctx, resolver, "internal.example"), ErrForbiddenAddress)
+	assert.ErrorIs(t, CheckHost(ctx, resolver, "169.254.169.254"), ErrForbiddenAddress)
+	assert.Error(t, CheckHost(ctx, resolver, "unknown.example"))
+}
+
+func TestControl(t *testing.T) {
+	assert.NoError(t, Control("tcp", "203.0.113.10:443", nil))
+	assert.ErrorIs(t, Control("tcp", "127.0.0.1:8080", nil), ErrForbiddenAddress)
+	assert.ErrorIs(t, Control("tcp6", "[::1]:80", nil), ErrForbiddenAddress)
+}
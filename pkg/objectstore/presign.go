@@ -0,0 +1,168 @@
+package objectstore
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxPresignTTL - наибольший срок действия presigned URL в Signature Version 4
+const MaxPresignTTL = 7 * 24 * time.Hour
+
+// Config - параметры S3-совместимого хранилища
+type Config struct {
+	// Endpoint - адрес хранилища, например https://s3.amazonaws.com или http://minio:9000
+	Endpoint        string
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	// PathStyle - адресовать бакет путем (endpoint/bucket/key), а не поддоменом (bucket.endpoint/key)
+	PathStyle bool
+}
+
+// Presigner формирует presigned URL S3-совместимого хранилища (AWS Signature Version 4 с подписью в строке запроса).
+// URL подписываются локально, без обращения к хранилищу.
+type Presigner struct {
+	endpoint  *url.URL
+	region    string
+	bucket    string
+	accessKey string
+	secretKey string
+	pathStyle bool
+	now       func() time.Time
+}
+
+// NewPresigner создает Presigner и проверяет параметры хранилища
+func NewPresigner(cfg Config) (*Presigner, error) {
+	endpoint, err := url.Parse(cfg.Endpoint)
+	if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+		return nil, fmt.Errorf("object storage endpoint %q must be an absolute http(s) URL", cfg.Endpoint)
+	}
+	if cfg.Region == "" || cfg.Bucket == "" || cfg.AccessKeyID == "" || cfg.SecretAccessKey == "" {
+		return nil, errors.New("object storage region, bucket and credentials are required")
+	}
+	return &Presigner{
+		endpoint:  endpoint,
+		region:    cfg.Region,
+		bucket:    cfg.Bucket,
+		accessKey: cfg.AccessKeyID,
+		secretKey: cfg.SecretAccessKey,
+		pathStyle: cfg.PathStyle,
+		now:       time.Now,
+	}, nil
+}
+
+// PresignPut возвращает URL для загрузки объекта методом PUT. Заголовок Content-Type входит в подпись,
+// поэтому клиент должен загрузить объект именно с этим типом.
+func (p *Presigner) PresignPut(key, contentType string, ttl time.Duration) (string, error) {
+	return p.presign(http.MethodPut, key, map[string]string{"content-type": contentType}, ttl)
+}
+
+// PresignGet возвращает URL для скачивания объекта методом GET
+func (p *Presigner) PresignGet(key string, ttl time.Duration) (string, error) {
+	return p.presign(http.MethodGet, key, nil, ttl)
+}
+
+// presign подписывает запрос method к объекту key; headers - дополнительные подписываемые заголовки в нижнем регистре
+func (p *Presigner) presign(method, key string, headers map[string]string, ttl time.Duration) (string, error) {
+	if key == "" {
+		return "", errors.New("object key is required")
+	}
+	if ttl < time.Second || ttl > MaxPresignTTL {
+		return "", fmt.Errorf("presign ttl must be between 1s and %s, got %s", MaxPresignTTL, ttl)
+	}
+
+	now := p.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	scope := now.Format("20060102") + "/" + p.region + "/s3/aws4_request"
+
+	host := p.endpoint.Host
+	path := strings.TrimSuffix(p.endpoint.Path, "/")
+	if p.pathStyle {
+		path += "/" + p.bucket
+	} else {
+		host = p.bucket + "." + host
+	}
+	path = uriEncode(path+"/"+key, false)
+
+	signed := map[string]string{"host": host}
+	for name, value := range headers {
+		signed[name] = strings.TrimSpace(value)
+	}
+	names := make([]string, 0, len(signed))
+	for name := range signed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + signed[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	query := canonicalQuery(map[string]string{
+		"X-Amz-Algorithm":     "AWS4-HMAC-SHA256",
+		"X-Amz-Credential":    p.accessKey + "/" + scope,
+		"X-Amz-Date":          amzDate,
+		"X-Amz-Expires":       strconv.Itoa(int(ttl / time.Second)),
+		"X-Amz-SignedHeaders": signedHeaders,
+	})
+	canonicalRequest := strings.Join([]string{method, path, query, canonicalHeaders.String(), signedHeaders, "UNSIGNED-PAYLOAD"}, "\n")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, hex.EncodeToString(requestHash[:])}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+p.secretKey), now.Format("20060102"))
+	for _, part := range []string{p.region, "s3", "aws4_request"} {
+		signingKey = hmacSHA256(signingKey, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	return p.endpoint.Scheme + "://" + host + path + "?" + query + "&X-Amz-Signature=" + signature, nil
+}
+
+// canonicalQuery кодирует параметры строки запроса в каноническом для подписи виде: по порядку имен
+func canonicalQuery(params map[string]string) string {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = uriEncode(name, true) + "=" + uriEncode(params[name], true)
+	}
+	return strings.Join(parts, "&")
+}
+
+// uriEncode кодирует строку по правилам Signature Version 4: без изменений остаются только
+// A-Z, a-z, 0-9, '-', '_', '.', '~' и, если encodeSlash = false, '/'
+func uriEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z', c >= '0' && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,34 @@
+package postgres
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// IsUnavailable сообщает, вызвана ли ошибка недоступностью PostgreSQL (а не ошибкой запроса или данных):
+// не удалось установить соединение, истек таймаут, сервер перезапускается или исчерпан лимит соединений.
+func IsUnavailable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var connectErr *pgconn.ConnectError
+	if errors.As(err, &connectErr) {
+		return true
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		// Класс 08 - ошибки соединения; 57P01-57P03 - сервер останавливается или еще не принимает подключения;
+		// 53300 - слишком много соединений
+		switch pgErr.Code {
+		case "57P01", "57P02", "57P03", "53300":
+			return true
+		}
+		return strings.HasPrefix(pgErr.Code, "08")
+	}
+
+	return pgconn.Timeout(err) || pgconn.SafeToRetry(err)
+}
@@ -4,16 +4,18 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/shenikar/geo_broadcasting_system/internal/config"
 )
 
-// NewPostgresDB создает новый пул соединений PostgreSQL
-func NewPostgresDB(ctx context.Context, appCfg *config.Config) (*pgxpool.Pool, error) {
+// NewPostgresDB создает новый пул соединений PostgreSQL; tracer (если задан) получает каждый запрос пула
+func NewPostgresDB(ctx context.Context, appCfg *config.Config, tracer pgx.QueryTracer) (*pgxpool.Pool, error) {
 	cfgPool, err := pgxpool.ParseConfig(appCfg.DatabaseURL)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка при разборе конфигурации postgres: %w", err)
 	}
+	cfgPool.ConnConfig.Tracer = tracer
 
 	dbpool, err := pgxpool.NewWithConfig(ctx, cfgPool)
 	if err != nil {
@@ -0,0 +1,27 @@
+package redis
+
+import (
+	"errors"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// IsUnavailable сообщает, вызвана ли ошибка недоступностью Redis: режим деградации, клиент закрыт, пул соединений
+// исчерпан или сервер временно не обслуживает запросы (загрузка данных, недоступный master, переполнение клиентов).
+func IsUnavailable(err error) bool {
+	if err == nil {
+		return false
+	}
+	return errors.Is(err, ErrDegraded) ||
+		errors.Is(err, redis.ErrClosed) ||
+		errors.Is(err, redis.ErrPoolTimeout) ||
+		redis.IsLoadingError(err) ||
+		redis.IsMasterDownError(err) ||
+		redis.IsClusterDownError(err) ||
+		redis.IsTryAgainError(err) ||
+		redis.IsMaxClientsError(err)
+}
+
+// ErrDegraded возвращается вместо выполнения команды, пока Redis считается недоступным (режим деградации):
+// команды не ждут таймаута соединения, а вызывающий код сразу переходит к запасному пути
+var ErrDegraded = errors.New("redis is unavailable, running in degraded mode")
@@ -0,0 +1,31 @@
+package redis
+
+import "strings"
+
+// KeyBuilder формирует ключи Redis с общим префиксом (пространством имен) окружения
+type KeyBuilder struct {
+	prefix string
+}
+
+// NewKeyBuilder создает KeyBuilder. Пустой префикс оставляет ключи без изменений.
+func NewKeyBuilder(prefix string) KeyBuilder {
+	return KeyBuilder{prefix: strings.TrimSuffix(prefix, ":")}
+}
+
+// Key склеивает части ключа через ":" и добавляет префикс
+func (b KeyBuilder) Key(parts ...string) string {
+	key := strings.Join(parts, ":")
+	if b.prefix == "" {
+		return key
+	}
+	return b.prefix + ":" + key
+}
+
+// globReplacer экранирует спецсимволы шаблонов SCAN/KEYS
+var globReplacer = strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`, "]", `\]`)
+
+// Pattern возвращает шаблон для SCAN MATCH, совпадающий со всеми ключами, вложенными в Key(parts...).
+// Префикс и части экранируются, поэтому шаблон не выходит за пространство имен окружения.
+func (b KeyBuilder) Pattern(parts ...string) string {
+	return globReplacer.Replace(b.Key(parts...)) + ":*"
+}
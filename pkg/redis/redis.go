@@ -7,14 +7,19 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
-// NewRedisClient создает и возвращает новый клиент Redis
-func NewRedisClient(ctx context.Context, addr, password string, db int) (*redis.Client, error) {
-	rdb := redis.NewClient(&redis.Options{
+// NewClient создает клиент Redis без проверки соединения: соединения устанавливаются при первых командах
+func NewClient(addr, password string, db int) *redis.Client {
+	return redis.NewClient(&redis.Options{
 		Addr:     addr,
 		Password: password,
 		DB:       db,
 		PoolSize: 10,
 	})
+}
+
+// NewRedisClient создает и возвращает новый клиент Redis, проверяя соединение
+func NewRedisClient(ctx context.Context, addr, password string, db int) (*redis.Client, error) {
+	rdb := NewClient(addr, password, db)
 
 	// Проверяем соединение с Redis
 	_, err := rdb.Ping(ctx).Result()